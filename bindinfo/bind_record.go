@@ -52,6 +52,8 @@ const (
 	Capture = "capture"
 	// Evolve indicates the binding is evolved by TiDB from old bindings.
 	Evolve = "evolve"
+	// Regression indicates the binding is captured by TiDB automatically from the previous plan of a regressed statement.
+	Regression = "regression"
 	// Builtin indicates the binding is a builtin record for internal locking purpose. It is also the status for the builtin binding.
 	Builtin = "builtin"
 )
@@ -155,6 +157,16 @@ func (br *BindRecord) FindBinding(hint string) *Binding {
 	return nil
 }
 
+// hasBindSQL checks whether there is a binding with the bind SQL in BindRecord.
+func (br *BindRecord) hasBindSQL(bindSQL string) bool {
+	for _, binding := range br.Bindings {
+		if binding.BindSQL == bindSQL {
+			return true
+		}
+	}
+	return false
+}
+
 // prepareHints builds ID and Hint for BindRecord. If sctx is not nil, we check if
 // the BindSQL is still valid.
 func (br *BindRecord) prepareHints(sctx sessionctx.Context) error {
@@ -18,13 +18,18 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/stretchr/testify/require"
 )
@@ -1029,3 +1034,85 @@ func TestCaptureHints(t *testing.T) {
 		require.True(t, strings.Contains(res[0][1].(string), capCase.hint)) // the binding contains the expected hint
 	}
 }
+
+func TestCapturePlanRegression(t *testing.T) {
+	originalVal := config.CheckTableBeforeDrop
+	config.CheckTableBeforeDrop = true
+	defer func() {
+		config.CheckTableBeforeDrop = originalVal
+	}()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+
+	utilCleanBindingEnv(tk, dom)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("SET GLOBAL tidb_capture_plan_regression = on")
+	defer func() {
+		tk.MustExec("SET GLOBAL tidb_capture_plan_regression = off")
+	}()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, key idx_a(a))")
+	tk.MustExec("insert into t values (1,1), (2,2), (3,3), (20,20)")
+
+	sql := "select * from t where a > 10"
+	normalizedSQL, digest := parser.NormalizeDigest(sql)
+	addStmt := func(planDigest, planHint string, latency time.Duration, startTime time.Time) {
+		stmtsummary.StmtSummaryByDigestMap.AddStatement(&stmtsummary.StmtExecInfo{
+			SchemaName:    "test",
+			OriginalSQL:   sql,
+			NormalizedSQL: normalizedSQL,
+			Digest:        digest.String(),
+			PlanDigest:    planDigest,
+			PlanGenerator: func() (string, string) {
+				return "", planHint
+			},
+			User:         "root",
+			TotalLatency: latency,
+			StmtCtx:      &stmtctx.StatementContext{StmtType: "Select"},
+			CopTasks:     &stmtctx.CopTasksDetails{},
+			ExecDetail:   &execdetails.ExecDetails{},
+			StartTime:    startTime,
+			Succeed:      true,
+		})
+	}
+	now := time.Now()
+	addStmt("plan_digest1", "use_index(@`sel_1` `test`.`t` `idx_a`)", time.Millisecond, now.Add(-time.Minute))
+	addStmt("plan_digest1", "use_index(@`sel_1` `test`.`t` `idx_a`)", time.Millisecond, now.Add(-time.Minute))
+	dom.BindHandle().CapturePlanRegressions()
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+
+	// The latest plan is 10 times slower than the previous one.
+	addStmt("plan_digest2", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now)
+	addStmt("plan_digest2", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now)
+	dom.BindHandle().CapturePlanRegressions()
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "select * from `test` . `t` where `a` > ?", rows[0][0])
+	require.Equal(t, "SELECT /*+ use_index(@`sel_1` `test`.`t` `idx_a`)*/ * FROM `test`.`t` WHERE `a` > 10", rows[0][1])
+	require.Equal(t, bindinfo.PendingVerify, rows[0][3])
+	require.Equal(t, bindinfo.Regression, rows[0][8])
+	tk.MustQuery("select decision, source, current_plan_latency, binding_plan_latency, reason from mysql.plan_evolution_history").Check(testkit.Rows(
+		"captured regression 10000000 1000000 plan plan_digest2 is 10.00 times slower than plan plan_digest1"))
+
+	// The captured binding is not captured again.
+	dom.BindHandle().CapturePlanRegressions()
+	require.Len(t, tk.MustQuery("show global bindings").Rows(), 1)
+	tk.MustQuery("select count(*) from mysql.plan_evolution_history").Check(testkit.Rows("1"))
+
+	// The plan of the binding is verified to be 10 times faster than the current plan.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/bindinfo/mockVerifyPlanSpeedup", "return(10)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/bindinfo/mockVerifyPlanSpeedup"))
+	}()
+	tk.MustExec("set global tidb_evolve_plan_verify_count = 2")
+	tk.MustExec("admin evolve bindings")
+	rows = tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, bindinfo.Enabled, rows[0][3])
+	tk.MustQuery("select decision, source, current_plan_latency, binding_plan_latency from mysql.plan_evolution_history order by create_time").Check(testkit.Rows(
+		"captured regression 10000000 1000000", "accepted regression 10000000 1000000"))
+}
@@ -773,6 +773,28 @@ func (cf *captureFilter) isEmpty() bool {
 	return len(cf.tables) == 0 && len(cf.users) == 0
 }
 
+// filter returns true if the statement should not be captured.
+func (cf *captureFilter) filter(stmt ast.StmtNode, bindableStmt *stmtsummary.BindableStmt) bool {
+	if cf.isEmpty() {
+		return false
+	}
+	cf.fail = false
+	cf.currentDB = bindableStmt.Schema
+	stmt.Accept(cf)
+	if cf.fail {
+		return true
+	}
+	if len(cf.users) > 0 {
+		for user := range bindableStmt.Users {
+			if _, ok := cf.users[user]; !ok {
+				return false // some user not in the black-list has processed this stmt
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // ParseCaptureTableFilter checks whether this filter is valid and parses it.
 func ParseCaptureTableFilter(tableFilter string) (f tablefilter.Filter, valid bool) {
 	// forbid wildcards '!' and '@' for safety,
@@ -843,7 +865,6 @@ func (h *BindHandle) extractCaptureFilterFromStorage() (filter *captureFilter) {
 func (h *BindHandle) CaptureBaselines() {
 	parser4Capture := parser.New()
 	captureFilter := h.extractCaptureFilterFromStorage()
-	emptyCaptureFilter := captureFilter.isEmpty()
 	bindableStmts := stmtsummary.StmtSummaryByDigestMap.GetMoreThanCntBindableStmt(captureFilter.frequency)
 	for _, bindableStmt := range bindableStmts {
 		stmt, err := parser4Capture.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
@@ -854,26 +875,8 @@ func (h *BindHandle) CaptureBaselines() {
 		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
 			continue
 		}
-		if !emptyCaptureFilter {
-			captureFilter.fail = false
-			captureFilter.currentDB = bindableStmt.Schema
-			stmt.Accept(captureFilter)
-			if captureFilter.fail {
-				continue
-			}
-
-			if len(captureFilter.users) > 0 {
-				filteredByUser := true
-				for user := range bindableStmt.Users {
-					if _, ok := captureFilter.users[user]; !ok {
-						filteredByUser = false // some user not in the black-list has processed this stmt
-						break
-					}
-				}
-				if filteredByUser {
-					continue
-				}
-			}
+		if captureFilter.filter(stmt, bindableStmt) {
+			continue
 		}
 		dbName := utilparser.GetDefaultDB(stmt, bindableStmt.Schema)
 		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, bindableStmt.Query))
@@ -1173,12 +1176,16 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 	if maxTime == 0 || (!timeutil.WithinDayTimePeriod(startTime, endTime, time.Now()) && !adminEvolve) {
 		return nil
 	}
+	if binding.Source == Regression {
+		return h.verifyRegressedBinding(sctx, originalSQL, db, binding, maxTime)
+	}
 	sctx.GetSessionVars().UsePlanBaselines = true
 	currentPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, maxTime)
 	// If we just return the error to the caller, this job will be retried again and again and cause endless logs,
 	// since it is still in the bind record. Now we just drop it and if it is actually retryable,
 	// we will hope for that we can capture this evolve task again.
 	if err != nil {
+		h.recordEvolveDecision(originalSQL, db, &binding, Dropped, 0, 0, err.Error())
 		return h.DropBindRecord(originalSQL, db, &binding)
 	}
 	// If the accepted plan timeouts, it is hard to decide the timeout for verify plan.
@@ -1189,6 +1196,7 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 	sctx.GetSessionVars().UsePlanBaselines = false
 	verifyPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, maxTime)
 	if err != nil {
+		h.recordEvolveDecision(originalSQL, db, &binding, Dropped, currentPlanTime, 0, err.Error())
 		return h.DropBindRecord(originalSQL, db, &binding)
 	}
 	if verifyPlanTime == -1 || (float64(verifyPlanTime)*acceptFactor > float64(currentPlanTime)) {
@@ -1203,7 +1211,16 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 		binding.Status = Enabled
 	}
 	// We don't need to pass the `sctx` because the BindSQL has been validated already.
-	return h.AddBindRecord(nil, &BindRecord{OriginalSQL: originalSQL, Db: db, Bindings: []Binding{binding}})
+	err = h.AddBindRecord(nil, &BindRecord{OriginalSQL: originalSQL, Db: db, Bindings: []Binding{binding}})
+	if err != nil {
+		return err
+	}
+	reason := ""
+	if verifyPlanTime == -1 {
+		reason = "binding plan timed out"
+	}
+	h.recordEvolveDecision(originalSQL, db, &binding, evolveDecision(binding.Status), currentPlanTime, verifyPlanTime, reason)
+	return nil
 }
 
 // Clear resets the bind handle. It is only used for test.
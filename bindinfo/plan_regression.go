@@ -0,0 +1,235 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/hint"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mathutil"
+	utilparser "github.com/pingcap/tidb/util/parser"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// Captured means that the binding has been captured as a pending verify binding.
+	Captured = "captured"
+	// Accepted means that the binding has been enabled after verify process.
+	Accepted = "accepted"
+	// Dropped means that the binding has been dropped because it fails to run during verify process.
+	Dropped = "dropped"
+)
+
+// CapturePlanRegressions is used to automatically capture the previous plan of the statements whose latest
+// plan is `tidb_plan_regression_ratio` times slower than it. The previous plans are captured as pending
+// verify bindings, which are then verified by HandleEvolvePlanTask.
+func (h *BindHandle) CapturePlanRegressions() {
+	parser4Capture := parser.New()
+	captureFilter := h.extractCaptureFilterFromStorage()
+	ratio := getPlanRegressionRatio(h.sctx.Context)
+	regressedStmts := stmtsummary.StmtSummaryByDigestMap.GetPlanRegressedStmts(ratio, captureFilter.frequency)
+	for _, regressedStmt := range regressedStmts {
+		stmt, err := parser4Capture.ParseOneStmt(regressedStmt.Query, regressedStmt.Charset, regressedStmt.Collation)
+		if err != nil {
+			logutil.BgLogger().Debug("[sql-bind] parse SQL failed in plan regression capture", zap.String("SQL", regressedStmt.Query), zap.Error(err))
+			continue
+		}
+		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+			continue
+		}
+		if captureFilter.filter(stmt, &regressedStmt.BindableStmt) {
+			continue
+		}
+		dbName := utilparser.GetDefaultDB(stmt, regressedStmt.Schema)
+		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, regressedStmt.Query))
+		bindSQL := GenerateBindSQL(context.TODO(), stmt, regressedStmt.PlanHint, true, dbName)
+		if bindSQL == "" {
+			continue
+		}
+		if r := h.GetBindRecord(digest.String(), normalizedSQL, dbName); r != nil {
+			// Either the statement has been bound by others, or the previous plan has been captured already.
+			if r.HasAvailableBinding() || r.hasBindSQL(bindSQL) {
+				continue
+			}
+		}
+		charset, collation := h.sctx.GetSessionVars().GetCharsetInfo()
+		binding := Binding{
+			BindSQL:   bindSQL,
+			Status:    PendingVerify,
+			Charset:   charset,
+			Collation: collation,
+			Source:    Regression,
+		}
+		// We don't need to pass the `sctx` because the BindSQL has been validated already.
+		err = h.AddBindRecord(nil, &BindRecord{OriginalSQL: normalizedSQL, Db: dbName, Bindings: []Binding{binding}})
+		if err != nil {
+			logutil.BgLogger().Debug("[sql-bind] add bind record failed in plan regression capture", zap.String("SQL", regressedStmt.Query), zap.Error(err))
+			continue
+		}
+		reason := fmt.Sprintf("plan %s is %.2f times slower than plan %s", regressedStmt.PlanDigest,
+			float64(regressedStmt.AvgLatency)/float64(regressedStmt.PrevAvgLatency), regressedStmt.PrevPlanDigest)
+		h.recordEvolveDecision(normalizedSQL, dbName, &binding, Captured, regressedStmt.AvgLatency, regressedStmt.PrevAvgLatency, reason)
+	}
+}
+
+// verifyRegressedBinding runs the plan chosen by the optimizer and the plan of the binding alternately for
+// several times, and accepts the binding if the median latency of its plan is `acceptFactor` times better.
+func (h *BindHandle) verifyRegressedBinding(sctx sessionctx.Context, originalSQL, db string, binding Binding, maxTime time.Duration) error {
+	currentSQL, err := removeBindHints(binding.BindSQL, binding.Charset, binding.Collation, db)
+	if err != nil {
+		h.recordEvolveDecision(originalSQL, db, &binding, Dropped, 0, 0, err.Error())
+		return h.DropBindRecord(originalSQL, db, &binding)
+	}
+	verifyCount := getEvolveVerifyCount(sctx)
+	// Both plans are forced by the statement itself, the plan baselines should not take effect.
+	sctx.GetSessionVars().UsePlanBaselines = false
+	currentPlanTimes := make([]time.Duration, 0, verifyCount)
+	verifyPlanTimes := make([]time.Duration, 0, verifyCount)
+	rejected := false
+	for i := 0; i < verifyCount && !rejected; i++ {
+		currentPlanTime, err := h.getRunningDuration(sctx, db, currentSQL, maxTime)
+		if err != nil {
+			h.recordEvolveDecision(originalSQL, db, &binding, Dropped, 0, 0, err.Error())
+			return h.DropBindRecord(originalSQL, db, &binding)
+		}
+		verifyMaxTime := maxTime
+		if currentPlanTime > 0 {
+			verifyMaxTime = time.Duration(float64(currentPlanTime) * verifyTimeoutFactor)
+		} else {
+			// The current plan timeouts, regard it as running for maxTime.
+			currentPlanTime = maxTime
+		}
+		verifyPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, verifyMaxTime)
+		if err != nil {
+			h.recordEvolveDecision(originalSQL, db, &binding, Dropped, 0, 0, err.Error())
+			return h.DropBindRecord(originalSQL, db, &binding)
+		}
+		if verifyPlanTime == -1 {
+			verifyPlanTime = verifyMaxTime
+			rejected = true
+		}
+		currentPlanTimes = append(currentPlanTimes, currentPlanTime)
+		verifyPlanTimes = append(verifyPlanTimes, verifyPlanTime)
+	}
+	currentPlanTime, verifyPlanTime := medianDuration(currentPlanTimes), medianDuration(verifyPlanTimes)
+	failpoint.Inject("mockVerifyPlanSpeedup", func(val failpoint.Value) {
+		// The plan of the binding runs val times faster than the current plan.
+		rejected = false
+		currentPlanTime, verifyPlanTime = time.Duration(val.(int))*time.Millisecond, time.Millisecond
+	})
+	var reason string
+	if rejected || float64(verifyPlanTime)*acceptFactor > float64(currentPlanTime) {
+		binding.Status = Rejected
+		reason = fmt.Sprintf("binding plan is not %.1f times faster than current plan in %d runs", acceptFactor, len(verifyPlanTimes))
+		digestText, _ := parser.NormalizeDigest(binding.BindSQL) // for log desensitization
+		logutil.BgLogger().Debug("[sql-bind] regressed plan rejected",
+			zap.Duration("currentPlanTime", currentPlanTime),
+			zap.Duration("verifyPlanTime", verifyPlanTime),
+			zap.String("digestText", digestText),
+		)
+	} else {
+		binding.Status = Enabled
+		reason = fmt.Sprintf("binding plan is %.2f times faster than current plan in %d runs", float64(currentPlanTime)/float64(verifyPlanTime), len(verifyPlanTimes))
+	}
+	// We don't need to pass the `sctx` because the BindSQL has been validated already.
+	err = h.AddBindRecord(nil, &BindRecord{OriginalSQL: originalSQL, Db: db, Bindings: []Binding{binding}})
+	if err != nil {
+		return err
+	}
+	h.recordEvolveDecision(originalSQL, db, &binding, evolveDecision(binding.Status), currentPlanTime, verifyPlanTime, reason)
+	return nil
+}
+
+// recordEvolveDecision records a decision made on the binding into mysql.plan_evolution_history.
+func (h *BindHandle) recordEvolveDecision(originalSQL, db string, binding *Binding, decision string, currentPlanTime, bindingPlanTime time.Duration, reason string) {
+	// A plan timed out during verification has a negative latency.
+	currentPlanTime, bindingPlanTime = mathutil.Max(currentPlanTime, 0), mathutil.Max(bindingPlanTime, 0)
+	exec := h.sctx.Context.(sqlexec.RestrictedSQLExecutor)
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
+	// No need to acquire the session context lock for ExecRestrictedSQL, it
+	// uses another background session.
+	_, _, err := exec.ExecRestrictedSQL(ctx, nil, `INSERT INTO mysql.plan_evolution_history
+	(sql_digest, original_sql, default_db, bind_sql, source, decision, current_plan_latency, binding_plan_latency, reason)
+	VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?)`,
+		parser.DigestNormalized(originalSQL).String(), originalSQL, db, binding.BindSQL, binding.Source, decision,
+		int64(currentPlanTime), int64(bindingPlanTime), reason)
+	if err != nil {
+		logutil.BgLogger().Warn("[sql-bind] failed to record plan evolution history", zap.String("decision", decision), zap.Error(err))
+	}
+}
+
+// evolveDecision converts the status of a verified binding to the decision in mysql.plan_evolution_history.
+func evolveDecision(status string) string {
+	if status == Enabled {
+		return Accepted
+	}
+	return status
+}
+
+// removeBindHints removes the hints in the bind SQL, so the plan is chosen by the optimizer itself.
+func removeBindHints(bindSQL, charset, collation, db string) (string, error) {
+	stmt, err := parser.New().ParseOneStmt(bindSQL, charset, collation)
+	if err != nil {
+		return "", err
+	}
+	hint.BindHint(stmt, &hint.HintsSet{})
+	return utilparser.RestoreWithDefaultDB(stmt, db, ""), nil
+}
+
+func getPlanRegressionRatio(sctx sessionctx.Context) float64 {
+	val, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBPlanRegressionRatio)
+	if err != nil {
+		return variable.DefTiDBPlanRegressionRatio
+	}
+	ratio, err := strconv.ParseFloat(val, 64)
+	if err != nil || ratio < 1 {
+		return variable.DefTiDBPlanRegressionRatio
+	}
+	return ratio
+}
+
+func getEvolveVerifyCount(sctx sessionctx.Context) int {
+	val, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBEvolvePlanVerifyCount)
+	if err != nil {
+		return variable.DefTiDBEvolvePlanVerifyCount
+	}
+	count, err := strconv.Atoi(val)
+	if err != nil || count < 1 {
+		return variable.DefTiDBEvolvePlanVerifyCount
+	}
+	return count
+}
+
+func medianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	return sorted[len(sorted)/2]
+}
@@ -1865,23 +1865,39 @@ func TestAlterTableAddUniqueOnPartionRangeColumn(t *testing.T) {
 	tk.MustGetErrCode("alter table t add unique index idx_b(b)", errno.ErrUniqueKeyNeedAllFieldsInPf)
 }
 
-func TestFulltextIndexIgnore(t *testing.T) {
+func TestFulltextIndex(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
 	tk := testkit.NewTestKit(t, store)
 	tk.MustExec("use test")
 	tk.MustExec("drop table if exists t_ft")
 	defer tk.MustExec("drop table if exists t_ft")
-	// Make sure that creating and altering to add a fulltext key gives the correct warning
-	assertWarningExec(tk, t, "create table t_ft (a text, fulltext key (a))", dbterror.ErrTableCantHandleFt)
-	assertWarningExec(tk, t, "alter table t_ft add fulltext key (a)", dbterror.ErrTableCantHandleFt)
-
-	// Make sure table t_ft still has no indexes even after it was created and altered
-	r := tk.MustQuery("show index from t_ft")
-	require.Len(t, r.Rows(), 0)
-	r = tk.MustQuery("select * from information_schema.statistics where table_schema='test' and table_name='t_ft'")
-	require.Len(t, r.Rows(), 0)
+	tk.MustExec("create table t_ft (a text, b varchar(100), c int, fulltext key (a))")
+	tk.MustExec("alter table t_ft add fulltext key ft_ab (a, b) with parser ngram")
+	tk.MustExec("create fulltext index ft_b on t_ft (b)")
+	tk.MustQuery("show index from t_ft").Check(testkit.Rows(
+		"t_ft 1 a 1 a <nil> 0 <nil> <nil> YES FULLTEXT   YES <nil> NO",
+		"t_ft 1 ft_ab 1 a <nil> 0 <nil> <nil> YES FULLTEXT   YES <nil> NO",
+		"t_ft 1 ft_ab 2 b <nil> 0 <nil> <nil> YES FULLTEXT   YES <nil> NO",
+		"t_ft 1 ft_b 1 b <nil> 0 <nil> <nil> YES FULLTEXT   YES <nil> NO"))
+	tk.MustQuery("select index_name, collation, index_type from information_schema.statistics where table_schema='test' and table_name='t_ft'").Check(testkit.Rows(
+		"a <nil> FULLTEXT", "ft_ab <nil> FULLTEXT", "ft_ab <nil> FULLTEXT", "ft_b <nil> FULLTEXT"))
+	tk.MustQuery("show create table t_ft").Check(testkit.Rows("t_ft CREATE TABLE `t_ft` (\n" +
+		"  `a` text DEFAULT NULL,\n" +
+		"  `b` varchar(100) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  FULLTEXT KEY `a` (`a`),\n" +
+		"  FULLTEXT KEY `ft_ab` (`a`,`b`) /*!50100 WITH PARSER `ngram` */,\n" +
+		"  FULLTEXT KEY `ft_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 
+	// FULLTEXT indexes can only be created on the non-binary string columns.
+	tk.MustGetErrCode("alter table t_ft add fulltext key (c)", errno.ErrBadFtColumn)
+	tk.MustGetErrCode("alter table t_ft modify column b int", errno.ErrBadFtColumn)
+	tk.MustGetErrCode("alter table t_ft add fulltext key (b(10))", errno.ErrWrongSubKey)
+	tk.MustGetErrCode("alter table t_ft add fulltext key (b) with parser unknown", errno.ErrFunctionNotDefined)
+	tk.MustGetErrCode("create temporary table t_ft_tmp (a text, fulltext key (a))", errno.ErrTableCantHandleFt)
+	tk.MustGetErrCode("create table t_ft_bin (a blob, fulltext key (a))", errno.ErrBadFtColumn)
 }
 
 func TestTreatOldVersionUTF8AsUTF8MB4(t *testing.T) {
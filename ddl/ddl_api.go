@@ -881,11 +881,11 @@ func buildColumnAndConstraint(
 
 // checkColumnDefaultValue checks the default value of the column.
 // In non-strict SQL mode, if the default value of the column is an empty string, the default value can be ignored.
-// In strict SQL mode, TEXT/BLOB/JSON can't have not null default values.
+// In strict SQL mode, TEXT/BLOB/JSON/GEOMETRY can't have not null default values.
 // In NO_ZERO_DATE SQL mode, TIMESTAMP/DATE/DATETIME type can't have zero date like '0000-00-00' or '0000-00-00 00:00:00'.
 func checkColumnDefaultValue(ctx sessionctx.Context, col *table.Column, value interface{}) (bool, interface{}, error) {
 	hasDefaultValue := true
-	if value != nil && (col.GetType() == mysql.TypeJSON ||
+	if value != nil && (col.GetType() == mysql.TypeJSON || col.GetType() == mysql.TypeGeometry ||
 		col.GetType() == mysql.TypeTinyBlob || col.GetType() == mysql.TypeMediumBlob ||
 		col.GetType() == mysql.TypeLongBlob || col.GetType() == mysql.TypeBlob) {
 		// In non-strict SQL mode.
@@ -1111,9 +1111,13 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 					col.FieldType.SetCollate(v.StrValue)
 				}
 			case ast.ColumnOptionFulltext:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt.GenWithStackByArgs())
+				constraints = append(constraints, &ast.Constraint{Tp: ast.ConstraintFulltext, Keys: keys})
 			case ast.ColumnOptionCheck:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			case ast.ColumnOptionSRID:
+				if err = setColumnSRID(col, v); err != nil {
+					return nil, nil, errors.Trace(err)
+				}
 			}
 		}
 	}
@@ -1864,7 +1868,24 @@ func BuildTableInfo(
 		}
 
 		if constr.Tp == ast.ConstraintFulltext {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt.GenWithStackByArgs())
+			var parserName model.CIStr
+			if constr.Option != nil {
+				parserName = constr.Option.ParserName
+			}
+			idxInfo, err := buildFulltextIndexInfo(tbInfo, model.NewCIStr(constr.Name), constr.Keys, parserName, model.StatePublic)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if constr.Option != nil {
+				idxInfo.Comment, err = validateCommentLength(ctx.GetSessionVars(), idxInfo.Name.String(), &constr.Option.Comment, dbterror.ErrTooLongIndexComment)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				idxInfo.Invisible = constr.Option.Visibility == ast.IndexVisibilityInvisible
+			}
+			addIndexColumnFlag(tbInfo, idxInfo)
+			idxInfo.ID = allocateIndexID(tbInfo)
+			tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
@@ -2082,6 +2103,7 @@ func BuildTableInfoWithLike(ctx sessionctx.Context, ident ast.Ident, referTblInf
 	tblInfo.Name = ident.Name
 	tblInfo.AutoIncID = 0
 	tblInfo.ForeignKeys = nil
+	tblInfo.Triggers = nil
 	// Ignore TiFlash replicas for temporary tables.
 	if s.TemporaryKeyword != ast.TemporaryNone {
 		tblInfo.TiFlashReplica = nil
@@ -2287,6 +2309,13 @@ func setTemporaryType(ctx sessionctx.Context, tbInfo *model.TableInfo, s *ast.Cr
 	default:
 		tbInfo.TempTableType = model.TempTableNone
 	}
+	if tbInfo.TempTableType != model.TempTableNone {
+		for _, idx := range tbInfo.Indices {
+			if idx.Tp == model.IndexTypeFulltext {
+				return errors.Trace(dbterror.ErrTableCantHandleFt)
+			}
+		}
+	}
 	return nil
 }
 
@@ -3079,6 +3108,9 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 	if tb.Meta().IsView() || tb.Meta().IsSequence() {
 		return dbterror.ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
 	}
+	if err = checkMaterializedViewTableDDL(ident, tb.Meta(), "Alter Table"); err != nil {
+		return err
+	}
 	if tb.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		if len(validSpecs) != 1 {
 			return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Alter Table")
@@ -3160,7 +3192,8 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 			case ast.ConstraintPrimaryKey:
 				err = d.CreatePrimaryKey(sctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, constr.Option)
 			case ast.ConstraintFulltext:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt)
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeFullText, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
 			case ast.ConstraintCheck:
 				sctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
 			default:
@@ -4167,6 +4200,26 @@ func setColumnComment(ctx sessionctx.Context, col *table.Column, option *ast.Col
 	return errors.Trace(err)
 }
 
+// setColumnSRID restricts the geometry column to values of the given spatial reference system.
+func setColumnSRID(col *table.Column, option *ast.ColumnOption) error {
+	if col.GetType() != mysql.TypeGeometry {
+		return dbterror.ErrWrongUsage.GenWithStackByArgs("SRID", "non-geometry column")
+	}
+	if _, err := types.GetSpatialReferenceSystem(option.SRID); err != nil {
+		return errors.Trace(err)
+	}
+	srid := option.SRID
+	col.SRID = &srid
+	return nil
+}
+
+func isSameColumnSRID(a, b *uint32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // processColumnOptions is only used in getModifiableColumnJob.
 func processColumnOptions(ctx sessionctx.Context, col *table.Column, options []*ast.ColumnOption) error {
 	var sb strings.Builder
@@ -4230,6 +4283,10 @@ func processColumnOptions(ctx sessionctx.Context, col *table.Column, options []*
 			return errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't modify with full text"))
 		case ast.ColumnOptionCheck:
 			return errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't modify with check"))
+		case ast.ColumnOptionSRID:
+			if err = setColumnSRID(col, opt); err != nil {
+				return errors.Trace(err)
+			}
 		// Ignore ColumnOptionAutoRandom. It will be handled later.
 		case ast.ColumnOptionAutoRandom:
 		default:
@@ -4370,6 +4427,9 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 	if err = processColumnOptions(sctx, newCol, specNewColumn.Options); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if !isSameColumnSRID(col.SRID, newCol.SRID) {
+		return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't change the SRID of a geometry column")
+	}
 
 	if err = checkModifyTypes(sctx, &col.FieldType, &newCol.FieldType, isColumnWithIndex(col.Name.L, t.Meta().Indices)); err != nil {
 		if strings.Contains(err.Error(), "Unsupported modifying collation") {
@@ -4470,6 +4530,9 @@ func checkColumnWithIndexConstraint(tbInfo *model.TableInfo, originalCol, newCol
 		if !modified {
 			return
 		}
+		if indexInfo.Tp == model.IndexTypeFulltext {
+			return checkFulltextIndexColumn(newCol)
+		}
 		err = checkIndexInModifiableColumns(columns, indexInfo.Columns)
 		if err != nil {
 			return
@@ -5000,18 +5063,11 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
 	}
 	tblInfo := tbl.Meta()
-	if tblInfo.GetPartitionInfo() != nil {
-		return errors.New("Extended statistics on partitioned tables are not supported now")
-	}
 	colIDs := make([]int64, 0, 2)
 	colIDSet := make(map[int64]struct{}, 2)
 	// Check whether columns exist.
@@ -5033,10 +5089,9 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
-		return errors.New("Only support Cardinality statistics type on at least 2 columns")
+	if len(colIDs) < 2 && (stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeHistogram) {
+		return errors.New("Only support Cardinality and Histogram statistics types on at least 2 columns")
 	}
-	// TODO: check whether covering index exists for cardinality / dependency types.
 
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
@@ -5055,7 +5110,19 @@ func (d *ddl) AlterTableDropStatistics(ctx sessionctx.Context, ident ast.Ident,
 	tblInfo := tbl.Meta()
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
-	return d.ddlCtx.statsHandle.MarkExtendedStatsDeleted(stats.StatsName, tblInfo.ID, ifExists)
+	err = d.ddlCtx.statsHandle.MarkExtendedStatsDeleted(stats.StatsName, tblInfo.ID, ifExists)
+	if err != nil {
+		return err
+	}
+	// The partition-level extended stats are collected by ANALYZE, and may not exist on every partition.
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			if err = d.ddlCtx.statsHandle.MarkExtendedStatsDeleted(stats.StatsName, def.ID, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // UpdateTableReplicaInfo updates the table flash replica infos.
@@ -5298,6 +5365,9 @@ func (d *ddl) dropTableObject(
 				}
 			}
 
+			if err = checkMaterializedViewTableDDL(fullti, tableInfo.Meta(), "Drop Table"); err != nil {
+				return err
+			}
 			if tableInfo.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 				return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Drop Table")
 			}
@@ -5377,6 +5447,9 @@ func (d *ddl) TruncateTable(ctx sessionctx.Context, ti ast.Ident) error {
 	if tb.Meta().IsView() || tb.Meta().IsSequence() {
 		return infoschema.ErrTableNotExists.GenWithStackByArgs(schema.Name.O, tb.Meta().Name.O)
 	}
+	if err = checkMaterializedViewTableDDL(ti, tb.Meta(), "Truncate Table"); err != nil {
+		return err
+	}
 	if tb.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Truncate Table")
 	}
@@ -5460,6 +5533,13 @@ func (d *ddl) renameTable(ctx sessionctx.Context, oldIdent, newIdent ast.Ident,
 		if tbl.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 			return errors.Trace(dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Rename Table"))
 		}
+		if err = checkMaterializedViewTableDDL(oldIdent, tbl.Meta(), "Rename Table"); err != nil {
+			return errors.Trace(err)
+		}
+		// The triggers must be in the schema of their tables.
+		if len(tbl.Meta().Triggers) > 0 && schemas[0].ID != schemas[1].ID {
+			return errors.Trace(dbterror.ErrTrgInWrongSchema)
+		}
 	}
 
 	job := &model.Job{
@@ -5502,6 +5582,9 @@ func (d *ddl) renameTables(ctx sessionctx.Context, oldIdents, newIdents []ast.Id
 			if t.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 				return errors.Trace(dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Rename Tables"))
 			}
+			if len(t.Meta().Triggers) > 0 && schemas[0].ID != schemas[1].ID {
+				return errors.Trace(dbterror.ErrTrgInWrongSchema)
+			}
 		}
 
 		tableIDs = append(tableIDs, tableID)
@@ -5813,9 +5896,18 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, stmt *ast.CreateIndexStmt) err
 
 func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
-	// not support Spatial and FullText index
-	if keyType == ast.IndexKeyTypeFullText || keyType == ast.IndexKeyTypeSpatial {
-		return dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT and SPATIAL index is not supported")
+	// not support Spatial index
+	if keyType == ast.IndexKeyTypeSpatial {
+		return dbterror.ErrUnsupportedIndexType.GenWithStack("SPATIAL index is not supported")
+	}
+	if keyType == ast.IndexKeyTypeFullText {
+		// The index type is passed to the DDL job by the index option.
+		fulltextOption := &ast.IndexOption{Tp: model.IndexTypeFulltext}
+		if indexOption != nil {
+			*fulltextOption = *indexOption
+			fulltextOption.Tp = model.IndexTypeFulltext
+		}
+		indexOption = fulltextOption
 	}
 	unique := keyType == ast.IndexKeyTypeUnique
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
@@ -5857,6 +5949,17 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 
 	tblInfo := t.Meta()
 
+	if indexOption != nil && indexOption.Tp == model.IndexTypeHypo {
+		return createHypoIndex(ctx, tblInfo, unique, indexName, indexPartSpecifications, indexOption, ifNotExists)
+	}
+
+	if keyType == ast.IndexKeyTypeFullText {
+		// Check before the job is put to the queue, the same as the normal index below.
+		if _, err = buildFulltextIndexInfo(tblInfo, indexName, indexPartSpecifications, indexOption.ParserName, model.StateNone); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	// Build hidden columns if necessary.
 	hiddenCols, err := buildHiddenColumnInfo(ctx, indexPartSpecifications, indexName, t.Meta(), t.Cols())
 	if err != nil {
@@ -5875,9 +5978,12 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	// After DDL job is put to the queue, and if the check fail, TiDB will run the DDL cancel logic.
 	// The recover step causes DDL wait a few seconds, makes the unit test painfully slow.
 	// For same reason, decide whether index is global here.
-	indexColumns, err := buildIndexColumns(ctx, finalColumns, indexPartSpecifications)
-	if err != nil {
-		return errors.Trace(err)
+	var indexColumns []*model.IndexColumn
+	if keyType != ast.IndexKeyTypeFullText {
+		indexColumns, err = buildIndexColumns(ctx, finalColumns, indexPartSpecifications)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	global := false
@@ -5929,6 +6035,76 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	return errors.Trace(err)
 }
 
+// HypoIndexIDBase is the base of the IDs of the hypothetical indexes, so they never conflict with the real ones.
+const HypoIndexIDBase = int64(1) << 48
+
+// createHypoIndex creates a hypothetical index, which is only kept in the session and is only visible to the
+// optimizer when explaining a statement. No DDL job is submitted and no index data is written.
+func createHypoIndex(ctx sessionctx.Context, tblInfo *model.TableInfo, unique bool, indexName model.CIStr,
+	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
+	for _, spec := range indexPartSpecifications {
+		if spec.Expr != nil {
+			return dbterror.ErrUnsupportedIndexType.GenWithStack("expression index is not supported by HYPO index")
+		}
+	}
+	vars := ctx.GetSessionVars()
+	hypoIndexes := vars.HypoIndexes[tblInfo.ID]
+	id := HypoIndexIDBase
+	for _, idx := range hypoIndexes {
+		if idx.Name.L == indexName.L {
+			err := dbterror.ErrDupKeyName.GenWithStack("index already exist %s", indexName)
+			if ifNotExists {
+				vars.StmtCtx.AppendNote(err)
+				return nil
+			}
+			return err
+		}
+		if idx.ID >= id {
+			id = idx.ID + 1
+		}
+	}
+	indexColumns, err := buildIndexColumns(ctx, tblInfo.Columns, indexPartSpecifications)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err = validateCommentLength(vars, indexName.String(), &indexOption.Comment, dbterror.ErrTooLongIndexComment); err != nil {
+		return errors.Trace(err)
+	}
+	idxInfo := &model.IndexInfo{
+		ID:      id,
+		Name:    indexName,
+		Table:   tblInfo.Name,
+		Columns: indexColumns,
+		Unique:  unique,
+		State:   model.StatePublic,
+		Tp:      model.IndexTypeHypo,
+		Comment: indexOption.Comment,
+	}
+	if vars.HypoIndexes == nil {
+		vars.HypoIndexes = make(map[int64][]*model.IndexInfo)
+	}
+	vars.HypoIndexes[tblInfo.ID] = append(hypoIndexes, idxInfo)
+	return nil
+}
+
+// dropHypoIndex drops the hypothetical index of the table in the session, it returns false if there is no such index.
+func dropHypoIndex(ctx sessionctx.Context, tblInfo *model.TableInfo, indexName model.CIStr) bool {
+	vars := ctx.GetSessionVars()
+	hypoIndexes := vars.HypoIndexes[tblInfo.ID]
+	for i, idx := range hypoIndexes {
+		if idx.Name.L == indexName.L {
+			hypoIndexes = append(hypoIndexes[:i:i], hypoIndexes[i+1:]...)
+			if len(hypoIndexes) == 0 {
+				delete(vars.HypoIndexes, tblInfo.ID)
+			} else {
+				vars.HypoIndexes[tblInfo.ID] = hypoIndexes
+			}
+			return true
+		}
+	}
+	return false
+}
+
 func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *ast.ReferenceDef, cols []*table.Column, tbInfo *model.TableInfo) (*model.FKInfo, error) {
 	if len(keys) != len(refer.IndexPartSpecifications) {
 		return nil, infoschema.ErrForeignKeyNotMatch.GenWithStackByArgs("foreign key without name")
@@ -6099,6 +6275,9 @@ func (d *ddl) dropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	}
 
 	indexInfo := t.Meta().FindIndexByName(indexName.L)
+	if indexInfo == nil && dropHypoIndex(ctx, t.Meta(), indexName) {
+		return nil
+	}
 
 	isPK, err := checkIsDropPrimaryKey(indexName, indexInfo, t)
 	if err != nil {
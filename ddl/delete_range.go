@@ -307,6 +307,22 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 		endKey := tablecodec.EncodeTablePrefix(tableID + 1)
 		elemID := ea.allocForPhysicalID(tableID)
 		return doInsert(ctx, s, job.ID, elemID, startKey, endKey, now, fmt.Sprintf("table ID is %d", tableID))
+	case model.ActionDropMaterializedView:
+		var (
+			baseSchemaIDs []int64
+			tableIDs      []int64
+		)
+		if err := job.DecodeArgs(&baseSchemaIDs, &tableIDs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, tid := range tableIDs {
+			startKey := tablecodec.EncodeTablePrefix(tid)
+			endKey := tablecodec.EncodeTablePrefix(tid + 1)
+			elemID := ea.allocForPhysicalID(tid)
+			if err := doInsert(ctx, s, job.ID, elemID, startKey, endKey, now, fmt.Sprintf("table ID is %d", tid)); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	case model.ActionDropTablePartition, model.ActionTruncateTablePartition:
 		var physicalTableIDs []int64
 		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
@@ -40,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/logutil"
 	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/prometheus/client_golang/prometheus"
@@ -150,6 +151,14 @@ func checkIndexColumn(ctx sessionctx.Context, col *model.ColumnInfo, indexColumn
 		return errors.Trace(dbterror.ErrJSONUsedAsKey.GenWithStackByArgs(col.Name.O))
 	}
 
+	// Geometry column cannot index until spatial indexes are supported.
+	if col.FieldType.GetType() == mysql.TypeGeometry {
+		if col.Hidden {
+			return dbterror.ErrFunctionalIndexOnJSONOrGeometryFunction
+		}
+		return errors.Trace(dbterror.ErrUnsupportedIndexType)
+	}
+
 	// Length must be specified and non-zero for BLOB and TEXT column indexes.
 	if types.IsTypeBlob(col.FieldType.GetType()) {
 		if indexColumnLen == types.UnspecifiedLength {
@@ -255,6 +264,63 @@ func buildIndexInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, indexName
 	return idxInfo, nil
 }
 
+// buildFulltextIndexInfo builds the info of a FULLTEXT index, whose entries are the tokens split by the parser.
+func buildFulltextIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSpecifications []*ast.IndexPartSpecification, parserName model.CIStr, state model.SchemaState) (*model.IndexInfo, error) {
+	if err := checkTooLongIndex(indexName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tblInfo.TempTableType != model.TempTableNone {
+		return nil, errors.Trace(dbterror.ErrTableCantHandleFt)
+	}
+	if _, ok := fulltext.GetTokenizer(parserName.L); !ok {
+		return nil, errors.Trace(dbterror.ErrUnknownFtParser.GenWithStackByArgs(parserName.O))
+	}
+	idxColumns := make([]*model.IndexColumn, 0, len(indexPartSpecifications))
+	for _, ip := range indexPartSpecifications {
+		if ip.Column == nil {
+			return nil, errors.Trace(dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT index on expression is not supported"))
+		}
+		col := model.FindColumnInfo(tblInfo.Columns, ip.Column.Name.L)
+		if col == nil {
+			return nil, dbterror.ErrKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
+		}
+		if err := checkFulltextIndexColumn(col); err != nil {
+			return nil, err
+		}
+		if ip.Length != types.UnspecifiedLength {
+			return nil, errors.Trace(dbterror.ErrIncorrectPrefixKey)
+		}
+		idxColumns = append(idxColumns, &model.IndexColumn{
+			Name:   col.Name,
+			Offset: col.Offset,
+			Length: types.UnspecifiedLength,
+		})
+	}
+	idxInfo := &model.IndexInfo{
+		Name:    indexName,
+		Columns: idxColumns,
+		State:   state,
+		Tp:      model.IndexTypeFulltext,
+	}
+	if parserName.L != fulltext.StandardParser {
+		idxInfo.FulltextParser = parserName.L
+	}
+	return idxInfo, nil
+}
+
+// checkFulltextIndexColumn checks whether the column can be a part of FULLTEXT index,
+// only the non-binary string columns are allowed.
+func checkFulltextIndexColumn(col *model.ColumnInfo) error {
+	switch col.GetType() {
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		if col.GetCharset() != charset.CharsetBin {
+			return nil
+		}
+	}
+	return errors.Trace(dbterror.ErrBadFtColumn.GenWithStackByArgs(col.Name.O))
+}
+
 func addIndexColumnFlag(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) {
 	if indexInfo.Primary {
 		for _, col := range indexInfo.Columns {
@@ -521,7 +587,11 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
-		indexInfo, err = buildIndexInfo(nil, tblInfo, indexName, indexPartSpecifications, model.StateNone)
+		if indexOption != nil && indexOption.Tp == model.IndexTypeFulltext {
+			indexInfo, err = buildFulltextIndexInfo(tblInfo, indexName, indexPartSpecifications, indexOption.ParserName, model.StateNone)
+		} else {
+			indexInfo, err = buildIndexInfo(nil, tblInfo, indexName, indexPartSpecifications, model.StateNone)
+		}
 		if err != nil {
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
@@ -0,0 +1,680 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	tidb_util "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/rowcodec"
+)
+
+// materializedViewLogPrefix is the prefix of the name of the change log table of a materialized view.
+const materializedViewLogPrefix = "mlog$_"
+
+// RestoreMaterializedViewSelect restores the definition of a materialized view. The queries are restored in the same
+// way to be matched with the definitions.
+func RestoreMaterializedViewSelect(node ast.Node) (string, error) {
+	// Always Use `format.RestoreNameBackQuotes` to restore `SELECT` statement despite the `ANSI_QUOTES` SQL Mode is enabled or not.
+	restoreFlag := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	var sb strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(restoreFlag, &sb)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// CreateMaterializedView creates the table to store the data of the materialized view. If the changes of the base
+// tables can be tracked, a change log table is created as well, and the base tables log their changes to it.
+func (d *ddl) CreateMaterializedView(ctx sessionctx.Context, s *ast.CreateMaterializedViewStmt) error {
+	ident := ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+	if _, err := is.TableByName(ident.Schema, ident.Name); err == nil {
+		err = infoschema.ErrTableExists.GenWithStackByArgs(ident)
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	if len(s.Cols) != len(s.ColTypes) {
+		return dbterror.ErrViewWrongList
+	}
+
+	tbInfo, err := buildMaterializedViewTableInfo(ctx, schema, s)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	genIDs, err := d.genGlobalIDs(2)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo.ID = genIDs[0]
+	tbInfo.MaterializedView.SelectStmt = s.Definition
+	if s.Definition == "" {
+		if tbInfo.MaterializedView.SelectStmt, err = RestoreMaterializedViewSelect(s.Select); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	var (
+		logInfo       *model.TableInfo
+		baseSchemaIDs []int64
+	)
+	if bases := collectMaterializedViewBaseTables(is, s.Select); len(bases) > 0 {
+		logName := materializedViewLogPrefix + tbInfo.Name.O
+		if len(logName) > mysql.MaxTableNameLength {
+			return dbterror.ErrTooLongIdent.GenWithStackByArgs(logName)
+		}
+		var fastRefreshBase *model.TableInfo
+		if len(bases) == 1 {
+			fastRefreshBase = bases[0].TableInfo
+			tbInfo.MaterializedView.FastRefresh, err = buildMaterializedViewFastRefreshInfo(tbInfo.MaterializedView.SelectStmt,
+				bases[0], &ast.TableName{Schema: schema.Name, Name: model.NewCIStr(logName)})
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		// The changes of the base table are only needed to refresh the view incrementally, otherwise the log only
+		// records that the base tables are changed.
+		if tbInfo.MaterializedView.FastRefresh == nil {
+			fastRefreshBase = nil
+		}
+		logInfo = buildMaterializedViewLogInfo(tbInfo, fastRefreshBase, genIDs[1], model.NewCIStr(logName))
+		tbInfo.MaterializedView.LogTableID = logInfo.ID
+		for _, base := range bases {
+			tbInfo.MaterializedView.BaseTableIDs = append(tbInfo.MaterializedView.BaseTableIDs, base.TableInfo.ID)
+			baseSchemaIDs = append(baseSchemaIDs, base.DBInfo.ID)
+		}
+		// The view can't be used to answer queries before it's refreshed for the first time.
+		if err = markMaterializedViewLog(d.store, logInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionCreateMaterializedView,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo, logInfo, baseSchemaIDs},
+	}
+	err = d.DoDDLJob(ctx, job)
+	if err != nil && s.IfNotExists && infoschema.ErrTableExists.Equal(err) {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		err = nil
+	}
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropMaterializedView drops the materialized view with its change log, and stops logging the changes of the base
+// tables.
+func (d *ddl) DropMaterializedView(ctx sessionctx.Context, s *ast.DropMaterializedViewStmt) error {
+	ident := ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+	tbl, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		err = infoschema.ErrTableDropExists.GenWithStackByArgs(ident.String())
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	mvInfo := tbl.Meta().MaterializedView
+	if mvInfo == nil {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "MATERIALIZED VIEW")
+	}
+	// The base tables may have been dropped with their databases, they're skipped when the job runs.
+	baseSchemaIDs := make([]int64, len(mvInfo.BaseTableIDs))
+	for i, baseID := range mvInfo.BaseTableIDs {
+		if base, ok := is.TableByID(baseID); ok {
+			if baseSchema, ok := is.SchemaByTable(base.Meta()); ok {
+				baseSchemaIDs[i] = baseSchema.ID
+			}
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbl.Meta().ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbl.Meta().Name.L,
+		Type:       model.ActionDropMaterializedView,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{baseSchemaIDs},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// checkMaterializedViewTableDDL checks whether the DDL can be applied to the table. The materialized views and their
+// logs are only maintained by the materialized view statements, and the base tables can't be changed in the ways
+// that break the definitions or the logs of the views.
+func checkMaterializedViewTableDDL(ident ast.Ident, tblInfo *model.TableInfo, op string) error {
+	if tblInfo.MaterializedView != nil || tblInfo.MaterializedViewLog != nil {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
+	}
+	if len(tblInfo.MaterializedViewLogIDs) > 0 {
+		return dbterror.ErrOptOnMaterializedViewBaseTable.GenWithStackByArgs(op)
+	}
+	return nil
+}
+
+func buildMaterializedViewTableInfo(ctx sessionctx.Context, schema *model.DBInfo, s *ast.CreateMaterializedViewStmt) (*model.TableInfo, error) {
+	colDefs := make([]*ast.ColumnDef, 0, len(s.Cols))
+	for i, col := range s.Cols {
+		tp := s.ColTypes[i].Clone()
+		// The constraints of the result columns don't hold for the table.
+		tp.SetFlag(tp.GetFlag() & (mysql.UnsignedFlag | mysql.BinaryFlag))
+		if types.IsString(tp.GetType()) && tp.GetFlen() == types.UnspecifiedLength {
+			tp.SetType(mysql.TypeLongBlob)
+		}
+		if tp.GetFlen() == types.UnspecifiedLength {
+			flen, decimal := mysql.GetDefaultFieldLengthAndDecimal(tp.GetType())
+			tp.SetFlen(flen)
+			if tp.GetDecimal() == types.UnspecifiedLength {
+				tp.SetDecimal(decimal)
+			}
+		}
+		colDefs = append(colDefs, &ast.ColumnDef{Name: &ast.ColumnName{Name: col}, Tp: tp})
+	}
+	createStmt := &ast.CreateTableStmt{Table: s.ViewName, Cols: colDefs}
+	tbInfo, err := BuildTableInfoWithStmt(ctx, createStmt, schema.Charset, schema.Collate, schema.PlacementPolicyRef)
+	if err != nil {
+		return nil, err
+	}
+	tbInfo.MaterializedView = &model.MaterializedViewInfo{}
+	return tbInfo, nil
+}
+
+// materializedViewBaseTable is a base table of a materialized view, and the name it's referred by in the definition.
+type materializedViewBaseTable struct {
+	*ast.TableName
+	DBInfo    *model.DBInfo
+	TableInfo *model.TableInfo
+}
+
+type materializedViewBaseTableCollector struct {
+	tables []*ast.TableName
+	hasCTE bool
+}
+
+func (c *materializedViewBaseTableCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.TableName:
+		c.tables = append(c.tables, x)
+	case *ast.SelectStmt:
+		c.hasCTE = c.hasCTE || x.With != nil
+	case *ast.SetOprStmt:
+		c.hasCTE = c.hasCTE || x.With != nil
+	}
+	return in, false
+}
+
+func (c *materializedViewBaseTableCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// collectMaterializedViewBaseTables returns the base tables of the materialized view, or nil if the changes of some
+// base tables can't be tracked, e.g. the view is based on a view or a temporary table.
+func collectMaterializedViewBaseTables(is infoschema.InfoSchema, sel ast.StmtNode) []materializedViewBaseTable {
+	collector := &materializedViewBaseTableCollector{}
+	sel.Accept(collector)
+	// The names of the common table expressions can't be told from the table names.
+	if collector.hasCTE {
+		return nil
+	}
+	bases := make([]materializedViewBaseTable, 0, len(collector.tables))
+	for _, tn := range collector.tables {
+		if tidb_util.IsMemOrSysDB(tn.Schema.L) {
+			return nil
+		}
+		tbl, err := is.TableByName(tn.Schema, tn.Name)
+		if err != nil {
+			return nil
+		}
+		tblInfo := tbl.Meta()
+		if !tblInfo.IsBaseTable() || tblInfo.TempTableType != model.TempTableNone || tblInfo.MaterializedViewLog != nil {
+			return nil
+		}
+		dbInfo, ok := is.SchemaByTable(tblInfo)
+		if !ok {
+			return nil
+		}
+		bases = append(bases, materializedViewBaseTable{TableName: tn, DBInfo: dbInfo, TableInfo: tblInfo})
+	}
+	// A table joined with itself is logged once.
+	dedup := bases[:0]
+	for _, base := range bases {
+		found := false
+		for _, b := range dedup {
+			found = found || b.TableInfo.ID == base.TableInfo.ID
+		}
+		if !found {
+			dedup = append(dedup, base)
+		}
+	}
+	return dedup
+}
+
+// buildMaterializedViewLogInfo builds the change log table of the materialized view. It has the columns of the base
+// table if the view can be refreshed incrementally, and the column to record the operation. The columns have the
+// same IDs as the base table, so the log rows can be built from the rows of the base table directly.
+func buildMaterializedViewLogInfo(tbInfo, baseInfo *model.TableInfo, logID int64, logName model.CIStr) *model.TableInfo {
+	logInfo := &model.TableInfo{
+		ID:                  logID,
+		Name:                logName,
+		Version:             model.CurrLatestTableInfoVersion,
+		Charset:             tbInfo.Charset,
+		Collate:             tbInfo.Collate,
+		MaterializedViewLog: &model.MaterializedViewLogInfo{MViewID: tbInfo.ID},
+	}
+	if baseInfo != nil {
+		for _, col := range baseInfo.Columns {
+			if col.State != model.StatePublic || col.Hidden {
+				continue
+			}
+			logCol := &model.ColumnInfo{
+				ID:        col.ID,
+				Name:      col.Name,
+				Offset:    len(logInfo.Columns),
+				FieldType: *col.FieldType.Clone(),
+				State:     model.StatePublic,
+				Version:   col.Version,
+			}
+			logCol.SetFlag(logCol.GetFlag() & (mysql.UnsignedFlag | mysql.BinaryFlag))
+			logInfo.Columns = append(logInfo.Columns, logCol)
+		}
+		logInfo.MaxColumnID = baseInfo.MaxColumnID
+	}
+	logInfo.MaxColumnID++
+	logInfo.Columns = append(logInfo.Columns, &model.ColumnInfo{
+		ID:        logInfo.MaxColumnID,
+		Name:      model.MaterializedViewLogOpName,
+		Offset:    len(logInfo.Columns),
+		FieldType: *types.NewFieldType(mysql.TypeTiny),
+		State:     model.StatePublic,
+		Version:   model.CurrLatestColumnInfoVersion,
+	})
+	return logInfo
+}
+
+// markMaterializedViewLog writes the mark that the view needs to be refreshed completely to the log. It's written
+// before the log table is created, at the handle which is never allocated to the rows.
+func markMaterializedViewLog(store kv.Storage, logInfo *model.TableInfo) error {
+	opCol := logInfo.Columns[len(logInfo.Columns)-1]
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	value, err := tablecodec.EncodeRow(sc, []types.Datum{types.NewIntDatum(0)}, []int64{opCol.ID}, nil, nil, &rowcodec.Encoder{Enable: true})
+	if err != nil {
+		return err
+	}
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnDDL)
+	return kv.RunInNewTxn(ctx, store, true, func(ctx context.Context, txn kv.Transaction) error {
+		return txn.Set(tablecodec.EncodeRowKeyWithHandle(logInfo.ID, kv.IntHandle(0)), value)
+	})
+}
+
+// buildMaterializedViewFastRefreshInfo checks whether the materialized view can be refreshed incrementally, and
+// builds the query to aggregate the changes in the log. Only the views like
+//
+//	SELECT g1, ..., COUNT(*), COUNT(c1), SUM(c2), ... FROM t [WHERE ...] GROUP BY g1, ...
+//
+// are supported, where the arguments of SUM are NOT NULL numeric columns, so the aggregates of a group can be
+// updated by adding the deltas. It returns nil if the view can't be refreshed incrementally.
+func buildMaterializedViewFastRefreshInfo(selectText string, base materializedViewBaseTable, logName *ast.TableName) (*model.MaterializedViewFastRefreshInfo, error) {
+	// The definition is parsed again so the statement of the user is kept unchanged.
+	stmt, err := parser.New().ParseOneStmt(selectText, "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.Distinct || sel.With != nil || sel.GroupBy == nil || sel.Having != nil || sel.OrderBy != nil ||
+		sel.Limit != nil || len(sel.WindowSpecs) > 0 || sel.From == nil || sel.From.TableRefs.Right != nil {
+		return nil, nil
+	}
+	source, ok := sel.From.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := source.Source.(*ast.TableName); !ok {
+		return nil, nil
+	}
+	if sel.Where != nil {
+		checker := &fastRefreshWhereChecker{ok: true}
+		sel.Where.Accept(checker)
+		if !checker.ok {
+			return nil, nil
+		}
+	}
+	groupByCols := make([]*ast.ColumnName, 0, len(sel.GroupBy.Items))
+	for _, item := range sel.GroupBy.Items {
+		col, ok := item.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return nil, nil
+		}
+		groupByCols = append(groupByCols, col.Name)
+	}
+
+	info := &model.MaterializedViewFastRefreshInfo{CountOffset: -1}
+	groupBySelected := make([]bool, len(groupByCols))
+	opCol := &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: model.MaterializedViewLogOpName}}
+	for i, field := range sel.Fields.Fields {
+		if field.WildCard != nil {
+			return nil, nil
+		}
+		switch x := field.Expr.(type) {
+		case *ast.ColumnNameExpr:
+			found := false
+			for j, col := range groupByCols {
+				if col.Name.L == x.Name.Name.L {
+					groupBySelected[j], found = true, true
+				}
+			}
+			if !found {
+				return nil, nil
+			}
+			info.GroupByOffsets = append(info.GroupByOffsets, i)
+		case *ast.AggregateFuncExpr:
+			if x.Distinct || len(x.Args) != 1 {
+				return nil, nil
+			}
+			var (
+				argCol  *model.ColumnInfo
+				notNull bool
+			)
+			switch arg := x.Args[0].(type) {
+			case ast.ValueExpr:
+				notNull = arg.GetValue() != nil
+			case *ast.ColumnNameExpr:
+				argCol = model.FindColumnInfo(base.TableInfo.Columns, arg.Name.Name.L)
+				if argCol == nil {
+					return nil, nil
+				}
+				notNull = mysql.HasNotNullFlag(argCol.GetFlag())
+			default:
+				return nil, nil
+			}
+			var delta ast.ExprNode
+			switch strings.ToLower(x.F) {
+			case ast.AggFuncCount:
+				if notNull {
+					if info.CountOffset < 0 {
+						info.CountOffset = i
+					}
+					delta = opCol
+				} else if argCol != nil {
+					// COUNT(c) is changed by the rows whose c is not NULL.
+					delta = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.If), Args: []ast.ExprNode{
+						&ast.IsNullExpr{Expr: x.Args[0]}, ast.NewValueExpr(0, "", ""), opCol,
+					}}
+				} else {
+					return nil, nil
+				}
+			case ast.AggFuncSum:
+				if argCol == nil || !notNull || !types.IsTypeNumeric(argCol.GetType()) {
+					return nil, nil
+				}
+				delta = &ast.BinaryOperationExpr{Op: opcode.Mul, L: opCol, R: x.Args[0]}
+			default:
+				return nil, nil
+			}
+			field.Expr = &ast.AggregateFuncExpr{F: ast.AggFuncSum, Args: []ast.ExprNode{delta}}
+			field.AsName = model.CIStr{}
+		default:
+			return nil, nil
+		}
+	}
+	if info.CountOffset < 0 {
+		return nil, nil
+	}
+	for _, selected := range groupBySelected {
+		if !selected {
+			return nil, nil
+		}
+	}
+
+	// Read the changes from the log, which is referred by the name of the base table.
+	if source.AsName.L == "" {
+		source.AsName = base.Name
+	}
+	source.Source = logName
+	sel.Accept(&columnSchemaEraser{schema: base.Schema.L})
+	if info.DeltaSelectStmt, err = RestoreMaterializedViewSelect(sel); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// fastRefreshWhereChecker checks whether the WHERE clause can be applied to the changes in the log.
+type fastRefreshWhereChecker struct {
+	ok bool
+}
+
+func (c *fastRefreshWhereChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch in.(type) {
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.AggregateFuncExpr, *ast.WindowFuncExpr, *ast.VariableExpr:
+		c.ok = false
+		return in, true
+	}
+	return in, false
+}
+
+func (c *fastRefreshWhereChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.ok
+}
+
+// columnSchemaEraser erases the schema names of the columns, since the log is in the schema of the view.
+type columnSchemaEraser struct {
+	schema string
+}
+
+func (e *columnSchemaEraser) Enter(in ast.Node) (ast.Node, bool) {
+	if col, ok := in.(*ast.ColumnName); ok && col.Schema.L == e.schema {
+		col.Schema = model.CIStr{}
+	}
+	return in, false
+}
+
+func (e *columnSchemaEraser) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func onCreateMaterializedView(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	schemaID := job.SchemaID
+	tbInfo := &model.TableInfo{}
+	var (
+		logInfo       *model.TableInfo
+		baseSchemaIDs []int64
+	)
+	if err := job.DecodeArgs(tbInfo, &logInfo, &baseSchemaIDs); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfos := []*model.TableInfo{tbInfo}
+	if logInfo != nil {
+		tblInfos = append(tblInfos, logInfo)
+	}
+	for _, tblInfo := range tblInfos {
+		if err := checkTableNotExists(d, t, schemaID, tblInfo.Name.L); err != nil {
+			if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableExists.Equal(err) {
+				job.State = model.JobStateCancelled
+			}
+			return ver, errors.Trace(err)
+		}
+	}
+	baseInfos := make([]*model.TableInfo, 0, len(baseSchemaIDs))
+	for i, baseID := range tbInfo.MaterializedView.BaseTableIDs {
+		baseInfo, err := getTableInfo(t, baseID, baseSchemaIDs[i])
+		if err != nil {
+			if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err) {
+				job.State = model.JobStateCancelled
+			}
+			return ver, errors.Trace(err)
+		}
+		baseInfo.MaterializedViewLogIDs = append(append([]int64(nil), baseInfo.MaterializedViewLogIDs...), logInfo.ID)
+		baseInfos = append(baseInfos, baseInfo)
+	}
+
+	job.CtxVars = []interface{}{buildMaterializedViewAffects(schemaID, logInfo, true, baseInfos, baseSchemaIDs)}
+	ver, err := updateSchemaVersion(d, t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	for _, tblInfo := range tblInfos {
+		tblInfo.State = model.StatePublic
+		tblInfo.UpdateTS = t.StartTS
+		if err = createTableOrViewWithCheck(t, job, schemaID, tblInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	for i, baseInfo := range baseInfos {
+		if err = t.UpdateTable(baseSchemaIDs[i], baseInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tbInfo)
+	for _, tblInfo := range tblInfos {
+		asyncNotifyEvent(d, &util.Event{Tp: model.ActionCreateTable, TableInfo: tblInfo})
+	}
+	return ver, nil
+}
+
+func onDropMaterializedView(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var baseSchemaIDs []int64
+	if err := job.DecodeArgs(&baseSchemaIDs); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tbInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	mvInfo := tbInfo.MaterializedView
+	if mvInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrWrongObject.GenWithStackByArgs(job.SchemaName, tbInfo.Name, "MATERIALIZED VIEW")
+	}
+	var logInfo *model.TableInfo
+	dropIDs := []int64{tbInfo.ID}
+	if mvInfo.LogTableID != 0 {
+		logInfo = &model.TableInfo{ID: mvInfo.LogTableID}
+		dropIDs = append(dropIDs, mvInfo.LogTableID)
+	}
+	baseInfos := make([]*model.TableInfo, 0, len(mvInfo.BaseTableIDs))
+	existBaseSchemaIDs := make([]int64, 0, len(mvInfo.BaseTableIDs))
+	for i, baseID := range mvInfo.BaseTableIDs {
+		if i >= len(baseSchemaIDs) || baseSchemaIDs[i] == 0 {
+			continue
+		}
+		baseInfo, err := getTableInfo(t, baseID, baseSchemaIDs[i])
+		if err != nil {
+			if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err) {
+				continue
+			}
+			return ver, errors.Trace(err)
+		}
+		logIDs := make([]int64, 0, len(baseInfo.MaterializedViewLogIDs))
+		for _, id := range baseInfo.MaterializedViewLogIDs {
+			if id != mvInfo.LogTableID {
+				logIDs = append(logIDs, id)
+			}
+		}
+		baseInfo.MaterializedViewLogIDs = logIDs
+		baseInfos = append(baseInfos, baseInfo)
+		existBaseSchemaIDs = append(existBaseSchemaIDs, baseSchemaIDs[i])
+	}
+
+	job.CtxVars = []interface{}{buildMaterializedViewAffects(job.SchemaID, logInfo, false, baseInfos, existBaseSchemaIDs)}
+	ver, err = updateSchemaVersion(d, t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	for i, baseInfo := range baseInfos {
+		if err = t.UpdateTable(existBaseSchemaIDs[i], baseInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	for _, id := range dropIDs {
+		if err = t.DropTableOrView(job.SchemaID, id); err != nil {
+			return ver, errors.Trace(err)
+		}
+		if err = t.GetAutoIDAccessors(job.SchemaID, id).Del(); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	// Finish this job.
+	tbInfo.State = model.StateNone
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tbInfo)
+	job.Args = append(job.Args, dropIDs)
+	return ver, nil
+}
+
+// buildMaterializedViewAffects builds the affected options of the schema diff, the log table is created or dropped
+// with the view, and the base tables are updated.
+func buildMaterializedViewAffects(schemaID int64, logInfo *model.TableInfo, create bool, baseInfos []*model.TableInfo, baseSchemaIDs []int64) []*model.AffectedOption {
+	affects := make([]*model.AffectedOption, 0, len(baseInfos)+1)
+	if logInfo != nil {
+		logAffect := &model.AffectedOption{SchemaID: schemaID, OldSchemaID: schemaID}
+		if create {
+			logAffect.TableID = logInfo.ID
+		} else {
+			logAffect.OldTableID = logInfo.ID
+		}
+		affects = append(affects, logAffect)
+	}
+	for i, baseInfo := range baseInfos {
+		affects = append(affects, &model.AffectedOption{
+			SchemaID:    baseSchemaIDs[i],
+			OldSchemaID: baseSchemaIDs[i],
+			TableID:     baseInfo.ID,
+			OldTableID:  baseInfo.ID,
+		})
+	}
+	return affects
+}
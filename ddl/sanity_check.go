@@ -104,6 +104,12 @@ func expectedDeleteRangeCnt(ctx delRangeCntCtx, job *model.Job) (int, error) {
 			return 0, errors.Trace(err)
 		}
 		return len(physicalTableIDs), nil
+	case model.ActionDropMaterializedView:
+		var baseSchemaIDs, tableIDs []int64
+		if err := job.DecodeArgs(&baseSchemaIDs, &tableIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(tableIDs), nil
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		hasDelRange := job.State == model.JobStateRollbackDone
 		if !hasDelRange {
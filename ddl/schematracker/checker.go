@@ -231,6 +231,26 @@ func (d Checker) RecoverTable(ctx sessionctx.Context, recoverInfo *ddl.RecoverIn
 	panic("implement me")
 }
 
+// CreateMaterializedView implements the DDL interface.
+func (d Checker) CreateMaterializedView(ctx sessionctx.Context, stmt *ast.CreateMaterializedViewStmt) error {
+	return d.realDDL.CreateMaterializedView(ctx, stmt)
+}
+
+// DropMaterializedView implements the DDL interface.
+func (d Checker) DropMaterializedView(ctx sessionctx.Context, stmt *ast.DropMaterializedViewStmt) error {
+	return d.realDDL.DropMaterializedView(ctx, stmt)
+}
+
+// CreateTrigger implements the DDL interface.
+func (d Checker) CreateTrigger(ctx sessionctx.Context, stmt *ast.CreateTriggerStmt) error {
+	return d.realDDL.CreateTrigger(ctx, stmt)
+}
+
+// DropTrigger implements the DDL interface.
+func (d Checker) DropTrigger(ctx sessionctx.Context, stmt *ast.DropTriggerStmt) error {
+	return d.realDDL.DropTrigger(ctx, stmt)
+}
+
 // DropView implements the DDL interface.
 func (d Checker) DropView(ctx sessionctx.Context, stmt *ast.DropTableStmt) (err error) {
 	err = d.realDDL.DropView(ctx, stmt)
@@ -301,6 +301,26 @@ func (d SchemaTracker) RecoverTable(ctx sessionctx.Context, recoverInfo *ddl.Rec
 	return nil
 }
 
+// CreateMaterializedView implements the DDL interface, which is no-op in DM's case.
+func (d SchemaTracker) CreateMaterializedView(ctx sessionctx.Context, stmt *ast.CreateMaterializedViewStmt) error {
+	return nil
+}
+
+// DropMaterializedView implements the DDL interface, which is no-op in DM's case.
+func (d SchemaTracker) DropMaterializedView(ctx sessionctx.Context, stmt *ast.DropMaterializedViewStmt) error {
+	return nil
+}
+
+// CreateTrigger implements the DDL interface, which is no-op in DM's case.
+func (d SchemaTracker) CreateTrigger(ctx sessionctx.Context, stmt *ast.CreateTriggerStmt) error {
+	return nil
+}
+
+// DropTrigger implements the DDL interface, which is no-op in DM's case.
+func (d SchemaTracker) DropTrigger(ctx sessionctx.Context, stmt *ast.DropTriggerStmt) error {
+	return nil
+}
+
 // DropView implements the DDL interface.
 func (d SchemaTracker) DropView(ctx sessionctx.Context, stmt *ast.DropTableStmt) (err error) {
 	notExistTables := make([]string, 0, len(stmt.Tables))
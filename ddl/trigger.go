@@ -0,0 +1,241 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	tidb_util "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+// CreateTrigger creates a trigger on the table. The triggers are stored in the meta of their tables, so they're
+// dropped together with the tables.
+func (d *ddl) CreateTrigger(ctx sessionctx.Context, s *ast.CreateTriggerStmt) error {
+	// The trigger must be in the schema of its table.
+	if s.Name.Schema.L != s.Table.Schema.L {
+		return dbterror.ErrTrgInWrongSchema
+	}
+	if tidb_util.IsMemOrSysDB(s.Table.Schema.L) {
+		return dbterror.ErrNoTriggersOnSystemSchema
+	}
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tb.Meta()
+	if !tbInfo.IsBaseTable() || tbInfo.TempTableType != model.TempTableNone {
+		return dbterror.ErrTrgOnViewOrTempTable.GenWithStackByArgs(tbInfo.Name.O)
+	}
+	if err = checkMaterializedViewTableDDL(ident, tbInfo, "CREATE TRIGGER"); err != nil {
+		return errors.Trace(err)
+	}
+	if _, trigger := findTriggerInSchema(d.GetInfoSchemaWithInterceptor(ctx), schema.Name, s.Name.Name.L); trigger != nil {
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(dbterror.ErrTrgAlreadyExists)
+			return nil
+		}
+		return dbterror.ErrTrgAlreadyExists
+	}
+
+	sessVars := ctx.GetSessionVars()
+	sqlMode, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.SQLModeVar)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	charsetClient, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.CharacterSetClient)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	collation, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.CollationConnection)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	trigger := &model.TriggerInfo{
+		Name:                s.Name.Name,
+		Time:                s.Time,
+		Event:               s.Event,
+		Body:                s.Body.Text(),
+		Definer:             s.Definer,
+		SQLMode:             sqlMode,
+		CharsetClient:       charsetClient,
+		CollationConnection: collation,
+		DBCollation:         schema.Collate,
+		Created:             time.Now(),
+	}
+	var refTrigger model.CIStr
+	precedes := false
+	if s.Order != nil {
+		refTrigger, precedes = s.Order.Trigger, s.Order.Tp == ast.TriggerPrecedes
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionCreateTrigger,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{trigger, refTrigger, precedes},
+	}
+	err = d.DoDDLJob(ctx, job)
+	if err != nil && s.IfNotExists && dbterror.ErrTrgAlreadyExists.Equal(err) {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		err = nil
+	}
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropTrigger drops the trigger. The names of the triggers are unique in a schema, so the table is found by the name
+// of the trigger.
+func (d *ddl) DropTrigger(ctx sessionctx.Context, s *ast.DropTriggerStmt) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(s.Name.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.Name.Schema)
+	}
+	tbInfo, _ := findTriggerInSchema(is, schema.Name, s.Name.Name.L)
+	if tbInfo == nil {
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(dbterror.ErrTrgDoesNotExist)
+			return nil
+		}
+		return dbterror.ErrTrgDoesNotExist
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionDropTrigger,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{s.Name.Name},
+	}
+	err := d.DoDDLJob(ctx, job)
+	if err != nil && s.IfExists && dbterror.ErrTrgDoesNotExist.Equal(err) {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		err = nil
+	}
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// findTriggerInSchema finds the trigger and its table in the schema.
+func findTriggerInSchema(is infoschema.InfoSchema, schema model.CIStr, name string) (*model.TableInfo, *model.TriggerInfo) {
+	for _, tbl := range is.SchemaTables(schema) {
+		if trigger := tbl.Meta().FindTrigger(name); trigger != nil {
+			return tbl.Meta(), trigger
+		}
+	}
+	return nil, nil
+}
+
+func onCreateTrigger(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	trigger := &model.TriggerInfo{}
+	var refTrigger model.CIStr
+	var precedes bool
+	if err := job.DecodeArgs(trigger, &refTrigger, &precedes); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// The trigger may be created on another table of the schema concurrently.
+	tables, err := t.ListTables(job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	for _, tbl := range tables {
+		if tbl.FindTrigger(trigger.Name.L) != nil {
+			job.State = model.JobStateCancelled
+			return ver, dbterror.ErrTrgAlreadyExists
+		}
+	}
+
+	// The triggers of the same action time and event are activated in the order they're stored. A new trigger is
+	// activated after the existing ones unless FOLLOWS or PRECEDES is specified.
+	pos := len(tblInfo.Triggers)
+	if refTrigger.L != "" {
+		pos = -1
+		for i, trg := range tblInfo.Triggers {
+			if trg.Name.L == refTrigger.L && trg.Time == trigger.Time && trg.Event == trigger.Event {
+				pos = i
+				if !precedes {
+					pos++
+				}
+				break
+			}
+		}
+		if pos < 0 {
+			job.State = model.JobStateCancelled
+			return ver, dbterror.ErrReferencedTrgDoesNotExist.GenWithStackByArgs(refTrigger.O)
+		}
+	}
+	triggers := make([]*model.TriggerInfo, 0, len(tblInfo.Triggers)+1)
+	triggers = append(triggers, tblInfo.Triggers[:pos]...)
+	triggers = append(triggers, trigger)
+	tblInfo.Triggers = append(triggers, tblInfo.Triggers[pos:]...)
+
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropTrigger(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var name model.CIStr
+	if err := job.DecodeArgs(&name); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	triggers := make([]*model.TriggerInfo, 0, len(tblInfo.Triggers))
+	for _, trigger := range tblInfo.Triggers {
+		if trigger.Name.L != name.L {
+			triggers = append(triggers, trigger)
+		}
+	}
+	if len(triggers) == len(tblInfo.Triggers) {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrTrgDoesNotExist
+	}
+	tblInfo.Triggers = triggers
+
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
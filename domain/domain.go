@@ -87,6 +87,7 @@ type Domain struct {
 	exit                 chan struct{}
 	etcdClient           *clientv3.Client
 	sysVarCache          sysVarCache // replaces GlobalVariableCache
+	udfCacheRebuildLock  sync.Mutex  // protects concurrent rebuild of the loadable function cache
 	slowQuery            *topNSlowQueries
 	expensiveQueryHandle *expensivequery.Handle
 	wg                   util.WaitGroupWrapper
@@ -1129,6 +1130,52 @@ func (do *Domain) LoadSysVarCacheLoop(ctx sessionctx.Context) error {
 	return nil
 }
 
+// LoadUDFCacheLoop loads the loadable functions and creates a goroutine reloading them in a loop,
+// it should be called only once in BootstrapSession.
+func (do *Domain) LoadUDFCacheLoop() error {
+	err := do.rebuildUDFCache()
+	if err != nil {
+		return err
+	}
+	var watchCh clientv3.WatchChan
+	duration := 30 * time.Second
+	if do.etcdClient != nil {
+		watchCh = do.etcdClient.Watch(context.Background(), udfCacheKey)
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("LoadUDFCacheLoop exited.")
+			util.Recover(metrics.LabelDomain, "LoadUDFCacheLoop", nil, false)
+		}()
+		var count int
+		for {
+			ok := true
+			select {
+			case <-do.exit:
+				return
+			case _, ok = <-watchCh:
+			case <-time.After(duration):
+			}
+			if !ok {
+				logutil.BgLogger().Error("LoadUDFCacheLoop loop watch channel closed")
+				watchCh = do.etcdClient.Watch(context.Background(), udfCacheKey)
+				count++
+				if count > 10 {
+					time.Sleep(time.Duration(count) * time.Second)
+				}
+				continue
+			}
+			count = 0
+			if err := do.rebuildUDFCache(); err != nil {
+				logutil.BgLogger().Error("LoadUDFCacheLoop failed", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
 // PrivilegeHandle returns the MySQLPrivilege.
 func (do *Domain) PrivilegeHandle() *privileges.Handle {
 	return do.privHandle
@@ -1185,6 +1232,10 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if err == nil && variable.TiDBOptOn(optVal) {
 					do.bindHandle.CaptureBaselines()
 				}
+				optVal, err = do.GetGlobalVar(variable.TiDBCapturePlanRegression)
+				if err == nil && variable.TiDBOptOn(optVal) {
+					do.bindHandle.CapturePlanRegressions()
+				}
 				do.bindHandle.SaveEvolveTasksToStore()
 			case <-gcBindTicker.C:
 				if !owner.IsOwner() {
@@ -1588,6 +1639,7 @@ func (do *Domain) ExpensiveQueryHandle() *expensivequery.Handle {
 const (
 	privilegeKey   = "/tidb/privilege"
 	sysVarCacheKey = "/tidb/sysvars"
+	udfCacheKey    = "/tidb/udfs"
 )
 
 // NotifyUpdatePrivilege updates privilege key in etcd, TiDB client that watches
@@ -1638,6 +1690,21 @@ func (do *Domain) NotifyUpdateSysVarCache() {
 	}
 }
 
+// NotifyUpdateUDFCache updates the loadable function cache key in etcd, which other TiDB
+// clients are subscribed to for updates. For the caller, the cache is also rebuilt
+// synchronously so that the effect is immediate.
+func (do *Domain) NotifyUpdateUDFCache() error {
+	if do.etcdClient != nil {
+		row := do.etcdClient.KV
+		_, err := row.Put(context.Background(), udfCacheKey, "")
+		if err != nil {
+			logutil.BgLogger().Warn("notify update loadable function cache failed", zap.Error(err))
+		}
+	}
+	// update locally
+	return do.rebuildUDFCache()
+}
+
 // LoadSigningCertLoop loads the signing cert periodically to make sure it's fresh new.
 func (do *Domain) LoadSigningCertLoop() {
 	do.wg.Add(1)
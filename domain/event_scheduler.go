@@ -0,0 +1,379 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+const (
+	eventSchedulerPrompt   = "event-scheduler"
+	eventSchedulerOwnerKey = "/tidb/event_scheduler/owner"
+)
+
+// EventSchedulerInterval is the interval to check whether there are events to execute.
+var EventSchedulerInterval = time.Second
+
+// EventTimeFormat is the format of the times of the events saved in mysql.events, which are in UTC.
+const EventTimeFormat = "2006-01-02 15:04:05"
+
+// EventInfo is the definition and the execution status of an event saved in mysql.events.
+type EventInfo struct {
+	DB      string
+	Name    string
+	Body    string
+	Definer string
+	// ExecuteAt is the execution time of a one-time event. It's zero for a recurring event.
+	ExecuteAt time.Time
+	// IntervalValue and IntervalField are the interval of a recurring event, such as '1:30' and HOUR_MINUTE.
+	IntervalValue string
+	IntervalField string
+	// Starts and Ends are the time range of a recurring event. Ends is zero if the range is unbounded.
+	Starts              time.Time
+	Ends                time.Time
+	Status              string
+	Preserve            bool
+	SQLMode             string
+	Comment             string
+	Originator          uint64
+	TimeZone            string
+	CharsetClient       string
+	CollationConnection string
+	DBCollation         string
+	Created             time.Time
+	LastAltered         time.Time
+	// LastExecuted is the time when the event is started to execute for the last time. It's zero if the event has
+	// never been executed.
+	LastExecuted time.Time
+	Executions   uint64
+	Failures     uint64
+	// LastError is the error message of the last execution. It's empty if the last execution succeeded.
+	LastError string
+}
+
+const eventColumns = "db, name, body, definer, execute_at, interval_value, interval_field, starts, ends, status, " +
+	"on_completion, sql_mode, comment, originator, time_zone, character_set_client, collation_connection, db_collation, " +
+	"created, last_altered, last_executed, executions, failures, last_error"
+
+// LoadEvents loads the events matching the condition from mysql.events, ordered by the schema and the name.
+func LoadEvents(ctx context.Context, sctx sessionctx.Context, where string, args ...interface{}) ([]*EventInfo, error) {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnEvent)
+	sql := "SELECT " + eventColumns + " FROM mysql.events"
+	if where != "" {
+		sql += " WHERE " + where
+	}
+	sql += " ORDER BY db, name"
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil, sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events := make([]*EventInfo, 0, len(rows))
+	for _, row := range rows {
+		event, err := decodeEvent(row)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// LoadEvent loads the event from mysql.events. It returns nil if the event doesn't exist.
+func LoadEvent(ctx context.Context, sctx sessionctx.Context, db, name string) (*EventInfo, error) {
+	events, err := LoadEvents(ctx, sctx, "db = %? AND name = %?", strings.ToLower(db), name)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+func decodeEvent(row chunk.Row) (*EventInfo, error) {
+	e := &EventInfo{
+		DB:                  row.GetString(0),
+		Name:                row.GetString(1),
+		Body:                row.GetString(2),
+		Definer:             row.GetString(3),
+		IntervalValue:       row.GetString(5),
+		IntervalField:       row.GetString(6),
+		Status:              row.GetEnum(9).String(),
+		Preserve:            row.GetEnum(10).String() == "PRESERVE",
+		SQLMode:             row.GetString(11),
+		Comment:             row.GetString(12),
+		Originator:          row.GetUint64(13),
+		TimeZone:            row.GetString(14),
+		CharsetClient:       row.GetString(15),
+		CollationConnection: row.GetString(16),
+		DBCollation:         row.GetString(17),
+		Executions:          row.GetUint64(21),
+		Failures:            row.GetUint64(22),
+		LastError:           row.GetString(23),
+	}
+	for idx, t := range map[int]*time.Time{4: &e.ExecuteAt, 7: &e.Starts, 8: &e.Ends, 18: &e.Created, 19: &e.LastAltered, 20: &e.LastExecuted} {
+		if row.IsNull(idx) {
+			continue
+		}
+		goTime, err := row.GetTime(idx).GoTime(time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		*t = goTime
+	}
+	return e, nil
+}
+
+// IsRecurring returns whether the event is executed repeatedly.
+func (e *EventInfo) IsRecurring() bool {
+	return e.ExecuteAt.IsZero()
+}
+
+// CreateStmt rebuilds the CREATE statement of the event, in the format of SHOW CREATE EVENT. The times of the
+// schedule are shown in the location loc.
+func (e *EventInfo) CreateStmt(loc *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString("CREATE DEFINER=")
+	user, host := e.Definer, ""
+	if idx := strings.LastIndexByte(e.Definer, '@'); idx >= 0 {
+		user, host = e.Definer[:idx], e.Definer[idx+1:]
+	}
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	restoreCtx.WriteName(user)
+	sb.WriteString("@")
+	restoreCtx.WriteName(host)
+	sb.WriteString(" EVENT ")
+	restoreCtx.WriteName(e.Name)
+	sb.WriteString(" ON SCHEDULE ")
+	if e.IsRecurring() {
+		sb.WriteString("EVERY ")
+		if _, err := strconv.ParseInt(e.IntervalValue, 10, 64); err == nil {
+			sb.WriteString(e.IntervalValue)
+		} else {
+			restoreCtx.WriteString(e.IntervalValue)
+		}
+		sb.WriteString(" ")
+		sb.WriteString(e.IntervalField)
+		sb.WriteString(" STARTS ")
+		restoreCtx.WriteString(e.Starts.In(loc).Format(EventTimeFormat))
+		if !e.Ends.IsZero() {
+			sb.WriteString(" ENDS ")
+			restoreCtx.WriteString(e.Ends.In(loc).Format(EventTimeFormat))
+		}
+	} else {
+		sb.WriteString("AT ")
+		restoreCtx.WriteString(e.ExecuteAt.In(loc).Format(EventTimeFormat))
+	}
+	if e.Preserve {
+		sb.WriteString(" ON COMPLETION PRESERVE")
+	} else {
+		sb.WriteString(" ON COMPLETION NOT PRESERVE")
+	}
+	switch e.Status {
+	case "ENABLED":
+		sb.WriteString(" ENABLE")
+	case "DISABLED":
+		sb.WriteString(" DISABLE")
+	default:
+		sb.WriteString(" DISABLE ON SLAVE")
+	}
+	if e.Comment != "" {
+		sb.WriteString(" COMMENT ")
+		restoreCtx.WriteString(e.Comment)
+	}
+	sb.WriteString(" DO ")
+	sb.WriteString(e.Body)
+	return sb.String()
+}
+
+// NextExecution returns the first execution time of the event after the time after. The second return value is false
+// if the event won't be executed after that time.
+func (e *EventInfo) NextExecution(after time.Time) (time.Time, bool) {
+	if !e.IsRecurring() {
+		return e.ExecuteAt, e.ExecuteAt.After(after)
+	}
+	years, months, days, nanos, err := ParseEventInterval(e.IntervalValue, e.IntervalField)
+	if err != nil {
+		return time.Time{}, false
+	}
+	nth := func(n int64) time.Time {
+		return types.AddDate(n*years, n*months, n*days, e.Starts.Add(time.Duration(n*nanos)))
+	}
+	// Estimate the number of the intervals since the start time, then move forward to the first execution time after
+	// the given time, the estimation may be inaccurate because of the different lengths of the months.
+	var n int64
+	if elapsed := after.Sub(e.Starts); elapsed > 0 {
+		approx := time.Duration(years)*365*24*time.Hour + time.Duration(months)*28*24*time.Hour +
+			time.Duration(days)*24*time.Hour + time.Duration(nanos)
+		if n = int64(elapsed/approx) - 1; n < 0 {
+			n = 0
+		}
+		for n > 0 && nth(n).After(after) {
+			n--
+		}
+	}
+	next := nth(n)
+	for !next.After(after) {
+		n++
+		next = nth(n)
+	}
+	if !e.Ends.IsZero() && next.After(e.Ends) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// ParseEventInterval parses the interval of a recurring event to the numbers of years, months, days and nanoseconds.
+func ParseEventInterval(value, field string) (years, months, days, nanos int64, err error) {
+	years, months, days, nanos, _, err = types.ParseDurationValue(field, value)
+	return
+}
+
+// EventSchedulerLoop creates a goroutine that executes the due events if event_scheduler is ON. Only the owner of
+// the event scheduler in the cluster executes the events. It should be called only once in BootstrapSession.
+func (do *Domain) EventSchedulerLoop() {
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("eventSchedulerLoop exited.")
+			util.Recover(metrics.LabelDomain, "eventSchedulerLoop", nil, false)
+		}()
+		owner := do.newOwnerManager(eventSchedulerPrompt, eventSchedulerOwnerKey)
+		ticker := time.NewTicker(EventSchedulerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-do.exit:
+				owner.Cancel()
+				return
+			case <-ticker.C:
+			}
+			if !owner.IsOwner() {
+				continue
+			}
+			if val, err := do.GetGlobalVar(variable.EventScheduler); err != nil || !variable.TiDBOptOn(val) {
+				continue
+			}
+			if err := do.scheduleEvents(); err != nil {
+				logutil.BgLogger().Warn("schedule events failed", zap.Error(err))
+			}
+		}
+	}()
+}
+
+// scheduleEvents starts executing the enabled events which are due. The schedule of an event is updated before it
+// starts, and an event is dropped or disabled when it won't be executed anymore.
+func (do *Domain) scheduleEvents() error {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnEvent)
+	se, err := do.sysSessionPool.Get()
+	if err != nil {
+		return err
+	}
+	defer do.sysSessionPool.Put(se)
+	sctx := se.(sessionctx.Context)
+	events, err := LoadEvents(ctx, sctx, "status = 'ENABLED'")
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	for _, event := range events {
+		after := event.LastExecuted
+		if after.IsZero() {
+			after = event.Starts.Add(-time.Second)
+		}
+		next, ok := event.NextExecution(after)
+		due := ok && !next.After(now)
+		if ok && !due {
+			continue
+		}
+		// An event is completed if it won't be executed after now, then it's disabled or dropped.
+		var completed bool
+		if _, more := event.NextExecution(now); !more {
+			completed = true
+		}
+		lastExecuted := "last_executed"
+		if due {
+			lastExecuted = "'" + now.Format(EventTimeFormat) + "'"
+		}
+		var sql string
+		switch {
+		case !completed:
+			sql = "UPDATE mysql.events SET last_executed = " + lastExecuted + " WHERE db = %? AND name = %? AND last_altered = %?"
+		case event.Preserve:
+			sql = "UPDATE mysql.events SET last_executed = " + lastExecuted + ", status = 'DISABLED' WHERE db = %? AND name = %? AND last_altered = %?"
+		default:
+			sql = "DELETE FROM mysql.events WHERE db = %? AND name = %? AND last_altered = %?"
+		}
+		args := []interface{}{event.DB, event.Name, event.LastAltered.Format(EventTimeFormat)}
+		// The condition on last_altered makes sure that the event isn't changed since it's loaded, and the current
+		// session is used to check whether the event is updated.
+		if _, _, err = exec.ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession}, sql, args...); err != nil {
+			return err
+		}
+		if !due || sctx.GetSessionVars().StmtCtx.AffectedRows() == 0 {
+			continue
+		}
+		event := event
+		do.wg.Run(func() { do.execEvent(event) })
+	}
+	return nil
+}
+
+// execEvent executes the event in a session of the system session pool and records the result of the execution.
+func (do *Domain) execEvent(event *EventInfo) {
+	defer util.Recover(metrics.LabelDomain, "execEvent", nil, false)
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnEvent)
+	se, err := do.sysSessionPool.Get()
+	if err != nil {
+		logutil.BgLogger().Warn("get session for event failed", zap.Error(err))
+		return
+	}
+	defer do.sysSessionPool.Put(se)
+	sctx := se.(sessionctx.Context)
+	exec, ok := sctx.(sqlexec.EventExecutor)
+	if !ok {
+		logutil.BgLogger().Warn("events are not supported by the session")
+		return
+	}
+	err = exec.ExecEvent(ctx, event.DB, event.CreateStmt(time.UTC), event.SQLMode, event.TimeZone, event.DBCollation)
+	if err != nil {
+		logutil.BgLogger().Warn("execute event failed", zap.String("schema", event.DB), zap.String("event", event.Name), zap.Error(err))
+		_, _, err = sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+			"UPDATE mysql.events SET executions = executions + 1, failures = failures + 1, last_error = %? WHERE db = %? AND name = %?",
+			err.Error(), event.DB, event.Name)
+	} else {
+		_, _, err = sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+			"UPDATE mysql.events SET executions = executions + 1, last_error = NULL WHERE db = %? AND name = %?",
+			event.DB, event.Name)
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("record event execution failed", zap.String("schema", event.DB), zap.String("event", event.Name), zap.Error(err))
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// The loadable functions created by CREATE FUNCTION ... SONAME are cached like the privileges:
+// - the cache is reloaded from mysql.func every 30s
+// - the cache is reloaded on CREATE FUNCTION and DROP FUNCTION
+// - an etcd notification is sent to other tidb servers, which reload their caches.
+
+// rebuildUDFCache reloads the loadable functions from mysql.func.
+func (do *Domain) rebuildUDFCache() error {
+	sysSessionPool := do.SysSessionPool()
+	res, err := sysSessionPool.Get()
+	if err != nil {
+		return err
+	}
+	defer sysSessionPool.Put(res)
+	sctx := res.(sessionctx.Context)
+	// Only one rebuild can be in progress at a time, this prevents a lost update race
+	// where an earlier read of mysql.func finishes last.
+	do.udfCacheRebuildLock.Lock()
+	defer do.udfCacheRebuildLock.Unlock()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnUDF)
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "SELECT name, dl FROM mysql.func")
+	if err != nil {
+		return err
+	}
+	created := make(map[string]string, len(rows))
+	for _, row := range rows {
+		created[row.GetString(0)] = row.GetString(1)
+	}
+	logutil.BgLogger().Debug("rebuilding loadable function cache")
+	expression.SetCreatedUDFs(created)
+	return nil
+}
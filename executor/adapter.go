@@ -1107,6 +1107,7 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	// `LowSlowQuery` and `SummaryStmt` must be called before recording `PrevStmt`.
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
+	a.recordCardFeedback(succ)
 	a.observeStmtFinishedForTopSQL()
 	if sessVars.StmtCtx.IsTiFlash.Load() {
 		if succ {
@@ -1134,6 +1135,21 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	}
 }
 
+// recordCardFeedback learns the cardinality corrections from the actual row counts of the executed plan.
+func (a *ExecStmt) recordCardFeedback(succ bool) {
+	sessVars := a.Ctx.GetSessionVars()
+	if !succ || !sessVars.EnableCardinalityCorrection || sessVars.InRestrictedSQL || a.Plan == nil {
+		return
+	}
+	statsHandle := domain.GetDomain(a.Ctx).StatsHandle()
+	if statsHandle == nil {
+		return
+	}
+	for _, fb := range plannercore.CollectCardFeedback(a.Plan, sessVars.StmtCtx.RuntimeStatsColl) {
+		statsHandle.CardCorrectionCache().Update(fb)
+	}
+}
+
 // CloseRecordSet will finish the execution of current statement and do some record work
 func (a *ExecStmt) CloseRecordSet(txnStartTS uint64, lastErr error) {
 	a.FinishExecuteStmt(txnStartTS, lastErr, false)
@@ -0,0 +1,185 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
+)
+
+// AdaptiveJoinExec starts as an index join, and switches to a hash join once the index join has read more outer rows
+// than the threshold, which means the outer side has many more rows than estimated and looking up the inner side for
+// each outer row costs more than scanning the whole inner side. Every outer row is joined on its own, so the outer
+// rows read by the index join and the ones left to the hash join are joined separately.
+type AdaptiveJoinExec struct {
+	baseExecutor
+
+	planID    int
+	indexJoin Executor
+	hashJoin  *HashJoinExec
+	// outer is the outer side shared by the two joins.
+	outer *adaptiveJoinOuterExec
+
+	switched bool
+	stats    *adaptiveJoinRuntimeStats
+}
+
+// Open implements the Executor Open interface.
+func (e *AdaptiveJoinExec) Open(ctx context.Context) error {
+	e.outer.reset()
+	e.switched = false
+	if e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl != nil {
+		e.stats = &adaptiveJoinRuntimeStats{threshold: e.outer.threshold}
+		e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl.RegisterStats(e.planID, e.stats)
+	}
+	return e.indexJoin.Open(ctx)
+}
+
+// Next implements the Executor Next interface.
+func (e *AdaptiveJoinExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if !e.switched {
+		if err := Next(ctx, e.indexJoin, req); err != nil {
+			return err
+		}
+		if req.NumRows() > 0 || !e.outer.exceeded {
+			return nil
+		}
+		e.switched = true
+		if e.stats != nil {
+			e.stats.switched = true
+		}
+		if err := e.indexJoin.Close(); err != nil {
+			return err
+		}
+		e.outer.resumed = true
+		if err := e.hashJoin.Open(ctx); err != nil {
+			return err
+		}
+	}
+	return Next(ctx, e.hashJoin, req)
+}
+
+// Close implements the Executor Close interface.
+func (e *AdaptiveJoinExec) Close() error {
+	var firstErr error
+	if e.switched {
+		firstErr = e.hashJoin.Close()
+	} else {
+		firstErr = e.indexJoin.Close()
+	}
+	// The joins don't close the outer side they share.
+	if err := e.outer.children[0].Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// adaptiveJoinOuterExec feeds the outer rows to the index join until the threshold is crossed, then it returns no rows
+// until it's resumed for the hash join. The chunk crossing the threshold is held back, and it is returned to the hash
+// join before the rest of the outer rows.
+type adaptiveJoinOuterExec struct {
+	baseExecutor
+
+	threshold uint64
+	readRows  uint64
+	exceeded  bool
+	resumed   bool
+	held      *chunk.Chunk
+	opened    bool
+}
+
+func (e *adaptiveJoinOuterExec) reset() {
+	e.readRows = 0
+	e.exceeded = false
+	e.resumed = false
+	e.held = nil
+	e.opened = false
+}
+
+// Open implements the Executor Open interface. Both the joins open the outer side, but it is only opened once.
+func (e *adaptiveJoinOuterExec) Open(ctx context.Context) error {
+	if e.opened {
+		return nil
+	}
+	e.opened = true
+	return e.children[0].Open(ctx)
+}
+
+// Next implements the Executor Next interface.
+func (e *adaptiveJoinOuterExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.exceeded && !e.resumed {
+		return nil
+	}
+	if e.held != nil {
+		req.Append(e.held, 0, e.held.NumRows())
+		e.held = nil
+		return nil
+	}
+	if err := Next(ctx, e.children[0], req); err != nil {
+		return err
+	}
+	if e.exceeded {
+		return nil
+	}
+	e.readRows += uint64(req.NumRows())
+	if e.readRows > e.threshold {
+		e.exceeded = true
+		e.held = req.CopyConstruct()
+		req.Reset()
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface. The outer side is closed by the AdaptiveJoinExec after both the joins.
+func (e *adaptiveJoinOuterExec) Close() error {
+	return nil
+}
+
+type adaptiveJoinRuntimeStats struct {
+	threshold uint64
+	switched  bool
+}
+
+// String implements the RuntimeStats interface.
+func (s *adaptiveJoinRuntimeStats) String() string {
+	strategy := "index_join"
+	if s.switched {
+		strategy = "index_join->hash_join"
+	}
+	return fmt.Sprintf("adaptive_join:{threshold:%d, strategy:%s}", s.threshold, strategy)
+}
+
+// Clone implements the RuntimeStats interface.
+func (s *adaptiveJoinRuntimeStats) Clone() execdetails.RuntimeStats {
+	return &adaptiveJoinRuntimeStats{threshold: s.threshold, switched: s.switched}
+}
+
+// Merge implements the RuntimeStats interface.
+func (s *adaptiveJoinRuntimeStats) Merge(other execdetails.RuntimeStats) {
+	tmp, ok := other.(*adaptiveJoinRuntimeStats)
+	if !ok {
+		return
+	}
+	s.switched = s.switched || tmp.switched
+}
+
+// Tp implements the RuntimeStats interface.
+func (s *adaptiveJoinRuntimeStats) Tp() int {
+	return execdetails.TpAdaptiveJoinRuntimeStats
+}
@@ -212,3 +212,31 @@ type MaxMinSlidingWindowAggFunc interface {
 	// SetWindowStart sets the start position of window
 	SetWindowStart(start uint64)
 }
+
+// RowsAccessWindowFunc is the interface of the window functions which access the rows of the partition by their
+// indexes, such as LEAD and LAG. The rows are read on demand from the window executor, which may spill them to the
+// disk, rather than buffered in the partial result.
+type RowsAccessWindowFunc interface {
+	// SetRowGetter sets the function to get the i-th row of the current partition for the partial result.
+	SetRowGetter(pr PartialResult, getRow func(i uint64) (chunk.Row, error))
+}
+
+// partialResultAppender is implemented by the aggregate functions whose partial
+// output is different from their final result.
+type partialResultAppender interface {
+	appendPartialResult2Chunk(pr PartialResult, chk *chunk.Chunk)
+}
+
+// AppendPartialResult2Chunk appends the partial result to the input chunk in
+// the format of the partial output of the aggregate function, i.e. the format
+// sent by the partial aggregation to the final aggregation. The appended data
+// can be consumed by the UpdatePartialResult of the same aggregate function in
+// FinalMode. Most of the aggregate functions output their final results as the
+// partial results, while AVG outputs both its count and sum.
+func AppendPartialResult2Chunk(sctx sessionctx.Context, af AggFunc, pr PartialResult, chk *chunk.Chunk) error {
+	if appender, ok := af.(partialResultAppender); ok {
+		appender.appendPartialResult2Chunk(pr, chk)
+		return nil
+	}
+	return af.AppendFinalResult2Chunk(sctx, pr, chk)
+}
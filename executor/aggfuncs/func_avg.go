@@ -89,6 +89,12 @@ func (e *baseAvgDecimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Par
 	return nil
 }
 
+func (e *baseAvgDecimal) appendPartialResult2Chunk(pr PartialResult, chk *chunk.Chunk) {
+	p := (*partialResult4AvgDecimal)(pr)
+	chk.AppendInt64(e.ordinal, p.count)
+	chk.AppendMyDecimal(e.ordinal+1, &p.sum)
+}
+
 type avgOriginal4Decimal struct {
 	baseAvgDecimal
 }
@@ -320,6 +326,12 @@ func (e *baseAvgFloat64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Par
 	return nil
 }
 
+func (e *baseAvgFloat64) appendPartialResult2Chunk(pr PartialResult, chk *chunk.Chunk) {
+	p := (*partialResult4AvgFloat64)(pr)
+	chk.AppendInt64(e.ordinal, p.count)
+	chk.AppendFloat64(e.ordinal+1, p.sum)
+}
+
 type avgOriginal4Float64HighPrecision struct {
 	baseAvgFloat64
 }
@@ -862,3 +862,8 @@ type approxCountDistinctFinal struct {
 func (e *approxCountDistinctFinal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	return e.baseApproxCountDistinct.AppendFinalResult2Chunk(sctx, pr, chk)
 }
+
+func (e *approxCountDistinctFinal) appendPartialResult2Chunk(pr PartialResult, chk *chunk.Chunk) {
+	p := (*partialResult4ApproxCountDistinct)(pr)
+	chk.AppendBytes(e.ordinal, p.Serialize())
+}
@@ -15,6 +15,7 @@
 package aggfuncs
 
 import (
+	"sort"
 	"unsafe"
 
 	"github.com/pingcap/tidb/expression"
@@ -33,11 +34,17 @@ type baseLeadLag struct {
 
 	defaultExpr expression.Expression
 	offset      uint64
+	ignoreNull  bool
 }
 
 type partialResult4LeadLag struct {
-	rows   []chunk.Row
-	curIdx uint64
+	// getRow gets the i-th row of the partition, which is set by the window executor.
+	getRow  func(i uint64) (chunk.Row, error)
+	numRows uint64
+	curIdx  uint64
+	// nonNullIdxs records the indexes of the rows whose argument is not null,
+	// it's only used when ignoreNull is true.
+	nonNullIdxs []uint64
 }
 
 func (v *baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -46,29 +53,47 @@ func (v *baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
 
 func (v *baseLeadLag) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4LeadLag)(pr)
-	p.rows = p.rows[:0]
+	p.numRows = 0
 	p.curIdx = 0
+	p.nonNullIdxs = p.nonNullIdxs[:0]
 }
 
 func (v *baseLeadLag) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LeadLag)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
-	memDelta += int64(len(rowsInGroup)) * DefRowSize
+	if v.ignoreNull {
+		for i, row := range rowsInGroup {
+			isNull, err := isNullArg(v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if !isNull {
+				p.nonNullIdxs = append(p.nonNullIdxs, p.numRows+uint64(i))
+				memDelta += DefUint64Size
+			}
+		}
+	}
+	p.numRows += uint64(len(rowsInGroup))
 	return memDelta, nil
 }
 
-type lead struct {
-	baseLeadLag
+// SetRowGetter implements the RowsAccessWindowFunc interface.
+func (v *baseLeadLag) SetRowGetter(pr PartialResult, getRow func(i uint64) (chunk.Row, error)) {
+	p := (*partialResult4LeadLag)(pr)
+	p.getRow = getRow
 }
 
-func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4LeadLag)(pr)
-	var err error
-	if p.curIdx+v.offset < uint64(len(p.rows)) {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx+v.offset])
-	} else {
-		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+// appendTarget evaluates the argument on the target row, or the default value on the current row
+// if the target row doesn't exist, and appends it to chunk.
+func (v *baseLeadLag) appendTarget(sctx sessionctx.Context, p *partialResult4LeadLag, target uint64, ok bool, chk *chunk.Chunk) error {
+	expr := v.args[0]
+	if !ok {
+		expr, target = v.defaultExpr, p.curIdx
+	}
+	row, err := p.getRow(target)
+	if err != nil {
+		return err
 	}
+	_, err = v.evaluateRow(sctx, expr, row)
 	if err != nil {
 		return err
 	}
@@ -77,22 +102,46 @@ func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult
 	return nil
 }
 
+// isNullArg reports whether the argument evaluates to null on the row.
+func isNullArg(arg expression.Expression, row chunk.Row) (bool, error) {
+	d, err := arg.Eval(row)
+	if err != nil {
+		return false, err
+	}
+	return d.IsNull(), nil
+}
+
+type lead struct {
+	baseLeadLag
+}
+
+func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4LeadLag)(pr)
+	if !v.ignoreNull || v.offset == 0 {
+		return v.appendTarget(sctx, p, p.curIdx+v.offset, p.curIdx+v.offset < p.numRows, chk)
+	}
+	// The offset-th non-null row after the current row.
+	i := sort.Search(len(p.nonNullIdxs), func(i int) bool { return p.nonNullIdxs[i] > p.curIdx })
+	i += int(v.offset) - 1
+	if i < len(p.nonNullIdxs) {
+		return v.appendTarget(sctx, p, p.nonNullIdxs[i], true, chk)
+	}
+	return v.appendTarget(sctx, p, 0, false, chk)
+}
+
 type lag struct {
 	baseLeadLag
 }
 
 func (v *lag) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
-	var err error
-	if p.curIdx >= v.offset {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx-v.offset])
-	} else {
-		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+	if !v.ignoreNull || v.offset == 0 {
+		return v.appendTarget(sctx, p, p.curIdx-v.offset, p.curIdx >= v.offset, chk)
 	}
-	if err != nil {
-		return err
+	// The offset-th non-null row before the current row.
+	n := uint64(sort.Search(len(p.nonNullIdxs), func(i int) bool { return p.nonNullIdxs[i] >= p.curIdx }))
+	if n >= v.offset {
+		return v.appendTarget(sctx, p, p.nonNullIdxs[n-v.offset], true, chk)
 	}
-	v.appendResult(chk, v.ordinal)
-	p.curIdx++
-	return nil
+	return v.appendTarget(sctx, p, 0, false, chk)
 }
@@ -133,31 +133,32 @@ func TestMemLeadLag(t *testing.T) {
 		RetType: types.NewFieldType(mysql.TypeLong),
 	}
 
+	// The rows are read from the window executor rather than buffered in the partial result.
 	numRows := 3
 	tests := []windowMemTest{
 		// lag(field0, N)
 		buildWindowMemTesterWithArgs(ast.WindowFuncLag, mysql.TypeLonglong,
-			[]expression.Expression{zero}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{zero}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLag, mysql.TypeLonglong,
-			[]expression.Expression{one}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{one}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLag, mysql.TypeLonglong,
-			[]expression.Expression{two}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{two}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLag, mysql.TypeLonglong,
-			[]expression.Expression{three}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{three}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLag, mysql.TypeLonglong,
-			[]expression.Expression{million}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{million}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 
 		// lead(field0, N)
 		buildWindowMemTesterWithArgs(ast.WindowFuncLead, mysql.TypeLonglong,
-			[]expression.Expression{zero}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{zero}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLead, mysql.TypeLonglong,
-			[]expression.Expression{one}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{one}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLead, mysql.TypeLonglong,
-			[]expression.Expression{two}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{two}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLead, mysql.TypeLonglong,
-			[]expression.Expression{three}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{three}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 		buildWindowMemTesterWithArgs(ast.WindowFuncLead, mysql.TypeLonglong,
-			[]expression.Expression{million}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, rowMemDeltaGens),
+			[]expression.Expression{million}, 0, numRows, aggfuncs.DefPartialResult4LeadLagSize, defaultUpdateMemDeltaGens),
 	}
 
 	for _, test := range tests {
@@ -241,7 +241,8 @@ func buildValueEvaluator(tp *types.FieldType) (ve valueEvaluator, memDelta int64
 type firstValue struct {
 	baseAggFunc
 
-	tp *types.FieldType
+	tp         *types.FieldType
+	ignoreNull bool
 }
 
 type partialResult4FirstValue struct {
@@ -265,14 +266,20 @@ func (v *firstValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []
 	if p.gotFirstValue {
 		return 0, nil
 	}
-	if len(rowsInGroup) > 0 {
-		p.gotFirstValue = true
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[0])
-		if err != nil {
-			return 0, err
+	for _, row := range rowsInGroup {
+		if v.ignoreNull {
+			isNull, err := isNullArg(v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
 		}
+		p.gotFirstValue = true
+		return p.evaluator.evaluateRow(sctx, v.args[0], row)
 	}
-	return memDelta, nil
+	return 0, nil
 }
 
 func (v *firstValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
@@ -288,7 +295,8 @@ func (v *firstValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Partial
 type lastValue struct {
 	baseAggFunc
 
-	tp *types.FieldType
+	tp         *types.FieldType
+	ignoreNull bool
 }
 
 type partialResult4LastValue struct {
@@ -298,7 +306,7 @@ type partialResult4LastValue struct {
 
 func (v *lastValue) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	ve, veMemDelta := buildValueEvaluator(v.tp)
-	p := &partialResult4FirstValue{evaluator: ve}
+	p := &partialResult4LastValue{evaluator: ve}
 	return PartialResult(p), DefPartialResult4LastValueSize + veMemDelta
 }
 
@@ -309,14 +317,20 @@ func (v *lastValue) ResetPartialResult(pr PartialResult) {
 
 func (v *lastValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LastValue)(pr)
-	if len(rowsInGroup) > 0 {
-		p.gotLastValue = true
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[len(rowsInGroup)-1])
-		if err != nil {
-			return 0, err
+	for i := len(rowsInGroup) - 1; i >= 0; i-- {
+		if v.ignoreNull {
+			isNull, err := isNullArg(v.args[0], rowsInGroup[i])
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
 		}
+		p.gotLastValue = true
+		return p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[i])
 	}
-	return memDelta, nil
+	return 0, nil
 }
 
 func (v *lastValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
@@ -332,18 +346,23 @@ func (v *lastValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialR
 type nthValue struct {
 	baseAggFunc
 
-	tp  *types.FieldType
-	nth uint64
+	tp         *types.FieldType
+	nth        uint64
+	ignoreNull bool
+	fromLast   bool
 }
 
 type partialResult4NthValue struct {
 	seenRows  uint64
 	evaluator valueEvaluator
+	// lastEvaluators is a ring buffer holding the values of the last nth seen rows,
+	// it's only used when counting from the last row.
+	lastEvaluators []valueEvaluator
 }
 
 func (v *nthValue) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	ve, veMemDelta := buildValueEvaluator(v.tp)
-	p := &partialResult4FirstValue{evaluator: ve}
+	p := &partialResult4NthValue{evaluator: ve}
 	return PartialResult(p), DefPartialResult4NthValueSize + veMemDelta
 }
 
@@ -357,22 +376,69 @@ func (v *nthValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []ch
 		return 0, nil
 	}
 	p := (*partialResult4NthValue)(pr)
-	numRows := uint64(len(rowsInGroup))
-	if v.nth > p.seenRows && v.nth-p.seenRows <= numRows {
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[v.nth-p.seenRows-1])
+	if !v.ignoreNull && !v.fromLast {
+		numRows := uint64(len(rowsInGroup))
+		if v.nth > p.seenRows && v.nth-p.seenRows <= numRows {
+			memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[v.nth-p.seenRows-1])
+			if err != nil {
+				return 0, err
+			}
+		}
+		p.seenRows += numRows
+		return memDelta, nil
+	}
+	for _, row := range rowsInGroup {
+		if v.ignoreNull {
+			isNull, err := isNullArg(v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
+		}
+		var delta int64
+		if v.fromLast {
+			delta, err = v.evaluateLastRow(sctx, row, p)
+		} else if p.seenRows+1 == v.nth {
+			delta, err = p.evaluator.evaluateRow(sctx, v.args[0], row)
+		}
 		if err != nil {
 			return 0, err
 		}
+		memDelta += delta
+		p.seenRows++
+		if !v.fromLast && p.seenRows >= v.nth {
+			break
+		}
 	}
-	p.seenRows += numRows
 	return memDelta, nil
 }
 
+// evaluateLastRow evaluates the row into the ring buffer, the oldest value is overwritten
+// once there are nth values in it.
+func (v *nthValue) evaluateLastRow(sctx sessionctx.Context, row chunk.Row, p *partialResult4NthValue) (memDelta int64, err error) {
+	if p.lastEvaluators == nil {
+		p.lastEvaluators = make([]valueEvaluator, 0, v.nth)
+	}
+	if uint64(len(p.lastEvaluators)) < v.nth {
+		ve, veMemDelta := buildValueEvaluator(v.tp)
+		p.lastEvaluators = append(p.lastEvaluators, ve)
+		memDelta += veMemDelta
+	}
+	delta, err := p.lastEvaluators[p.seenRows%v.nth].evaluateRow(sctx, v.args[0], row)
+	return memDelta + delta, err
+}
+
 func (v *nthValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4NthValue)(pr)
-	if v.nth == 0 || p.seenRows < v.nth {
+	switch {
+	case v.nth == 0 || p.seenRows < v.nth:
 		chk.AppendNull(v.ordinal)
-	} else {
+	case v.fromLast:
+		// The nth row from the last one is the oldest value in the ring buffer.
+		p.lastEvaluators[p.seenRows%v.nth].appendResult(chk, v.ordinal)
+	default:
 		p.evaluator.appendResult(chk, v.ordinal)
 	}
 	return nil
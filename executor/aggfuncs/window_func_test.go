@@ -64,6 +64,9 @@ func testWindowFunc(t *testing.T, p windowTest) {
 	require.NoError(t, err)
 	finalFunc := aggfuncs.BuildWindowFunctions(ctx, desc, 0, p.orderByCols)
 	finalPr, _ := finalFunc.AllocPartialResult()
+	if rowsAccessWindowFunc, ok := finalFunc.(aggfuncs.RowsAccessWindowFunc); ok {
+		rowsAccessWindowFunc.SetRowGetter(finalPr, func(i uint64) (chunk.Row, error) { return srcChk.GetRow(int(i)), nil })
+	}
 	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
 
 	iter := chunk.NewIterator4Chunk(srcChk)
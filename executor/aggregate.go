@@ -60,6 +60,14 @@ type baseHashAggWorker struct {
 
 	memTracker *memory.Tracker
 	BInMap     int // indicate there are 2^BInMap buckets in Golang Map.
+
+	// spillHelper is not nil if the partial results can be spilled to the disk.
+	spillHelper *parallelHashAggSpillHelper
+	// spillWriters are used to spill the partial results of the worker for every final worker.
+	spillWriters []*aggSpillWriter
+	// spillAggFuncs are used to append the partial results to the spilled chunks.
+	spillAggFuncs []aggfuncs.AggFunc
+	spillRound    uint32
 }
 
 func newBaseHashAggWorker(ctx sessionctx.Context, finishCh <-chan struct{}, aggFuncs []aggfuncs.AggFunc,
@@ -97,6 +105,7 @@ type HashAggPartialWorker struct {
 type HashAggFinalWorker struct {
 	baseHashAggWorker
 
+	workerIdx           int
 	rowBuffer           []types.Datum
 	mutableRow          chunk.MutRow
 	partialResultMap    aggPartialResultMapper
@@ -211,6 +220,15 @@ type HashAggExec struct {
 	spillAction *AggSpillDiskAction
 	// isChildDrained indicates whether the all data from child has been taken out.
 	isChildDrained bool
+
+	// spillFieldTypes are the field types of the partial results spilled by the parallel workers. It's nil if the
+	// aggregate functions don't support spilling in the parallel execution.
+	spillFieldTypes []*types.FieldType
+	// finalSpillAggFuncs are used by the final workers to spill their partial results in the format of the partial
+	// output, so that they can be merged with the ones spilled by the partial workers.
+	finalSpillAggFuncs []aggfuncs.AggFunc
+	// spillHelper coordinates the spilling of the parallel workers.
+	spillHelper *parallelHashAggSpillHelper
 }
 
 // HashAggInput indicates the input of hash agg exec.
@@ -284,9 +302,20 @@ func (e *HashAggExec) Close() error {
 		for range e.finalOutputCh {
 		}
 		e.executed = false
+		for i := range e.partialWorkers {
+			e.partialWorkers[i].memTracker.Detach()
+		}
+		for i := range e.finalWorkers {
+			e.finalWorkers[i].memTracker.Detach()
+		}
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
+		if e.spillHelper != nil {
+			// All the workers have exited since finalOutputCh is closed.
+			terror.Log(e.spillHelper.close())
+			e.spillHelper, e.spillAction = nil, nil
+		}
 	}
 	return e.baseExecutor.Close()
 }
@@ -373,10 +402,18 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	e.finalWorkers = make([]HashAggFinalWorker, finalConcurrency)
 	e.initRuntimeStats()
 
+	e.spillHelper = nil
+	if e.spillFieldTypes != nil && sessionVars.TrackAggregateMemoryUsage && config.GetGlobalConfig().OOMUseTmpStorage {
+		e.diskTracker = disk.NewTracker(e.id, -1)
+		e.diskTracker.AttachTo(sessionVars.StmtCtx.DiskTracker)
+		e.spillHelper = newParallelHashAggSpillHelper(e.spillFieldTypes, partialConcurrency, finalConcurrency, e.diskTracker)
+		sessionVars.StmtCtx.MemTracker.FallbackOldAndSetNewActionForSoftLimit(e.ActionSpill())
+	}
+
 	// Init partial workers.
 	for i := 0; i < partialConcurrency; i++ {
 		w := HashAggPartialWorker{
-			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, e.memTracker),
+			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, e.newWorkerMemTracker()),
 			inputCh:           e.partialInputChs[i],
 			outputChs:         e.partialOutputChs,
 			giveBackCh:        e.inputCh,
@@ -386,14 +423,17 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			chk:               newFirstChunk(e.children[0]),
 			groupKey:          make([][]byte, 0, 8),
 		}
+		if e.spillHelper != nil {
+			w.spillHelper, w.spillWriters, w.spillAggFuncs = e.spillHelper, e.spillHelper.partialWriters[i], e.PartialAggFuncs
+		}
 		// There is a bucket in the empty partialResultsMap.
 		failpoint.Inject("ConsumeRandomPanic", nil)
-		e.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice * (1 << w.BInMap))
+		w.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice * (1 << w.BInMap))
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.PartialStats = append(e.stats.PartialStats, w.stats)
 		}
-		e.memTracker.Consume(w.chk.MemoryUsage())
+		w.memTracker.Consume(w.chk.MemoryUsage())
 		e.partialWorkers[i] = w
 		input := &HashAggInput{
 			chk:        newFirstChunk(e.children[0]),
@@ -407,7 +447,8 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	for i := 0; i < finalConcurrency; i++ {
 		groupSet, setSize := set.NewStringSetWithMemoryUsage()
 		w := HashAggFinalWorker{
-			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, e.memTracker),
+			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, e.newWorkerMemTracker()),
+			workerIdx:           i,
 			partialResultMap:    make(aggPartialResultMapper),
 			groupSet:            groupSet,
 			inputCh:             e.partialOutputChs[i],
@@ -417,8 +458,11 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
 			groupKeys:           make([][]byte, 0, 8),
 		}
+		if e.spillHelper != nil {
+			w.spillHelper, w.spillWriters, w.spillAggFuncs = e.spillHelper, e.spillHelper.finalWriters[i], e.finalSpillAggFuncs
+		}
 		// There is a bucket in the empty partialResultsMap.
-		e.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice*(1<<w.BInMap) + setSize)
+		w.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice*(1<<w.BInMap) + setSize)
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.FinalStats = append(e.stats.FinalStats, w.stats)
@@ -430,6 +474,14 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	e.parallelExecInitialized = true
 }
 
+// newWorkerMemTracker creates the memory tracker of a parallel worker, so that the memory of the partial results
+// can be released after they are spilled.
+func (e *HashAggExec) newWorkerMemTracker() *memory.Tracker {
+	tracker := memory.NewTracker(memory.LabelForHashAggWorker, -1)
+	tracker.AttachTo(e.memTracker)
+	return tracker
+}
+
 func (w *HashAggPartialWorker) getChildInput() bool {
 	select {
 	case <-w.finishCh:
@@ -483,6 +535,10 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 			w.globalOutputCh <- &AfFinalResult{err: err}
 			return
 		}
+		if err := w.spillPartialResultsIfNeeded(ctx); err != nil {
+			w.globalOutputCh <- &AfFinalResult{err: err}
+			return
+		}
 		if w.stats != nil {
 			w.stats.ExecTime += int64(time.Since(execStart))
 			w.stats.TaskNum += 1
@@ -529,6 +585,20 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 	return nil
 }
 
+// spillPartialResultsIfNeeded spills all the partial results to the disk if the memory quota is exceeded.
+func (w *HashAggPartialWorker) spillPartialResultsIfNeeded(ctx sessionctx.Context) error {
+	if w.spillHelper == nil || !w.spillHelper.needSpill(&w.spillRound) || len(w.partialResultsMap) == 0 {
+		return nil
+	}
+	if err := w.spillHelper.spill(ctx, w.partialResultsMap, w.spillAggFuncs, w.spillWriters, w.maxChunkSize); err != nil {
+		return err
+	}
+	w.partialResultsMap = make(aggPartialResultMapper)
+	w.BInMap = 0
+	w.memTracker.ReplaceBytesUsed(hack.DefBucketMemoryUsageForMapStrToSlice + w.chk.MemoryUsage() + getGroupKeyMemUsage(w.groupKey))
+	return nil
+}
+
 // shuffleIntermData shuffles the intermediate data of partial workers to corresponded final workers.
 // We only support parallel execution for single-machine, so process of encode and decode can be skipped.
 func (w *HashAggPartialWorker) shuffleIntermData(sc *stmtctx.StatementContext, finalConcurrency int) {
@@ -692,6 +762,9 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 			}
 			w.memTracker.Consume(allMemDelta)
 		}
+		if err := w.spillPartialResultsIfNeeded(sctx); err != nil {
+			return err
+		}
 		if w.stats != nil {
 			w.stats.ExecTime += int64(time.Since(execStart))
 			w.stats.TaskNum += 1
@@ -699,6 +772,63 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 	}
 }
 
+// spillPartialResultsIfNeeded spills all the partial results to the disk if the memory quota is exceeded.
+func (w *HashAggFinalWorker) spillPartialResultsIfNeeded(sctx sessionctx.Context) error {
+	if w.spillHelper == nil || !w.spillHelper.needSpill(&w.spillRound) {
+		return nil
+	}
+	return w.spillPartialResults(sctx)
+}
+
+func (w *HashAggFinalWorker) spillPartialResults(sctx sessionctx.Context) error {
+	if len(w.partialResultMap) == 0 {
+		return nil
+	}
+	if err := w.spillHelper.spill(sctx, w.partialResultMap, w.spillAggFuncs, w.spillWriters, w.maxChunkSize); err != nil {
+		return err
+	}
+	w.resetPartialResults()
+	return nil
+}
+
+// resetPartialResults clears the partial results and releases their memory.
+func (w *HashAggFinalWorker) resetPartialResults() {
+	var setSize int64
+	w.partialResultMap = make(aggPartialResultMapper)
+	w.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	w.BInMap = 0
+	w.memTracker.ReplaceBytesUsed(hack.DefBucketMemoryUsageForMapStrToSlice + setSize + getGroupKeyMemUsage(w.groupKeys))
+}
+
+// mergeSpilledChunk merges the partial results in the spilled chunk, whose last column is the group key.
+func (w *HashAggFinalWorker) mergeSpilledChunk(sctx sessionctx.Context, chk *chunk.Chunk) error {
+	keyIdx := chk.NumCols() - 1
+	memSize := getGroupKeyMemUsage(w.groupKeys)
+	w.groupKeys = w.groupKeys[:0]
+	for i := 0; i < chk.NumRows(); i++ {
+		w.groupKeys = append(w.groupKeys, []byte(chk.GetRow(i).GetString(keyIdx)))
+	}
+	w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - memSize)
+	partialResults := w.getPartialResult(sctx.GetSessionVars().StmtCtx, w.groupKeys, w.partialResultMap)
+	rows := make([]chunk.Row, 1)
+	allMemDelta := int64(0)
+	for i := 0; i < chk.NumRows(); i++ {
+		if groupKey := string(w.groupKeys[i]); !w.groupSet.Exist(groupKey) {
+			allMemDelta += w.groupSet.Insert(groupKey)
+		}
+		rows[0] = chk.GetRow(i)
+		for j, af := range w.aggFuncs {
+			memDelta, err := af.UpdatePartialResult(sctx, rows, partialResults[i][j])
+			if err != nil {
+				return err
+			}
+			allMemDelta += memDelta
+		}
+	}
+	w.memTracker.Consume(allMemDelta)
+	return nil
+}
+
 func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 	waitStart := time.Now()
 	result, finished := w.receiveFinalResultHolder()
@@ -709,6 +839,28 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 		return
 	}
 	execStart := time.Now()
+	if w.spillHelper != nil {
+		atomic.StoreUint32(&w.spillHelper.isMerging, 1)
+		if w.spillHelper.hasSpilledData(w.workerIdx) {
+			result, finished = w.mergeSpilledPartialResults(sctx, result)
+		} else {
+			result, finished = w.appendFinalResults(sctx, result)
+		}
+	} else {
+		result, finished = w.appendFinalResults(sctx, result)
+	}
+	if finished {
+		return
+	}
+	w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.finalResultHolderCh}
+	if w.stats != nil {
+		w.stats.ExecTime += int64(time.Since(execStart))
+	}
+}
+
+// appendFinalResults appends the final results of all the groups to the result chunks, and sends the full ones
+// to the main thread. It returns the last result chunk which is not full.
+func (w *HashAggFinalWorker) appendFinalResults(sctx sessionctx.Context, result *chunk.Chunk) (_ *chunk.Chunk, finished bool) {
 	memSize := getGroupKeyMemUsage(w.groupKeys)
 	w.groupKeys = w.groupKeys[:0]
 	for groupKey := range w.groupSet.StringSet {
@@ -730,14 +882,34 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 			w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.finalResultHolderCh}
 			result, finished = w.receiveFinalResultHolder()
 			if finished {
-				return
+				return nil, true
 			}
 		}
 	}
-	w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.finalResultHolderCh}
-	if w.stats != nil {
-		w.stats.ExecTime += int64(time.Since(execStart))
+	return result, false
+}
+
+// mergeSpilledPartialResults spills the partial results left in memory, then reads back and merges the spilled
+// partial results partition by partition, and appends the final results of every partition to the result chunks.
+func (w *HashAggFinalWorker) mergeSpilledPartialResults(sctx sessionctx.Context, result *chunk.Chunk) (_ *chunk.Chunk, finished bool) {
+	err := w.spillPartialResults(sctx)
+	for i := 0; err == nil && i < aggSpillPartitionNum; i++ {
+		err = w.spillHelper.readPartition(w.workerIdx, i, func(chk *chunk.Chunk) error {
+			return w.mergeSpilledChunk(sctx, chk)
+		})
+		if err != nil {
+			break
+		}
+		if result, finished = w.appendFinalResults(sctx, result); finished {
+			return nil, true
+		}
+		w.resetPartialResults()
+	}
+	if err != nil {
+		w.outputCh <- &AfFinalResult{err: err}
+		return nil, true
 	}
+	return result, false
 }
 
 func (w *HashAggFinalWorker) receiveFinalResultHolder() (*chunk.Chunk, bool) {
@@ -1911,7 +2083,7 @@ func (e *HashAggExec) ActionSpill() *AggSpillDiskAction {
 // maxSpillTimes indicates how many times the data can spill at most.
 const maxSpillTimes = 10
 
-// AggSpillDiskAction implements memory.ActionOnExceed for unparalleled HashAgg.
+// AggSpillDiskAction implements memory.ActionOnExceed for HashAgg.
 // If the memory quota of a query is exceeded, AggSpillDiskAction.Action is
 // triggered.
 type AggSpillDiskAction struct {
@@ -1922,6 +2094,22 @@ type AggSpillDiskAction struct {
 
 // Action set HashAggExec spill mode.
 func (a *AggSpillDiskAction) Action(t *memory.Tracker) {
+	if h := a.e.spillHelper; h != nil {
+		// The parallel workers spill their partial results once they find the round changed. The spilling stops
+		// when the final workers begin to merge the spilled partial results.
+		if atomic.LoadUint32(&h.isMerging) == 0 && a.e.memTracker.BytesConsumed() >= t.GetBytesLimit()/5 {
+			round := atomic.AddUint32(&h.spillRound, 1)
+			logutil.BgLogger().Info("memory exceeds quota, spill the partial results of the parallel aggregate to disk",
+				zap.Uint32("spillRound", round),
+				zap.Int64("consumed", t.BytesConsumed()),
+				zap.Int64("quota", t.GetBytesLimit()))
+			return
+		}
+		if fallback := a.GetFallback(); fallback != nil {
+			fallback.Action(t)
+		}
+		return
+	}
 	// Guarantee that processed data is at least 20% of the threshold, to avoid spilling too frequently.
 	if atomic.LoadUint32(&a.e.inSpillMode) == 0 && a.spillTimes < maxSpillTimes && a.e.memTracker.BytesConsumed() >= t.GetBytesLimit()/5 {
 		a.spillTimes++
@@ -0,0 +1,218 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/twmb/murmur3"
+)
+
+// aggSpillPartitionNum is the number of the partitions that the spilled partial results of a final worker are split
+// into. The final worker merges the partitions one by one, so only about 1/aggSpillPartitionNum of its partial
+// results are kept in memory at the same time.
+const aggSpillPartitionNum = 4
+
+// parallelHashAggSpillHelper coordinates the spilling of the workers of the parallel HashAgg.
+// When the memory quota is exceeded, AggSpillDiskAction increases spillRound. Every partial and final worker checks
+// the round after it processes its input, spills all of its partial results to the disk if the round is changed,
+// and then goes on with an empty map. The spilled partial results are partitioned by the group key in the same way
+// as the intermediate data is shuffled, so every final worker only needs to read back and merge its own partitions.
+type parallelHashAggSpillHelper struct {
+	spillRound uint32
+	// isMerging indicates the final workers have begun to output the results, so spilling no longer helps.
+	isMerging uint32
+
+	// fieldTypes are the field types of the partial output of the aggregate functions, followed by the group key.
+	fieldTypes       []*types.FieldType
+	finalConcurrency int
+	diskTracker      *disk.Tracker
+
+	// partialWriters[i][j] spills the partial results of the i-th partial worker for the j-th final worker.
+	partialWriters [][]*aggSpillWriter
+	// finalWriters[j] spills the partial results of the j-th final worker. Only finalWriters[j][j] is not nil,
+	// so that the partial and final workers can spill in the same way.
+	finalWriters [][]*aggSpillWriter
+}
+
+func newParallelHashAggSpillHelper(fieldTypes []*types.FieldType, partialConcurrency, finalConcurrency int,
+	diskTracker *disk.Tracker) *parallelHashAggSpillHelper {
+	h := &parallelHashAggSpillHelper{
+		fieldTypes:       fieldTypes,
+		finalConcurrency: finalConcurrency,
+		diskTracker:      diskTracker,
+		partialWriters:   make([][]*aggSpillWriter, partialConcurrency),
+		finalWriters:     make([][]*aggSpillWriter, finalConcurrency),
+	}
+	for i := range h.partialWriters {
+		h.partialWriters[i] = make([]*aggSpillWriter, finalConcurrency)
+		for j := range h.partialWriters[i] {
+			h.partialWriters[i][j] = &aggSpillWriter{}
+		}
+	}
+	for j := range h.finalWriters {
+		h.finalWriters[j] = make([]*aggSpillWriter, finalConcurrency)
+		h.finalWriters[j][j] = &aggSpillWriter{}
+	}
+	return h
+}
+
+// needSpill checks whether the spill round has been changed since the last check of the worker.
+func (h *parallelHashAggSpillHelper) needSpill(lastRound *uint32) bool {
+	round := atomic.LoadUint32(&h.spillRound)
+	if round == *lastRound {
+		return false
+	}
+	*lastRound = round
+	return true
+}
+
+// getPartitionIdx returns the index of the final worker and the index of the partition that the group key belongs to.
+// The index of the final worker is the same as the one used by shuffleIntermData.
+func (h *parallelHashAggSpillHelper) getPartitionIdx(groupKey string) (finalWorkerIdx, partitionIdx int) {
+	hash := murmur3.Sum32(hack.Slice(groupKey))
+	return int(hash) % h.finalConcurrency, int(hash/uint32(h.finalConcurrency)) % aggSpillPartitionNum
+}
+
+// spill writes all the partial results in the map to the disk by the writers of a worker.
+func (h *parallelHashAggSpillHelper) spill(sctx sessionctx.Context, mapper aggPartialResultMapper, aggFuncs []aggfuncs.AggFunc,
+	writers []*aggSpillWriter, maxChunkSize int) error {
+	keyIdx := len(h.fieldTypes) - 1
+	for groupKey, prs := range mapper {
+		finalWorkerIdx, partitionIdx := h.getPartitionIdx(groupKey)
+		w := writers[finalWorkerIdx]
+		chk := w.getTmpChunk(h, partitionIdx, maxChunkSize)
+		for i, af := range aggFuncs {
+			if err := aggfuncs.AppendPartialResult2Chunk(sctx, af, prs[i], chk); err != nil {
+				return err
+			}
+		}
+		chk.AppendString(keyIdx, groupKey)
+		if chk.IsFull() {
+			if err := w.flush(partitionIdx); err != nil {
+				return err
+			}
+		}
+	}
+	for _, w := range writers {
+		if w == nil {
+			continue
+		}
+		for i := range w.tmpChks {
+			if err := w.flush(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasSpilledData checks whether any partial results of the final worker have been spilled.
+func (h *parallelHashAggSpillHelper) hasSpilledData(finalWorkerIdx int) bool {
+	for _, writers := range h.partialWriters {
+		if writers[finalWorkerIdx].list != nil {
+			return true
+		}
+	}
+	return h.finalWriters[finalWorkerIdx][finalWorkerIdx].list != nil
+}
+
+// readPartition reads all the spilled chunks of the partition of the final worker, and calls fn for every chunk.
+// It should be called after all the partial workers exit.
+func (h *parallelHashAggSpillHelper) readPartition(finalWorkerIdx, partitionIdx int, fn func(chk *chunk.Chunk) error) error {
+	writers := make([]*aggSpillWriter, 0, len(h.partialWriters)+1)
+	for _, partialWriters := range h.partialWriters {
+		writers = append(writers, partialWriters[finalWorkerIdx])
+	}
+	writers = append(writers, h.finalWriters[finalWorkerIdx][finalWorkerIdx])
+	for _, w := range writers {
+		if w.list == nil {
+			continue
+		}
+		for _, chkIdx := range w.chkIdxes[partitionIdx] {
+			chk, err := w.list.GetChunk(chkIdx)
+			if err != nil {
+				return err
+			}
+			if err = fn(chk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// close releases the disk files of all the writers.
+func (h *parallelHashAggSpillHelper) close() (firstErr error) {
+	closeWriters := func(writers []*aggSpillWriter) {
+		for _, w := range writers {
+			if w == nil || w.list == nil {
+				continue
+			}
+			if err := w.list.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	for _, writers := range h.partialWriters {
+		closeWriters(writers)
+	}
+	for _, writers := range h.finalWriters {
+		closeWriters(writers)
+	}
+	return firstErr
+}
+
+// aggSpillWriter spills the partial results of a worker for a final worker.
+// A writer is only used by its own worker, so it's not thread-safe.
+type aggSpillWriter struct {
+	list    *chunk.ListInDisk
+	tmpChks [aggSpillPartitionNum]*chunk.Chunk
+	// chkIdxes[i] are the indexes of the chunks of the i-th partition in list.
+	chkIdxes [aggSpillPartitionNum][]int
+}
+
+func (w *aggSpillWriter) getTmpChunk(h *parallelHashAggSpillHelper, partitionIdx int, maxChunkSize int) *chunk.Chunk {
+	if w.list == nil {
+		w.list = chunk.NewListInDisk(h.fieldTypes)
+		if h.diskTracker != nil {
+			w.list.GetDiskTracker().AttachTo(h.diskTracker)
+		}
+	}
+	if w.tmpChks[partitionIdx] == nil {
+		w.tmpChks[partitionIdx] = chunk.New(h.fieldTypes, maxChunkSize, maxChunkSize)
+	}
+	return w.tmpChks[partitionIdx]
+}
+
+// flush writes the temporary chunk of the partition to the disk.
+func (w *aggSpillWriter) flush(partitionIdx int) error {
+	chk := w.tmpChks[partitionIdx]
+	if chk == nil || chk.NumRows() == 0 {
+		return nil
+	}
+	if err := w.list.Add(chk); err != nil {
+		return err
+	}
+	w.chkIdxes[partitionIdx] = append(w.chkIdxes[partitionIdx], w.list.NumChunks()-1)
+	chk.Reset()
+	return nil
+}
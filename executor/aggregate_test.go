@@ -1533,6 +1533,63 @@ func TestAggInDisk(t *testing.T) {
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t group by c1;").Check(testkit.Rows())
 }
 
+func TestParallelAggInDisk(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 4;")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4;")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	sql := "insert into t values (0, '0')"
+	for i := 1; i <= 200; i++ {
+		sql += fmt.Sprintf(",(%v, '%v')", i, i)
+	}
+	tk.MustExec(sql)
+	sqls := []string{
+		"select /*+ HASH_AGG() */ t1.a, t2.a, count(t1.b), sum(t2.a), avg(t1.a + t2.a), max(t2.b), min(t1.b), bit_or(t2.a) " +
+			"from t t1 join t t2 group by t1.a, t2.a",
+		"select /*+ HASH_AGG() */ t1.a % 50, count(*), avg(t2.a), length(group_concat(t2.b)), approx_count_distinct(t2.a) " +
+			"from t t1 join t t2 group by t1.a % 50",
+		"select /*+ HASH_AGG() */ t2.a, sum(t1.a), avg(t1.a), length(group_concat(t1.b)), min(t1.a), max(t1.a) " +
+			"from t t1 join t t2 group by t2.a, t1.a % 100",
+	}
+	expected := make([][][]interface{}, 0, len(sqls))
+	for _, sql := range sqls {
+		expected = append(expected, tk.MustQuery(sql).Sort().Rows())
+	}
+
+	tk.MustExec("set tidb_mem_quota_query = 4194304")
+	checkAggSpilled(t, tk.MustQuery("explain analyze "+sqls[0]).Rows())
+	for i, sql := range sqls {
+		tk.MustQuery(sql).Sort().Check(expected[i])
+	}
+
+	// The sketches of approx_count_distinct spilled by the final workers are merged back.
+	tk.MustExec("set tidb_mem_quota_query = default")
+	sql = "select /*+ HASH_AGG() */ t1.a, t2.a % 100, approx_count_distinct(t2.b), count(*) from t t1 join t t2 group by t1.a, t2.a % 100"
+	expectedRows := tk.MustQuery(sql).Sort().Rows()
+	tk.MustExec("set tidb_mem_quota_query = 4194304")
+	checkAggSpilled(t, tk.MustQuery("explain analyze "+sql).Rows())
+	tk.MustQuery(sql).Sort().Check(expectedRows)
+}
+
+func checkAggSpilled(t *testing.T, rows [][]interface{}) {
+	for _, row := range rows {
+		length := len(row)
+		line := fmt.Sprintf("%v", row)
+		disk := fmt.Sprintf("%v", row[length-1])
+		if strings.Contains(line, "HashAgg") {
+			require.False(t, strings.Contains(disk, "N/A"))
+			require.False(t, strings.Contains(disk, "0 Bytes"))
+			require.True(t, strings.Contains(disk, "MB") ||
+				strings.Contains(disk, "KB") ||
+				strings.Contains(disk, "Bytes"))
+		}
+	}
+}
+
 func TestRandomPanicAggConsume(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
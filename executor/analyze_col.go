@@ -50,6 +50,9 @@ type AnalyzeColumnsExec struct {
 	commonHandle  *model.IndexInfo
 	resultHandler *tableResultHandler
 	indexes       []*model.IndexInfo
+	// extStatsColGroups are the column groups of the extended stats, which are pushed down after the
+	// column groups of the indexes to collect their FMSketches.
+	extStatsColGroups [][]int64
 	core.AnalyzeInfo
 
 	samplingBuilderWg *notifyErrorWaitGroupWrapper
@@ -275,7 +278,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 	}
 	if needExtStats {
 		statsHandle := domain.GetDomain(e.ctx).StatsHandle()
-		extStats, err = statsHandle.BuildExtendedStats(e.TableID.GetStatisticsID(), e.colsInfo, collectors)
+		extStats, err = statsHandle.BuildExtendedStats(e.TableID.TableID, e.colsInfo, collectors, nil)
 		if err != nil {
 			return nil, nil, nil, nil, nil, err
 		}
@@ -384,7 +384,15 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	count = rootRowCollector.Base().Count
 	if needExtStats {
 		statsHandle := domain.GetDomain(e.ctx).StatsHandle()
-		extStats, err = statsHandle.BuildExtendedStats(e.TableID.GetStatisticsID(), e.colsInfo, sampleCollectors)
+		rowSamples := &statistics.ExtStatsRowSamples{
+			Samples:      rootRowCollector.Base().Samples,
+			Count:        count,
+			NumTopN:      uint32(e.opts[ast.AnalyzeOptNumTopN]),
+			ColGroups:    e.extStatsColGroups,
+			FMSketches:   rootRowCollector.Base().FMSketches[colLen+len(e.indexes):],
+			KeepFMSketch: e.TableID.IsPartitionTable(),
+		}
+		extStats, err = statsHandle.BuildExtendedStats(e.TableID.TableID, e.colsInfo, sampleCollectors, rowSamples)
 		if err != nil {
 			return 0, nil, nil, nil, nil, err
 		}
@@ -75,6 +75,12 @@ func (e *AnalyzeExec) handleGlobalStats(ctx context.Context, needGlobalStats boo
 				logutil.BgLogger().Error("record historical stats failed", zap.Error(err))
 			}
 		}
+		if globalStats.ExtStats != nil {
+			err = statsHandle.SaveExtendedStatsToStorage(globalStatsID.tableID, globalStats.ExtStats, false)
+			if err != nil {
+				logutil.Logger(ctx).Error("save global-level extended stats to storage failed", zap.Error(err))
+			}
+		}
 	}
 	return nil
 }
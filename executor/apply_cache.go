@@ -16,12 +16,17 @@ package executor
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
 )
 
 // applyCache is used in the apply executor. When we get the same value of the outer row.
@@ -31,6 +36,22 @@ type applyCache struct {
 	cache       *kvcache.SimpleLRUCache // cache.Get/Put are not thread-safe, so it's protected by the lock above
 	memCapacity int64
 	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *disk.Tracker   // track disk usage.
+
+	// inSpillMode is set by the spill action if the memory quota of the query is exceeded. The items in the memory are
+	// evicted to inDisk in the next Set, and the later items are put into inDisk directly.
+	inSpillMode uint32
+	spillAction *applyCacheSpillAction
+	// inDisk stores the evicted items, diskIndex maps their keys to the chunks in inDisk. They are protected by the lock.
+	inDisk           *chunk.ListInDisk
+	inDiskFieldTypes []*types.FieldType
+	diskIndex        map[string]applyCacheDiskItem
+}
+
+// applyCacheDiskItem is the chunks [begin, end) of an item in applyCache.inDisk.
+type applyCacheDiskItem struct {
+	begin int
+	end   int
 }
 
 type applyCacheKey []byte
@@ -51,6 +72,7 @@ func newApplyCache(ctx sessionctx.Context) (*applyCache, error) {
 		cache:       cache,
 		memCapacity: ctx.GetSessionVars().MemQuotaApplyCache,
 		memTracker:  memory.NewTracker(memory.LabelForApplyCache, -1),
+		diskTracker: disk.NewTracker(memory.LabelForApplyCache, -1),
 	}
 	return &c, nil
 }
@@ -71,14 +93,36 @@ func (c *applyCache) put(key applyCacheKey, val kvcache.Value) {
 func (c *applyCache) Get(key applyCacheKey) (*chunk.List, error) {
 	value, hit := c.get(key)
 	if !hit {
-		return nil, nil
+		return c.getFromDisk(key)
 	}
 	typedValue := value.(*chunk.List)
 	return typedValue, nil
 }
 
+// getFromDisk reads the item evicted to the disk, it returns nil if the key is not found.
+func (c *applyCache) getFromDisk(key applyCacheKey) (*chunk.List, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	item, ok := c.diskIndex[string(key)]
+	if !ok {
+		return nil, nil
+	}
+	value := chunk.NewList(c.inDiskFieldTypes, 0, 0)
+	for i := item.begin; i < item.end; i++ {
+		chk, err := c.inDisk.GetChunk(i)
+		if err != nil {
+			return nil, err
+		}
+		value.Add(chk)
+	}
+	return value, nil
+}
+
 // Set inserts an item to the cache. It's thread-safe.
 func (c *applyCache) Set(key applyCacheKey, value *chunk.List) (bool, error) {
+	if atomic.LoadUint32(&c.inSpillMode) == 1 {
+		return c.setInDisk(key, value)
+	}
 	mem := applyCacheKVMem(key, value)
 	if mem > c.memCapacity { // ignore this kv pair if its size is too large
 		return false, nil
@@ -95,7 +139,106 @@ func (c *applyCache) Set(key applyCacheKey, value *chunk.List) (bool, error) {
 	return true, nil
 }
 
+// setInDisk evicts all the items in the memory to the disk, then puts the item into the disk.
+func (c *applyCache) setInDisk(key applyCacheKey, value *chunk.List) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for {
+		evictedKey, evictedValue, evicted := c.cache.RemoveOldest()
+		if !evicted {
+			break
+		}
+		c.memTracker.Consume(-applyCacheKVMem(evictedKey.(applyCacheKey), evictedValue.(*chunk.List)))
+		if err := c.writeToDisk(evictedKey.(applyCacheKey), evictedValue.(*chunk.List)); err != nil {
+			return false, err
+		}
+	}
+	if err := c.writeToDisk(key, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *applyCache) writeToDisk(key applyCacheKey, value *chunk.List) error {
+	if c.inDisk == nil {
+		c.inDiskFieldTypes = value.FieldTypes()
+		c.inDisk = chunk.NewListInDisk(c.inDiskFieldTypes)
+		c.inDisk.GetDiskTracker().AttachTo(c.diskTracker)
+		c.diskIndex = make(map[string]applyCacheDiskItem)
+	}
+	item := applyCacheDiskItem{begin: c.inDisk.NumChunks()}
+	for i := 0; i < value.NumChunks(); i++ {
+		if chk := value.GetChunk(i); chk.NumRows() > 0 {
+			if err := c.inDisk.Add(chk); err != nil {
+				return err
+			}
+		}
+	}
+	item.end = c.inDisk.NumChunks()
+	c.diskIndex[string(key)] = item
+	return nil
+}
+
 // GetMemTracker returns the memory tracker of this apply cache.
 func (c *applyCache) GetMemTracker() *memory.Tracker {
 	return c.memTracker
 }
+
+// GetDiskTracker returns the disk tracker of this apply cache.
+func (c *applyCache) GetDiskTracker() *disk.Tracker {
+	return c.diskTracker
+}
+
+// Close releases the items in the disk.
+func (c *applyCache) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.spillAction != nil {
+		c.spillAction.SetFinished()
+	}
+	if c.inDisk == nil {
+		return nil
+	}
+	err := c.inDisk.Close()
+	c.inDisk = nil
+	c.diskIndex = nil
+	return err
+}
+
+// ActionSpill returns an applyCacheSpillAction for evicting the items to disk.
+func (c *applyCache) ActionSpill() *applyCacheSpillAction {
+	if c.spillAction == nil {
+		c.spillAction = &applyCacheSpillAction{c: c}
+	}
+	return c.spillAction
+}
+
+// applyCacheSpillAction implements memory.ActionOnExceed for applyCache.
+// If the memory quota of a query is exceeded, applyCacheSpillAction.Action is
+// triggered to set the apply cache to spill mode.
+type applyCacheSpillAction struct {
+	memory.BaseOOMAction
+	c *applyCache
+}
+
+// Action sets the apply cache to spill mode, and triggers the fallback action if it's already in spill mode or
+// nothing is cached in the memory.
+func (a *applyCacheSpillAction) Action(t *memory.Tracker) {
+	if a.c.memTracker.BytesConsumed() > 0 && atomic.CompareAndSwapUint32(&a.c.inSpillMode, 0, 1) {
+		logutil.BgLogger().Info("memory exceeds quota, evict the apply cache to disk",
+			zap.Int64("consumed", t.BytesConsumed()),
+			zap.Int64("quota", t.GetBytesLimit()))
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority get the priority of the Action.
+func (*applyCacheSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// SetLogHook sets the hook, it does nothing just to form the memory.ActionOnExceed interface.
+func (*applyCacheSpillAction) SetLogHook(_ func(uint64)) {}
@@ -19,9 +19,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -77,3 +79,62 @@ func TestApplyCache(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, result)
 }
+
+func TestApplyCacheSpill(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TempStoragePath = t.TempDir()
+	})
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().MemQuotaApplyCache = 1000
+	applyCache, err := newApplyCache(ctx)
+	require.NoError(t, err)
+	tracker := memory.NewTracker(-1, 1)
+	tracker.FallbackOldAndSetNewAction(applyCache.ActionSpill())
+	applyCache.GetMemTracker().AttachTo(tracker)
+
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+	newValue := func(i int) *chunk.List {
+		value := chunk.NewList(fields, 2, 2)
+		for j := 0; j < 3; j++ {
+			srcChunk := chunk.NewChunkWithCapacity(fields, 1)
+			srcChunk.AppendInt64(0, int64(i*10+j))
+			value.AppendRow(srcChunk.GetRow(0))
+		}
+		return value
+	}
+	checkValue := func(i int, value *chunk.List) {
+		require.NotNil(t, value)
+		require.Equal(t, 3, value.Len())
+		for j := 0; j < 3; j++ {
+			require.Equal(t, int64(i*10+j), value.GetRow(chunk.RowPtr{ChkIdx: uint32(j / 2), RowIdx: uint32(j % 2)}).GetInt64(0))
+		}
+	}
+
+	// The first item exceeds the memory quota and triggers the spill action.
+	ok, err := applyCache.Set([]byte("0"), newValue(0))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Greater(t, applyCache.GetMemTracker().BytesConsumed(), int64(0))
+
+	// Both the items in the memory and the new ones are put into the disk.
+	for i := 1; i < 3; i++ {
+		ok, err = applyCache.Set([]byte(strconv.Itoa(i)), newValue(i))
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.Equal(t, int64(0), applyCache.GetMemTracker().BytesConsumed())
+	require.Greater(t, applyCache.GetDiskTracker().BytesConsumed(), int64(0))
+	for i := 0; i < 3; i++ {
+		value, err := applyCache.Get([]byte(strconv.Itoa(i)))
+		require.NoError(t, err)
+		checkValue(i, value)
+	}
+	value, err := applyCache.Get([]byte("3"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	require.NoError(t, applyCache.Close())
+	require.Equal(t, int64(0), applyCache.GetDiskTracker().BytesConsumed())
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/parser/ast"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -130,6 +131,10 @@ func (e *SQLBindExec) flushBindings() error {
 
 func (e *SQLBindExec) captureBindings() {
 	domain.GetDomain(e.ctx).BindHandle().CaptureBaselines()
+	optVal, err := e.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBCapturePlanRegression)
+	if err == nil && variable.TiDBOptOn(optVal) {
+		domain.GetDomain(e.ctx).BindHandle().CapturePlanRegressions()
+	}
 }
 
 func (e *SQLBindExec) evolveBindings() error {
@@ -111,6 +111,21 @@ type executorBuilder struct {
 	// can return a correct value even if the session context has already been destroyed
 	forDataReaderBuilder bool
 	dataReaderTS         uint64
+
+	// runtimeFilters are the runtime filters of the hash join being built, they are attached to the probe side
+	// table readers when the children of the hash join are built.
+	runtimeFilters map[*plannercore.RuntimeFilter]*runtimeFilter
+
+	// partitionWiseWorker is the worker of the partition-wise shuffle being built, the partitioned table readers of
+	// the worker only read the partitions dealt out to it.
+	partitionWiseWorker *partitionWiseWorker
+}
+
+// partitionWiseWorker is the idx-th worker of a shuffle with TablePartitionSplitterType, the partitions whose ordinals
+// modulo the concurrency equal to idx are dealt out to it.
+type partitionWiseWorker struct {
+	idx         int
+	concurrency int
 }
 
 // CTEStorages stores resTbl and iterInTbl for CTEExec.
@@ -203,6 +218,10 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildLoadStats(v)
 	case *plannercore.IndexAdvise:
 		return b.buildIndexAdvise(v)
+	case *plannercore.RecommendIndex:
+		return b.buildRecommendIndex(v)
+	case *plannercore.RefreshMaterializedView:
+		return b.buildRefreshMaterializedView(v)
 	case *plannercore.PlanReplayer:
 		return b.buildPlanReplayer(v)
 	case *plannercore.PhysicalLimit:
@@ -250,13 +269,15 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 	case *plannercore.PhysicalMergeJoin:
 		return b.buildMergeJoin(v)
 	case *plannercore.PhysicalIndexJoin:
-		return b.buildIndexLookUpJoin(v)
+		return b.buildAdaptiveJoin(v, b.buildIndexLookUpJoin(v))
 	case *plannercore.PhysicalIndexMergeJoin:
 		return b.buildIndexLookUpMergeJoin(v)
 	case *plannercore.PhysicalIndexHashJoin:
-		return b.buildIndexNestedLoopHashJoin(v)
+		return b.buildAdaptiveJoin(&v.PhysicalIndexJoin, b.buildIndexNestedLoopHashJoin(v))
 	case *plannercore.PhysicalSelection:
 		return b.buildSelection(v)
+	case *plannercore.PhysicalExpand:
+		return b.buildExpand(v)
 	case *plannercore.PhysicalHashAgg:
 		return b.buildHashAgg(v)
 	case *plannercore.PhysicalStreamAgg:
@@ -267,6 +288,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PhysicalJSONTable:
+		return b.buildJSONTable(v)
 	case *plannercore.PhysicalApply:
 		return b.buildApply(v)
 	case *plannercore.PhysicalMaxOneRow:
@@ -518,6 +541,10 @@ func (b *executorBuilder) buildRecoverIndex(v *plannercore.RecoverIndex) Executo
 		b.err = errors.Errorf("index `%v` is not found in table `%v`", v.IndexName, v.Table.Name.O)
 		return nil
 	}
+	if index.Meta().Tp == model.IndexTypeFulltext {
+		b.err = errors.Errorf("index `%v` is a FULLTEXT index, which can't be recovered or cleaned up", v.IndexName)
+		return nil
+	}
 	e := &RecoverIndexExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		columns:      buildIdxColsConcatHandleCols(tblInfo, index.Meta()),
@@ -578,6 +605,10 @@ func (b *executorBuilder) buildCleanupIndex(v *plannercore.CleanupIndex) Executo
 		b.err = errors.Errorf("index `%v` is not found in table `%v`", v.IndexName, v.Table.Name.O)
 		return nil
 	}
+	if index.Meta().Tp == model.IndexTypeFulltext {
+		b.err = errors.Errorf("index `%v` is a FULLTEXT index, which can't be recovered or cleaned up", v.IndexName)
+		return nil
+	}
 	e := &CleanupIndexExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		columns:      buildIdxColsConcatHandleCols(tblInfo, index.Meta()),
@@ -945,6 +976,24 @@ func (b *executorBuilder) buildIndexAdvise(v *plannercore.IndexAdvise) Executor
 	return e
 }
 
+func (b *executorBuilder) buildRecommendIndex(v *plannercore.RecommendIndex) Executor {
+	return &RecommendIndexExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		sql:          v.SQL,
+		maxMinutes:   v.MaxMinutes,
+		maxIndexNum:  v.MaxIndexNum,
+	}
+}
+
+func (b *executorBuilder) buildRefreshMaterializedView(v *plannercore.RefreshMaterializedView) Executor {
+	return &RefreshMaterializedViewExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		dbName:       v.DBName,
+		tblInfo:      v.TableInfo,
+		method:       v.Method,
+	}
+}
+
 func (b *executorBuilder) buildPlanReplayer(v *plannercore.PlanReplayer) Executor {
 	if v.Load {
 		e := &PlanReplayerLoadExec{
@@ -1309,6 +1358,15 @@ func (b *executorBuilder) buildSideEstCount(v *plannercore.PhysicalHashJoin) flo
 }
 
 func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executor {
+	runtimeFilters := make([]*runtimeFilter, 0, len(v.RuntimeFilters))
+	for _, rfPlan := range v.RuntimeFilters {
+		if b.runtimeFilters == nil {
+			b.runtimeFilters = make(map[*plannercore.RuntimeFilter]*runtimeFilter)
+		}
+		rf := newRuntimeFilter(rfPlan)
+		b.runtimeFilters[rfPlan] = rf
+		runtimeFilters = append(runtimeFilters, rf)
+	}
 	leftExec := b.build(v.Children()[0])
 	if b.err != nil {
 		return nil
@@ -1319,8 +1377,23 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 		return nil
 	}
 
+	e := b.newHashJoinExec(v, v.ID(), leftExec, rightExec)
+	if b.err != nil {
+		return nil
+	}
+	for _, rf := range runtimeFilters {
+		rf.buildColIdx = e.buildKeys[rf.plan.KeyIdx].Index
+		rf.buildTp, rf.probeTp = e.buildTypes[rf.plan.KeyIdx], e.probeTypes[rf.plan.KeyIdx]
+		delete(b.runtimeFilters, rf.plan)
+	}
+	e.runtimeFilters = runtimeFilters
+	return e
+}
+
+// newHashJoinExec builds a HashJoinExec with the built children, and records its runtime stats with the plan id.
+func (b *executorBuilder) newHashJoinExec(v *plannercore.PhysicalHashJoin, id int, leftExec, rightExec Executor) *HashJoinExec {
 	e := &HashJoinExec{
-		baseExecutor:    newBaseExecutor(b.ctx, v.Schema(), v.ID(), leftExec, rightExec),
+		baseExecutor:    newBaseExecutor(b.ctx, v.Schema(), id, leftExec, rightExec),
 		concurrency:     v.Concurrency,
 		joinType:        v.JoinType,
 		isOuterJoin:     v.JoinType.IsOuterJoin(),
@@ -1463,6 +1536,7 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 		e.isUnparallelExec = true
 	}
 	partialOrdinal := 0
+	spillable := !e.isUnparallelExec && len(v.GroupByItems) > 0
 	for i, aggDesc := range v.AggFuncs {
 		if e.isUnparallelExec {
 			e.PartialAggFuncs = append(e.PartialAggFuncs, aggfuncs.Build(b.ctx, aggDesc, i))
@@ -1470,19 +1544,30 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 			ordinal := []int{partialOrdinal}
 			partialOrdinal++
 			if aggDesc.Name == ast.AggFuncAvg {
-				ordinal = append(ordinal, partialOrdinal+1)
+				ordinal = append(ordinal, partialOrdinal)
 				partialOrdinal++
 			}
 			partialAggDesc, finalDesc := aggDesc.Split(ordinal)
-			partialAggFunc := aggfuncs.Build(b.ctx, partialAggDesc, i)
+			// The partial results are spilled at the ordinals of the partial output, which are read by the args of finalDesc.
+			partialAggFunc := aggfuncs.Build(b.ctx, partialAggDesc, ordinal[0])
 			finalAggFunc := aggfuncs.Build(b.ctx, finalDesc, i)
+			finalSpillAggFunc := aggfuncs.Build(b.ctx, finalDesc, ordinal[0])
 			e.PartialAggFuncs = append(e.PartialAggFuncs, partialAggFunc)
 			e.FinalAggFuncs = append(e.FinalAggFuncs, finalAggFunc)
+			e.finalSpillAggFuncs = append(e.finalSpillAggFuncs, finalSpillAggFunc)
 			if partialAggDesc.Name == ast.AggFuncGroupConcat {
 				// For group_concat, finalAggFunc and partialAggFunc need shared `truncate` flag to do duplicate.
-				finalAggFunc.(interface{ SetTruncated(t *int32) }).SetTruncated(
-					partialAggFunc.(interface{ GetTruncated() *int32 }).GetTruncated(),
-				)
+				for _, af := range []aggfuncs.AggFunc{finalAggFunc, finalSpillAggFunc} {
+					af.(interface{ SetTruncated(t *int32) }).SetTruncated(
+						partialAggFunc.(interface{ GetTruncated() *int32 }).GetTruncated(),
+					)
+				}
+			}
+			spillable = spillable && isAggFuncSpillable(aggDesc.Name)
+			for _, arg := range finalDesc.Args {
+				if col, ok := arg.(*expression.Column); ok {
+					e.spillFieldTypes = append(e.spillFieldTypes, col.RetType)
+				}
 			}
 		}
 		if e.defaultVal != nil {
@@ -1491,10 +1576,28 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 		}
 	}
 
+	if spillable {
+		// The last column is the group key.
+		e.spillFieldTypes = append(e.spillFieldTypes, types.NewFieldType(mysql.TypeVarString))
+	} else {
+		e.spillFieldTypes = nil
+	}
+
 	executorCounterHashAggExec.Inc()
 	return e
 }
 
+// isAggFuncSpillable checks whether the partial results of the aggregate function can be spilled by the parallel
+// HashAgg, i.e. whether the final aggregate function can merge them back from the partial output.
+func isAggFuncSpillable(name string) bool {
+	switch name {
+	case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin,
+		ast.AggFuncBitOr, ast.AggFuncBitXor, ast.AggFuncBitAnd, ast.AggFuncApproxCountDistinct, ast.AggFuncGroupConcat:
+		return true
+	}
+	return false
+}
+
 func (b *executorBuilder) buildStreamAgg(v *plannercore.PhysicalStreamAgg) Executor {
 	src := b.build(v.Children()[0])
 	if b.err != nil {
@@ -1539,6 +1642,33 @@ func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Execu
 	return e
 }
 
+func (b *executorBuilder) buildExpand(v *plannercore.PhysicalExpand) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	e := &ExpandExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
+		childIdxs:    make([]int, 0, v.Schema().Len()-len(v.GroupingCols)-1),
+		sourceIdxs:   make([]int, 0, len(v.SourceCols)),
+		kept:         make([][]bool, 0, len(v.GroupingSets)),
+	}
+	for _, col := range v.Schema().Columns[:cap(e.childIdxs)] {
+		e.childIdxs = append(e.childIdxs, col.Index)
+	}
+	for _, col := range v.SourceCols {
+		e.sourceIdxs = append(e.sourceIdxs, col.Index)
+	}
+	for _, set := range v.GroupingSets {
+		kept := make([]bool, len(v.SourceCols))
+		for _, offset := range set {
+			kept[offset] = true
+		}
+		e.kept = append(e.kept, kept)
+	}
+	return e
+}
+
 func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -1580,6 +1710,14 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Execu
 	return e
 }
 
+func (b *executorBuilder) buildJSONTable(v *plannercore.PhysicalJSONTable) Executor {
+	return &JSONTableExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		expr:         v.Expr,
+		path:         v.Path,
+	}
+}
+
 // `getSnapshotTS` returns for-update-ts if in insert/update/delete/lock statement otherwise the isolation read ts
 // Please notice that in RC isolation, the above two ts are the same
 func (b *executorBuilder) getSnapshotTS() (uint64, error) {
@@ -1750,6 +1888,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableStatistics),
 			strings.ToLower(infoschema.TableTiDBIndexes),
 			strings.ToLower(infoschema.TableViews),
+			strings.ToLower(infoschema.TableRoutines),
+			strings.ToLower(infoschema.TableEvents),
+			strings.ToLower(infoschema.TableTriggers),
 			strings.ToLower(infoschema.TableTables),
 			strings.ToLower(infoschema.TableReferConst),
 			strings.ToLower(infoschema.TableSequences),
@@ -1782,6 +1923,7 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TablePlacementPolicies),
 			strings.ToLower(infoschema.TableTrxSummary),
 			strings.ToLower(infoschema.TableVariablesInfo),
+			strings.ToLower(infoschema.TableCardinalityCorrections),
 			strings.ToLower(infoschema.ClusterTableTrxSummary):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -1908,6 +2050,7 @@ func (b *executorBuilder) buildSort(v *plannercore.PhysicalSort) Executor {
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
 		ByItems:      v.ByItems,
 		schema:       v.Schema(),
+		concurrency:  b.ctx.GetSessionVars().ExecutorConcurrency,
 	}
 	executorCounterSortExec.Inc()
 	return &sortExec
@@ -2356,6 +2499,30 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(task plannercore.AnalyzeC
 	failpoint.Inject("injectBaseModifyCount", func(val failpoint.Value) {
 		modifyCount = int64(val.(int))
 	})
+	var extStatsColGroups [][]int64
+	if b.ctx.GetSessionVars().EnableExtendedStats {
+		groups, err := statsHandle.CollectColumnGroupsInExtendedStats(task.TableID.TableID)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+	groupLoop:
+		for _, group := range groups {
+			colGroup := &tipb.AnalyzeColumnGroup{
+				ColumnOffsets: make([]int64, 0, len(group)),
+			}
+			for _, colID := range group {
+				offset := slices.IndexFunc(task.ColsInfo, func(col *model.ColumnInfo) bool { return col.ID == colID })
+				// The virtual generated columns cannot be calculated by the storage layer.
+				if offset < 0 || (task.ColsInfo[offset].IsGenerated() && !task.ColsInfo[offset].GeneratedStored) {
+					continue groupLoop
+				}
+				colGroup.ColumnOffsets = append(colGroup.ColumnOffsets, int64(offset))
+			}
+			colGroups = append(colGroups, colGroup)
+			extStatsColGroups = append(extStatsColGroups, group)
+		}
+	}
 	sampleRate := new(float64)
 	if opts[ast.AnalyzeOptNumSamples] == 0 {
 		*sampleRate = math.Float64frombits(opts[ast.AnalyzeOptSampleRate])
@@ -2404,6 +2571,7 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(task plannercore.AnalyzeC
 		colsInfo:                task.ColsInfo,
 		handleCols:              task.HandleCols,
 		indexes:                 availableIdx,
+		extStatsColGroups:       extStatsColGroups,
 		AnalyzeInfo:             task.AnalyzeInfo,
 		schemaForVirtualColEval: schemaForVirtualColEval,
 		baseCount:               count,
@@ -3131,6 +3299,40 @@ func (b *executorBuilder) buildIndexNestedLoopHashJoin(v *plannercore.PhysicalIn
 	return idxHash
 }
 
+// buildAdaptiveJoin wraps the index join in an AdaptiveJoinExec if the plan is adaptive. The index join reads its outer
+// side through an adaptiveJoinOuterExec, and the hash join probes with the outer rows left by the index join.
+func (b *executorBuilder) buildAdaptiveJoin(v *plannercore.PhysicalIndexJoin, indexJoin Executor) Executor {
+	if b.err != nil || v.AdaptiveHashJoin == nil {
+		return indexJoin
+	}
+	outerExec := indexJoin.base().children[0]
+	outer := &adaptiveJoinOuterExec{
+		baseExecutor: newBaseExecutor(b.ctx, outerExec.Schema(), 0, outerExec),
+		threshold:    v.AdaptiveThreshold,
+	}
+	indexJoin.base().children[0] = outer
+	innerExec := b.build(v.AdaptiveHashJoin.Children()[v.InnerChildIdx])
+	if b.err != nil {
+		return nil
+	}
+	var leftExec, rightExec Executor = outer, innerExec
+	if v.InnerChildIdx == 0 {
+		leftExec, rightExec = innerExec, outer
+	}
+	// The hash join takes over the index join, so their runtime stats are recorded together.
+	hashJoin := b.newHashJoinExec(v.AdaptiveHashJoin, v.ID(), leftExec, rightExec)
+	if b.err != nil {
+		return nil
+	}
+	return &AdaptiveJoinExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), 0, indexJoin),
+		planID:       v.ID(),
+		indexJoin:    indexJoin,
+		hashJoin:     hashJoin,
+		outer:        outer,
+	}
+}
+
 // containsLimit tests if the execs contains Limit because we do not know whether `Limit` has consumed all of its' source,
 // so the feedback may not be accurate.
 func containsLimit(execs []*tipb.Executor) bool {
@@ -3272,6 +3474,8 @@ func (b *executorBuilder) buildTableReader(v *plannercore.PhysicalTableReader) E
 
 	if ret.table.Meta().TempTableType != model.TempTableNone {
 		ret.dummy = true
+	} else {
+		b.buildRuntimeFilterProbe(ret, v.TablePlans)
 	}
 
 	ret.ranges = ts.Ranges
@@ -3298,6 +3502,7 @@ func (b *executorBuilder) buildTableReader(v *plannercore.PhysicalTableReader) E
 		b.err = err
 		return nil
 	}
+	partitions = b.partitionsOfWorker(pi, partitions)
 	if v.StoreType == kv.TiFlash {
 		sctx.IsTiFlash.Store(true)
 	}
@@ -3549,6 +3754,12 @@ func (b *executorBuilder) buildIndexReader(v *plannercore.PhysicalIndexReader) E
 		b.err = err
 		return nil
 	}
+	if b.partitionWiseWorker != nil {
+		partitions = b.partitionsOfWorker(pi, partitions)
+		if len(partitions) == 0 {
+			return &TableDualExec{baseExecutor: *ret.base()}
+		}
+	}
 	ret.partitions = partitions
 	return ret
 }
@@ -3724,6 +3935,12 @@ func (b *executorBuilder) buildIndexLookUpReader(v *plannercore.PhysicalIndexLoo
 		b.err = err
 		return nil
 	}
+	if b.partitionWiseWorker != nil {
+		partitions = b.partitionsOfWorker(is.Table.Partition, partitions)
+		if len(partitions) == 0 {
+			return &TableDualExec{baseExecutor: *ret.base()}
+		}
+	}
 	ret.partitionTableMode = true
 	ret.prunedPartitions = partitions
 	return ret
@@ -4457,7 +4674,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 	partialResults := make([]aggfuncs.PartialResult, 0, len(v.WindowFuncDescs))
 	resultColIdx := v.Schema().Len() - len(v.WindowFuncDescs)
 	for _, desc := range v.WindowFuncDescs {
-		aggDesc, err := aggregation.NewAggFuncDescForWindowFunc(b.ctx, desc, false)
+		aggDesc, err := aggregation.NewAggFuncDescForWindowFunc(b.ctx, desc, desc.HasDistinct)
 		if err != nil {
 			b.err = err
 			return nil
@@ -4490,6 +4707,9 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 		} else {
 			exec.start = v.Frame.Start
 			exec.end = v.Frame.End
+			if v.Frame.Type == ast.Groups {
+				exec.groups = newPeerGroups(retTypes(childExec), orderByCols)
+			}
 			if v.Frame.Type == ast.Ranges {
 				cmpResult := int64(-1)
 				if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
@@ -4500,6 +4720,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 				exec.isRangeFrame = true
 			}
 		}
+		setRowGetters(windowFuncs, partialResults, func(i uint64) (chunk.Row, error) { return exec.rows.getRow(i) })
 		return exec
 	}
 	var processor windowProcessor
@@ -4515,6 +4736,14 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			start:          v.Frame.Start,
 			end:            v.Frame.End,
 		}
+	} else if v.Frame.Type == ast.Groups {
+		processor = &groupsFrameWindowProcessor{
+			windowFuncs:    windowFuncs,
+			partialResults: partialResults,
+			start:          v.Frame.Start,
+			end:            v.Frame.End,
+			peerGroups:     newPeerGroups(retTypes(childExec), orderByCols),
+		}
 	} else {
 		cmpResult := int64(-1)
 		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
@@ -4529,11 +4758,13 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			expectedCmpResult: cmpResult,
 		}
 	}
-	return &WindowExec{baseExecutor: base,
+	exec := &WindowExec{baseExecutor: base,
 		processor:      processor,
 		groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
 		numWindowFuncs: len(v.WindowFuncDescs),
 	}
+	setRowGetters(windowFuncs, partialResults, func(i uint64) (chunk.Row, error) { return exec.rows.getRow(i) })
+	return exec
 }
 
 func (b *executorBuilder) buildShuffle(v *plannercore.PhysicalShuffle) *ShuffleExec {
@@ -4542,6 +4773,9 @@ func (b *executorBuilder) buildShuffle(v *plannercore.PhysicalShuffle) *ShuffleE
 		baseExecutor: base,
 		concurrency:  v.Concurrency,
 	}
+	if v.SplitterType == plannercore.TablePartitionSplitterType {
+		return b.buildPartitionWiseShuffle(v, shuffle)
+	}
 
 	// 1. initialize the splitters
 	splitters := make([]partitionSplitter, len(v.ByItemArrays))
@@ -4610,6 +4844,46 @@ func (b *executorBuilder) buildShuffle(v *plannercore.PhysicalShuffle) *ShuffleE
 	return shuffle
 }
 
+// buildPartitionWiseShuffle builds the shuffle whose workers execute the plan on their own partitions, there are no data
+// sources to split, every worker reads the partitions by itself.
+func (b *executorBuilder) buildPartitionWiseShuffle(v *plannercore.PhysicalShuffle, shuffle *ShuffleExec) *ShuffleExec {
+	defer func(worker *partitionWiseWorker) {
+		b.partitionWiseWorker = worker
+	}(b.partitionWiseWorker)
+
+	shuffle.workers = make([]*shuffleWorker, shuffle.concurrency)
+	for i := range shuffle.workers {
+		b.partitionWiseWorker = &partitionWiseWorker{idx: i, concurrency: shuffle.concurrency}
+		w := &shuffleWorker{
+			childExec: b.build(v.Children()[0]),
+		}
+		if b.err != nil {
+			return nil
+		}
+		shuffle.workers[i] = w
+	}
+	return shuffle
+}
+
+// partitionsOfWorker returns the partitions read by the worker of the partition-wise shuffle being built.
+func (b *executorBuilder) partitionsOfWorker(pi *model.PartitionInfo, partitions []table.PhysicalTable) []table.PhysicalTable {
+	if b.partitionWiseWorker == nil {
+		return partitions
+	}
+	ret := make([]table.PhysicalTable, 0, len(partitions))
+	for _, p := range partitions {
+		for ordinal, def := range pi.Definitions {
+			if def.ID == p.GetPhysicalID() {
+				if ordinal%b.partitionWiseWorker.concurrency == b.partitionWiseWorker.idx {
+					ret = append(ret, p)
+				}
+				break
+			}
+		}
+	}
+	return ret
+}
+
 func (b *executorBuilder) buildShuffleReceiverStub(v *plannercore.PhysicalShuffleReceiverStub) *shuffleReceiver {
 	return (*shuffleReceiver)(v.Receiver)
 }
@@ -408,6 +408,10 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "CreateBinding"
 	case *ast.IndexAdviseStmt:
 		return "IndexAdvise"
+	case *ast.RecommendIndexStmt:
+		return "RecommendIndex"
+	case *ast.RefreshMaterializedViewStmt:
+		return "RefreshMaterializedView"
 	case *ast.DropBindingStmt:
 		return "DropBinding"
 	case *ast.TraceStmt:
@@ -152,6 +152,14 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeCreateTable(x)
 	case *ast.CreateViewStmt:
 		err = e.executeCreateView(x)
+	case *ast.CreateMaterializedViewStmt:
+		err = e.executeCreateMaterializedView(ctx, x)
+	case *ast.DropMaterializedViewStmt:
+		err = e.executeDropMaterializedView(x)
+	case *ast.CreateTriggerStmt:
+		err = e.executeCreateTrigger(x)
+	case *ast.DropTriggerStmt:
+		err = e.executeDropTrigger(x)
 	case *ast.DropIndexStmt:
 		err = e.executeDropIndex(x)
 	case *ast.DropDatabaseStmt:
@@ -303,6 +311,12 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	}
 
 	err := domain.GetDomain(e.ctx).DDL().DropSchema(e.ctx, s)
+	if err == nil {
+		err = dropSchemaRoutines(e.ctx, dbName)
+	}
+	if err == nil {
+		err = dropSchemaEvents(e.ctx, dbName)
+	}
 	sessionVars := e.ctx.GetSessionVars()
 	if err == nil && strings.ToLower(sessionVars.CurrentDB) == dbName.L {
 		sessionVars.CurrentDB = ""
@@ -326,6 +340,35 @@ func (e *DDLExec) executeDropView(s *ast.DropTableStmt) error {
 	return domain.GetDomain(e.ctx).DDL().DropView(e.ctx, s)
 }
 
+func (e *DDLExec) executeCreateMaterializedView(ctx context.Context, s *ast.CreateMaterializedViewStmt) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	warnCnt := sc.WarningCount()
+	if err := domain.GetDomain(e.ctx).DDL().CreateMaterializedView(e.ctx, s); err != nil {
+		return err
+	}
+	// The view exists if the note is appended.
+	if sc.WarningCount() > warnCnt {
+		return nil
+	}
+	tbl, err := domain.GetDomain(e.ctx).InfoSchema().TableByName(s.ViewName.Schema, s.ViewName.Name)
+	if err != nil {
+		return err
+	}
+	return e.refreshMaterializedView(ctx, s.ViewName.Schema, tbl.Meta(), ast.RefreshMethodComplete)
+}
+
+func (e *DDLExec) executeDropMaterializedView(s *ast.DropMaterializedViewStmt) error {
+	return domain.GetDomain(e.ctx).DDL().DropMaterializedView(e.ctx, s)
+}
+
+func (e *DDLExec) executeCreateTrigger(s *ast.CreateTriggerStmt) error {
+	return domain.GetDomain(e.ctx).DDL().CreateTrigger(e.ctx, s)
+}
+
+func (e *DDLExec) executeDropTrigger(s *ast.DropTriggerStmt) error {
+	return domain.GetDomain(e.ctx).DDL().DropTrigger(e.ctx, s)
+}
+
 func (e *DDLExec) executeDropSequence(s *ast.DropSequenceStmt) error {
 	return domain.GetDomain(e.ctx).DDL().DropSequence(e.ctx, s)
 }
@@ -0,0 +1,329 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// maxEventIntervalValue is the max interval of a recurring event, in seconds or in months.
+const maxEventIntervalValue = 1000000000
+
+// evalEventTime evaluates the time of the schedule clause in the time zone of the session, and returns it in UTC.
+func (e *SimpleExec) evalEventTime(clause string, expr ast.ExprNode) (time.Time, error) {
+	d, err := expression.EvalAstExpr(e.ctx, expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !d.IsNull() {
+		sessVars := e.ctx.GetSessionVars()
+		t, err := d.ConvertTo(sessVars.StmtCtx, types.NewFieldType(mysql.TypeDatetime))
+		if err == nil && !t.IsNull() && !t.GetMysqlTime().IsZero() {
+			if goTime, err := t.GetMysqlTime().GoTime(sessVars.Location()); err == nil {
+				return goTime.UTC().Truncate(time.Second), nil
+			}
+		}
+	}
+	str, err := d.ToString()
+	if err != nil || d.IsNull() {
+		str = "NULL"
+	}
+	return time.Time{}, types.ErrWrongValue.GenWithStackByArgs(clause, str)
+}
+
+// evalEventInterval evaluates the interval of a recurring event. The interval must be positive and not too big.
+func (e *SimpleExec) evalEventInterval(expr ast.ExprNode, unit ast.TimeUnitType) (string, error) {
+	field := unit.String()
+	if strings.HasSuffix(field, "MICROSECOND") {
+		return "", plannercore.ErrNotSupportedYet.GenWithStackByArgs("MICROSECOND")
+	}
+	d, err := expression.EvalAstExpr(e.ctx, expr)
+	if err != nil {
+		return "", err
+	}
+	if d.IsNull() {
+		return "", ErrEventIntervalNotPositiveOrTooBig
+	}
+	value, err := d.ToString()
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	years, months, days, nanos, err := domain.ParseEventInterval(value, field)
+	if err != nil || years < 0 || months < 0 || days < 0 || nanos < 0 || years+months+days+nanos == 0 {
+		return "", ErrEventIntervalNotPositiveOrTooBig
+	}
+	if years*12+months > maxEventIntervalValue || days*24*3600+nanos/int64(time.Second) > maxEventIntervalValue {
+		return "", ErrEventIntervalNotPositiveOrTooBig
+	}
+	return value, nil
+}
+
+// buildEventSchedule sets the schedule of the event. STARTS is the current time if it's not specified.
+func (e *SimpleExec) buildEventSchedule(event *domain.EventInfo, schedule *ast.EventSchedule, now time.Time) (err error) {
+	event.ExecuteAt, event.IntervalValue, event.IntervalField, event.Starts, event.Ends = time.Time{}, "", "", time.Time{}, time.Time{}
+	if schedule.At != nil {
+		event.ExecuteAt, err = e.evalEventTime("AT", schedule.At)
+		return err
+	}
+	if event.IntervalValue, err = e.evalEventInterval(schedule.Every, schedule.Unit); err != nil {
+		return err
+	}
+	event.IntervalField = schedule.Unit.String()
+	event.Starts = now
+	if schedule.Starts != nil {
+		if event.Starts, err = e.evalEventTime("STARTS", schedule.Starts); err != nil {
+			return err
+		}
+	}
+	if schedule.Ends != nil {
+		if event.Ends, err = e.evalEventTime("ENDS", schedule.Ends); err != nil {
+			return err
+		}
+		if event.Ends.Before(event.Starts) {
+			return ErrEventEndsBeforeStarts
+		}
+	}
+	return nil
+}
+
+// isEventExpired returns whether the event won't be executed after now.
+func isEventExpired(event *domain.EventInfo, now time.Time) bool {
+	if event.IsRecurring() {
+		return !event.Ends.IsZero() && event.Ends.Before(now)
+	}
+	return event.ExecuteAt.Before(now)
+}
+
+// setEventEnv sets the SQL mode, the time zone and the character sets of the event from the current session.
+func (e *SimpleExec) setEventEnv(event *domain.EventInfo) (err error) {
+	sessVars := e.ctx.GetSessionVars()
+	for name, value := range map[string]*string{
+		variable.SQLModeVar:          &event.SQLMode,
+		variable.TimeZone:            &event.TimeZone,
+		variable.CharacterSetClient:  &event.CharsetClient,
+		variable.CollationConnection: &event.CollationConnection,
+	} {
+		if *value, err = variable.GetSessionOrGlobalSystemVar(sessVars, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eventStatus(status ast.EventStatus) string {
+	if status == ast.EventStatusUnspecified {
+		return ast.EventEnabled.String()
+	}
+	return status.String()
+}
+
+func eventTimeArg(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(domain.EventTimeFormat)
+}
+
+// eventArgs returns the values of the columns of the event saved in mysql.events, except the execution status.
+func eventArgs(event *domain.EventInfo) []interface{} {
+	onCompletion := "DROP"
+	if event.Preserve {
+		onCompletion = "PRESERVE"
+	}
+	var intervalValue, intervalField interface{}
+	if event.IsRecurring() {
+		intervalValue, intervalField = event.IntervalValue, event.IntervalField
+	}
+	return []interface{}{
+		event.DB, event.Name, event.Body, event.Definer, eventTimeArg(event.ExecuteAt), intervalValue, intervalField,
+		eventTimeArg(event.Starts), eventTimeArg(event.Ends), event.Status, onCompletion, event.SQLMode, event.Comment,
+		event.Originator, event.TimeZone, event.CharsetClient, event.CollationConnection, event.DBCollation,
+		eventTimeArg(event.LastAltered),
+	}
+}
+
+func (e *SimpleExec) executeCreateEvent(ctx context.Context, s *ast.CreateEventStmt) error {
+	dbInfo, ok := e.ctx.GetInfoSchema().(infoschema.InfoSchema).SchemaByName(s.Name.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.Name.Schema.O)
+	}
+	sessVars := e.ctx.GetSessionVars()
+	now := time.Now().UTC().Truncate(time.Second)
+	event := &domain.EventInfo{
+		DB:          s.Name.Schema.L,
+		Name:        s.Name.Name.O,
+		Body:        s.Body.Text(),
+		Definer:     s.Definer.String(),
+		Status:      eventStatus(s.Status),
+		Preserve:    s.Completion == ast.EventCompletionPreserve,
+		Comment:     s.Comment,
+		Originator:  domain.GetDomain(e.ctx).ServerID(),
+		DBCollation: dbInfo.Collate,
+		LastAltered: now,
+	}
+	if err := e.buildEventSchedule(event, s.Schedule, now); err != nil {
+		return err
+	}
+	if err := e.setEventEnv(event); err != nil {
+		return err
+	}
+
+	existing, err := domain.LoadEvent(ctx, e.ctx, event.DB, event.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		err = ErrEventAlreadyExists.GenWithStackByArgs(event.Name)
+		if s.IfNotExists {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	if isEventExpired(event, now) {
+		if !event.Preserve {
+			// The event would be dropped immediately after creation.
+			sessVars.StmtCtx.AppendNote(ErrEventCannotCreateInThePast)
+			return nil
+		}
+		event.Status = ast.EventDisabled.String()
+		sessVars.StmtCtx.AppendWarning(ErrEventExecTimeInThePast)
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnEvent)
+	_, _, err = e.ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"INSERT INTO mysql.events (db, name, body, definer, execute_at, interval_value, interval_field, starts, ends, "+
+			"status, on_completion, sql_mode, comment, originator, time_zone, character_set_client, collation_connection, "+
+			"db_collation, last_altered, created) VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)",
+		append(eventArgs(event), eventTimeArg(now))...)
+	if terror.ErrorEqual(err, kv.ErrKeyExists) {
+		// The event is created concurrently.
+		return ErrEventAlreadyExists.GenWithStackByArgs(event.Name)
+	}
+	return err
+}
+
+func (e *SimpleExec) executeAlterEvent(ctx context.Context, s *ast.AlterEventStmt) error {
+	event, err := domain.LoadEvent(ctx, e.ctx, s.Name.Schema.L, s.Name.Name.O)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return ErrEventDoesNotExist.GenWithStackByArgs(s.Name.Name.O)
+	}
+	sessVars := e.ctx.GetSessionVars()
+	now := time.Now().UTC().Truncate(time.Second)
+	oldDB, oldName := event.DB, event.Name
+	if s.NewName != nil {
+		if s.NewName.Schema.L == event.DB && strings.EqualFold(s.NewName.Name.O, event.Name) {
+			return ErrEventSameName
+		}
+		dbInfo, ok := e.ctx.GetInfoSchema().(infoschema.InfoSchema).SchemaByName(s.NewName.Schema)
+		if !ok {
+			return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.NewName.Schema.O)
+		}
+		event.DB, event.Name, event.DBCollation = s.NewName.Schema.L, s.NewName.Name.O, dbInfo.Collate
+	}
+	if s.Schedule != nil {
+		if err = e.buildEventSchedule(event, s.Schedule, now); err != nil {
+			return err
+		}
+	}
+	if s.Completion != ast.EventCompletionUnspecified {
+		event.Preserve = s.Completion == ast.EventCompletionPreserve
+	}
+	if s.Status != ast.EventStatusUnspecified {
+		event.Status = s.Status.String()
+	}
+	if s.Comment != nil {
+		event.Comment = *s.Comment
+	}
+	if s.Body != nil {
+		event.Body = s.Body.Text()
+	}
+	event.Definer = s.Definer.String()
+	event.LastAltered = now
+	if err = e.setEventEnv(event); err != nil {
+		return err
+	}
+	if s.Schedule != nil && isEventExpired(event, now) {
+		if !event.Preserve {
+			return ErrEventCannotAlterInThePast
+		}
+		event.Status = ast.EventDisabled.String()
+		sessVars.StmtCtx.AppendWarning(ErrEventExecTimeInThePast)
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnEvent)
+	_, _, err = e.ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"UPDATE mysql.events SET db = %?, name = %?, body = %?, definer = %?, execute_at = %?, interval_value = %?, "+
+			"interval_field = %?, starts = %?, ends = %?, status = %?, on_completion = %?, sql_mode = %?, comment = %?, "+
+			"originator = %?, time_zone = %?, character_set_client = %?, collation_connection = %?, db_collation = %?, "+
+			"last_altered = %? WHERE db = %? AND name = %?",
+		append(eventArgs(event), oldDB, oldName)...)
+	if terror.ErrorEqual(err, kv.ErrKeyExists) {
+		return ErrEventAlreadyExists.GenWithStackByArgs(event.Name)
+	}
+	return err
+}
+
+func (e *SimpleExec) executeDropEvent(ctx context.Context, s *ast.DropEventStmt) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnEvent)
+	se, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(ctx, se)
+	_, _, err = se.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession},
+		"DELETE FROM mysql.events WHERE db = %? AND name = %?", s.Name.Schema.L, s.Name.Name.O)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if se.GetSessionVars().StmtCtx.AffectedRows() == 0 {
+		err = ErrEventDoesNotExist.GenWithStackByArgs(s.Name.Name.O)
+		if s.IfExists {
+			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// dropSchemaEvents removes the events of the dropped schema.
+func dropSchemaEvents(sctx sessionctx.Context, dbName model.CIStr) error {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnEvent)
+	_, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"DELETE FROM mysql.events WHERE db = %?", dbName.L)
+	return errors.Trace(err)
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAlterDropEvent(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("set time_zone = '+08:00'")
+	tk.MustExec("create event e1 on schedule every 1 hour starts '2030-01-01 08:00:00' ends '2030-02-01 08:00:00' comment 'hourly' do insert into t values (1)")
+	tk.MustExec("create event e2 on schedule at '2030-01-01 00:00:00' on completion preserve disable do begin delete from t; end")
+	tk.MustGetErrCode("create event e1 on schedule at '2030-01-01 00:00:00' do select 1", errno.ErrEventAlreadyExists)
+	tk.MustExec("create event if not exists e1 on schedule at '2030-01-01 00:00:00' do select 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1537 Event 'e1' already exists"))
+	tk.MustGetErrCode("create event nodb.e on schedule at '2030-01-01 00:00:00' do select 1", errno.ErrBadDB)
+
+	tk.MustQuery("show create event e1").CheckAt([]int{0, 2, 3}, testkit.RowsWithSep("|",
+		"e1|+08:00|CREATE DEFINER=`root`@`%` EVENT `e1` ON SCHEDULE EVERY 1 HOUR STARTS '2030-01-01 08:00:00' ENDS '2030-02-01 08:00:00' "+
+			"ON COMPLETION NOT PRESERVE ENABLE COMMENT 'hourly' DO insert into t values (1)"))
+	tk.MustQuery("show events").CheckAt([]int{0, 1, 3, 4, 5, 6, 7, 8, 10}, testkit.RowsWithSep("|",
+		"test|e1|root@%|RECURRING|<nil>|1|HOUR|2030-01-01 08:00:00|ENABLED",
+		"test|e2|root@%|ONE TIME|2030-01-01 00:00:00|<nil>|<nil>|<nil>|DISABLED"))
+	tk.MustQuery("show events like 'e2'").CheckAt([]int{1}, testkit.Rows("e2"))
+	// The times are saved in UTC, and shown in the time zone of the session.
+	tk.MustQuery("select starts from mysql.events where name = 'e1'").Check(testkit.Rows("2030-01-01 00:00:00"))
+	tk.MustExec("set time_zone = '+00:00'")
+	tk.MustQuery("select event_name, event_type, execute_at, starts, status, on_completion, event_comment, tidb_execution_count, tidb_last_error " +
+		"from information_schema.events where event_schema = 'test' order by event_name").Check(testkit.RowsWithSep("|",
+		"e1|RECURRING|<nil>|2030-01-01 00:00:00|ENABLED|NOT PRESERVE|hourly|0|<nil>",
+		"e2|ONE TIME|2029-12-31 16:00:00|<nil>|DISABLED|PRESERVE||0|<nil>"))
+
+	tk.MustExec("alter event e1 on schedule every '1:30' hour_minute starts '2030-01-01 00:00:00' disable comment 'altered'")
+	tk.MustQuery("show create event e1").CheckAt([]int{3}, testkit.RowsWithSep("|",
+		"CREATE DEFINER=`root`@`%` EVENT `e1` ON SCHEDULE EVERY '1:30' HOUR_MINUTE STARTS '2030-01-01 00:00:00' "+
+			"ON COMPLETION NOT PRESERVE DISABLE COMMENT 'altered' DO insert into t values (1)"))
+	tk.MustExec("alter event e1 rename to e3 do delete from t")
+	tk.MustQuery("select name, body from mysql.events order by name").Check(testkit.RowsWithSep("|", "e2|begin delete from t; end", "e3|delete from t"))
+	tk.MustGetErrCode("alter event e3 rename to e3", errno.ErrEventSameName)
+	tk.MustGetErrCode("alter event e3 rename to e2", errno.ErrEventAlreadyExists)
+	tk.MustGetErrCode("alter event e1 enable", errno.ErrEventDoesNotExist)
+
+	tk.MustExec("drop event e3")
+	tk.MustGetErrCode("drop event e3", errno.ErrEventDoesNotExist)
+	tk.MustExec("drop event if exists e3")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1539 Unknown event 'e3'"))
+
+	// The events are dropped with the database.
+	tk.MustExec("create database event_db")
+	tk.MustExec("create event event_db.e on schedule every 1 day do select 1")
+	tk.MustQuery("select count(*) from mysql.events where db = 'event_db'").Check(testkit.Rows("1"))
+	tk.MustExec("drop database event_db")
+	tk.MustQuery("select count(*) from mysql.events where db = 'event_db'").Check(testkit.Rows("0"))
+}
+
+func TestEventSchedule(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustGetErrCode("create event e on schedule every 0 second do select 1", errno.ErrEventIntervalNotPositiveOrTooBig)
+	tk.MustGetErrCode("create event e on schedule every -1 day do select 1", errno.ErrEventIntervalNotPositiveOrTooBig)
+	tk.MustGetErrCode("create event e on schedule every 1000000001 second do select 1", errno.ErrEventIntervalNotPositiveOrTooBig)
+	tk.MustGetErrCode("create event e on schedule every 1 microsecond do select 1", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("create event e on schedule every 1 day starts '2030-01-02' ends '2030-01-01' do select 1", errno.ErrEventEndsBeforeStarts)
+	tk.MustGetErrCode("create event e on schedule at 'abc' do select 1", errno.ErrTruncatedWrongValue)
+
+	// An event in the past is dropped immediately, or disabled if it's preserved.
+	tk.MustExec("create event e on schedule at '2000-01-01 00:00:00' do select 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1588 Event execution time is in the past and ON COMPLETION NOT PRESERVE is set. The event was dropped immediately after creation."))
+	tk.MustQuery("select count(*) from mysql.events").Check(testkit.Rows("0"))
+	tk.MustExec("create event e on schedule at '2000-01-01 00:00:00' on completion preserve do select 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1544 Event execution time is in the past. Event has been disabled"))
+	tk.MustQuery("select status from mysql.events where name = 'e'").Check(testkit.Rows("DISABLED"))
+	tk.MustGetErrCode("alter event e on schedule at '2000-01-01 00:00:00' on completion not preserve", errno.ErrEventCannotAlterInThePast)
+	tk.MustExec("alter event e on schedule every 1 day starts current_timestamp enable")
+	tk.MustQuery("select status, execute_at, interval_value, interval_field from mysql.events where name = 'e'").Check(testkit.Rows("ENABLED <nil> 1 DAY"))
+
+	// The body of an event can't create or alter events.
+	tk.MustGetErrCode("create event e2 on schedule every 1 day do create event e3 on schedule every 1 day do select 1", errno.ErrEventRecursionForbidden)
+	tk.MustGetErrCode("create event e2 on schedule every 1 day do alter event e do select 2", errno.ErrEventRecursionForbidden)
+	tk.MustExec("create event e2 on schedule every 1 day do alter event e disable")
+}
+
+func TestEventPrivileges(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create event e on schedule every 1 day do select 1")
+	tk.MustExec("create user 'u1'@'%', 'u2'@'%'")
+	tk.MustExec("grant event on test.* to 'u1'@'%'")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("use test")
+	tk1.MustExec("create event e1 on schedule every 1 day do select 1")
+	tk1.MustGetErrCode("create definer = 'root'@'%' event e2 on schedule every 1 day do select 1", errno.ErrSpecificAccessDenied)
+	tk1.MustQuery("show events").CheckAt([]int{1, 3}, testkit.Rows("e root@%", "e1 u1@%"))
+	tk1.MustExec("alter event e disable")
+	tk.MustQuery("select definer from mysql.events where name = 'e'").Check(testkit.Rows("u1@%"))
+
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil))
+	tk2.MustGetErrCode("create event test.e2 on schedule every 1 day do select 1", errno.ErrDBaccessDenied)
+	tk2.MustGetErrCode("drop event test.e1", errno.ErrDBaccessDenied)
+	tk2.MustQuery("show events from test").Check(testkit.Rows())
+	tk2.MustQuery("select count(*) from information_schema.events").Check(testkit.Rows("0"))
+	require.True(t, terror.ErrorEqual(tk2.QueryToErr("show create event test.e1"), executor.ErrEventDoesNotExist))
+}
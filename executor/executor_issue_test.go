@@ -410,6 +410,12 @@ func TestIssue30971(t *testing.T) {
 }
 
 func TestIndexJoin31494(t *testing.T) {
+	// The query is expected to be canceled by the memory quota, so the index join is not allowed to spill its inner
+	// results to the disk, which would keep the memory usage under the quota.
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = false
+	})
 	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
 	defer clean()
 	tk := testkit.NewTestKit(t, store)
@@ -0,0 +1,98 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// ExpandExec duplicates every row of its child for each grouping set. The output row consists of the child row,
+// the grouping columns, which are NULL if the column is not in the grouping set, and the offset of the grouping set.
+type ExpandExec struct {
+	baseExecutor
+
+	// childIdxs are the offsets of the output child columns in the child row.
+	childIdxs []int
+	// sourceIdxs are the offsets of the grouping columns in the child row.
+	sourceIdxs []int
+	// kept marks whether the i-th grouping column is kept in the grouping set.
+	kept [][]bool
+
+	childResult *chunk.Chunk
+	rowIdx      int
+	setIdx      int
+	memTracker  *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *ExpandExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.childResult = newFirstChunk(e.children[0])
+	e.memTracker.Consume(e.childResult.MemoryUsage())
+	e.rowIdx, e.setIdx = 0, 0
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *ExpandExec) Close() error {
+	if e.childResult != nil {
+		e.memTracker.Consume(-e.childResult.MemoryUsage())
+		e.childResult = nil
+	}
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *ExpandExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	childCols := len(e.childIdxs)
+	gidIdx := childCols + len(e.sourceIdxs)
+	for !req.IsFull() {
+		if e.rowIdx >= e.childResult.NumRows() {
+			mSize := e.childResult.MemoryUsage()
+			err := Next(ctx, e.children[0], e.childResult)
+			e.memTracker.Consume(e.childResult.MemoryUsage() - mSize)
+			if err != nil {
+				return err
+			}
+			if e.childResult.NumRows() == 0 {
+				return nil
+			}
+			e.rowIdx, e.setIdx = 0, 0
+		}
+		row := e.childResult.GetRow(e.rowIdx)
+		req.AppendPartialRowByColIdxs(0, row, e.childIdxs)
+		for i := range e.sourceIdxs {
+			if e.kept[e.setIdx][i] {
+				req.AppendPartialRowByColIdxs(childCols+i, row, e.sourceIdxs[i:i+1])
+			} else {
+				req.AppendNull(childCols + i)
+			}
+		}
+		req.AppendInt64(gidIdx, int64(e.setIdx))
+		e.setIdx++
+		if e.setIdx == len(e.kept) {
+			e.rowIdx, e.setIdx = e.rowIdx+1, 0
+		}
+	}
+	return nil
+}
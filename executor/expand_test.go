@@ -0,0 +1,55 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+)
+
+func TestGroupingSets(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int not null, b varchar(10), c int)")
+	tk.MustExec("insert into t values (1, 'x', 10), (1, 'y', 20), (2, 'x', 30), (2, null, 40)")
+
+	tk.MustQuery("select a, b, sum(c) from t group by a, b with rollup order by grouping(a), a, grouping(b), b").
+		Check(testkit.Rows("1 x 10", "1 y 20", "1 <nil> 30", "2 <nil> 40", "2 x 30", "2 <nil> 70", "<nil> <nil> 100"))
+	tk.MustQuery("select a, b, sum(c), grouping(a), grouping(b), grouping(a, b) from t group by cube(a, b) order by grouping(a, b), a, b").
+		Check(testkit.Rows(
+			"1 x 10 0 0 0", "1 y 20 0 0 0", "2 <nil> 40 0 0 0", "2 x 30 0 0 0",
+			"1 <nil> 30 0 1 1", "2 <nil> 70 0 1 1",
+			"<nil> <nil> 40 1 0 2", "<nil> x 40 1 0 2", "<nil> y 20 1 0 2",
+			"<nil> <nil> 100 1 1 3"))
+	tk.MustQuery("select a, b, count(*) from t group by grouping sets ((a), (b), ()) order by grouping(a, b), a, b").
+		Check(testkit.Rows("1 <nil> 2", "2 <nil> 2", "<nil> <nil> 1", "<nil> x 2", "<nil> y 1", "<nil> <nil> 4"))
+	// The duplicated grouping sets are aggregated separately.
+	tk.MustQuery("select a, count(*) from t group by grouping sets ((a), (a, a), ()) order by grouping(a), a").
+		Check(testkit.Rows("1 2", "1 2", "2 2", "2 2", "<nil> 4"))
+	tk.MustQuery("select a, sum(c) from t group by a with rollup having grouping(a) = 1").Check(testkit.Rows("<nil> 100"))
+	tk.MustQuery("select a, sum(c) from t where a > 5 group by a with rollup").Check(testkit.Rows())
+	tk.MustQuery("select a, b, sum(c) from t group by 1, 2 with rollup having a = 2 and b is null order by grouping(b)").
+		Check(testkit.Rows("2 <nil> 40", "2 <nil> 70"))
+
+	tk.MustGetErrCode("select grouping(a) from t", errno.ErrInvalidGroupFuncUse)
+	tk.MustGetErrCode("select grouping(a) from t group by a", errno.ErrInvalidGroupFuncUse)
+	tk.MustGetErrCode("select grouping(c) from t group by a with rollup", errno.ErrFieldInGroupingNotGroupBy)
+	tk.MustGetErrCode("select sum(c) from t group by a + 1 with rollup", errno.ErrNotSupportedYet)
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFulltextMatchAgainst(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table articles (id int primary key, title varchar(200), body text, fulltext key ft (title, body))")
+	tk.MustExec(`insert into articles values
+		(1, 'MySQL Tutorial', 'DBMS stands for DataBase ...'),
+		(2, 'How To Use MySQL Well', 'After you went through a ...'),
+		(3, 'Optimizing MySQL', 'In this tutorial, we show ...'),
+		(4, '1001 MySQL Tricks', '1. Never run mysqld as root. 2. ...'),
+		(5, 'MySQL vs. YourSQL', 'In the following database comparison ...'),
+		(6, 'MySQL Security', 'When configured properly, MySQL ...')`)
+
+	// Natural language mode, the rows are ordered by the relevance.
+	tk.MustQuery("select id from articles where match (title, body) against ('database') order by match (title, body) against ('database') desc, id").
+		Check(testkit.Rows("1", "5"))
+	tk.MustQuery("select id from articles where match (body, title) against ('tutorial security') order by id").
+		Check(testkit.Rows("1", "3", "6"))
+	tk.MustQuery("select id, (match (title, body) against ('tutorial')) > 0 from articles order by id").
+		Check(testkit.Rows("1 1", "2 0", "3 1", "4 0", "5 0", "6 0"))
+
+	// Boolean mode.
+	tk.MustQuery("select id from articles where match (title, body) against ('+MySQL -YourSQL' in boolean mode) order by id").
+		Check(testkit.Rows("1", "2", "3", "4", "6"))
+	tk.MustQuery(`select id from articles where match (title, body) against ('"following database"' in boolean mode)`).
+		Check(testkit.Rows("5"))
+	tk.MustQuery("select id from articles where match (title, body) against ('optim*' in boolean mode)").
+		Check(testkit.Rows("3"))
+
+	// The FULLTEXT index is used by IndexMerge.
+	rows := tk.MustQuery("explain select id from articles where match (title, body) against ('tutorial security')").Rows()
+	require.True(t, strings.Contains(rows[2][0].(string), "IndexMerge"), "%v", rows)
+	tk.MustQuery("explain format = 'brief' select id from articles where match (title, body) against ('+security' in boolean mode) and id > 1").CheckAt([]int{0, 3, 4}, testkit.RowsWithSep("|",
+		"Projection||test.articles.id",
+		"└─Selection||match(test.articles.title, test.articles.body) against(\"+security\" in boolean mode)",
+		"  └─IndexMerge||",
+		"    ├─IndexRangeScan(Build)|table:articles, index:ft(title, body)|range:[\"security\",\"security\"], keep order:false, stats:pseudo",
+		"    └─Selection(Probe)||gt(test.articles.id, 1)",
+		"      └─TableRowIDScan|table:articles|keep order:false, stats:pseudo",
+	))
+
+	// The index is maintained by the DML statements.
+	tk.MustExec("update articles set body = 'Database security' where id = 6")
+	tk.MustExec("delete from articles where id = 1")
+	tk.MustExec("insert into articles values (7, 'TiDB', 'A distributed database')")
+	tk.MustQuery("select id from articles where match (title, body) against ('database') order by id").
+		Check(testkit.Rows("5", "6", "7"))
+	tk.MustExec("admin check table articles")
+
+	// The FULLTEXT index is created on the existing rows.
+	tk.MustExec("create table t (a varchar(100), b text)")
+	tk.MustExec("insert into t values ('数据库', 'full-text search'), ('分布式', 'search engine')")
+	tk.MustExec("alter table t add fulltext key ft_a (a) with parser ngram")
+	tk.MustExec("create fulltext index ft_b on t (b)")
+	tk.MustQuery("select a from t where match (a) against ('数据' in boolean mode)").Check(testkit.Rows("数据库"))
+	tk.MustQuery("select a from t where match (b) against ('search') order by a").Check(testkit.Rows("分布式", "数据库"))
+	tk.MustQuery("select a from t where match (b) against ('engine')").Check(testkit.Rows("分布式"))
+
+	tk.MustGetErrCode("select * from t where match (a, b) against ('search')", errno.ErrFtMatchingKeyNotFound)
+	tk.MustGetErrCode("select * from t where match (a) against (b)", errno.ErrWrongArguments)
+	tk.MustGetErrCode("select * from t where match (b) against ('search' with query expansion)", errno.ErrNotSupportedYet)
+}
@@ -16,14 +16,29 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/planner"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/stmtsummary"
+	"github.com/pingcap/tidb/util/stringutil"
+	"golang.org/x/exp/slices"
 )
 
 // IndexAdviseExec represents a index advise executor.
@@ -123,13 +138,436 @@ func (e *IndexAdviseInfo) GetIndexAdvice(ctx context.Context, data []byte) error
 	if err := e.prepareInfo(data); err != nil {
 		return err
 	}
-	// TODO: Finish the index advise process. It will be done in another PR.
+	currentDB := e.Ctx.GetSessionVars().CurrentDB
+	queries := make([]*adviseQuery, 0, len(e.StmtNodes))
+	for _, stmtNodes := range e.StmtNodes {
+		for _, stmtNode := range stmtNodes {
+			queries = append(queries, &adviseQuery{schema: currentDB, sql: stmtNode.Text(), weight: 1, stmt: stmtNode})
+		}
+	}
+	indexes, err := newIndexAdvisor(e.Ctx, e.MaxMinutes, e.MaxIndexNum).advise(ctx, queries, true)
+	if err != nil {
+		return err
+	}
+	e.Result = &IndexAdvice{Indexes: indexes}
 	return nil
 }
 
 // IndexAdvice represents the index advice.
 type IndexAdvice struct {
-	// TODO: Define index advice data structure and implements the ResultSet interface. It will be done in another PR
+	// Indexes are the recommended indexes ordered by the estimated benefit.
+	Indexes []*IndexRecommendation
+}
+
+// IndexRecommendation is an index recommended by the index advisor.
+type IndexRecommendation struct {
+	Database string
+	Table    string
+	Name     string
+	Columns  []string
+	// Benefit is the estimated cost reduced by the index for the whole workload.
+	Benefit float64
+	// TopImpactedQuery is the query whose cost is reduced the most by the index.
+	TopImpactedQuery string
+}
+
+// CreateStatement returns the statement to create the recommended index.
+func (r *IndexRecommendation) CreateStatement() string {
+	cols := make([]string, 0, len(r.Columns))
+	for _, col := range r.Columns {
+		cols = append(cols, stringutil.Escape(col, 0))
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s.%s(%s)", stringutil.Escape(r.Name, 0), stringutil.Escape(r.Database, 0),
+		stringutil.Escape(r.Table, 0), strings.Join(cols, ", "))
+}
+
+// RecommendIndexExec represents a recommend index executor.
+type RecommendIndexExec struct {
+	baseExecutor
+
+	sql         string
+	maxMinutes  uint64
+	maxIndexNum *ast.MaxIndexNumClause
+	done        bool
+}
+
+// Next implements the Executor Next interface.
+func (e *RecommendIndexExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	queries, err := e.getWorkload()
+	if err != nil {
+		return err
+	}
+	indexes, err := newIndexAdvisor(e.ctx, e.maxMinutes, e.maxIndexNum).advise(ctx, queries, e.sql == "")
+	if err != nil {
+		return err
+	}
+	for _, idx := range indexes {
+		req.AppendString(0, idx.Database)
+		req.AppendString(1, idx.Table)
+		req.AppendString(2, idx.Name)
+		req.AppendString(3, strings.Join(idx.Columns, ","))
+		req.AppendFloat64(4, idx.Benefit)
+		req.AppendString(5, idx.TopImpactedQuery)
+		req.AppendString(6, idx.CreateStatement())
+	}
+	return nil
+}
+
+// getWorkload gets the statements to recommend indexes for, which are the given SQL or the top statements in the
+// statement summary.
+func (e *RecommendIndexExec) getWorkload() ([]*adviseQuery, error) {
+	vars := e.ctx.GetSessionVars()
+	sqlParser := parser.New()
+	sqlParser.SetSQLMode(vars.SQLMode)
+	sqlParser.SetParserConfig(vars.BuildParserConfig())
+	charset, collation := vars.GetCharsetInfo()
+	if e.sql != "" {
+		stmtNodes, warns, err := sqlParser.ParseSQL(e.sql, parser.CharsetConnection(charset), parser.CollationConnection(collation))
+		if err != nil {
+			return nil, err
+		}
+		for _, warn := range warns {
+			vars.StmtCtx.AppendWarning(util.SyntaxWarn(warn))
+		}
+		queries := make([]*adviseQuery, 0, len(stmtNodes))
+		for _, stmtNode := range stmtNodes {
+			queries = append(queries, &adviseQuery{schema: vars.CurrentDB, sql: stmtNode.Text(), weight: 1, stmt: stmtNode})
+		}
+		return queries, nil
+	}
+	stmts := stmtsummary.StmtSummaryByDigestMap.GetTopLatencyStmts(maxAdviseWorkloadStmts)
+	queries := make([]*adviseQuery, 0, len(stmts))
+	for _, stmt := range stmts {
+		// The sample SQL may be truncated, so the statements which can't be parsed are ignored.
+		stmtNode, err := sqlParser.ParseOneStmt(stmt.Query, charset, collation)
+		if err != nil {
+			continue
+		}
+		queries = append(queries, &adviseQuery{schema: stmt.Schema, sql: stmt.Query, weight: float64(stmt.ExecCount), stmt: stmtNode})
+	}
+	return queries, nil
+}
+
+const (
+	// defaultMaxIndexNumPerTable and defaultMaxIndexNumPerDB limit the number of the recommended indexes if they are
+	// not specified by the MAX_IDXNUM clause.
+	defaultMaxIndexNumPerTable = 3
+	defaultMaxIndexNumPerDB    = 5
+	// maxAdviseIndexColumns is the max number of the columns of a recommended index.
+	maxAdviseIndexColumns = 3
+	// maxAdviseWorkloadStmts is the max number of the statements fetched from the statement summary.
+	maxAdviseWorkloadStmts = 100
+	// minAdviseCostReduceRatio is the min ratio of the plan cost that an index should reduce for a query, the smaller
+	// reduction is taken as the noise of the estimation.
+	minAdviseCostReduceRatio = 0.1
+)
+
+// adviseQuery is a query of the workload to advise indexes for.
+type adviseQuery struct {
+	schema string
+	sql    string
+	// weight is the number of times the query is executed.
+	weight float64
+	stmt   ast.StmtNode
+	// cost is the cost of the plan without the recommended indexes.
+	cost float64
+}
+
+// indexCandidate is an index which may reduce the cost of the workload.
+type indexCandidate struct {
+	dbName     model.CIStr
+	tblInfo    *model.TableInfo
+	columns    []*model.ColumnInfo
+	benefit    float64
+	topQuery   string
+	topBenefit float64
+	// queryBenefits are the benefits of the candidate to each query.
+	queryBenefits map[*adviseQuery]float64
+}
+
+func (c *indexCandidate) key() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.FormatInt(c.tblInfo.ID, 10))
+	for _, col := range c.columns {
+		sb.WriteString(",")
+		sb.WriteString(col.Name.L)
+	}
+	return sb.String()
+}
+
+// isPrefixOf checks whether the columns of the candidate are the prefix of the given index columns.
+func (c *indexCandidate) isPrefixOf(cols []*model.ColumnInfo) bool {
+	if len(c.columns) > len(cols) {
+		return false
+	}
+	for i, col := range c.columns {
+		if col.ID != cols[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// indexAdvisor recommends indexes for the workload. The candidates are enumerated from the columns used by the
+// predicates, the join keys and the ORDER BY / GROUP BY items of the queries, and each candidate is costed by planning
+// the queries with it as a hypothetical index.
+type indexAdvisor struct {
+	sctx        sessionctx.Context
+	is          infoschema.InfoSchema
+	deadline    time.Time
+	maxPerTable uint64
+	maxPerDB    uint64
+	warnings    []error
+}
+
+func newIndexAdvisor(sctx sessionctx.Context, maxMinutes uint64, maxIndexNum *ast.MaxIndexNumClause) *indexAdvisor {
+	a := &indexAdvisor{
+		sctx:        sctx,
+		maxPerTable: defaultMaxIndexNumPerTable,
+		maxPerDB:    defaultMaxIndexNumPerDB,
+	}
+	if maxMinutes != ast.UnspecifiedSize {
+		a.deadline = time.Now().Add(time.Duration(maxMinutes) * time.Minute)
+	}
+	if maxIndexNum != nil {
+		if maxIndexNum.PerTable != ast.UnspecifiedSize {
+			a.maxPerTable = maxIndexNum.PerTable
+		}
+		if maxIndexNum.PerDB != ast.UnspecifiedSize {
+			a.maxPerDB = maxIndexNum.PerDB
+		}
+	}
+	return a
+}
+
+// advise returns the recommended indexes for the queries. If ignoreErr is true, the queries which fail to be planned
+// are skipped with warnings, otherwise the error is returned.
+func (a *indexAdvisor) advise(ctx context.Context, queries []*adviseQuery, ignoreErr bool) ([]*IndexRecommendation, error) {
+	vars := a.sctx.GetSessionVars()
+	sc := vars.StmtCtx
+	// The hypothetical indexes are only visible to the optimizer when a statement is explained.
+	origDB, origHypoIndexes, origWarnings := vars.CurrentDB, vars.HypoIndexes, sc.GetWarnings()
+	origInExplain, origInExplainAnalyze := sc.InExplainStmt, sc.InExplainAnalyzeStmt
+	sc.InExplainStmt, sc.InExplainAnalyzeStmt = true, false
+	defer func() {
+		vars.CurrentDB, vars.HypoIndexes = origDB, origHypoIndexes
+		sc.InExplainStmt, sc.InExplainAnalyzeStmt = origInExplain, origInExplainAnalyze
+		sc.SetWarnings(origWarnings)
+		for _, warn := range a.warnings {
+			sc.AppendWarning(warn)
+		}
+	}()
+
+	candidates := make(map[string]*indexCandidate)
+	for _, q := range queries {
+		if !a.deadline.IsZero() && time.Now().After(a.deadline) {
+			a.warnings = append(a.warnings, errors.New("Index Advise: the time limit is exceeded, the rest of the workload is ignored"))
+			break
+		}
+		queryCandidates, err := a.prepareQuery(ctx, q)
+		if err == nil {
+			err = a.evaluate(ctx, q, queryCandidates, candidates)
+		}
+		if err != nil {
+			if !ignoreErr {
+				return nil, err
+			}
+			a.warnings = append(a.warnings, errors.Errorf("Index Advise: skip the query '%s': %v", q.sql, err))
+		}
+	}
+	return a.chooseIndexes(candidates), nil
+}
+
+// prepareQuery computes the cost of the query without the recommended indexes and extracts the index candidates.
+func (a *indexAdvisor) prepareQuery(ctx context.Context, q *adviseQuery) ([]*indexCandidate, error) {
+	switch q.stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt:
+	default:
+		return nil, nil
+	}
+	a.sctx.GetSessionVars().CurrentDB = q.schema
+	ret := &plannercore.PreprocessorReturn{}
+	if err := plannercore.Preprocess(a.sctx, q.stmt, plannercore.WithPreprocessorReturn(ret)); err != nil {
+		return nil, err
+	}
+	a.is = ret.InfoSchema
+	cost, err := a.planCost(ctx, q, nil)
+	if err != nil {
+		return nil, err
+	}
+	q.cost = cost
+	return extractIndexCandidates(q.stmt), nil
+}
+
+// evaluate costs the query with each candidate, and accumulates the benefits to the candidates of the workload.
+func (a *indexAdvisor) evaluate(ctx context.Context, q *adviseQuery, queryCandidates []*indexCandidate, candidates map[string]*indexCandidate) error {
+	for _, candidate := range queryCandidates {
+		cost, err := a.planCost(ctx, q, candidate)
+		if err != nil {
+			return err
+		}
+		benefit := 0.0
+		if q.cost-cost > q.cost*minAdviseCostReduceRatio {
+			benefit = (q.cost - cost) * q.weight
+		}
+		if benefit == 0 {
+			continue
+		}
+		key := candidate.key()
+		if c, ok := candidates[key]; ok {
+			candidate = c
+		} else {
+			candidate.queryBenefits = make(map[*adviseQuery]float64)
+			candidates[key] = candidate
+		}
+		candidate.benefit += benefit
+		candidate.queryBenefits[q] += benefit
+		if benefit > candidate.topBenefit {
+			candidate.topBenefit = benefit
+			candidate.topQuery = q.sql
+		}
+	}
+	return nil
+}
+
+// planCost returns the cost of the plan of the query, the candidate is used as a hypothetical index if it's not nil.
+func (a *indexAdvisor) planCost(ctx context.Context, q *adviseQuery, candidate *indexCandidate) (float64, error) {
+	vars := a.sctx.GetSessionVars()
+	vars.HypoIndexes = nil
+	if candidate != nil {
+		idxCols := make([]*model.IndexColumn, 0, len(candidate.columns))
+		for _, col := range candidate.columns {
+			idxCols = append(idxCols, &model.IndexColumn{Name: col.Name, Offset: col.Offset, Length: types.UnspecifiedLength})
+		}
+		vars.HypoIndexes = map[int64][]*model.IndexInfo{
+			candidate.tblInfo.ID: {{
+				ID:      ddl.HypoIndexIDBase,
+				Name:    model.NewCIStr("hypo_index"),
+				Table:   candidate.tblInfo.Name,
+				Columns: idxCols,
+				State:   model.StatePublic,
+				Tp:      model.IndexTypeHypo,
+			}},
+		}
+	}
+	p, _, err := planner.Optimize(ctx, a.sctx, q.stmt, a.is)
+	if err != nil {
+		return 0, err
+	}
+	physicalPlan, ok := p.(plannercore.PhysicalPlan)
+	if !ok {
+		return 0, errors.Errorf("unexpected plan %T", p)
+	}
+	return physicalPlan.GetPlanCost(property.RootTaskType, plannercore.CostFlagRecalculate)
+}
+
+// chooseIndexes chooses the candidates greedily by their benefits under the limits of the index numbers. The benefit
+// of a candidate to a query is discounted by the benefit of the chosen index on the same table, since the query can
+// only use one of them to access the table.
+func (a *indexAdvisor) chooseIndexes(candidates map[string]*indexCandidate) []*IndexRecommendation {
+	remaining := make([]*indexCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.benefit > 0 {
+			remaining = append(remaining, c)
+		}
+	}
+	// Prefer the narrower index if the benefits are the same.
+	slices.SortFunc(remaining, func(i, j *indexCandidate) bool {
+		if len(i.columns) != len(j.columns) {
+			return len(i.columns) < len(j.columns)
+		}
+		return i.key() < j.key()
+	})
+	type queryTable struct {
+		q       *adviseQuery
+		tableID int64
+	}
+	served := make(map[queryTable]float64)
+	chosen := make(map[int64][]*indexCandidate)
+	numPerDB := make(map[string]uint64)
+	indexes := make([]*IndexRecommendation, 0)
+	for {
+		var (
+			best        *indexCandidate
+			bestBenefit float64
+		)
+		for _, c := range remaining {
+			tblChosen := chosen[c.tblInfo.ID]
+			if uint64(len(tblChosen)) >= a.maxPerTable || numPerDB[c.dbName.L] >= a.maxPerDB {
+				continue
+			}
+			covered := false
+			for _, other := range tblChosen {
+				if c.isPrefixOf(other.columns) || other.isPrefixOf(c.columns) {
+					covered = true
+					break
+				}
+			}
+			if covered {
+				continue
+			}
+			benefit := 0.0
+			for q, b := range c.queryBenefits {
+				if s := served[queryTable{q, c.tblInfo.ID}]; b > s {
+					benefit += b - s
+				}
+			}
+			if benefit > bestBenefit {
+				best, bestBenefit = c, benefit
+			}
+		}
+		if best == nil {
+			return indexes
+		}
+		chosen[best.tblInfo.ID] = append(chosen[best.tblInfo.ID], best)
+		numPerDB[best.dbName.L]++
+		for q, b := range best.queryBenefits {
+			if key := (queryTable{q, best.tblInfo.ID}); b > served[key] {
+				served[key] = b
+			}
+		}
+		cols := make([]string, 0, len(best.columns))
+		for _, col := range best.columns {
+			cols = append(cols, col.Name.O)
+		}
+		indexes = append(indexes, &IndexRecommendation{
+			Database:         best.dbName.O,
+			Table:            best.tblInfo.Name.O,
+			Name:             genAdviseIndexName(best.dbName, best.tblInfo, cols, indexes),
+			Columns:          cols,
+			Benefit:          bestBenefit,
+			TopImpactedQuery: best.topQuery,
+		})
+	}
+}
+
+// genAdviseIndexName generates a name for the recommended index which doesn't conflict with the existing indexes.
+func genAdviseIndexName(dbName model.CIStr, tblInfo *model.TableInfo, cols []string, recommended []*IndexRecommendation) string {
+	name := "idx_" + strings.Join(cols, "_")
+	if len(name) > mysql.MaxIndexIdentifierLen-4 {
+		name = name[:mysql.MaxIndexIdentifierLen-4]
+	}
+	exists := func(name string) bool {
+		if tblInfo.FindIndexByName(strings.ToLower(name)) != nil {
+			return true
+		}
+		for _, idx := range recommended {
+			if idx.Database == dbName.O && idx.Table == tblInfo.Name.O && strings.EqualFold(idx.Name, name) {
+				return true
+			}
+		}
+		return false
+	}
+	newName := name
+	for i := 2; exists(newName); i++ {
+		newName = fmt.Sprintf("%s_%d", name, i)
+	}
+	return newName
 }
 
 // IndexAdviseVarKeyType is a dummy type to avoid naming collision in context.
@@ -142,3 +580,272 @@ func (k IndexAdviseVarKeyType) String() string {
 
 // IndexAdviseVarKey is a variable key for index advise.
 const IndexAdviseVarKey IndexAdviseVarKeyType = 0
+
+// candidateTable is a table referred by a query, with the columns which may benefit from indexes.
+type candidateTable struct {
+	alias string
+	tbl   *ast.TableName
+	// eqCols are the columns compared with constants by equal conditions or used as join keys.
+	eqCols []*model.ColumnInfo
+	// rangeCols are the columns compared with constants by range conditions.
+	rangeCols []*model.ColumnInfo
+	// orderCols are the columns of the ORDER BY or GROUP BY items.
+	orderCols []*model.ColumnInfo
+}
+
+func appendCandidateColumn(cols []*model.ColumnInfo, col *model.ColumnInfo) []*model.ColumnInfo {
+	for _, c := range cols {
+		if c.ID == col.ID {
+			return cols
+		}
+	}
+	return append(cols, col)
+}
+
+// candidates enumerates the single-column and the composite index candidates of the table.
+func (t *candidateTable) candidates() [][]*model.ColumnInfo {
+	eqCols := t.eqCols
+	if len(eqCols) > maxAdviseIndexColumns {
+		eqCols = eqCols[:maxAdviseIndexColumns]
+	}
+	result := make([][]*model.ColumnInfo, 0, len(t.eqCols)+len(t.rangeCols)+4)
+	for _, col := range t.eqCols {
+		result = append(result, []*model.ColumnInfo{col})
+	}
+	for _, col := range t.rangeCols {
+		result = append(result, []*model.ColumnInfo{col})
+	}
+	if len(t.orderCols) > 0 {
+		result = append(result, t.orderCols[:1])
+	}
+	if len(eqCols) > 1 {
+		result = append(result, eqCols)
+	}
+	// The equal conditions are followed by a range condition or the ORDER BY columns in a composite index.
+	if len(eqCols) > 0 && len(eqCols) < maxAdviseIndexColumns {
+		for _, col := range t.rangeCols {
+			result = append(result, append(eqCols[:len(eqCols):len(eqCols)], col))
+		}
+	}
+	if len(t.orderCols) > 1 {
+		result = append(result, t.orderCols)
+	}
+	if len(eqCols) > 0 && len(t.orderCols) > 0 {
+		cols := eqCols[:len(eqCols):len(eqCols)]
+		for _, col := range t.orderCols {
+			cols = appendCandidateColumn(cols, col)
+		}
+		result = append(result, cols)
+	}
+	for i, cols := range result {
+		if len(cols) > maxAdviseIndexColumns {
+			result[i] = cols[:maxAdviseIndexColumns]
+		}
+	}
+	return result
+}
+
+// indexCandidateExtractor extracts the index candidates from a preprocessed statement.
+type indexCandidateExtractor struct {
+	tables []*candidateTable
+	// collectingTables indicates whether the extractor is collecting the tables or the columns.
+	collectingTables bool
+}
+
+func extractIndexCandidates(stmt ast.StmtNode) []*indexCandidate {
+	e := &indexCandidateExtractor{collectingTables: true}
+	stmt.Accept(e)
+	e.collectingTables = false
+	stmt.Accept(e)
+
+	candidates := make([]*indexCandidate, 0)
+	keys := make(map[string]struct{})
+	for _, t := range e.tables {
+		for _, cols := range t.candidates() {
+			candidate := &indexCandidate{dbName: t.tbl.Schema, tblInfo: t.tbl.TableInfo, columns: cols}
+			if _, ok := keys[candidate.key()]; ok || !isValidIndexCandidate(candidate) {
+				continue
+			}
+			keys[candidate.key()] = struct{}{}
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// isValidIndexCandidate checks whether the candidate can be created and is not covered by the existing indexes.
+func isValidIndexCandidate(c *indexCandidate) bool {
+	for _, col := range c.columns {
+		switch col.GetType() {
+		case mysql.TypeJSON, mysql.TypeGeometry, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+			return false
+		}
+	}
+	if c.tblInfo.PKIsHandle && mysql.HasPriKeyFlag(c.columns[0].GetFlag()) {
+		return false
+	}
+	for _, idx := range c.tblInfo.Indices {
+		if idx.State != model.StatePublic || len(idx.Columns) < len(c.columns) {
+			continue
+		}
+		covered := true
+		for i, col := range c.columns {
+			if idx.Columns[i].Offset != col.Offset || idx.Columns[i].Length != types.UnspecifiedLength {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return false
+		}
+	}
+	return true
+}
+
+// Enter implements Visitor interface.
+func (e *indexCandidateExtractor) Enter(in ast.Node) (ast.Node, bool) {
+	if e.collectingTables {
+		if ts, ok := in.(*ast.TableSource); ok {
+			tn, ok := ts.Source.(*ast.TableName)
+			if ok && tn.TableInfo != nil && !tn.TableInfo.IsView() && !tn.TableInfo.IsSequence() &&
+				tn.TableInfo.TempTableType == model.TempTableNone && !util.IsMemOrSysDB(tn.Schema.L) {
+				alias := ts.AsName.L
+				if alias == "" {
+					alias = tn.Name.L
+				}
+				e.tables = append(e.tables, &candidateTable{alias: alias, tbl: tn})
+			}
+		}
+		return in, false
+	}
+	switch x := in.(type) {
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.EQ, opcode.NullEQ:
+			lt, lCol := e.resolveColumn(x.L)
+			rt, rCol := e.resolveColumn(x.R)
+			if lCol != nil && (rCol != nil || isAdviseConstant(x.R)) {
+				lt.eqCols = appendCandidateColumn(lt.eqCols, lCol)
+			}
+			if rCol != nil && (lCol != nil || isAdviseConstant(x.L)) {
+				rt.eqCols = appendCandidateColumn(rt.eqCols, rCol)
+			}
+		case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+			if t, col := e.resolveColumn(x.L); col != nil && isAdviseConstant(x.R) {
+				t.rangeCols = appendCandidateColumn(t.rangeCols, col)
+			} else if t, col := e.resolveColumn(x.R); col != nil && isAdviseConstant(x.L) {
+				t.rangeCols = appendCandidateColumn(t.rangeCols, col)
+			}
+		}
+	case *ast.PatternInExpr:
+		if t, col := e.resolveColumn(x.Expr); col != nil && !x.Not && x.Sel == nil && isAdviseConstant(x.List...) {
+			t.eqCols = appendCandidateColumn(t.eqCols, col)
+		}
+	case *ast.IsNullExpr:
+		if t, col := e.resolveColumn(x.Expr); col != nil && !x.Not {
+			t.eqCols = appendCandidateColumn(t.eqCols, col)
+		}
+	case *ast.BetweenExpr:
+		if t, col := e.resolveColumn(x.Expr); col != nil && !x.Not && isAdviseConstant(x.Left, x.Right) {
+			t.rangeCols = appendCandidateColumn(t.rangeCols, col)
+		}
+	case *ast.PatternLikeExpr:
+		// Only the patterns with a constant prefix can be converted to ranges.
+		if t, col := e.resolveColumn(x.Expr); col != nil && !x.Not {
+			if v, ok := x.Pattern.(ast.ValueExpr); ok {
+				if pattern := v.GetString(); pattern != "" && pattern[0] != '%' && pattern[0] != '_' {
+					t.rangeCols = appendCandidateColumn(t.rangeCols, col)
+				}
+			}
+		}
+	case *ast.SelectStmt:
+		if x.OrderBy != nil {
+			e.collectOrderColumns(x.OrderBy.Items)
+		} else if x.GroupBy != nil {
+			e.collectOrderColumns(x.GroupBy.Items)
+		}
+	}
+	return in, false
+}
+
+// collectOrderColumns collects the columns of the ORDER BY or GROUP BY items if they all belong to the same table.
+func (e *indexCandidateExtractor) collectOrderColumns(items []*ast.ByItem) {
+	var (
+		table *candidateTable
+		cols  []*model.ColumnInfo
+	)
+	for _, item := range items {
+		t, col := e.resolveColumn(item.Expr)
+		if col == nil || (table != nil && t != table) {
+			return
+		}
+		table = t
+		cols = appendCandidateColumn(cols, col)
+	}
+	if table != nil && len(table.orderCols) == 0 {
+		table.orderCols = cols
+	}
+}
+
+// resolveColumn finds the table and the column which the expression refers to, it returns nil if the expression is
+// not a column or the column is ambiguous.
+func (e *indexCandidateExtractor) resolveColumn(expr ast.ExprNode) (*candidateTable, *model.ColumnInfo) {
+	for {
+		p, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			break
+		}
+		expr = p.Expr
+	}
+	colExpr, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, nil
+	}
+	name := colExpr.Name
+	var (
+		table *candidateTable
+		col   *model.ColumnInfo
+	)
+	for _, t := range e.tables {
+		if (name.Table.L != "" && name.Table.L != t.alias) || (name.Schema.L != "" && name.Schema.L != t.tbl.Schema.L) {
+			continue
+		}
+		c := model.FindColumnInfo(t.tbl.TableInfo.Columns, name.Name.L)
+		if c == nil || c.State != model.StatePublic {
+			continue
+		}
+		// The same table may be referred by different query blocks.
+		if table != nil && table.tbl.TableInfo.ID != t.tbl.TableInfo.ID {
+			return nil, nil
+		}
+		if table == nil {
+			table, col = t, c
+		}
+	}
+	return table, col
+}
+
+// Leave implements Visitor interface.
+func (e *indexCandidateExtractor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// isAdviseConstant checks whether the expressions are constants, which can be used to build index ranges.
+func isAdviseConstant(exprs ...ast.ExprNode) bool {
+	for _, expr := range exprs {
+		switch x := expr.(type) {
+		case ast.ValueExpr, ast.ParamMarkerExpr:
+		case *ast.UnaryOperationExpr:
+			if !isAdviseConstant(x.V) {
+				return false
+			}
+		case *ast.ParenthesesExpr:
+			if !isAdviseConstant(x.Expr) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -15,10 +15,14 @@
 package executor_test
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
@@ -68,4 +72,64 @@ func TestIndexAdvise(t *testing.T) {
 	require.Equal(t, uint64(3), ia.MaxMinutes)
 	require.Equal(t, uint64(4), ia.MaxIndexNum.PerTable)
 	require.Equal(t, uint64(5), ia.MaxIndexNum.PerDB)
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, key(b))")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), data))
+	require.NotNil(t, ia.Result)
+	require.NotEmpty(t, ia.Result.Indexes)
+	for _, idx := range ia.Result.Indexes {
+		require.Greater(t, idx.Benefit, 0.0)
+		require.NotEqual(t, "t2", idx.Table)
+	}
+}
+
+func TestRecommendIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, d json, key(c))")
+	tk.MustExec("create table t1(a int, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d, '{}')", i, i%10, i))
+		tk.MustExec(fmt.Sprintf("insert into t1 values (%d, %d)", i, i))
+	}
+	tk.MustExec("analyze table t, t1")
+
+	tk.MustQuery("recommend index for 'select * from t where a = 1'").CheckAt([]int{0, 1, 2, 3, 5, 6}, [][]interface{}{
+		{"test", "t", "idx_a", "a", "select * from t where a = 1", "CREATE INDEX `idx_a` ON `test`.`t`(`a`)"}})
+	tk.MustQuery("recommend index for 'select * from t where b = 1 and a > 10 and a < 20'").CheckAt([]int{2, 3}, testkit.Rows(
+		"idx_b_a b,a"))
+	// The columns already covered by the existing indexes and the columns can't be indexed are not recommended.
+	tk.MustQuery("recommend index for 'select * from t where c = 1 and d is null'").Check(testkit.Rows())
+	tk.MustQuery("recommend index for 'select * from t where a = 1; select * from t1 where b = 1' max_idxnum per_db 1").CheckAt([]int{1, 2}, testkit.Rows(
+		"t idx_a"))
+	err := tk.QueryToErr("recommend index for 'select * from t_not_exist'")
+	require.True(t, infoschema.ErrTableNotExists.Equal(err), "%v", err)
+
+	// The hypothetical indexes created by the advisor are invisible to the statement executed afterwards.
+	require.Nil(t, tk.Session().GetSessionVars().HypoIndexes)
+	rows := tk.MustQuery("explain format = 'brief' select * from t where a = 1").Rows()
+	require.NotContains(t, fmt.Sprintf("%v", rows), "idx_a")
+
+	// Recommend indexes for the top statements in the statement summary.
+	// Disabling the statement summary clears the statements of other tests.
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	defer tk.MustExec("set global tidb_enable_stmt_summary = default")
+	// The statements without users are taken as internal ones.
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	for i := 0; i < 3; i++ {
+		tk.MustQuery("select * from t1 where a = 1").Check(testkit.Rows("1 1"))
+	}
+	rows = tk.MustQuery("recommend index").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "CREATE INDEX `idx_a` ON `test`.`t1`(`a`)", rows[0][6])
+	require.Equal(t, "select * from t1 where a = 1", rows[0][5])
 }
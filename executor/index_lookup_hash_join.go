@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/ranger"
 )
@@ -127,6 +128,8 @@ func (e *IndexNestedLoopHashJoin) Open(ctx context.Context) error {
 	}
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
 	e.cancelFunc = nil
 	e.innerPtrBytes = make([][]byte, 0, 8)
 	if e.runtimeStats != nil {
@@ -616,6 +619,12 @@ func (iw *indexHashJoinInnerWorker) handleTask(ctx context.Context, task *indexH
 			close(resultCh)
 		}
 	}()
+	defer func() {
+		// The inner result is not used after the task is processed.
+		if closeErr := task.closeInnerResult(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
 	var joinStartTime time.Time
 	if iw.stats != nil {
 		start := time.Now()
@@ -662,11 +671,16 @@ func (iw *indexHashJoinInnerWorker) handleTask(ctx context.Context, task *indexH
 
 func (iw *indexHashJoinInnerWorker) doJoinUnordered(ctx context.Context, task *indexHashJoinTask, joinResult *indexHashJoinResult, h hash.Hash64, resultCh chan *indexHashJoinResult) error {
 	var ok bool
-	iter := chunk.NewIterator4List(task.innerResult)
-	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
-		ok, joinResult = iw.joinMatchedInnerRow2Chunk(ctx, row, task, joinResult, h, iw.joinKeyBuf)
-		if !ok {
-			return joinResult.err
+	for i, numChunks := 0, task.innerResult.NumChunks(); i < numChunks; i++ {
+		chk, err := task.innerResult.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < chk.NumRows(); j++ {
+			ok, joinResult = iw.joinMatchedInnerRow2Chunk(ctx, chk.GetRow(j), task, joinResult, h, iw.joinKeyBuf)
+			if !ok {
+				return joinResult.err
+			}
 		}
 	}
 	for chkIdx, outerRowStatus := range task.outerRowStatus {
@@ -797,7 +811,11 @@ func (iw *indexHashJoinInnerWorker) doJoinInOrder(ctx context.Context, task *ind
 		}
 	}()
 	for i, numChunks := 0, task.innerResult.NumChunks(); i < numChunks; i++ {
-		for j, chk := 0, task.innerResult.GetChunk(i); j < chk.NumRows(); j++ {
+		chk, err := task.innerResult.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < chk.NumRows(); j++ {
 			row := chk.GetRow(j)
 			ptr := chunk.RowPtr{ChkIdx: uint32(i), RowIdx: uint32(j)}
 			err = iw.collectMatchedInnerPtrs4OuterRows(ctx, row, ptr, task, h, iw.joinKeyBuf)
@@ -817,7 +835,11 @@ func (iw *indexHashJoinInnerWorker) doJoinInOrder(ctx context.Context, task *ind
 			matchedInnerRows, hasMatched, hasNull = matchedInnerRows[:0], false, false
 			outerRow := task.outerResult.GetChunk(chkIdx).GetRow(outerRowIdx)
 			for _, ptr := range innerRowPtrs {
-				matchedInnerRows = append(matchedInnerRows, task.innerResult.GetRow(ptr))
+				innerRow, err := task.innerResult.GetRow(ptr)
+				if err != nil {
+					return err
+				}
+				matchedInnerRows = append(matchedInnerRows, innerRow)
 			}
 			iter := chunk.NewIterator4Slice(matchedInnerRows)
 			for iter.Begin(); iter.Current() != iter.End(); {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
@@ -81,7 +83,8 @@ type IndexLookUpJoin struct {
 	// lastColHelper store the information for last col if there's complicated filter like col > x_col and col < x_col + 100.
 	lastColHelper *plannercore.ColWithCmpFuncManager
 
-	memTracker *memory.Tracker // track memory usage.
+	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *disk.Tracker   // track disk usage.
 
 	stats    *indexLookUpJoinRuntimeStats
 	finished *atomic.Value
@@ -113,7 +116,8 @@ type lookUpJoinTask struct {
 	outerResult *chunk.List
 	outerMatch  [][]bool
 
-	innerResult       *chunk.List
+	// innerResult may be spilled to disk if the memory quota of the query is exceeded.
+	innerResult       *chunk.RowContainer
 	encodedLookUpKeys []*chunk.Chunk
 	lookupMap         *mvmap.MVMap
 	matchedInners     []chunk.Row
@@ -167,6 +171,8 @@ func (e *IndexLookUpJoin) Open(ctx context.Context) error {
 	}
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
 	e.innerPtrBytes = make([][]byte, 0, 8)
 	e.finished.Store(false)
 	if e.runtimeStats != nil {
@@ -276,7 +282,9 @@ func (e *IndexLookUpJoin) Next(ctx context.Context, req *chunk.Chunk) error {
 		}
 		startTime := time.Now()
 		if e.innerIter == nil || e.innerIter.Current() == e.innerIter.End() {
-			e.lookUpMatchedInners(task, task.cursor)
+			if err := e.lookUpMatchedInners(task, task.cursor); err != nil {
+				return err
+			}
 			e.innerIter = chunk.NewIterator4Slice(task.matchedInners)
 			e.innerIter.Begin()
 		}
@@ -320,6 +328,9 @@ func (e *IndexLookUpJoin) getFinishedTask(ctx context.Context) (*lookUpJoinTask,
 	// The previous task has been processed, so release the occupied memory
 	if task != nil {
 		task.memTracker.Detach()
+		if err := task.closeInnerResult(); err != nil {
+			return nil, err
+		}
 	}
 	select {
 	case task = <-e.resultCh:
@@ -343,16 +354,35 @@ func (e *IndexLookUpJoin) getFinishedTask(ctx context.Context) (*lookUpJoinTask,
 	return task, nil
 }
 
-func (e *IndexLookUpJoin) lookUpMatchedInners(task *lookUpJoinTask, rowPtr chunk.RowPtr) {
+func (e *IndexLookUpJoin) lookUpMatchedInners(task *lookUpJoinTask, rowPtr chunk.RowPtr) error {
 	outerKey := task.encodedLookUpKeys[rowPtr.ChkIdx].GetRow(int(rowPtr.RowIdx)).GetBytes(0)
 	e.innerPtrBytes = task.lookupMap.Get(outerKey, e.innerPtrBytes[:0])
 	task.matchedInners = task.matchedInners[:0]
 
 	for _, b := range e.innerPtrBytes {
 		ptr := *(*chunk.RowPtr)(unsafe.Pointer(&b[0]))
-		matchedInner := task.innerResult.GetRow(ptr)
+		matchedInner, err := task.innerResult.GetRow(ptr)
+		if err != nil {
+			return err
+		}
 		task.matchedInners = append(task.matchedInners, matchedInner)
 	}
+	return nil
+}
+
+// closeInnerResult closes the inner result of the task to release the memory and delete the spilled files.
+func (task *lookUpJoinTask) closeInnerResult() error {
+	if task.innerResult == nil {
+		return nil
+	}
+	failpoint.Inject("testIndexLookUpJoinRowContainerSpill", func(val failpoint.Value) {
+		if val.(bool) {
+			task.innerResult.ActionSpill().WaitForTest()
+		}
+	})
+	err := task.innerResult.Close()
+	task.innerResult = nil
+	return err
 }
 
 func (ow *outerWorker) run(ctx context.Context, wg *sync.WaitGroup) {
@@ -690,9 +720,22 @@ func (iw *innerWorker) fetchInnerResults(ctx context.Context, task *lookUpJoinTa
 		return err
 	}
 
-	innerResult := chunk.NewList(retTypes(innerExec), iw.ctx.GetSessionVars().MaxChunkSize, iw.ctx.GetSessionVars().MaxChunkSize)
+	innerResult := chunk.NewRowContainer(retTypes(innerExec), iw.ctx.GetSessionVars().MaxChunkSize)
 	innerResult.GetMemTracker().SetLabel(memory.LabelForBuildSideResult)
 	innerResult.GetMemTracker().AttachTo(task.memTracker)
+	innerResult.GetDiskTracker().SetLabel(memory.LabelForBuildSideResult)
+	innerResult.GetDiskTracker().AttachTo(iw.lookup.diskTracker)
+	// The task is closed by the main thread, so the inner result is set before it's filled.
+	task.innerResult = innerResult
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		actionSpill := innerResult.ActionSpill()
+		failpoint.Inject("testIndexLookUpJoinRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				actionSpill = innerResult.ActionSpillForTest()
+			}
+		})
+		iw.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(actionSpill)
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -706,10 +749,11 @@ func (iw *innerWorker) fetchInnerResults(ctx context.Context, task *lookUpJoinTa
 		if iw.executorChk.NumRows() == 0 {
 			break
 		}
-		innerResult.Add(iw.executorChk)
+		if err := innerResult.Add(iw.executorChk); err != nil {
+			return err
+		}
 		iw.executorChk = newFirstChunk(innerExec)
 	}
-	task.innerResult = innerResult
 	return nil
 }
 
@@ -723,7 +767,10 @@ func (iw *innerWorker) buildLookUpMap(task *lookUpJoinTask) error {
 	keyBuf := make([]byte, 0, 64)
 	valBuf := make([]byte, 8)
 	for i := 0; i < task.innerResult.NumChunks(); i++ {
-		chk := task.innerResult.GetChunk(i)
+		chk, err := task.innerResult.GetChunk(i)
+		if err != nil {
+			return err
+		}
 		for j := 0; j < chk.NumRows(); j++ {
 			innerRow := chk.GetRow(j)
 			if iw.hasNullInJoinKey(innerRow) {
@@ -733,7 +780,6 @@ func (iw *innerWorker) buildLookUpMap(task *lookUpJoinTask) error {
 			keyBuf = keyBuf[:0]
 			for _, keyCol := range iw.hashCols {
 				d := innerRow.GetDatum(keyCol, iw.rowTypes[keyCol])
-				var err error
 				keyBuf, err = codec.EncodeKey(iw.ctx.GetSessionVars().StmtCtx, keyBuf, d)
 				if err != nil {
 					return err
@@ -762,11 +808,28 @@ func (e *IndexLookUpJoin) Close() error {
 		e.cancelFunc()
 	}
 	e.workerWg.Wait()
+	var firstErr error
+	if e.task != nil {
+		firstErr = e.task.closeInnerResult()
+	}
+	// The tasks which are not consumed by the main thread still hold their inner results.
+	if e.resultCh != nil {
+		for task := range e.resultCh {
+			if err := task.closeInnerResult(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		e.resultCh = nil
+	}
 	e.memTracker = nil
+	e.diskTracker = nil
 	e.task = nil
 	e.finished.Store(false)
 	e.prepared = false
-	return e.baseExecutor.Close()
+	if err := e.baseExecutor.Close(); err != nil {
+		return err
+	}
+	return firstErr
 }
 
 type indexLookUpJoinRuntimeStats struct {
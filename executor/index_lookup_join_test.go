@@ -21,6 +21,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
 )
@@ -500,3 +502,77 @@ func TestPartitionTableIndexJoinAndIndexReader(t *testing.T) {
 		tk.MustQuery("select /*+ TIDB_INLJ(t1, t2) */ t1.a from t t1, t t2 where t1.a=t2.b and " + cond).Sort().Check(result)
 	}
 }
+
+func TestIndexJoinInDisk(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.TempStoragePath = t.TempDir()
+	})
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/testIndexLookUpJoinRowContainerSpill", "return(true)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/testIndexLookUpJoinRowContainerSpill"))
+	}()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("SET GLOBAL tidb_mem_oom_action = DEFAULT")
+	tk.MustExec("SET GLOBAL tidb_mem_oom_action='LOG'")
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, key(a))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	tk.MustExec("insert into t2 values (1, 1), (1, 2), (2, 3), (3, 4), (3, 5), (6, 6)")
+	tk.MustExec("set @@tidb_index_join_batch_size=2")
+	tk.MustExec("set @@tidb_mem_quota_query=1")
+
+	queries := []string{
+		"select /*+ INL_JOIN(t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a, t2.b",
+		"select /*+ INL_HASH_JOIN(t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a, t2.b",
+		"select /*+ INL_HASH_JOIN(t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a",
+	}
+	for _, query := range queries {
+		tk.MustQuery(query).Sort().Check(testkit.Rows("1 1", "1 2", "2 3", "3 4", "3 5", "4 <nil>", "5 <nil>"))
+		require.Equal(t, int64(0), tk.Session().GetSessionVars().StmtCtx.DiskTracker.BytesConsumed())
+		require.Greater(t, tk.Session().GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), int64(0))
+	}
+}
+
+func TestIndexJoinSpillUnderMemQuota(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.TempStoragePath = t.TempDir()
+	})
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("SET GLOBAL tidb_mem_oom_action = DEFAULT")
+	tk.MustExec("SET GLOBAL tidb_mem_oom_action='CANCEL'")
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int, key(b))")
+	tk.MustExec("create table t2(a int, b int, c int)")
+	insertStr1, insertStr2 := "insert into t1 values(0, 0)", "insert into t2 values(0, 0, 0)"
+	for i := 1; i < 32768; i++ {
+		insertStr1 += fmt.Sprintf(", (%d, %d)", i, i)
+		insertStr2 += fmt.Sprintf(", (%d, %d, %d)", i, i, i)
+	}
+	tk.MustExec(insertStr1)
+	tk.MustExec(insertStr2)
+	// The query is canceled by the memory quota if the inner results of the index join aren't spilled. The batches are
+	// small so that the outer rows of the tasks, which aren't spilled, fit in the quota.
+	tk.MustExec("set @@tidb_index_join_batch_size=1000")
+	tk.MustExec("set @@tidb_mem_quota_query=1048576")
+
+	for _, test := range []struct{ hint, join string }{{"inl_join(t1)", "IndexJoin"}, {"inl_hash_join(t1)", "IndexHashJoin"}} {
+		sql := fmt.Sprintf("select /*+ %s */ t1.b, t2.a from t1 right join t2 on t1.b = t2.b", test.hint)
+		rows := tk.MustQuery(sql).Rows()
+		require.Len(t, rows, 32768, sql)
+		require.Greater(t, tk.Session().GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), int64(0), sql)
+		rows = tk.MustQuery("explain analyze " + sql).Rows()
+		require.Contains(t, rows[0][0], test.join, sql)
+		require.NotEqual(t, "N/A", rows[0][len(rows[0])-1], sql)
+	}
+}
@@ -123,6 +123,12 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			e.setDataFromIndexes(sctx, dbs)
 		case infoschema.TableViews:
 			e.setDataFromViews(sctx, dbs)
+		case infoschema.TableRoutines:
+			err = e.setDataFromRoutines(ctx, sctx)
+		case infoschema.TableEvents:
+			err = e.setDataFromEvents(ctx, sctx)
+		case infoschema.TableTriggers:
+			e.setDataFromTriggers(sctx, dbs)
 		case infoschema.TableEngines:
 			e.setDataFromEngines()
 		case infoschema.TableCharacterSets:
@@ -174,6 +180,8 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForClusterTrxSummary(sctx)
 		case infoschema.TableVariablesInfo:
 			err = e.setDataForVariablesInfo(sctx)
+		case infoschema.TableCardinalityCorrections:
+			e.setDataForCardinalityCorrections(sctx, is)
 		}
 		if err != nil {
 			return nil, err
@@ -393,6 +401,56 @@ func (e *memtableRetriever) setDataForVariablesInfo(ctx sessionctx.Context) erro
 	return nil
 }
 
+func (e *memtableRetriever) setDataForCardinalityCorrections(ctx sessionctx.Context, is infoschema.InfoSchema) {
+	statsHandle := domain.GetDomain(ctx).StatsHandle()
+	if statsHandle == nil {
+		return
+	}
+	checker := privilege.GetPrivilegeManager(ctx)
+	loc := ctx.GetSessionVars().Location()
+	corrections := statsHandle.CardCorrectionCache().Corrections()
+	rows := make([][]types.Datum, 0, len(corrections))
+	for _, item := range corrections {
+		var (
+			tbl           table.Table
+			db            *model.DBInfo
+			partition     *model.PartitionDefinition
+			ok            bool
+			partitionName interface{}
+		)
+		if tbl, ok = is.TableByID(item.TableID); ok {
+			db, ok = is.SchemaByTable(tbl.Meta())
+		} else {
+			tbl, db, partition = is.FindTableByPartitionID(item.TableID)
+			ok = tbl != nil
+		}
+		// Skip the corrections on the dropped tables.
+		if !ok {
+			continue
+		}
+		if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, db.Name.L, tbl.Meta().Name.L, "", mysql.AllPrivMask) {
+			continue
+		}
+		if partition != nil {
+			partitionName = partition.Name.O
+		}
+		rows = append(rows, types.MakeDatums(
+			db.Name.O,          // TABLE_SCHEMA
+			tbl.Meta().Name.O,  // TABLE_NAME
+			partitionName,      // PARTITION_NAME
+			item.TableID,       // TABLE_ID
+			item.Predicates,    // PREDICATES
+			item.StatsVersion,  // STATS_VERSION
+			item.EstRows,       // ESTIMATED_ROWS
+			item.ActRows,       // ACTUAL_ROWS
+			item.Ratio,         // CORRECTION_RATIO
+			item.FeedbackCount, // FEEDBACK_COUNT
+			types.NewTime(types.FromGoTime(item.LastUpdateTime.In(loc)), mysql.TypeDatetime, 0), // LAST_UPDATE_TIME
+		))
+	}
+	e.rows = rows
+}
+
 func (e *memtableRetriever) setDataFromSchemata(ctx sessionctx.Context, schemas []*model.DBInfo) {
 	checker := privilege.GetPrivilegeManager(ctx)
 	rows := make([][]types.Datum, 0, len(schemas))
@@ -492,6 +550,12 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, ta
 				visible = "NO"
 			}
 
+			var collation interface{} = "A"
+			indexType := "BTREE"
+			if index.Tp == model.IndexTypeFulltext {
+				collation, indexType = nil, index.Tp.String()
+			}
+
 			colName := col.Name.O
 			var expression interface{}
 			expression = nil
@@ -510,12 +574,12 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, ta
 				index.Name.O,          // INDEX_NAME
 				i+1,                   // SEQ_IN_INDEX
 				colName,               // COLUMN_NAME
-				"A",                   // COLLATION
+				collation,             // COLLATION
 				0,                     // CARDINALITY
 				nil,                   // SUB_PART
 				nil,                   // PACKED
 				nullable,              // NULLABLE
-				"BTREE",               // INDEX_TYPE
+				indexType,             // INDEX_TYPE
 				"",                    // COMMENT
 				index.Comment,         // INDEX_COMMENT
 				visible,               // IS_VISIBLE
@@ -2124,6 +2188,166 @@ func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas
 	e.rows = rows
 }
 
+func (e *memtableRetriever) setDataFromRoutines(ctx context.Context, sctx sessionctx.Context) error {
+	routines, err := LoadRoutines(ctx, sctx, "")
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(routines))
+	for _, r := range routines {
+		if !routineVisible(sctx, r) {
+			continue
+		}
+		var dataType, dtdIdentifier interface{} = "", nil
+		if r.Type == ast.RoutineFunction {
+			dtdIdentifier = strings.ToLower(r.Returns)
+			dataType = strings.ToLower(strings.FieldsFunc(r.Returns, func(c rune) bool { return c == '(' || c == ' ' })[0])
+		}
+		created, lastAltered := r.Created, r.LastAltered
+		created.SetType(mysql.TypeDatetime)
+		lastAltered.SetType(mysql.TypeDatetime)
+		record := types.MakeDatums(
+			r.Name,                // SPECIFIC_NAME
+			infoschema.CatalogVal, // ROUTINE_CATALOG
+			r.DB,                  // ROUTINE_SCHEMA
+			r.Name,                // ROUTINE_NAME
+			r.Type.String(),       // ROUTINE_TYPE
+			dataType,              // DATA_TYPE
+			nil,                   // CHARACTER_MAXIMUM_LENGTH
+			nil,                   // CHARACTER_OCTET_LENGTH
+			nil,                   // NUMERIC_PRECISION
+			nil,                   // NUMERIC_SCALE
+			nil,                   // DATETIME_PRECISION
+			nil,                   // CHARACTER_SET_NAME
+			nil,                   // COLLATION_NAME
+			dtdIdentifier,         // DTD_IDENTIFIER
+			"SQL",                 // ROUTINE_BODY
+			r.Body,                // ROUTINE_DEFINITION
+			nil,                   // EXTERNAL_NAME
+			"SQL",                 // EXTERNAL_LANGUAGE
+			"SQL",                 // PARAMETER_STYLE
+			r.IsDeterministic,     // IS_DETERMINISTIC
+			r.SQLDataAccess,       // SQL_DATA_ACCESS
+			nil,                   // SQL_PATH
+			r.SecurityType,        // SECURITY_TYPE
+			created,               // CREATED
+			lastAltered,           // LAST_ALTERED
+			r.SQLMode,             // SQL_MODE
+			r.Comment,             // ROUTINE_COMMENT
+			r.Definer,             // DEFINER
+			r.CharsetClient,       // CHARACTER_SET_CLIENT
+			r.CollationConnection, // COLLATION_CONNECTION
+			r.DBCollation,         // DATABASE_COLLATION
+		)
+		rows = append(rows, record)
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataFromEvents(ctx context.Context, sctx sessionctx.Context) error {
+	events, err := domain.LoadEvents(ctx, sctx, "")
+	if err != nil {
+		return err
+	}
+	loc := sctx.GetSessionVars().Location()
+	rows := make([][]types.Datum, 0, len(events))
+	for _, event := range events {
+		if !eventVisible(sctx, event) {
+			continue
+		}
+		tp, intervalValue, intervalField := "ONE TIME", interface{}(nil), interface{}(nil)
+		if event.IsRecurring() {
+			tp, intervalValue, intervalField = "RECURRING", event.IntervalValue, event.IntervalField
+		}
+		onCompletion := "NOT PRESERVE"
+		if event.Preserve {
+			onCompletion = "PRESERVE"
+		}
+		var lastError interface{}
+		if event.LastError != "" {
+			lastError = event.LastError
+		}
+		record := types.MakeDatums(
+			infoschema.CatalogVal,                  // EVENT_CATALOG
+			event.DB,                               // EVENT_SCHEMA
+			event.Name,                             // EVENT_NAME
+			event.Definer,                          // DEFINER
+			event.TimeZone,                         // TIME_ZONE
+			"SQL",                                  // EVENT_BODY
+			event.Body,                             // EVENT_DEFINITION
+			tp,                                     // EVENT_TYPE
+			eventShowTime(event.ExecuteAt, loc),    // EXECUTE_AT
+			intervalValue,                          // INTERVAL_VALUE
+			intervalField,                          // INTERVAL_FIELD
+			event.SQLMode,                          // SQL_MODE
+			eventShowTime(event.Starts, loc),       // STARTS
+			eventShowTime(event.Ends, loc),         // ENDS
+			event.Status,                           // STATUS
+			onCompletion,                           // ON_COMPLETION
+			eventShowTime(event.Created, loc),      // CREATED
+			eventShowTime(event.LastAltered, loc),  // LAST_ALTERED
+			eventShowTime(event.LastExecuted, loc), // LAST_EXECUTED
+			event.Comment,                          // EVENT_COMMENT
+			event.Originator,                       // ORIGINATOR
+			event.CharsetClient,                    // CHARACTER_SET_CLIENT
+			event.CollationConnection,              // COLLATION_CONNECTION
+			event.DBCollation,                      // DATABASE_COLLATION
+			event.Executions,                       // TIDB_EXECUTION_COUNT
+			event.Failures,                         // TIDB_FAILURE_COUNT
+			lastError,                              // TIDB_LAST_ERROR
+		)
+		rows = append(rows, record)
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataFromTriggers(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if len(table.Triggers) == 0 || !triggerVisible(ctx, schema.Name.L, table.Name.L) {
+				continue
+			}
+			// ACTION_ORDER is the position of the trigger in the triggers of the same action time and event.
+			orders := make(map[[2]int]int)
+			for _, trigger := range table.Triggers {
+				key := [2]int{int(trigger.Time), int(trigger.Event)}
+				orders[key]++
+				created := triggerCreated(ctx, trigger)
+				created.SetType(mysql.TypeDatetime)
+				record := types.MakeDatums(
+					infoschema.CatalogVal,       // TRIGGER_CATALOG
+					schema.Name.O,               // TRIGGER_SCHEMA
+					trigger.Name.O,              // TRIGGER_NAME
+					trigger.Event.String(),      // EVENT_MANIPULATION
+					infoschema.CatalogVal,       // EVENT_OBJECT_CATALOG
+					schema.Name.O,               // EVENT_OBJECT_SCHEMA
+					table.Name.O,                // EVENT_OBJECT_TABLE
+					orders[key],                 // ACTION_ORDER
+					nil,                         // ACTION_CONDITION
+					trigger.Body,                // ACTION_STATEMENT
+					"ROW",                       // ACTION_ORIENTATION
+					trigger.Time.String(),       // ACTION_TIMING
+					nil,                         // ACTION_REFERENCE_OLD_TABLE
+					nil,                         // ACTION_REFERENCE_NEW_TABLE
+					"OLD",                       // ACTION_REFERENCE_OLD_ROW
+					"NEW",                       // ACTION_REFERENCE_NEW_ROW
+					created,                     // CREATED
+					trigger.SQLMode,             // SQL_MODE
+					triggerDefiner(trigger),     // DEFINER
+					trigger.CharsetClient,       // CHARACTER_SET_CLIENT
+					trigger.CollationConnection, // COLLATION_CONNECTION
+					trigger.DBCollation,         // DATABASE_COLLATION
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
 // dataForTableTiFlashReplica constructs data for table tiflash replica info.
 func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, schemas []*model.DBInfo) {
 	var rows [][]types.Datum
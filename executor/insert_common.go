@@ -644,6 +644,19 @@ func (e *InsertValues) fillRow(ctx context.Context, row []types.Datum, hasValue
 		}
 	}
 	tbl := e.Table.Meta()
+	if len(tbl.Triggers) > 0 {
+		if err := execTriggers(ctx, e.ctx, e.Table, model.TriggerBefore, model.TriggerInsert, nil, row); err != nil {
+			return nil, err
+		}
+		// The triggers may set the columns to NULL.
+		for i, c := range tCols {
+			if !c.IsGenerated() && (!e.lazyFillAutoID || !mysql.HasAutoIncrementFlag(c.GetFlag())) {
+				if err := c.HandleBadNull(&row[i], e.ctx.GetSessionVars().StmtCtx); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
 	// Handle exchange partition
 	if tbl.ExchangePartitionInfo != nil && tbl.ExchangePartitionInfo.ExchangePartitionFlag {
 		is := e.ctx.GetDomainInfoSchema().(infoschema.InfoSchema)
@@ -1191,13 +1204,16 @@ func (e *InsertValues) removeRow(ctx context.Context, txn kv.Transaction, r toBe
 		return nil
 	}
 
+	if err = execTriggers(ctx, e.ctx, r.t, model.TriggerBefore, model.TriggerDelete, oldRow, nil); err != nil {
+		return err
+	}
 	err = r.t.RemoveRecord(e.ctx, handle, oldRow)
 	if err != nil {
 		return err
 	}
 	e.ctx.GetSessionVars().StmtCtx.AddDeletedRows(1)
 
-	return nil
+	return execTriggers(ctx, e.ctx, r.t, model.TriggerAfter, model.TriggerDelete, oldRow, nil)
 }
 
 // equalDatumsAsBinary compare if a and b contains the same datum values in binary collation.
@@ -1239,7 +1255,7 @@ func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.
 	if e.lastInsertID != 0 {
 		vars.SetLastInsertID(e.lastInsertID)
 	}
-	return nil
+	return execTriggers(ctx, e.ctx, e.Table, model.TriggerAfter, model.TriggerInsert, nil, row)
 }
 
 // InsertRuntimeStat record the stat about insert and check
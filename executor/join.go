@@ -98,6 +98,9 @@ type HashJoinExec struct {
 	// We pre-alloc and reuse the Rows and RowPtrs for each probe goroutine, to avoid allocation frequently
 	buildSideRows    [][]chunk.Row
 	buildSideRowPtrs [][]chunk.RowPtr
+
+	// runtimeFilters are built with the build side keys, and applied by the table readers of the probe side.
+	runtimeFilters []*runtimeFilter
 }
 
 // probeChkResource stores the result of the join probe side fetch worker,
@@ -172,6 +175,7 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 
 	e.diskTracker = disk.NewTracker(e.id, -1)
 	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.resetRuntimeFilters()
 
 	e.closeCh = make(chan struct{})
 	e.finished.Store(false)
@@ -735,6 +739,14 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) {
 			e.stats.fetchAndBuildHashTable = time.Since(start)
 		}()
 	}
+	built := false
+	if len(e.runtimeFilters) > 0 {
+		// The probe side table readers are blocked until the runtime filters are published, so they are published
+		// even if building the hash table fails or panics.
+		defer func() {
+			e.publishRuntimeFilters(built)
+		}()
+	}
 	// buildSideResultCh transfers build side chunk from build side fetch to build hash table.
 	buildSideResultCh := make(chan *chunk.Chunk, 1)
 	doneCh := make(chan struct{})
@@ -769,6 +781,7 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) {
 			e.buildFinished <- err
 		}
 	}
+	built = err == nil && !e.finished.Load().(bool)
 }
 
 // buildHashTableForList builds hash table from `list`.
@@ -812,6 +825,9 @@ func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chu
 		if err != nil {
 			return err
 		}
+		if err = e.insertRuntimeFilters(chk, selected); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -857,6 +873,11 @@ type NestedLoopApplyExec struct {
 func (e *NestedLoopApplyExec) Close() error {
 	e.innerRows = nil
 	e.memTracker = nil
+	var cacheErr error
+	if e.cache != nil {
+		cacheErr = e.cache.Close()
+		e.cache = nil
+	}
 	if e.runtimeStats != nil {
 		runtimeStats := newJoinRuntimeStats()
 		e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl.RegisterStats(e.id, runtimeStats)
@@ -871,7 +892,10 @@ func (e *NestedLoopApplyExec) Close() error {
 		}
 		runtimeStats.SetConcurrencyInfo(execdetails.NewConcurrencyInfo("Concurrency", 0))
 	}
-	return e.outerExec.Close()
+	if err := e.outerExec.Close(); err != nil {
+		return err
+	}
+	return cacheErr
 }
 
 // Open implements the Executor interface.
@@ -900,6 +924,10 @@ func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
 		e.cacheHitCounter = 0
 		e.cacheAccessCounter = 0
 		e.cache.GetMemTracker().AttachTo(e.memTracker)
+		e.cache.GetDiskTracker().AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+		if config.GetGlobalConfig().OOMUseTmpStorage {
+			e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.cache.ActionSpill())
+		}
 	}
 	return nil
 }
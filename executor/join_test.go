@@ -2744,6 +2744,12 @@ func TestIssue25902(t *testing.T) {
 }
 
 func TestIssue30211(t *testing.T) {
+	// The query is expected to be canceled by the memory quota, so the index join is not allowed to spill its inner
+	// results to the disk, which would keep the memory usage under the quota.
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = false
+	})
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
 	tk := testkit.NewTestKit(t, store)
@@ -2869,3 +2875,110 @@ func TestOuterJoin(t *testing.T) {
 		),
 	)
 }
+
+func TestHashJoinRuntimeFilter(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fact, dim")
+	tk.MustExec("create table fact(id int primary key, dim_id int, v int)")
+	tk.MustExec("create table dim(id int primary key, name varchar(20))")
+	var factValues, dimValues []string
+	for i := 0; i < 4000; i++ {
+		factValues = append(factValues, fmt.Sprintf("(%d, %d, %d)", i, i%2000, i))
+		if i%2 == 0 {
+			factValues[i] = fmt.Sprintf("(%d, null, %d)", i, i)
+		}
+	}
+	for i := 0; i < 2000; i++ {
+		dimValues = append(dimValues, fmt.Sprintf("(%d, 'n%d')", i, i))
+	}
+	tk.MustExec("insert into fact values " + strings.Join(factValues, ","))
+	tk.MustExec("insert into dim values " + strings.Join(dimValues, ","))
+	tk.MustExec("analyze table fact, dim")
+
+	tests := []struct {
+		sql        string
+		filterInfo string
+		bloom      bool
+	}{
+		// The distinct keys are pushed down as an IN list.
+		{"select fact.id, dim.name from fact join dim on fact.dim_id = dim.id where dim.name in ('n1', 'n5', 'n1001')", "rf0:{in:3}", false},
+		{"select fact.id from fact where fact.dim_id in (select id from dim where name in ('n3', 'n7')) and fact.v > 10", "rf0:{in:2}", false},
+		// The min/max values are pushed down, and the bloom filter is applied after the rows are read.
+		{"select fact.id, dim.name from fact join dim on fact.dim_id = dim.id where dim.name like 'n1%'", "rf0:{min_max bloom}", true},
+		// No row is read if the build side is empty.
+		{"select fact.id, dim.name from fact join dim on fact.dim_id = dim.id where dim.name = 'none'", "rf0:{empty}", false},
+	}
+	for _, test := range tests {
+		tk.MustExec("set @@tidb_enable_runtime_filter = 0")
+		expected := tk.MustQuery(test.sql).Sort().Rows()
+		require.NotContains(t, fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' "+test.sql).Rows()), "runtime filter")
+		tk.MustExec("set @@tidb_enable_runtime_filter = 1")
+		tk.MustQuery(test.sql).Sort().Check(expected)
+
+		explain := fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' "+test.sql).Rows())
+		require.Contains(t, explain, "runtime filter:[rf0(test.dim.id)]", explain)
+		require.Contains(t, explain, "runtime filter:[rf0(test.fact.dim_id)]", explain)
+		rows := tk.MustQuery("explain analyze " + test.sql).Rows()
+		found := false
+		for _, row := range rows {
+			if strings.Contains(row[0].(string), "TableReader") && strings.Contains(row[5].(string), "runtime_filter:") {
+				require.Contains(t, row[5].(string), test.filterInfo)
+				require.Equal(t, test.bloom, !strings.Contains(row[5].(string), "bloom_filtered_rows:0}"), row[5].(string))
+				found = true
+			}
+		}
+		require.True(t, found, fmt.Sprintf("%v", rows))
+	}
+}
+
+func TestAdaptiveJoin(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, index idx(a))")
+	var values []string
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t2 values " + strings.Join(values, ","))
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("analyze table t1, t2")
+	// The stats of t1 are not updated, so the outer side has many more rows than estimated.
+	values = values[:0]
+	for i := 0; i < 3000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i%1200, i))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values, ","))
+
+	for _, sql := range []string{
+		"select t1.b, t2.b from t1 join t2 on t1.a = t2.a where t1.b < 2800",
+		"select t1.b, t2.b from t1 left join t2 on t1.a = t2.a and t2.b > 100",
+		"select t1.b from t1 where exists (select 1 from t2 where t2.a = t1.a and t2.b > 500)",
+		"select t1.b from t1 where not exists (select 1 from t2 where t2.a = t1.a and t2.b > 10)",
+	} {
+		tk.MustExec("set @@tidb_enable_adaptive_join = 0")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		tk.MustExec("set @@tidb_enable_adaptive_join = 1")
+		tk.MustQuery(sql).Sort().Check(expected)
+
+		explain := fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' "+sql).Rows())
+		require.Contains(t, explain, "adaptive threshold:", explain)
+		// The inner plan of the hash join which the index join switches to is shown.
+		require.Contains(t, explain, "(Adaptive Build)", explain)
+		rows := tk.MustQuery("explain analyze " + sql).Rows()
+		require.Contains(t, rows[0][5].(string), "strategy:index_join->hash_join", fmt.Sprintf("%v", rows))
+		require.Equal(t, fmt.Sprintf("%d", len(expected)), rows[0][2], fmt.Sprintf("%v", rows))
+	}
+
+	// The index join doesn't switch if the outer side has as few rows as estimated.
+	sql := "select t1.b, t2.b from t1 join t2 on t1.a = t2.a where t1.b < 2"
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("0 0", "1 1", "1 1"))
+	rows := tk.MustQuery("explain analyze " + sql).Rows()
+	require.Contains(t, rows[0][5].(string), "strategy:index_join}", fmt.Sprintf("%v", rows))
+}
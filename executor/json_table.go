@@ -0,0 +1,184 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// JSONTableExec extracts the rows from the JSON document for the JSON_TABLE. It's the inner child of an Apply if
+// the document refers to the preceding tables, so the document is evaluated every time the executor is opened.
+type JSONTableExec struct {
+	baseExecutor
+
+	expr expression.Expression
+	path *plannercore.JSONTablePath
+
+	// sc is used to convert the JSON values to the column types, the conversion errors are not ignored in it and
+	// are handled by the ON ERROR clauses.
+	sc *stmtctx.StatementContext
+	// rows are all the rows extracted from the document.
+	rows   [][]types.Datum
+	cursor int
+}
+
+// Open implements the Executor Open interface.
+func (e *JSONTableExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.sc = &stmtctx.StatementContext{TimeZone: e.ctx.GetSessionVars().Location()}
+	e.rows, e.cursor = e.rows[:0], 0
+	doc, isNull, err := e.expr.EvalJSON(e.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return err
+	}
+	return e.appendRows(e.path, doc, make([]types.Datum, e.schema.Len()))
+}
+
+// Next implements the Executor Next interface.
+func (e *JSONTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	for ; e.cursor < len(e.rows) && !req.IsFull(); e.cursor++ {
+		for i, d := range e.rows[e.cursor] {
+			req.AppendDatum(i, &d)
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *JSONTableExec) Close() error {
+	e.rows = nil
+	return e.baseExecutor.Close()
+}
+
+// appendRows appends the rows of the values matched by the path in the document. A row is the copy of the parent
+// row with the columns of the path filled, and it's joined with the rows of every nested path in turn, or it's
+// appended as it is if there is no row for all the nested paths.
+func (e *JSONTableExec) appendRows(path *plannercore.JSONTablePath, doc json.BinaryJSON, parent []types.Datum) error {
+	for i, value := range doc.ExtractAll(path.PathExpr) {
+		row := make([]types.Datum, len(parent))
+		copy(row, parent)
+		for _, col := range path.Columns {
+			d, err := e.evalColumn(col, value, i+1)
+			if err != nil {
+				return err
+			}
+			row[col.Idx] = d
+		}
+		numRows := len(e.rows)
+		for _, nested := range path.Nested {
+			if err := e.appendRows(nested, value, row); err != nil {
+				return err
+			}
+		}
+		if len(e.rows) == numRows {
+			e.rows = append(e.rows, row)
+		}
+	}
+	return nil
+}
+
+func (e *JSONTableExec) evalColumn(col *plannercore.JSONTableColumn, value json.BinaryJSON, ordinality int) (types.Datum, error) {
+	ft := e.schema.Columns[col.Idx].RetType
+	switch col.Tp {
+	case ast.JSONTableColumnOrdinality:
+		return types.NewUintDatum(uint64(ordinality)), nil
+	case ast.JSONTableColumnExists:
+		d := types.NewIntDatum(0)
+		if len(value.ExtractAll(col.PathExpr)) > 0 {
+			d.SetInt64(1)
+		}
+		return d.ConvertTo(e.sc, ft)
+	}
+	name := col.Name.O
+	values := value.ExtractAll(col.PathExpr)
+	switch len(values) {
+	case 0:
+		return e.evalOnResponse(col, ft, ErrMissingJSONTableValue.GenWithStackByArgs(name), false)
+	case 1:
+		d, err := convertJSONTableValue(e.sc, values[0], ft, name)
+		if err != nil {
+			return e.evalOnResponse(col, ft, err, true)
+		}
+		return d, nil
+	default:
+		return e.evalOnResponse(col, ft, ErrWrongJSONTableValue.GenWithStackByArgs(name), true)
+	}
+}
+
+// evalOnResponse returns the value of the column by the ON EMPTY clause, or the ON ERROR clause if onError is true.
+// The error is reported as a warning for NULL ON ERROR.
+func (e *JSONTableExec) evalOnResponse(col *plannercore.JSONTableColumn, ft *types.FieldType, err error, onError bool) (types.Datum, error) {
+	response := col.OnEmpty
+	if onError {
+		response = col.OnError
+	}
+	switch response.Tp {
+	case ast.JSONTableOnResponseError:
+		return types.Datum{}, err
+	case ast.JSONTableOnResponseDefault:
+		return convertJSONTableValue(e.sc, response.Default, ft, col.Name.O)
+	}
+	if onError {
+		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+	}
+	return types.Datum{}, nil
+}
+
+// convertJSONTableValue converts the JSON value to the column type, the arrays and objects can only be stored in
+// the JSON columns.
+func convertJSONTableValue(sc *stmtctx.StatementContext, value json.BinaryJSON, ft *types.FieldType, name string) (types.Datum, error) {
+	if ft.GetType() == mysql.TypeJSON {
+		return types.NewJSONDatum(value), nil
+	}
+	var d types.Datum
+	switch value.TypeCode {
+	case json.TypeCodeObject, json.TypeCodeArray:
+		return d, ErrWrongJSONTableValue.GenWithStackByArgs(name)
+	case json.TypeCodeLiteral:
+		switch value.Value[0] {
+		case json.LiteralNil:
+			return d, nil
+		case json.LiteralTrue:
+			d.SetInt64(1)
+		default:
+			d.SetInt64(0)
+		}
+	case json.TypeCodeInt64:
+		d.SetInt64(value.GetInt64())
+	case json.TypeCodeUint64:
+		d.SetUint64(value.GetUint64())
+	case json.TypeCodeFloat64:
+		d.SetFloat64(value.GetFloat64())
+	default:
+		s, err := value.Unquote()
+		if err != nil {
+			return d, err
+		}
+		d.SetString(s, mysql.DefaultCollationName)
+	}
+	return d.ConvertTo(sc, ft)
+}
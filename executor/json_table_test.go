@@ -0,0 +1,103 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustQuery("select * from json_table('[1, \"2\", 3.5]', '$[*]' columns (a int path '$', b varchar(10) path '$', c json path '$')) as jt").
+		Check(testkit.Rows("1 1 1", "2 2 \"2\"", "4 3.5 3.5"))
+	tk.MustQuery("select * from json_table('{\"a\": 1}', '$' columns (id for ordinality, a int path '$.a', b int exists path '$.b', c int exists path '$.a')) jt").
+		Check(testkit.Rows("1 1 0 1"))
+	tk.MustQuery("select * from json_table(null, '$[*]' columns (a int path '$')) jt").Check(testkit.Rows())
+	tk.MustQuery("select * from json_table('[]', '$[*]' columns (a int path '$')) jt").Check(testkit.Rows())
+	tk.MustQuery("select a, count(*) from json_table('[1, 2, 1, 3, 1]', '$[*]' columns (a int path '$')) jt group by a order by a").
+		Check(testkit.Rows("1 3", "2 1", "3 1"))
+
+	// Shred the documents of the preceding table.
+	tk.MustExec("create table t (id int, doc json)")
+	tk.MustExec(`insert into t values (1, '{"items": [{"name": "a", "price": 10}, {"name": "b"}]}'), (2, '{"items": []}'), (3, null)`)
+	tk.MustQuery("select t.id, jt.* from t, json_table(t.doc, '$.items[*]' columns (seq for ordinality, name varchar(10) path '$.name', price int path '$.price')) as jt order by t.id, jt.seq").
+		Check(testkit.Rows("1 1 a 10", "1 2 b <nil>"))
+	tk.MustQuery("select t.id, jt.name from t left join json_table(t.doc, '$.items[*]' columns (name varchar(10) path '$.name')) as jt on true order by t.id, jt.name").
+		Check(testkit.Rows("1 a", "1 b", "2 <nil>", "3 <nil>"))
+	tk.MustQuery("select t.id, jt.name from t join json_table(t.doc, '$.items[*]' columns (name varchar(10) path '$.name')) as jt on jt.name = 'b'").
+		Check(testkit.Rows("1 b"))
+	tk.MustQuery("select t.id, (select count(*) from json_table(t.doc, '$.items[*]' columns (name varchar(10) path '$.name')) jt) from t order by t.id").
+		Check(testkit.Rows("1 2", "2 0", "3 0"))
+	tk.MustGetErrCode("select * from t right join json_table(t.doc, '$.items[*]' columns (name varchar(10) path '$.name')) as jt on true", errno.ErrBadField)
+	tk.MustGetErrCode("select * from json_table(t.doc, '$.items[*]' columns (name varchar(10) path '$.name')) as jt, t", errno.ErrBadField)
+	tk.MustGetErrCode("select * from json_table('[]', '$[*]' columns (a int path '$', a int path '$')) as jt", errno.ErrDupFieldName)
+	tk.MustGetErrCode("select * from json_table('[]', '$[*' columns (a int path '$')) as jt", errno.ErrInvalidJSONPath)
+}
+
+func TestJSONTableNestedPath(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	doc := `'[{"a": 1, "b": [11, 12], "c": [{"d": "x"}]}, {"a": 2, "b": [], "c": [{"d": "y"}, {"d": "z"}]}, {"a": 3}]'`
+
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a int path '$', nested path '$.b[*]' columns (b int path '$'))) jt").
+		Check(testkit.Rows("<nil> 11", "<nil> 12", "<nil> <nil>", "<nil> <nil>"))
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a int path '$.a', nested path '$.b[*]' columns (id for ordinality, b int path '$'))) jt").
+		Check(testkit.Rows("1 1 11", "1 2 12", "2 <nil> <nil>", "3 <nil> <nil>"))
+	// The rows of the sibling nested paths are not joined with each other.
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a int path '$.a', nested path '$.b[*]' columns (b int path '$'), nested path '$.c[*]' columns (d varchar(10) path '$.d'))) jt").
+		Check(testkit.Rows("1 11 <nil>", "1 12 <nil>", "1 <nil> x", "2 <nil> y", "2 <nil> z", "3 <nil> <nil>"))
+	tk.MustQuery(`select * from json_table('{"a": [{"b": [1, 2]}, {"b": [3]}]}', '$' columns (nested path '$.a[*]' columns (x for ordinality, nested path '$.b[*]' columns (y for ordinality, b int path '$')))) jt`).
+		Check(testkit.Rows("1 1 1", "1 2 2", "2 1 3"))
+}
+
+func TestJSONTableOnEmptyOnError(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	doc := `'[{"a": 1}, {"a": "x"}, {"a": [1, 2]}, {}]'`
+
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a int path '$.a')) jt").
+		Check(testkit.Rows("1", "<nil>", "<nil>", "<nil>"))
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.GetWarnings(), 2)
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a int path '$.a' default '100' on empty default '200' on error)) jt").
+		Check(testkit.Rows("1", "200", "200", "100"))
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a json path '$.a' error on error)) jt").
+		Check(testkit.Rows("1", "\"x\"", "[1, 2]", "<nil>"))
+	tk.MustQuery("select * from json_table(" + doc + ", '$[*]' columns (a varchar(10) path '$.a' null on error)) jt").
+		Check(testkit.Rows("1", "x", "<nil>", "<nil>"))
+	tk.MustQuery("select * from json_table('[[1], [2, 3]]', '$[*]' columns (a int path '$[*]' null on error)) jt").
+		Check(testkit.Rows("1", "<nil>"))
+
+	tk.MustContainErrMsg("select * from json_table("+doc+", '$[*]' columns (a int path '$.a' error on empty)) jt",
+		"Missing value for JSON_TABLE column 'a'")
+	tk.MustContainErrMsg("select * from json_table("+doc+", '$[*]' columns (a int path '$.a' null on empty error on error)) jt",
+		"Truncated incorrect")
+	tk.MustContainErrMsg(`select * from json_table('[{"a": [1]}]', '$[*]' columns (a int path '$.a' error on error)) jt`,
+		"Can't store an array or an object in the scalar column 'a' of JSON_TABLE")
+	tk.MustGetErrCode("select * from json_table('[]', '$[*]' columns (a int path '$.a' default '{' on empty)) jt", errno.ErrInvalidJSONText)
+	tk.MustGetErrCode("select * from json_table('{', '$[*]' columns (a int path '$.a')) jt", errno.ErrInvalidJSONText)
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// RefreshMaterializedViewExec represents a refresh materialized view executor.
+type RefreshMaterializedViewExec struct {
+	baseExecutor
+
+	dbName  model.CIStr
+	tblInfo *model.TableInfo
+	method  ast.RefreshMethod
+	done    bool
+}
+
+// Next implements the Executor Next interface.
+func (e *RefreshMaterializedViewExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.done {
+		return nil
+	}
+	e.done = true
+	return e.refreshMaterializedView(ctx, e.dbName, e.tblInfo, e.method)
+}
+
+// refreshMaterializedView refreshes the materialized view in a new transaction, so the data of the view and its log
+// are changed atomically. The view is refreshed incrementally with the changes in the log if it's supported and the
+// log is complete, otherwise the view is computed from scratch.
+func (e *baseExecutor) refreshMaterializedView(ctx context.Context, dbName model.CIStr, tblInfo *model.TableInfo, method ast.RefreshMethod) (err error) {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnMaterializedView)
+	se, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(ctx, se)
+
+	if _, err = se.(sqlexec.SQLExecutor).ExecuteInternal(ctx, "BEGIN OPTIMISTIC"); err != nil {
+		return err
+	}
+	mvInfo := tblInfo.MaterializedView
+	var logName model.CIStr
+	if logTbl, ok := domain.GetDomain(e.ctx).InfoSchema().TableByID(mvInfo.LogTableID); ok {
+		logName = logTbl.Meta().Name
+	}
+	fast := false
+	if mvInfo.FastRefresh != nil && logName.L != "" && method != ast.RefreshMethodComplete {
+		// The log is incomplete before the view is refreshed completely for the first time.
+		rows, err := execMaterializedViewSQL(ctx, se, "SELECT 1 FROM %n.%n WHERE %n = 0 LIMIT 1",
+			dbName.O, logName.O, model.MaterializedViewLogOpName.O)
+		if err != nil {
+			return err
+		}
+		fast = len(rows) == 0
+	}
+	if fast {
+		err = refreshMaterializedViewFast(ctx, se, dbName, tblInfo)
+	} else {
+		err = refreshMaterializedViewComplete(ctx, se, dbName, tblInfo)
+	}
+	if err == nil && logName.L != "" {
+		_, err = execMaterializedViewSQL(ctx, se, "DELETE FROM %n.%n", dbName.O, logName.O)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = se.(sqlexec.SQLExecutor).ExecuteInternal(ctx, "COMMIT")
+	return err
+}
+
+func refreshMaterializedViewComplete(ctx context.Context, se sessionctx.Context, dbName model.CIStr, tblInfo *model.TableInfo) error {
+	if _, err := execMaterializedViewSQL(ctx, se, "DELETE FROM %n.%n", dbName.O, tblInfo.Name.O); err != nil {
+		return err
+	}
+	insert, err := sqlexec.EscapeSQL("INSERT INTO %n.%n ", dbName.O, tblInfo.Name.O)
+	if err != nil {
+		return err
+	}
+	// The definition is not escaped, it has no parameters.
+	_, err = execMaterializedViewSQL(ctx, se, insert+tblInfo.MaterializedView.SelectStmt)
+	return err
+}
+
+// refreshMaterializedViewFast applies the aggregated changes in the log to the view. The groups not in the view are
+// inserted, and the groups with no rows left are deleted.
+func refreshMaterializedViewFast(ctx context.Context, se sessionctx.Context, dbName model.CIStr, tblInfo *model.TableInfo) error {
+	fastInfo := tblInfo.MaterializedView.FastRefresh
+	deltas, fields, err := se.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx,
+		[]sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession}, fastInfo.DeltaSelectStmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	isGroupBy := make([]bool, len(tblInfo.Columns))
+	for _, offset := range fastInfo.GroupByOffsets {
+		isGroupBy[offset] = true
+	}
+	for _, delta := range deltas {
+		values := make([]interface{}, len(tblInfo.Columns))
+		for i, field := range fields {
+			d := delta.GetDatum(i, &field.Column.FieldType)
+			if d.IsNull() {
+				continue
+			}
+			if values[i], err = d.ToString(); err != nil {
+				return err
+			}
+		}
+
+		var sql strings.Builder
+		args := []interface{}{dbName.O, tblInfo.Name.O}
+		sql.WriteString("UPDATE %n.%n SET ")
+		for i, col := range tblInfo.Columns {
+			if isGroupBy[i] {
+				continue
+			}
+			if len(args) > 2 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("%n = %n + %?")
+			args = append(args, col.Name.O, col.Name.O, values[i])
+		}
+		sql.WriteString(" WHERE ")
+		for i, offset := range fastInfo.GroupByOffsets {
+			if i > 0 {
+				sql.WriteString(" AND ")
+			}
+			sql.WriteString("%n <=> %?")
+			args = append(args, tblInfo.Columns[offset].Name.O, values[offset])
+		}
+		if _, err = execMaterializedViewSQL(ctx, se, sql.String(), args...); err != nil {
+			return err
+		}
+		if se.GetSessionVars().StmtCtx.AffectedRows() > 0 {
+			continue
+		}
+		// The group is not in the view.
+		sql.Reset()
+		sql.WriteString("INSERT INTO %n.%n VALUES (")
+		for i := range values {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("%?")
+		}
+		sql.WriteString(")")
+		if _, err = execMaterializedViewSQL(ctx, se, sql.String(), append([]interface{}{dbName.O, tblInfo.Name.O}, values...)...); err != nil {
+			return err
+		}
+	}
+	_, err = execMaterializedViewSQL(ctx, se, "DELETE FROM %n.%n WHERE %n = 0", dbName.O, tblInfo.Name.O,
+		tblInfo.Columns[fastInfo.CountOffset].Name.O)
+	return err
+}
+
+func execMaterializedViewSQL(ctx context.Context, se sessionctx.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	rows, _, err := se.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession}, sql, args...)
+	return rows, errors.Trace(err)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterializedViewRefresh(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int not null, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, null), (2, 3, 3)")
+	tk.MustExec("create materialized view mv as select a, count(*) as cnt, sum(b) as s, count(c) as cc from t where b < 100 group by a")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3 1", "2 1 3 1"))
+	tk.MustQuery("select count(*) from mlog$_mv").Check(testkit.Rows("0"))
+
+	// The changes are logged, and applied to the view incrementally.
+	tk.MustExec("insert into t values (3, 4, 4), (1, 5, 5)")
+	tk.MustExec("delete from t where a = 2")
+	tk.MustExec("update t set c = 6 where b = 2")
+	tk.MustQuery("select count(*) from mlog$_mv").Check(testkit.Rows("5"))
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3 1", "2 1 3 1"))
+	tk.MustExec("refresh materialized view mv fast")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 3 8 3", "3 1 4 1"))
+	tk.MustQuery("select count(*) from mlog$_mv").Check(testkit.Rows("0"))
+	tk.MustQuery("select a, count(*), sum(b), count(c) from t group by a order by a").Check(testkit.Rows("1 3 8 3", "3 1 4 1"))
+
+	tk.MustExec("insert into t values (4, 7, null)")
+	tk.MustExec("refresh materialized view mv complete")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 3 8 3", "3 1 4 1", "4 1 7 0"))
+	tk.MustQuery("select count(*) from mlog$_mv").Check(testkit.Rows("0"))
+
+	// The views which can't be refreshed incrementally are refreshed completely.
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t2 values (1, 10)")
+	tk.MustExec("create materialized view mv2 (a, b, c) as select t.a, t.b, t2.b from t join t2 on t.a = t2.a where t.b > 1")
+	tk.MustQuery("select * from mv2 order by b").Check(testkit.Rows("1 2 10", "1 5 10"))
+	tk.MustGetErrCode("refresh materialized view mv2 fast", errno.ErrNotSupportedYet)
+	tk.MustExec("insert into t2 values (3, 30)")
+	tk.MustExec("refresh materialized view mv2")
+	tk.MustQuery("select * from mv2 order by b").Check(testkit.Rows("1 2 10", "3 4 30", "1 5 10"))
+
+	tk.MustExec("drop materialized view mv2")
+	tk.MustGetErrCode("select * from mv2", errno.ErrNoSuchTable)
+	tk.MustExec("drop table t2")
+}
+
+func TestMaterializedViewRestrictions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("create materialized view mv as select a, count(*) from t group by a")
+	tk.MustExec("create materialized view if not exists mv as select a from t")
+	require.Equal(t, uint16(1), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+	tk.MustGetErrCode("create materialized view mv as select a from t", errno.ErrTableExists)
+
+	// The views and logs are only changed by refreshing.
+	tk.MustGetErrCode("insert into mv values (1, 1)", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("update mv set a = 1", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("delete from mlog$_mv", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("drop table mv", errno.ErrWrongObject)
+	tk.MustGetErrCode("truncate table mlog$_mv", errno.ErrWrongObject)
+	tk.MustGetErrCode("alter table mv add column c int", errno.ErrWrongObject)
+
+	// The base tables can't be changed in the ways that break the views.
+	tk.MustGetErrCode("drop table t", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("truncate table t", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t drop column b", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("rename table t to t1", errno.ErrUnsupportedDDLOperation)
+
+	tk.MustGetErrCode("drop materialized view t", errno.ErrWrongObject)
+	tk.MustGetErrCode("refresh materialized view t", errno.ErrWrongObject)
+	tk.MustExec("drop materialized view mv")
+	tk.MustGetErrCode("select * from mlog$_mv", errno.ErrNoSuchTable)
+	tk.MustExec("drop materialized view if exists mv")
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustExec("drop table t")
+}
+
+func TestMaterializedViewRewrite(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int not null)")
+	tk.MustExec("insert into t values (1, 1), (1, 2), (2, 3)")
+	tk.MustExec("create materialized view mv as select a, count(*), sum(b) from t group by a")
+
+	query := "select a, count(*), sum(b) from t group by a"
+	readsView := func() bool {
+		rows := tk.MustQuery("explain " + query).Rows()
+		return strings.Contains(fmt.Sprintf("%v", rows), "table:mv")
+	}
+	require.True(t, readsView())
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 2 3", "2 1 3"))
+	tk.MustQuery("select a, count(*), sum(b) from test.t group by a").Sort().Check(testkit.Rows("1 2 3", "2 1 3"))
+
+	// The view is stale after the base table is changed.
+	tk.MustExec("insert into t values (2, 4)")
+	require.False(t, readsView())
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 2 3", "2 2 7"))
+	tk.MustExec("refresh materialized view mv")
+	require.True(t, readsView())
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 2 3", "2 2 7"))
+
+	// The changes of the current transaction are not in the view.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (3, 5)")
+	require.False(t, readsView())
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 2 3", "2 2 7", "3 1 5"))
+	tk.MustExec("rollback")
+	require.True(t, readsView())
+
+	tk.MustExec("set @@tidb_enable_materialized_view_rewrite = 0")
+	require.False(t, readsView())
+	tk.MustExec("set @@tidb_enable_materialized_view_rewrite = 1")
+
+	// The view is only used when the user can read it.
+	tk.MustExec("create user 'mvu'@'%'")
+	tk.MustExec("grant select on test.t to 'mvu'@'%'")
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "mvu", Hostname: "%"}, nil, nil))
+	tk1.MustExec("use test")
+	require.NotContains(t, fmt.Sprintf("%v", tk1.MustQuery("explain "+query).Rows()), "table:mv")
+	tk1.MustQuery(query).Sort().Check(testkit.Rows("1 2 3", "2 2 7"))
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/util/chunk"
@@ -126,6 +127,10 @@ func (e *ParallelNestedLoopApplyExec) Open(ctx context.Context) error {
 			return err
 		}
 		e.cache.GetMemTracker().AttachTo(e.memTracker)
+		e.cache.GetDiskTracker().AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+		if config.GetGlobalConfig().OOMUseTmpStorage {
+			e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.cache.ActionSpill())
+		}
 	}
 	return nil
 }
@@ -173,6 +178,12 @@ func (e *ParallelNestedLoopApplyExec) Close() error {
 	// Wait all workers to finish before Close() is called.
 	// Otherwise we may got data race.
 	err := e.outerExec.Close()
+	if e.cache != nil {
+		if cacheErr := e.cache.Close(); cacheErr != nil && err == nil {
+			err = cacheErr
+		}
+		e.cache = nil
+	}
 
 	if e.runtimeStats != nil {
 		runtimeStats := newJoinRuntimeStats()
@@ -563,6 +563,32 @@ func TestApplyConcurrency(t *testing.T) {
 	}
 }
 
+func TestParallelApplyWithLateralDerivedTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 10), (1, 11), (1, 12), (2, 20), (4, 40)")
+	sql := "select t1.a, dt.b from t1 left join lateral (select t2.b from t2 where t2.a = t1.a order by t2.b limit 2) dt on true"
+	results := []string{"1 10", "1 10", "1 11", "1 11", "2 20", "2 20", "3 <nil>"}
+	tk.MustQuery(sql).Sort().Check(testkit.Rows(results...))
+
+	tk.MustExec("set tidb_enable_parallel_apply=true")
+	for _, con := range []int{2, 4} {
+		tk.MustExec(fmt.Sprintf("set tidb_executor_concurrency = %v", con))
+		checkApplyPlan(t, tk, sql, con)
+		tk.MustQuery(sql).Sort().Check(testkit.Rows(results...))
+	}
+	// The inner results of the duplicated outer rows are cached.
+	tk.MustExec("set tidb_enable_parallel_apply=false")
+	rows := tk.MustQuery("explain analyze " + sql).Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows[1]), "cache:ON, cacheHitRatio:40.000%")
+}
+
 func TestApplyCacheRatio(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -0,0 +1,305 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+// sortWorker sorts the chunks dispatched to it. It works like the serial SortExec: the chunks are added to a
+// SortedRowContainer until the container is sorted and spilled by the spill action, then the container becomes a
+// sorted partition and a new one is used. The containers of the workers are sorted and spilled concurrently.
+type sortWorker struct {
+	e           *SortExec
+	byItemsDesc []bool
+
+	rowChunks  *chunk.SortedRowContainer
+	partitions []*chunk.SortedRowContainer
+	err        error
+
+	// testSpillActions are waited before the worker exits, so the spilling in tests is finished in time.
+	testSpillActions []*chunk.SortAndSpillDiskAction
+}
+
+func (w *sortWorker) newRowChunks() {
+	e := w.e
+	w.rowChunks = chunk.NewSortedRowContainer(retTypes(e), e.maxChunkSize, w.byItemsDesc, e.keyColumns, e.keyCmpFuncs)
+	w.rowChunks.GetMemTracker().AttachTo(e.memTracker)
+	w.rowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	if !config.GetGlobalConfig().OOMUseTmpStorage {
+		return
+	}
+	w.rowChunks.GetDiskTracker().AttachTo(e.diskTracker)
+	w.rowChunks.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
+	spillAction := w.rowChunks.ActionSpill()
+	failpoint.Inject("testSortedRowContainerSpill", func(val failpoint.Value) {
+		if val.(bool) {
+			spillAction = w.rowChunks.ActionSpillForTest()
+			w.testSpillActions = append(w.testSpillActions, spillAction)
+		}
+	})
+	e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(spillAction)
+}
+
+func (w *sortWorker) run(ctx context.Context, chkCh <-chan *chunk.Chunk, wg *sync.WaitGroup) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.err = perrors.Errorf("%v", r)
+			logutil.Logger(ctx).Error("sort worker panicked", zap.Error(w.err), zap.Stack("stack"))
+			for range chkCh {
+			}
+		}
+		if w.rowChunks != nil {
+			w.partitions = append(w.partitions, w.rowChunks)
+			w.rowChunks = nil
+		}
+		for _, action := range w.testSpillActions {
+			action.WaitForTest()
+		}
+		wg.Done()
+	}()
+	w.newRowChunks()
+	// The channel is drained after an error occurs, so the fetcher is never blocked.
+	for chk := range chkCh {
+		if w.err != nil {
+			continue
+		}
+		err := w.rowChunks.Add(chk)
+		if errors.Is(err, chunk.ErrCannotAddBecauseSorted) {
+			w.partitions = append(w.partitions, w.rowChunks)
+			w.newRowChunks()
+			err = w.rowChunks.Add(chk)
+		}
+		w.err = err
+	}
+	if w.err == nil && w.rowChunks.NumRow() > 0 {
+		w.rowChunks.Sort()
+	}
+}
+
+// fetchRowChunksParallel reads the chunks from the child and dispatches them to the sort workers in turn, so the
+// result is the same however the workers are scheduled. The sorted partitions of all the workers are merged later.
+func (e *SortExec) fetchRowChunksParallel(ctx context.Context) error {
+	byItemsDesc := make([]bool, len(e.ByItems))
+	for i, byItem := range e.ByItems {
+		byItemsDesc[i] = byItem.Desc
+	}
+	// The workers are started when the first chunks are dispatched to them, so a small sort needs few goroutines.
+	workers := make([]*sortWorker, 0, e.concurrency)
+	chkChs := make([]chan *chunk.Chunk, 0, e.concurrency)
+	wg := &sync.WaitGroup{}
+	var err error
+	for i := 0; ; i = (i + 1) % e.concurrency {
+		chk := newFirstChunk(e.children[0])
+		if err = Next(ctx, e.children[0], chk); err != nil || chk.NumRows() == 0 {
+			break
+		}
+		if i == len(workers) {
+			w := &sortWorker{e: e, byItemsDesc: byItemsDesc}
+			workers = append(workers, w)
+			chkChs = append(chkChs, make(chan *chunk.Chunk, 1))
+			wg.Add(1)
+			go w.run(ctx, chkChs[i], wg)
+		}
+		chkChs[i] <- chk
+	}
+	for _, ch := range chkChs {
+		close(ch)
+	}
+	wg.Wait()
+
+	// All the containers are kept in partitionList to be closed, even if an error occurs.
+	for _, w := range workers {
+		for _, p := range w.partitions {
+			if p.NumRow() > 0 || err != nil || w.err != nil {
+				e.partitionList = append(e.partitionList, p)
+			} else if closeErr := p.Close(); closeErr != nil {
+				err = closeErr
+			}
+		}
+		if err == nil {
+			err = w.err
+		}
+	}
+	return err
+}
+
+// sortMergeWorker merges a group of the sorted partitions, and sends the merged rows to the final merge in chunks.
+type sortMergeWorker struct {
+	e          *SortExec
+	partitions []*chunk.SortedRowContainer
+	resultCh   chan *chunk.Chunk
+	err        error
+}
+
+func (w *sortMergeWorker) run(ctx context.Context, finishCh <-chan struct{}, wg *sync.WaitGroup) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.err = perrors.Errorf("%v", r)
+			logutil.Logger(ctx).Error("sort merge worker panicked", zap.Error(w.err), zap.Stack("stack"))
+		}
+		close(w.resultCh)
+		wg.Done()
+	}()
+	merge := &multiWayMerge{w.e.lessRow, make([]partitionPointer, 0, len(w.partitions))}
+	for i, p := range w.partitions {
+		row, err := p.GetSortedRow(0)
+		if err != nil {
+			w.err = err
+			return
+		}
+		merge.elements = append(merge.elements, partitionPointer{row: row, partitionID: i, consumed: 0})
+	}
+	heap.Init(merge)
+
+	fields := retTypes(w.e)
+	for merge.Len() > 0 {
+		chk := chunk.New(fields, w.e.maxChunkSize, w.e.maxChunkSize)
+		for !chk.IsFull() && merge.Len() > 0 {
+			ptr := merge.elements[0]
+			chk.AppendRow(ptr.row)
+			ptr.consumed++
+			if ptr.consumed >= w.partitions[ptr.partitionID].NumRow() {
+				heap.Remove(merge, 0)
+				continue
+			}
+			var err error
+			if ptr.row, err = w.partitions[ptr.partitionID].GetSortedRow(ptr.consumed); err != nil {
+				w.err = err
+				return
+			}
+			merge.elements[0] = ptr
+			heap.Fix(merge, 0)
+		}
+		w.e.memTracker.Consume(chk.MemoryUsage())
+		select {
+		case w.resultCh <- chk:
+		case <-finishCh:
+			w.e.memTracker.Consume(-chk.MemoryUsage())
+			return
+		}
+	}
+}
+
+// parallelMerge merges the sorted partitions in two levels. The partitions are split into groups which are merged
+// by the merge workers concurrently, then the outputs of the merge workers are merged into the result.
+type parallelMerge struct {
+	workers  []*sortMergeWorker
+	finishCh chan struct{}
+	wg       sync.WaitGroup
+
+	// curChks[i] is the chunk being merged from the i-th worker.
+	curChks []*chunk.Chunk
+	merge   *multiWayMerge
+}
+
+func (e *SortExec) startParallelMerge(ctx context.Context) {
+	m := &parallelMerge{
+		workers:  make([]*sortMergeWorker, e.concurrency),
+		finishCh: make(chan struct{}),
+		curChks:  make([]*chunk.Chunk, e.concurrency),
+	}
+	for i := range m.workers {
+		m.workers[i] = &sortMergeWorker{e: e, resultCh: make(chan *chunk.Chunk, 1)}
+	}
+	for i, p := range e.partitionList {
+		w := m.workers[i%e.concurrency]
+		w.partitions = append(w.partitions, p)
+	}
+	m.wg.Add(len(m.workers))
+	for _, w := range m.workers {
+		go w.run(ctx, m.finishCh, &m.wg)
+	}
+	e.parallelMerge = m
+}
+
+// fetchMergedChunk receives the next chunk of the i-th merge worker, it returns nil if the worker is finished.
+func (m *parallelMerge) fetchMergedChunk(e *SortExec, i int) (*chunk.Chunk, error) {
+	if chk := m.curChks[i]; chk != nil {
+		e.memTracker.Consume(-chk.MemoryUsage())
+	}
+	chk, ok := <-m.workers[i].resultCh
+	m.curChks[i] = chk
+	if !ok {
+		return nil, m.workers[i].err
+	}
+	return chk, nil
+}
+
+func (e *SortExec) parallelExternalSorting(ctx context.Context, req *chunk.Chunk) error {
+	if e.parallelMerge == nil {
+		e.startParallelMerge(ctx)
+		m := e.parallelMerge
+		m.merge = &multiWayMerge{e.lessRow, make([]partitionPointer, 0, len(m.workers))}
+		for i := range m.workers {
+			chk, err := m.fetchMergedChunk(e, i)
+			if err != nil {
+				return err
+			}
+			if chk != nil {
+				m.merge.elements = append(m.merge.elements, partitionPointer{row: chk.GetRow(0), partitionID: i, consumed: 0})
+			}
+		}
+		heap.Init(m.merge)
+	}
+
+	m := e.parallelMerge
+	for !req.IsFull() && m.merge.Len() > 0 {
+		ptr := m.merge.elements[0]
+		req.AppendRow(ptr.row)
+		ptr.consumed++
+		if ptr.consumed >= m.curChks[ptr.partitionID].NumRows() {
+			chk, err := m.fetchMergedChunk(e, ptr.partitionID)
+			if err != nil {
+				return err
+			}
+			if chk == nil {
+				heap.Remove(m.merge, 0)
+				continue
+			}
+			ptr.consumed = 0
+		}
+		ptr.row = m.curChks[ptr.partitionID].GetRow(ptr.consumed)
+		m.merge.elements[0] = ptr
+		heap.Fix(m.merge, 0)
+	}
+	return nil
+}
+
+// close stops the merge workers and waits for them to exit, so the partitions can be closed safely.
+func (m *parallelMerge) close(e *SortExec) {
+	close(m.finishCh)
+	for i, w := range m.workers {
+		for chk := range w.resultCh {
+			e.memTracker.Consume(-chk.MemoryUsage())
+		}
+		if chk := m.curChks[i]; chk != nil {
+			e.memTracker.Consume(-chk.MemoryUsage())
+			m.curChks[i] = nil
+		}
+	}
+	m.wg.Wait()
+}
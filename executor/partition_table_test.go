@@ -3615,3 +3615,66 @@ func TestIssue35181(t *testing.T) {
 	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
 	tk.MustExec(`insert into t select * from t where a=3000`)
 }
+
+func TestPartitionWiseJoinAndAgg(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("create table th1(a int, b int, key(a)) partition by hash(a) partitions 4")
+	tk.MustExec("create table th2(a int, b int, key(a)) partition by hash(a) partitions 4")
+	tk.MustExec("create table th3(a int, b int, key(a)) partition by hash(a) partitions 3")
+	rangeDef := `partition by range(a) (
+		partition p0 values less than (250),
+		partition p1 values less than (500),
+		partition p2 values less than (750),
+		partition p3 values less than maxvalue)`
+	tk.MustExec("create table tr1(a int, b int, key(a)) " + rangeDef)
+	tk.MustExec("create table tr2(a int, b int, key(a)) " + rangeDef)
+	for _, tbl := range []string{"th1", "th2", "th3", "tr1", "tr2"} {
+		vals := make([]string, 0, 500)
+		for i := 0; i < 500; i++ {
+			vals = append(vals, fmt.Sprintf("(%v, %v)", rand.Intn(1000), rand.Intn(1000)))
+		}
+		vals = append(vals, "(null, null)")
+		tk.MustExec(fmt.Sprintf("insert into %s values %s", tbl, strings.Join(vals, ",")))
+	}
+
+	partitionWise := []string{
+		"select /*+ hash_join(th1, th2) */ * from th1 join th2 on th1.a = th2.a",
+		"select /*+ hash_join(th1, th2) */ * from th1 left join th2 on th1.a = th2.a and th2.b > 500",
+		"select /*+ hash_join(th1, th2) */ * from th1 where exists (select 1 from th2 where th2.a = th1.a and th2.b < th1.b)",
+		"select /*+ hash_join(th1, th2) */ * from th1 where not exists (select 1 from th2 where th2.a = th1.a)",
+		"select /*+ hash_agg() */ a, count(*), sum(b) from th1 group by a",
+		"select /*+ hash_join(th1, th2), hash_agg() */ th1.a, count(*), max(th2.b) from th1 join th2 on th1.a = th2.a group by th1.a",
+		"select /*+ hash_join(tr1, tr2) */ * from tr1 join tr2 on tr1.a = tr2.a where tr1.b > 100",
+		"select /*+ hash_join(tr1, tr2) */ * from tr1 right join tr2 on tr1.a = tr2.a",
+		// Only the join is executed partition-wise, the aggregation on the inner side of the outer join isn't.
+		"select /*+ hash_join(th1, th2), hash_agg() */ th2.a, count(*) from th1 left join th2 on th1.a = th2.a group by th2.a",
+	}
+	notPartitionWise := []string{
+		"select /*+ hash_join(th1, th3) */ * from th1 join th3 on th1.a = th3.a",
+		"select /*+ hash_join(th1, tr1) */ * from th1 join tr1 on th1.a = tr1.a",
+		"select /*+ hash_join(th1, th2) */ * from th1 join th2 on th1.b = th2.b",
+		"select /*+ hash_agg() */ b, count(*) from th1 group by b",
+	}
+	for _, sql := range append(partitionWise, notPartitionWise...) {
+		tk.MustExec("set @@tidb_enable_partition_wise_join = off")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		require.False(t, tk.HasPlan(sql, "Shuffle"), sql)
+		tk.MustExec("set @@tidb_enable_partition_wise_join = on")
+		tk.MustQuery(sql).Sort().Check(expected)
+	}
+	for _, sql := range partitionWise {
+		require.True(t, tk.HasPlan(sql, "Shuffle"), sql)
+	}
+	for _, sql := range notPartitionWise {
+		require.False(t, tk.HasPlan(sql, "Shuffle"), sql)
+	}
+
+	// The partitions are only split under the dynamic prune mode.
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	require.False(t, tk.HasPlan(partitionWise[0], "Shuffle"))
+}
@@ -21,18 +21,15 @@ import (
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/mathutil"
+	"github.com/pingcap/tidb/util/memory"
 )
 
-type dataInfo struct {
-	chk         *chunk.Chunk
-	remaining   uint64
-	accumulated uint64
-}
-
 // PipelinedWindowExec is the executor for window functions.
 type PipelinedWindowExec struct {
 	baseExecutor
@@ -44,15 +41,13 @@ type PipelinedWindowExec struct {
 	end                *core.FrameBound
 	groupChecker       *vecGroupChecker
 
-	// childResult stores the child chunk. Note that even if remaining is 0, e.rows might still references rows in data[0].chk after returned it to upper executor, since there is no guarantee what the upper executor will do to the returned chunk, it might destroy the data (as in the benchmark test, it reused the chunk to pull data, and it will be chk.Reset(), causing panicking). So dataIdx, accumulated and dropped are added to ensure that chunk will only be returned if there is no row reference.
+	// childResult stores the child chunk. The rows in it are copied into rows, so it's reused to fetch the next chunk.
 	childResult *chunk.Chunk
-	data        []dataInfo
-	dataIdx     int
+	// childColIdxs are the indexes of the child columns in the output.
+	childColIdxs []int
 
 	// done indicates the child executor is drained or something unexpected happened.
 	done         bool
-	accumulated  uint64
-	dropped      uint64
 	rowToConsume uint64
 	newPartition bool
 
@@ -62,56 +57,66 @@ type PipelinedWindowExec struct {
 	lastEndRow     uint64
 	stagedStartRow uint64
 	stagedEndRow   uint64
-	rowStart       uint64
 	orderByCols    []*expression.Column
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
 
-	// rows keeps rows starting from curStartRow
-	rows                     []chunk.Row
+	// rows keeps rows starting from curStartRow, which may be spilled to the disk.
+	rows                     *windowRows
 	rowCnt                   uint64
 	whole                    bool
 	isRangeFrame             bool
 	emptyFrame               bool
 	initializedSlidingWindow bool
+	// groups tracks the peer groups for the GROUPS frames, it's nil for the other frames.
+	groups *peerGroups
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
 }
 
 // Close implements the Executor Close interface.
 func (e *PipelinedWindowExec) Close() error {
+	if e.rows != nil {
+		terror.Log(e.rows.close())
+		e.rows = nil
+	}
+	e.childResult = nil
 	return errors.Trace(e.baseExecutor.Close())
 }
 
 // Open implements the Executor Open interface
 func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
+	if err = e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
 	e.rowToConsume = 0
 	e.done = false
-	e.accumulated = 0
-	e.dropped = 0
-	e.data = make([]dataInfo, 0)
-	e.dataIdx = 0
 	e.slidingWindowFuncs = make([]aggfuncs.SlidingWindowAggFunc, len(e.windowFuncs))
 	for i, windowFunc := range e.windowFuncs {
 		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
 			e.slidingWindowFuncs[i] = slidingWindowAggFunc
 		}
 	}
-	e.rows = make([]chunk.Row, 0)
-	return e.baseExecutor.Open(ctx)
-}
-
-func (e *PipelinedWindowExec) firstResultChunkNotReady() bool {
-	if !e.done && len(e.data) == 0 {
-		return true
+	e.childResult = newFirstChunk(e.children[0])
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.childColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.childColIdxs = append(e.childColIdxs, col.Index)
 	}
-	// chunk can't be ready unless, 1. all of the rows in the chunk is filled, 2. e.rows doesn't contain rows in the chunk
-	return len(e.data) > 0 && (e.data[0].remaining != 0 || e.data[0].accumulated > e.dropped)
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.rows = newWindowRows(e.ctx, retTypes(e.children[0]), e.maxChunkSize, e.memTracker, e.diskTracker)
+	return nil
 }
 
 // Next implements the Executor Next interface.
 func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err error) {
 	chk.Reset()
 
-	for e.firstResultChunkNotReady() {
+	for !chk.IsFull() {
 		// we firstly gathering enough rows and consume them, until we are able to produce.
 		// for unbounded frame, it needs consume the whole partition before being able to produce, in this case
 		// e.p.enoughToProduce will be false until so.
@@ -138,7 +143,9 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 					continue
 				}
 				e.newPartition = false
-				e.reset()
+				if err = e.reset(); err != nil {
+					return err
+				}
 				if e.rowToConsume == 0 {
 					// no more data
 					break
@@ -149,28 +156,16 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 		}
 
 		// e.p is ready to produce data
-		if len(e.data) > e.dataIdx && e.data[e.dataIdx].remaining != 0 {
-			produced, err := e.produce(e.ctx, e.data[e.dataIdx].chk, e.data[e.dataIdx].remaining)
-			if err != nil {
-				return err
-			}
-			e.data[e.dataIdx].remaining -= produced
-			if e.data[e.dataIdx].remaining == 0 {
-				e.dataIdx++
-			}
+		if err = e.produce(e.ctx, chk, uint64(chk.RequiredRows()-chk.NumRows())); err != nil {
+			return err
 		}
 	}
-	if len(e.data) > 0 {
-		chk.SwapColumns(e.data[0].chk)
-		e.data = e.data[1:]
-		e.dataIdx--
-	}
 	return nil
 }
 
 func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error) {
 	e.newPartition = true
-	if len(e.rows) == 0 {
+	if e.rows.numRows() == 0 {
 		// if getRowsInPartition is called for the first time, we ignore it as a new partition
 		e.newPartition = false
 	}
@@ -197,54 +192,16 @@ func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error
 	}
 	begin, end := e.groupChecker.getNextGroup()
 	e.rowToConsume += uint64(end - begin)
-	for i := begin; i < end; i++ {
-		e.rows = append(e.rows, e.childResult.GetRow(i))
-	}
-	return
+	return e.rows.add(e.childResult, begin, end)
 }
 
 func (e *PipelinedWindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	// TODO: reuse chunks
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+	err = Next(ctx, e.children[0], e.childResult)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
-		return true, nil
-	}
-
-	// TODO: reuse chunks
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
-	}
-	e.accumulated += uint64(numRows)
-	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated})
-
-	e.childResult = childResult
-	return false, nil
-}
-
-func (e *PipelinedWindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (e *PipelinedWindowExec) getRow(i uint64) chunk.Row {
-	return e.rows[i-e.rowStart]
-}
-
-func (e *PipelinedWindowExec) getRows(start, end uint64) []chunk.Row {
-	return e.rows[start-e.rowStart : end-e.rowStart]
+	return e.childResult.NumRows() == 0, nil
 }
 
 // finish is called upon a whole partition is consumed
@@ -256,13 +213,26 @@ func (e *PipelinedWindowExec) getStart(ctx sessionctx.Context) (uint64, error) {
 	if e.start.UnBounded {
 		return 0, nil
 	}
+	if e.groups != nil {
+		if err := e.locatePeerGroup(); err != nil {
+			return 0, err
+		}
+		return e.groups.getStartOffset(e.start), nil
+	}
 	if e.isRangeFrame {
+		curRow, err := e.rows.getRow(e.curRowIdx)
+		if err != nil {
+			return 0, err
+		}
 		var start uint64
 		for start = mathutil.Max(e.lastStartRow, e.stagedStartRow); start < e.rowCnt; start++ {
+			row, err := e.rows.getRow(start)
+			if err != nil {
+				return 0, err
+			}
 			var res int64
-			var err error
 			for i := range e.orderByCols {
-				res, _, err = e.start.CmpFuncs[i](ctx, e.orderByCols[i], e.start.CalcFuncs[i], e.getRow(start), e.getRow(e.curRowIdx))
+				res, _, err = e.start.CmpFuncs[i](ctx, e.orderByCols[i], e.start.CalcFuncs[i], row, curRow)
 				if err != nil {
 					return 0, err
 				}
@@ -296,13 +266,26 @@ func (e *PipelinedWindowExec) getEnd(ctx sessionctx.Context) (uint64, error) {
 	if e.end.UnBounded {
 		return e.rowCnt, nil
 	}
+	if e.groups != nil {
+		if err := e.locatePeerGroup(); err != nil {
+			return 0, err
+		}
+		return e.groups.getEndOffset(e.end), nil
+	}
 	if e.isRangeFrame {
+		curRow, err := e.rows.getRow(e.curRowIdx)
+		if err != nil {
+			return 0, err
+		}
 		var end uint64
 		for end = mathutil.Max(e.lastEndRow, e.stagedEndRow); end < e.rowCnt; end++ {
+			row, err := e.rows.getRow(end)
+			if err != nil {
+				return 0, err
+			}
 			var res int64
-			var err error
 			for i := range e.orderByCols {
-				res, _, err = e.end.CmpFuncs[i](ctx, e.end.CalcFuncs[i], e.orderByCols[i], e.getRow(e.curRowIdx), e.getRow(end))
+				res, _, err = e.end.CmpFuncs[i](ctx, e.end.CalcFuncs[i], e.orderByCols[i], curRow, row)
 				if err != nil {
 					return 0, err
 				}
@@ -332,9 +315,17 @@ func (e *PipelinedWindowExec) getEnd(ctx sessionctx.Context) (uint64, error) {
 	}
 }
 
-// produce produces rows and append it to chk, return produced means number of rows appended into chunk, available means
-// number of rows processed but not fetched
-func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk, remained uint64) (produced uint64, err error) {
+// locatePeerGroup finds the peer groups of the rows consumed so far, and moves to the peer group of the current row.
+func (e *PipelinedWindowExec) locatePeerGroup() error {
+	if err := e.groups.scan(e.rows, e.rowCnt); err != nil {
+		return err
+	}
+	e.groups.locate(e.curRowIdx)
+	return nil
+}
+
+// produce produces at most remained rows and append them to chk.
+func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk, remained uint64) (err error) {
 	var (
 		start  uint64
 		end    uint64
@@ -362,6 +353,12 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 		if start >= e.rowCnt {
 			start = e.rowCnt
 		}
+		var row chunk.Row
+		row, err = e.rows.getRow(e.curRowIdx)
+		if err != nil {
+			return
+		}
+		chk.AppendPartialRowByColIdxs(0, row, e.childColIdxs)
 		// if start >= end, we should return a default value, and we reset the frame to empty.
 		if start >= end {
 			for i, wf := range e.windowFuncs {
@@ -383,17 +380,14 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 				slidingWindowAggFunc := e.slidingWindowFuncs[i]
 				if e.lastStartRow != start || e.lastEndRow != end {
 					if slidingWindowAggFunc != nil && e.initializedSlidingWindow {
-						err = slidingWindowAggFunc.Slide(ctx, e.getRow, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
-					} else {
-						// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
-						// whether elements inside deque are out of current window.
-						if minMaxSlidingWindowAggFunc, ok := wf.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-							// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
-							minMaxSlidingWindowAggFunc.SetWindowStart(start)
+						err = slidingWindowAggFunc.Slide(ctx, e.rows.getRowOrNull, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
+						if err == nil {
+							err = e.rows.takeErr()
 						}
+					} else {
 						// TODO(zhifeng): track memory usage here
 						wf.ResetPartialResult(e.partialResults[i])
-						_, err = wf.UpdatePartialResult(ctx, e.getRows(start, end), e.partialResults[i])
+						err = updatePartialResultOfFrame(ctx, wf, e.rows, start, end, e.partialResults[i])
 					}
 				}
 				if err != nil {
@@ -409,17 +403,9 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 		e.curRowIdx++
 		e.lastStartRow, e.lastEndRow = start, end
 
-		produced++
 		remained--
 	}
-	extend := mathutil.Min(e.curRowIdx, e.lastEndRow, e.lastStartRow)
-	if extend > e.rowStart {
-		numDrop := extend - e.rowStart
-		e.dropped += numDrop
-		e.rows = e.rows[numDrop:]
-		e.rowStart = extend
-	}
-	return
+	return e.rows.drop(mathutil.Min(e.curRowIdx, e.lastEndRow, e.lastStartRow))
 }
 
 func (e *PipelinedWindowExec) enoughToProduce(ctx sessionctx.Context) (enough bool, err error) {
@@ -441,7 +427,7 @@ func (e *PipelinedWindowExec) enoughToProduce(ctx sessionctx.Context) (enough bo
 }
 
 // reset resets the processor
-func (e *PipelinedWindowExec) reset() {
+func (e *PipelinedWindowExec) reset() error {
 	e.lastStartRow = 0
 	e.lastEndRow = 0
 	e.stagedStartRow = 0
@@ -449,13 +435,16 @@ func (e *PipelinedWindowExec) reset() {
 	e.emptyFrame = false
 	e.curRowIdx = 0
 	e.whole = false
-	numDrop := e.rowCnt - e.rowStart
-	e.dropped += numDrop
-	e.rows = e.rows[numDrop:]
-	e.rowStart = 0
+	if err := e.rows.nextPartition(e.rowCnt); err != nil {
+		return err
+	}
 	e.rowCnt = 0
+	if e.groups != nil {
+		e.groups.reset()
+	}
 	e.initializedSlidingWindow = false
 	for i, windowFunc := range e.windowFuncs {
 		windowFunc.ResetPartialResult(e.partialResults[i])
 	}
+	return nil
 }
@@ -0,0 +1,274 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// RoutineInfo is the definition of a stored routine saved in mysql.routines.
+type RoutineInfo struct {
+	DB   string
+	Name string
+	Type ast.RoutineType
+	// ParamList is the parameter list without the enclosing parentheses.
+	ParamList string
+	// Returns is the return type of a stored function.
+	Returns             string
+	Body                string
+	Definer             string
+	SecurityType        string
+	IsDeterministic     string
+	SQLDataAccess       string
+	Comment             string
+	SQLMode             string
+	CharsetClient       string
+	CollationConnection string
+	DBCollation         string
+	Created             types.Time
+	LastAltered         types.Time
+}
+
+const routineColumns = "db, name, type, param_list, returns, body, definer, security_type, is_deterministic, " +
+	"sql_data_access, comment, sql_mode, character_set_client, collation_connection, db_collation, created, last_altered"
+
+// CreateStmt rebuilds the CREATE statement of the routine, in the format of SHOW CREATE PROCEDURE.
+func (r *RoutineInfo) CreateStmt() string {
+	var sb strings.Builder
+	sb.WriteString("CREATE DEFINER=")
+	user, host := r.Definer, ""
+	if idx := strings.LastIndexByte(r.Definer, '@'); idx >= 0 {
+		user, host = r.Definer[:idx], r.Definer[idx+1:]
+	}
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	restoreCtx.WriteName(user)
+	sb.WriteString("@")
+	restoreCtx.WriteName(host)
+	sb.WriteString(" ")
+	sb.WriteString(r.Type.String())
+	sb.WriteString(" ")
+	restoreCtx.WriteName(r.Name)
+	sb.WriteString("(")
+	sb.WriteString(r.ParamList)
+	sb.WriteString(")")
+	if r.Type == ast.RoutineFunction {
+		sb.WriteString(" RETURNS ")
+		sb.WriteString(r.Returns)
+	}
+	sb.WriteString("\n")
+	if r.IsDeterministic == "YES" {
+		sb.WriteString("    DETERMINISTIC\n")
+	}
+	if r.SQLDataAccess != "CONTAINS SQL" {
+		sb.WriteString("    ")
+		sb.WriteString(r.SQLDataAccess)
+		sb.WriteString("\n")
+	}
+	if r.SecurityType != "DEFINER" {
+		sb.WriteString("    SQL SECURITY ")
+		sb.WriteString(r.SecurityType)
+		sb.WriteString("\n")
+	}
+	if r.Comment != "" {
+		sb.WriteString("    COMMENT ")
+		restoreCtx.WriteString(r.Comment)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(r.Body)
+	return sb.String()
+}
+
+// LoadRoutines loads the routines matching the condition from mysql.routines, ordered by the schema and the name.
+func LoadRoutines(ctx context.Context, sctx sessionctx.Context, where string, args ...interface{}) ([]*RoutineInfo, error) {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnRoutine)
+	sql := "SELECT " + routineColumns + " FROM mysql.routines"
+	if where != "" {
+		sql += " WHERE " + where
+	}
+	sql += " ORDER BY db, name, type"
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil, sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	routines := make([]*RoutineInfo, 0, len(rows))
+	for _, row := range rows {
+		routines = append(routines, decodeRoutine(row))
+	}
+	return routines, nil
+}
+
+// LoadRoutine loads the routine from mysql.routines. It returns nil if the routine doesn't exist.
+func LoadRoutine(ctx context.Context, sctx sessionctx.Context, tp ast.RoutineType, db, name string) (*RoutineInfo, error) {
+	routines, err := LoadRoutines(ctx, sctx, "db = %? AND name = %? AND type = %?", strings.ToLower(db), name, tp.String())
+	if err != nil || len(routines) == 0 {
+		return nil, err
+	}
+	return routines[0], nil
+}
+
+func decodeRoutine(row chunk.Row) *RoutineInfo {
+	r := &RoutineInfo{
+		DB:                  row.GetString(0),
+		Name:                row.GetString(1),
+		ParamList:           row.GetString(3),
+		Returns:             row.GetString(4),
+		Body:                row.GetString(5),
+		Definer:             row.GetString(6),
+		SecurityType:        row.GetEnum(7).String(),
+		IsDeterministic:     row.GetEnum(8).String(),
+		SQLDataAccess:       row.GetEnum(9).String(),
+		Comment:             row.GetString(10),
+		SQLMode:             row.GetString(11),
+		CharsetClient:       row.GetString(12),
+		CollationConnection: row.GetString(13),
+		DBCollation:         row.GetString(14),
+		Created:             row.GetTime(15),
+		LastAltered:         row.GetTime(16),
+	}
+	if row.GetEnum(2).String() == ast.RoutineFunction.String() {
+		r.Type = ast.RoutineFunction
+	}
+	return r
+}
+
+func restoreToString(restore func(*format.RestoreCtx) error) (string, error) {
+	var sb strings.Builder
+	if err := restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (e *SimpleExec) executeCreateRoutine(ctx context.Context, s *ast.CreateRoutineStmt) error {
+	dbInfo, ok := e.ctx.GetInfoSchema().(infoschema.InfoSchema).SchemaByName(s.Name.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.Name.Schema.O)
+	}
+	params, err := restoreToString(s.RestoreParams)
+	if err != nil {
+		return err
+	}
+	params = params[1 : len(params)-1]
+	returns := ""
+	if s.Returns != nil {
+		if returns, err = restoreToString(s.Returns.Restore); err != nil {
+			return err
+		}
+	}
+	security, deterministic, dataAccess, comment := model.SecurityDefiner, false, ast.RoutineDataAccessContainsSQL, ""
+	for _, option := range s.Options {
+		switch option.Tp {
+		case ast.RoutineOptionComment:
+			comment = option.StrValue
+		case ast.RoutineOptionDeterministic:
+			deterministic = option.BoolValue
+		case ast.RoutineOptionDataAccess:
+			dataAccess = option.DataAccess
+		case ast.RoutineOptionSecurity:
+			security = option.Security
+		}
+	}
+	isDeterministic := "NO"
+	if deterministic {
+		isDeterministic = "YES"
+	}
+	sessVars := e.ctx.GetSessionVars()
+	sqlMode, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.SQLModeVar)
+	if err != nil {
+		return err
+	}
+	charsetClient, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.CharacterSetClient)
+	if err != nil {
+		return err
+	}
+	collation, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.CollationConnection)
+	if err != nil {
+		return err
+	}
+
+	routine, err := LoadRoutine(ctx, e.ctx, s.Type, s.Name.Schema.L, s.Name.Name.O)
+	if err != nil {
+		return err
+	}
+	if routine != nil {
+		err = ErrSpAlreadyExists.GenWithStackByArgs(s.Type.String(), s.Name.Name.O)
+		if s.IfNotExists {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnRoutine)
+	_, _, err = e.ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"INSERT INTO mysql.routines ("+routineColumns[:strings.Index(routineColumns, ", created")]+
+			") VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)",
+		s.Name.Schema.L, s.Name.Name.O, s.Type.String(), params, returns, s.Body.Text(), s.Definer.String(),
+		security.String(), isDeterministic, dataAccess.String(), comment, sqlMode, charsetClient, collation, dbInfo.Collate)
+	if terror.ErrorEqual(err, kv.ErrKeyExists) {
+		// The routine is created concurrently.
+		return ErrSpAlreadyExists.GenWithStackByArgs(s.Type.String(), s.Name.Name.O)
+	}
+	return err
+}
+
+func (e *SimpleExec) executeDropRoutine(ctx context.Context, s *ast.DropRoutineStmt) error {
+	if s.Name.Schema.L == "" {
+		// The plan builder leaves the schema empty for the loadable functions.
+		return e.executeDropLoadableFunction(ctx, s)
+	}
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnRoutine)
+	se, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(ctx, se)
+	_, _, err = se.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession},
+		"DELETE FROM mysql.routines WHERE db = %? AND name = %? AND type = %?", s.Name.Schema.L, s.Name.Name.O, s.Type.String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if se.GetSessionVars().StmtCtx.AffectedRows() == 0 {
+		err = ErrSpDoesNotExist.GenWithStackByArgs(s.Type.String(), s.Name.Schema.O+"."+s.Name.Name.O)
+		if s.IfExists {
+			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// dropSchemaRoutines removes the routines of the dropped schema.
+func dropSchemaRoutines(sctx sessionctx.Context, dbName model.CIStr) error {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnRoutine)
+	_, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"DELETE FROM mysql.routines WHERE db = %?", dbName.L)
+	return errors.Trace(err)
+}
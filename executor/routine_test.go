@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDropRoutine(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create procedure p(in a int, out b varchar(10)) comment 'proc' begin set b = a; end")
+	tk.MustGetErrCode("create function f(a int) returns int deterministic return a + 1", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("create function f(a int) returns int begin return a + 1; end", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("create procedure p() select 1", errno.ErrSpAlreadyExists)
+	tk.MustExec("create procedure if not exists p() select 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1304 PROCEDURE p already exists"))
+	tk.MustGetErrCode("create procedure nodb.p() select 1", errno.ErrBadDB)
+
+	tk.MustQuery("show create procedure p").Check(testkit.Rows("p ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_AUTO_CREATE_USER,NO_ENGINE_SUBSTITUTION " +
+		"CREATE DEFINER=`root`@`%` PROCEDURE `p`(`a` INT,OUT `b` VARCHAR(10))\n    COMMENT 'proc'\nbegin set b = a; end utf8mb4 utf8mb4_bin utf8mb4_bin"))
+	require.True(t, terror.ErrorEqual(tk.QueryToErr("show create function p"), executor.ErrSpDoesNotExist))
+
+	tk.MustQuery("show procedure status where db = 'test'").CheckAt([]int{0, 1, 2, 3, 6, 7}, testkit.Rows("test p PROCEDURE root@% DEFINER proc"))
+	tk.MustQuery("show function status like 'p'").Check(testkit.Rows())
+	tk.MustQuery("select routine_schema, routine_name, routine_type, data_type, dtd_identifier, routine_definition, is_deterministic, routine_comment " +
+		"from information_schema.routines order by routine_name").Check(testkit.Rows(
+		"test p PROCEDURE  <nil> begin set b = a; end NO proc"))
+
+	tk.MustExec("drop procedure p")
+	tk.MustGetErrCode("drop procedure p", errno.ErrSpDoesNotExist)
+	tk.MustExec("drop procedure if exists p")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1305 PROCEDURE test.p does not exist"))
+	tk.MustGetErrCode("drop function if exists f; drop function f", errno.ErrSpDoesNotExist)
+
+	// The routines are dropped with the database.
+	tk.MustExec("create database routine_db")
+	tk.MustExec("create procedure routine_db.p() select 1")
+	tk.MustQuery("select count(*) from mysql.routines where db = 'routine_db'").Check(testkit.Rows("1"))
+	tk.MustExec("drop database routine_db")
+	tk.MustQuery("select count(*) from mysql.routines where db = 'routine_db'").Check(testkit.Rows("0"))
+}
+
+func TestRoutinePrivileges(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create procedure p() select 1")
+	tk.MustExec("create user 'u1'@'%', 'u2'@'%'")
+	tk.MustExec("grant create routine on test.* to 'u1'@'%'")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("create procedure test.p1() select 1")
+	tk1.MustGetErrCode("create definer = 'root'@'%' procedure test.p2() select 1", errno.ErrSpecificAccessDenied)
+	tk1.MustGetErrCode("create procedure mysql.p() select 1", errno.ErrDBaccessDenied)
+	tk1.MustGetErrCode("drop procedure test.p1", errno.ErrDBaccessDenied)
+	tk1.MustQuery("show procedure status").CheckAt([]int{1, 3}, testkit.Rows("p root@%", "p1 u1@%"))
+
+	// The routines are invisible to the users without routine privileges.
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("show procedure status").Check(testkit.Rows())
+	tk2.MustQuery("select count(*) from information_schema.routines").Check(testkit.Rows("0"))
+	require.True(t, terror.ErrorEqual(tk2.QueryToErr("show create procedure test.p"), executor.ErrSpDoesNotExist))
+	tk.MustExec("grant execute on test.* to 'u2'@'%'")
+	tk2.MustQuery("select routine_name from information_schema.routines order by routine_name").Check(testkit.Rows("p", "p1"))
+}
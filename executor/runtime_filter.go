@@ -0,0 +1,487 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+const (
+	// runtimeFilterMaxInValues is the max number of the distinct values of a runtime filter that are pushed down to
+	// the storage as an IN list. If there are more values, only the min/max values are pushed down, and the rows are
+	// filtered by the bloom filter after they are read.
+	runtimeFilterMaxInValues = 1024
+	// runtimeFilterBloomBitsPerKey makes the false positive rate of the bloom filter about 3% with 3 hash functions.
+	runtimeFilterBloomBitsPerKey = 8
+	runtimeFilterBloomHashNum    = 3
+	runtimeFilterBloomMinBits    = 1 << 12
+	runtimeFilterBloomMaxBits    = 1 << 28
+)
+
+// runtimeFilter is built by a HashJoinExec with the keys of its build side, and applied by the TableReaderExecutor of
+// its probe side. The reader waits until the filter is published, so the filter must be published by the hash join
+// no matter whether the build side is read successfully.
+type runtimeFilter struct {
+	plan *plannercore.RuntimeFilter
+
+	buildColIdx int
+	buildTp     *types.FieldType
+	probeTp     *types.FieldType
+	collator    collate.Collator
+
+	// ready is closed when the filter is published. The fields below are only read after that.
+	ready chan struct{}
+	// built indicates whether the filter is built successfully. If not, the hash join returns an error or is closed,
+	// so the rows of the probe side are useless.
+	built bool
+	// numValues is the number of the non-null keys. If it's 0, no probe side row can be joined.
+	numValues      int
+	minVal, maxVal types.Datum
+	// inValues are the distinct keys, it's nil if there are more than runtimeFilterMaxInValues of them.
+	inValues   []types.Datum
+	inValueSet map[string]struct{}
+	bloom      *runtimeBloomFilter
+
+	hCtx       *hashContext
+	encodedBuf []byte
+}
+
+func newRuntimeFilter(plan *plannercore.RuntimeFilter) *runtimeFilter {
+	return &runtimeFilter{plan: plan}
+}
+
+// reset prepares the filter to be built again.
+func (rf *runtimeFilter) reset(estKeyNum float64) {
+	rf.collator = collate.GetCollator(rf.buildTp.GetCollate())
+	rf.ready = make(chan struct{})
+	rf.built = false
+	rf.numValues = 0
+	rf.minVal.SetNull()
+	rf.maxVal.SetNull()
+	rf.inValues = rf.inValues[:0]
+	rf.inValueSet = make(map[string]struct{})
+	rf.bloom = newRuntimeBloomFilter(estKeyNum)
+	rf.hCtx = &hashContext{}
+}
+
+// insert adds the keys of the selected rows in the build side chunk into the filter.
+func (rf *runtimeFilter) insert(sc *stmtctx.StatementContext, chk *chunk.Chunk, selected []bool) error {
+	numRows := chk.NumRows()
+	rf.hCtx.initHash(numRows)
+	err := codec.HashChunkSelected(sc, rf.hCtx.hashVals, chk, rf.buildTp, rf.buildColIdx, rf.hCtx.buf, rf.hCtx.hasNull, selected, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i := 0; i < numRows; i++ {
+		if (selected != nil && !selected[i]) || rf.hCtx.hasNull[i] {
+			continue
+		}
+		rf.bloom.insert(rf.hCtx.hashVals[i].Sum64())
+		d := chk.GetRow(i).GetDatum(rf.buildColIdx, rf.buildTp)
+		if rf.numValues == 0 {
+			d.Copy(&rf.minVal)
+			d.Copy(&rf.maxVal)
+		} else {
+			cmp, err := d.Compare(sc, &rf.minVal, rf.collator)
+			if err != nil {
+				return err
+			}
+			if cmp < 0 {
+				d.Copy(&rf.minVal)
+			}
+			cmp, err = d.Compare(sc, &rf.maxVal, rf.collator)
+			if err != nil {
+				return err
+			}
+			if cmp > 0 {
+				d.Copy(&rf.maxVal)
+			}
+		}
+		rf.numValues++
+		if rf.inValueSet != nil {
+			if err = rf.insertInValue(sc, d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (rf *runtimeFilter) insertInValue(sc *stmtctx.StatementContext, d types.Datum) (err error) {
+	rf.encodedBuf, err = codec.EncodeValue(sc, rf.encodedBuf[:0], d)
+	if err != nil {
+		return err
+	}
+	if _, ok := rf.inValueSet[string(rf.encodedBuf)]; ok {
+		return nil
+	}
+	if len(rf.inValues) >= runtimeFilterMaxInValues {
+		rf.inValues, rf.inValueSet = nil, nil
+		return nil
+	}
+	rf.inValueSet[string(rf.encodedBuf)] = struct{}{}
+	var value types.Datum
+	d.Copy(&value)
+	rf.inValues = append(rf.inValues, value)
+	return nil
+}
+
+// publish wakes up the probe side reader.
+func (rf *runtimeFilter) publish(built bool) {
+	rf.built = built
+	rf.inValueSet = nil
+	close(rf.ready)
+}
+
+// canFilterAll checks whether no probe side row can be joined.
+func (rf *runtimeFilter) canFilterAll() bool {
+	return !rf.built || rf.numValues == 0
+}
+
+// useBloom checks whether the bloom filter should be applied to the rows read from the storage. It's unnecessary if
+// all the distinct keys are pushed down as an IN list.
+func (rf *runtimeFilter) useBloom() bool {
+	return rf.inValues == nil
+}
+
+// conditions builds the conditions pushed down to the storage.
+func (rf *runtimeFilter) conditions(sctx sessionctx.Context) ([]expression.Expression, error) {
+	col := rf.plan.ProbeCol
+	newConstant := func(d types.Datum) *expression.Constant {
+		return &expression.Constant{Value: d, RetType: rf.buildTp}
+	}
+	if !rf.useBloom() {
+		args := make([]expression.Expression, 0, len(rf.inValues)+1)
+		args = append(args, col)
+		for _, d := range rf.inValues {
+			args = append(args, newConstant(d))
+		}
+		cond, err := expression.NewFunction(sctx, ast.In, types.NewFieldType(mysql.TypeLonglong), args...)
+		if err != nil {
+			return nil, err
+		}
+		return []expression.Expression{cond}, nil
+	}
+	ge, err := expression.NewFunction(sctx, ast.GE, types.NewFieldType(mysql.TypeLonglong), col, newConstant(rf.minVal))
+	if err != nil {
+		return nil, err
+	}
+	le, err := expression.NewFunction(sctx, ast.LE, types.NewFieldType(mysql.TypeLonglong), col, newConstant(rf.maxVal))
+	if err != nil {
+		return nil, err
+	}
+	return []expression.Expression{ge, le}, nil
+}
+
+func (rf *runtimeFilter) explainInfo() string {
+	switch {
+	case !rf.built:
+		return "unavailable"
+	case rf.numValues == 0:
+		return "empty"
+	case rf.useBloom():
+		return "min_max bloom"
+	}
+	return fmt.Sprintf("in:%d", len(rf.inValues))
+}
+
+// runtimeBloomFilter is a bloom filter of the hash values of the keys.
+type runtimeBloomFilter struct {
+	bits []uint64
+	mask uint64
+}
+
+func newRuntimeBloomFilter(estKeyNum float64) *runtimeBloomFilter {
+	numBits := uint64(runtimeFilterBloomMinBits)
+	if want := estKeyNum * runtimeFilterBloomBitsPerKey; want > float64(numBits) {
+		if want > runtimeFilterBloomMaxBits {
+			numBits = runtimeFilterBloomMaxBits
+		} else {
+			numBits = 1 << bits.Len64(uint64(want)-1)
+		}
+	}
+	return &runtimeBloomFilter{
+		bits: make([]uint64, numBits/64),
+		mask: numBits - 1,
+	}
+}
+
+func (bf *runtimeBloomFilter) insert(hashVal uint64) {
+	h1, h2 := hashVal, bits.RotateLeft64(hashVal, 32)|1
+	for i := uint64(0); i < runtimeFilterBloomHashNum; i++ {
+		pos := (h1 + i*h2) & bf.mask
+		bf.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+func (bf *runtimeBloomFilter) mayContain(hashVal uint64) bool {
+	h1, h2 := hashVal, bits.RotateLeft64(hashVal, 32)|1
+	for i := uint64(0); i < runtimeFilterBloomHashNum; i++ {
+		pos := (h1 + i*h2) & bf.mask
+		if bf.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (bf *runtimeBloomFilter) memUsage() int64 {
+	return int64(len(bf.bits)) * 8
+}
+
+// resetRuntimeFilters prepares the runtime filters before the hash join is executed.
+func (e *HashJoinExec) resetRuntimeFilters() {
+	for _, rf := range e.runtimeFilters {
+		rf.reset(e.buildSideEstCount)
+		e.memTracker.Consume(rf.bloom.memUsage())
+	}
+}
+
+// insertRuntimeFilters adds the keys of the build side chunk into the runtime filters.
+func (e *HashJoinExec) insertRuntimeFilters(chk *chunk.Chunk, selected []bool) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	for _, rf := range e.runtimeFilters {
+		if err := rf.insert(sc, chk, selected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *HashJoinExec) publishRuntimeFilters(built bool) {
+	for _, rf := range e.runtimeFilters {
+		rf.publish(built)
+	}
+}
+
+// runtimeFilterProbe applies the runtime filters to a TableReaderExecutor. The request is not sent until all the
+// filters are published. Then the min/max values or the IN lists of the filters are added into the conditions of the
+// selection pushed down to the storage, and the bloom filters are applied to the rows read from the storage.
+type runtimeFilterProbe struct {
+	filters []*runtimeFilter
+	// selIdx is the index of the pushed down selection in the executors of the DAG request.
+	selIdx int
+	// baseCondNum is the number of the conditions of the selection without the runtime filters.
+	baseCondNum int
+
+	prepared bool
+	// skipScan indicates no row can be joined, so the request is not sent.
+	skipScan bool
+
+	hCtx     *hashContext
+	selected []int
+	stats    *runtimeFilterRuntimeStats
+}
+
+func (p *runtimeFilterProbe) reset() {
+	p.prepared = false
+	p.skipScan = false
+	p.stats = &runtimeFilterRuntimeStats{filters: p.filters}
+}
+
+// buildRuntimeFilterProbe attaches the runtime filters built by the parent hash join to the table reader.
+func (b *executorBuilder) buildRuntimeFilterProbe(e *TableReaderExecutor, plans []plannercore.PhysicalPlan) {
+	for i, p := range plans {
+		sel, ok := p.(*plannercore.PhysicalSelection)
+		if !ok || len(sel.RuntimeFilters) == 0 {
+			continue
+		}
+		probe := &runtimeFilterProbe{
+			selIdx:      i,
+			baseCondNum: len(sel.Conditions),
+			hCtx:        &hashContext{},
+		}
+		for _, rfPlan := range sel.RuntimeFilters {
+			if rf, ok := b.runtimeFilters[rfPlan]; ok {
+				probe.filters = append(probe.filters, rf)
+			}
+		}
+		if len(probe.filters) > 0 {
+			e.runtimeFilterProbe = probe
+		}
+		return
+	}
+}
+
+// prepareRuntimeFilters waits for the runtime filters, and sends the request with them.
+func (e *TableReaderExecutor) prepareRuntimeFilters(ctx context.Context) error {
+	p := e.runtimeFilterProbe
+	p.prepared = true
+	start := time.Now()
+	for _, rf := range p.filters {
+		select {
+		case <-rf.ready:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	p.stats.waitTime = time.Since(start)
+	conds := make([]expression.Expression, 0, 2*len(p.filters))
+	for _, rf := range p.filters {
+		if rf.canFilterAll() {
+			p.skipScan = true
+			return nil
+		}
+		rfConds, err := rf.conditions(e.ctx)
+		if err != nil {
+			return err
+		}
+		conds = append(conds, rfConds...)
+	}
+	pbConds, err := expression.ExpressionsToPBList(e.ctx.GetSessionVars().StmtCtx, conds, e.ctx.GetClient())
+	if err != nil {
+		return err
+	}
+	var sel *tipb.Selection
+	if e.dagPB.RootExecutor != nil {
+		sel = e.dagPB.RootExecutor.Selection
+	} else if p.selIdx < len(e.dagPB.Executors) {
+		sel = e.dagPB.Executors[p.selIdx].Selection
+	}
+	if sel == nil {
+		return errors.New("the selection for the runtime filters is not found")
+	}
+	sel.Conditions = append(sel.Conditions[:p.baseCondNum:p.baseCondNum], pbConds...)
+	return e.sendRequest(ctx)
+}
+
+// nextWithRuntimeFilters reads the rows that pass the bloom filters.
+func (e *TableReaderExecutor) nextWithRuntimeFilters(ctx context.Context, req *chunk.Chunk) error {
+	p := e.runtimeFilterProbe
+	if !p.prepared {
+		if err := e.prepareRuntimeFilters(ctx); err != nil {
+			return err
+		}
+	}
+	if p.skipScan {
+		req.Reset()
+		return nil
+	}
+	for {
+		if err := e.next(ctx, req); err != nil {
+			return err
+		}
+		numRows := req.NumRows()
+		if numRows == 0 {
+			return nil
+		}
+		if err := p.filter(e.ctx.GetSessionVars().StmtCtx, req); err != nil {
+			return err
+		}
+		atomic.AddInt64(&p.stats.bloomFilteredRows, int64(numRows-req.NumRows()))
+		if req.NumRows() > 0 {
+			return nil
+		}
+	}
+}
+
+// filter removes the rows of the chunk that can't pass the bloom filters.
+func (p *runtimeFilterProbe) filter(sc *stmtctx.StatementContext, chk *chunk.Chunk) error {
+	numRows := chk.NumRows()
+	p.selected = p.selected[:0]
+	for i := 0; i < numRows; i++ {
+		p.selected = append(p.selected, i)
+	}
+	for _, rf := range p.filters {
+		if !rf.useBloom() {
+			continue
+		}
+		p.hCtx.initHash(numRows)
+		err := codec.HashChunkColumns(sc, p.hCtx.hashVals, chk, rf.probeTp, rf.plan.ProbeCol.Index, p.hCtx.buf, p.hCtx.hasNull)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		selected := p.selected[:0]
+		for _, i := range p.selected {
+			if !p.hCtx.hasNull[i] && rf.bloom.mayContain(p.hCtx.hashVals[i].Sum64()) {
+				selected = append(selected, i)
+			}
+		}
+		p.selected = selected
+	}
+	if len(p.selected) < numRows {
+		chk.SetSel(p.selected)
+		chk.Reconstruct()
+	}
+	return nil
+}
+
+// runtimeFilterRuntimeStats is the runtime stats of the runtime filters applied by a table reader.
+type runtimeFilterRuntimeStats struct {
+	filters  []*runtimeFilter
+	waitTime time.Duration
+	// bloomFilteredRows is the number of the rows read from the storage but filtered out by the bloom filters. The rows
+	// filtered out in the storage by the min/max values or the IN lists are not counted, since they can't be told apart
+	// from the ones filtered out by the other pushed down conditions.
+	bloomFilteredRows int64
+}
+
+// String implements the RuntimeStats interface.
+func (s *runtimeFilterRuntimeStats) String() string {
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+	buf.WriteString("runtime_filter:{")
+	for _, rf := range s.filters {
+		select {
+		case <-rf.ready:
+			fmt.Fprintf(buf, "rf%d:{%s}, ", rf.plan.ID, rf.explainInfo())
+		default:
+		}
+	}
+	fmt.Fprintf(buf, "wait:%v, bloom_filtered_rows:%d}", execdetails.FormatDuration(s.waitTime), atomic.LoadInt64(&s.bloomFilteredRows))
+	return buf.String()
+}
+
+// Clone implements the RuntimeStats interface.
+func (s *runtimeFilterRuntimeStats) Clone() execdetails.RuntimeStats {
+	return &runtimeFilterRuntimeStats{
+		filters:           s.filters,
+		waitTime:          s.waitTime,
+		bloomFilteredRows: atomic.LoadInt64(&s.bloomFilteredRows),
+	}
+}
+
+// Merge implements the RuntimeStats interface.
+func (s *runtimeFilterRuntimeStats) Merge(other execdetails.RuntimeStats) {
+	tmp, ok := other.(*runtimeFilterRuntimeStats)
+	if !ok {
+		return
+	}
+	s.waitTime += tmp.waitTime
+	atomic.AddInt64(&s.bloomFilteredRows, atomic.LoadInt64(&tmp.bloomFilteredRows))
+}
+
+// Tp implements the RuntimeStats interface.
+func (s *runtimeFilterRuntimeStats) Tp() int {
+	return execdetails.TpRuntimeFilterRuntimeStats
+}
@@ -21,6 +21,7 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
@@ -47,7 +48,11 @@ type SelectIntoExec struct {
 
 // Open implements the Executor Open interface.
 func (s *SelectIntoExec) Open(ctx context.Context) error {
-	// only 'select ... into outfile' is supported now
+	if s.intoOpt.Tp == ast.SelectIntoVars {
+		s.chk = newFirstChunk(s.children[0])
+		return s.baseExecutor.Open(ctx)
+	}
+	// only 'select ... into outfile' and 'select ... into @var' are supported now
 	if s.intoOpt.Tp != ast.SelectIntoOutfile {
 		return errors.New("unsupported SelectInto type")
 	}
@@ -69,6 +74,9 @@ func (s *SelectIntoExec) Open(ctx context.Context) error {
 
 // Next implements the Executor Next interface.
 func (s *SelectIntoExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if s.intoOpt.Tp == ast.SelectIntoVars {
+		return s.assignVariables(ctx)
+	}
 	for {
 		if err := Next(ctx, s.children[0], s.chk); err != nil {
 			return err
@@ -83,6 +91,47 @@ func (s *SelectIntoExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return nil
 }
 
+// assignVariables assigns the only row of the result to the user variables.
+func (s *SelectIntoExec) assignVariables(ctx context.Context) error {
+	var row []types.Datum
+	for {
+		if err := Next(ctx, s.children[0], s.chk); err != nil {
+			return err
+		}
+		if s.chk.NumRows() == 0 {
+			break
+		}
+		if row != nil || s.chk.NumRows() > 1 {
+			return ErrTooManyRows.GenWithStackByArgs()
+		}
+		// The datums refer to the memory of the chunk, which is reused by the next call.
+		datums := s.chk.GetRow(0).GetDatumRow(retTypes(s.children[0]))
+		row = make([]types.Datum, len(datums))
+		for i := range datums {
+			datums[i].Copy(&row[i])
+		}
+	}
+	sessionVars := s.ctx.GetSessionVars()
+	if row == nil {
+		sessionVars.StmtCtx.AppendWarning(ErrSpFetchNoData.GenWithStackByArgs())
+		return nil
+	}
+	cols := s.children[0].Schema().Columns
+	sessionVars.UsersLock.Lock()
+	defer sessionVars.UsersLock.Unlock()
+	for i, v := range s.intoOpt.Variables {
+		name := strings.ToLower(v.(*ast.VariableExpr).Name)
+		if row[i].IsNull() {
+			delete(sessionVars.Users, name)
+			delete(sessionVars.UserVarTypes, name)
+			continue
+		}
+		sessionVars.Users[name] = row[i]
+		sessionVars.UserVarTypes[name] = cols[i].GetType()
+	}
+	return nil
+}
+
 func (s *SelectIntoExec) considerEncloseOpt(et types.EvalType) bool {
 	return et == types.ETString || et == types.ETDuration ||
 		et == types.ETTimestamp || et == types.ETDatetime ||
@@ -179,7 +228,7 @@ func (s *SelectIntoExec) dumpToOutfile() error {
 			case mysql.TypeNewDecimal:
 				s.fieldBuf = append(s.fieldBuf, row.GetMyDecimal(j).String()...)
 			case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
-				mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+				mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeGeometry:
 				s.fieldBuf = append(s.fieldBuf, row.GetBytes(j)...)
 			case mysql.TypeBit:
 				// bit value won't be escaped anyway (verified on MySQL, test case added)
@@ -59,6 +59,7 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/etcd"
 	"github.com/pingcap/tidb/util/format"
 	"github.com/pingcap/tidb/util/hack"
@@ -171,8 +172,14 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowGrants()
 	case ast.ShowIndex:
 		return e.fetchShowIndex()
-	case ast.ShowProcedureStatus:
-		return e.fetchShowProcedureStatus()
+	case ast.ShowProcedureStatus, ast.ShowFunctionStatus:
+		return e.fetchShowProcedureStatus(ctx)
+	case ast.ShowCreateProcedure, ast.ShowCreateFunction:
+		return e.fetchShowCreateProcedure(ctx)
+	case ast.ShowCreateTrigger:
+		return e.fetchShowCreateTrigger()
+	case ast.ShowCreateEvent:
+		return e.fetchShowCreateEvent(ctx)
 	case ast.ShowPumpStatus:
 		return e.fetchShowPumpOrDrainerStatus(node.PumpNode)
 	case ast.ShowStatus:
@@ -194,7 +201,7 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 	case ast.ShowProcessList:
 		return e.fetchShowProcessList()
 	case ast.ShowEvents:
-		// empty result
+		return e.fetchShowEvents(ctx)
 	case ast.ShowStatsExtended:
 		return e.fetchShowStatsExtended()
 	case ast.ShowStatsMeta:
@@ -749,6 +756,12 @@ func (e *ShowExec) fetchShowIndex() error {
 				visible = "NO"
 			}
 
+			// The entries of a FULLTEXT index are not sorted by the column values.
+			var collation interface{} = "A"
+			if idx.Meta().Tp == model.IndexTypeFulltext {
+				collation = nil
+			}
+
 			colName := col.Name.O
 			var expression interface{}
 			if tblCol.Hidden {
@@ -762,7 +775,7 @@ func (e *ShowExec) fetchShowIndex() error {
 				idx.Meta().Name.O,      // Key_name
 				i + 1,                  // Seq_in_index
 				colName,                // Column_name
-				"A",                    // Collation
+				collation,              // Collation
 				0,                      // Cardinality
 				subPart,                // Sub_part
 				nil,                    // Packed
@@ -1029,6 +1042,9 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 				buf.WriteString(table.OptionalFsp(&col.FieldType))
 			}
 		}
+		if col.SRID != nil {
+			fmt.Fprintf(buf, " /*!80003 SRID %d */", *col.SRID)
+		}
 		if ddl.IsAutoRandomColumnID(tableInfo, col.ID) {
 			buf.WriteString(fmt.Sprintf(" /*T![auto_rand] AUTO_RANDOM(%d) */", tableInfo.AutoRandomBits))
 		}
@@ -1065,6 +1081,8 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 			buf.WriteString("  PRIMARY KEY ")
 		} else if idxInfo.Unique {
 			fmt.Fprintf(buf, "  UNIQUE KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
+		} else if idxInfo.Tp == model.IndexTypeFulltext {
+			fmt.Fprintf(buf, "  FULLTEXT KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		} else {
 			fmt.Fprintf(buf, "  KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		}
@@ -1083,6 +1101,9 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 			cols = append(cols, colInfo)
 		}
 		fmt.Fprintf(buf, "(%s)", strings.Join(cols, ","))
+		if idxInfo.FulltextParser != "" {
+			fmt.Fprintf(buf, " /*!50100 WITH PARSER %s */", stringutil.Escape(idxInfo.FulltextParser, sqlMode))
+		}
 		if idxInfo.Invisible {
 			fmt.Fprintf(buf, ` /*!80000 INVISIBLE */`)
 		}
@@ -1671,13 +1692,145 @@ func (e *ShowExec) fetchShowPrivileges() error {
 }
 
 func (e *ShowExec) fetchShowTriggers() error {
+	if !e.is.SchemaExists(e.DBName) {
+		return ErrBadDB.GenWithStackByArgs(e.DBName)
+	}
+	tables := e.is.SchemaTables(e.DBName)
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Meta().Name.L < tables[j].Meta().Name.L })
+	for _, tbl := range tables {
+		tblInfo := tbl.Meta()
+		if len(tblInfo.Triggers) == 0 || !triggerVisible(e.ctx, e.DBName.L, tblInfo.Name.L) {
+			continue
+		}
+		for _, trigger := range tblInfo.Triggers {
+			e.appendRow([]interface{}{trigger.Name.O, trigger.Event.String(), tblInfo.Name.O, trigger.Body,
+				trigger.Time.String(), triggerCreated(e.ctx, trigger), trigger.SQLMode, triggerDefiner(trigger),
+				trigger.CharsetClient, trigger.CollationConnection, trigger.DBCollation})
+		}
+	}
+	return nil
+}
+
+func (e *ShowExec) fetchShowCreateTrigger() error {
+	var tblInfo *model.TableInfo
+	var trigger *model.TriggerInfo
+	for _, tbl := range e.is.SchemaTables(e.Table.Schema) {
+		if trigger = tbl.Meta().FindTrigger(e.Table.Name.L); trigger != nil {
+			tblInfo = tbl.Meta()
+			break
+		}
+	}
+	if trigger == nil || !triggerVisible(e.ctx, e.Table.Schema.L, tblInfo.Name.L) {
+		return dbterror.ErrTrgDoesNotExist
+	}
+	e.appendRow([]interface{}{trigger.Name.O, trigger.SQLMode, TriggerCreateStmt(tblInfo, trigger),
+		trigger.CharsetClient, trigger.CollationConnection, trigger.DBCollation, triggerCreated(e.ctx, trigger)})
 	return nil
 }
 
-func (e *ShowExec) fetchShowProcedureStatus() error {
+func (e *ShowExec) fetchShowProcedureStatus(ctx context.Context) error {
+	tp := ast.RoutineProcedure
+	if e.Tp == ast.ShowFunctionStatus {
+		tp = ast.RoutineFunction
+	}
+	routines, err := LoadRoutines(ctx, e.ctx, "type = %?", tp.String())
+	if err != nil {
+		return err
+	}
+	for _, r := range routines {
+		if !routineVisible(e.ctx, r) {
+			continue
+		}
+		e.appendRow([]interface{}{r.DB, r.Name, r.Type.String(), r.Definer, r.LastAltered, r.Created,
+			r.SecurityType, r.Comment, r.CharsetClient, r.CollationConnection, r.DBCollation})
+	}
 	return nil
 }
 
+func (e *ShowExec) fetchShowCreateProcedure(ctx context.Context) error {
+	tp := ast.RoutineProcedure
+	if e.Tp == ast.ShowCreateFunction {
+		tp = ast.RoutineFunction
+	}
+	r, err := LoadRoutine(ctx, e.ctx, tp, e.Table.Schema.L, e.Table.Name.O)
+	if err != nil {
+		return err
+	}
+	if r == nil || !routineVisible(e.ctx, r) {
+		return ErrSpDoesNotExist.GenWithStackByArgs(tp.String(), e.Table.Name.O)
+	}
+	e.appendRow([]interface{}{r.Name, r.SQLMode, r.CreateStmt(), r.CharsetClient, r.CollationConnection, r.DBCollation})
+	return nil
+}
+
+// routineVisible checks whether the routine can be seen by the current user, that is the user is the definer of the
+// routine or has any routine privilege on its schema.
+func routineVisible(sctx sessionctx.Context, r *RoutineInfo) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	user := sctx.GetSessionVars().User
+	if checker == nil || user == nil || user.String() == r.Definer {
+		return true
+	}
+	return checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, r.DB, "", "",
+		mysql.CreateRoutinePriv|mysql.AlterRoutinePriv|mysql.ExecutePriv)
+}
+
+func (e *ShowExec) fetchShowEvents(ctx context.Context) error {
+	if !e.is.SchemaExists(e.DBName) {
+		return ErrBadDB.GenWithStackByArgs(e.DBName)
+	}
+	events, err := domain.LoadEvents(ctx, e.ctx, "db = %?", e.DBName.L)
+	if err != nil {
+		return err
+	}
+	loc := e.ctx.GetSessionVars().Location()
+	for _, event := range events {
+		if !eventVisible(e.ctx, event) {
+			continue
+		}
+		tp, intervalValue, intervalField := "ONE TIME", interface{}(nil), interface{}(nil)
+		if event.IsRecurring() {
+			tp, intervalValue, intervalField = "RECURRING", event.IntervalValue, event.IntervalField
+		}
+		e.appendRow([]interface{}{event.DB, event.Name, event.TimeZone, event.Definer, tp, eventShowTime(event.ExecuteAt, loc),
+			intervalValue, intervalField, eventShowTime(event.Starts, loc), eventShowTime(event.Ends, loc), event.Status,
+			event.Originator, event.CharsetClient, event.CollationConnection, event.DBCollation})
+	}
+	return nil
+}
+
+func (e *ShowExec) fetchShowCreateEvent(ctx context.Context) error {
+	event, err := domain.LoadEvent(ctx, e.ctx, e.Table.Schema.L, e.Table.Name.O)
+	if err != nil {
+		return err
+	}
+	if event == nil || !eventVisible(e.ctx, event) {
+		return ErrEventDoesNotExist.GenWithStackByArgs(e.Table.Name.O)
+	}
+	e.appendRow([]interface{}{event.Name, event.SQLMode, event.TimeZone, event.CreateStmt(e.ctx.GetSessionVars().Location()),
+		event.CharsetClient, event.CollationConnection, event.DBCollation})
+	return nil
+}
+
+// eventVisible checks whether the event can be seen by the current user, that is the user is the definer of the
+// event or has the EVENT privilege on its schema.
+func eventVisible(sctx sessionctx.Context, event *domain.EventInfo) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	user := sctx.GetSessionVars().User
+	if checker == nil || user == nil || user.String() == event.Definer {
+		return true
+	}
+	return checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, event.DB, "", "", mysql.EventPriv)
+}
+
+// eventShowTime converts the time of an event to a DATETIME in the location loc, it's nil if the time is unset.
+func eventShowTime(t time.Time, loc *time.Location) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return types.NewTime(types.FromGoTime(t.In(loc)), mysql.TypeDatetime, 0)
+}
+
 func (e *ShowExec) fetchShowPlugins() error {
 	tiPlugins := plugin.GetAll()
 	for _, ps := range tiPlugins {
@@ -35,11 +35,7 @@ func (e *ShowExec) fetchShowStatsExtended() error {
 	dbs := do.InfoSchema().AllSchemas()
 	for _, db := range dbs {
 		for _, tblInfo := range db.Tables {
-			pi := tblInfo.GetPartitionInfo()
-			// Extended statistics for partitioned table is not supported now.
-			if pi != nil {
-				continue
-			}
+			// Only the global-level extended stats are shown for partitioned tables.
 			e.appendTableForStatsExtended(db.Name.L, tblInfo, h.GetTableStats(tblInfo))
 		}
 	}
@@ -83,10 +79,13 @@ func (e *ShowExec) appendTableForStatsExtended(dbName string, tbl *model.TableIn
 			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		case ast.StatsTypeDependency:
 			statsType = "dependency"
-			statsVal = item.StringVals
+			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		case ast.StatsTypeCardinality:
 			statsType = "cardinality"
-			statsVal = item.StringVals
+			statsVal = fmt.Sprintf("%f", item.ScalarVals)
+		case ast.StatsTypeHistogram:
+			statsType = "histogram"
+			statsVal = fmt.Sprintf("ndv: %f, topn: %d", item.ScalarVals, item.TopN.Num())
 		}
 		e.appendRow([]interface{}{
 			dbName,
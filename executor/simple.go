@@ -162,6 +162,18 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		return nil
 	case *ast.DropStatsStmt:
 		err = e.executeDropStats(x)
+	case *ast.CreateRoutineStmt:
+		err = e.executeCreateRoutine(ctx, x)
+	case *ast.DropRoutineStmt:
+		err = e.executeDropRoutine(ctx, x)
+	case *ast.CreateLoadableFunctionStmt:
+		err = e.executeCreateLoadableFunction(ctx, x)
+	case *ast.CreateEventStmt:
+		err = e.executeCreateEvent(ctx, x)
+	case *ast.AlterEventStmt:
+		err = e.executeAlterEvent(ctx, x)
+	case *ast.DropEventStmt:
+		err = e.executeDropEvent(ctx, x)
 	case *ast.SetRoleStmt:
 		err = e.executeSetRole(x)
 	case *ast.RevokeRoleStmt:
@@ -1716,6 +1728,8 @@ func (e *SimpleExec) executeAdmin(s *ast.AdminStmt) error {
 		return e.executeAdminReloadStatistics(s)
 	case ast.AdminFlushPlanCache:
 		return e.executeAdminFlushPlanCache(s)
+	case ast.AdminResetCardinalityCorrection:
+		return e.executeAdminResetCardinalityCorrection(s)
 	}
 	return nil
 }
@@ -1730,6 +1744,29 @@ func (e *SimpleExec) executeAdminReloadStatistics(s *ast.AdminStmt) error {
 	return domain.GetDomain(e.ctx).StatsHandle().ReloadExtendedStatistics()
 }
 
+func (e *SimpleExec) executeAdminResetCardinalityCorrection(s *ast.AdminStmt) error {
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+	if statsHandle == nil {
+		return nil
+	}
+	tableIDs := make([]int64, 0, len(s.Tables))
+	for _, tn := range s.Tables {
+		tbl, err := e.is.TableByName(tn.Schema, tn.Name)
+		if err != nil {
+			return err
+		}
+		tblInfo := tbl.Meta()
+		tableIDs = append(tableIDs, tblInfo.ID)
+		if pi := tblInfo.GetPartitionInfo(); pi != nil {
+			for _, def := range pi.Definitions {
+				tableIDs = append(tableIDs, def.ID)
+			}
+		}
+	}
+	statsHandle.CardCorrectionCache().Reset(tableIDs...)
+	return nil
+}
+
 func (e *SimpleExec) executeAdminFlushPlanCache(s *ast.AdminStmt) error {
 	if s.Tp != ast.AdminFlushPlanCache {
 		return errors.New("This AdminStmt is not ADMIN FLUSH PLAN_CACHE")
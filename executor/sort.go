@@ -58,10 +58,20 @@ type SortExec struct {
 	multiWayMerge *multiWayMerge
 	// spillAction save the Action for spill disk.
 	spillAction *chunk.SortAndSpillDiskAction
+
+	// concurrency is the number of the workers to sort and merge the rows in parallel, the rows are sorted in the
+	// current goroutine if it's not larger than 1.
+	concurrency int
+	// parallelMerge merges the partitions in parallel if there are more partitions than workers.
+	parallelMerge *parallelMerge
 }
 
 // Close implements the Executor Close interface.
 func (e *SortExec) Close() error {
+	if e.parallelMerge != nil {
+		e.parallelMerge.close(e)
+		e.parallelMerge = nil
+	}
 	for _, container := range e.partitionList {
 		err := container.Close()
 		if err != nil {
@@ -108,12 +118,19 @@ func (e *SortExec) Open(ctx context.Context) error {
 // 3. If memory quota is not triggered and child is consumed, sort these rows in memory as partition N.
 // 4. Merge sort if the count of partitions is larger than 1. If there is only one partition in step 4, it works
 //    just like in-memory sort before.
+// If the concurrency is larger than 1, the rows are dispatched to the workers which run the step 1-3 concurrently,
+// and the partitions are merged by the workers in parallel if there are more partitions than workers.
 func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if !e.fetched {
 		e.initCompareFuncs()
 		e.buildKeyColumns()
-		err := e.fetchRowChunks(ctx)
+		var err error
+		if e.concurrency > 1 {
+			err = e.fetchRowChunksParallel(ctx)
+		} else {
+			err = e.fetchRowChunks(ctx)
+		}
 		if err != nil {
 			return err
 		}
@@ -123,7 +140,11 @@ func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if len(e.partitionList) == 0 {
 		return nil
 	}
-	if len(e.partitionList) > 1 {
+	if e.concurrency > 1 && len(e.partitionList) > e.concurrency {
+		if err := e.parallelExternalSorting(ctx, req); err != nil {
+			return err
+		}
+	} else if len(e.partitionList) > 1 {
 		if err := e.externalSorting(req); err != nil {
 			return err
 		}
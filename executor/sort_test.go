@@ -16,8 +16,10 @@ package executor_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
@@ -135,3 +137,65 @@ func TestIssue16696(t *testing.T) {
 		}
 	}
 }
+
+func TestParallelSort(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.TempStoragePath = t.TempDir()
+	})
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("SET GLOBAL tidb_mem_oom_action = DEFAULT")
+	tk.MustExec("SET GLOBAL tidb_mem_oom_action='LOG'")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values ")
+	rows := make([][2]int, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		// The rows are inserted out of order, and a has duplicated values.
+		row := [2]int{(i * 7919) % 500, (i * 104729) % 2000}
+		buf.WriteString(fmt.Sprintf("(%v, %v)", row[0], row[1]))
+		rows = append(rows, row)
+	}
+	tk.MustExec(buf.String())
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0] || (rows[i][0] == rows[j][0] && rows[i][1] > rows[j][1])
+	})
+	expected := make([]string, 0, len(rows))
+	for _, row := range rows {
+		expected = append(expected, fmt.Sprintf("%v %v", row[0], row[1]))
+	}
+
+	tk.MustExec("set @@tidb_max_chunk_size=32")
+	tk.MustExec("set @@tidb_executor_concurrency=4")
+	query := "select /*+ read_from_storage(tikv[t]) */ a, b from t order by a, b desc"
+	tk.MustQuery(query).Check(testkit.Rows(expected...))
+
+	// The rows are spilled into more partitions than the workers, and the partitions are merged in parallel.
+	tk.MustExec("set @@tidb_mem_quota_query=1")
+	tk.MustQuery(query).Check(testkit.Rows(expected...))
+	require.Equal(t, int64(0), tk.Session().GetSessionVars().StmtCtx.MemTracker.BytesConsumed())
+	require.Equal(t, int64(0), tk.Session().GetSessionVars().StmtCtx.DiskTracker.BytesConsumed())
+	require.Greater(t, tk.Session().GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), int64(0))
+
+	// The merge workers exit when the executor is closed before all the rows are read.
+	rs, err := tk.Exec(query)
+	require.NoError(t, err)
+	req := rs.NewChunk(nil)
+	require.NoError(t, rs.Next(context.Background(), req))
+	require.Equal(t, 32, req.NumRows())
+	require.Equal(t, expected[0], fmt.Sprintf("%v %v", req.GetRow(0).GetInt64(0), req.GetRow(0).GetInt64(1)))
+	require.NoError(t, rs.Close())
+	require.Equal(t, int64(0), tk.Session().GetSessionVars().StmtCtx.MemTracker.BytesConsumed())
+
+	tk.MustExec("set @@tidb_mem_quota_query=default")
+	tk.MustExec("set @@tidb_executor_concurrency=1")
+	tk.MustQuery(query).Check(testkit.Rows(expected...))
+}
@@ -117,6 +117,9 @@ type TableReaderExecutor struct {
 	// If dummy flag is set, this is not a real TableReader, it just provides the KV ranges for UnionScan.
 	// Used by the temporary table, cached table.
 	dummy bool
+
+	// runtimeFilterProbe applies the runtime filters built by the parent hash join.
+	runtimeFilterProbe *runtimeFilterProbe
 }
 
 // Table implements the dataSourceExecutor interface.
@@ -169,7 +172,21 @@ func (e *TableReaderExecutor) Open(ctx context.Context) error {
 			return err
 		}
 	}
+	if e.runtimeFilterProbe != nil && !e.dummy {
+		// The request is sent after the runtime filters are built, see nextWithRuntimeFilters.
+		e.runtimeFilterProbe.reset()
+		if e.runtimeStats != nil {
+			e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl.RegisterStats(e.id, e.runtimeFilterProbe.stats)
+		}
+		e.resultHandler = nil
+		return nil
+	}
+	return e.sendRequest(ctx)
+}
 
+// sendRequest sends the DAG request, and reads the results by resultHandler.
+func (e *TableReaderExecutor) sendRequest(ctx context.Context) error {
+	var err error
 	e.resultHandler = &tableResultHandler{}
 	if e.feedback != nil && e.feedback.Hist != nil {
 		// EncodeInt don't need *statement.Context.
@@ -246,6 +263,13 @@ func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 		}
 		return tableName
 	}), e.ranges)
+	if e.runtimeFilterProbe != nil {
+		return e.nextWithRuntimeFilters(ctx, req)
+	}
+	return e.next(ctx, req)
+}
+
+func (e *TableReaderExecutor) next(ctx context.Context, req *chunk.Chunk) error {
 	if err := e.resultHandler.nextChunk(ctx, req); err != nil {
 		e.feedback.Invalidate()
 		return err
@@ -897,6 +897,54 @@ func TestMppApply(t *testing.T) {
 	tk.MustQuery("select /*+ agg_to_cop(), hash_agg()*/ count(*) from x1 where b > any (select x2.a from x2 where x1.a = x2.a);").Check(testkit.Rows("2"))
 }
 
+func TestMppExpand(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t, withMockTiFlash(2))
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists x1, x2")
+	tk.MustExec("create table x1(a int, b int, c int)")
+	tk.MustExec("create table x2(a int, b int)")
+	for _, name := range []string{"x1", "x2"} {
+		tk.MustExec("alter table " + name + " set tiflash replica 1")
+		tb := external.GetTableByName(t, tk, "test", name)
+		err := domain.GetDomain(tk.Session()).DDL().UpdateTableReplicaInfo(tk.Session(), tb.Meta().ID, true)
+		require.NoError(t, err)
+	}
+	tk.MustExec("insert into x1 values (1, 1, 1), (1, 2, 2), (2, 1, 3), (2, null, 4), (3, 3, 5)")
+	tk.MustExec("insert into x2 values (1, 10), (2, 20), (3, 30)")
+
+	sqls := []string{
+		"select a, b, count(c), sum(c) from x1 group by a, b with rollup",
+		"select a, b, count(*) from x1 group by cube(a, b)",
+		"select x1.a, x2.b, sum(x1.c) from x1 join x2 on x1.a = x2.a group by x1.a, x2.b with rollup",
+		// TiFlash doesn't support the GROUPING function, so the aggregation is executed in TiDB.
+		"select a, b, count(c), grouping(a, b) from x1 group by a, b with rollup",
+	}
+	tk.MustExec("set @@session.tidb_isolation_read_engines = 'tikv'")
+	expected := make([][][]interface{}, 0, len(sqls))
+	for _, sql := range sqls {
+		expected = append(expected, tk.MustQuery(sql).Sort().Rows())
+	}
+
+	tk.MustExec("set @@session.tidb_isolation_read_engines = 'tiflash'")
+	tk.MustExec("set @@session.tidb_enforce_mpp = 1")
+	for i, sql := range sqls {
+		rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		if i < 3 {
+			require.Contains(t, fmt.Sprintf("%v", rows), "mpp[tiflash]  grouping sets")
+		}
+		tk.MustQuery(sql).Sort().Check(expected[i])
+	}
+
+	// The child of the Expand is not executed in the same MPP task if it receives the shuffled data.
+	tk.MustExec("set @@session.tidb_broadcast_join_threshold_count = 0")
+	tk.MustExec("set @@session.tidb_broadcast_join_threshold_size = 0")
+	rows := tk.MustQuery("explain format = 'brief' " + sqls[2]).Rows()
+	require.NotContains(t, fmt.Sprintf("%v", rows), "mpp[tiflash]  grouping sets")
+	tk.MustQuery(sqls[2]).Sort().Check(expected[2])
+}
+
 func TestTiFlashVirtualColumn(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t, withMockTiFlash(2))
 	defer clean()
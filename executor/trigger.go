@@ -0,0 +1,213 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessiontxn"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// TriggerCreateStmt rebuilds the CREATE statement of the trigger, in the format of SHOW CREATE TRIGGER.
+func TriggerCreateStmt(tblInfo *model.TableInfo, trigger *model.TriggerInfo) string {
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	sb.WriteString("CREATE ")
+	if trigger.Definer != nil {
+		sb.WriteString("DEFINER=")
+		terror.Log(trigger.Definer.Restore(restoreCtx))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("TRIGGER ")
+	restoreCtx.WriteName(trigger.Name.O)
+	sb.WriteString(" ")
+	sb.WriteString(trigger.Time.String())
+	sb.WriteString(" ")
+	sb.WriteString(trigger.Event.String())
+	sb.WriteString(" ON ")
+	restoreCtx.WriteName(tblInfo.Name.O)
+	sb.WriteString(" FOR EACH ROW ")
+	sb.WriteString(trigger.Body)
+	return sb.String()
+}
+
+// triggerVisible checks whether the triggers of the table can be seen by the current user.
+func triggerVisible(sctx sessionctx.Context, db, tbl string) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	if checker == nil || sctx.GetSessionVars().User == nil {
+		return true
+	}
+	return checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, db, tbl, "", mysql.TriggerPriv)
+}
+
+func triggerDefiner(trigger *model.TriggerInfo) string {
+	if trigger.Definer == nil {
+		return ""
+	}
+	return trigger.Definer.String()
+}
+
+// triggerCreated returns the creation time of the trigger in the time zone of the session.
+func triggerCreated(sctx sessionctx.Context, trigger *model.TriggerInfo) types.Time {
+	return types.NewTime(types.FromGoTime(trigger.Created.In(sctx.GetSessionVars().Location())), mysql.TypeTimestamp, 2)
+}
+
+// execTriggers executes the triggers of the table which are activated by the event at the action time. The rows are
+// the values of the columns of the table in the order of the column offsets, the triggers executed before the event
+// may change the new row.
+func execTriggers(ctx context.Context, sctx sessionctx.Context, t table.Table, tm model.TriggerTime, event model.TriggerEvent, oldRow, newRow []types.Datum) error {
+	tblInfo := t.Meta()
+	var db *model.DBInfo
+	for _, trigger := range tblInfo.Triggers {
+		if trigger.Time != tm || trigger.Event != event {
+			continue
+		}
+		if db == nil {
+			var ok bool
+			if db, ok = sessiontxn.GetTxnManager(sctx).GetTxnInfoSchema().SchemaByTable(tblInfo); !ok {
+				return errors.Errorf("the schema of table %s is not found", tblInfo.Name.O)
+			}
+		}
+		exec, ok := sctx.(sqlexec.TriggerExecutor)
+		if !ok {
+			return errors.New("triggers are not supported by the session")
+		}
+		if err := exec.ExecTrigger(ctx, db.Name.O, tblInfo, trigger, oldRow, newRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecSubStmt executes a statement inside the statement being executed, such as a statement of a trigger. The
+// statement shares the transaction and the uncommitted changes with the outer statement, so its changes are rolled
+// back together with the outer statement. It returns the rows if the statement returns a result set, and the warnings
+// of the statement, which are appended to the outer statement as well.
+func ExecSubStmt(ctx context.Context, sctx sessionctx.Context, stmtNode ast.StmtNode) (fields []*ast.ResultField, rows []chunk.Row, warnings []stmtctx.SQLWarn, err error) {
+	sessVars := sctx.GetSessionVars()
+	outerStmtCtx := sessVars.StmtCtx
+	if err = ResetContextOfStmt(sctx, stmtNode); err != nil {
+		sessVars.StmtCtx = outerStmtCtx
+		return nil, nil, nil, err
+	}
+	defer func() {
+		sc := sessVars.StmtCtx
+		if sc.MemTracker != nil {
+			sc.MemTracker.Detach()
+		}
+		if sc.DiskTracker != nil {
+			sc.DiskTracker.Detach()
+		}
+		warnings = sc.GetWarnings()
+		outerStmtCtx.AppendWarnings(warnings)
+		sessVars.StmtCtx = outerStmtCtx
+	}()
+
+	stmt, err := (&Compiler{Ctx: sctx}).Compile(ctx, stmtNode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err = checkTriggerTables(stmt); err != nil {
+		return nil, nil, nil, err
+	}
+	e, err := stmt.buildExecutor()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = e.Open(ctx)
+	if err == nil {
+		rows, err = drainSubStmt(ctx, e)
+	}
+	if closeErr := e.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if e.Schema().Len() > 0 {
+		fields = colNames2ResultFields(stmt.Plan.Schema(), stmt.OutputNames, sessVars.CurrentDB)
+	}
+	return fields, rows, nil, nil
+}
+
+func drainSubStmt(ctx context.Context, e Executor) ([]chunk.Row, error) {
+	if e.Schema().Len() == 0 {
+		return nil, Next(ctx, e, newFirstChunk(e))
+	}
+	var rows []chunk.Row
+	for {
+		chk := newFirstChunk(e)
+		if err := Next(ctx, e, chk); err != nil {
+			return nil, err
+		}
+		if chk.NumRows() == 0 {
+			return rows, nil
+		}
+		iter := chunk.NewIterator4Chunk(chk)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			rows = append(rows, row)
+		}
+	}
+}
+
+// checkTriggerTables checks that the statement executed by a trigger doesn't modify the tables whose triggers are
+// being executed.
+func checkTriggerTables(stmt *ExecStmt) error {
+	triggerTables := stmt.Ctx.GetSessionVars().TriggerTables
+	if len(triggerTables) == 0 {
+		return nil
+	}
+	var tableIDs []int64
+	switch x := stmt.Plan.(type) {
+	case *plannercore.Insert:
+		tableIDs = append(tableIDs, x.Table.Meta().ID)
+	case *plannercore.Update:
+		for _, info := range x.TblColPosInfos {
+			tableIDs = append(tableIDs, info.TblID)
+		}
+	case *plannercore.Delete:
+		for _, info := range x.TblColPosInfos {
+			tableIDs = append(tableIDs, info.TblID)
+		}
+	}
+	for _, id := range tableIDs {
+		for _, triggerTable := range triggerTables {
+			if id != triggerTable {
+				continue
+			}
+			name := ""
+			if tbl, ok := stmt.InfoSchema.TableByID(id); ok {
+				name = tbl.Meta().Name.O
+			}
+			return ErrCantUpdateUsedTableInSfOrTrg.GenWithStackByArgs(name)
+		}
+	}
+	return nil
+}
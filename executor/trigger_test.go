@@ -0,0 +1,113 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDropTrigger(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("create view v as select a from t")
+	tk.MustExec("create trigger tr1 before insert on t for each row set new.b = new.a + 1")
+	tk.MustExec("create trigger tr2 after update on t for each row insert into t2 values (old.a)")
+	tk.MustExec("create trigger tr3 before insert on t for each row precedes tr1 set new.b = 0")
+	tk.MustExec("create trigger tr4 before insert on t for each row follows tr1 set new.b = 1")
+	tk.MustGetErrCode("create trigger tr1 before delete on t2 for each row set @x = 1", errno.ErrTrgAlreadyExists)
+	tk.MustExec("create trigger if not exists tr1 before delete on t2 for each row set @x = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1359 Trigger already exists"))
+	tk.MustGetErrCode("create trigger tr5 before insert on t for each row follows tr2 set @x = 1", errno.ErrReferencedTrgDoesNotExist)
+	tk.MustGetErrCode("create trigger tr5 before insert on v for each row set @x = 1", errno.ErrTrgOnViewOrTempTable)
+	tk.MustGetErrCode("create trigger tr5 before insert on no_such_table for each row set @x = 1", errno.ErrNoSuchTable)
+	tk.MustGetErrCode("create trigger mysql.tr5 before insert on test.t for each row set @x = 1", errno.ErrTrgInWrongSchema)
+	tk.MustGetErrCode("create trigger tr5 before delete on t for each row set @x = new.a", errno.ErrTrgNoSuchRowInTrg)
+	tk.MustGetErrCode("create trigger tr5 before insert on t for each row set @x = old.a", errno.ErrTrgNoSuchRowInTrg)
+	tk.MustGetErrCode("create trigger tr5 after insert on t for each row set new.a = 1", errno.ErrTrgCantChangeRow)
+	tk.MustGetErrCode("create trigger tr5 before update on t for each row set old.a = 1", errno.ErrTrgCantChangeRow)
+	tk.MustGetErrCode("create trigger tr5 before insert on t for each row set new.c = 1", errno.ErrBadField)
+	tk.MustGetErrCode("create trigger tr5 before insert on t for each row select 1", errno.ErrSpNoRetset)
+	tk.MustGetErrCode("create trigger tr5 before insert on t for each row commit", errno.ErrCommitNotAllowedInSfOrTrg)
+
+	tk.MustQuery("show triggers").CheckAt([]int{0, 1, 2, 3, 4, 7}, testkit.RowsWithSep("|",
+		"tr3|INSERT|t|set new.b = 0|BEFORE|root@%",
+		"tr1|INSERT|t|set new.b = new.a + 1|BEFORE|root@%",
+		"tr4|INSERT|t|set new.b = 1|BEFORE|root@%",
+		"tr2|UPDATE|t|insert into t2 values (old.a)|AFTER|root@%"))
+	tk.MustQuery("show triggers like 't2'").Check(testkit.Rows())
+	tk.MustQuery("show create trigger tr2").CheckAt([]int{0, 2, 3, 4, 5}, testkit.RowsWithSep("|",
+		"tr2|CREATE DEFINER=`root`@`%` TRIGGER `tr2` AFTER UPDATE ON `t` FOR EACH ROW insert into t2 values (old.a)|utf8mb4|utf8mb4_bin|utf8mb4_bin"))
+	require.True(t, terror.ErrorEqual(tk.QueryToErr("show create trigger no_such_trigger"), dbterror.ErrTrgDoesNotExist))
+	tk.MustQuery("select trigger_schema, trigger_name, event_manipulation, event_object_table, action_order, action_statement, action_timing " +
+		"from information_schema.triggers order by trigger_name").Check(testkit.RowsWithSep("|",
+		"test|tr1|INSERT|t|2|set new.b = new.a + 1|BEFORE",
+		"test|tr2|UPDATE|t|1|insert into t2 values (old.a)|AFTER",
+		"test|tr3|INSERT|t|1|set new.b = 0|BEFORE",
+		"test|tr4|INSERT|t|3|set new.b = 1|BEFORE"))
+
+	tk.MustGetErrCode("rename table t to mysql.t", errno.ErrTrgInWrongSchema)
+	tk.MustExec("rename table t to t1")
+	tk.MustQuery("select distinct event_object_table from information_schema.triggers").Check(testkit.Rows("t1"))
+	tk.MustExec("create table t3 like t1")
+	tk.MustQuery("show triggers like 't3'").Check(testkit.Rows())
+
+	tk.MustExec("drop trigger tr1")
+	tk.MustGetErrCode("drop trigger tr1", errno.ErrTrgDoesNotExist)
+	tk.MustExec("drop trigger if exists tr1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1360 Trigger does not exist"))
+	tk.MustExec("drop trigger test.tr2")
+	// The triggers are dropped with the table.
+	tk.MustExec("drop table t1")
+	tk.MustQuery("select count(*) from information_schema.triggers").Check(testkit.Rows("0"))
+}
+
+func TestTriggerPrivileges(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create trigger tr before insert on t for each row set @x = 1")
+	tk.MustExec("create user 'u1'@'%', 'u2'@'%'")
+	tk.MustExec("grant trigger on test.t to 'u1'@'%'")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("create trigger test.tr1 after insert on test.t for each row set @x = 2")
+	tk1.MustGetErrCode("create definer = 'root'@'%' trigger test.tr2 after insert on test.t for each row set @x = 2", errno.ErrSpecificAccessDenied)
+	tk1.MustQuery("show triggers from test").CheckAt([]int{0, 7}, testkit.Rows("tr root@%", "tr1 u1@%"))
+	tk1.MustExec("drop trigger test.tr")
+
+	// The triggers are invisible to the users without the TRIGGER privilege.
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil))
+	tk2.MustGetErrCode("create trigger test.tr2 after insert on test.t for each row set @x = 2", errno.ErrTableaccessDenied)
+	tk2.MustGetErrCode("drop trigger test.tr1", errno.ErrTableaccessDenied)
+	tk2.MustQuery("select count(*) from information_schema.triggers").Check(testkit.Rows("0"))
+	require.True(t, terror.ErrorEqual(tk2.QueryToErr("show create trigger test.tr1"), dbterror.ErrTrgDoesNotExist))
+}
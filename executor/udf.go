@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+var udfRetTypes = map[types.EvalType]ast.LoadableFunctionRetType{
+	types.ETString:  ast.LoadableFunctionRetString,
+	types.ETReal:    ast.LoadableFunctionRetReal,
+	types.ETInt:     ast.LoadableFunctionRetInteger,
+	types.ETDecimal: ast.LoadableFunctionRetDecimal,
+}
+
+// pluginNameOfSOName gets the name of the function plugin from the SONAME clause,
+// which can be the plugin name or the plugin ID, with or without the library suffix.
+func pluginNameOfSOName(soName string) string {
+	soName = strings.TrimSuffix(soName, plugin.LibrarySuffix)
+	if name, _, err := plugin.ID(soName).Decode(); err == nil {
+		return name
+	}
+	return soName
+}
+
+func (e *SimpleExec) executeCreateLoadableFunction(ctx context.Context, s *ast.CreateLoadableFunctionStmt) error {
+	if expression.IsFunctionSupported(s.Name.L) {
+		return expression.ErrNativeFctNameCollision.GenWithStackByArgs(s.Name.O)
+	}
+	if strings.ContainsAny(s.SOName, "/\\") {
+		return ErrUDFNoPaths
+	}
+	pluginName := pluginNameOfSOName(s.SOName)
+	udf, err := expression.GetLoadedUDF(pluginName, s.Name.L)
+	if err != nil {
+		return err
+	}
+	if udfRetTypes[udf.RetType] != s.Returns {
+		return expression.ErrCantInitializeUDF.GenWithStackByArgs(s.Name.O, "the function returns "+udfRetTypes[udf.RetType].String())
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnUDF)
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "SELECT 1 FROM mysql.func WHERE name = %?", s.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		err = ErrUDFExists.GenWithStackByArgs(s.Name.O)
+		if s.IfNotExists {
+			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	_, _, err = exec.ExecRestrictedSQL(ctx, nil,
+		"INSERT INTO mysql.func (name, ret, dl, type) VALUES (%?, %?, %?, 'function')", s.Name.L, int(s.Returns), pluginName)
+	if terror.ErrorEqual(err, kv.ErrKeyExists) {
+		// The function is created concurrently.
+		return ErrUDFExists.GenWithStackByArgs(s.Name.O)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return domain.GetDomain(e.ctx).NotifyUpdateUDFCache()
+}
+
+func (e *SimpleExec) executeDropLoadableFunction(ctx context.Context, s *ast.DropRoutineStmt) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnUDF)
+	se, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(ctx, se)
+	_, _, err = se.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession},
+		"DELETE FROM mysql.func WHERE name = %?", s.Name.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if se.GetSessionVars().StmtCtx.AffectedRows() == 0 {
+		err = ErrFunctionNotDefined.GenWithStackByArgs(s.Name.Name.O)
+		if s.IfExists {
+			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	return domain.GetDomain(e.ctx).NotifyUpdateUDFCache()
+}
@@ -16,15 +16,20 @@ package executor
 
 import (
 	"context"
+	"math"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/mathutil"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // WindowExec is the executor for window functions.
@@ -36,45 +41,97 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// rows buffers the rows of the current partition, which may be spilled to the disk.
+	rows *windowRows
+	// outputIdx is the index of the next row to output in the current partition.
+	outputIdx uint64
+	// childColIdxs are the indexes of the child columns in the output.
+	childColIdxs []int
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.executed = false
+	e.outputIdx = 0
+	e.childResult = newFirstChunk(e.children[0])
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.childColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.childColIdxs = append(e.childColIdxs, col.Index)
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.rows = newWindowRows(e.ctx, retTypes(e.children[0]), e.maxChunkSize, e.memTracker, e.diskTracker)
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
+	if e.rows != nil {
+		terror.Log(e.rows.close())
+		e.rows = nil
+	}
+	e.childResult = nil
 	return errors.Trace(e.baseExecutor.Close())
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
-		err := e.consumeOneGroup(ctx)
-		if err != nil {
-			e.executed = true
+	for !chk.IsFull() {
+		if e.outputIdx == e.rows.numRows() {
+			if e.executed {
+				break
+			}
+			if err := e.finishGroup(); err != nil {
+				e.executed = true
+				return err
+			}
+			if err := e.consumeOneGroup(ctx); err != nil {
+				e.executed = true
+				return err
+			}
+			continue
+		}
+		n := mathutil.Min(uint64(chk.RequiredRows()-chk.NumRows()), e.rows.numRows()-e.outputIdx)
+		for i := e.outputIdx; i < e.outputIdx+n; i++ {
+			row, err := e.rows.getRow(i)
+			if err != nil {
+				return err
+			}
+			chk.AppendPartialRowByColIdxs(0, row, e.childColIdxs)
+		}
+		if err := e.processor.appendResult2Chunk(e.ctx, e.rows, chk, int(n)); err != nil {
 			return err
 		}
-	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
+		e.outputIdx += n
 	}
 	return nil
 }
 
-func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
+// finishGroup releases the rows of the current group, whose results have been all output.
+func (e *WindowExec) finishGroup() error {
+	if e.outputIdx == 0 {
+		return nil
+	}
+	e.processor.resetPartialResult()
+	err := e.rows.nextPartition(e.outputIdx)
+	e.outputIdx = 0
+	return err
 }
 
 func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
 	if e.groupChecker.isExhausted() {
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
@@ -82,7 +139,7 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			return nil
 		}
 		_, err = e.groupChecker.splitIntoGroups(e.childResult)
 		if err != nil {
@@ -90,8 +147,8 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 	}
 	begin, end := e.groupChecker.getNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
+	if err := e.rows.add(e.childResult, begin, end); err != nil {
+		return err
 	}
 
 	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
@@ -102,7 +159,7 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			break
 		}
 
 		isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
@@ -112,86 +169,43 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 
 		if isFirstGroupSameAsPrev {
 			begin, end = e.groupChecker.getNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
+			if err := e.rows.add(e.childResult, begin, end); err != nil {
+				return err
 			}
 			meetLastGroup = end == e.childResult.NumRows()
 		}
 	}
-	return e.consumeGroupRows(groupRows)
-}
-
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
-	if remainingRowsInGroup == 0 {
-		return nil
-	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := mathutil.Min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
-
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.ctx, groupRows)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		_, err = e.processor.appendResult2Chunk(e.ctx, groupRows, e.resultChunks[i], remained)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if remainingRowsInGroup == 0 {
-			e.processor.resetPartialResult()
-			break
-		}
-	}
-	return nil
+	return e.processor.consumeGroupRows(e.ctx, e.rows)
 }
 
 func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+	// The rows in childResult have been copied into e.rows, so it can be reused.
+	err = Next(ctx, e.children[0], e.childResult)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
-		return true, nil
-	}
-
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
-	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
-
-	e.childResult = childResult
-	return false, nil
+	return e.childResult.NumRows() == 0, nil
 }
 
-func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
+// setRowGetters lets the window functions which access the rows of the partition by their indexes, such as LEAD and
+// LAG, read the rows buffered by the window executor through getRow.
+func setRowGetters(windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult, getRow func(i uint64) (chunk.Row, error)) {
+	for i, windowFunc := range windowFuncs {
+		if rowsAccessWindowFunc, ok := windowFunc.(aggfuncs.RowsAccessWindowFunc); ok {
+			rowsAccessWindowFunc.SetRowGetter(partialResults[i], getRow)
 		}
 	}
-	return nil
 }
 
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
 	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
-	// appendResult2Chunk appends the final results to chunk.
+	consumeGroupRows(ctx sessionctx.Context, rows *windowRows) error
+	// appendResult2Chunk appends the final results of the next remained rows in the partition to chunk.
 	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	appendResult2Chunk(ctx sessionctx.Context, rows *windowRows, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -201,30 +215,31 @@ type aggWindowProcessor struct {
 	partialResults []aggfuncs.PartialResult
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	for i, windowFunc := range p.windowFuncs {
-		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
-		if err != nil {
-			return nil, err
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowRows) error {
+	return rows.forEachBatch(0, rows.numRows(), func(batch []chunk.Row, _ uint64) error {
+		for i, windowFunc := range p.windowFuncs {
+			// @todo Add memory trace
+			_, err := windowFunc.UpdatePartialResult(ctx, batch, p.partialResults[i])
+			if err != nil {
+				return err
+			}
 		}
-	}
-	rows = rows[:0]
-	return rows, nil
+		return nil
+	})
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, _ *windowRows, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -287,88 +302,17 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowRows) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
-	var (
-		err                      error
-		initializedSlidingWindow bool
-		start                    uint64
-		end                      uint64
-		lastStart                uint64
-		lastEnd                  uint64
-		shiftStart               uint64
-		shiftEnd                 uint64
-	)
-	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
-		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
-			slidingWindowAggFuncs[i] = slidingWindowAggFunc
-		}
-	}
-	for ; remained > 0; lastStart, lastEnd = start, end {
-		start = p.getStartOffset(numRows)
-		end = p.getEndOffset(numRows)
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowRows, chk *chunk.Chunk, remained int) error {
+	numRows := rows.numRows()
+	return appendFrameResult2Chunk(ctx, p.windowFuncs, p.partialResults, rows, chk, remained, func() (start, end uint64, err error) {
+		start, end = p.getStartOffset(numRows), p.getEndOffset(numRows)
 		p.curRowIdx++
-		remained--
-		shiftStart = start - lastStart
-		shiftEnd = end - lastEnd
-		if start >= end {
-			for i, windowFunc := range p.windowFuncs {
-				slidingWindowAggFunc := slidingWindowAggFuncs[i]
-				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-					if err != nil {
-						return nil, err
-					}
-				}
-				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
-				if err != nil {
-					return nil, err
-				}
-			}
-			continue
-		}
-
-		for i, windowFunc := range p.windowFuncs {
-			slidingWindowAggFunc := slidingWindowAggFuncs[i]
-			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-			} else {
-				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
-				// whether elements inside deque are out of current window.
-				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
-					minMaxSlidingWindowAggFunc.SetWindowStart(start)
-				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
-			}
-			if err != nil {
-				return nil, err
-			}
-			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
-			if err != nil {
-				return nil, err
-			}
-			if slidingWindowAggFunc == nil {
-				windowFunc.ResetPartialResult(p.partialResults[i])
-			}
-		}
-		if !initializedSlidingWindow {
-			initializedSlidingWindow = true
-		}
-	}
-	for i, windowFunc := range p.windowFuncs {
-		windowFunc.ResetPartialResult(p.partialResults[i])
-	}
-	return rows, nil
+		return start, end, nil
+	})
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -388,16 +332,23 @@ type rangeFrameWindowProcessor struct {
 	expectedCmpResult int64
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows *windowRows) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
+	numRows := rows.numRows()
 	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
+		row, err := rows.getRow(p.lastStartOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], row, curRow)
 			if err != nil {
 				return 0, err
 			}
@@ -414,16 +365,23 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows *windowRows) (uint64, error) {
+	numRows := rows.numRows()
 	if p.end.UnBounded {
 		return numRows, nil
 	}
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastEndOffset < numRows; p.lastEndOffset++ {
+		row, err := rows.getRow(p.lastEndOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], curRow, row)
 			if err != nil {
 				return 0, err
 			}
@@ -440,7 +398,193 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowRows, chk *chunk.Chunk, remained int) error {
+	return appendFrameResult2Chunk(ctx, p.windowFuncs, p.partialResults, rows, chk, remained, func() (start, end uint64, err error) {
+		start, err = p.getStartOffset(ctx, rows)
+		if err != nil {
+			return 0, 0, err
+		}
+		end, err = p.getEndOffset(ctx, rows)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.curRowIdx++
+		return start, end, nil
+	})
+}
+
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowRows) error {
+	return nil
+}
+
+func (p *rangeFrameWindowProcessor) resetPartialResult() {
+	p.curRowIdx = 0
+	p.lastStartOffset = 0
+	p.lastEndOffset = 0
+}
+
+// groupsFrameWindowProcessor processes the GROUPS frames, whose bounds are the numbers of the peer groups preceding or
+// following the peer group of the current row.
+type groupsFrameWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+	start          *core.FrameBound
+	end            *core.FrameBound
+	curRowIdx      uint64
+	peerGroups     *peerGroups
+}
+
+func (p *groupsFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowRows) error {
+	return p.peerGroups.scan(rows, rows.numRows())
+}
+
+func (p *groupsFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowRows, chk *chunk.Chunk, remained int) error {
+	return appendFrameResult2Chunk(ctx, p.windowFuncs, p.partialResults, rows, chk, remained, func() (start, end uint64, err error) {
+		p.peerGroups.locate(p.curRowIdx)
+		start, end = p.peerGroups.getStartOffset(p.start), p.peerGroups.getEndOffset(p.end)
+		p.curRowIdx++
+		return start, end, nil
+	})
+}
+
+func (p *groupsFrameWindowProcessor) resetPartialResult() {
+	p.curRowIdx = 0
+	p.peerGroups.reset()
+}
+
+// peerGroups tracks the peer groups of the rows in a partition, the peers have the same values of the ORDER BY items.
+// If there is no ORDER BY items, all the rows in the partition are peers.
+type peerGroups struct {
+	colIdxs  []int
+	cmpFuncs []chunk.CompareFunc
+	// starts are the indexes of the first rows of the peer groups found so far.
+	starts []uint64
+	// numRows is the number of the rows scanned.
+	numRows uint64
+	// lastRow keeps the last scanned row, since it may be dropped before the next row is scanned.
+	lastRow *chunk.Chunk
+	// curGroup is the index of the peer group of the current row.
+	curGroup uint64
+}
+
+func newPeerGroups(fieldTypes []*types.FieldType, orderByCols []*expression.Column) *peerGroups {
+	p := &peerGroups{
+		colIdxs:  make([]int, 0, len(orderByCols)),
+		cmpFuncs: make([]chunk.CompareFunc, 0, len(orderByCols)),
+		lastRow:  chunk.New(fieldTypes, 1, 1),
+	}
+	for _, col := range orderByCols {
+		p.colIdxs = append(p.colIdxs, col.Index)
+		p.cmpFuncs = append(p.cmpFuncs, chunk.GetCompareFunc(col.RetType))
+	}
+	return p
+}
+
+func (p *peerGroups) isPeer(a, b chunk.Row) bool {
+	for i, colIdx := range p.colIdxs {
+		if p.cmpFuncs[i](a, colIdx, b, colIdx) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// scan finds the peer groups of the rows before the numRows-th row of the partition.
+func (p *peerGroups) scan(rows *windowRows, numRows uint64) error {
+	if p.numRows < numRows {
+		prev := p.lastRow.GetRow(0)
+		for i := p.numRows; i < numRows; i++ {
+			row, err := rows.getRow(i)
+			if err != nil {
+				return err
+			}
+			if i == 0 || !p.isPeer(prev, row) {
+				p.starts = append(p.starts, i)
+			}
+			prev = row
+		}
+		p.lastRow.Reset()
+		p.lastRow.AppendRow(prev)
+		p.numRows = numRows
+	}
+	return nil
+}
+
+// locate moves to the peer group of the rowIdx-th row, the rows are located in order.
+func (p *peerGroups) locate(rowIdx uint64) {
+	for p.curGroup+1 < uint64(len(p.starts)) && p.starts[p.curGroup+1] <= rowIdx {
+		p.curGroup++
+	}
+}
+
+// groupStart returns the index of the first row of the n-th peer group after the current one, or the number of the
+// scanned rows if the peer group is not scanned yet or doesn't exist.
+func (p *peerGroups) groupStart(n uint64) uint64 {
+	if n >= uint64(len(p.starts))-p.curGroup {
+		return p.numRows
+	}
+	return p.starts[p.curGroup+n]
+}
+
+// groupStartBefore returns the index of the first row of the n-th peer group before the current one.
+func (p *peerGroups) groupStartBefore(n uint64) uint64 {
+	if n > p.curGroup {
+		return 0
+	}
+	return p.starts[p.curGroup-n]
+}
+
+// getStartOffset returns the index of the first row in the frame of the current row.
+func (p *peerGroups) getStartOffset(bound *core.FrameBound) uint64 {
+	if bound.UnBounded {
+		return 0
+	}
+	switch bound.Type {
+	case ast.Preceding:
+		return p.groupStartBefore(bound.Num)
+	case ast.Following:
+		return p.groupStart(bound.Num)
+	default: // ast.CurrentRow
+		return p.groupStart(0)
+	}
+}
+
+// getEndOffset returns the index after the last row in the frame of the current row. If the end of the frame is not
+// scanned yet, it returns the number of the scanned rows, so the callers can tell whether more rows are needed.
+func (p *peerGroups) getEndOffset(bound *core.FrameBound) uint64 {
+	if bound.UnBounded {
+		return p.numRows
+	}
+	switch bound.Type {
+	case ast.Preceding:
+		if bound.Num == 0 {
+			return p.groupStart(1)
+		}
+		if bound.Num > p.curGroup {
+			return 0
+		}
+		return p.groupStartBefore(bound.Num - 1)
+	case ast.Following:
+		if bound.Num == math.MaxUint64 {
+			return p.numRows
+		}
+		return p.groupStart(bound.Num + 1)
+	default: // ast.CurrentRow
+		return p.groupStart(1)
+	}
+}
+
+func (p *peerGroups) reset() {
+	p.starts = p.starts[:0]
+	p.numRows = 0
+	p.curGroup = 0
+}
+
+// appendFrameResult2Chunk appends the results of the next remained rows to chk, getFrame returns the frame [start, end)
+// of the next row, whose start and end never decrease, so the functions implementing SlidingWindowAggFunc slide the
+// frame rather than recompute it.
+func appendFrameResult2Chunk(ctx sessionctx.Context, windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult,
+	rows *windowRows, chk *chunk.Chunk, remained int, getFrame func() (start, end uint64, err error)) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -451,83 +595,83 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		shiftStart               uint64
 		shiftEnd                 uint64
 	)
-	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
+	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(windowFuncs))
+	for i, windowFunc := range windowFuncs {
 		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
 			slidingWindowAggFuncs[i] = slidingWindowAggFunc
 		}
 	}
 	for ; remained > 0; lastStart, lastEnd = start, end {
-		start, err = p.getStartOffset(ctx, rows)
-		if err != nil {
-			return nil, err
-		}
-		end, err = p.getEndOffset(ctx, rows)
+		start, end, err = getFrame()
 		if err != nil {
-			return nil, err
+			return err
 		}
-		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
 		shiftEnd = end - lastEnd
 		if start >= end {
-			for i, windowFunc := range p.windowFuncs {
+			for i, windowFunc := range windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = slidingWindowAggFunc.Slide(ctx, rows.getRowOrNull, lastStart, lastEnd, shiftStart, shiftEnd, partialResults[i])
+					if err == nil {
+						err = rows.takeErr()
+					}
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
-				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+				err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
 		}
 
-		for i, windowFunc := range p.windowFuncs {
+		for i, windowFunc := range windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-			} else {
-				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-					minMaxSlidingWindowAggFunc.SetWindowStart(start)
+				err = slidingWindowAggFunc.Slide(ctx, rows.getRowOrNull, lastStart, lastEnd, shiftStart, shiftEnd, partialResults[i])
+				if err == nil {
+					err = rows.takeErr()
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+			} else {
+				err = updatePartialResultOfFrame(ctx, windowFunc, rows, start, end, partialResults[i])
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
-			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+			err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
-				windowFunc.ResetPartialResult(p.partialResults[i])
+				windowFunc.ResetPartialResult(partialResults[i])
 			}
 		}
 		if !initializedSlidingWindow {
 			initializedSlidingWindow = true
 		}
 	}
-	for i, windowFunc := range p.windowFuncs {
-		windowFunc.ResetPartialResult(p.partialResults[i])
+	for i, windowFunc := range windowFuncs {
+		windowFunc.ResetPartialResult(partialResults[i])
 	}
-	return rows, nil
-}
-
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+	return nil
 }
 
-func (p *rangeFrameWindowProcessor) resetPartialResult() {
-	p.curRowIdx = 0
-	p.lastStartOffset = 0
-	p.lastEndOffset = 0
+// updatePartialResultOfFrame updates the partial result of the window function with the rows in [start, end) of the
+// partition, which are read batch by batch since they may be spilled to the disk.
+func updatePartialResultOfFrame(ctx sessionctx.Context, windowFunc aggfuncs.AggFunc, rows *windowRows, start, end uint64, pr aggfuncs.PartialResult) error {
+	minMaxSlidingWindowAggFunc, isMinMax := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc)
+	return rows.forEachBatch(start, end, func(batch []chunk.Row, batchStart uint64) error {
+		// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
+		// whether elements inside deque are out of current window.
+		if isMinMax {
+			// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
+			minMaxSlidingWindowAggFunc.SetWindowStart(batchStart)
+		}
+		_, err := windowFunc.UpdatePartialResult(ctx, batch, pr)
+		return err
+	})
 }
@@ -0,0 +1,245 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/mathutil"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// windowRows buffers the rows of the partitions processed by the window executors. The rows are copied into the chunks
+// of the same size and kept in a chunk.RowContainer, which spills them to the disk if the memory quota is exceeded,
+// so the rows are always accessed by their indexes in the current partition.
+type windowRows struct {
+	rc         *chunk.RowContainer
+	fieldTypes []*types.FieldType
+	chunkSize  int
+
+	// partitionStart is the index of the first row of the current partition in all the rows ever added.
+	partitionStart uint64
+	// offset is the index of the first row in rc in all the rows ever added.
+	offset uint64
+	// numRowsInRC is the number of the rows in rc.
+	numRowsInRC uint64
+	// tmpChk stores the rows which are not added into rc yet.
+	tmpChk *chunk.Chunk
+	batch  []chunk.Row
+	// cachedChks caches the recently accessed chunks of rc, so that the rows spilled to the disk are read chunk by
+	// chunk. The frames usually access the chunks of their start and end alternately, so there are two of them.
+	cachedChks     [2]*chunk.Chunk
+	cachedChkIdxes [2]int
+	// lastCacheIdx is the index of the most recently used chunk in cachedChks.
+	lastCacheIdx int
+
+	// nullRow and err are used by getRowOrNull.
+	nullRow chunk.Row
+	err     error
+}
+
+func newWindowRows(ctx sessionctx.Context, fieldTypes []*types.FieldType, chunkSize int,
+	memTracker *memory.Tracker, diskTracker *disk.Tracker) *windowRows {
+	r := &windowRows{
+		rc:         chunk.NewRowContainer(fieldTypes, chunkSize),
+		fieldTypes: fieldTypes,
+		chunkSize:  chunkSize,
+		tmpChk:     chunk.New(fieldTypes, chunkSize, chunkSize),
+	}
+	r.clearCache()
+	r.rc.GetMemTracker().AttachTo(memTracker)
+	r.rc.GetMemTracker().SetLabel(memory.LabelForRowContainer)
+	r.rc.GetDiskTracker().AttachTo(diskTracker)
+	r.rc.GetDiskTracker().SetLabel(memory.LabelForRowContainer)
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(r.rc.ActionSpill())
+	}
+	return r
+}
+
+// numRows returns the number of the rows added since the current partition starts.
+func (r *windowRows) numRows() uint64 {
+	return r.offset + r.numRowsInRC + uint64(r.tmpChk.NumRows()) - r.partitionStart
+}
+
+// add appends the rows in [begin, end) of chk.
+func (r *windowRows) add(chk *chunk.Chunk, begin, end int) error {
+	for begin < end {
+		n := mathutil.Min(end-begin, r.chunkSize-r.tmpChk.NumRows())
+		r.tmpChk.Append(chk, begin, begin+n)
+		begin += n
+		if err := r.flushIfFull(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *windowRows) flushIfFull() error {
+	if r.tmpChk.NumRows() < r.chunkSize {
+		return nil
+	}
+	if err := r.rc.Add(r.tmpChk); err != nil {
+		return err
+	}
+	r.numRowsInRC += uint64(r.chunkSize)
+	// The rows in tmpChk may be still referenced, so it can't be reused.
+	r.tmpChk = chunk.New(r.fieldTypes, r.chunkSize, r.chunkSize)
+	return nil
+}
+
+// getRow returns the i-th row of the current partition. The row must not be dropped.
+func (r *windowRows) getRow(i uint64) (chunk.Row, error) {
+	i += r.partitionStart - r.offset
+	if i >= r.numRowsInRC {
+		return r.tmpChk.GetRow(int(i - r.numRowsInRC)), nil
+	}
+	chunkSize := uint64(r.chunkSize)
+	chk, err := r.getChunk(int(i / chunkSize))
+	if err != nil {
+		return chunk.Row{}, err
+	}
+	return chk.GetRow(int(i % chunkSize)), nil
+}
+
+func (r *windowRows) getChunk(chkIdx int) (*chunk.Chunk, error) {
+	for i, idx := range r.cachedChkIdxes {
+		if idx == chkIdx {
+			r.lastCacheIdx = i
+			return r.cachedChks[i], nil
+		}
+	}
+	chk, err := r.rc.GetChunk(chkIdx)
+	if err != nil {
+		return nil, err
+	}
+	// Replace the least recently used one.
+	r.lastCacheIdx = 1 - r.lastCacheIdx
+	r.cachedChks[r.lastCacheIdx], r.cachedChkIdxes[r.lastCacheIdx] = chk, chkIdx
+	return chk, nil
+}
+
+func (r *windowRows) clearCache() {
+	for i := range r.cachedChks {
+		r.cachedChks[i], r.cachedChkIdxes[i] = nil, -1
+	}
+}
+
+// getRowOrNull is the same as getRow, except that it returns a row of nulls if it fails, and the error should be
+// checked by takeErr later. It's used by the callbacks which can't return errors, such as the one of
+// SlidingWindowAggFunc.Slide.
+func (r *windowRows) getRowOrNull(i uint64) chunk.Row {
+	row, err := r.getRow(i)
+	if err == nil {
+		return row
+	}
+	if r.err == nil {
+		r.err = err
+	}
+	if r.nullRow.IsEmpty() {
+		nullChk := chunk.New(r.fieldTypes, 1, 1)
+		for j := range r.fieldTypes {
+			nullChk.AppendNull(j)
+		}
+		r.nullRow = nullChk.GetRow(0)
+	}
+	return r.nullRow
+}
+
+// takeErr returns the error met by getRowOrNull and clears it.
+func (r *windowRows) takeErr() error {
+	err := r.err
+	r.err = nil
+	return err
+}
+
+// forEachBatch calls fn with the rows in [start, end) of the current partition batch by batch. The index of the first
+// row in the batch is also passed to fn. The batch is only valid in fn.
+func (r *windowRows) forEachBatch(start, end uint64, fn func(rows []chunk.Row, start uint64) error) error {
+	for start < end {
+		batchEnd := mathutil.Min(end, start+uint64(r.chunkSize))
+		r.batch = r.batch[:0]
+		for i := start; i < batchEnd; i++ {
+			row, err := r.getRow(i)
+			if err != nil {
+				return err
+			}
+			r.batch = append(r.batch, row)
+		}
+		if err := fn(r.batch, start); err != nil {
+			return err
+		}
+		start = batchEnd
+	}
+	return nil
+}
+
+// drop tells the rows before the end-th row of the current partition are no longer needed. To amortize the cost of
+// copying the rows which are still needed, the dropped rows are only released when they are the majority.
+func (r *windowRows) drop(end uint64) error {
+	end += r.partitionStart
+	total := r.offset + r.numRowsInRC + uint64(r.tmpChk.NumRows())
+	if end < r.offset+uint64(r.chunkSize) || (end-r.offset)*2 < total-r.offset {
+		return nil
+	}
+	// The dropped rows may be still referenced by the partial results, so rc is cleared rather than reset to avoid
+	// reusing the chunks.
+	numKept := int(total - end)
+	kept := chunk.New(r.fieldTypes, numKept, numKept)
+	for i := end; i < total; i++ {
+		row, err := r.getRow(i - r.partitionStart)
+		if err != nil {
+			return err
+		}
+		kept.AppendRow(row)
+	}
+	if err := r.rc.Clear(); err != nil {
+		return err
+	}
+	r.clearCache()
+	r.offset, r.numRowsInRC = end, 0
+	r.tmpChk = chunk.New(r.fieldTypes, r.chunkSize, r.chunkSize)
+	return r.add(kept, 0, numKept)
+}
+
+// nextPartition starts a new partition from the end-th row of the current partition, and the rows before it are
+// dropped.
+func (r *windowRows) nextPartition(end uint64) error {
+	if err := r.drop(end); err != nil {
+		return err
+	}
+	r.partitionStart += end
+	return nil
+}
+
+// reset removes all the rows.
+func (r *windowRows) reset() error {
+	if err := r.rc.Clear(); err != nil {
+		return err
+	}
+	r.clearCache()
+	r.partitionStart, r.offset, r.numRowsInRC = 0, 0, 0
+	r.tmpChk = chunk.New(r.fieldTypes, r.chunkSize, r.chunkSize)
+	return nil
+}
+
+func (r *windowRows) close() error {
+	r.tmpChk = nil
+	r.clearCache()
+	return r.rc.Close()
+}
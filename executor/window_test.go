@@ -16,9 +16,11 @@ package executor_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWindowFunctions(t *testing.T) {
@@ -454,6 +456,40 @@ func baseTestSlidingWindowFunctions(tk *testkit.TestKit) {
 		Check(testkit.Rows("M 3", "F 4", "F 5", "F 5", "M 5", "<nil> 11", "<nil> 11"))
 	tk.MustQuery("SELECT sex, MAX(id) OVER (ORDER BY id DESC RANGE BETWEEN 1 PRECEDING and 2 FOLLOWING) FROM t;").
 		Check(testkit.Rows("<nil> 11", "<nil> 11", "M 5", "F 5", "F 4", "F 3", "M 2"))
+
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1,1),(1,2),(2,null),(3,3),(3,null),(5,5)")
+	tk.MustQuery("select a, sum(a) over(order by a groups between 1 preceding and current row) from t").
+		Check(testkit.Rows("1 2", "1 2", "2 4", "3 8", "3 8", "5 11"))
+	tk.MustQuery("select a, sum(a) over(order by a groups between current row and 1 following) from t").
+		Check(testkit.Rows("1 4", "1 4", "2 8", "3 11", "3 11", "5 5"))
+	tk.MustQuery("select a, sum(a) over(order by a groups between 2 preceding and 1 preceding) from t").
+		Check(testkit.Rows("1 <nil>", "1 <nil>", "2 2", "3 4", "3 4", "5 8"))
+	tk.MustQuery("select a, count(a) over(order by a groups between 1 following and unbounded following) from t").
+		Check(testkit.Rows("1 4", "1 4", "2 3", "3 1", "3 1", "5 0"))
+
+	tk.MustQuery("select a, b, lag(b) ignore nulls over(order by a, b) from t").
+		Check(testkit.Rows("1 1 <nil>", "1 2 1", "2 <nil> 2", "3 <nil> 2", "3 3 2", "5 5 3"))
+	tk.MustQuery("select a, b, lead(b, 1, 0) ignore nulls over(order by a, b) from t").
+		Check(testkit.Rows("1 1 2", "1 2 3", "2 <nil> 3", "3 <nil> 3", "3 3 5", "5 5 0"))
+	tk.MustQuery("select a, b, first_value(b) ignore nulls over(order by a, b rows between 2 preceding and current row) from t").
+		Check(testkit.Rows("1 1 1", "1 2 1", "2 <nil> 1", "3 <nil> 2", "3 3 3", "5 5 3"))
+	tk.MustQuery("select a, b, last_value(b) ignore nulls over(order by a, b rows between current row and 1 following) from t").
+		Check(testkit.Rows("1 1 2", "1 2 2", "2 <nil> <nil>", "3 <nil> 3", "3 3 5", "5 5 5"))
+	tk.MustQuery("select a, b, nth_value(b, 3) from last over(order by a, b rows between unbounded preceding and unbounded following) from t").
+		Check(testkit.Rows("1 1 <nil>", "1 2 <nil>", "2 <nil> <nil>", "3 <nil> <nil>", "3 3 <nil>", "5 5 <nil>"))
+	tk.MustQuery("select a, b, nth_value(b, 2) from last ignore nulls over(order by a, b) from t").
+		Check(testkit.Rows("1 1 <nil>", "1 2 1", "2 <nil> 1", "3 <nil> 1", "3 3 2", "5 5 3"))
+
+	tk.MustQuery("select a, count(distinct a) over() from t").
+		Check(testkit.Rows("1 4", "1 4", "2 4", "3 4", "3 4", "5 4"))
+	tk.MustQuery("select a, sum(distinct a) over(order by a) from t").
+		Check(testkit.Rows("1 1", "1 1", "2 3", "3 6", "3 6", "5 11"))
+	tk.MustQuery("select a, group_concat(a) over(partition by a) from t").Sort().
+		Check(testkit.Rows("1 1,1", "1 1,1", "2 2", "3 3,3", "3 3,3", "5 5"))
+	tk.MustQuery("select a, group_concat(distinct a order by a desc separator ';') over() from t").
+		Check(testkit.Rows("1 5;3;2;1", "1 5;3;2;1", "2 5;3;2;1", "3 5;3;2;1", "3 5;3;2;1", "5 5;3;2;1"))
 }
 
 func TestIssue24264(t *testing.T) {
@@ -507,3 +543,55 @@ func TestIssue29947(t *testing.T) {
 	result.Check(testkit.Rows("2", "3"))
 	tk.MustExec("commit")
 }
+
+func TestWindowSpillDisk(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(255))")
+	sql := "insert into t values (0, repeat('x', 200))"
+	for i := 1; i <= 200; i++ {
+		sql += fmt.Sprintf(",(%v, repeat('%v', 200))", i, i%10)
+	}
+	tk.MustExec(sql)
+
+	queries := []struct {
+		sql string
+		// streamed indicates the pipelined window executor doesn't buffer the whole partition for the query.
+		streamed bool
+	}{
+		{"select count(*), sum(c), sum(length(b)) from (select t1.b, count(*) over () c from t t1 join t t2) tt", false},
+		{"select count(*), sum(c), sum(length(b)) from (select t1.b, row_number() over (order by t1.a, t2.a) c from t t1 join t t2) tt", true},
+		{"select count(*), sum(c), sum(d), sum(length(b)) from (select t1.b, sum(t1.a) over (order by t1.a, t2.a rows between 100 preceding and 1 following) c, " +
+			"max(t2.a) over (order by t1.a, t2.a rows between 1 preceding and 1000 following) d from t t1 join t t2) tt", true},
+		{"select count(*), sum(c), sum(length(b)) from (select t1.b, count(*) over (order by t1.a range between 1 preceding and current row) c from t t1 join t t2) tt", true},
+		// LEAD and LAG read the rows of the partition from the spilled rows by their indexes.
+		{"select count(*), sum(c), sum(d), sum(e), sum(length(b)) from (select t1.b, lead(t1.a, 1000) over (order by t1.a, t2.a) c, " +
+			"lag(t2.a, 30000, -1) over (order by t1.a, t2.a) d, lead(nullif(t2.a, 3), 2) ignore nulls over (order by t1.a, t2.a) e from t t1 join t t2) tt", false},
+	}
+	for _, pipelined := range []bool{false, true} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %v", pipelined))
+		for _, query := range queries {
+			tk.MustExec("set @@tidb_mem_quota_query = default")
+			expected := tk.MustQuery(query.sql).Rows()
+			tk.MustExec("set @@tidb_mem_quota_query = 4194304")
+			tk.MustQuery(query.sql).Check(expected)
+			if pipelined && query.streamed {
+				continue
+			}
+			rows := tk.MustQuery("explain analyze " + query.sql).Rows()
+			spilled := false
+			for _, row := range rows {
+				if strings.Contains(fmt.Sprintf("%v", row[0]), "Window") {
+					disk := fmt.Sprintf("%v", row[len(row)-1])
+					spilled = disk != "N/A" && !strings.HasPrefix(disk, "0 Bytes")
+				}
+			}
+			require.True(t, spilled, query.sql)
+		}
+	}
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = default")
+}
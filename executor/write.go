@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
@@ -73,6 +74,11 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 	// because all of them are sorted by their `Offset`, which
 	// causes all writable columns are after public columns.
 
+	// The triggers executed before the update may change the new row.
+	if err = execTriggers(ctx, sctx, t, model.TriggerBefore, model.TriggerUpdate, oldData, newData); err != nil {
+		return false, err
+	}
+
 	// Handle the bad null error.
 	for i, col := range t.Cols() {
 		var err error
@@ -174,7 +180,7 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 				txnCtx.AddUnchangedLockKey(unchangedUniqueKey)
 			}
 		}
-		return false, nil
+		return false, execTriggers(ctx, sctx, t, model.TriggerAfter, model.TriggerUpdate, oldData, newData)
 	}
 
 	// Fill values into on-update-now fields, only if they are really changed.
@@ -237,7 +243,7 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 	sc.AddUpdatedRows(1)
 	sc.AddCopiedRows(1)
 
-	return true, nil
+	return true, execTriggers(ctx, sctx, t, model.TriggerAfter, model.TriggerUpdate, oldData, newData)
 }
 
 func rebaseAutoRandomValue(ctx context.Context, sctx sessionctx.Context, t table.Table, newData *types.Datum, col *table.Column) error {
@@ -40,6 +40,9 @@ type AggFuncDesc struct {
 	HasDistinct bool
 	// OrderByItems represents the order by clause used in GROUP_CONCAT
 	OrderByItems []*util.ByItems
+	// IgnoreNull and FromLast are only used by the window functions, see WindowFuncDesc.
+	IgnoreNull bool
+	FromLast   bool
 }
 
 // NewAggFuncDesc creates an aggregation function signature descriptor.
@@ -54,10 +57,20 @@ func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expre
 
 // NewAggFuncDescForWindowFunc creates an aggregation function from window functions, where baseFuncDesc may be ready.
 func NewAggFuncDescForWindowFunc(ctx sessionctx.Context, Desc *WindowFuncDesc, hasDistinct bool) (*AggFuncDesc, error) {
+	var desc *AggFuncDesc
 	if Desc.RetTp == nil { // safety check
-		return NewAggFuncDesc(ctx, Desc.Name, Desc.Args, hasDistinct)
+		var err error
+		desc, err = NewAggFuncDesc(ctx, Desc.Name, Desc.Args, hasDistinct)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		desc = &AggFuncDesc{baseFuncDesc: baseFuncDesc{Desc.Name, Desc.Args, Desc.RetTp}, HasDistinct: hasDistinct}
 	}
-	return &AggFuncDesc{baseFuncDesc: baseFuncDesc{Desc.Name, Desc.Args, Desc.RetTp}, HasDistinct: hasDistinct}, nil
+	desc.OrderByItems = Desc.OrderByItems
+	desc.IgnoreNull = Desc.IgnoreNull
+	desc.FromLast = Desc.FromLast
+	return desc, nil
 }
 
 // String implements the fmt.Stringer interface.
@@ -88,7 +101,7 @@ func (a *AggFuncDesc) String() string {
 
 // Equal checks whether two aggregation function signatures are equal.
 func (a *AggFuncDesc) Equal(ctx sessionctx.Context, other *AggFuncDesc) bool {
-	if a.HasDistinct != other.HasDistinct {
+	if a.HasDistinct != other.HasDistinct || a.IgnoreNull != other.IgnoreNull || a.FromLast != other.FromLast {
 		return false
 	}
 	if len(a.OrderByItems) != len(other.OrderByItems) {
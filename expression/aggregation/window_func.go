@@ -15,11 +15,13 @@
 package aggregation
 
 import (
+	"bytes"
 	"strings"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tipb/go-tipb"
 )
@@ -27,6 +29,14 @@ import (
 // WindowFuncDesc describes a window function signature, only used in planner.
 type WindowFuncDesc struct {
 	baseFuncDesc
+	// HasDistinct indicates that the aggregate function only aggregates the distinct values in the frame.
+	HasDistinct bool
+	// OrderByItems represents the order by clause used in GROUP_CONCAT.
+	OrderByItems []*util.ByItems
+	// IgnoreNull indicates that LEAD, LAG, FIRST_VALUE, LAST_VALUE and NTH_VALUE skip the rows whose values are NULL.
+	IgnoreNull bool
+	// FromLast indicates that NTH_VALUE counts the rows backwards from the last row of the frame.
+	FromLast bool
 }
 
 // NewWindowFuncDesc creates a window function signature descriptor.
@@ -61,7 +71,7 @@ func NewWindowFuncDesc(ctx sessionctx.Context, name string, args []expression.Ex
 	if err != nil {
 		return nil, err
 	}
-	return &WindowFuncDesc{base}, nil
+	return &WindowFuncDesc{baseFuncDesc: base}, nil
 }
 
 // noFrameWindowFuncs is the functions that operate on the entire partition,
@@ -101,7 +111,58 @@ func NeedFrame(name string) bool {
 
 // Clone makes a copy of SortItem.
 func (s *WindowFuncDesc) Clone() *WindowFuncDesc {
-	return &WindowFuncDesc{*s.baseFuncDesc.clone()}
+	clone := *s
+	clone.baseFuncDesc = *s.baseFuncDesc.clone()
+	clone.OrderByItems = make([]*util.ByItems, len(s.OrderByItems))
+	for i, byItem := range s.OrderByItems {
+		clone.OrderByItems[i] = byItem.Clone()
+	}
+	return &clone
+}
+
+// String implements the fmt.Stringer interface.
+func (s *WindowFuncDesc) String() string {
+	buffer := bytes.NewBufferString(s.Name)
+	buffer.WriteString("(")
+	if s.HasDistinct {
+		buffer.WriteString("distinct ")
+	}
+	for i, arg := range s.Args {
+		buffer.WriteString(arg.String())
+		if i+1 != len(s.Args) {
+			buffer.WriteString(", ")
+		}
+	}
+	if len(s.OrderByItems) > 0 {
+		buffer.WriteString(" order by ")
+	}
+	for i, byItem := range s.OrderByItems {
+		buffer.WriteString(byItem.String())
+		if i+1 != len(s.OrderByItems) {
+			buffer.WriteString(", ")
+		}
+	}
+	buffer.WriteString(")")
+	if s.FromLast {
+		buffer.WriteString(" from last")
+	}
+	if s.IgnoreNull {
+		buffer.WriteString(" ignore nulls")
+	}
+	return buffer.String()
+}
+
+// Exprs returns the arguments and the order by expressions of the window function.
+func (s *WindowFuncDesc) Exprs() []expression.Expression {
+	if len(s.OrderByItems) == 0 {
+		return s.Args
+	}
+	exprs := make([]expression.Expression, 0, len(s.Args)+len(s.OrderByItems))
+	exprs = append(exprs, s.Args...)
+	for _, byItem := range s.OrderByItems {
+		exprs = append(exprs, byItem.Expr)
+	}
+	return exprs
 }
 
 // WindowFuncToPBExpr converts aggregate function to pb.
@@ -125,6 +186,9 @@ func WindowFuncToPBExpr(sctx sessionctx.Context, client kv.Client, desc *WindowF
 
 // CanPushDownToTiFlash control whether a window function desc can be push down to tiflash.
 func (s *WindowFuncDesc) CanPushDownToTiFlash() bool {
+	if s.HasDistinct || s.IgnoreNull || s.FromLast || len(s.OrderByItems) > 0 {
+		return false
+	}
 	// window functions
 	switch s.Name {
 	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
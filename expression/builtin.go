@@ -315,6 +315,10 @@ func (b *baseBuiltinFunc) isChildrenVectorized() bool {
 func (b *baseBuiltinFunc) getRetTp() *types.FieldType {
 	switch b.tp.EvalType() {
 	case types.ETString:
+		if b.tp.GetType() == mysql.TypeGeometry {
+			// The geometry values are stored in binary like the blobs, but the type is kept for the columns.
+			return b.tp
+		}
 		if b.tp.GetFlen() >= mysql.MaxBlobWidth {
 			b.tp.SetType(mysql.TypeLongBlob)
 		} else if b.tp.GetFlen() >= 65536 {
@@ -671,6 +675,10 @@ var funcs = map[string]functionClass{
 	ast.Ord:             &ordFunctionClass{baseFunctionClass{ast.Ord, 1, 1}},
 	ast.Position:        &locateFunctionClass{baseFunctionClass{ast.Position, 2, 2}},
 	ast.Quote:           &quoteFunctionClass{baseFunctionClass{ast.Quote, 1, 1}},
+	ast.RegexpLike:      &regexpLikeFunctionClass{baseFunctionClass{ast.RegexpLike, 2, 3}},
+	ast.RegexpSubstr:    &regexpSubstrFunctionClass{baseFunctionClass{ast.RegexpSubstr, 2, 5}},
+	ast.RegexpInStr:     &regexpInStrFunctionClass{baseFunctionClass{ast.RegexpInStr, 2, 6}},
+	ast.RegexpReplace:   &regexpReplaceFunctionClass{baseFunctionClass{ast.RegexpReplace, 3, 6}},
 	ast.Repeat:          &repeatFunctionClass{baseFunctionClass{ast.Repeat, 2, 2}},
 	ast.Replace:         &replaceFunctionClass{baseFunctionClass{ast.Replace, 3, 3}},
 	ast.Reverse:         &reverseFunctionClass{baseFunctionClass{ast.Reverse, 1, 1}},
@@ -722,6 +730,7 @@ var funcs = map[string]functionClass{
 	ast.Sleep:           &sleepFunctionClass{baseFunctionClass{ast.Sleep, 1, 1}},
 	ast.AnyValue:        &anyValueFunctionClass{baseFunctionClass{ast.AnyValue, 1, 1}},
 	ast.DefaultFunc:     &defaultFunctionClass{baseFunctionClass{ast.DefaultFunc, 1, 1}},
+	ast.Grouping:        &groupingFunctionClass{baseFunctionClass{ast.Grouping, 2, -1}},
 	ast.InetAton:        &inetAtonFunctionClass{baseFunctionClass{ast.InetAton, 1, 1}},
 	ast.InetNtoa:        &inetNtoaFunctionClass{baseFunctionClass{ast.InetNtoa, 1, 1}},
 	ast.Inet6Aton:       &inet6AtonFunctionClass{baseFunctionClass{ast.Inet6Aton, 1, 1}},
@@ -828,6 +837,21 @@ var funcs = map[string]functionClass{
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
 
+	// spatial functions
+	ast.StGeomFromText:     &stGeomFromTextFunctionClass{baseFunctionClass{ast.StGeomFromText, 1, 2}},
+	ast.StGeometryFromText: &stGeomFromTextFunctionClass{baseFunctionClass{ast.StGeometryFromText, 1, 2}},
+	ast.StAsText:           &stAsTextFunctionClass{baseFunctionClass{ast.StAsText, 1, 1}},
+	ast.StAsWKT:            &stAsTextFunctionClass{baseFunctionClass{ast.StAsWKT, 1, 1}},
+	ast.StSRID:             &stSRIDFunctionClass{baseFunctionClass{ast.StSRID, 1, 2}},
+	ast.StDistanceSphere:   &stDistanceSphereFunctionClass{baseFunctionClass{ast.StDistanceSphere, 2, 3}},
+	ast.StContains:         &stContainsFunctionClass{baseFunctionClass{ast.StContains, 2, 2}},
+	ast.StWithin:           &stWithinFunctionClass{baseFunctionClass{ast.StWithin, 2, 2}},
+	ast.StIntersects:       &stIntersectsFunctionClass{baseFunctionClass{ast.StIntersects, 2, 2}},
+	ast.StBuffer:           &stBufferFunctionClass{baseFunctionClass{ast.StBuffer, 2, 2}},
+
+	// full-text search functions
+	ast.FulltextMatch: &fulltextMatchFunctionClass{baseFunctionClass{ast.FulltextMatch, 3, -1}},
+
 	// TiDB internal function.
 	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
 	// This function is used to show tidb-server version info.
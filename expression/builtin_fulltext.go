@@ -0,0 +1,210 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+var (
+	_ functionClass = &fulltextMatchFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinFulltextMatchSig{}
+)
+
+// FulltextMatchInfo is the information of the FULLTEXT index searched by `MATCH ... AGAINST`.
+// It's stored as the second argument of the function, so that the function can be rebuilt from
+// its arguments, e.g. when the columns are substituted.
+type FulltextMatchInfo struct {
+	// PhysicalIDs are the IDs of the table or the partitions of the table.
+	PhysicalIDs []int64 `json:"physical_ids"`
+	IndexID     int64   `json:"index_id"`
+	Parser      string  `json:"parser,omitempty"`
+	// Collation is the collation of the tokens stored in the index.
+	Collation   string `json:"collation"`
+	BooleanMode bool   `json:"boolean_mode,omitempty"`
+	// RowCount is the number of the rows of the table when the statement is planned,
+	// it's used to compute the inverse document frequency of the tokens.
+	RowCount int64 `json:"row_count"`
+}
+
+// NewQuery parses the search string of `AGAINST`.
+func (info *FulltextMatchInfo) NewQuery(t fulltext.Tokenizer, text string) *fulltext.Query {
+	if info.BooleanMode {
+		return fulltext.NewBooleanQuery(t, text)
+	}
+	return fulltext.NewNaturalLanguageQuery(t, text)
+}
+
+// NewFulltextMatch builds the `MATCH (cols) AGAINST (against)` function.
+func NewFulltextMatch(ctx sessionctx.Context, info *FulltextMatchInfo, against Expression, cols []Expression) (Expression, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	args := make([]Expression, 0, len(cols)+2)
+	args = append(args, against, DatumToConstant(types.NewStringDatum(string(data)), mysql.TypeVarString, 0))
+	args = append(args, cols...)
+	return NewFunction(ctx, ast.FulltextMatch, types.NewFieldType(mysql.TypeDouble), args...)
+}
+
+// GetFulltextMatchInfo returns the information of the FULLTEXT index searched by the `MATCH ... AGAINST` function.
+func GetFulltextMatchInfo(sf *ScalarFunction) (*FulltextMatchInfo, error) {
+	return decodeFulltextMatchInfo(sf.GetArgs())
+}
+
+func decodeFulltextMatchInfo(args []Expression) (*FulltextMatchInfo, error) {
+	c, ok := args[1].(*Constant)
+	if !ok || c.Value.Kind() != types.KindString {
+		return nil, errors.Errorf("invalid arguments of %s", ast.FulltextMatch)
+	}
+	info := &FulltextMatchInfo{}
+	if err := json.Unmarshal(c.Value.GetBytes(), info); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return info, nil
+}
+
+type fulltextMatchFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *fulltextMatchFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	info, err := decodeFulltextMatchInfo(args)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := fulltext.GetTokenizer(info.Parser)
+	if !ok {
+		return nil, errors.Errorf("unknown full-text parser %s", info.Parser)
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range argTps {
+		argTps[i] = types.ETString
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlag(mysql.NotNullFlag)
+	sig := &builtinFulltextMatchSig{baseBuiltinFunc: bf, info: info, tokenizer: t}
+	return sig, nil
+}
+
+type builtinFulltextMatchSig struct {
+	baseBuiltinFunc
+	info      *FulltextMatchInfo
+	tokenizer fulltext.Tokenizer
+
+	// The expression may be evaluated by several workers concurrently, e.g. by the parallel projection.
+	mu struct {
+		sync.Mutex
+		against string
+		query   *fulltext.Query
+		idf     map[string]float64
+	}
+}
+
+func (b *builtinFulltextMatchSig) Clone() builtinFunc {
+	newSig := &builtinFulltextMatchSig{info: b.info, tokenizer: b.tokenizer}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals `MATCH (col1, col2, ...) AGAINST (expr [search_modifier])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/fulltext-search.html#function_match.
+func (b *builtinFulltextMatchSig) evalReal(row chunk.Row) (float64, bool, error) {
+	against, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	if isNull {
+		return 0, false, nil
+	}
+	texts := make([]string, 0, len(b.args)-2)
+	for _, arg := range b.args[2:] {
+		text, isNull, err := arg.EvalString(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull {
+			texts = append(texts, text)
+		}
+	}
+	doc := fulltext.NewDocument(b.tokenizer, texts...)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.mu.query == nil || b.mu.against != against {
+		b.mu.against = against
+		b.mu.query = b.info.NewQuery(b.tokenizer, against)
+	}
+	score, err := b.mu.query.Score(doc, b.idf)
+	if err != nil {
+		return 0, true, err
+	}
+	return score, false, nil
+}
+
+// idf returns the inverse document frequency of the token, the number of the documents
+// containing the token is counted by scanning the index entries of the token.
+func (b *builtinFulltextMatchSig) idf(token string) (float64, error) {
+	if w, ok := b.mu.idf[token]; ok {
+		return w, nil
+	}
+	txn, err := b.ctx.Txn(true)
+	if err != nil {
+		return 0, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	var docs int64
+	for _, pid := range b.info.PhysicalIDs {
+		r, err := fulltext.TokenKeyRange(sc, pid, b.info.IndexID, b.info.Collation, token)
+		if err != nil {
+			return 0, err
+		}
+		it, err := txn.Iter(r.StartKey, r.EndKey)
+		if err != nil {
+			return 0, err
+		}
+		for it.Valid() {
+			docs++
+			if err = it.Next(); err != nil {
+				it.Close()
+				return 0, err
+			}
+		}
+		it.Close()
+	}
+	w := fulltext.IDF(b.info.RowCount, docs)
+	if b.mu.idf == nil {
+		b.mu.idf = make(map[string]float64)
+	}
+	b.mu.idf[token] = w
+	return w, nil
+}
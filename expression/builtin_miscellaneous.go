@@ -44,6 +44,7 @@ var (
 	_ functionClass = &releaseLockFunctionClass{}
 	_ functionClass = &anyValueFunctionClass{}
 	_ functionClass = &defaultFunctionClass{}
+	_ functionClass = &groupingFunctionClass{}
 	_ functionClass = &inetAtonFunctionClass{}
 	_ functionClass = &inetNtoaFunctionClass{}
 	_ functionClass = &inet6AtonFunctionClass{}
@@ -78,6 +79,7 @@ var (
 	_ builtinFunc = &builtinRealAnyValueSig{}
 	_ builtinFunc = &builtinStringAnyValueSig{}
 	_ builtinFunc = &builtinTimeAnyValueSig{}
+	_ builtinFunc = &builtinGroupingSig{}
 	_ builtinFunc = &builtinInetAtonSig{}
 	_ builtinFunc = &builtinInetNtoaSig{}
 	_ builtinFunc = &builtinInet6AtonSig{}
@@ -1467,3 +1469,50 @@ func (b *builtinTidbShardSig) evalInt(row chunk.Row) (int64, bool, error) {
 	hashed = hashed % tidbShardBucketCount
 	return int64(hashed), false, nil
 }
+
+type groupingFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *groupingFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxIntWidth)
+	sig := &builtinGroupingSig{bf}
+	return sig, nil
+}
+
+// builtinGroupingSig is the GROUPING function after the planner resolves its arguments. The planner rewrites
+// GROUPING(a, b, ...) to grouping(gid, r0, r1, ...), where gid is the id of the grouping set which the row belongs
+// to, and ri is the result of the function for the i-th grouping set.
+type builtinGroupingSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGroupingSig) Clone() builtinFunc {
+	newSig := &builtinGroupingSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals grouping(gid, r0, r1, ...).
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_grouping
+func (b *builtinGroupingSig) evalInt(row chunk.Row) (int64, bool, error) {
+	gid, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if gid < 0 || gid >= int64(len(b.args)-1) {
+		return 0, true, errors.Errorf("invalid grouping set id %d", gid)
+	}
+	return b.args[gid+1].EvalInt(b.ctx, row)
+}
@@ -623,3 +623,34 @@ func TestTidbShard(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestGrouping(t *testing.T) {
+	ctx := createContext(t)
+
+	fc := funcs[ast.Grouping]
+
+	// grouping(gid, r0, r1, r2) returns r_gid.
+	results := []int{0, 1, 3}
+	for gid, res := range results {
+		args := makeDatums(append([]int{gid}, results...))
+		f, err := fc.getFunction(ctx, datumsToConstants(args))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		testutil.DatumEqual(t, types.NewIntDatum(int64(res)), d)
+	}
+
+	f, err := fc.getFunction(ctx, datumsToConstants(makeDatums([]interface{}{nil, 0})))
+	require.NoError(t, err)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+
+	f, err = fc.getFunction(ctx, datumsToConstants(makeDatums([]int{1, 0})))
+	require.NoError(t, err)
+	_, err = evalBuiltinFunc(f, chunk.Row{})
+	require.Error(t, err)
+
+	_, err = fc.getFunction(ctx, datumsToConstants(makeDatums([]int{0})))
+	require.Error(t, err)
+}
@@ -0,0 +1,516 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeSig{}
+	_ builtinFunc = &builtinRegexpSubstrSig{}
+	_ builtinFunc = &builtinRegexpInStrSig{}
+	_ builtinFunc = &builtinRegexpReplaceSig{}
+)
+
+const (
+	regexpErrIllegalArgument       = "Illegal argument to a regular expression."
+	regexpErrIndexOutOfBounds      = "Index out of bounds in regular expression search."
+	regexpErrInvalidMatchType      = "Invalid match mode flag in regular expression."
+	regexpErrInvalidReturnOpt      = "regexp_instr: return_option must be 1 or 0."
+	regexpDefaultPosition          = 1
+	regexpDefaultOccurrence        = 1
+	regexpDefaultReplaceOccurrence = 0
+)
+
+// regexpFuncSharedSig is shared by the REGEXP_LIKE, REGEXP_SUBSTR, REGEXP_INSTR and REGEXP_REPLACE functions.
+// The second argument is always the pattern, and matchTypeIdx is the index of the optional match_type argument.
+type regexpFuncSharedSig struct {
+	baseBuiltinFunc
+	matchTypeIdx int
+
+	// memorizedRegexp is the compiled regexp of the constant pattern and match_type.
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+	once            sync.Once
+}
+
+func newRegexpFuncSharedSig(bf baseBuiltinFunc, matchTypeIdx int) regexpFuncSharedSig {
+	return regexpFuncSharedSig{baseBuiltinFunc: bf, matchTypeIdx: matchTypeIdx}
+}
+
+func (b *regexpFuncSharedSig) clone(from *regexpFuncSharedSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+	b.matchTypeIdx = from.matchTypeIdx
+}
+
+// isBinary indicates whether the strings are matched byte by byte, the positions are counted in bytes as well.
+func (b *regexpFuncSharedSig) isBinary() bool {
+	return b.collation == charset.CollationBin
+}
+
+// getMatchFlags converts the match_type to the flags of the regexp. The case sensitivity is decided by the collation
+// unless `c` or `i` is specified, and the rightmost one wins if they are both specified. Binary strings are always
+// matched case-sensitively.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *regexpFuncSharedSig) getMatchFlags(matchType string) (string, error) {
+	ci := collate.IsCICollation(b.collation)
+	multiLine, dotAll := false, false
+	for _, c := range matchType {
+		switch c {
+		case 'c':
+			ci = false
+		case 'i':
+			ci = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Only `\n` is recognized as the line terminator.
+		default:
+			return "", ErrRegexp.GenWithStackByArgs(regexpErrInvalidMatchType)
+		}
+	}
+	var flags strings.Builder
+	if ci && !b.isBinary() {
+		flags.WriteByte('i')
+	}
+	if multiLine {
+		flags.WriteByte('m')
+	}
+	if dotAll {
+		flags.WriteByte('s')
+	}
+	if flags.Len() == 0 {
+		return "", nil
+	}
+	return "(?" + flags.String() + ")", nil
+}
+
+// getRegexp compiles the pattern, the compiled regexp is memorized when the pattern and the match_type are constant.
+func (b *regexpFuncSharedSig) getRegexp(pattern, matchType string) (*regexp.Regexp, error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if b.args[1].ConstItem(sc) && (b.matchTypeIdx >= len(b.args) || b.args[b.matchTypeIdx].ConstItem(sc)) {
+		b.once.Do(func() {
+			b.memorizedRegexp, b.memorizedErr = b.compile(pattern, matchType)
+		})
+		return b.memorizedRegexp, b.memorizedErr
+	}
+	return b.compile(pattern, matchType)
+}
+
+func (b *regexpFuncSharedSig) compile(pattern, matchType string) (*regexp.Regexp, error) {
+	if len(pattern) == 0 {
+		return nil, ErrRegexp.GenWithStackByArgs(regexpErrIllegalArgument)
+	}
+	flags, err := b.getMatchFlags(matchType)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(flags + pattern)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+// getByteOffset converts the 1-based position of the characters to the offset of the bytes.
+func (b *regexpFuncSharedSig) getByteOffset(expr string, pos int64) (int, error) {
+	if pos < 1 {
+		return 0, ErrRegexp.GenWithStackByArgs(regexpErrIndexOutOfBounds)
+	}
+	if b.isBinary() {
+		if pos > int64(len(expr))+1 {
+			return 0, ErrRegexp.GenWithStackByArgs(regexpErrIndexOutOfBounds)
+		}
+		return int(pos - 1), nil
+	}
+	offset := 0
+	for i := int64(1); i < pos; i++ {
+		if offset >= len(expr) {
+			return 0, ErrRegexp.GenWithStackByArgs(regexpErrIndexOutOfBounds)
+		}
+		_, size := utf8.DecodeRuneInString(expr[offset:])
+		offset += size
+	}
+	return offset, nil
+}
+
+// getCharPos converts the offset of the bytes to the 1-based position of the characters.
+func (b *regexpFuncSharedSig) getCharPos(expr string, offset int) int64 {
+	if b.isBinary() {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(expr[:offset])) + 1
+}
+
+// findMatch returns the submatch indexes of the occurrence-th match in the expr starting from the position, it
+// returns nil if there aren't enough matches. The indexes are offsets in the expr.
+func (b *regexpFuncSharedSig) findMatch(re *regexp.Regexp, expr string, pos, occurrence int64) ([]int, error) {
+	offset, err := b.getByteOffset(expr, pos)
+	if err != nil {
+		return nil, err
+	}
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches := re.FindAllStringSubmatchIndex(expr[offset:], int(occurrence))
+	if int64(len(matches)) < occurrence {
+		return nil, nil
+	}
+	match := matches[occurrence-1]
+	for i := range match {
+		if match[i] >= 0 {
+			match[i] += offset
+		}
+	}
+	return match, nil
+}
+
+func (b *regexpFuncSharedSig) evalStringArg(row chunk.Row, idx int, defaultVal string) (string, bool, error) {
+	if idx >= len(b.args) {
+		return defaultVal, false, nil
+	}
+	return b.args[idx].EvalString(b.ctx, row)
+}
+
+func (b *regexpFuncSharedSig) evalIntArg(row chunk.Row, idx int, defaultVal int64) (int64, bool, error) {
+	if idx >= len(b.args) {
+		return defaultVal, false, nil
+	}
+	return b.args[idx].EvalInt(b.ctx, row)
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETString}[:len(args)]
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(1)
+	sig := &builtinRegexpLikeSig{newRegexpFuncSharedSig(bf, 2)}
+	if sig.isBinary() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpLikeSig struct {
+	regexpFuncSharedSig
+}
+
+func (b *builtinRegexpLikeSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeSig{}
+	newSig.clone(&b.regexpFuncSharedSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_LIKE(expr, pat[, match_type])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pattern, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 2, "")
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return b.regexpLike(expr, pattern, matchType)
+}
+
+func (b *builtinRegexpLikeSig) regexpLike(expr, pattern, matchType string) (int64, bool, error) {
+	re, err := b.getRegexp(pattern, matchType)
+	if err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(re.MatchString(expr)), false, nil
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}[:len(args)]
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	argType := args[0].GetType()
+	bf.tp.SetFlen(argType.GetFlen())
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinRegexpSubstrSig{newRegexpFuncSharedSig(bf, 4)}
+	if sig.isBinary() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpSubstrSig struct {
+	regexpFuncSharedSig
+}
+
+func (b *builtinRegexpSubstrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrSig{}
+	newSig.clone(&b.regexpFuncSharedSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pattern, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalIntArg(row, 2, regexpDefaultPosition)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalIntArg(row, 3, regexpDefaultOccurrence)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 4, "")
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.regexpSubstr(expr, pattern, pos, occurrence, matchType)
+}
+
+func (b *builtinRegexpSubstrSig) regexpSubstr(expr, pattern string, pos, occurrence int64, matchType string) (string, bool, error) {
+	re, err := b.getRegexp(pattern, matchType)
+	if err != nil {
+		return "", true, err
+	}
+	match, err := b.findMatch(re, expr, pos, occurrence)
+	if match == nil || err != nil {
+		return "", true, err
+	}
+	return expr[match[0]:match[1]], false, nil
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString}[:len(args)]
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxIntWidth)
+	sig := &builtinRegexpInStrSig{newRegexpFuncSharedSig(bf, 5)}
+	if sig.isBinary() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpInStrSig struct {
+	regexpFuncSharedSig
+}
+
+func (b *builtinRegexpInStrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrSig{}
+	newSig.clone(&b.regexpFuncSharedSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pattern, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pos, isNull, err := b.evalIntArg(row, 2, regexpDefaultPosition)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	occurrence, isNull, err := b.evalIntArg(row, 3, regexpDefaultOccurrence)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	returnOption, isNull, err := b.evalIntArg(row, 4, 0)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 5, "")
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return b.regexpInStr(expr, pattern, pos, occurrence, returnOption, matchType)
+}
+
+func (b *builtinRegexpInStrSig) regexpInStr(expr, pattern string, pos, occurrence, returnOption int64, matchType string) (int64, bool, error) {
+	if returnOption != 0 && returnOption != 1 {
+		return 0, true, errIncorrectArgs.GenWithStackByArgs(regexpErrInvalidReturnOpt)
+	}
+	re, err := b.getRegexp(pattern, matchType)
+	if err != nil {
+		return 0, true, err
+	}
+	match, err := b.findMatch(re, expr, pos, occurrence)
+	if err != nil {
+		return 0, true, err
+	}
+	if match == nil {
+		return 0, false, nil
+	}
+	return b.getCharPos(expr, match[returnOption]), false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}[:len(args)]
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxBlobWidth)
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	sig := &builtinRegexpReplaceSig{newRegexpFuncSharedSig(bf, 5)}
+	if sig.isBinary() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpReplaceSig struct {
+	regexpFuncSharedSig
+}
+
+func (b *builtinRegexpReplaceSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceSig{}
+	newSig.clone(&b.regexpFuncSharedSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pattern, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	repl, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalIntArg(row, 3, regexpDefaultPosition)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalIntArg(row, 4, regexpDefaultReplaceOccurrence)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 5, "")
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.regexpReplace(expr, pattern, repl, pos, occurrence, matchType)
+}
+
+// regexpReplace replaces the occurrence-th match starting from the position, all the matches are replaced if the
+// occurrence is 0. The groups are referenced by `$n` in the replacement.
+func (b *builtinRegexpReplaceSig) regexpReplace(expr, pattern, repl string, pos, occurrence int64, matchType string) (string, bool, error) {
+	re, err := b.getRegexp(pattern, matchType)
+	if err != nil {
+		return "", true, err
+	}
+	if occurrence < 1 {
+		offset, err := b.getByteOffset(expr, pos)
+		if err != nil {
+			return "", true, err
+		}
+		return expr[:offset] + re.ReplaceAllString(expr[offset:], repl), false, nil
+	}
+	match, err := b.findMatch(re, expr, pos, occurrence)
+	if err != nil {
+		return "", true, err
+	}
+	if match == nil {
+		return expr, false, nil
+	}
+	// The matches are expanded against the expr, so the offsets in match are valid.
+	replaced := re.ExpandString(nil, repl, expr, match)
+	return expr[:match[0]] + string(replaced) + expr[match[1]:], false, nil
+}
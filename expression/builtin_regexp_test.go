@@ -0,0 +1,183 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit/testutil"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegexpFunc(t *testing.T, funcName string, args []interface{}, expected interface{}, expectedErr error) {
+	ctx := createContext(t)
+	comment := fmt.Sprintf("%s%v", funcName, args)
+	f, err := funcs[funcName].getFunction(ctx, datumsToConstants(types.MakeDatums(args...)))
+	require.NoError(t, err, comment)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	if expectedErr != nil {
+		require.True(t, terror.ErrorEqual(err, expectedErr), comment)
+		return
+	}
+	require.NoError(t, err, comment)
+	testutil.DatumEqual(t, types.NewDatum(expected), d, comment)
+}
+
+func TestRegexpLike(t *testing.T) {
+	tests := []struct {
+		args     []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{"abc", "b"}, 1, nil},
+		{[]interface{}{"abc", "^b"}, 0, nil},
+		{[]interface{}{"abc", "B"}, 0, nil},
+		{[]interface{}{"abc", "B", "i"}, 1, nil},
+		{[]interface{}{"abc", "B", "ic"}, 0, nil},
+		{[]interface{}{"abc", "B", "ci"}, 1, nil},
+		{[]interface{}{"a\nb", "^b"}, 0, nil},
+		{[]interface{}{"a\nb", "^b", "m"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b"}, 0, nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b", "un"}, 1, nil},
+		{[]interface{}{"你好", "^.好$"}, 1, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", ""}, nil, ErrRegexp},
+		{[]interface{}{"a", "("}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, ErrRegexp},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpLike, tt.args, tt.expected, tt.err)
+	}
+}
+
+func TestRegexpSubstr(t *testing.T) {
+	tests := []struct {
+		args     []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2}, "bc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 5, 0}, "def", nil},
+		{[]interface{}{"abc", "b", 4}, nil, nil},
+		{[]interface{}{"abc", "b", 5}, nil, ErrRegexp},
+		{[]interface{}{"abc", "b", 0}, nil, ErrRegexp},
+		{[]interface{}{"你好世界", ".界", 3}, "世界", nil},
+		{[]interface{}{"aBc", "b", 1, 1, "i"}, "B", nil},
+		{[]interface{}{"aBc", "b", 1, 1, "c"}, nil, nil},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpSubstr, tt.args, tt.expected, tt.err)
+	}
+}
+
+func TestRegexpInStr(t *testing.T) {
+	tests := []struct {
+		args     []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{"dog cat dog", "dog"}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, 0, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 1, 1}, 4, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, 1, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "c"}, 0, nil},
+		{[]interface{}{"你好世界", "世界"}, 3, nil},
+		{[]interface{}{"你好世界", "世界", 1, 1, 1}, 5, nil},
+		{[]interface{}{"abc", "b", 1, 1, 2}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "b", 10}, nil, ErrRegexp},
+		{[]interface{}{"abc", "b", 1, nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpInStr, tt.args, tt.expected, tt.err)
+	}
+}
+
+func TestRegexpReplace(t *testing.T) {
+	tests := []struct {
+		args     []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 2}, "abc X ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 2}, "aX X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5, 1}, "abc X ghi", nil},
+		{[]interface{}{"abc def", "([a-z]+) ([a-z]+)", "$2 $1"}, "def abc", nil},
+		{[]interface{}{"你好世界", "世.", "X", 2}, "你好X", nil},
+		{[]interface{}{"aBc", "b", "X", 1, 0, "i"}, "aXc", nil},
+		{[]interface{}{"aBc", "b", "X", 1, 0, "c"}, "aBc", nil},
+		{[]interface{}{"abc", "b", "X", 5}, nil, ErrRegexp},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpReplace, tt.args, tt.expected, tt.err)
+	}
+}
+
+func TestRegexpCollation(t *testing.T) {
+	ctx := createContext(t)
+	tests := []struct {
+		collation string
+		expected  int64
+	}{
+		{"utf8mb4_bin", 0},
+		{"utf8mb4_general_ci", 1},
+		{"utf8mb4_unicode_ci", 1},
+		{charset.CollationBin, 0},
+	}
+	for _, tt := range tests {
+		ft := types.NewFieldType(mysql.TypeVarString)
+		ft.SetCharset(charset.CharsetUTF8MB4)
+		if tt.collation == charset.CollationBin {
+			ft.SetCharset(charset.CharsetBin)
+		}
+		ft.SetCollate(tt.collation)
+		args := []Expression{
+			&Constant{Value: types.NewStringDatum("aBc"), RetType: ft},
+			&Constant{Value: types.NewStringDatum("b"), RetType: ft},
+		}
+		f, err := funcs[ast.RegexpLike].getFunction(ctx, args)
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, d.GetInt64(), tt.collation)
+
+		// The binary strings are matched case-sensitively even with `i`.
+		args = append(args, &Constant{Value: types.NewStringDatum("i"), RetType: types.NewFieldType(mysql.TypeVarString)})
+		f, err = funcs[ast.RegexpLike].getFunction(ctx, args)
+		require.NoError(t, err)
+		d, err = evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, int64(1)-boolToInt64(tt.collation == charset.CollationBin), d.GetInt64(), tt.collation)
+	}
+}
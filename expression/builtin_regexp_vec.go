@@ -0,0 +1,193 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalArgs evaluates all the arguments to the buffers, the buffers should be put back by the returned function.
+func (b *regexpFuncSharedSig) vecEvalArgs(input *chunk.Chunk) ([]*chunk.Column, func(), error) {
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	release := func() {
+		for _, buf := range bufs {
+			b.bufAllocator.put(buf)
+		}
+	}
+	for _, arg := range b.args {
+		buf, err := b.bufAllocator.get()
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		bufs = append(bufs, buf)
+		if arg.GetType().EvalType() == types.ETInt {
+			err = arg.VecEvalInt(b.ctx, input, buf)
+		} else {
+			err = arg.VecEvalString(b.ctx, input, buf)
+		}
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+	}
+	return bufs, release, nil
+}
+
+func isRegexpArgsNull(bufs []*chunk.Column, row int) bool {
+	for _, buf := range bufs {
+		if buf.IsNull(row) {
+			return true
+		}
+	}
+	return false
+}
+
+func getRegexpStringArg(bufs []*chunk.Column, idx, row int, defaultVal string) string {
+	if idx >= len(bufs) {
+		return defaultVal
+	}
+	return bufs[idx].GetString(row)
+}
+
+func getRegexpIntArg(bufs []*chunk.Column, idx, row int, defaultVal int64) int64 {
+	if idx >= len(bufs) {
+		return defaultVal
+	}
+	return bufs[idx].GetInt64(row)
+}
+
+func (b *builtinRegexpLikeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i], _, err = b.regexpLike(bufs[0].GetString(i), bufs[1].GetString(i), getRegexpStringArg(bufs, 2, i, ""))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpSubstrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isRegexpArgsNull(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		pos := getRegexpIntArg(bufs, 2, i, regexpDefaultPosition)
+		occurrence := getRegexpIntArg(bufs, 3, i, regexpDefaultOccurrence)
+		matchType := getRegexpStringArg(bufs, 4, i, "")
+		substr, isNull, err := b.regexpSubstr(bufs[0].GetString(i), bufs[1].GetString(i), pos, occurrence, matchType)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(substr)
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpInStrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpInStrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		pos := getRegexpIntArg(bufs, 2, i, regexpDefaultPosition)
+		occurrence := getRegexpIntArg(bufs, 3, i, regexpDefaultOccurrence)
+		returnOption := getRegexpIntArg(bufs, 4, i, 0)
+		matchType := getRegexpStringArg(bufs, 5, i, "")
+		i64s[i], _, err = b.regexpInStr(bufs[0].GetString(i), bufs[1].GetString(i), pos, occurrence, returnOption, matchType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isRegexpArgsNull(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		pos := getRegexpIntArg(bufs, 3, i, regexpDefaultPosition)
+		occurrence := getRegexpIntArg(bufs, 4, i, regexpDefaultReplaceOccurrence)
+		matchType := getRegexpStringArg(bufs, 5, i, "")
+		replaced, _, err := b.regexpReplace(bufs[0].GetString(i), bufs[1].GetString(i), bufs[2].GetString(i), pos, occurrence, matchType)
+		if err != nil {
+			return err
+		}
+		result.AppendString(replaced)
+	}
+	return nil
+}
@@ -0,0 +1,528 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var (
+	_ functionClass = &stGeomFromTextFunctionClass{}
+	_ functionClass = &stAsTextFunctionClass{}
+	_ functionClass = &stSRIDFunctionClass{}
+	_ functionClass = &stDistanceSphereFunctionClass{}
+	_ functionClass = &stContainsFunctionClass{}
+	_ functionClass = &stWithinFunctionClass{}
+	_ functionClass = &stIntersectsFunctionClass{}
+	_ functionClass = &stBufferFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinSTGeomFromTextSig{}
+	_ builtinFunc = &builtinSTAsTextSig{}
+	_ builtinFunc = &builtinSTSRIDSig{}
+	_ builtinFunc = &builtinSTSetSRIDSig{}
+	_ builtinFunc = &builtinSTDistanceSphereSig{}
+	_ builtinFunc = &builtinSTContainsSig{}
+	_ builtinFunc = &builtinSTWithinSig{}
+	_ builtinFunc = &builtinSTIntersectsSig{}
+	_ builtinFunc = &builtinSTBufferSig{}
+)
+
+// spatialFuncSharedSig is shared by the spatial functions, the name is reported in the errors.
+type spatialFuncSharedSig struct {
+	baseBuiltinFunc
+	name string
+}
+
+func newSpatialFuncSharedSig(bf baseBuiltinFunc, name string) spatialFuncSharedSig {
+	return spatialFuncSharedSig{baseBuiltinFunc: bf, name: name}
+}
+
+func (b *spatialFuncSharedSig) clone(from *spatialFuncSharedSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+	b.name = from.name
+}
+
+// setGeometryFieldType sets the return type of the functions returning a geometry.
+func setGeometryFieldType(tp *types.FieldType) {
+	tp.SetType(mysql.TypeGeometry)
+	tp.SetGeometryType(types.GeometryTypeGeometry)
+	tp.SetFlen(mysql.MaxBlobWidth)
+	types.SetBinChsClnFlag(tp)
+}
+
+// evalGeometry evaluates the argument as the stored binary of a geometry.
+func evalGeometry(ctx sessionctx.Context, arg Expression, row chunk.Row, funcName string) (*types.Geometry, bool, error) {
+	b, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	g, err := types.DecodeGeometry([]byte(b))
+	if err != nil {
+		return nil, true, types.ErrGISInvalidData.GenWithStackByArgs(funcName)
+	}
+	return g, false, nil
+}
+
+// evalSRID evaluates the argument as a SRID, it must refer to a known spatial reference system.
+func evalSRID(ctx sessionctx.Context, arg Expression, row chunk.Row) (*types.SpatialReferenceSystem, bool, error) {
+	srid, isNull, err := arg.EvalInt(ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	if srid < 0 || srid > math.MaxUint32 {
+		return nil, true, types.ErrSRSNotFound.GenWithStackByArgs(srid)
+	}
+	srs, err := types.GetSpatialReferenceSystem(uint32(srid))
+	if err != nil {
+		return nil, true, err
+	}
+	return srs, false, nil
+}
+
+// evalGeometryPair evaluates the two geometries of the binary spatial functions, which must be in the same spatial
+// reference system.
+func evalGeometryPair(ctx sessionctx.Context, args []Expression, row chunk.Row, funcName string) (g1, g2 *types.Geometry, isNull bool, err error) {
+	g1, isNull, err = evalGeometry(ctx, args[0], row, funcName)
+	if isNull || err != nil {
+		return nil, nil, true, err
+	}
+	g2, isNull, err = evalGeometry(ctx, args[1], row, funcName)
+	if isNull || err != nil {
+		return nil, nil, true, err
+	}
+	if g1.SRID != g2.SRID {
+		return nil, nil, true, errGISDifferentSRIDs.GenWithStackByArgs(funcName, g1.SRID, g2.SRID)
+	}
+	if _, err = types.GetSpatialReferenceSystem(g1.SRID); err != nil {
+		return nil, nil, true, err
+	}
+	return g1, g2, false, nil
+}
+
+type stGeomFromTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stGeomFromTextFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}[:len(args)]
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryFieldType(bf.tp)
+	sig := &builtinSTGeomFromTextSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTGeomFromTextSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTGeomFromTextSig) Clone() builtinFunc {
+	newSig := &builtinSTGeomFromTextSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalString evals `ST_GeomFromText(wkt[, srid])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-wkt-functions.html#function_st-geomfromtext
+func (b *builtinSTGeomFromTextSig) evalString(row chunk.Row) (string, bool, error) {
+	wkt, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	srs, _ := types.GetSpatialReferenceSystem(0)
+	if len(b.args) > 1 {
+		srs, isNull, err = evalSRID(b.ctx, b.args[1], row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	g, err := types.ParseGeometryFromWKT(wkt, srs.SRID)
+	if err != nil {
+		return "", true, types.ErrGISInvalidData.GenWithStackByArgs(b.name)
+	}
+	if srs.Geographic {
+		if err = g.CheckGeographicRange(b.name); err != nil {
+			return "", true, err
+		}
+	}
+	return string(g.Encode()), false, nil
+}
+
+type stAsTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stAsTextFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxBlobWidth)
+	sig := &builtinSTAsTextSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTAsTextSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTAsTextSig) Clone() builtinFunc {
+	newSig := &builtinSTAsTextSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalString evals `ST_AsText(g)`.
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-format-conversion-functions.html#function_st-astext
+func (b *builtinSTAsTextSig) evalString(row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(b.ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return g.String(), false, nil
+}
+
+type stSRIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stSRIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+		if err != nil {
+			return nil, err
+		}
+		bf.tp.SetFlen(10)
+		bf.tp.AddFlag(mysql.UnsignedFlag)
+		sig := &builtinSTSRIDSig{newSpatialFuncSharedSig(bf, c.funcName)}
+		return sig, nil
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryFieldType(bf.tp)
+	sig := &builtinSTSetSRIDSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTSRIDSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTSRIDSig) Clone() builtinFunc {
+	newSig := &builtinSTSRIDSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalInt evals `ST_SRID(g)`.
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-general-property-functions.html#function_st-srid
+func (b *builtinSTSRIDSig) evalInt(row chunk.Row) (int64, bool, error) {
+	g, isNull, err := evalGeometry(b.ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(g.SRID), false, nil
+}
+
+type builtinSTSetSRIDSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTSetSRIDSig) Clone() builtinFunc {
+	newSig := &builtinSTSetSRIDSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalString evals `ST_SRID(g, srid)`, which returns the geometry with its SRID changed and its coordinates kept.
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-general-property-functions.html#function_st-srid
+func (b *builtinSTSetSRIDSig) evalString(row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(b.ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	srs, isNull, err := evalSRID(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if srs.Geographic {
+		if err = g.CheckGeographicRange(b.name); err != nil {
+			return "", true, err
+		}
+	}
+	g.SRID = srs.SRID
+	return string(g.Encode()), false, nil
+}
+
+type stDistanceSphereFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stDistanceSphereFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETReal}[:len(args)]
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinSTDistanceSphereSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTDistanceSphereSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTDistanceSphereSig) Clone() builtinFunc {
+	newSig := &builtinSTDistanceSphereSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalReal evals `ST_Distance_Sphere(g1, g2[, radius])`. The geometries must be points or multipoints, their
+// coordinates are the longitude and the latitude in SRID 0, or the latitude and the longitude in a geographic
+// spatial reference system.
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-convenience-functions.html#function_st-distance-sphere
+func (b *builtinSTDistanceSphereSig) evalReal(row chunk.Row) (float64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(b.ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	radius := float64(types.DefaultSphereRadius)
+	if len(b.args) > 2 {
+		radius, isNull, err = b.args[2].EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if radius <= 0 {
+			return 0, true, errNonpositiveRadius.GenWithStackByArgs(b.name)
+		}
+	}
+	for _, g := range []*types.Geometry{g1, g2} {
+		if g.Type != types.GeometryTypePoint && g.Type != types.GeometryTypeMultiPoint {
+			return 0, true, errGISUnsupportedArgument.GenWithStackByArgs(b.name)
+		}
+	}
+	srs, err := types.GetSpatialReferenceSystem(g1.SRID)
+	if err != nil {
+		return 0, true, err
+	}
+	if !srs.Geographic {
+		if srs.SRID != 0 {
+			return 0, true, errGISUnsupportedArgument.GenWithStackByArgs(b.name)
+		}
+		g1, g2 = g1.SwapXY(), g2.SwapXY()
+	}
+	for _, g := range []*types.Geometry{g1, g2} {
+		if err = g.CheckGeographicRange(b.name); err != nil {
+			return 0, true, err
+		}
+	}
+	return g1.DistanceSphere(g2, radius), false, nil
+}
+
+// newSpatialRelationFunc returns the base of the functions checking the spatial relation of two geometries.
+func newSpatialRelationFunc(ctx sessionctx.Context, c *baseFunctionClass, args []Expression) (baseBuiltinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return baseBuiltinFunc{}, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return baseBuiltinFunc{}, err
+	}
+	bf.tp.SetFlen(1)
+	return bf, nil
+}
+
+type stContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := newSpatialRelationFunc(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinSTContainsSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTContainsSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTContainsSig) Clone() builtinFunc {
+	newSig := &builtinSTContainsSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalInt evals `ST_Contains(g1, g2)`. The relation is computed in the Cartesian plane for all the spatial
+// reference systems.
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-relation-functions-object-shapes.html#function_st-contains
+func (b *builtinSTContainsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(b.ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(g1.Contains(g2)), false, nil
+}
+
+type stWithinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stWithinFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := newSpatialRelationFunc(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinSTWithinSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTWithinSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTWithinSig) Clone() builtinFunc {
+	newSig := &builtinSTWithinSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalInt evals `ST_Within(g1, g2)`.
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-relation-functions-object-shapes.html#function_st-within
+func (b *builtinSTWithinSig) evalInt(row chunk.Row) (int64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(b.ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(g1.Within(g2)), false, nil
+}
+
+type stIntersectsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stIntersectsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := newSpatialRelationFunc(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinSTIntersectsSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTIntersectsSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTIntersectsSig) Clone() builtinFunc {
+	newSig := &builtinSTIntersectsSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalInt evals `ST_Intersects(g1, g2)`.
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-relation-functions-object-shapes.html#function_st-intersects
+func (b *builtinSTIntersectsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(b.ctx, b.args, row, b.name)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(g1.Intersects(g2)), false, nil
+}
+
+type stBufferFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stBufferFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString, types.ETReal)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryFieldType(bf.tp)
+	sig := &builtinSTBufferSig{newSpatialFuncSharedSig(bf, c.funcName)}
+	return sig, nil
+}
+
+type builtinSTBufferSig struct {
+	spatialFuncSharedSig
+}
+
+func (b *builtinSTBufferSig) Clone() builtinFunc {
+	newSig := &builtinSTBufferSig{}
+	newSig.clone(&b.spatialFuncSharedSig)
+	return newSig
+}
+
+// evalString evals `ST_Buffer(g, d)`. Only the points in the Cartesian spatial reference systems are supported, whose
+// buffers are polygons of types.BufferPointsPerCircle points like the default point_circle strategy of MySQL. The other
+// geometries and the geographic spatial reference systems are reported as not implemented.
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-operator-functions.html#function_st-buffer
+func (b *builtinSTBufferSig) evalString(row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(b.ctx, b.args[0], row, b.name)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	d, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	srs, err := types.GetSpatialReferenceSystem(g.SRID)
+	if err != nil {
+		return "", true, err
+	}
+	if srs.Geographic {
+		return "", true, errNotImplementedForGeographicSRS.GenWithStackByArgs(b.name, g.Type)
+	}
+	if g.Type != types.GeometryTypePoint {
+		return "", true, errNotImplementedForCartesianSRS.GenWithStackByArgs(b.name, g.Type)
+	}
+	switch {
+	case d < 0:
+		g = &types.Geometry{Type: types.GeometryTypeGeometryCollection, SRID: g.SRID}
+	case d > 0:
+		g = g.BufferPoint(d, types.BufferPointsPerCircle)
+	}
+	return string(g.Encode()), false, nil
+}
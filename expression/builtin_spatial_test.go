@@ -0,0 +1,217 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+func evalSpatialFunc(t *testing.T, funcName string, args ...interface{}) (types.Datum, error) {
+	ctx := createContext(t)
+	f, err := funcs[funcName].getFunction(ctx, datumsToConstants(types.MakeDatums(args...)))
+	require.NoError(t, err, funcName)
+	return evalBuiltinFunc(f, chunk.Row{})
+}
+
+func mustGeometry(t *testing.T, wkt string, srid uint32) []byte {
+	g, err := types.ParseGeometryFromWKT(wkt, srid)
+	require.NoError(t, err, wkt)
+	return g.Encode()
+}
+
+func TestSpatialFuncTypes(t *testing.T) {
+	ctx := createContext(t)
+	f, err := funcs[ast.StGeomFromText].getFunction(ctx, datumsToConstants(types.MakeDatums("POINT(1 1)")))
+	require.NoError(t, err)
+	tp := f.getRetTp()
+	require.Equal(t, mysql.TypeGeometry, tp.GetType())
+	require.Equal(t, types.GeometryTypeGeometry, tp.GetGeometryType())
+	require.Equal(t, charset.CollationBin, tp.GetCollate())
+
+	f, err = funcs[ast.StAsText].getFunction(ctx, datumsToConstants(types.MakeDatums(mustGeometry(t, "POINT(1 1)", 0))))
+	require.NoError(t, err)
+	require.Equal(t, mysql.TypeLongBlob, f.getRetTp().GetType())
+	require.False(t, types.IsBinaryStr(f.getRetTp()))
+
+	f, err = funcs[ast.StContains].getFunction(ctx, datumsToConstants(types.MakeDatums(nil, nil)))
+	require.NoError(t, err)
+	require.Equal(t, mysql.TypeLonglong, f.getRetTp().GetType())
+}
+
+func TestSTGeomFromTextAndAsText(t *testing.T) {
+	tests := []struct {
+		args     []interface{}
+		expected interface{}
+		srid     int64
+		err      error
+	}{
+		{[]interface{}{"POINT(1 2)"}, "POINT(1 2)", 0, nil},
+		{[]interface{}{"point(1 2)", 4326}, "POINT(1 2)", 4326, nil},
+		{[]interface{}{"MULTIPOINT(1 1,2 2)", 3857}, "MULTIPOINT((1 1),(2 2))", 3857, nil},
+		{[]interface{}{"GEOMCOLLECTION(POINT(1 1))"}, "GEOMETRYCOLLECTION(POINT(1 1))", 0, nil},
+		{[]interface{}{nil}, nil, 0, nil},
+		{[]interface{}{"POINT(1 2)", nil}, nil, 0, nil},
+		{[]interface{}{"POINT(1)"}, nil, 0, types.ErrGISInvalidData},
+		{[]interface{}{"POINT(1 2)", 1234}, nil, 0, types.ErrSRSNotFound},
+		{[]interface{}{"POINT(1 2)", -1}, nil, 0, types.ErrSRSNotFound},
+		{[]interface{}{"POINT(100 2)", 4326}, nil, 0, types.ErrLatitudeOutOfRange},
+		{[]interface{}{"POINT(10 -200)", 4326}, nil, 0, types.ErrLongitudeOutOfRange},
+	}
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%v", tt.args)
+		for _, name := range []string{ast.StGeomFromText, ast.StGeometryFromText} {
+			d, err := evalSpatialFunc(t, name, tt.args...)
+			if tt.err != nil {
+				require.True(t, terror.ErrorEqual(err, tt.err), comment)
+				continue
+			}
+			require.NoError(t, err, comment)
+			if tt.expected == nil {
+				require.True(t, d.IsNull(), comment)
+				continue
+			}
+			wkt, err := evalSpatialFunc(t, ast.StAsText, d.GetBytes())
+			require.NoError(t, err, comment)
+			require.Equal(t, tt.expected, wkt.GetString(), comment)
+			srid, err := evalSpatialFunc(t, ast.StSRID, d.GetBytes())
+			require.NoError(t, err, comment)
+			require.Equal(t, tt.srid, srid.GetInt64(), comment)
+		}
+	}
+
+	_, err := evalSpatialFunc(t, ast.StAsWKT, []byte("abc"))
+	require.True(t, terror.ErrorEqual(err, types.ErrGISInvalidData))
+}
+
+func TestSTSRID(t *testing.T) {
+	d, err := evalSpatialFunc(t, ast.StSRID, mustGeometry(t, "POINT(1 2)", 0), 4326)
+	require.NoError(t, err)
+	require.Equal(t, mustGeometry(t, "POINT(1 2)", 4326), d.GetBytes())
+
+	_, err = evalSpatialFunc(t, ast.StSRID, mustGeometry(t, "POINT(100 2)", 0), 4326)
+	require.True(t, terror.ErrorEqual(err, types.ErrLatitudeOutOfRange))
+	_, err = evalSpatialFunc(t, ast.StSRID, mustGeometry(t, "POINT(1 2)", 0), 1234)
+	require.True(t, terror.ErrorEqual(err, types.ErrSRSNotFound))
+	d, err = evalSpatialFunc(t, ast.StSRID, nil)
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+}
+
+func TestSTDistanceSphere(t *testing.T) {
+	tests := []struct {
+		args     []interface{}
+		expected float64
+		err      error
+	}{
+		// Longitude-latitude in SRID 0, latitude-longitude in SRID 4326.
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "POINT(1 0)", 0)}, 111194.68, nil},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 4326), mustGeometry(t, "POINT(0 1)", 4326)}, 111194.68, nil},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "MULTIPOINT((5 0),(1 0))", 0)}, 111194.68, nil},
+		{[]interface{}{mustGeometry(t, "POINT(0 90)", 0), mustGeometry(t, "POINT(0 -90)", 0), 1}, 3.14159265, nil},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "POINT(1 0)", 0), 0}, 0, errNonpositiveRadius},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "POINT(1 0)", 4326)}, 0, errGISDifferentSRIDs},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 3857), mustGeometry(t, "POINT(1 0)", 3857)}, 0, errGISUnsupportedArgument},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "LINESTRING(0 0,1 1)", 0)}, 0, errGISUnsupportedArgument},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "POINT(0 100)", 0)}, 0, types.ErrLatitudeOutOfRange},
+		{[]interface{}{mustGeometry(t, "POINT(0 0)", 0), mustGeometry(t, "POINT(200 0)", 0)}, 0, types.ErrLongitudeOutOfRange},
+	}
+	for _, tt := range tests {
+		d, err := evalSpatialFunc(t, ast.StDistanceSphere, tt.args...)
+		if tt.err != nil {
+			require.True(t, terror.ErrorEqual(err, tt.err), "%v", err)
+			continue
+		}
+		require.NoError(t, err)
+		require.InDelta(t, tt.expected, d.GetFloat64(), 0.01)
+	}
+
+	d, err := evalSpatialFunc(t, ast.StDistanceSphere, nil, mustGeometry(t, "POINT(1 0)", 0))
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+}
+
+func TestSTRelations(t *testing.T) {
+	square := mustGeometry(t, "POLYGON((0 0,10 0,10 10,0 10,0 0))", 0)
+	tests := []struct {
+		funcName string
+		args     []interface{}
+		expected interface{}
+		err      error
+	}{
+		{ast.StContains, []interface{}{square, mustGeometry(t, "POINT(5 5)", 0)}, int64(1), nil},
+		{ast.StContains, []interface{}{square, mustGeometry(t, "POINT(0 5)", 0)}, int64(0), nil},
+		{ast.StContains, []interface{}{mustGeometry(t, "POINT(5 5)", 0), square}, int64(0), nil},
+		{ast.StWithin, []interface{}{mustGeometry(t, "LINESTRING(1 1,9 9)", 0), square}, int64(1), nil},
+		{ast.StWithin, []interface{}{mustGeometry(t, "LINESTRING(1 1,19 9)", 0), square}, int64(0), nil},
+		{ast.StIntersects, []interface{}{mustGeometry(t, "LINESTRING(1 1,19 9)", 0), square}, int64(1), nil},
+		{ast.StIntersects, []interface{}{mustGeometry(t, "POINT(20 20)", 0), square}, int64(0), nil},
+		{ast.StIntersects, []interface{}{mustGeometry(t, "POINT(5 5)", 4326), square}, nil, errGISDifferentSRIDs},
+		{ast.StIntersects, []interface{}{[]byte("abc"), square}, nil, types.ErrGISInvalidData},
+		{ast.StWithin, []interface{}{nil, square}, nil, nil},
+	}
+	for _, tt := range tests {
+		d, err := evalSpatialFunc(t, tt.funcName, tt.args...)
+		if tt.err != nil {
+			require.True(t, terror.ErrorEqual(err, tt.err), "%v", err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, types.NewDatum(tt.expected), d)
+	}
+}
+
+func TestSTBuffer(t *testing.T) {
+	point := mustGeometry(t, "POINT(1 1)", 0)
+	d, err := evalSpatialFunc(t, ast.StBuffer, point, 2)
+	require.NoError(t, err)
+	g, err := types.DecodeGeometry(d.GetBytes())
+	require.NoError(t, err)
+	require.Equal(t, types.GeometryTypePolygon, g.Type)
+	require.Len(t, g.Rings[0], types.BufferPointsPerCircle+1)
+
+	d, err = evalSpatialFunc(t, ast.StBuffer, point, 0)
+	require.NoError(t, err)
+	require.Equal(t, point, d.GetBytes())
+
+	d, err = evalSpatialFunc(t, ast.StBuffer, point, -1)
+	require.NoError(t, err)
+	require.Equal(t, mustGeometry(t, "GEOMETRYCOLLECTION EMPTY", 0), d.GetBytes())
+
+	// Only the points in the Cartesian spatial reference systems are supported.
+	for _, wkt := range []string{
+		"LINESTRING(0 0,1 1)",
+		"POLYGON((0 0,1 0,1 1,0 0))",
+		"MULTIPOINT((0 0),(1 1))",
+		"GEOMETRYCOLLECTION(POINT(0 0))",
+	} {
+		for _, distance := range []float64{1, 0, -1} {
+			_, err = evalSpatialFunc(t, ast.StBuffer, mustGeometry(t, wkt, 0), distance)
+			require.True(t, terror.ErrorEqual(err, errNotImplementedForCartesianSRS), "%v", err)
+		}
+	}
+	_, err = evalSpatialFunc(t, ast.StBuffer, mustGeometry(t, "POINT(1 1)", 4326), 1)
+	require.True(t, terror.ErrorEqual(err, errNotImplementedForGeographicSRS), "%v", err)
+	_, err = evalSpatialFunc(t, ast.StBuffer, mustGeometry(t, "LINESTRING(0 0,1 1)", 4326), 1)
+	require.True(t, terror.ErrorEqual(err, errNotImplementedForGeographicSRS), "%v", err)
+}
@@ -667,6 +667,14 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 		f = newBuiltinRegexpSig(base)
 	case tipb.ScalarFuncSig_RegexpUTF8Sig:
 		f = newBuiltinRegexpUTF8Sig(base)
+	case tipb.ScalarFuncSig_RegexpLikeSig, tipb.ScalarFuncSig_RegexpLikeUTF8Sig:
+		f = &builtinRegexpLikeSig{newRegexpFuncSharedSig(base, 2)}
+	case tipb.ScalarFuncSig_RegexpSubstrSig, tipb.ScalarFuncSig_RegexpSubstrUTF8Sig:
+		f = &builtinRegexpSubstrSig{newRegexpFuncSharedSig(base, 4)}
+	case tipb.ScalarFuncSig_RegexpInStrSig, tipb.ScalarFuncSig_RegexpInStrUTF8Sig:
+		f = &builtinRegexpInStrSig{newRegexpFuncSharedSig(base, 5)}
+	case tipb.ScalarFuncSig_RegexpReplaceSig, tipb.ScalarFuncSig_RegexpReplaceUTF8Sig:
+		f = &builtinRegexpReplaceSig{newRegexpFuncSharedSig(base, 5)}
 	case tipb.ScalarFuncSig_JsonExtractSig:
 		f = &builtinJSONExtractSig{base}
 	case tipb.ScalarFuncSig_JsonUnquoteSig:
@@ -36,6 +36,10 @@ var (
 	ErrInvalidTableSample          = dbterror.ClassExpression.NewStd(mysql.ErrInvalidTableSample)
 	ErrInternal                    = dbterror.ClassOptimizer.NewStd(mysql.ErrInternal)
 	ErrNoDB                        = dbterror.ClassOptimizer.NewStd(mysql.ErrNoDB)
+	ErrCantInitializeUDF           = dbterror.ClassExpression.NewStd(mysql.ErrCantInitializeUdf)
+	ErrCantOpenLibrary             = dbterror.ClassExpression.NewStd(mysql.ErrCantOpenLibrary)
+	ErrCantFindDlEntry             = dbterror.ClassExpression.NewStd(mysql.ErrCantFindDlEntry)
+	ErrNativeFctNameCollision      = dbterror.ClassExpression.NewStd(mysql.ErrNativeFctNameCollision)
 
 	// All the un-exported errors are defined here:
 	errFunctionNotExists             = dbterror.ClassExpression.NewStd(mysql.ErrSpDoesNotExist)
@@ -55,6 +59,13 @@ var (
 	errSpecificAccessDenied          = dbterror.ClassExpression.NewStd(mysql.ErrSpecificAccessDenied)
 	errUserLockDeadlock              = dbterror.ClassExpression.NewStd(mysql.ErrUserLockDeadlock)
 	errUserLockWrongName             = dbterror.ClassExpression.NewStd(mysql.ErrUserLockWrongName)
+	errGISDifferentSRIDs             = dbterror.ClassExpression.NewStd(mysql.ErrGISDifferentSRIDs)
+	errGISUnsupportedArgument        = dbterror.ClassExpression.NewStd(mysql.ErrGISUnsupportedArgument)
+	errNonpositiveRadius             = dbterror.ClassExpression.NewStd(mysql.ErrNonpositiveRadius)
+
+	// Spatial function usages which are not implemented.
+	errNotImplementedForCartesianSRS  = dbterror.ClassExpression.NewStd(mysql.ErrNotImplementedForCartesianSRS)
+	errNotImplementedForGeographicSRS = dbterror.ClassExpression.NewStd(mysql.ErrNotImplementedForGeographicSRS)
 
 	// Sequence usage privilege check.
 	errSequenceAccessDenied      = dbterror.ClassExpression.NewStd(mysql.ErrTableaccessDenied)
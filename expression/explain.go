@@ -44,6 +44,28 @@ func (expr *ScalarFunction) explainInfo(normalized bool) string {
 			buffer.WriteString(", ")
 			buffer.WriteString(expr.RetType.String())
 		}
+	case ast.FulltextMatch:
+		// The arguments are the search string, the index information and the columns.
+		args := expr.GetArgs()
+		for i, arg := range args[2:] {
+			if normalized {
+				buffer.WriteString(arg.ExplainNormalizedInfo())
+			} else {
+				buffer.WriteString(arg.ExplainInfo())
+			}
+			if i+3 < len(args) {
+				buffer.WriteString(", ")
+			}
+		}
+		buffer.WriteString(") against(")
+		if normalized {
+			buffer.WriteString(args[0].ExplainNormalizedInfo())
+		} else {
+			buffer.WriteString(args[0].ExplainInfo())
+		}
+		if info, err := GetFulltextMatchInfo(expr); err == nil && info.BooleanMode {
+			buffer.WriteString(" in boolean mode")
+		}
 	default:
 		for i, arg := range expr.GetArgs() {
 			if normalized {
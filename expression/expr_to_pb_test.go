@@ -1064,6 +1064,23 @@ func TestExprPushDownToFlash(t *testing.T) {
 	require.NoError(t, err)
 	exprs = append(exprs, function)
 
+	// regexp_like, regexp_substr, regexp_instr, regexp_replace: supported
+	function, err = NewFunction(mock.NewContext(), ast.RegexpLike, types.NewFieldType(mysql.TypeLonglong), stringColumn, stringColumn, stringColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	function, err = NewFunction(mock.NewContext(), ast.RegexpSubstr, types.NewFieldType(mysql.TypeString), stringColumn, stringColumn, intColumn, intColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	function, err = NewFunction(mock.NewContext(), ast.RegexpInStr, types.NewFieldType(mysql.TypeLonglong), binaryStringColumn, binaryStringColumn, intColumn, intColumn, intColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	function, err = NewFunction(mock.NewContext(), ast.RegexpReplace, types.NewFieldType(mysql.TypeString), stringColumn, stringColumn, stringColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
 	// greatest
 	function, err = NewFunction(mock.NewContext(), ast.Greatest, types.NewFieldType(mysql.TypeLonglong), int32Column, intColumn)
 	require.NoError(t, err)
@@ -1399,6 +1416,26 @@ func TestExprPushDownToTiKV(t *testing.T) {
 			retType:      types.NewFieldType(mysql.TypeInt24),
 			args:         []Expression{intColumn, intColumn},
 		},
+		{
+			functionName: ast.RegexpLike,
+			retType:      types.NewFieldType(mysql.TypeLonglong),
+			args:         []Expression{stringColumn, stringColumn},
+		},
+		{
+			functionName: ast.RegexpSubstr,
+			retType:      types.NewFieldType(mysql.TypeString),
+			args:         []Expression{stringColumn, stringColumn, intColumn},
+		},
+		{
+			functionName: ast.RegexpInStr,
+			retType:      types.NewFieldType(mysql.TypeLonglong),
+			args:         []Expression{binaryStringColumn, binaryStringColumn},
+		},
+		{
+			functionName: ast.RegexpReplace,
+			retType:      types.NewFieldType(mysql.TypeString),
+			args:         []Expression{stringColumn, stringColumn, stringColumn, intColumn, intColumn, stringColumn},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -34,22 +34,35 @@ var UnCacheableFunctions = map[string]struct{}{
 
 // unFoldableFunctions stores functions which can not be folded duration constant folding stage.
 var unFoldableFunctions = map[string]struct{}{
-	ast.Sysdate:   {},
-	ast.FoundRows: {},
-	ast.Rand:      {},
-	ast.UUID:      {},
-	ast.Sleep:     {},
-	ast.RowFunc:   {},
-	ast.Values:    {},
-	ast.SetVar:    {},
-	ast.GetVar:    {},
-	ast.GetParam:  {},
-	ast.Benchmark: {},
-	ast.DayName:   {},
-	ast.NextVal:   {},
-	ast.LastVal:   {},
-	ast.SetVal:    {},
-	ast.AnyValue:  {},
+	ast.Sysdate:       {},
+	ast.FoundRows:     {},
+	ast.Rand:          {},
+	ast.UUID:          {},
+	ast.Sleep:         {},
+	ast.RowFunc:       {},
+	ast.Values:        {},
+	ast.SetVar:        {},
+	ast.GetVar:        {},
+	ast.GetParam:      {},
+	ast.Benchmark:     {},
+	ast.DayName:       {},
+	ast.NextVal:       {},
+	ast.LastVal:       {},
+	ast.SetVal:        {},
+	ast.AnyValue:      {},
+	ast.FulltextMatch: {},
+}
+
+// isUnFoldableFunction checks whether the function can not be folded during constant folding stage.
+// The non-deterministic user-defined functions are not foldable either.
+func isUnFoldableFunction(name string) bool {
+	if _, ok := unFoldableFunctions[name]; ok {
+		return true
+	}
+	if _, ok := funcs[name]; ok {
+		return false
+	}
+	return isNonDeterministicUDF(name)
 }
 
 // DisableFoldFunctions stores functions which prevent child scope functions from being constant folded.
@@ -250,6 +263,7 @@ var booleanFunctions = map[string]struct{}{
 	ast.IsNull:             {},
 	ast.Like:               {},
 	ast.Regexp:             {},
+	ast.RegexpLike:         {},
 	ast.IsIPv4:             {},
 	ast.IsIPv4Compat:       {},
 	ast.IsIPv4Mapped:       {},
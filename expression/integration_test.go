@@ -7560,3 +7560,97 @@ func TestIssue36358(t *testing.T) {
 	tk.MustQuery("select extract(day_microsecond from cast('2001-01-01 02:03:04.050607' as datetime(6))) from t").Check(testkit.Rows("1020304050607"))
 	tk.MustQuery("select extract(day_microsecond from c) from t").Check(testkit.Rows("1020304050607"))
 }
+
+func TestRegexpFunctions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a varchar(20), b varchar(20) collate utf8mb4_general_ci, c varbinary(20))")
+	tk.MustExec("insert into t values ('abc def', 'ABC def', 'abc def'), ('你好 世界', '你好 世界', 'xyz'), (null, null, null)")
+
+	tk.MustQuery("select regexp_like(a, 'B'), regexp_like(b, 'b'), regexp_like(c, 'B'), regexp_like(a, 'B', 'i'), regexp_like(b, 'b', 'c') from t").
+		Check(testkit.Rows("0 1 0 1 0", "0 0 0 0 0", "<nil> <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select regexp_substr(a, '[^ ]+', 1, 2), regexp_instr(a, '[^ ]+', 1, 2), regexp_instr(a, '[^ ]+', 1, 2, 1), regexp_replace(a, '[^ ]+', 'X', 1, 2) from t").
+		Check(testkit.Rows("def 5 8 abc X", "世界 4 6 你好 X", "<nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select regexp_replace(a, '([a-z]+) ([a-z]+)', '$2 $1'), regexp_replace(b, 'abc', 'x'), regexp_substr(c, 'z$') from t").
+		Check(testkit.Rows("def abc x def <nil>", "你好 世界 你好 世界 z", "<nil> <nil> <nil>"))
+
+	// The functions are pushed down, and they are evaluated by the coprocessor.
+	pushedDown := false
+	for _, row := range tk.MustQuery("explain format='brief' select * from t where regexp_like(a, '^abc') and regexp_instr(b, 'def') > 0").Rows() {
+		if row[2] == "cop[tikv]" && strings.Contains(row[4].(string), "regexp_like") && strings.Contains(row[4].(string), "regexp_instr") {
+			pushedDown = true
+		}
+	}
+	require.True(t, pushedDown)
+	tk.MustQuery("select a from t where regexp_like(a, '^abc') and regexp_instr(b, 'DEF') > 0").Check(testkit.Rows("abc def"))
+	tk.MustQuery("select a from t where regexp_substr(a, '世.') = '世界'").Check(testkit.Rows("你好 世界"))
+
+	for sql, errMsg := range map[string]string{
+		"select regexp_like('a', '')":              "Illegal argument to a regular expression.",
+		"select regexp_like('a', 'a', 'x')":        "Invalid match mode flag in regular expression.",
+		"select regexp_substr('abc', 'a', 5)":      "Index out of bounds in regular expression search.",
+		"select regexp_instr('abc', 'a', 1, 1, 2)": "return_option must be 1 or 0.",
+	} {
+		require.ErrorContains(t, tk.QueryToErr(sql), errMsg, sql)
+	}
+}
+
+func TestSpatialFunctions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, g geometry, p point srid 4326)")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `g` geometry DEFAULT NULL,\n" +
+		"  `p` point DEFAULT NULL /*!80003 SRID 4326 */,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into t values " +
+		"(1, st_geomfromtext('POLYGON((0 0,10 0,10 10,0 10,0 0))'), st_geomfromtext('POINT(39.9 116.4)', 4326)), " +
+		"(2, st_geomfromtext('LINESTRING(-5 5,5 5)'), st_geomfromtext('POINT(31.2 121.5)', 4326)), " +
+		"(3, null, null)")
+	tk.MustQuery("select id, st_astext(g), st_srid(g), st_astext(p), st_srid(p) from t order by id").Check(testkit.Rows(
+		"1 POLYGON((0 0,10 0,10 10,0 10,0 0)) 0 POINT(39.9 116.4) 4326",
+		"2 LINESTRING(-5 5,5 5) 0 POINT(31.2 121.5) 4326",
+		"3 <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select id from t where st_contains(g, st_geomfromtext('POINT(1 1)'))").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where st_intersects(g, st_geomfromtext('POINT(0 5)')) order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where st_within(st_geomfromtext('POINT(1 1)'), st_buffer(st_geomfromtext('POINT(0 0)'), 2))").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select round(st_distance_sphere(a.p, b.p)) from t a, t b where a.id = 1 and b.id = 2").Check(testkit.Rows("1071283"))
+	tk.MustQuery("select st_astext(st_buffer(st_geomfromtext('POINT(0 0)'), -1)), st_astext(st_srid(st_geomfromtext('POINT(1 2)'), 4326))").
+		Check(testkit.Rows("GEOMETRYCOLLECTION EMPTY POINT(1 2)"))
+
+	// The values must be geometries of the column's type and SRID.
+	tk.MustGetErrCode("insert into t(id, g) values (4, 'abc')", errno.ErrCantCreateGeometryObject)
+	tk.MustGetErrCode("insert into t(id, p) values (4, st_geomfromtext('LINESTRING(0 0,1 1)', 4326))", errno.ErrCantCreateGeometryObject)
+	tk.MustGetErrCode("insert into t(id, p) values (4, st_geomfromtext('POINT(1 1)'))", errno.ErrWrongSRIDForColumn)
+	err := tk.QueryToErr("select st_contains(g, p) from t")
+	require.EqualError(t, err, "[expression:3033]Binary geometry function st_contains given two geometries of different srids: 0 and 4326, which should have been identical.")
+	err = tk.QueryToErr("select st_geomfromtext('POINT(1 1', 0)")
+	require.True(t, types.ErrGISInvalidData.Equal(err))
+	err = tk.QueryToErr("select st_geomfromtext('POINT(1 1)', 1)")
+	require.True(t, types.ErrSRSNotFound.Equal(err))
+	err = tk.QueryToErr("select st_geomfromtext('POINT(91 1)', 4326)")
+	require.True(t, types.ErrLatitudeOutOfRange.Equal(err))
+	err = tk.QueryToErr("select st_distance_sphere(p, p, 0) from t")
+	require.EqualError(t, err, "[expression:3706]Invalid radius provided to function st_distance_sphere: Radius must be greater than zero.")
+	err = tk.QueryToErr("select st_buffer(g, 1) from t")
+	require.EqualError(t, err, "[expression:3704]st_buffer(polygon) has not been implemented for Cartesian spatial reference systems.")
+	err = tk.QueryToErr("select st_buffer(g, 1) from t where id = 2")
+	require.EqualError(t, err, "[expression:3704]st_buffer(linestring) has not been implemented for Cartesian spatial reference systems.")
+	err = tk.QueryToErr("select st_buffer(p, 1) from t")
+	require.EqualError(t, err, "[expression:3618]st_buffer(point) has not been implemented for geographic spatial reference systems.")
+
+	// DDL on the geometry columns.
+	tk.MustGetErrCode("create table t1(a int srid 0)", errno.ErrWrongUsage)
+	tk.MustGetErrCode("create table t1(a geometry srid 1)", errno.ErrSRSNotFound)
+	tk.MustGetErrCode("create table t1(a geometry default 'abc')", errno.ErrBlobCantHaveDefault)
+	tk.MustGetErrCode("create table t1(a geometry, key(a))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t modify p point srid 0", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t modify p geometry srid 4326", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t modify g varchar(100)", errno.ErrUnsupportedDDLOperation)
+}
@@ -194,11 +194,18 @@ func newFunctionImpl(ctx sessionctx.Context, fold int, funcName string, retType
 	}
 	fc, ok := funcs[funcName]
 	if !ok {
-		db := ctx.GetSessionVars().CurrentDB
-		if db == "" {
-			return nil, errors.Trace(ErrNoDB)
+		udf, err := getCreatedUDF(funcName)
+		if err != nil {
+			return nil, err
+		}
+		if udf == nil {
+			db := ctx.GetSessionVars().CurrentDB
+			if db == "" {
+				return nil, errors.Trace(ErrNoDB)
+			}
+			return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", db+"."+funcName)
 		}
-		return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", db+"."+funcName)
+		fc = newUDFFunctionClass(funcName, udf)
 	}
 	noopFuncsMode := ctx.GetSessionVars().NoopFuncsMode
 	if noopFuncsMode != variable.OnInt {
@@ -324,7 +331,7 @@ func (sf *ScalarFunction) IsCorrelated() bool {
 // ConstItem implements Expression interface.
 func (sf *ScalarFunction) ConstItem(sc *stmtctx.StatementContext) bool {
 	// Note: some unfoldable functions are deterministic, we use unFoldableFunctions here for simplification.
-	if _, ok := unFoldableFunctions[sf.FuncName.L]; ok {
+	if isUnFoldableFunction(sf.FuncName.L) {
 		return false
 	}
 	for _, arg := range sf.GetArgs() {
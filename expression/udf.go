@@ -0,0 +1,342 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// UDF is a scalar user-defined function provided by a function plugin.
+// A UDF can be called after it's created by CREATE FUNCTION ... SONAME.
+type UDF struct {
+	// Name is the name of the function, it's case-insensitive.
+	Name string
+	// ArgTypes are the types of the arguments, the arguments are converted to these types before the function is called.
+	// Only ETInt, ETReal, ETDecimal and ETString are supported.
+	ArgTypes []types.EvalType
+	// Variadic indicates that the last argument can be repeated any number of times.
+	Variadic bool
+	// RetType is the type of the result. Only ETInt, ETReal, ETDecimal and ETString are supported.
+	RetType types.EvalType
+	// Deterministic indicates that the function always returns the same result for the same arguments,
+	// so it can be folded when all the arguments are constants.
+	Deterministic bool
+	// VecEval evaluates the function on numRows rows, args[i] holds the values of the i-th argument.
+	// For the INTEGER, REAL and DECIMAL results, result is resized to numRows rows before the call and VecEval sets
+	// the values and the null flags. For the STRING results, result is empty and VecEval appends numRows values.
+	// It may be called concurrently.
+	VecEval func(args []*chunk.Column, numRows int, result *chunk.Column) error
+}
+
+func (u *UDF) argType(i int) types.EvalType {
+	if i >= len(u.ArgTypes) {
+		return u.ArgTypes[len(u.ArgTypes)-1]
+	}
+	return u.ArgTypes[i]
+}
+
+func isSupportedUDFType(tp types.EvalType) bool {
+	switch tp {
+	case types.ETInt, types.ETReal, types.ETDecimal, types.ETString:
+		return true
+	}
+	return false
+}
+
+func (u *UDF) validate() error {
+	name := strings.ToLower(u.Name)
+	if _, ok := funcs[name]; ok {
+		return ErrNativeFctNameCollision.GenWithStackByArgs(u.Name)
+	}
+	if u.VecEval == nil {
+		return ErrCantInitializeUDF.GenWithStackByArgs(u.Name, "VecEval is not defined")
+	}
+	if u.Variadic && len(u.ArgTypes) == 0 {
+		return ErrCantInitializeUDF.GenWithStackByArgs(u.Name, "a variadic function must have at least one argument")
+	}
+	for _, tp := range u.ArgTypes {
+		if !isSupportedUDFType(tp) {
+			return ErrCantInitializeUDF.GenWithStackByArgs(u.Name, "unsupported argument type")
+		}
+	}
+	if !isSupportedUDFType(u.RetType) {
+		return ErrCantInitializeUDF.GenWithStackByArgs(u.Name, "unsupported return type")
+	}
+	return nil
+}
+
+// udfs keeps the functions provided by the function plugins and the functions created from them.
+var udfs = struct {
+	sync.RWMutex
+	// loaded maps the plugin names to the functions they provide.
+	loaded map[string]map[string]*UDF
+	// created maps the names of the created functions to the plugin names.
+	created map[string]string
+}{
+	loaded:  make(map[string]map[string]*UDF),
+	created: make(map[string]string),
+}
+
+// RegisterUDFs makes the functions provided by the plugin available for CREATE FUNCTION ... SONAME.
+// The functions registered by the plugin before are replaced.
+func RegisterUDFs(pluginName string, fns []*UDF) error {
+	loaded := make(map[string]*UDF, len(fns))
+	for _, fn := range fns {
+		if err := fn.validate(); err != nil {
+			return err
+		}
+		loaded[strings.ToLower(fn.Name)] = fn
+	}
+	udfs.Lock()
+	udfs.loaded[pluginName] = loaded
+	udfs.Unlock()
+	return nil
+}
+
+// GetLoadedUDF returns the function provided by the plugin.
+func GetLoadedUDF(pluginName, name string) (*UDF, error) {
+	udfs.RLock()
+	defer udfs.RUnlock()
+	return getLoadedUDF(pluginName, name)
+}
+
+func getLoadedUDF(pluginName, name string) (*UDF, error) {
+	fns, ok := udfs.loaded[pluginName]
+	if !ok {
+		return nil, ErrCantOpenLibrary.GenWithStackByArgs(pluginName, 0, "function plugin is not loaded")
+	}
+	udf, ok := fns[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrCantFindDlEntry.GenWithStackByArgs(name)
+	}
+	return udf, nil
+}
+
+// SetCreatedUDFs replaces the created functions, created maps the lower-case names of the functions to the plugin
+// names. It's called when the cache of mysql.func is rebuilt, created must not be modified after the call.
+func SetCreatedUDFs(created map[string]string) {
+	udfs.Lock()
+	udfs.created = created
+	udfs.Unlock()
+}
+
+// IsCreatedUDF checks whether the function is created by CREATE FUNCTION ... SONAME.
+func IsCreatedUDF(name string) bool {
+	udfs.RLock()
+	defer udfs.RUnlock()
+	_, ok := udfs.created[strings.ToLower(name)]
+	return ok
+}
+
+// getCreatedUDF returns the created function. It returns nil if the function isn't created.
+func getCreatedUDF(name string) (*UDF, error) {
+	udfs.RLock()
+	defer udfs.RUnlock()
+	pluginName, ok := udfs.created[name]
+	if !ok {
+		return nil, nil
+	}
+	return getLoadedUDF(pluginName, name)
+}
+
+func isNonDeterministicUDF(name string) bool {
+	udf, err := getCreatedUDF(name)
+	return err == nil && udf != nil && !udf.Deterministic
+}
+
+type udfFunctionClass struct {
+	baseFunctionClass
+	udf *UDF
+}
+
+func newUDFFunctionClass(name string, udf *UDF) *udfFunctionClass {
+	maxArgs := len(udf.ArgTypes)
+	if udf.Variadic {
+		maxArgs = -1
+	}
+	return &udfFunctionClass{baseFunctionClass{name, len(udf.ArgTypes), maxArgs}, udf}
+}
+
+func (c *udfFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := range args {
+		argTps = append(argTps, c.udf.argType(i))
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, c.udf.RetType, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinUDFSig{bf, c.udf}
+	return sig, nil
+}
+
+type builtinUDFSig struct {
+	baseBuiltinFunc
+	udf *UDF
+}
+
+func (b *builtinUDFSig) Clone() builtinFunc {
+	newSig := &builtinUDFSig{udf: b.udf}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// call calls the function on the evaluated arguments.
+func (b *builtinUDFSig) call(args []*chunk.Column, n int, result *chunk.Column) error {
+	switch b.udf.RetType {
+	case types.ETInt:
+		result.ResizeInt64(n, false)
+	case types.ETReal:
+		result.ResizeFloat64(n, false)
+	case types.ETDecimal:
+		result.ResizeDecimal(n, false)
+	default:
+		result.ReserveString(n)
+	}
+	return b.udf.VecEval(args, n, result)
+}
+
+// evalRow evaluates the function on a single row, it returns a column with one element.
+func (b *builtinUDFSig) evalRow(row chunk.Row) (*chunk.Column, error) {
+	args := make([]*chunk.Column, 0, len(b.args))
+	for i, arg := range b.args {
+		col := chunk.NewColumn(arg.GetType(), 1)
+		switch b.udf.argType(i) {
+		case types.ETInt:
+			v, isNull, err := arg.EvalInt(b.ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			if isNull {
+				col.AppendNull()
+			} else {
+				col.AppendInt64(v)
+			}
+		case types.ETReal:
+			v, isNull, err := arg.EvalReal(b.ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			if isNull {
+				col.AppendNull()
+			} else {
+				col.AppendFloat64(v)
+			}
+		case types.ETDecimal:
+			v, isNull, err := arg.EvalDecimal(b.ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			if isNull {
+				col.AppendNull()
+			} else {
+				col.AppendMyDecimal(v)
+			}
+		default:
+			v, isNull, err := arg.EvalString(b.ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			if isNull {
+				col.AppendNull()
+			} else {
+				col.AppendString(v)
+			}
+		}
+		args = append(args, col)
+	}
+	result := chunk.NewColumn(b.tp, 1)
+	return result, b.call(args, 1, result)
+}
+
+func (b *builtinUDFSig) evalInt(row chunk.Row) (int64, bool, error) {
+	result, err := b.evalRow(row)
+	if err != nil || result.IsNull(0) {
+		return 0, true, err
+	}
+	return result.GetInt64(0), false, nil
+}
+
+func (b *builtinUDFSig) evalReal(row chunk.Row) (float64, bool, error) {
+	result, err := b.evalRow(row)
+	if err != nil || result.IsNull(0) {
+		return 0, true, err
+	}
+	return result.GetFloat64(0), false, nil
+}
+
+func (b *builtinUDFSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	result, err := b.evalRow(row)
+	if err != nil || result.IsNull(0) {
+		return nil, true, err
+	}
+	return result.GetDecimal(0), false, nil
+}
+
+func (b *builtinUDFSig) evalString(row chunk.Row) (string, bool, error) {
+	result, err := b.evalRow(row)
+	if err != nil || result.IsNull(0) {
+		return "", true, err
+	}
+	return result.GetString(0), false, nil
+}
+
+func (b *builtinUDFSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUDFSig) vecEval(input *chunk.Chunk, result *chunk.Column) error {
+	args := make([]*chunk.Column, 0, len(b.args))
+	defer func() {
+		for _, buf := range args {
+			b.bufAllocator.put(buf)
+		}
+	}()
+	for i, arg := range b.args {
+		buf, err := b.bufAllocator.get()
+		if err != nil {
+			return err
+		}
+		args = append(args, buf)
+		if err := EvalExpr(b.ctx, arg, b.udf.argType(i), input, buf); err != nil {
+			return err
+		}
+	}
+	return b.call(args, input.NumRows(), result)
+}
+
+func (b *builtinUDFSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEval(input, result)
+}
+
+func (b *builtinUDFSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEval(input, result)
+}
+
+func (b *builtinUDFSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEval(input, result)
+}
+
+func (b *builtinUDFSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEval(input, result)
+}
@@ -1035,7 +1035,7 @@ func BuildNotNullExpr(ctx sessionctx.Context, expr Expression) Expression {
 func IsRuntimeConstExpr(expr Expression) bool {
 	switch x := expr.(type) {
 	case *ScalarFunction:
-		if _, ok := unFoldableFunctions[x.FuncName.L]; ok {
+		if isUnFoldableFunction(x.FuncName.L) {
 			return false
 		}
 		for _, arg := range x.GetArgs() {
@@ -1058,7 +1058,7 @@ func CheckNonDeterministic(e Expression) bool {
 	case *Constant, *Column, *CorrelatedColumn:
 		return false
 	case *ScalarFunction:
-		if _, ok := unFoldableFunctions[x.FuncName.L]; ok {
+		if isUnFoldableFunction(x.FuncName.L) {
 			return true
 		}
 		for _, arg := range x.GetArgs() {
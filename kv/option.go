@@ -163,4 +163,12 @@ const (
 	InternalTxnBR = InternalTxnTools
 	// InternalTxnTrace handles the trace statement.
 	InternalTxnTrace = "Trace"
+	// InternalTxnMaterializedView is the type of the txns that refresh materialized views.
+	InternalTxnMaterializedView = InternalTxnOthers
+	// InternalTxnRoutine is the type of the txns that read and write stored routines.
+	InternalTxnRoutine = InternalTxnOthers
+	// InternalTxnEvent is the type of the txns that read and write events, and the txns of the event scheduler.
+	InternalTxnEvent = InternalTxnOthers
+	// InternalTxnUDF is the type of the txns that read and write the loadable functions.
+	InternalTxnUDF = InternalTxnOthers
 )
@@ -59,6 +59,45 @@ func (n *IndexAdviseStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+var _ StmtNode = &RecommendIndexStmt{}
+
+// RecommendIndexStmt is used to recommend indexes for the workload or the given SQL.
+type RecommendIndexStmt struct {
+	stmtNode
+
+	// SQL is the statement to recommend indexes for, the top statements in the statement summary are used if it's empty.
+	SQL         string
+	MaxMinutes  uint64
+	MaxIndexNum *MaxIndexNumClause
+}
+
+// Restore implements Node interface.
+func (n *RecommendIndexStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RECOMMEND INDEX")
+	if n.SQL != "" {
+		ctx.WriteKeyWord(" FOR ")
+		ctx.WriteString(n.SQL)
+	}
+	if n.MaxMinutes != UnspecifiedSize {
+		ctx.WriteKeyWord(" MAX_MINUTES ")
+		ctx.WritePlainf("%d", n.MaxMinutes)
+	}
+	if n.MaxIndexNum != nil {
+		return n.MaxIndexNum.Restore(ctx)
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *RecommendIndexStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*RecommendIndexStmt)
+	return v.Leave(n)
+}
+
 // MaxIndexNumClause represents 'maximum number of indexes' clause in index advise statement.
 type MaxIndexNumClause struct {
 	PerTable uint64
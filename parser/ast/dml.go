@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
@@ -93,25 +94,31 @@ func (*Join) resultSet() {}
 // NewCrossJoin builds a cross join without `on` or `using` clause.
 // If the right child is a join tree, we need to handle it differently to make the precedence get right.
 // Here is the example: t1 join t2 join t3
-//                 JOIN ON t2.a = t3.a
-//  t1    join    /    \
-//              t2      t3
+//
+//	               JOIN ON t2.a = t3.a
+//	t1    join    /    \
+//	            t2      t3
+//
 // (left)         (right)
 //
 // We can not build it directly to:
-//         JOIN
-//        /    \
-//       t1	   JOIN ON t2.a = t3.a
-//             /   \
-//            t2    t3
+//
+//	  JOIN
+//	 /    \
+//	t1	   JOIN ON t2.a = t3.a
+//	      /   \
+//	     t2    t3
+//
 // The precedence would be t1 join (t2 join t3 on t2.a=t3.a), not (t1 join t2) join t3 on t2.a=t3.a
 // We need to find the left-most child of the right child, and build a cross join of the left-hand side
 // of the left child(t1), and the right hand side with the original left-most child of the right child(t2).
-//          JOIN t2.a = t3.a
-//         /    \
-//       JOIN    t3
-//       /  \
-//      t1  t2
+//
+//	    JOIN t2.a = t3.a
+//	   /    \
+//	 JOIN    t3
+//	 /  \
+//	t1  t2
+//
 // Besides, if the right handle side join tree's join type is right join and has explicit parentheses, we need to rewrite it to left join.
 // So t1 join t2 right join t3 would be rewrite to t1 join t3 left join t2.
 // If not, t1 join (t2 right join t3) would be (t1 join t2) right join t3. After rewrite the right join to left join.
@@ -507,11 +514,15 @@ type TableSource struct {
 	node
 
 	// Source is the source of the data, can be a TableName,
-	// a SelectStmt, a SetOprStmt, or a JoinNode.
+	// a SelectStmt, a SetOprStmt, a JoinNode, or a JSONTable.
 	Source ResultSetNode
 
 	// AsName is the alias name of the table source.
 	AsName model.CIStr
+
+	// Lateral indicates whether the derived table is a LATERAL one, which can refer to
+	// the columns of the preceding tables in the same FROM clause.
+	Lateral bool
 }
 
 func (*TableSource) resultSet() {}
@@ -558,6 +569,9 @@ func (n *TableSource) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain(")")
 		}
 	} else {
+		if n.Lateral {
+			ctx.WriteKeyWord("LATERAL ")
+		}
 		if needParen {
 			ctx.WritePlain("(")
 		}
@@ -591,6 +605,164 @@ func (n *TableSource) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// JSONTable represents the JSON_TABLE table function, which extracts the data from a JSON document and returns it
+// as a relational table.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-table-functions.html
+type JSONTable struct {
+	node
+
+	// Expr is the JSON document, it can refer to the columns of the preceding tables in the same FROM clause.
+	Expr ExprNode
+	// Path is the path of the rows in the JSON document.
+	Path string
+	// Columns are the columns of the result table.
+	Columns []*JSONTableColumn
+}
+
+func (*JSONTable) resultSet() {}
+
+// Restore implements Node interface.
+func (n *JSONTable) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("JSON_TABLE")
+	ctx.WritePlain("(")
+	if err := n.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTable.Expr")
+	}
+	ctx.WritePlain(", ")
+	ctx.WriteString(n.Path)
+	ctx.WritePlain(" ")
+	if err := restoreJSONTableColumns(ctx, n.Columns); err != nil {
+		return err
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *JSONTable) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*JSONTable)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
+func restoreJSONTableColumns(ctx *format.RestoreCtx, columns []*JSONTableColumn) error {
+	ctx.WriteKeyWord("COLUMNS ")
+	ctx.WritePlain("(")
+	for i, col := range columns {
+		if i > 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := col.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore JSONTable.Columns[%d]", i)
+		}
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// JSONTableColumnType is the type of the JSON_TABLE column.
+type JSONTableColumnType int
+
+// JSON_TABLE column types.
+const (
+	// JSONTableColumnPath is the column which extracts its value by the path: `name type PATH path`.
+	JSONTableColumnPath JSONTableColumnType = iota
+	// JSONTableColumnExists is the column which checks whether the path exists: `name type EXISTS PATH path`.
+	JSONTableColumnExists
+	// JSONTableColumnOrdinality is the row counter starting from 1: `name FOR ORDINALITY`.
+	JSONTableColumnOrdinality
+	// JSONTableColumnNested flattens the nested arrays and objects: `NESTED PATH path COLUMNS (...)`.
+	JSONTableColumnNested
+)
+
+// JSONTableColumn is a column of the JSON_TABLE, or a NESTED PATH clause which contains the columns.
+type JSONTableColumn struct {
+	Tp JSONTableColumnType
+	// Name and FieldType are not set for the NESTED PATH clause.
+	Name      model.CIStr
+	FieldType *types.FieldType
+	Path      string
+	// OnEmpty and OnError are only set for the JSONTableColumnPath column.
+	OnEmpty *JSONTableOnResponse
+	OnError *JSONTableOnResponse
+	// NestedColumns are the columns of the NESTED PATH clause.
+	NestedColumns []*JSONTableColumn
+}
+
+// Restore implements Node interface.
+func (n *JSONTableColumn) Restore(ctx *format.RestoreCtx) error {
+	if n.Tp == JSONTableColumnNested {
+		ctx.WriteKeyWord("NESTED PATH ")
+		ctx.WriteString(n.Path)
+		ctx.WritePlain(" ")
+		return restoreJSONTableColumns(ctx, n.NestedColumns)
+	}
+	ctx.WriteName(n.Name.O)
+	if n.Tp == JSONTableColumnOrdinality {
+		ctx.WriteKeyWord(" FOR ORDINALITY")
+		return nil
+	}
+	ctx.WritePlain(" ")
+	if err := n.FieldType.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTableColumn.FieldType")
+	}
+	if n.Tp == JSONTableColumnExists {
+		ctx.WriteKeyWord(" EXISTS")
+	}
+	ctx.WriteKeyWord(" PATH ")
+	ctx.WriteString(n.Path)
+	if n.OnEmpty != nil {
+		ctx.WritePlain(" ")
+		n.OnEmpty.Restore(ctx)
+		ctx.WriteKeyWord(" ON EMPTY")
+	}
+	if n.OnError != nil {
+		ctx.WritePlain(" ")
+		n.OnError.Restore(ctx)
+		ctx.WriteKeyWord(" ON ERROR")
+	}
+	return nil
+}
+
+// JSONTableOnResponseType is the type of the ON EMPTY and ON ERROR clauses.
+type JSONTableOnResponseType int
+
+// JSON_TABLE ON EMPTY and ON ERROR types.
+const (
+	JSONTableOnResponseNull JSONTableOnResponseType = iota
+	JSONTableOnResponseError
+	JSONTableOnResponseDefault
+)
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of the JSON_TABLE column, it decides the value of the
+// column when the path doesn't exist or the value is invalid.
+type JSONTableOnResponse struct {
+	Tp JSONTableOnResponseType
+	// Default is the JSON string of the value for the DEFAULT type.
+	Default string
+}
+
+// Restore writes the ON EMPTY or ON ERROR clause without the `ON ...` suffix.
+func (n *JSONTableOnResponse) Restore(ctx *format.RestoreCtx) {
+	switch n.Tp {
+	case JSONTableOnResponseNull:
+		ctx.WriteKeyWord("NULL")
+	case JSONTableOnResponseError:
+		ctx.WriteKeyWord("ERROR")
+	case JSONTableOnResponseDefault:
+		ctx.WriteKeyWord("DEFAULT ")
+		ctx.WriteString(n.Default)
+	}
+}
+
 // SelectLockType is the lock type for SelectStmt.
 type SelectLockType int
 
@@ -827,21 +999,75 @@ func (n *ByItem) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// GroupingSetsType is the way to generate the grouping sets from the items of the GROUP BY clause.
+type GroupingSetsType int
+
+const (
+	// GroupingSetsNone means all the items form the only grouping set, i.e. a plain GROUP BY.
+	GroupingSetsNone GroupingSetsType = iota
+	// GroupingSetsRollup is GROUP BY ... WITH ROLLUP, the grouping sets are all the prefixes of the items.
+	GroupingSetsRollup
+	// GroupingSetsCube is GROUP BY CUBE (...), the grouping sets are all the subsets of the items.
+	GroupingSetsCube
+	// GroupingSetsList is GROUP BY GROUPING SETS (...), the grouping sets are listed in GroupByClause.GroupingSets.
+	GroupingSetsList
+)
+
 // GroupByClause represents group by clause.
 type GroupByClause struct {
 	node
 	Items []*ByItem
+	Tp    GroupingSetsType
+	// GroupingSets are the offsets of the items in every grouping set, it's only used by GroupingSetsList.
+	GroupingSets [][]int
 }
 
 // Restore implements Node interface.
 func (n *GroupByClause) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord("GROUP BY ")
-	for i, v := range n.Items {
+	switch n.Tp {
+	case GroupingSetsCube:
+		ctx.WriteKeyWord("CUBE ")
+		ctx.WritePlain("(")
+	case GroupingSetsList:
+		ctx.WriteKeyWord("GROUPING SETS ")
+		ctx.WritePlain("(")
+		for i, set := range n.GroupingSets {
+			if i != 0 {
+				ctx.WritePlain(",")
+			}
+			ctx.WritePlain("(")
+			if err := n.restoreItems(ctx, set); err != nil {
+				return err
+			}
+			ctx.WritePlain(")")
+		}
+		ctx.WritePlain(")")
+		return nil
+	}
+	offsets := make([]int, 0, len(n.Items))
+	for i := range n.Items {
+		offsets = append(offsets, i)
+	}
+	if err := n.restoreItems(ctx, offsets); err != nil {
+		return err
+	}
+	switch n.Tp {
+	case GroupingSetsRollup:
+		ctx.WriteKeyWord(" WITH ROLLUP")
+	case GroupingSetsCube:
+		ctx.WritePlain(")")
+	}
+	return nil
+}
+
+func (n *GroupByClause) restoreItems(ctx *format.RestoreCtx, offsets []int) error {
+	for i, offset := range offsets {
 		if i != 0 {
 			ctx.WritePlain(",")
 		}
-		if err := v.Restore(ctx); err != nil {
-			return errors.Annotatef(err, "An error occurred while restore GroupByClause.Items[%d]", i)
+		if err := n.Items[offset].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore GroupByClause.Items[%d]", offset)
 		}
 	}
 	return nil
@@ -2675,6 +2901,11 @@ const (
 	ShowPlacementForPartition
 	ShowPlacementLabels
 	ShowSessionStates
+	ShowFunctionStatus
+	ShowCreateProcedure
+	ShowCreateFunction
+	ShowCreateTrigger
+	ShowCreateEvent
 )
 
 const (
@@ -2768,6 +2999,25 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := n.Table.Restore(ctx); err != nil {
 			return errors.Annotate(err, "An error occurred while restore ShowStmt.VIEW")
 		}
+	case ShowCreateTrigger:
+		ctx.WriteKeyWord("CREATE TRIGGER ")
+		if err := n.Table.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ShowStmt.Table")
+		}
+	case ShowCreateEvent:
+		ctx.WriteKeyWord("CREATE EVENT ")
+		if err := n.Table.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ShowStmt.Table")
+		}
+	case ShowCreateProcedure, ShowCreateFunction:
+		if n.Tp == ShowCreateProcedure {
+			ctx.WriteKeyWord("CREATE PROCEDURE ")
+		} else {
+			ctx.WriteKeyWord("CREATE FUNCTION ")
+		}
+		if err := n.Table.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ShowStmt.Table")
+		}
 	case ShowCreateDatabase:
 		ctx.WriteKeyWord("CREATE DATABASE ")
 		if n.IfNotExists {
@@ -2975,6 +3225,8 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 			restoreShowDatabaseNameOpt()
 		case ShowProcedureStatus:
 			ctx.WriteKeyWord("PROCEDURE STATUS")
+		case ShowFunctionStatus:
+			ctx.WriteKeyWord("FUNCTION STATUS")
 		case ShowEvents:
 			ctx.WriteKeyWord("EVENTS")
 			restoreShowDatabaseNameOpt()
@@ -3179,12 +3431,28 @@ type SelectIntoOption struct {
 	FileName   string
 	FieldsInfo *FieldsClause
 	LinesInfo  *LinesClause
+	// Variables are the targets of SELECT ... INTO var_list. A target is either
+	// a *VariableExpr for a user variable or a *ColumnNameExpr for a local
+	// variable of a stored routine.
+	Variables []ExprNode
 }
 
 // Restore implements Node interface.
 func (n *SelectIntoOption) Restore(ctx *format.RestoreCtx) error {
+	if n.Tp == SelectIntoVars {
+		ctx.WriteKeyWord("INTO ")
+		for i, variable := range n.Variables {
+			if i > 0 {
+				ctx.WritePlain(",")
+			}
+			if err := variable.Restore(ctx); err != nil {
+				return errors.Annotatef(err, "An error occurred while restore SelectInto.Variables[%d]", i)
+			}
+		}
+		return nil
+	}
 	if n.Tp != SelectIntoOutfile {
-		// only support SELECT/TABLE/VALUES ... INTO OUTFILE statement now
+		// only support SELECT/TABLE/VALUES ... INTO OUTFILE or INTO var_list statement now
 		return errors.New("Unsupported SelectionInto type")
 	}
 
@@ -3276,6 +3544,8 @@ func (n *FrameClause) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("ROWS")
 	case Ranges:
 		ctx.WriteKeyWord("RANGE")
+	case Groups:
+		ctx.WriteKeyWord("GROUPS")
 	default:
 		return errors.New("Unsupported window function frame type")
 	}
@@ -211,6 +211,10 @@ const (
 	Ord             = "ord"
 	Position        = "position"
 	Quote           = "quote"
+	RegexpLike      = "regexp_like"
+	RegexpSubstr    = "regexp_substr"
+	RegexpInStr     = "regexp_instr"
+	RegexpReplace   = "regexp_replace"
 	Repeat          = "repeat"
 	Replace         = "replace"
 	Reverse         = "reverse"
@@ -270,6 +274,7 @@ const (
 	// miscellaneous functions
 	AnyValue        = "any_value"
 	DefaultFunc     = "default_func"
+	Grouping        = "grouping"
 	InetAton        = "inet_aton"
 	InetNtoa        = "inet_ntoa"
 	Inet6Aton       = "inet6_aton"
@@ -340,6 +345,21 @@ const (
 	JSONKeys          = "json_keys"
 	JSONLength        = "json_length"
 
+	// spatial functions
+	StGeomFromText     = "st_geomfromtext"
+	StGeometryFromText = "st_geometryfromtext"
+	StAsText           = "st_astext"
+	StAsWKT            = "st_aswkt"
+	StSRID             = "st_srid"
+	StDistanceSphere   = "st_distance_sphere"
+	StContains         = "st_contains"
+	StWithin           = "st_within"
+	StIntersects       = "st_intersects"
+	StBuffer           = "st_buffer"
+
+	// full-text search functions
+	FulltextMatch = "match"
+
 	// TiDB internal function.
 	TiDBDecodeKey       = "tidb_decode_key"
 	TiDBDecodeBase64Key = "tidb_decode_base64_key"
@@ -729,6 +749,8 @@ const (
 	AggFuncApproxCountDistinct = "approx_count_distinct"
 	// AggFuncApproxPercentile is the name of approx_percentile function.
 	AggFuncApproxPercentile = "approx_percentile"
+	// AggFuncGrouping is the name of grouping function.
+	AggFuncGrouping = "grouping"
 )
 
 // AggregateFuncExpr represents aggregate function expression.
@@ -849,15 +871,16 @@ type WindowFuncExpr struct {
 	F string
 	// Args is the function args.
 	Args []ExprNode
-	// Distinct cannot be true for most window functions, except `max` and `min`.
-	// We need to raise error if it is not allowed to be true.
+	// Distinct is true if the aggregate function only aggregates the distinct values in the frame.
 	Distinct bool
 	// IgnoreNull indicates how to handle null value.
-	// MySQL only supports `RESPECT NULLS`, so we need to raise error if it is true.
+	// It's true for `IGNORE NULLS` and false for `RESPECT NULLS`.
 	IgnoreNull bool
 	// FromLast indicates the calculation direction of this window function.
-	// MySQL only supports calculation from first, so we need to raise error if it is true.
+	// It's true for `NTH_VALUE(...) FROM LAST`.
 	FromLast bool
+	// Order is only used in GROUP_CONCAT.
+	Order *OrderByClause
 	// Spec is the specification of this window.
 	Spec WindowSpec
 }
@@ -866,7 +889,13 @@ type WindowFuncExpr struct {
 func (n *WindowFuncExpr) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord(n.F)
 	ctx.WritePlain("(")
-	for i, v := range n.Args {
+	args := n.Args
+	isGroupConcat := strings.ToLower(n.F) == AggFuncGroupConcat
+	if isGroupConcat {
+		// The last argument is SEPARATOR.
+		args = args[:len(args)-1]
+	}
+	for i, v := range args {
 		if i != 0 {
 			ctx.WritePlain(", ")
 		} else if n.Distinct {
@@ -876,6 +905,18 @@ func (n *WindowFuncExpr) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore WindowFuncExpr.Args[%d]", i)
 		}
 	}
+	if isGroupConcat {
+		if n.Order != nil {
+			ctx.WritePlain(" ")
+			if err := n.Order.Restore(ctx); err != nil {
+				return errors.Annotate(err, "An error occur while restore WindowFuncExpr.Args Order")
+			}
+		}
+		ctx.WriteKeyWord(" SEPARATOR ")
+		if err := n.Args[len(n.Args)-1].Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore WindowFuncExpr.Args SEPARATOR")
+		}
+	}
 	ctx.WritePlain(")")
 	if n.FromLast {
 		ctx.WriteKeyWord(" FROM LAST")
@@ -910,6 +951,13 @@ func (n *WindowFuncExpr) Accept(v Visitor) (Node, bool) {
 		}
 		n.Args[i] = node.(ExprNode)
 	}
+	if n.Order != nil {
+		node, ok := n.Order.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Order = node.(*OrderByClause)
+	}
 	node, ok := n.Spec.Accept(v)
 	if !ok {
 		return n, false
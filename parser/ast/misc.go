@@ -1874,6 +1874,7 @@ const (
 	StatsTypeCardinality uint8 = iota
 	StatsTypeDependency
 	StatsTypeCorrelation
+	StatsTypeHistogram
 )
 
 // StatisticsSpec is the specification for ADD /DROP STATISTICS.
@@ -1888,6 +1889,7 @@ type StatisticsSpec struct {
 //   CREATE STATISTICS stats1 (cardinality) ON t(a, b, c);
 //   CREATE STATISTICS stats2 (dependency) ON t(a, b);
 //   CREATE STATISTICS stats3 (correlation) ON t(a, b);
+//   CREATE STATISTICS stats4 (histogram) ON t(a, b);
 type CreateStatisticsStmt struct {
 	stmtNode
 
@@ -1912,6 +1914,8 @@ func (n *CreateStatisticsStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord(" (dependency) ")
 	case StatsTypeCorrelation:
 		ctx.WriteKeyWord(" (correlation) ")
+	case StatsTypeHistogram:
+		ctx.WriteKeyWord(" (histogram) ")
 	}
 	ctx.WriteKeyWord("ON ")
 	if err := n.Table.Restore(ctx); err != nil {
@@ -2047,6 +2051,7 @@ const (
 	AdminResetTelemetryID
 	AdminReloadStatistics
 	AdminFlushPlanCache
+	AdminResetCardinalityCorrection
 )
 
 // HandleRange represents a range where handle value >= Begin and < End.
@@ -2281,6 +2286,14 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("RESET TELEMETRY_ID")
 	case AdminReloadStatistics:
 		ctx.WriteKeyWord("RELOAD STATS_EXTENDED")
+	case AdminResetCardinalityCorrection:
+		ctx.WriteKeyWord("RESET CARDINALITY_CORRECTION")
+		if len(n.Tables) > 0 {
+			ctx.WritePlain(" ")
+			if err := restoreTables(); err != nil {
+				return err
+			}
+		}
 	case AdminFlushPlanCache:
 		if n.StatementScope == StatementScopeSession {
 			ctx.WriteKeyWord("FLUSH SESSION PLAN_CACHE")
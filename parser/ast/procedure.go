@@ -0,0 +1,1491 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/types"
+)
+
+var (
+	_ StmtNode = &CreateRoutineStmt{}
+	_ StmtNode = &DropRoutineStmt{}
+	_ StmtNode = &CreateLoadableFunctionStmt{}
+	_ DDLNode  = &CreateTriggerStmt{}
+	_ DDLNode  = &DropTriggerStmt{}
+	_ StmtNode = &CreateEventStmt{}
+	_ StmtNode = &AlterEventStmt{}
+	_ StmtNode = &DropEventStmt{}
+	_ StmtNode = &ProcedureBlockStmt{}
+	_ StmtNode = &ProcedureVarDeclStmt{}
+	_ StmtNode = &ProcedureCursorDeclStmt{}
+	_ StmtNode = &ProcedureHandlerDeclStmt{}
+	_ StmtNode = &ProcedureIfStmt{}
+	_ StmtNode = &ProcedureCaseStmt{}
+	_ StmtNode = &ProcedureWhileStmt{}
+	_ StmtNode = &ProcedureRepeatStmt{}
+	_ StmtNode = &ProcedureLoopStmt{}
+	_ StmtNode = &ProcedureJumpStmt{}
+	_ StmtNode = &ProcedureOpenStmt{}
+	_ StmtNode = &ProcedureFetchStmt{}
+	_ StmtNode = &ProcedureCloseStmt{}
+	_ StmtNode = &ProcedureReturnStmt{}
+
+	_ Node = &RoutineParam{}
+	_ Node = &EventSchedule{}
+)
+
+// RoutineType is the type of a stored routine.
+type RoutineType int
+
+// Stored routine types.
+const (
+	RoutineProcedure RoutineType = iota
+	RoutineFunction
+)
+
+// String implements fmt.Stringer interface.
+func (t RoutineType) String() string {
+	if t == RoutineFunction {
+		return "FUNCTION"
+	}
+	return "PROCEDURE"
+}
+
+// RoutineParamMode is the mode of a stored procedure parameter.
+type RoutineParamMode int
+
+// Stored procedure parameter modes.
+const (
+	RoutineParamModeIn RoutineParamMode = iota
+	RoutineParamModeOut
+	RoutineParamModeInOut
+)
+
+// String implements fmt.Stringer interface.
+func (m RoutineParamMode) String() string {
+	switch m {
+	case RoutineParamModeOut:
+		return "OUT"
+	case RoutineParamModeInOut:
+		return "INOUT"
+	}
+	return "IN"
+}
+
+// RoutineParam is a parameter of a stored routine.
+type RoutineParam struct {
+	node
+
+	Mode RoutineParamMode
+	Name model.CIStr
+	Tp   *types.FieldType
+}
+
+// Restore implements Node interface.
+func (n *RoutineParam) Restore(ctx *format.RestoreCtx) error {
+	if n.Mode != RoutineParamModeIn {
+		ctx.WriteKeyWord(n.Mode.String())
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteName(n.Name.O)
+	ctx.WritePlain(" ")
+	if err := n.Tp.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore RoutineParam.Tp")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *RoutineParam) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// RoutineDataAccess describes how a stored routine accesses data.
+type RoutineDataAccess int
+
+// Stored routine data access characteristics.
+const (
+	RoutineDataAccessContainsSQL RoutineDataAccess = iota
+	RoutineDataAccessNoSQL
+	RoutineDataAccessReadsSQLData
+	RoutineDataAccessModifiesSQLData
+)
+
+// String implements fmt.Stringer interface.
+func (a RoutineDataAccess) String() string {
+	switch a {
+	case RoutineDataAccessNoSQL:
+		return "NO SQL"
+	case RoutineDataAccessReadsSQLData:
+		return "READS SQL DATA"
+	case RoutineDataAccessModifiesSQLData:
+		return "MODIFIES SQL DATA"
+	}
+	return "CONTAINS SQL"
+}
+
+// RoutineOptionType is the type of a stored routine characteristic.
+type RoutineOptionType int
+
+// Stored routine characteristic types.
+const (
+	RoutineOptionComment RoutineOptionType = iota + 1
+	RoutineOptionLanguage
+	RoutineOptionDeterministic
+	RoutineOptionDataAccess
+	RoutineOptionSecurity
+)
+
+// RoutineOption is a characteristic of a stored routine.
+type RoutineOption struct {
+	Tp         RoutineOptionType
+	StrValue   string
+	BoolValue  bool
+	DataAccess RoutineDataAccess
+	Security   model.ViewSecurity
+}
+
+// Restore implements Node interface.
+func (n *RoutineOption) Restore(ctx *format.RestoreCtx) error {
+	switch n.Tp {
+	case RoutineOptionComment:
+		ctx.WriteKeyWord("COMMENT ")
+		ctx.WriteString(n.StrValue)
+	case RoutineOptionLanguage:
+		ctx.WriteKeyWord("LANGUAGE SQL")
+	case RoutineOptionDeterministic:
+		if !n.BoolValue {
+			ctx.WriteKeyWord("NOT ")
+		}
+		ctx.WriteKeyWord("DETERMINISTIC")
+	case RoutineOptionDataAccess:
+		ctx.WriteKeyWord(n.DataAccess.String())
+	case RoutineOptionSecurity:
+		ctx.WriteKeyWord("SQL SECURITY ")
+		ctx.WriteKeyWord(n.Security.String())
+	default:
+		return errors.Errorf("invalid RoutineOption: %d", n.Tp)
+	}
+	return nil
+}
+
+// CreateRoutineStmt is a statement to create a stored procedure or a stored function.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-procedure.html
+type CreateRoutineStmt struct {
+	stmtNode
+
+	Type        RoutineType
+	IfNotExists bool
+	Definer     *auth.UserIdentity
+	Name        *TableName
+	Params      []*RoutineParam
+	// Returns is the return type of a stored function.
+	Returns *types.FieldType
+	Options []*RoutineOption
+	// Body is the routine body. Its text is the original text of the body.
+	Body StmtNode
+}
+
+// Restore implements Node interface.
+func (n *CreateRoutineStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE ")
+	if n.Definer != nil && !n.Definer.CurrentUser {
+		ctx.WriteKeyWord("DEFINER")
+		ctx.WritePlain(" = ")
+		if err := n.Definer.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateRoutineStmt.Definer")
+		}
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteKeyWord(n.Type.String())
+	ctx.WritePlain(" ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateRoutineStmt.Name")
+	}
+	if err := n.RestoreParams(ctx); err != nil {
+		return err
+	}
+	if n.Returns != nil {
+		ctx.WriteKeyWord(" RETURNS ")
+		if err := n.Returns.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateRoutineStmt.Returns")
+		}
+	}
+	for i, option := range n.Options {
+		ctx.WritePlain(" ")
+		if err := option.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore CreateRoutineStmt.Options[%d]", i)
+		}
+	}
+	ctx.WritePlain(" ")
+	if err := n.Body.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateRoutineStmt.Body")
+	}
+	return nil
+}
+
+// RestoreParams restores the parenthesized parameter list of the routine.
+func (n *CreateRoutineStmt) RestoreParams(ctx *format.RestoreCtx) error {
+	ctx.WritePlain("(")
+	for i, param := range n.Params {
+		if i > 0 {
+			ctx.WritePlain(",")
+		}
+		if err := param.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore CreateRoutineStmt.Params[%d]", i)
+		}
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateRoutineStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateRoutineStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	for i, param := range n.Params {
+		node, ok = param.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Params[i] = node.(*RoutineParam)
+	}
+	node, ok = n.Body.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Body = node.(StmtNode)
+	return v.Leave(n)
+}
+
+// DropRoutineStmt is a statement to drop a stored procedure or a stored function.
+type DropRoutineStmt struct {
+	stmtNode
+
+	Type     RoutineType
+	IfExists bool
+	Name     *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropRoutineStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP ")
+	ctx.WriteKeyWord(n.Type.String())
+	ctx.WritePlain(" ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropRoutineStmt.Name")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropRoutineStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropRoutineStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
+// LoadableFunctionRetType is the return type of a loadable function.
+// The values are the same as the ret column of mysql.func in MySQL.
+type LoadableFunctionRetType int
+
+// Loadable function return types.
+const (
+	LoadableFunctionRetString  LoadableFunctionRetType = 0
+	LoadableFunctionRetReal    LoadableFunctionRetType = 1
+	LoadableFunctionRetInteger LoadableFunctionRetType = 2
+	LoadableFunctionRetDecimal LoadableFunctionRetType = 4
+)
+
+// String implements fmt.Stringer interface.
+func (t LoadableFunctionRetType) String() string {
+	switch t {
+	case LoadableFunctionRetReal:
+		return "REAL"
+	case LoadableFunctionRetInteger:
+		return "INTEGER"
+	case LoadableFunctionRetDecimal:
+		return "DECIMAL"
+	}
+	return "STRING"
+}
+
+// CreateLoadableFunctionStmt is a statement to create a loadable function.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-function-loadable.html
+type CreateLoadableFunctionStmt struct {
+	stmtNode
+
+	IfNotExists bool
+	Name        model.CIStr
+	Returns     LoadableFunctionRetType
+	// SOName is the name of the library, i.e. the plugin, which implements the function.
+	SOName string
+}
+
+// Restore implements Node interface.
+func (n *CreateLoadableFunctionStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE FUNCTION ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	ctx.WriteName(n.Name.O)
+	ctx.WriteKeyWord(" RETURNS ")
+	ctx.WriteKeyWord(n.Returns.String())
+	ctx.WriteKeyWord(" SONAME ")
+	ctx.WriteString(n.SOName)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateLoadableFunctionStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateLoadableFunctionStmt)
+	return v.Leave(n)
+}
+
+// TriggerOrderType is the type of the order of a trigger relative to another trigger.
+type TriggerOrderType int
+
+// List trigger order types.
+const (
+	TriggerFollows TriggerOrderType = iota
+	TriggerPrecedes
+)
+
+// TriggerOrder is the FOLLOWS or PRECEDES clause of the CREATE TRIGGER statement.
+type TriggerOrder struct {
+	Tp TriggerOrderType
+	// Trigger is the name of the existing trigger of the same action time and event.
+	Trigger model.CIStr
+}
+
+// CreateTriggerStmt is a statement to create a trigger.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-trigger.html
+type CreateTriggerStmt struct {
+	ddlNode
+
+	IfNotExists bool
+	Definer     *auth.UserIdentity
+	Name        *TableName
+	Time        model.TriggerTime
+	Event       model.TriggerEvent
+	Table       *TableName
+	Order       *TriggerOrder
+	// Body is the trigger body. Its text is the original text of the body.
+	Body StmtNode
+}
+
+// Restore implements Node interface.
+func (n *CreateTriggerStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE ")
+	if n.Definer != nil && !n.Definer.CurrentUser {
+		ctx.WriteKeyWord("DEFINER")
+		ctx.WritePlain(" = ")
+		if err := n.Definer.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateTriggerStmt.Definer")
+		}
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteKeyWord("TRIGGER ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateTriggerStmt.Name")
+	}
+	ctx.WritePlain(" ")
+	ctx.WriteKeyWord(n.Time.String())
+	ctx.WritePlain(" ")
+	ctx.WriteKeyWord(n.Event.String())
+	ctx.WriteKeyWord(" ON ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateTriggerStmt.Table")
+	}
+	ctx.WriteKeyWord(" FOR EACH ROW ")
+	if n.Order != nil {
+		if n.Order.Tp == TriggerFollows {
+			ctx.WriteKeyWord("FOLLOWS ")
+		} else {
+			ctx.WriteKeyWord("PRECEDES ")
+		}
+		ctx.WriteName(n.Order.Trigger.O)
+		ctx.WritePlain(" ")
+	}
+	if err := n.Body.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateTriggerStmt.Body")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateTriggerStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateTriggerStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	node, ok = n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	node, ok = n.Body.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Body = node.(StmtNode)
+	return v.Leave(n)
+}
+
+// DropTriggerStmt is a statement to drop a trigger.
+type DropTriggerStmt struct {
+	ddlNode
+
+	IfExists bool
+	Name     *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropTriggerStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP TRIGGER ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropTriggerStmt.Name")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropTriggerStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropTriggerStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
+// EventStatus is the status of an event.
+type EventStatus int
+
+// List event statuses.
+const (
+	// EventStatusUnspecified means that the statement doesn't change the status of the event.
+	EventStatusUnspecified EventStatus = iota
+	EventEnabled
+	EventDisabled
+	EventSlavesideDisabled
+)
+
+// String implements fmt.Stringer interface.
+func (s EventStatus) String() string {
+	switch s {
+	case EventEnabled:
+		return "ENABLED"
+	case EventDisabled:
+		return "DISABLED"
+	case EventSlavesideDisabled:
+		return "SLAVESIDE_DISABLED"
+	}
+	return ""
+}
+
+// Restore writes the ENABLE or DISABLE clause.
+func (s EventStatus) Restore(ctx *format.RestoreCtx) {
+	switch s {
+	case EventEnabled:
+		ctx.WriteKeyWord(" ENABLE")
+	case EventDisabled:
+		ctx.WriteKeyWord(" DISABLE")
+	case EventSlavesideDisabled:
+		ctx.WriteKeyWord(" DISABLE ON SLAVE")
+	}
+}
+
+// EventCompletion is the ON COMPLETION clause of an event, which decides whether the event is dropped when it is
+// completed.
+type EventCompletion int
+
+// List event completion options.
+const (
+	// EventCompletionUnspecified means that the statement doesn't change the completion option of the event.
+	EventCompletionUnspecified EventCompletion = iota
+	EventCompletionNotPreserve
+	EventCompletionPreserve
+)
+
+// Restore writes the ON COMPLETION clause.
+func (c EventCompletion) Restore(ctx *format.RestoreCtx) {
+	switch c {
+	case EventCompletionNotPreserve:
+		ctx.WriteKeyWord(" ON COMPLETION NOT PRESERVE")
+	case EventCompletionPreserve:
+		ctx.WriteKeyWord(" ON COMPLETION PRESERVE")
+	}
+}
+
+// EventSchedule is the ON SCHEDULE clause of an event.
+type EventSchedule struct {
+	node
+
+	// At is the execution time of a one-time event. It's nil for a recurring event.
+	At ExprNode
+	// Every and Unit are the interval of a recurring event.
+	Every ExprNode
+	Unit  TimeUnitType
+	// Starts and Ends are the optional time range of a recurring event.
+	Starts ExprNode
+	Ends   ExprNode
+}
+
+// Restore implements Node interface.
+func (n *EventSchedule) Restore(ctx *format.RestoreCtx) error {
+	if n.At != nil {
+		ctx.WriteKeyWord("AT ")
+		if err := n.At.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore EventSchedule.At")
+		}
+		return nil
+	}
+	ctx.WriteKeyWord("EVERY ")
+	if err := n.Every.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore EventSchedule.Every")
+	}
+	ctx.WritePlain(" ")
+	ctx.WriteKeyWord(n.Unit.String())
+	if n.Starts != nil {
+		ctx.WriteKeyWord(" STARTS ")
+		if err := n.Starts.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore EventSchedule.Starts")
+		}
+	}
+	if n.Ends != nil {
+		ctx.WriteKeyWord(" ENDS ")
+		if err := n.Ends.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore EventSchedule.Ends")
+		}
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *EventSchedule) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*EventSchedule)
+	for _, expr := range []*ExprNode{&n.At, &n.Every, &n.Starts, &n.Ends} {
+		if *expr == nil {
+			continue
+		}
+		node, ok := (*expr).Accept(v)
+		if !ok {
+			return n, false
+		}
+		*expr = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
+// CreateEventStmt is a statement to create an event.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-event.html
+type CreateEventStmt struct {
+	stmtNode
+
+	IfNotExists bool
+	Definer     *auth.UserIdentity
+	Name        *TableName
+	Schedule    *EventSchedule
+	Completion  EventCompletion
+	Status      EventStatus
+	Comment     string
+	// Body is the event body. Its text is the original text of the body.
+	Body StmtNode
+}
+
+// Restore implements Node interface.
+func (n *CreateEventStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE ")
+	if n.Definer != nil && !n.Definer.CurrentUser {
+		ctx.WriteKeyWord("DEFINER")
+		ctx.WritePlain(" = ")
+		if err := n.Definer.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateEventStmt.Definer")
+		}
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteKeyWord("EVENT ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateEventStmt.Name")
+	}
+	ctx.WriteKeyWord(" ON SCHEDULE ")
+	if err := n.Schedule.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateEventStmt.Schedule")
+	}
+	n.Completion.Restore(ctx)
+	n.Status.Restore(ctx)
+	if n.Comment != "" {
+		ctx.WriteKeyWord(" COMMENT ")
+		ctx.WriteString(n.Comment)
+	}
+	ctx.WriteKeyWord(" DO ")
+	if err := n.Body.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateEventStmt.Body")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateEventStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateEventStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	node, ok = n.Schedule.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Schedule = node.(*EventSchedule)
+	node, ok = n.Body.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Body = node.(StmtNode)
+	return v.Leave(n)
+}
+
+// AlterEventStmt is a statement to change an event. The clauses which are not specified are not changed.
+// See https://dev.mysql.com/doc/refman/8.0/en/alter-event.html
+type AlterEventStmt struct {
+	stmtNode
+
+	Definer    *auth.UserIdentity
+	Name       *TableName
+	Schedule   *EventSchedule
+	Completion EventCompletion
+	NewName    *TableName
+	Status     EventStatus
+	Comment    *string
+	Body       StmtNode
+}
+
+// Restore implements Node interface.
+func (n *AlterEventStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("ALTER ")
+	if n.Definer != nil && !n.Definer.CurrentUser {
+		ctx.WriteKeyWord("DEFINER")
+		ctx.WritePlain(" = ")
+		if err := n.Definer.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterEventStmt.Definer")
+		}
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteKeyWord("EVENT ")
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore AlterEventStmt.Name")
+	}
+	if n.Schedule != nil {
+		ctx.WriteKeyWord(" ON SCHEDULE ")
+		if err := n.Schedule.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterEventStmt.Schedule")
+		}
+	}
+	n.Completion.Restore(ctx)
+	if n.NewName != nil {
+		ctx.WriteKeyWord(" RENAME TO ")
+		if err := n.NewName.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterEventStmt.NewName")
+		}
+	}
+	n.Status.Restore(ctx)
+	if n.Comment != nil {
+		ctx.WriteKeyWord(" COMMENT ")
+		ctx.WriteString(*n.Comment)
+	}
+	if n.Body != nil {
+		ctx.WriteKeyWord(" DO ")
+		if err := n.Body.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterEventStmt.Body")
+		}
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *AlterEventStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*AlterEventStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	if n.Schedule != nil {
+		node, ok = n.Schedule.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Schedule = node.(*EventSchedule)
+	}
+	if n.NewName != nil {
+		node, ok = n.NewName.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.NewName = node.(*TableName)
+	}
+	if n.Body != nil {
+		node, ok = n.Body.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Body = node.(StmtNode)
+	}
+	return v.Leave(n)
+}
+
+// DropEventStmt is a statement to drop an event.
+type DropEventStmt struct {
+	stmtNode
+
+	IfExists bool
+	Name     *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropEventStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP EVENT ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropEventStmt.Name")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropEventStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropEventStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
+func restoreProcedureStmts(ctx *format.RestoreCtx, stmts []StmtNode) error {
+	for i, stmt := range stmts {
+		ctx.WritePlain(" ")
+		if err := stmt.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore statement [%d]", i)
+		}
+		ctx.WritePlain(";")
+	}
+	return nil
+}
+
+func acceptProcedureStmts(v Visitor, stmts []StmtNode) bool {
+	for i, stmt := range stmts {
+		node, ok := stmt.Accept(v)
+		if !ok {
+			return false
+		}
+		stmts[i] = node.(StmtNode)
+	}
+	return true
+}
+
+func restoreEndLabel(ctx *format.RestoreCtx, label model.CIStr) {
+	if label.O != "" {
+		ctx.WritePlain(" ")
+		ctx.WriteName(label.O)
+	}
+}
+
+func restoreBeginLabel(ctx *format.RestoreCtx, label model.CIStr) {
+	if label.O != "" {
+		ctx.WriteName(label.O)
+		ctx.WritePlain(": ")
+	}
+}
+
+// ProcedureBlockStmt is a BEGIN ... END compound statement in a stored routine.
+type ProcedureBlockStmt struct {
+	stmtNode
+
+	Label model.CIStr
+	// Decls are the DECLARE statements at the beginning of the block.
+	Decls []StmtNode
+	Stmts []StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureBlockStmt) Restore(ctx *format.RestoreCtx) error {
+	restoreBeginLabel(ctx, n.Label)
+	ctx.WriteKeyWord("BEGIN")
+	if err := restoreProcedureStmts(ctx, n.Decls); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureBlockStmt.Decls")
+	}
+	if err := restoreProcedureStmts(ctx, n.Stmts); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureBlockStmt.Stmts")
+	}
+	ctx.WriteKeyWord(" END")
+	restoreEndLabel(ctx, n.Label)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureBlockStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureBlockStmt)
+	if !acceptProcedureStmts(v, n.Decls) || !acceptProcedureStmts(v, n.Stmts) {
+		return n, false
+	}
+	return v.Leave(n)
+}
+
+// ProcedureVarDeclStmt is a DECLARE statement of local variables.
+type ProcedureVarDeclStmt struct {
+	stmtNode
+
+	Names   []model.CIStr
+	Tp      *types.FieldType
+	Default ExprNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureVarDeclStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DECLARE ")
+	for i, name := range n.Names {
+		if i > 0 {
+			ctx.WritePlain(",")
+		}
+		ctx.WriteName(name.O)
+	}
+	ctx.WritePlain(" ")
+	if err := n.Tp.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureVarDeclStmt.Tp")
+	}
+	if n.Default != nil {
+		ctx.WriteKeyWord(" DEFAULT ")
+		if err := n.Default.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ProcedureVarDeclStmt.Default")
+		}
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureVarDeclStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureVarDeclStmt)
+	if n.Default != nil {
+		node, ok := n.Default.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Default = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
+// ProcedureCursorDeclStmt is a DECLARE ... CURSOR FOR statement.
+type ProcedureCursorDeclStmt struct {
+	stmtNode
+
+	Name   model.CIStr
+	Select StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureCursorDeclStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DECLARE ")
+	ctx.WriteName(n.Name.O)
+	ctx.WriteKeyWord(" CURSOR FOR ")
+	if err := n.Select.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureCursorDeclStmt.Select")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureCursorDeclStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureCursorDeclStmt)
+	node, ok := n.Select.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Select = node.(StmtNode)
+	return v.Leave(n)
+}
+
+// ProcedureHandlerAction is the action of a condition handler.
+type ProcedureHandlerAction int
+
+// Condition handler actions.
+const (
+	ProcedureHandlerContinue ProcedureHandlerAction = iota
+	ProcedureHandlerExit
+)
+
+// ProcedureConditionType is the type of a handler condition value.
+type ProcedureConditionType int
+
+// Handler condition value types.
+const (
+	ProcedureConditionErrorCode ProcedureConditionType = iota
+	ProcedureConditionSQLState
+	ProcedureConditionSQLWarning
+	ProcedureConditionNotFound
+	ProcedureConditionSQLException
+)
+
+// ProcedureHandlerCondition is a condition value of DECLARE ... HANDLER.
+type ProcedureHandlerCondition struct {
+	Tp        ProcedureConditionType
+	ErrorCode uint16
+	SQLState  string
+}
+
+// Restore implements Node interface.
+func (n *ProcedureHandlerCondition) Restore(ctx *format.RestoreCtx) error {
+	switch n.Tp {
+	case ProcedureConditionErrorCode:
+		ctx.WritePlainf("%d", n.ErrorCode)
+	case ProcedureConditionSQLState:
+		ctx.WriteKeyWord("SQLSTATE ")
+		ctx.WriteString(n.SQLState)
+	case ProcedureConditionSQLWarning:
+		ctx.WriteKeyWord("SQLWARNING")
+	case ProcedureConditionNotFound:
+		ctx.WriteKeyWord("NOT FOUND")
+	case ProcedureConditionSQLException:
+		ctx.WriteKeyWord("SQLEXCEPTION")
+	default:
+		return errors.Errorf("invalid ProcedureHandlerCondition: %d", n.Tp)
+	}
+	return nil
+}
+
+// ProcedureHandlerDeclStmt is a DECLARE ... HANDLER statement.
+type ProcedureHandlerDeclStmt struct {
+	stmtNode
+
+	Action     ProcedureHandlerAction
+	Conditions []*ProcedureHandlerCondition
+	Stmt       StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureHandlerDeclStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DECLARE ")
+	if n.Action == ProcedureHandlerExit {
+		ctx.WriteKeyWord("EXIT")
+	} else {
+		ctx.WriteKeyWord("CONTINUE")
+	}
+	ctx.WriteKeyWord(" HANDLER FOR ")
+	for i, cond := range n.Conditions {
+		if i > 0 {
+			ctx.WritePlain(",")
+		}
+		if err := cond.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore ProcedureHandlerDeclStmt.Conditions[%d]", i)
+		}
+	}
+	ctx.WritePlain(" ")
+	if err := n.Stmt.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureHandlerDeclStmt.Stmt")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureHandlerDeclStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureHandlerDeclStmt)
+	node, ok := n.Stmt.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Stmt = node.(StmtNode)
+	return v.Leave(n)
+}
+
+// ProcedureIfBranch is an IF or ELSEIF branch of ProcedureIfStmt.
+type ProcedureIfBranch struct {
+	Cond  ExprNode
+	Stmts []StmtNode
+}
+
+// ProcedureIfStmt is an IF ... THEN ... [ELSEIF ...] [ELSE ...] END IF statement.
+type ProcedureIfStmt struct {
+	stmtNode
+
+	Branches []*ProcedureIfBranch
+	// Else is nil if there is no ELSE clause.
+	Else []StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureIfStmt) Restore(ctx *format.RestoreCtx) error {
+	for i, branch := range n.Branches {
+		if i == 0 {
+			ctx.WriteKeyWord("IF ")
+		} else {
+			ctx.WriteKeyWord(" ELSEIF ")
+		}
+		if err := branch.Cond.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore ProcedureIfStmt.Branches[%d].Cond", i)
+		}
+		ctx.WriteKeyWord(" THEN")
+		if err := restoreProcedureStmts(ctx, branch.Stmts); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore ProcedureIfStmt.Branches[%d].Stmts", i)
+		}
+	}
+	if n.Else != nil {
+		ctx.WriteKeyWord(" ELSE")
+		if err := restoreProcedureStmts(ctx, n.Else); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ProcedureIfStmt.Else")
+		}
+	}
+	ctx.WriteKeyWord(" END IF")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureIfStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureIfStmt)
+	for _, branch := range n.Branches {
+		node, ok := branch.Cond.Accept(v)
+		if !ok {
+			return n, false
+		}
+		branch.Cond = node.(ExprNode)
+		if !acceptProcedureStmts(v, branch.Stmts) {
+			return n, false
+		}
+	}
+	if !acceptProcedureStmts(v, n.Else) {
+		return n, false
+	}
+	return v.Leave(n)
+}
+
+// ProcedureWhenClause is a WHEN clause of ProcedureCaseStmt.
+type ProcedureWhenClause struct {
+	Expr  ExprNode
+	Stmts []StmtNode
+}
+
+// ProcedureCaseStmt is a CASE ... END CASE statement.
+type ProcedureCaseStmt struct {
+	stmtNode
+
+	// Value is nil for a searched CASE statement.
+	Value       ExprNode
+	WhenClauses []*ProcedureWhenClause
+	// Else is nil if there is no ELSE clause.
+	Else []StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureCaseStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CASE")
+	if n.Value != nil {
+		ctx.WritePlain(" ")
+		if err := n.Value.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ProcedureCaseStmt.Value")
+		}
+	}
+	for i, clause := range n.WhenClauses {
+		ctx.WriteKeyWord(" WHEN ")
+		if err := clause.Expr.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore ProcedureCaseStmt.WhenClauses[%d].Expr", i)
+		}
+		ctx.WriteKeyWord(" THEN")
+		if err := restoreProcedureStmts(ctx, clause.Stmts); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore ProcedureCaseStmt.WhenClauses[%d].Stmts", i)
+		}
+	}
+	if n.Else != nil {
+		ctx.WriteKeyWord(" ELSE")
+		if err := restoreProcedureStmts(ctx, n.Else); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ProcedureCaseStmt.Else")
+		}
+	}
+	ctx.WriteKeyWord(" END CASE")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureCaseStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureCaseStmt)
+	if n.Value != nil {
+		node, ok := n.Value.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Value = node.(ExprNode)
+	}
+	for _, clause := range n.WhenClauses {
+		node, ok := clause.Expr.Accept(v)
+		if !ok {
+			return n, false
+		}
+		clause.Expr = node.(ExprNode)
+		if !acceptProcedureStmts(v, clause.Stmts) {
+			return n, false
+		}
+	}
+	if !acceptProcedureStmts(v, n.Else) {
+		return n, false
+	}
+	return v.Leave(n)
+}
+
+// ProcedureWhileStmt is a WHILE ... DO ... END WHILE statement.
+type ProcedureWhileStmt struct {
+	stmtNode
+
+	Label model.CIStr
+	Cond  ExprNode
+	Stmts []StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureWhileStmt) Restore(ctx *format.RestoreCtx) error {
+	restoreBeginLabel(ctx, n.Label)
+	ctx.WriteKeyWord("WHILE ")
+	if err := n.Cond.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureWhileStmt.Cond")
+	}
+	ctx.WriteKeyWord(" DO")
+	if err := restoreProcedureStmts(ctx, n.Stmts); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureWhileStmt.Stmts")
+	}
+	ctx.WriteKeyWord(" END WHILE")
+	restoreEndLabel(ctx, n.Label)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureWhileStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureWhileStmt)
+	node, ok := n.Cond.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Cond = node.(ExprNode)
+	if !acceptProcedureStmts(v, n.Stmts) {
+		return n, false
+	}
+	return v.Leave(n)
+}
+
+// ProcedureRepeatStmt is a REPEAT ... UNTIL ... END REPEAT statement.
+type ProcedureRepeatStmt struct {
+	stmtNode
+
+	Label model.CIStr
+	Stmts []StmtNode
+	Until ExprNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureRepeatStmt) Restore(ctx *format.RestoreCtx) error {
+	restoreBeginLabel(ctx, n.Label)
+	ctx.WriteKeyWord("REPEAT")
+	if err := restoreProcedureStmts(ctx, n.Stmts); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureRepeatStmt.Stmts")
+	}
+	ctx.WriteKeyWord(" UNTIL ")
+	if err := n.Until.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureRepeatStmt.Until")
+	}
+	ctx.WriteKeyWord(" END REPEAT")
+	restoreEndLabel(ctx, n.Label)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureRepeatStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureRepeatStmt)
+	if !acceptProcedureStmts(v, n.Stmts) {
+		return n, false
+	}
+	node, ok := n.Until.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Until = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// ProcedureLoopStmt is a LOOP ... END LOOP statement.
+type ProcedureLoopStmt struct {
+	stmtNode
+
+	Label model.CIStr
+	Stmts []StmtNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureLoopStmt) Restore(ctx *format.RestoreCtx) error {
+	restoreBeginLabel(ctx, n.Label)
+	ctx.WriteKeyWord("LOOP")
+	if err := restoreProcedureStmts(ctx, n.Stmts); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureLoopStmt.Stmts")
+	}
+	ctx.WriteKeyWord(" END LOOP")
+	restoreEndLabel(ctx, n.Label)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureLoopStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureLoopStmt)
+	if !acceptProcedureStmts(v, n.Stmts) {
+		return n, false
+	}
+	return v.Leave(n)
+}
+
+// ProcedureJumpType is the type of ProcedureJumpStmt.
+type ProcedureJumpType int
+
+// Jump statement types.
+const (
+	ProcedureLeave ProcedureJumpType = iota
+	ProcedureIterate
+)
+
+// ProcedureJumpStmt is a LEAVE or ITERATE statement.
+type ProcedureJumpStmt struct {
+	stmtNode
+
+	Tp    ProcedureJumpType
+	Label model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *ProcedureJumpStmt) Restore(ctx *format.RestoreCtx) error {
+	if n.Tp == ProcedureIterate {
+		ctx.WriteKeyWord("ITERATE ")
+	} else {
+		ctx.WriteKeyWord("LEAVE ")
+	}
+	ctx.WriteName(n.Label.O)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureJumpStmt) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// ProcedureOpenStmt is an OPEN cursor statement.
+type ProcedureOpenStmt struct {
+	stmtNode
+
+	Cursor model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *ProcedureOpenStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("OPEN ")
+	ctx.WriteName(n.Cursor.O)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureOpenStmt) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// ProcedureFetchStmt is a FETCH cursor INTO statement.
+type ProcedureFetchStmt struct {
+	stmtNode
+
+	Cursor model.CIStr
+	Vars   []model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *ProcedureFetchStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("FETCH ")
+	ctx.WriteName(n.Cursor.O)
+	ctx.WriteKeyWord(" INTO ")
+	for i, name := range n.Vars {
+		if i > 0 {
+			ctx.WritePlain(",")
+		}
+		ctx.WriteName(name.O)
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureFetchStmt) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// ProcedureCloseStmt is a CLOSE cursor statement.
+type ProcedureCloseStmt struct {
+	stmtNode
+
+	Cursor model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *ProcedureCloseStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CLOSE ")
+	ctx.WriteName(n.Cursor.O)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureCloseStmt) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// ProcedureReturnStmt is a RETURN statement of a stored function.
+type ProcedureReturnStmt struct {
+	stmtNode
+
+	Expr ExprNode
+}
+
+// Restore implements Node interface.
+func (n *ProcedureReturnStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RETURN ")
+	if err := n.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ProcedureReturnStmt.Expr")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ProcedureReturnStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ProcedureReturnStmt)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
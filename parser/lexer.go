@@ -236,6 +236,14 @@ func (s *Scanner) Lex(v *yySymType) int {
 		v.offset = pos.Offset
 		return asof
 	}
+	if tok == with && s.getNextToken() == rollup {
+		_, pos, lit = s.scan()
+		v.ident = fmt.Sprintf("%s %s", v.ident, lit)
+		s.lastKeyword = withRollup
+		s.lastScanOffset = pos.Offset
+		v.offset = pos.Offset
+		return withRollup
+	}
 
 	switch tok {
 	case intLit:
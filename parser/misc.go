@@ -156,8 +156,41 @@ var tokenMap = map[string]int{
 	"AS":                       as,
 	"ASC":                      asc,
 	"ASCII":                    ascii,
+	"AT":                       at,
 	"ATTRIBUTES":               attributes,
 	"BATCH":                    batch,
+	"BEFORE":                   before,
+	"CLOSE":                    close,
+	"COMPLETION":               completion,
+	"CONTAINS":                 contains,
+	"CONTINUE":                 continueKwd,
+	"CURSOR":                   cursor,
+	"DECLARE":                  declare,
+	"DETERMINISTIC":            deterministic,
+	"EACH":                     each,
+	"ELSEIF":                   elseIf,
+	"ENDS":                     ends,
+	"EVERY":                    every,
+	"EXIT":                     exit,
+	"FOLLOWS":                  follows,
+	"FOUND":                    found,
+	"HANDLER":                  handler,
+	"INOUT":                    inout,
+	"ITERATE":                  iterate,
+	"LEAVE":                    leave,
+	"LOOP":                     loop,
+	"MODIFIES":                 modifies,
+	"PRECEDES":                 precedes,
+	"OUT":                      out,
+	"READS":                    reads,
+	"RETURN":                   returnKwd,
+	"RETURNS":                  returns,
+	"SQLEXCEPTION":             sqlexception,
+	"SQLSTATE":                 sqlstate,
+	"SQLWARNING":               sqlwarning,
+	"SONAME":                   soname,
+	"STARTS":                   starts,
+	"STRING":                   stringType,
 	"STATS_OPTIONS":            statsOptions,
 	"STATS_SAMPLE_RATE":        statsSampleRate,
 	"STATS_COL_CHOICE":         statsColChoice,
@@ -201,6 +234,7 @@ var tokenMap = map[string]int{
 	"CANCEL":                   cancel,
 	"CAPTURE":                  capture,
 	"CARDINALITY":              cardinality,
+	"CARDINALITY_CORRECTION":   cardinalityCorrection,
 	"CASCADE":                  cascade,
 	"CASCADED":                 cascaded,
 	"CASE":                     caseKwd,
@@ -231,6 +265,7 @@ var tokenMap = map[string]int{
 	"COMMIT":                   commit,
 	"COMMITTED":                committed,
 	"COMPACT":                  compact,
+	"COMPLETE":                 complete,
 	"COMPRESSED":               compressed,
 	"COMPRESSION":              compression,
 	"CONCURRENCY":              concurrency,
@@ -254,6 +289,7 @@ var tokenMap = map[string]int{
 	"CSV_NULL":                 csvNull,
 	"CSV_SEPARATOR":            csvSeparator,
 	"CSV_TRIM_LAST_SEPARATORS": csvTrimLastSeparators,
+	"CUBE":                     cube,
 	"CURRENT_DATE":             currentDate,
 	"CURRENT_ROLE":             currentRole,
 	"CURRENT_TIME":             currentTime,
@@ -306,6 +342,7 @@ var tokenMap = map[string]int{
 	"DUPLICATE":                duplicate,
 	"DYNAMIC":                  dynamic,
 	"ELSE":                     elseKwd,
+	"EMPTY":                    empty,
 	"ENABLE":                   enable,
 	"ENABLED":                  enabled,
 	"ENCLOSED":                 enclosed,
@@ -358,12 +395,16 @@ var tokenMap = map[string]int{
 	"FUNCTION":                 function,
 	"GENERAL":                  general,
 	"GENERATED":                generated,
+	"GEOMCOLLECTION":           geomCollection,
+	"GEOMETRY":                 geometry,
+	"GEOMETRYCOLLECTION":       geometryCollection,
 	"GET_FORMAT":               getFormat,
 	"GLOBAL":                   global,
 	"GRANT":                    grant,
 	"GRANTS":                   grants,
 	"GROUP_CONCAT":             groupConcat,
 	"GROUP":                    group,
+	"GROUPING":                 grouping,
 	"HASH":                     hash,
 	"HAVING":                   having,
 	"HELP":                     help,
@@ -371,6 +412,7 @@ var tokenMap = map[string]int{
 	"HISTORY":                  history,
 	"HISTOGRAM":                histogram,
 	"HOSTS":                    hosts,
+	"HYPO":                     hypo,
 	"HOUR_MICROSECOND":         hourMicrosecond,
 	"HOUR_MINUTE":              hourMinute,
 	"HOUR_SECOND":              hourSecond,
@@ -413,6 +455,7 @@ var tokenMap = map[string]int{
 	"JOB":                      job,
 	"JOBS":                     jobs,
 	"JOIN":                     join,
+	"JSON_TABLE":               jsonTable,
 	"JSON_ARRAYAGG":            jsonArrayagg,
 	"JSON_OBJECTAGG":           jsonObjectAgg,
 	"JSON":                     jsonType,
@@ -425,6 +468,7 @@ var tokenMap = map[string]int{
 	"LAST_BACKUP":              lastBackup,
 	"LAST":                     last,
 	"LASTVAL":                  lastval,
+	"LATERAL":                  lateral,
 	"LEADER":                   leader,
 	"LEADER_CONSTRAINTS":       leaderConstraints,
 	"LEADING":                  leading,
@@ -434,6 +478,7 @@ var tokenMap = map[string]int{
 	"LEFT":                     left,
 	"LESS":                     less,
 	"LEVEL":                    level,
+	"LINESTRING":               lineString,
 	"LIKE":                     like,
 	"LIMIT":                    limit,
 	"LINEAR":                   linear,
@@ -452,6 +497,7 @@ var tokenMap = map[string]int{
 	"LONGTEXT":                 longtextType,
 	"LOW_PRIORITY":             lowPriority,
 	"MASTER":                   master,
+	"MATERIALIZED":             materialized,
 	"MATCH":                    match,
 	"MAX_CONNECTIONS_PER_HOUR": maxConnectionsPerHour,
 	"MAX_IDXNUM":               max_idxnum,
@@ -479,10 +525,14 @@ var tokenMap = map[string]int{
 	"MODE":                     mode,
 	"MODIFY":                   modify,
 	"MONTH":                    month,
+	"MULTILINESTRING":          multiLineString,
+	"MULTIPOINT":               multiPoint,
+	"MULTIPOLYGON":             multiPolygon,
 	"NAMES":                    names,
 	"NATIONAL":                 national,
 	"NATURAL":                  natural,
 	"NCHAR":                    ncharType,
+	"NESTED":                   nested,
 	"NEVER":                    never,
 	"NEXT_ROW_ID":              next_row_id,
 	"NEXT":                     next,
@@ -521,6 +571,7 @@ var tokenMap = map[string]int{
 	"OPTIONALLY":               optionally,
 	"OR":                       or,
 	"ORDER":                    order,
+	"ORDINALITY":               ordinality,
 	"OUTER":                    outer,
 	"OUTFILE":                  outfile,
 	"PACK_KEYS":                packKeys,
@@ -531,6 +582,7 @@ var tokenMap = map[string]int{
 	"PARTITIONING":             partitioning,
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
+	"PATH":                     path,
 	"PERCENT":                  percent,
 	"PER_DB":                   per_db,
 	"PER_TABLE":                per_table,
@@ -539,6 +591,8 @@ var tokenMap = map[string]int{
 	"PLAN":                     plan,
 	"PLAN_CACHE":               planCache,
 	"PLUGINS":                  plugins,
+	"POINT":                    point,
+	"POLYGON":                  polygon,
 	"POLICY":                   policy,
 	"POSITION":                 position,
 	"PRE_SPLIT_REGIONS":        preSplitRegions,
@@ -568,7 +622,9 @@ var tokenMap = map[string]int{
 	"REAL":                     realType,
 	"REBUILD":                  rebuild,
 	"RECENT":                   recent,
+	"RECOMMEND":                recommend,
 	"RECOVER":                  recover,
+	"REFRESH":                  refresh,
 	"RECURSIVE":                recursive,
 	"REDUNDANT":                redundant,
 	"REFERENCES":               references,
@@ -602,6 +658,7 @@ var tokenMap = map[string]int{
 	"RLIKE":                    rlike,
 	"ROLE":                     role,
 	"ROLLBACK":                 rollback,
+	"ROLLUP":                   rollup,
 	"ROUTINE":                  routine,
 	"ROW_COUNT":                rowCount,
 	"ROW_FORMAT":               rowFormat,
@@ -635,6 +692,7 @@ var tokenMap = map[string]int{
 	"SESSION_STATES":           sessionStates,
 	"SET":                      set,
 	"SETVAL":                   setval,
+	"SETS":                     sets,
 	"SHARD_ROW_ID_BITS":        shardRowIDBits,
 	"SHARE":                    share,
 	"SHARED":                   shared,
@@ -666,6 +724,7 @@ var tokenMap = map[string]int{
 	"SQL_TSI_SECOND":           sqlTsiSecond,
 	"SQL_TSI_WEEK":             sqlTsiWeek,
 	"SQL_TSI_YEAR":             sqlTsiYear,
+	"SRID":                     srid,
 	"SQL":                      sql,
 	"SSL":                      ssl,
 	"STALENESS":                staleness,
@@ -763,6 +822,7 @@ var tokenMap = map[string]int{
 	"UNKNOWN":                  unknown,
 	"UNLOCK":                   unlock,
 	"UNSIGNED":                 unsigned,
+	"UNTIL":                    until,
 	"UPDATE":                   update,
 	"USAGE":                    usage,
 	"USE":                      use,
@@ -796,6 +856,7 @@ var tokenMap = map[string]int{
 	"WEIGHT_STRING":            weightString,
 	"WHEN":                     when,
 	"WHERE":                    where,
+	"WHILE":                    while,
 	"WIDTH":                    width,
 	"WITH":                     with,
 	"WITHOUT":                  without,
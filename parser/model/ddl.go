@@ -97,6 +97,10 @@ const (
 	ActionCreateTables                  ActionType = 60
 	ActionMultiSchemaChange             ActionType = 61
 	ActionSetTiFlashMode                ActionType = 62
+	ActionCreateMaterializedView        ActionType = 63
+	ActionDropMaterializedView          ActionType = 64
+	ActionCreateTrigger                 ActionType = 65
+	ActionDropTrigger                   ActionType = 66
 )
 
 var actionMap = map[ActionType]string{
@@ -158,6 +162,10 @@ var actionMap = map[ActionType]string{
 	ActionAlterTableStatsOptions:        "alter table statistics options",
 	ActionMultiSchemaChange:             "alter table multi-schema change",
 	ActionSetTiFlashMode:                "set tiflash mode",
+	ActionCreateMaterializedView:        "create materialized view",
+	ActionDropMaterializedView:          "drop materialized view",
+	ActionCreateTrigger:                 "create trigger",
+	ActionDropTrigger:                   "drop trigger",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -114,6 +114,8 @@ type ColumnInfo struct {
 	FieldType           types.FieldType     `json:"type"`
 	State               SchemaState         `json:"state"`
 	Comment             string              `json:"comment"`
+	// SRID restricts the spatial reference system of the values of the spatial column, it's nil if unrestricted.
+	SRID *uint32 `json:"srid,omitempty"`
 	// A hidden column is used internally(expression index) and are not accessible by users.
 	Hidden           bool `json:"hidden"`
 	*ChangeStateInfo `json:"change_state_info"`
@@ -468,6 +470,17 @@ type TableInfo struct {
 	StatsOptions *StatsOptions `json:"stats_options"`
 
 	ExchangePartitionInfo *ExchangePartitionInfo `json:"exchange_partition_info"`
+
+	// MaterializedView is not nil if the table stores the data of a materialized view.
+	MaterializedView *MaterializedViewInfo `json:"materialized_view,omitempty"`
+	// MaterializedViewLog is not nil if the table is the change log of a materialized view.
+	MaterializedViewLog *MaterializedViewLogInfo `json:"materialized_view_log,omitempty"`
+	// MaterializedViewLogIDs are the IDs of the change log tables that the changes of the table are written to.
+	MaterializedViewLogIDs []int64 `json:"materialized_view_log_ids,omitempty"`
+
+	// Triggers are the triggers of the table. The triggers of the same action time and event are in the order of
+	// activation.
+	Triggers []*TriggerInfo `json:"triggers,omitempty"`
 }
 
 // TableCacheStatusType is the type of the table cache status
@@ -1019,8 +1032,9 @@ func (v *ViewCheckOption) String() string {
 	}
 }
 
-//revive:disable:exported
 // ViewInfo provides meta data describing a DB view.
+//
+//revive:disable:exported
 type ViewInfo struct {
 	Algorithm   ViewAlgorithm      `json:"view_algorithm"`
 	Definer     *auth.UserIdentity `json:"view_definer"`
@@ -1030,6 +1044,110 @@ type ViewInfo struct {
 	Cols        []CIStr            `json:"view_cols"`
 }
 
+// MaterializedViewInfo provides meta data describing a materialized view.
+type MaterializedViewInfo struct {
+	// SelectStmt is the definition of the view, in which the table names are qualified with the schema names.
+	SelectStmt string `json:"select_stmt"`
+	// BaseTableIDs are the IDs of the tables that the view is based on.
+	BaseTableIDs []int64 `json:"base_table_ids"`
+	// LogTableID is the ID of the table that the changes of the base tables are logged to. It's 0 if the changes
+	// can't be tracked, then the view can only be refreshed completely and is never used to answer queries.
+	LogTableID int64 `json:"log_table_id"`
+	// FastRefresh is not nil if the view can be refreshed incrementally with the changes in the log.
+	FastRefresh *MaterializedViewFastRefreshInfo `json:"fast_refresh,omitempty"`
+}
+
+// MaterializedViewFastRefreshInfo describes how to apply the changes in the log to an aggregate materialized view.
+type MaterializedViewFastRefreshInfo struct {
+	// DeltaSelectStmt aggregates the changes in the log. Its result columns correspond to the columns of the view,
+	// the group by columns output the group keys and the aggregate columns output the deltas of the aggregates.
+	DeltaSelectStmt string `json:"delta_select_stmt"`
+	// GroupByOffsets are the offsets of the group by columns in the view.
+	GroupByOffsets []int `json:"group_by_offsets"`
+	// CountOffset is the offset of the COUNT(*) column in the view, a group is removed when its count becomes 0.
+	CountOffset int `json:"count_offset"`
+}
+
+// MaterializedViewLogInfo provides meta data describing the change log of a materialized view.
+type MaterializedViewLogInfo struct {
+	MViewID int64 `json:"mview_id"`
+}
+
+// MaterializedViewLogOpName is the name of the column in the change log of a materialized view, which is 1 for an
+// inserted row, -1 for a deleted row and 0 for the mark that the view needs to be refreshed completely.
+var MaterializedViewLogOpName = NewCIStr("_tidb_mlog_op")
+
+// IsMaterializedView checks if TableInfo is a materialized view.
+func (t *TableInfo) IsMaterializedView() bool {
+	return t.MaterializedView != nil
+}
+
+// TriggerTime is the action time of a trigger.
+type TriggerTime int
+
+// List trigger action times.
+const (
+	TriggerBefore TriggerTime = iota
+	TriggerAfter
+)
+
+// String implements fmt.Stringer interface.
+func (t TriggerTime) String() string {
+	if t == TriggerAfter {
+		return "AFTER"
+	}
+	return "BEFORE"
+}
+
+// TriggerEvent is the kind of the operation that activates a trigger.
+type TriggerEvent int
+
+// List trigger events.
+const (
+	TriggerInsert TriggerEvent = iota
+	TriggerUpdate
+	TriggerDelete
+)
+
+// String implements fmt.Stringer interface.
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerUpdate:
+		return "UPDATE"
+	case TriggerDelete:
+		return "DELETE"
+	default:
+		return "INSERT"
+	}
+}
+
+// TriggerInfo provides meta data describing a trigger.
+type TriggerInfo struct {
+	Name  CIStr        `json:"name"`
+	Time  TriggerTime  `json:"time"`
+	Event TriggerEvent `json:"event"`
+	// Body is the original text of the trigger body.
+	Body    string             `json:"body"`
+	Definer *auth.UserIdentity `json:"definer"`
+	// SQLMode, CharsetClient and CollationConnection are the session variables in effect when the trigger is
+	// created, the body is parsed and executed with them.
+	SQLMode             string    `json:"sql_mode"`
+	CharsetClient       string    `json:"charset_client"`
+	CollationConnection string    `json:"collation_connection"`
+	DBCollation         string    `json:"db_collation"`
+	Created             time.Time `json:"created"`
+}
+
+// FindTrigger finds the trigger by the name, it returns nil if the trigger doesn't exist.
+func (t *TableInfo) FindTrigger(name string) *TriggerInfo {
+	for _, trigger := range t.Triggers {
+		if trigger.Name.L == name {
+			return trigger
+		}
+	}
+	return nil
+}
+
 const (
 	DefaultSequenceCacheBool          = true
 	DefaultSequenceCycleBool          = false
@@ -1250,7 +1368,7 @@ func (i *IndexColumn) Clone() *IndexColumn {
 }
 
 // PrimaryKeyType is the type of primary key.
-// Available values are 'clustered', 'nonclustered', and ''(default).
+// Available values are 'clustered', 'nonclustered', and ”(default).
 type PrimaryKeyType int8
 
 func (p PrimaryKeyType) String() string {
@@ -1285,6 +1403,10 @@ func (t IndexType) String() string {
 		return "HASH"
 	case IndexTypeRtree:
 		return "RTREE"
+	case IndexTypeHypo:
+		return "HYPO"
+	case IndexTypeFulltext:
+		return "FULLTEXT"
 	default:
 		return ""
 	}
@@ -1296,6 +1418,10 @@ const (
 	IndexTypeBtree
 	IndexTypeHash
 	IndexTypeRtree
+	// IndexTypeHypo is the type of the hypothetical index, which only exists in the planner of a session.
+	IndexTypeHypo
+	// IndexTypeFulltext is the type of the FULLTEXT index, whose entries are the tokens of the indexed columns.
+	IndexTypeFulltext
 )
 
 // IndexInfo provides meta data describing a DB index.
@@ -1313,6 +1439,8 @@ type IndexInfo struct {
 	Primary   bool           `json:"is_primary"`   // Whether the index is primary key.
 	Invisible bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global    bool           `json:"is_global"`    // Whether the index is global.
+	// FulltextParser is the name of the tokenizer of the FULLTEXT index, it's empty for the standard tokenizer.
+	FulltextParser string `json:"fulltext_parser,omitempty"`
 }
 
 // Clone clones IndexInfo.
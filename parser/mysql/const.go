@@ -155,6 +155,11 @@ const (
 	ClientPluginAuth
 	ClientConnectAtts
 	ClientPluginAuthLenencClientData
+	ClientHandleExpiredPasswords
+	ClientSessionTrack
+	ClientDeprecateEOF
+	ClientOptionalResultsetMetadata
+	ClientZstdCompressionAlgorithm
 )
 
 // Cache type information.
@@ -170,6 +175,13 @@ const (
 	AuthTiDBSessionToken    = "tidb_session_token"
 )
 
+// Compression algorithms of the client/server protocol.
+const (
+	CompressionZlib         = "zlib"
+	CompressionZstd         = "zstd"
+	CompressionUncompressed = "uncompressed"
+)
+
 // MySQL database and tables.
 const (
 	// SystemDB is the name of system database.
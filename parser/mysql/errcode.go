@@ -884,6 +884,8 @@ const (
 	ErrErrorLast                                             = 1863
 	ErrMaxExecTimeExceeded                                   = 1907
 	ErrInvalidFieldSize                                      = 3013
+	ErrGISDifferentSRIDs                                     = 3033
+	ErrGISInvalidData                                        = 3037
 	ErrIncorrectType                                         = 3064
 	ErrInvalidJSONData                                       = 3069
 	ErrGeneratedColumnFunctionIsNotAllowed                   = 3102
@@ -905,7 +907,9 @@ const (
 	ErrUserAlreadyExists                                     = 3163
 	ErrInvalidJSONPathArrayCell                              = 3165
 	ErrInvalidEncryptionOption                               = 3184
+	ErrGISUnsupportedArgument                                = 3516
 	ErrRoleNotGranted                                        = 3530
+	ErrSRSNotFound                                           = 3548
 	ErrLockAcquireFailAndNoWaitSet                           = 3572
 	ErrWindowNoSuchWindow                                    = 3579
 	ErrWindowCircularityInWindowGraph                        = 3580
@@ -928,6 +932,13 @@ const (
 	ErrWindowNoGroupOrderUnused                              = 3597
 	ErrWindowExplainJson                                     = 3598 //nolint: revive
 	ErrWindowFunctionIgnoresFrame                            = 3599
+	ErrFieldInGroupingNotGroupBy                             = 3601
+	ErrLatitudeOutOfRange                                    = 3616
+	ErrLongitudeOutOfRange                                   = 3617
+	ErrNotImplementedForGeographicSRS                        = 3618
+	ErrWrongSRIDForColumn                                    = 3643
+	ErrNotImplementedForCartesianSRS                         = 3704
+	ErrNonpositiveRadius                                     = 3706
 	ErrDataTruncatedFunctionalIndex                          = 3751
 	ErrDataOutOfRangeFunctionalIndex                         = 3752
 	ErrFunctionalIndexOnJsonOrGeometryFunction               = 3753 //nolint: revive
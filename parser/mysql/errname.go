@@ -899,6 +899,8 @@ var MySQLErrName = map[uint16]*ErrMessage{
 	ErrDependentByGeneratedColumn:                            Message("Column '%s' has a generated column dependency.", nil),
 	ErrGeneratedColumnRefAutoInc:                             Message("Generated column '%s' cannot refer to auto-increment column.", nil),
 	ErrInvalidFieldSize:                                      Message("Invalid size for column '%s'.", nil),
+	ErrGISDifferentSRIDs:                                     Message("Binary geometry function %s given two geometries of different srids: %d and %d, which should have been identical.", nil),
+	ErrGISInvalidData:                                        Message("Invalid GIS data provided to function %s.", nil),
 	ErrIncorrectType:                                         Message("Incorrect type for argument %s in function %s.", nil),
 	ErrInvalidJSONData:                                       Message("Invalid JSON data provided to function %s: %s", nil),
 	ErrInvalidJSONText:                                       Message("Invalid JSON text: %-.192s", nil),
@@ -912,6 +914,7 @@ var MySQLErrName = map[uint16]*ErrMessage{
 	ErrUserAlreadyExists:                                     Message("User %s already exists.", nil),
 	ErrInvalidJSONPathArrayCell:                              Message("A path expression is not a path to a cell in an array.", nil),
 	ErrInvalidEncryptionOption:                               Message("Invalid encryption option.", nil),
+	ErrGISUnsupportedArgument:                                Message("Calling geometry function %s with unsupported types of arguments.", nil),
 	ErrWindowNoSuchWindow:                                    Message("Window name '%s' is not defined.", nil),
 	ErrWindowCircularityInWindowGraph:                        Message("There is a circularity in the window dependency graph.", nil),
 	ErrWindowNoChildPartitioning:                             Message("A window which depends on another cannot define partitioning.", nil),
@@ -933,7 +936,15 @@ var MySQLErrName = map[uint16]*ErrMessage{
 	ErrWindowNoGroupOrderUnused:                              Message("ASC or DESC with GROUP BY isn't allowed with window functions; put ASC or DESC in ORDER BY", nil),
 	ErrWindowExplainJson:                                     Message("To get information about window functions use EXPLAIN FORMAT=JSON", nil),
 	ErrWindowFunctionIgnoresFrame:                            Message("Window function '%s' ignores the frame clause of window '%s' and aggregates over the whole partition", nil),
+	ErrFieldInGroupingNotGroupBy:                             Message("Argument #%d of GROUPING function is not in GROUP BY", nil),
+	ErrLatitudeOutOfRange:                                    Message("Latitude %f is out of range in function %s. It must be within [%f, %f].", nil),
+	ErrLongitudeOutOfRange:                                   Message("Longitude %f is out of range in function %s. It must be within (%f, %f].", nil),
+	ErrNotImplementedForGeographicSRS:                        Message("%s(%s) has not been implemented for geographic spatial reference systems.", nil),
+	ErrWrongSRIDForColumn:                                    Message("The SRID of the geometry does not match the SRID of the column '%s'. The SRID of the geometry is %d, but the SRID of the column is %d. Consider changing the SRID of the geometry or the SRID property of the column.", nil),
+	ErrNotImplementedForCartesianSRS:                         Message("%s(%s) has not been implemented for Cartesian spatial reference systems.", nil),
+	ErrNonpositiveRadius:                                     Message("Invalid radius provided to function %s: Radius must be greater than zero.", nil),
 	ErrRoleNotGranted:                                        Message("%s is not granted to %s", nil),
+	ErrSRSNotFound:                                           Message("There's no spatial reference system with SRID %d.", nil),
 	ErrMaxExecTimeExceeded:                                   Message("Query execution was interrupted, max_execution_time exceeded.", nil),
 	ErrLockAcquireFailAndNoWaitSet:                           Message("Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.", nil),
 	ErrDataTruncatedFunctionalIndex:                          Message("Data truncated for functional index '%s' at row %d", nil),
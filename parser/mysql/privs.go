@@ -233,7 +233,7 @@ const (
 	SuperPriv
 	// CreateUserPriv is the privilege to create user.
 	CreateUserPriv
-	// TriggerPriv is not checked yet.
+	// TriggerPriv is the privilege to create and drop triggers.
 	TriggerPriv
 	// DropPriv is the privilege to drop schema/table.
 	DropPriv
@@ -52,6 +52,7 @@ import (
 	/*yy:token "%c"     */
 	identifier "identifier"
 	asof       "AS OF"
+	withRollup "WITH ROLLUP"
 
 	/*yy:token "_%c"    */
 	underscoreCS "UNDERSCORE_CHARSET"
@@ -97,6 +98,7 @@ import (
 	convert           "CONVERT"
 	create            "CREATE"
 	cross             "CROSS"
+	cube              "CUBE"
 	cumeDist          "CUME_DIST"
 	currentDate       "CURRENT_DATE"
 	currentTime       "CURRENT_TIME"
@@ -141,6 +143,7 @@ import (
 	grant             "GRANT"
 	group             "GROUP"
 	groups            "GROUPS"
+	grouping          "GROUPING"
 	having            "HAVING"
 	highPriority      "HIGH_PRIORITY"
 	hourMicrosecond   "HOUR_MICROSECOND"
@@ -152,10 +155,12 @@ import (
 	index             "INDEX"
 	infile            "INFILE"
 	inner             "INNER"
+	inout             "INOUT"
 	integerType       "INTEGER"
 	intersect         "INTERSECT"
 	interval          "INTERVAL"
 	into              "INTO"
+	out               "OUT"
 	outfile           "OUTFILE"
 	is                "IS"
 	insert            "INSERT"
@@ -166,11 +171,13 @@ import (
 	int4Type          "INT4"
 	int8Type          "INT8"
 	join              "JOIN"
+	jsonTable         "JSON_TABLE"
 	key               "KEY"
 	keys              "KEYS"
 	kill              "KILL"
 	lag               "LAG"
 	lastValue         "LAST_VALUE"
+	lateral           "LATERAL"
 	lead              "LEAD"
 	leading           "LEADING"
 	left              "LEFT"
@@ -297,7 +304,38 @@ import (
 	always                "ALWAYS"
 	any                   "ANY"
 	ascii                 "ASCII"
+	at                    "AT"
 	attributes            "ATTRIBUTES"
+	before                "BEFORE"
+	close                 "CLOSE"
+	completion            "COMPLETION"
+	contains              "CONTAINS"
+	continueKwd           "CONTINUE"
+	cursor                "CURSOR"
+	declare               "DECLARE"
+	deterministic         "DETERMINISTIC"
+	each                  "EACH"
+	elseIf                "ELSEIF"
+	ends                  "ENDS"
+	every                 "EVERY"
+	exit                  "EXIT"
+	follows               "FOLLOWS"
+	found                 "FOUND"
+	handler               "HANDLER"
+	iterate               "ITERATE"
+	leave                 "LEAVE"
+	loop                  "LOOP"
+	modifies              "MODIFIES"
+	precedes              "PRECEDES"
+	reads                 "READS"
+	returnKwd             "RETURN"
+	returns               "RETURNS"
+	sqlexception          "SQLEXCEPTION"
+	sqlstate              "SQLSTATE"
+	sqlwarning            "SQLWARNING"
+	soname                "SONAME"
+	starts                "STARTS"
+	stringType            "STRING"
 	statsOptions          "STATS_OPTIONS"
 	statsSampleRate       "STATS_SAMPLE_RATE"
 	statsColChoice        "STATS_COL_CHOICE"
@@ -344,6 +382,7 @@ import (
 	commit                "COMMIT"
 	committed             "COMMITTED"
 	compact               "COMPACT"
+	complete              "COMPLETE"
 	compressed            "COMPRESSED"
 	compression           "COMPRESSION"
 	concurrency           "CONCURRENCY"
@@ -377,6 +416,7 @@ import (
 	do                    "DO"
 	duplicate             "DUPLICATE"
 	dynamic               "DYNAMIC"
+	empty                 "EMPTY"
 	enable                "ENABLE"
 	enabled               "ENABLED"
 	encryption            "ENCRYPTION"
@@ -407,6 +447,9 @@ import (
 	full                  "FULL"
 	function              "FUNCTION"
 	general               "GENERAL"
+	geomCollection        "GEOMCOLLECTION"
+	geometry              "GEOMETRY"
+	geometryCollection    "GEOMETRYCOLLECTION"
 	global                "GLOBAL"
 	grants                "GRANTS"
 	hash                  "HASH"
@@ -414,6 +457,7 @@ import (
 	histogram             "HISTOGRAM"
 	history               "HISTORY"
 	hosts                 "HOSTS"
+	hypo                  "HYPO"
 	hour                  "HOUR"
 	identified            "IDENTIFIED"
 	identSQLErrors        "ERRORS"
@@ -439,12 +483,14 @@ import (
 	lastval               "LASTVAL"
 	less                  "LESS"
 	level                 "LEVEL"
+	lineString            "LINESTRING"
 	list                  "LIST"
 	local                 "LOCAL"
 	locked                "LOCKED"
 	location              "LOCATION"
 	logs                  "LOGS"
 	master                "MASTER"
+	materialized          "MATERIALIZED"
 	max_idxnum            "MAX_IDXNUM"
 	max_minutes           "MAX_MINUTES"
 	maxConnectionsPerHour "MAX_CONNECTIONS_PER_HOUR"
@@ -462,9 +508,13 @@ import (
 	mode                  "MODE"
 	modify                "MODIFY"
 	month                 "MONTH"
+	multiLineString       "MULTILINESTRING"
+	multiPoint            "MULTIPOINT"
+	multiPolygon          "MULTIPOLYGON"
 	names                 "NAMES"
 	national              "NATIONAL"
 	ncharType             "NCHAR"
+	nested                "NESTED"
 	never                 "NEVER"
 	next                  "NEXT"
 	nextval               "NEXTVAL"
@@ -486,6 +536,7 @@ import (
 	only                  "ONLY"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	ordinality            "ORDINALITY"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -493,11 +544,14 @@ import (
 	partitioning          "PARTITIONING"
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
+	path                  "PATH"
 	percent               "PERCENT"
 	per_db                "PER_DB"
 	per_table             "PER_TABLE"
 	pipesAsOr
 	plugins               "PLUGINS"
+	point                 "POINT"
+	polygon               "POLYGON"
 	policy                "POLICY"
 	preSplitRegions       "PRE_SPLIT_REGIONS"
 	preceding             "PRECEDING"
@@ -516,7 +570,9 @@ import (
 	quick                 "QUICK"
 	rateLimit             "RATE_LIMIT"
 	rebuild               "REBUILD"
+	recommend             "RECOMMEND"
 	recover               "RECOVER"
+	refresh               "REFRESH"
 	redundant             "REDUNDANT"
 	reload                "RELOAD"
 	remove                "REMOVE"
@@ -535,6 +591,7 @@ import (
 	reverse               "REVERSE"
 	role                  "ROLE"
 	rollback              "ROLLBACK"
+	rollup                "ROLLUP"
 	routine               "ROUTINE"
 	rowCount              "ROW_COUNT"
 	rowFormat             "ROW_FORMAT"
@@ -552,6 +609,7 @@ import (
 	serial                "SERIAL"
 	serializable          "SERIALIZABLE"
 	session               "SESSION"
+	sets                  "SETS"
 	setval                "SETVAL"
 	shardRowIDBits        "SHARD_ROW_ID_BITS"
 	share                 "SHARE"
@@ -577,6 +635,7 @@ import (
 	sqlTsiSecond          "SQL_TSI_SECOND"
 	sqlTsiWeek            "SQL_TSI_WEEK"
 	sqlTsiYear            "SQL_TSI_YEAR"
+	srid                  "SRID"
 	start                 "START"
 	statsAutoRecalc       "STATS_AUTO_RECALC"
 	statsPersistent       "STATS_PERSISTENT"
@@ -613,6 +672,7 @@ import (
 	undefined             "UNDEFINED"
 	unicodeSym            "UNICODE"
 	unknown               "UNKNOWN"
+	until                 "UNTIL"
 	user                  "USER"
 	validation            "VALIDATION"
 	value                 "VALUE"
@@ -622,6 +682,7 @@ import (
 	warnings              "WARNINGS"
 	week                  "WEEK"
 	weightString          "WEIGHT_STRING"
+	while                 "WHILE"
 	without               "WITHOUT"
 	x509                  "X509"
 	yearType              "YEAR"
@@ -722,6 +783,7 @@ import (
 	builtins                   "BUILTINS"
 	cancel                     "CANCEL"
 	cardinality                "CARDINALITY"
+	cardinalityCorrection      "CARDINALITY_CORRECTION"
 	cmSketch                   "CMSKETCH"
 	columnStatsUsage           "COLUMN_STATS_USAGE"
 	correlation                "CORRELATION"
@@ -852,104 +914,125 @@ import (
 	ProcedureCall                   "Procedure call with Identifier or identifier"
 
 %type	<statement>
-	AdminStmt                  "Check table statement or show ddl statement"
-	AlterDatabaseStmt          "Alter database statement"
-	AlterTableStmt             "Alter table statement"
-	AlterUserStmt              "Alter user statement"
-	AlterImportStmt            "ALTER IMPORT statement"
-	AlterInstanceStmt          "Alter instance statement"
-	AlterPolicyStmt            "Alter Placement Policy statement"
-	AlterSequenceStmt          "Alter sequence statement"
-	AnalyzeTableStmt           "Analyze table statement"
-	BeginTransactionStmt       "BEGIN TRANSACTION statement"
-	BinlogStmt                 "Binlog base64 statement"
-	BRIEStmt                   "BACKUP or RESTORE statement"
-	CommitStmt                 "COMMIT statement"
-	CreateTableStmt            "CREATE TABLE statement"
-	CreateViewStmt             "CREATE VIEW  statement"
-	CreateUserStmt             "CREATE User statement"
-	CreateRoleStmt             "CREATE Role statement"
-	CreateDatabaseStmt         "Create Database Statement"
-	CreateIndexStmt            "CREATE INDEX statement"
-	CreateImportStmt           "CREATE IMPORT statement"
-	CreateBindingStmt          "CREATE BINDING  statement"
-	CreatePolicyStmt           "CREATE PLACEMENT POLICY statement"
-	CreateSequenceStmt         "CREATE SEQUENCE statement"
-	CreateStatisticsStmt       "CREATE STATISTICS statement"
-	DoStmt                     "Do statement"
-	DropDatabaseStmt           "DROP DATABASE statement"
-	DropImportStmt             "DROP IMPORT statement"
-	DropIndexStmt              "DROP INDEX statement"
-	DropStatisticsStmt         "DROP STATISTICS statement"
-	DropStatsStmt              "DROP STATS statement"
-	DropTableStmt              "DROP TABLE statement"
-	DropSequenceStmt           "DROP SEQUENCE statement"
-	DropUserStmt               "DROP USER"
-	DropRoleStmt               "DROP ROLE"
-	DropViewStmt               "DROP VIEW statement"
-	DropBindingStmt            "DROP BINDING  statement"
-	DropPolicyStmt             "DROP PLACEMENT POLICY statement"
-	DeallocateStmt             "Deallocate prepared statement"
-	DeleteFromStmt             "DELETE FROM statement"
-	DeleteWithoutUsingStmt     "Normal DELETE statement"
-	DeleteWithUsingStmt        "DELETE USING statement"
-	EmptyStmt                  "empty statement"
-	ExecuteStmt                "Execute statement"
-	ExplainStmt                "EXPLAIN statement"
-	ExplainableStmt            "explainable statement"
-	FlushStmt                  "Flush statement"
-	FlashbackTableStmt         "Flashback table statement"
-	GrantStmt                  "Grant statement"
-	GrantProxyStmt             "Grant proxy statement"
-	GrantRoleStmt              "Grant role statement"
-	InsertIntoStmt             "INSERT INTO statement"
-	CallStmt                   "CALL statement"
-	IndexAdviseStmt            "INDEX ADVISE statement"
-	KillStmt                   "Kill statement"
-	LoadDataStmt               "Load data statement"
-	LoadStatsStmt              "Load statistic statement"
-	LockTablesStmt             "Lock tables statement"
-	NonTransactionalDeleteStmt "Non-transactional delete statement"
-	PlanReplayerStmt           "Plan replayer statement"
-	PreparedStmt               "PreparedStmt"
-	PurgeImportStmt            "PURGE IMPORT statement that removes a IMPORT task record"
-	SelectStmt                 "SELECT statement"
-	SelectStmtWithClause       "common table expression SELECT statement"
-	RenameTableStmt            "rename table statement"
-	RenameUserStmt             "rename user statement"
-	ReplaceIntoStmt            "REPLACE INTO statement"
-	RecoverTableStmt           "recover table statement"
-	ResumeImportStmt           "RESUME IMPORT statement"
-	RevokeStmt                 "Revoke statement"
-	RevokeRoleStmt             "Revoke role statement"
-	RollbackStmt               "ROLLBACK statement"
-	ReleaseSavepointStmt       "RELEASE SAVEPOINT statement"
-	SavepointStmt              "SAVEPOINT statement"
-	SplitRegionStmt            "Split index region statement"
-	SetStmt                    "Set variable statement"
-	ChangeStmt                 "Change statement"
-	SetBindingStmt             "Set binding statement"
-	SetRoleStmt                "Set active role statement"
-	SetDefaultRoleStmt         "Set default statement for some user"
-	ShowImportStmt             "SHOW IMPORT statement"
-	ShowStmt                   "Show engines/databases/tables/user/columns/warnings/status statement"
-	Statement                  "statement"
-	StopImportStmt             "STOP IMPORT statement"
-	TraceStmt                  "TRACE statement"
-	TraceableStmt              "traceable statement"
-	TruncateTableStmt          "TRUNCATE TABLE statement"
-	UnlockTablesStmt           "Unlock tables statement"
-	UpdateStmt                 "UPDATE statement"
-	SetOprStmt                 "Union/Except/Intersect select statement"
-	SetOprStmtWithLimitOrderBy "Union/Except/Intersect select statement with limit and order by"
-	SetOprStmtWoutLimitOrderBy "Union/Except/Intersect select statement without limit and order by"
-	UseStmt                    "USE statement"
-	ShutdownStmt               "SHUTDOWN statement"
-	RestartStmt                "RESTART statement"
-	CreateViewSelectOpt        "Select/Union/Except/Intersect statement in CREATE VIEW ... AS SELECT"
-	BindableStmt               "Statement that can be created binding on"
-	UpdateStmtNoWith           "Update statement without CTE clause"
-	HelpStmt                   "HELP statement"
+	AdminStmt                   "Check table statement or show ddl statement"
+	AlterDatabaseStmt           "Alter database statement"
+	AlterEventStmt              "ALTER EVENT statement"
+	AlterTableStmt              "Alter table statement"
+	AlterUserStmt               "Alter user statement"
+	AlterImportStmt             "ALTER IMPORT statement"
+	AlterInstanceStmt           "Alter instance statement"
+	AlterPolicyStmt             "Alter Placement Policy statement"
+	AlterSequenceStmt           "Alter sequence statement"
+	AnalyzeTableStmt            "Analyze table statement"
+	BeginTransactionStmt        "BEGIN TRANSACTION statement"
+	BinlogStmt                  "Binlog base64 statement"
+	BRIEStmt                    "BACKUP or RESTORE statement"
+	CommitStmt                  "COMMIT statement"
+	CreateTableStmt             "CREATE TABLE statement"
+	CreateViewStmt              "CREATE VIEW  statement"
+	CreateMaterializedViewStmt  "CREATE MATERIALIZED VIEW statement"
+	CreateRoutineStmt           "CREATE PROCEDURE/FUNCTION statement"
+	CreateTriggerStmt           "CREATE TRIGGER statement"
+	CreateEventStmt             "CREATE EVENT statement"
+	CreateUserStmt              "CREATE User statement"
+	CreateRoleStmt              "CREATE Role statement"
+	CreateDatabaseStmt          "Create Database Statement"
+	CreateIndexStmt             "CREATE INDEX statement"
+	CreateImportStmt            "CREATE IMPORT statement"
+	CreateBindingStmt           "CREATE BINDING  statement"
+	CreatePolicyStmt            "CREATE PLACEMENT POLICY statement"
+	CreateSequenceStmt          "CREATE SEQUENCE statement"
+	CreateStatisticsStmt        "CREATE STATISTICS statement"
+	DoStmt                      "Do statement"
+	DropDatabaseStmt            "DROP DATABASE statement"
+	DropImportStmt              "DROP IMPORT statement"
+	DropIndexStmt               "DROP INDEX statement"
+	DropStatisticsStmt          "DROP STATISTICS statement"
+	DropStatsStmt               "DROP STATS statement"
+	DropTableStmt               "DROP TABLE statement"
+	DropSequenceStmt            "DROP SEQUENCE statement"
+	DropUserStmt                "DROP USER"
+	DropRoleStmt                "DROP ROLE"
+	DropViewStmt                "DROP VIEW statement"
+	DropMaterializedViewStmt    "DROP MATERIALIZED VIEW statement"
+	DropRoutineStmt             "DROP PROCEDURE/FUNCTION statement"
+	DropTriggerStmt             "DROP TRIGGER statement"
+	DropEventStmt               "DROP EVENT statement"
+	DropBindingStmt             "DROP BINDING  statement"
+	DropPolicyStmt              "DROP PLACEMENT POLICY statement"
+	DeallocateStmt              "Deallocate prepared statement"
+	DeleteFromStmt              "DELETE FROM statement"
+	DeleteWithoutUsingStmt      "Normal DELETE statement"
+	DeleteWithUsingStmt         "DELETE USING statement"
+	EmptyStmt                   "empty statement"
+	ExecuteStmt                 "Execute statement"
+	ExplainStmt                 "EXPLAIN statement"
+	ExplainableStmt             "explainable statement"
+	FlushStmt                   "Flush statement"
+	FlashbackTableStmt          "Flashback table statement"
+	GrantStmt                   "Grant statement"
+	GrantProxyStmt              "Grant proxy statement"
+	GrantRoleStmt               "Grant role statement"
+	InsertIntoStmt              "INSERT INTO statement"
+	CallStmt                    "CALL statement"
+	IndexAdviseStmt             "INDEX ADVISE statement"
+	KillStmt                    "Kill statement"
+	LoadDataStmt                "Load data statement"
+	LoadStatsStmt               "Load statistic statement"
+	LockTablesStmt              "Lock tables statement"
+	RecommendIndexStmt          "RECOMMEND INDEX statement"
+	NonTransactionalDeleteStmt  "Non-transactional delete statement"
+	PlanReplayerStmt            "Plan replayer statement"
+	PreparedStmt                "PreparedStmt"
+	PurgeImportStmt             "PURGE IMPORT statement that removes a IMPORT task record"
+	SelectStmt                  "SELECT statement"
+	SelectStmtWithClause        "common table expression SELECT statement"
+	RenameTableStmt             "rename table statement"
+	RenameUserStmt              "rename user statement"
+	ReplaceIntoStmt             "REPLACE INTO statement"
+	RecoverTableStmt            "recover table statement"
+	RefreshMaterializedViewStmt "REFRESH MATERIALIZED VIEW statement"
+	ResumeImportStmt            "RESUME IMPORT statement"
+	RevokeStmt                  "Revoke statement"
+	RevokeRoleStmt              "Revoke role statement"
+	RollbackStmt                "ROLLBACK statement"
+	ReleaseSavepointStmt        "RELEASE SAVEPOINT statement"
+	SavepointStmt               "SAVEPOINT statement"
+	SplitRegionStmt             "Split index region statement"
+	SetStmt                     "Set variable statement"
+	ChangeStmt                  "Change statement"
+	SetBindingStmt              "Set binding statement"
+	SetRoleStmt                 "Set active role statement"
+	SetDefaultRoleStmt          "Set default statement for some user"
+	ShowImportStmt              "SHOW IMPORT statement"
+	ShowStmt                    "Show engines/databases/tables/user/columns/warnings/status statement"
+	StartTransactionStmt        "START TRANSACTION statement"
+	ProcedureStatement          "statement in a stored routine body"
+	ProcedureUnlabeledStmt      "unlabeled statement in a stored routine body"
+	ProcedureSQLStmt            "SQL statement in a stored routine body"
+	ProcedureLabelableStmt      "BEGIN/WHILE/REPEAT/LOOP statement in a stored routine body"
+	ProcedureDecl               "DECLARE statement in a stored routine body"
+	ProcedureCursorSelect       "SELECT statement of a cursor"
+	ProcedureIfStmt             "IF statement in a stored routine body"
+	ProcedureIfBody             "conditions and branches of an IF statement"
+	ProcedureCaseStmt           "CASE statement in a stored routine body"
+	Statement                   "statement"
+	StopImportStmt              "STOP IMPORT statement"
+	TraceStmt                   "TRACE statement"
+	TraceableStmt               "traceable statement"
+	TruncateTableStmt           "TRUNCATE TABLE statement"
+	UnlockTablesStmt            "Unlock tables statement"
+	UpdateStmt                  "UPDATE statement"
+	SetOprStmt                  "Union/Except/Intersect select statement"
+	SetOprStmtWithLimitOrderBy  "Union/Except/Intersect select statement with limit and order by"
+	SetOprStmtWoutLimitOrderBy  "Union/Except/Intersect select statement without limit and order by"
+	UseStmt                     "USE statement"
+	ShutdownStmt                "SHUTDOWN statement"
+	RestartStmt                 "RESTART statement"
+	CreateViewSelectOpt         "Select/Union/Except/Intersect statement in CREATE VIEW ... AS SELECT"
+	BindableStmt                "Statement that can be created binding on"
+	UpdateStmtNoWith            "Update statement without CTE clause"
+	HelpStmt                    "HELP statement"
 
 %type	<item>
 	AdminShowSlow                          "Admin Show Slow statement"
@@ -1039,11 +1122,14 @@ import (
 	GlobalScope                            "The scope of variable"
 	StatementScope                         "The scope of statement"
 	GroupByClause                          "GROUP BY clause"
+	GroupingSet                            "grouping set"
+	GroupingSetList                        "grouping set list"
 	HavingClause                           "HAVING clause"
 	AsOfClause                             "AS OF clause"
 	AsOfClauseOpt                          "AS OF clause optional"
 	HandleRange                            "handle range"
 	HandleRangeList                        "handle range list"
+	RefreshMethodOpt                       "Optional method of REFRESH MATERIALIZED VIEW"
 	IfExists                               "If Exists"
 	IfNotExists                            "If Not Exists"
 	IfNotRunning                           "If Not Running"
@@ -1071,9 +1157,14 @@ import (
 	IndexPartSpecificationListOpt          "Optional list of index column name or expression"
 	InsertValues                           "Rest part of INSERT/REPLACE INTO statement"
 	JoinTable                              "join table"
+	JSONTableColumn                        "JSON_TABLE column"
+	JSONTableColumnList                    "JSON_TABLE column list"
+	JSONTableOnEmptyOnErrorOpt             "JSON_TABLE ON EMPTY and ON ERROR clauses optional"
+	JSONTableOnResponse                    "JSON_TABLE ON EMPTY or ON ERROR response"
 	JoinType                               "join type"
 	KillOrKillTiDB                         "Kill or Kill TiDB"
 	LocationLabelList                      "location label name list"
+	LoadableFunctionRetType                "loadable function return type"
 	LikeTableWithOrWithoutParen            "LIKE table_name or ( LIKE table_name )"
 	LimitClause                            "LIMIT clause"
 	LimitOption                            "Limit option could be integer or parameter marker."
@@ -1293,6 +1384,7 @@ import (
 	BlobType                               "Blob types"
 	TextType                               "Text types"
 	DateAndTimeType                        "Date and Time types"
+	SpatialType                            "Spatial types"
 	OptFieldLen                            "Field length or empty"
 	FieldLen                               "Field length"
 	FieldOpts                              "Field type definition option list"
@@ -1335,46 +1427,80 @@ import (
 	StatsOptionsOpt                        "Stats options"
 	DryRunOptions                          "Dry run options"
 	OptionalShardColumn                    "Optional shard column"
+	RoutineParamListOpt                    "stored procedure parameter list optional"
+	RoutineParamList                       "stored procedure parameter list"
+	RoutineParam                           "stored procedure parameter"
+	RoutineParamMode                       "stored procedure parameter mode"
+	FunctionParamListOpt                   "stored function parameter list optional"
+	FunctionParamList                      "stored function parameter list"
+	FunctionParam                          "stored function parameter"
+	RoutineOptionListOpt                   "stored routine characteristic list optional"
+	TriggerTime                            "trigger action time"
+	TriggerEvent                           "trigger event"
+	TriggerOrderOpt                        "trigger order optional"
+	EventSchedule                          "event schedule"
+	AlterEventScheduleOpt                  "ALTER EVENT schedule and completion clauses optional"
+	EventStartsOpt                         "event STARTS clause optional"
+	EventEndsOpt                           "event ENDS clause optional"
+	EventCompletionOpt                     "event ON COMPLETION clause optional"
+	EventStatusOpt                         "event ENABLE or DISABLE clause optional"
+	EventCommentOpt                        "event COMMENT clause optional"
+	EventRenameOpt                         "event RENAME TO clause optional"
+	EventBodyOpt                           "event DO clause optional"
+	RoutineOption                          "stored routine characteristic"
+	ProcedureStmtList                      "stored routine statement list"
+	ProcedureStmtList1                     "non-empty stored routine statement list"
+	ProcedureDeclList                      "stored routine declaration list"
+	ProcedureVarNameList                   "stored routine variable name list"
+	ProcedureDefaultOpt                    "stored routine variable default value"
+	ProcedureHandlerAction                 "CONTINUE or EXIT"
+	ProcedureHandlerConditionList          "condition handler condition list"
+	ProcedureHandlerCondition              "condition handler condition"
+	ProcedureWhenClauseList                "WHEN clauses of a CASE statement"
+	ProcedureElseOpt                       "ELSE clause of a CASE statement"
+	SelectIntoVarList                      "SELECT INTO variable list"
+	SelectIntoVar                          "SELECT INTO variable"
 
 %type	<ident>
-	AsOpt             "AS or EmptyString"
-	KeyOrIndex        "{KEY|INDEX}"
-	ColumnKeywordOpt  "Column keyword or empty"
-	PrimaryOpt        "Optional primary keyword"
-	NowSym            "CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP"
-	NowSymFunc        "CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP/NOW"
-	DefaultKwdOpt     "optional DEFAULT keyword"
-	DatabaseSym       "DATABASE or SCHEMA"
-	ExplainSym        "EXPLAIN or DESCRIBE or DESC"
-	RegexpSym         "REGEXP or RLIKE"
-	IntoOpt           "INTO or EmptyString"
-	ValueSym          "Value or Values"
-	NotSym            "Not token"
-	Char              "{CHAR|CHARACTER}"
-	NChar             "{NCHAR|NATIONAL CHARACTER|NATIONAL CHAR}"
-	Varchar           "{VARCHAR|VARCHARACTER|CHARACTER VARYING|CHAR VARYING}"
-	NVarchar          "{NATIONAL VARCHAR|NATIONAL VARCHARACTER|NVARCHAR|NCHAR VARCHAR|NATIONAL CHARACTER VARYING|NATIONAL CHAR VARYING|NCHAR VARYING}"
-	Year              "{YEAR|SQL_TSI_YEAR}"
-	DeallocateSym     "Deallocate or drop"
-	OuterOpt          "optional OUTER clause"
-	CrossOpt          "Cross join option"
-	TablesTerminalSym "{TABLE|TABLES}"
-	IsolationLevel    "Isolation level"
-	ShowIndexKwd      "Show index/indexs/key keyword"
-	DistinctKwd       "DISTINCT/DISTINCTROW keyword"
-	FromOrIn          "From or In"
-	OptTable          "Optional table keyword"
-	OptInteger        "Optional Integer keyword"
-	CharsetKw         "charset or charater set"
-	CommaOpt          "optional comma"
-	logAnd            "logical and operator"
-	logOr             "logical or operator"
-	LinearOpt         "linear or empty"
-	FieldsOrColumns   "Fields or columns"
-	StorageMedia      "{DISK|MEMORY|DEFAULT}"
-	EncryptionOpt     "Encryption option 'Y' or 'N'"
-	FirstOrNext       "FIRST or NEXT"
-	RowOrRows         "ROW or ROWS"
+	AsOpt                "AS or EmptyString"
+	KeyOrIndex           "{KEY|INDEX}"
+	ColumnKeywordOpt     "Column keyword or empty"
+	PrimaryOpt           "Optional primary keyword"
+	NowSym               "CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP"
+	NowSymFunc           "CURRENT_TIMESTAMP/LOCALTIME/LOCALTIMESTAMP/NOW"
+	DefaultKwdOpt        "optional DEFAULT keyword"
+	DatabaseSym          "DATABASE or SCHEMA"
+	ExplainSym           "EXPLAIN or DESCRIBE or DESC"
+	RegexpSym            "REGEXP or RLIKE"
+	IntoOpt              "INTO or EmptyString"
+	ProcedureEndLabelOpt "stored routine end label"
+	ValueSym             "Value or Values"
+	NotSym               "Not token"
+	Char                 "{CHAR|CHARACTER}"
+	NChar                "{NCHAR|NATIONAL CHARACTER|NATIONAL CHAR}"
+	Varchar              "{VARCHAR|VARCHARACTER|CHARACTER VARYING|CHAR VARYING}"
+	NVarchar             "{NATIONAL VARCHAR|NATIONAL VARCHARACTER|NVARCHAR|NCHAR VARCHAR|NATIONAL CHARACTER VARYING|NATIONAL CHAR VARYING|NCHAR VARYING}"
+	Year                 "{YEAR|SQL_TSI_YEAR}"
+	DeallocateSym        "Deallocate or drop"
+	OuterOpt             "optional OUTER clause"
+	CrossOpt             "Cross join option"
+	TablesTerminalSym    "{TABLE|TABLES}"
+	IsolationLevel       "Isolation level"
+	ShowIndexKwd         "Show index/indexs/key keyword"
+	DistinctKwd          "DISTINCT/DISTINCTROW keyword"
+	FromOrIn             "From or In"
+	OptTable             "Optional table keyword"
+	OptInteger           "Optional Integer keyword"
+	CharsetKw            "charset or charater set"
+	CommaOpt             "optional comma"
+	logAnd               "logical and operator"
+	logOr                "logical or operator"
+	LinearOpt            "linear or empty"
+	FieldsOrColumns      "Fields or columns"
+	StorageMedia         "{DISK|MEMORY|DEFAULT}"
+	EncryptionOpt        "Encryption option 'Y' or 'N'"
+	FirstOrNext          "FIRST or NEXT"
+	RowOrRows            "ROW or ROWS"
 
 %type	<ident>
 	Identifier                      "identifier or unreserved keyword"
@@ -1392,6 +1518,7 @@ import (
 	AuthPlugin                      "Authentication plugin name"
 	CharsetName                     "Character set name"
 	CollationName                   "Collation name"
+	RecommendIndexForOpt            "Optional FOR 'sql' clause of RECOMMEND INDEX"
 	ColumnFormat                    "Column format"
 	DBName                          "Database Name"
 	PolicyName                      "Placement Policy Name"
@@ -1430,6 +1557,8 @@ import (
 %precedence set
 %precedence selectKwd
 %precedence lowerThanSelectStmt
+%precedence lowerThanInto
+%precedence into
 %precedence lowerThanInsertValues
 %precedence insertValues
 %precedence lowerThanCreateTableSelect
@@ -2514,6 +2643,38 @@ RecoverTableStmt:
 		}
 	}
 
+/*******************************************************************
+ *
+ *  Refresh Materialized View Statement
+ *
+ *  Example:
+ *      REFRESH MATERIALIZED VIEW mv;
+ *      REFRESH MATERIALIZED VIEW mv COMPLETE;
+ *      REFRESH MATERIALIZED VIEW mv FAST;
+ *
+ *******************************************************************/
+RefreshMaterializedViewStmt:
+	"REFRESH" "MATERIALIZED" "VIEW" TableName RefreshMethodOpt
+	{
+		$$ = &ast.RefreshMaterializedViewStmt{
+			ViewName: $4.(*ast.TableName),
+			Method:   $5.(ast.RefreshMethod),
+		}
+	}
+
+RefreshMethodOpt:
+	{
+		$$ = ast.RefreshMethodDefault
+	}
+|	"COMPLETE"
+	{
+		$$ = ast.RefreshMethodComplete
+	}
+|	"FAST"
+	{
+		$$ = ast.RefreshMethodFast
+	}
+
 /*******************************************************************
  *
  *  Flush Back Table Statement
@@ -2782,7 +2943,10 @@ BeginTransactionStmt:
 			Mode: ast.Optimistic,
 		}
 	}
-|	"START" "TRANSACTION"
+|	StartTransactionStmt
+
+StartTransactionStmt:
+	"START" "TRANSACTION"
 	{
 		$$ = &ast.BeginStmt{}
 	}
@@ -3110,6 +3274,14 @@ ColumnOption:
 		yylex.AppendError(yylex.Errorf("The STORAGE clause is parsed but ignored by all storage engines."))
 		parser.lastErrorAsWarn()
 	}
+|	"SRID" LengthNum
+	{
+		if $2.(uint64) > 4294967295 {
+			yylex.AppendError(yylex.Errorf("SRID must be within [0, 4294967295]"))
+			return 1
+		}
+		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionSRID, SRID: uint32($2.(uint64))}
+	}
 |	"AUTO_RANDOM" OptFieldLen
 	{
 		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionAutoRandom, AutoRandomBitLength: $2.(int)}
@@ -3490,6 +3662,10 @@ StatsType:
 	{
 		$$ = ast.StatsTypeCorrelation
 	}
+|	"HISTOGRAM"
+	{
+		$$ = ast.StatsTypeHistogram
+	}
 
 BindingStatusType:
 	"ENABLED"
@@ -4288,6 +4464,23 @@ CreateViewStmt:
 		$$ = x
 	}
 
+CreateMaterializedViewStmt:
+	"CREATE" "MATERIALIZED" "VIEW" IfNotExists ViewName ViewFieldList "AS" CreateViewSelectOpt
+	{
+		startOffset := parser.startOffset(&yyS[yypt])
+		selStmt := $8.(ast.StmtNode)
+		selStmt.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:]))
+		x := &ast.CreateMaterializedViewStmt{
+			IfNotExists: $4.(bool),
+			ViewName:    $5.(*ast.TableName),
+			Select:      selStmt,
+		}
+		if $6 != nil {
+			x.Cols = $6.([]model.CIStr)
+		}
+		$$ = x
+	}
+
 OrReplace:
 	/* EMPTY */
 	{
@@ -4546,6 +4739,34 @@ DropViewStmt:
 		$$ = &ast.DropTableStmt{IfExists: true, Tables: $5.([]*ast.TableName), IsView: true}
 	}
 
+DropRoutineStmt:
+	"DROP" "PROCEDURE" IfExists TableName
+	{
+		$$ = &ast.DropRoutineStmt{Type: ast.RoutineProcedure, IfExists: $3.(bool), Name: $4.(*ast.TableName)}
+	}
+|	"DROP" "FUNCTION" IfExists TableName
+	{
+		$$ = &ast.DropRoutineStmt{Type: ast.RoutineFunction, IfExists: $3.(bool), Name: $4.(*ast.TableName)}
+	}
+
+DropTriggerStmt:
+	"DROP" "TRIGGER" IfExists TableName
+	{
+		$$ = &ast.DropTriggerStmt{IfExists: $3.(bool), Name: $4.(*ast.TableName)}
+	}
+
+DropEventStmt:
+	"DROP" "EVENT" IfExists TableName
+	{
+		$$ = &ast.DropEventStmt{IfExists: $3.(bool), Name: $4.(*ast.TableName)}
+	}
+
+DropMaterializedViewStmt:
+	"DROP" "MATERIALIZED" "VIEW" IfExists TableName
+	{
+		$$ = &ast.DropMaterializedViewStmt{IfExists: $4.(bool), ViewName: $5.(*ast.TableName)}
+	}
+
 DropUserStmt:
 	"DROP" "USER" UsernameList
 	{
@@ -5627,6 +5848,47 @@ GroupByClause:
 	{
 		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem)}
 	}
+|	"GROUP" "BY" ByList "WITH ROLLUP"
+	{
+		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem), Tp: ast.GroupingSetsRollup}
+	}
+|	"GROUP" "BY" "CUBE" '(' ByList ')'
+	{
+		$$ = &ast.GroupByClause{Items: $5.([]*ast.ByItem), Tp: ast.GroupingSetsCube}
+	}
+|	"GROUP" "BY" "GROUPING" "SETS" '(' GroupingSetList ')'
+	{
+		gby := &ast.GroupByClause{Tp: ast.GroupingSetsList}
+		for _, set := range $6.([][]*ast.ByItem) {
+			offsets := make([]int, 0, len(set))
+			for _, item := range set {
+				offsets = append(offsets, len(gby.Items))
+				gby.Items = append(gby.Items, item)
+			}
+			gby.GroupingSets = append(gby.GroupingSets, offsets)
+		}
+		$$ = gby
+	}
+
+GroupingSetList:
+	GroupingSet
+	{
+		$$ = [][]*ast.ByItem{$1.([]*ast.ByItem)}
+	}
+|	GroupingSetList ',' GroupingSet
+	{
+		$$ = append($1.([][]*ast.ByItem), $3.([]*ast.ByItem))
+	}
+
+GroupingSet:
+	'(' ')'
+	{
+		$$ = []*ast.ByItem{}
+	}
+|	'(' ByList ')'
+	{
+		$$ = $2
+	}
 
 HavingClause:
 	{
@@ -5825,6 +6087,10 @@ IndexTypeName:
 	{
 		$$ = model.IndexTypeRtree
 	}
+|	"HYPO"
+	{
+		$$ = model.IndexTypeHypo
+	}
 
 IndexInvisible:
 	"VISIBLE"
@@ -5874,6 +6140,7 @@ UnReservedKeyword:
 |	"SAN"
 |	"COMMIT"
 |	"COMPACT"
+|	"COMPLETE"
 |	"COMPRESSED"
 |	"CONSISTENCY"
 |	"CONSISTENT"
@@ -5926,7 +6193,9 @@ UnReservedKeyword:
 |	"PROXY"
 |	"QUICK"
 |	"REBUILD"
+|	"RECOMMEND"
 |	"REDUNDANT"
+|	"REFRESH"
 |	"REORGANIZE"
 |	"RESTART"
 |	"ROLE"
@@ -5970,6 +6239,7 @@ UnReservedKeyword:
 |	"COMPRESSION"
 |	"KEY_BLOCK_SIZE"
 |	"MASTER"
+|	"MATERIALIZED"
 |	"MAX_ROWS"
 |	"MIN_ROWS"
 |	"NATIONAL"
@@ -6122,6 +6392,7 @@ UnReservedKeyword:
 |	"LABELS"
 |	"LOGS"
 |	"HOSTS"
+|	"HYPO"
 |	"AGAINST"
 |	"EXPANSION"
 |	"INCREMENT"
@@ -6182,6 +6453,55 @@ UnReservedKeyword:
 |	"CLUSTERED"
 |	"NONCLUSTERED"
 |	"PRESERVE"
+|	"EMPTY"
+|	"NESTED"
+|	"ORDINALITY"
+|	"PATH"
+|	"ROLLUP"
+|	"SETS"
+|	"GEOMETRY"
+|	"POINT"
+|	"LINESTRING"
+|	"POLYGON"
+|	"MULTIPOINT"
+|	"MULTILINESTRING"
+|	"MULTIPOLYGON"
+|	"GEOMETRYCOLLECTION"
+|	"GEOMCOLLECTION"
+|	"SRID"
+|	"BEFORE"
+|	"AT"
+|	"CLOSE"
+|	"COMPLETION"
+|	"CONTAINS"
+|	"CONTINUE"
+|	"CURSOR"
+|	"DECLARE"
+|	"DETERMINISTIC"
+|	"EACH"
+|	"ELSEIF"
+|	"ENDS"
+|	"EVERY"
+|	"EXIT"
+|	"FOLLOWS"
+|	"FOUND"
+|	"HANDLER"
+|	"ITERATE"
+|	"LEAVE"
+|	"LOOP"
+|	"MODIFIES"
+|	"PRECEDES"
+|	"READS"
+|	"RETURN"
+|	"RETURNS"
+|	"SQLEXCEPTION"
+|	"SQLSTATE"
+|	"SQLWARNING"
+|	"SONAME"
+|	"STARTS"
+|	"STRING"
+|	"UNTIL"
+|	"WHILE"
 
 TiDBKeyword:
 	"ADMIN"
@@ -6190,6 +6510,7 @@ TiDBKeyword:
 |	"BUILTINS"
 |	"CANCEL"
 |	"CARDINALITY"
+|	"CARDINALITY_CORRECTION"
 |	"CMSKETCH"
 |	"COLUMN_STATS_USAGE"
 |	"CORRELATION"
@@ -6228,93 +6549,826 @@ TiDBKeyword:
 |	"DRY"
 |	"RUN"
 
-NotKeywordToken:
-	"ADDDATE"
-|	"APPROX_COUNT_DISTINCT"
-|	"APPROX_PERCENTILE"
-|	"BIT_AND"
-|	"BIT_OR"
-|	"BIT_XOR"
-|	"BRIEF"
-|	"CAST"
-|	"COPY"
-|	"CURTIME"
-|	"DATE_ADD"
-|	"DATE_SUB"
-|	"DOT"
-|	"DUMP"
-|	"EXTRACT"
-|	"GET_FORMAT"
-|	"GROUP_CONCAT"
-|	"INPLACE"
-|	"INSTANT"
-|	"INTERNAL"
-|	"MIN"
-|	"MAX"
-|	"NOW"
-|	"RECENT"
-|	"REPLAYER"
-|	"RUNNING"
-|	"PLACEMENT"
-|	"PLAN"
-|	"PLAN_CACHE"
-|	"POSITION"
-|	"PREDICATE"
-|	"S3"
-|	"STRICT"
-|	"SUBDATE"
-|	"SUBSTRING"
-|	"SUM"
-|	"STD"
-|	"STDDEV"
-|	"STDDEV_POP"
-|	"STDDEV_SAMP"
-|	"STOP"
-|	"VARIANCE"
-|	"VAR_POP"
-|	"VAR_SAMP"
-|	"TARGET"
-|	"TIMESTAMPADD"
-|	"TIMESTAMPDIFF"
-|	"TOKUDB_DEFAULT"
-|	"TOKUDB_FAST"
-|	"TOKUDB_LZMA"
-|	"TOKUDB_QUICKLZ"
-|	"TOKUDB_SNAPPY"
-|	"TOKUDB_SMALL"
-|	"TOKUDB_UNCOMPRESSED"
-|	"TOKUDB_ZLIB"
-|	"TOP"
-|	"TRIM"
-|	"NEXT_ROW_ID"
-|	"EXPR_PUSHDOWN_BLACKLIST"
-|	"OPT_RULE_BLACKLIST"
-|	"BOUND"
-|	"EXACT" %prec lowerThanStringLitToken
-|	"STALENESS"
-|	"STRONG"
-|	"FLASHBACK"
-|	"JSON_OBJECTAGG"
-|	"JSON_ARRAYAGG"
-|	"TLS"
-|	"FOLLOWER"
-|	"FOLLOWERS"
-|	"LEADER"
-|	"LEARNER"
-|	"LEARNERS"
-|	"VERBOSE"
-|	"TRUE_CARD_COST"
-|	"VOTER"
-|	"VOTERS"
-|	"CONSTRAINTS"
-|	"PRIMARY_REGION"
-|	"SCHEDULE"
-|	"LEADER_CONSTRAINTS"
-|	"FOLLOWER_CONSTRAINTS"
-|	"LEARNER_CONSTRAINTS"
-|	"VOTER_CONSTRAINTS"
-|	"NORMAL"
-|	"FAST"
+NotKeywordToken:
+	"ADDDATE"
+|	"APPROX_COUNT_DISTINCT"
+|	"APPROX_PERCENTILE"
+|	"BIT_AND"
+|	"BIT_OR"
+|	"BIT_XOR"
+|	"BRIEF"
+|	"CAST"
+|	"COPY"
+|	"CURTIME"
+|	"DATE_ADD"
+|	"DATE_SUB"
+|	"DOT"
+|	"DUMP"
+|	"EXTRACT"
+|	"GET_FORMAT"
+|	"GROUP_CONCAT"
+|	"INPLACE"
+|	"INSTANT"
+|	"INTERNAL"
+|	"MIN"
+|	"MAX"
+|	"NOW"
+|	"RECENT"
+|	"REPLAYER"
+|	"RUNNING"
+|	"PLACEMENT"
+|	"PLAN"
+|	"PLAN_CACHE"
+|	"POSITION"
+|	"PREDICATE"
+|	"S3"
+|	"STRICT"
+|	"SUBDATE"
+|	"SUBSTRING"
+|	"SUM"
+|	"STD"
+|	"STDDEV"
+|	"STDDEV_POP"
+|	"STDDEV_SAMP"
+|	"STOP"
+|	"VARIANCE"
+|	"VAR_POP"
+|	"VAR_SAMP"
+|	"TARGET"
+|	"TIMESTAMPADD"
+|	"TIMESTAMPDIFF"
+|	"TOKUDB_DEFAULT"
+|	"TOKUDB_FAST"
+|	"TOKUDB_LZMA"
+|	"TOKUDB_QUICKLZ"
+|	"TOKUDB_SNAPPY"
+|	"TOKUDB_SMALL"
+|	"TOKUDB_UNCOMPRESSED"
+|	"TOKUDB_ZLIB"
+|	"TOP"
+|	"TRIM"
+|	"NEXT_ROW_ID"
+|	"EXPR_PUSHDOWN_BLACKLIST"
+|	"OPT_RULE_BLACKLIST"
+|	"BOUND"
+|	"EXACT" %prec lowerThanStringLitToken
+|	"STALENESS"
+|	"STRONG"
+|	"FLASHBACK"
+|	"JSON_OBJECTAGG"
+|	"JSON_ARRAYAGG"
+|	"TLS"
+|	"FOLLOWER"
+|	"FOLLOWERS"
+|	"LEADER"
+|	"LEARNER"
+|	"LEARNERS"
+|	"VERBOSE"
+|	"TRUE_CARD_COST"
+|	"VOTER"
+|	"VOTERS"
+|	"CONSTRAINTS"
+|	"PRIMARY_REGION"
+|	"SCHEDULE"
+|	"LEADER_CONSTRAINTS"
+|	"FOLLOWER_CONSTRAINTS"
+|	"LEARNER_CONSTRAINTS"
+|	"VOTER_CONSTRAINTS"
+|	"NORMAL"
+|	"FAST"
+
+/************************************************************************************
+ *
+ *  Stored Routine Statements
+ *
+ *  See https://dev.mysql.com/doc/refman/8.0/en/create-procedure.html
+ **********************************************************************************/
+CreateRoutineStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "PROCEDURE" IfNotExists TableName '(' RoutineParamListOpt ')' RoutineOptionListOpt ProcedureStatement
+	{
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		body := $12
+		body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[parser.startOffset(&yyS[yypt]):parser.yylval.offset]))
+		$$ = &ast.CreateRoutineStmt{
+			Type:        ast.RoutineProcedure,
+			IfNotExists: $6.(bool),
+			Definer:     $4.(*auth.UserIdentity),
+			Name:        $7.(*ast.TableName),
+			Params:      $9.([]*ast.RoutineParam),
+			Options:     $11.([]*ast.RoutineOption),
+			Body:        body,
+		}
+	}
+|	"CREATE" OrReplace ViewAlgorithm ViewDefiner "FUNCTION" IfNotExists TableName '(' FunctionParamListOpt ')' "RETURNS" Type RoutineOptionListOpt ProcedureStatement
+	{
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		body := $14
+		body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[parser.startOffset(&yyS[yypt]):parser.yylval.offset]))
+		$$ = &ast.CreateRoutineStmt{
+			Type:        ast.RoutineFunction,
+			IfNotExists: $6.(bool),
+			Definer:     $4.(*auth.UserIdentity),
+			Name:        $7.(*ast.TableName),
+			Params:      $9.([]*ast.RoutineParam),
+			Returns:     $12.(*types.FieldType),
+			Options:     $13.([]*ast.RoutineOption),
+			Body:        body,
+		}
+	}
+|	"CREATE" OrReplace ViewAlgorithm ViewDefiner "FUNCTION" IfNotExists TableName "RETURNS" LoadableFunctionRetType "SONAME" stringLit
+	{
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined || !$4.(*auth.UserIdentity).CurrentUser || $7.(*ast.TableName).Schema.L != "" {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		$$ = &ast.CreateLoadableFunctionStmt{
+			IfNotExists: $6.(bool),
+			Name:        $7.(*ast.TableName).Name,
+			Returns:     $9.(ast.LoadableFunctionRetType),
+			SOName:      $11,
+		}
+	}
+
+LoadableFunctionRetType:
+	"STRING"
+	{
+		$$ = ast.LoadableFunctionRetString
+	}
+|	"INTEGER"
+	{
+		$$ = ast.LoadableFunctionRetInteger
+	}
+|	"REAL"
+	{
+		$$ = ast.LoadableFunctionRetReal
+	}
+|	"DECIMAL"
+	{
+		$$ = ast.LoadableFunctionRetDecimal
+	}
+
+/************************************************************************************
+ *
+ *  Trigger Statements
+ *
+ *  See https://dev.mysql.com/doc/refman/8.0/en/create-trigger.html
+ **********************************************************************************/
+CreateTriggerStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "TRIGGER" IfNotExists TableName TriggerTime TriggerEvent "ON" TableName "FOR" "EACH" "ROW" TriggerOrderOpt ProcedureStatement
+	{
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		body := $16
+		body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[parser.startOffset(&yyS[yypt]):parser.yylval.offset]))
+		stmt := &ast.CreateTriggerStmt{
+			IfNotExists: $6.(bool),
+			Definer:     $4.(*auth.UserIdentity),
+			Name:        $7.(*ast.TableName),
+			Time:        $8.(model.TriggerTime),
+			Event:       $9.(model.TriggerEvent),
+			Table:       $11.(*ast.TableName),
+			Body:        body,
+		}
+		if $15 != nil {
+			stmt.Order = $15.(*ast.TriggerOrder)
+		}
+		$$ = stmt
+	}
+
+TriggerTime:
+	"BEFORE"
+	{
+		$$ = model.TriggerBefore
+	}
+|	"AFTER"
+	{
+		$$ = model.TriggerAfter
+	}
+
+TriggerEvent:
+	"INSERT"
+	{
+		$$ = model.TriggerInsert
+	}
+|	"UPDATE"
+	{
+		$$ = model.TriggerUpdate
+	}
+|	"DELETE"
+	{
+		$$ = model.TriggerDelete
+	}
+
+TriggerOrderOpt:
+	{
+		$$ = nil
+	}
+|	"FOLLOWS" Identifier
+	{
+		$$ = &ast.TriggerOrder{Tp: ast.TriggerFollows, Trigger: model.NewCIStr($2)}
+	}
+|	"PRECEDES" Identifier
+	{
+		$$ = &ast.TriggerOrder{Tp: ast.TriggerPrecedes, Trigger: model.NewCIStr($2)}
+	}
+
+/************************************************************************************
+ *
+ *  Event Statements
+ *
+ *  See https://dev.mysql.com/doc/refman/8.0/en/create-event.html
+ **********************************************************************************/
+CreateEventStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "EVENT" IfNotExists TableName "ON" "SCHEDULE" EventSchedule EventCompletionOpt EventStatusOpt EventCommentOpt "DO" ProcedureStatement
+	{
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		body := $15
+		body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[parser.startOffset(&yyS[yypt]):parser.yylval.offset]))
+		stmt := &ast.CreateEventStmt{
+			IfNotExists: $6.(bool),
+			Definer:     $4.(*auth.UserIdentity),
+			Name:        $7.(*ast.TableName),
+			Schedule:    $10.(*ast.EventSchedule),
+			Completion:  $11.(ast.EventCompletion),
+			Status:      $12.(ast.EventStatus),
+			Body:        body,
+		}
+		if $13 != nil {
+			stmt.Comment = $13.(string)
+		}
+		$$ = stmt
+	}
+
+AlterEventStmt:
+	"ALTER" ViewDefiner "EVENT" TableName AlterEventScheduleOpt EventRenameOpt EventStatusOpt EventCommentOpt EventBodyOpt
+	{
+		stmt := $5.(*ast.AlterEventStmt)
+		stmt.Definer = $2.(*auth.UserIdentity)
+		stmt.Name = $4.(*ast.TableName)
+		stmt.Status = $7.(ast.EventStatus)
+		if $6 != nil {
+			stmt.NewName = $6.(*ast.TableName)
+		}
+		if $8 != nil {
+			comment := $8.(string)
+			stmt.Comment = &comment
+		}
+		if $9 != nil {
+			stmt.Body = $9.(ast.StmtNode)
+		}
+		if stmt.Schedule == nil && stmt.Completion == ast.EventCompletionUnspecified && stmt.NewName == nil &&
+			stmt.Status == ast.EventStatusUnspecified && stmt.Comment == nil && stmt.Body == nil {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		$$ = stmt
+	}
+
+EventSchedule:
+	"AT" Expression
+	{
+		$$ = &ast.EventSchedule{At: $2}
+	}
+|	"EVERY" Expression TimeUnit EventStartsOpt EventEndsOpt
+	{
+		schedule := &ast.EventSchedule{Every: $2, Unit: $3.(ast.TimeUnitType)}
+		if $4 != nil {
+			schedule.Starts = $4.(ast.ExprNode)
+		}
+		if $5 != nil {
+			schedule.Ends = $5.(ast.ExprNode)
+		}
+		$$ = schedule
+	}
+
+AlterEventScheduleOpt:
+	{
+		$$ = &ast.AlterEventStmt{}
+	}
+|	"ON" "SCHEDULE" EventSchedule EventCompletionOpt
+	{
+		$$ = &ast.AlterEventStmt{Schedule: $3.(*ast.EventSchedule), Completion: $4.(ast.EventCompletion)}
+	}
+|	"ON" "COMPLETION" "PRESERVE"
+	{
+		$$ = &ast.AlterEventStmt{Completion: ast.EventCompletionPreserve}
+	}
+|	"ON" "COMPLETION" "NOT" "PRESERVE"
+	{
+		$$ = &ast.AlterEventStmt{Completion: ast.EventCompletionNotPreserve}
+	}
+
+EventStartsOpt:
+	{
+		$$ = nil
+	}
+|	"STARTS" Expression
+	{
+		$$ = $2
+	}
+
+EventEndsOpt:
+	{
+		$$ = nil
+	}
+|	"ENDS" Expression
+	{
+		$$ = $2
+	}
+
+EventCompletionOpt:
+	{
+		$$ = ast.EventCompletionUnspecified
+	}
+|	"ON" "COMPLETION" "PRESERVE"
+	{
+		$$ = ast.EventCompletionPreserve
+	}
+|	"ON" "COMPLETION" "NOT" "PRESERVE"
+	{
+		$$ = ast.EventCompletionNotPreserve
+	}
+
+EventStatusOpt:
+	{
+		$$ = ast.EventStatusUnspecified
+	}
+|	"ENABLE"
+	{
+		$$ = ast.EventEnabled
+	}
+|	"DISABLE"
+	{
+		$$ = ast.EventDisabled
+	}
+|	"DISABLE" "ON" "SLAVE"
+	{
+		$$ = ast.EventSlavesideDisabled
+	}
+
+EventCommentOpt:
+	{
+		$$ = nil
+	}
+|	"COMMENT" stringLit
+	{
+		$$ = $2
+	}
+
+EventRenameOpt:
+	{
+		$$ = nil
+	}
+|	"RENAME" "TO" TableName
+	{
+		$$ = $3
+	}
+
+EventBodyOpt:
+	{
+		$$ = nil
+	}
+|	"DO" ProcedureStatement
+	{
+		body := $2
+		body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[parser.startOffset(&yyS[yypt]):parser.yylval.offset]))
+		$$ = body
+	}
+
+RoutineParamListOpt:
+	{
+		$$ = []*ast.RoutineParam{}
+	}
+|	RoutineParamList
+
+RoutineParamList:
+	RoutineParam
+	{
+		$$ = []*ast.RoutineParam{$1.(*ast.RoutineParam)}
+	}
+|	RoutineParamList ',' RoutineParam
+	{
+		$$ = append($1.([]*ast.RoutineParam), $3.(*ast.RoutineParam))
+	}
+
+RoutineParam:
+	RoutineParamMode Identifier Type
+	{
+		$$ = &ast.RoutineParam{Mode: $1.(ast.RoutineParamMode), Name: model.NewCIStr($2), Tp: $3.(*types.FieldType)}
+	}
+
+RoutineParamMode:
+	{
+		$$ = ast.RoutineParamModeIn
+	}
+|	"IN"
+	{
+		$$ = ast.RoutineParamModeIn
+	}
+|	"OUT"
+	{
+		$$ = ast.RoutineParamModeOut
+	}
+|	"INOUT"
+	{
+		$$ = ast.RoutineParamModeInOut
+	}
+
+FunctionParamListOpt:
+	{
+		$$ = []*ast.RoutineParam{}
+	}
+|	FunctionParamList
+
+FunctionParamList:
+	FunctionParam
+	{
+		$$ = []*ast.RoutineParam{$1.(*ast.RoutineParam)}
+	}
+|	FunctionParamList ',' FunctionParam
+	{
+		$$ = append($1.([]*ast.RoutineParam), $3.(*ast.RoutineParam))
+	}
+
+FunctionParam:
+	Identifier Type
+	{
+		$$ = &ast.RoutineParam{Name: model.NewCIStr($1), Tp: $2.(*types.FieldType)}
+	}
+
+RoutineOptionListOpt:
+	{
+		$$ = []*ast.RoutineOption{}
+	}
+|	RoutineOptionListOpt RoutineOption
+	{
+		$$ = append($1.([]*ast.RoutineOption), $2.(*ast.RoutineOption))
+	}
+
+RoutineOption:
+	"COMMENT" stringLit
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionComment, StrValue: $2}
+	}
+|	"LANGUAGE" "SQL"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionLanguage}
+	}
+|	"DETERMINISTIC"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionDeterministic, BoolValue: true}
+	}
+|	"NOT" "DETERMINISTIC"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionDeterministic, BoolValue: false}
+	}
+|	"CONTAINS" "SQL"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionDataAccess, DataAccess: ast.RoutineDataAccessContainsSQL}
+	}
+|	"NO" "SQL"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionDataAccess, DataAccess: ast.RoutineDataAccessNoSQL}
+	}
+|	"READS" "SQL" "DATA"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionDataAccess, DataAccess: ast.RoutineDataAccessReadsSQLData}
+	}
+|	"MODIFIES" "SQL" "DATA"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionDataAccess, DataAccess: ast.RoutineDataAccessModifiesSQLData}
+	}
+|	"SQL" "SECURITY" "DEFINER"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionSecurity, Security: model.SecurityDefiner}
+	}
+|	"SQL" "SECURITY" "INVOKER"
+	{
+		$$ = &ast.RoutineOption{Tp: ast.RoutineOptionSecurity, Security: model.SecurityInvoker}
+	}
+
+ProcedureStatement:
+	ProcedureUnlabeledStmt
+|	identifier ':' ProcedureLabelableStmt ProcedureEndLabelOpt
+	{
+		if $4 != "" && !strings.EqualFold($1, $4) {
+			yylex.AppendError(ErrSpLabelMismatch.GenWithStackByArgs($4))
+			return 1
+		}
+		label := model.NewCIStr($1)
+		switch x := $3.(type) {
+		case *ast.ProcedureBlockStmt:
+			x.Label = label
+		case *ast.ProcedureWhileStmt:
+			x.Label = label
+		case *ast.ProcedureRepeatStmt:
+			x.Label = label
+		case *ast.ProcedureLoopStmt:
+			x.Label = label
+		}
+		$$ = $3
+	}
+
+ProcedureEndLabelOpt:
+	{
+		$$ = ""
+	}
+|	identifier
+
+ProcedureUnlabeledStmt:
+	ProcedureSQLStmt
+|	ProcedureLabelableStmt
+|	ProcedureIfStmt
+|	ProcedureCaseStmt
+|	"LEAVE" identifier
+	{
+		$$ = &ast.ProcedureJumpStmt{Tp: ast.ProcedureLeave, Label: model.NewCIStr($2)}
+	}
+|	"ITERATE" identifier
+	{
+		$$ = &ast.ProcedureJumpStmt{Tp: ast.ProcedureIterate, Label: model.NewCIStr($2)}
+	}
+|	"OPEN" Identifier
+	{
+		$$ = &ast.ProcedureOpenStmt{Cursor: model.NewCIStr($2)}
+	}
+|	"FETCH" Identifier "INTO" ProcedureVarNameList
+	{
+		$$ = &ast.ProcedureFetchStmt{Cursor: model.NewCIStr($2), Vars: $4.([]model.CIStr)}
+	}
+|	"FETCH" "FROM" Identifier "INTO" ProcedureVarNameList
+	{
+		$$ = &ast.ProcedureFetchStmt{Cursor: model.NewCIStr($3), Vars: $5.([]model.CIStr)}
+	}
+|	"FETCH" "NEXT" "FROM" Identifier "INTO" ProcedureVarNameList
+	{
+		$$ = &ast.ProcedureFetchStmt{Cursor: model.NewCIStr($4), Vars: $6.([]model.CIStr)}
+	}
+|	"CLOSE" Identifier
+	{
+		$$ = &ast.ProcedureCloseStmt{Cursor: model.NewCIStr($2)}
+	}
+|	"RETURN" Expression
+	{
+		$$ = &ast.ProcedureReturnStmt{Expr: $2}
+	}
+
+ProcedureSQLStmt:
+	AlterEventStmt
+|	AlterTableStmt
+|	AnalyzeTableStmt
+|	CallStmt
+|	CommitStmt
+|	CreateEventStmt
+|	CreateIndexStmt
+|	CreateTableStmt
+|	CreateViewStmt
+|	DeallocateStmt
+|	DeleteFromStmt
+|	DoStmt
+|	DropEventStmt
+|	DropIndexStmt
+|	DropTableStmt
+|	DropViewStmt
+|	ExecuteStmt
+|	ExplainStmt
+|	InsertIntoStmt
+|	PreparedStmt
+|	ReleaseSavepointStmt
+|	RenameTableStmt
+|	ReplaceIntoStmt
+|	RollbackStmt
+|	SavepointStmt
+|	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
+|	SetStmt
+|	ShowStmt
+|	StartTransactionStmt
+|	TruncateTableStmt
+|	UpdateStmt
+
+ProcedureLabelableStmt:
+	"BEGIN" ProcedureDeclList ProcedureStmtList "END"
+	{
+		$$ = &ast.ProcedureBlockStmt{Decls: $2.([]ast.StmtNode), Stmts: $3.([]ast.StmtNode)}
+	}
+|	"WHILE" Expression "DO" ProcedureStmtList1 "END" "WHILE"
+	{
+		$$ = &ast.ProcedureWhileStmt{Cond: $2, Stmts: $4.([]ast.StmtNode)}
+	}
+|	"REPEAT" ProcedureStmtList1 "UNTIL" Expression "END" "REPEAT"
+	{
+		$$ = &ast.ProcedureRepeatStmt{Stmts: $2.([]ast.StmtNode), Until: $4}
+	}
+|	"LOOP" ProcedureStmtList1 "END" "LOOP"
+	{
+		$$ = &ast.ProcedureLoopStmt{Stmts: $2.([]ast.StmtNode)}
+	}
+
+ProcedureStmtList:
+	{
+		$$ = []ast.StmtNode{}
+	}
+|	ProcedureStmtList ProcedureStatement ';'
+	{
+		$$ = append($1.([]ast.StmtNode), $2)
+	}
+
+ProcedureStmtList1:
+	ProcedureStatement ';'
+	{
+		$$ = []ast.StmtNode{$1}
+	}
+|	ProcedureStmtList1 ProcedureStatement ';'
+	{
+		$$ = append($1.([]ast.StmtNode), $2)
+	}
+
+ProcedureDeclList:
+	{
+		$$ = []ast.StmtNode{}
+	}
+|	ProcedureDeclList ProcedureDecl ';'
+	{
+		$$ = append($1.([]ast.StmtNode), $2)
+	}
+
+ProcedureDecl:
+	"DECLARE" ProcedureVarNameList Type ProcedureDefaultOpt
+	{
+		x := &ast.ProcedureVarDeclStmt{Names: $2.([]model.CIStr), Tp: $3.(*types.FieldType)}
+		if $4 != nil {
+			x.Default = $4.(ast.ExprNode)
+		}
+		$$ = x
+	}
+|	"DECLARE" Identifier "CURSOR" "FOR" ProcedureCursorSelect
+	{
+		$$ = &ast.ProcedureCursorDeclStmt{Name: model.NewCIStr($2), Select: $5}
+	}
+|	"DECLARE" ProcedureHandlerAction "HANDLER" "FOR" ProcedureHandlerConditionList ProcedureStatement
+	{
+		$$ = &ast.ProcedureHandlerDeclStmt{
+			Action:     $2.(ast.ProcedureHandlerAction),
+			Conditions: $5.([]*ast.ProcedureHandlerCondition),
+			Stmt:       $6,
+		}
+	}
+
+ProcedureVarNameList:
+	Identifier
+	{
+		$$ = []model.CIStr{model.NewCIStr($1)}
+	}
+|	ProcedureVarNameList ',' Identifier
+	{
+		$$ = append($1.([]model.CIStr), model.NewCIStr($3))
+	}
+
+ProcedureDefaultOpt:
+	{
+		$$ = nil
+	}
+|	"DEFAULT" Expression
+	{
+		$$ = $2
+	}
+
+ProcedureCursorSelect:
+	SelectStmt
+|	SelectStmtWithClause
+|	SetOprStmt
+
+ProcedureHandlerAction:
+	"CONTINUE"
+	{
+		$$ = ast.ProcedureHandlerContinue
+	}
+|	"EXIT"
+	{
+		$$ = ast.ProcedureHandlerExit
+	}
+
+ProcedureHandlerConditionList:
+	ProcedureHandlerCondition
+	{
+		$$ = []*ast.ProcedureHandlerCondition{$1.(*ast.ProcedureHandlerCondition)}
+	}
+|	ProcedureHandlerConditionList ',' ProcedureHandlerCondition
+	{
+		$$ = append($1.([]*ast.ProcedureHandlerCondition), $3.(*ast.ProcedureHandlerCondition))
+	}
+
+ProcedureHandlerCondition:
+	"SQLEXCEPTION"
+	{
+		$$ = &ast.ProcedureHandlerCondition{Tp: ast.ProcedureConditionSQLException}
+	}
+|	"SQLWARNING"
+	{
+		$$ = &ast.ProcedureHandlerCondition{Tp: ast.ProcedureConditionSQLWarning}
+	}
+|	"NOT" "FOUND"
+	{
+		$$ = &ast.ProcedureHandlerCondition{Tp: ast.ProcedureConditionNotFound}
+	}
+|	"SQLSTATE" ValueOpt stringLit
+	{
+		if len($3) != 5 || strings.HasPrefix($3, "00") {
+			yylex.AppendError(ErrSpBadSQLState.GenWithStackByArgs($3))
+			return 1
+		}
+		$$ = &ast.ProcedureHandlerCondition{Tp: ast.ProcedureConditionSQLState, SQLState: $3}
+	}
+|	NUM
+	{
+		code := getUint64FromNUM($1)
+		if code == 0 || code > 65535 {
+			yylex.AppendError(ErrSyntax)
+			return 1
+		}
+		$$ = &ast.ProcedureHandlerCondition{Tp: ast.ProcedureConditionErrorCode, ErrorCode: uint16(code)}
+	}
+
+ValueOpt:
+	{}
+|	"VALUE"
+
+ProcedureIfStmt:
+	"IF" ProcedureIfBody "END" "IF"
+	{
+		$$ = $2
+	}
+
+ProcedureIfBody:
+	Expression "THEN" ProcedureStmtList1
+	{
+		$$ = &ast.ProcedureIfStmt{
+			Branches: []*ast.ProcedureIfBranch{{Cond: $1, Stmts: $3.([]ast.StmtNode)}},
+		}
+	}
+|	Expression "THEN" ProcedureStmtList1 "ELSEIF" ProcedureIfBody
+	{
+		x := $5.(*ast.ProcedureIfStmt)
+		x.Branches = append([]*ast.ProcedureIfBranch{{Cond: $1, Stmts: $3.([]ast.StmtNode)}}, x.Branches...)
+		$$ = x
+	}
+|	Expression "THEN" ProcedureStmtList1 "ELSE" ProcedureStmtList1
+	{
+		$$ = &ast.ProcedureIfStmt{
+			Branches: []*ast.ProcedureIfBranch{{Cond: $1, Stmts: $3.([]ast.StmtNode)}},
+			Else:     $5.([]ast.StmtNode),
+		}
+	}
+
+ProcedureCaseStmt:
+	"CASE" Expression ProcedureWhenClauseList ProcedureElseOpt "END" "CASE"
+	{
+		x := &ast.ProcedureCaseStmt{Value: $2, WhenClauses: $3.([]*ast.ProcedureWhenClause)}
+		if $4 != nil {
+			x.Else = $4.([]ast.StmtNode)
+		}
+		$$ = x
+	}
+|	"CASE" ProcedureWhenClauseList ProcedureElseOpt "END" "CASE"
+	{
+		x := &ast.ProcedureCaseStmt{WhenClauses: $2.([]*ast.ProcedureWhenClause)}
+		if $3 != nil {
+			x.Else = $3.([]ast.StmtNode)
+		}
+		$$ = x
+	}
+
+ProcedureWhenClauseList:
+	"WHEN" Expression "THEN" ProcedureStmtList1
+	{
+		$$ = []*ast.ProcedureWhenClause{{Expr: $2, Stmts: $4.([]ast.StmtNode)}}
+	}
+|	ProcedureWhenClauseList "WHEN" Expression "THEN" ProcedureStmtList1
+	{
+		$$ = append($1.([]*ast.ProcedureWhenClause), &ast.ProcedureWhenClause{Expr: $3, Stmts: $5.([]ast.StmtNode)})
+	}
+
+ProcedureElseOpt:
+	{
+		$$ = nil
+	}
+|	"ELSE" ProcedureStmtList1
+	{
+		$$ = $2
+	}
 
 /************************************************************************************
  *
@@ -7431,6 +8485,10 @@ SumExpr:
 			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
 		}
 	}
+|	"GROUPING" '(' ExpressionList ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: ast.AggFuncGrouping, Args: $3.([]ast.ExprNode)}
+	}
 |	builtinApproxCountDistinct '(' ExpressionList ')'
 	{
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: $3.([]ast.ExprNode), Distinct: false}
@@ -7487,9 +8545,13 @@ SumExpr:
 			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}}
 		}
 	}
-|	builtinCount '(' DistinctKwd ExpressionList ')'
+|	builtinCount '(' DistinctKwd ExpressionList ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true}
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true, Spec: *($6.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true}
+		}
 	}
 |	builtinCount '(' "ALL" Expression ')' OptWindowingClause
 	{
@@ -7521,7 +8583,11 @@ SumExpr:
 		args := $4.([]ast.ExprNode)
 		args = append(args, $6.(ast.ExprNode))
 		if $8 != nil {
-			$$ = &ast.WindowFuncExpr{F: $1, Args: args, Distinct: $3.(bool), Spec: *($8.(*ast.WindowSpec))}
+			windowFunc := &ast.WindowFuncExpr{F: $1, Args: args, Distinct: $3.(bool), Spec: *($8.(*ast.WindowSpec))}
+			if $5 != nil {
+				windowFunc.Order = $5.(*ast.OrderByClause)
+			}
+			$$ = windowFunc
 		} else {
 			agg := &ast.AggregateFuncExpr{F: $1, Args: args, Distinct: $3.(bool)}
 			if $5 != nil {
@@ -8211,7 +9277,7 @@ HelpStmt:
 	}
 
 SelectStmtBasic:
-	"SELECT" SelectStmtOpts SelectStmtFieldList HavingClause
+	"SELECT" SelectStmtOpts SelectStmtFieldList SelectStmtIntoOption HavingClause
 	{
 		st := &ast.SelectStmt{
 			SelectStmtOpts: $2.(*ast.SelectStmtOpts),
@@ -8223,7 +9289,15 @@ SelectStmtBasic:
 			st.TableHints = st.SelectStmtOpts.TableHints
 		}
 		if $4 != nil {
-			st.Having = $4.(*ast.HavingClause)
+			// The INTO clause is placed before FROM, e.g. SELECT a INTO @a FROM t.
+			st.SelectIntoOpt = $4.(*ast.SelectIntoOption)
+			lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
+			if lastField.Expr != nil && lastField.AsName.O == "" {
+				lastField.SetText(parser.lexer.client, parser.src[lastField.Offset:parser.endOffset(&yyS[yypt-1])])
+			}
+		}
+		if $5 != nil {
+			st.Having = $5.(*ast.HavingClause)
 		}
 		$$ = st
 	}
@@ -8233,7 +9307,7 @@ SelectStmtFromDualTable:
 	{
 		st := $1.(*ast.SelectStmt)
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
-		if lastField.Expr != nil && lastField.AsName.O == "" {
+		if lastField.Expr != nil && lastField.AsName.O == "" && st.SelectIntoOpt == nil {
 			lastEnd := yyS[yypt-1].offset - 1
 			lastField.SetText(parser.lexer.client, parser.src[lastField.Offset:lastEnd])
 		}
@@ -8248,7 +9322,7 @@ SelectStmtFromTable:
 		st := $1.(*ast.SelectStmt)
 		st.From = $3.(*ast.TableRefsClause)
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
-		if lastField.Expr != nil && lastField.AsName.O == "" {
+		if lastField.Expr != nil && lastField.AsName.O == "" && st.SelectIntoOpt == nil {
 			lastEnd := parser.endOffset(&yyS[yypt-5])
 			lastField.SetText(parser.lexer.client, parser.src[lastField.Offset:lastEnd])
 		}
@@ -8347,7 +9421,7 @@ SelectStmt:
 			st.LockInfo = $6.(*ast.SelectLockInfo)
 		}
 		lastField := st.Fields.Fields[len(st.Fields.Fields)-1]
-		if lastField.Expr != nil && lastField.AsName.O == "" {
+		if lastField.Expr != nil && lastField.AsName.O == "" && st.SelectIntoOpt == nil {
 			src := parser.src
 			var lastEnd int
 			if $2 != nil {
@@ -8383,6 +9457,10 @@ SelectStmt:
 			st.Limit = $5.(*ast.Limit)
 		}
 		if $7 != nil {
+			if st.SelectIntoOpt != nil {
+				yylex.AppendError(yylex.Errorf("Multiple INTO clauses in one query block."))
+				return 1
+			}
 			st.SelectIntoOpt = $7.(*ast.SelectIntoOption)
 		}
 		$$ = st
@@ -8403,6 +9481,10 @@ SelectStmt:
 			st.LockInfo = $5.(*ast.SelectLockInfo)
 		}
 		if $6 != nil {
+			if st.SelectIntoOpt != nil {
+				yylex.AppendError(yylex.Errorf("Multiple INTO clauses in one query block."))
+				return 1
+			}
 			st.SelectIntoOpt = $6.(*ast.SelectIntoOption)
 		}
 		$$ = st
@@ -8420,6 +9502,10 @@ SelectStmt:
 			st.Limit = $3.(*ast.Limit)
 		}
 		if $5 != nil {
+			if st.SelectIntoOpt != nil {
+				yylex.AppendError(yylex.Errorf("Multiple INTO clauses in one query block."))
+				return 1
+			}
 			st.SelectIntoOpt = $5.(*ast.SelectIntoOption)
 		}
 		$$ = st
@@ -8886,6 +9972,16 @@ TableFactor:
 		resultNode := $1.(*ast.SubqueryExpr).Query
 		$$ = &ast.TableSource{Source: resultNode, AsName: $2.(model.CIStr)}
 	}
+|	"LATERAL" SubSelect TableAsNameOpt
+	{
+		resultNode := $2.(*ast.SubqueryExpr).Query
+		$$ = &ast.TableSource{Source: resultNode, AsName: $3.(model.CIStr), Lateral: true}
+	}
+|	"JSON_TABLE" '(' Expression ',' stringLit "COLUMNS" '(' JSONTableColumnList ')' ')' TableAsName
+	{
+		jt := &ast.JSONTable{Expr: $3, Path: $5, Columns: $8.([]*ast.JSONTableColumn)}
+		$$ = &ast.TableSource{Source: jt, AsName: $11.(model.CIStr)}
+	}
 |	'(' TableRefs ')'
 	{
 		j := $2.(*ast.Join)
@@ -8893,6 +9989,74 @@ TableFactor:
 		$$ = $2
 	}
 
+JSONTableColumnList:
+	JSONTableColumn
+	{
+		$$ = []*ast.JSONTableColumn{$1.(*ast.JSONTableColumn)}
+	}
+|	JSONTableColumnList ',' JSONTableColumn
+	{
+		$$ = append($1.([]*ast.JSONTableColumn), $3.(*ast.JSONTableColumn))
+	}
+
+JSONTableColumn:
+	Identifier "FOR" "ORDINALITY"
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnOrdinality, Name: model.NewCIStr($1)}
+	}
+|	Identifier Type "PATH" stringLit JSONTableOnEmptyOnErrorOpt
+	{
+		col := $5.(*ast.JSONTableColumn)
+		col.Tp = ast.JSONTableColumnPath
+		col.Name = model.NewCIStr($1)
+		col.FieldType = $2.(*types.FieldType)
+		col.Path = $4
+		$$ = col
+	}
+|	Identifier Type "EXISTS" "PATH" stringLit
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnExists, Name: model.NewCIStr($1), FieldType: $2.(*types.FieldType), Path: $5}
+	}
+|	"NESTED" "PATH" stringLit "COLUMNS" '(' JSONTableColumnList ')'
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnNested, Path: $3, NestedColumns: $6.([]*ast.JSONTableColumn)}
+	}
+|	"NESTED" stringLit "COLUMNS" '(' JSONTableColumnList ')'
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnNested, Path: $2, NestedColumns: $5.([]*ast.JSONTableColumn)}
+	}
+
+JSONTableOnEmptyOnErrorOpt:
+	{
+		$$ = &ast.JSONTableColumn{}
+	}
+|	JSONTableOnResponse "ON" "EMPTY"
+	{
+		$$ = &ast.JSONTableColumn{OnEmpty: $1.(*ast.JSONTableOnResponse)}
+	}
+|	JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = &ast.JSONTableColumn{OnError: $1.(*ast.JSONTableOnResponse)}
+	}
+|	JSONTableOnResponse "ON" "EMPTY" JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = &ast.JSONTableColumn{OnEmpty: $1.(*ast.JSONTableOnResponse), OnError: $4.(*ast.JSONTableOnResponse)}
+	}
+
+JSONTableOnResponse:
+	"NULL"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}
+	}
+|	"ERROR"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseError}
+	}
+|	"DEFAULT" stringLit
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseDefault, Default: $2}
+	}
+
 PartitionNameListOpt:
 	/* empty */
 	{
@@ -9285,6 +10449,7 @@ SelectStmtGroup:
 |	GroupByClause
 
 SelectStmtIntoOption:
+	%prec lowerThanInto
 	{
 		$$ = nil
 	}
@@ -9303,6 +10468,33 @@ SelectStmtIntoOption:
 
 		$$ = x
 	}
+|	"INTO" SelectIntoVarList
+	{
+		$$ = &ast.SelectIntoOption{
+			Tp:        ast.SelectIntoVars,
+			Variables: $2.([]ast.ExprNode),
+		}
+	}
+
+SelectIntoVarList:
+	SelectIntoVar
+	{
+		$$ = []ast.ExprNode{$1.(ast.ExprNode)}
+	}
+|	SelectIntoVarList ',' SelectIntoVar
+	{
+		$$ = append($1.([]ast.ExprNode), $3.(ast.ExprNode))
+	}
+
+SelectIntoVar:
+	Identifier
+	{
+		$$ = &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: model.NewCIStr($1)}}
+	}
+|	UserVariable
+	{
+		$$ = $1
+	}
 
 // See https://dev.mysql.com/doc/refman/5.7/en/subqueries.html
 SubSelect:
@@ -10344,6 +11536,19 @@ AdminStmt:
 			Tp: ast.AdminResetTelemetryID,
 		}
 	}
+|	"ADMIN" "RESET" "CARDINALITY_CORRECTION"
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminResetCardinalityCorrection,
+		}
+	}
+|	"ADMIN" "RESET" "CARDINALITY_CORRECTION" TableNameList
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminResetCardinalityCorrection,
+			Tables: $4.([]*ast.TableName),
+		}
+	}
 |	"ADMIN" "FLUSH" StatementScope "PLAN_CACHE"
 	{
 		$$ = &ast.AdminStmt{
@@ -10439,6 +11644,34 @@ ShowStmt:
 			Table: $4.(*ast.TableName),
 		}
 	}
+|	"SHOW" "CREATE" "PROCEDURE" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowCreateProcedure,
+			Table: $4.(*ast.TableName),
+		}
+	}
+|	"SHOW" "CREATE" "FUNCTION" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowCreateFunction,
+			Table: $4.(*ast.TableName),
+		}
+	}
+|	"SHOW" "CREATE" "TRIGGER" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowCreateTrigger,
+			Table: $4.(*ast.TableName),
+		}
+	}
+|	"SHOW" "CREATE" "EVENT" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowCreateEvent,
+			Table: $4.(*ast.TableName),
+		}
+	}
 |	"SHOW" "CREATE" "DATABASE" IfNotExists DBName
 	{
 		$$ = &ast.ShowStmt{
@@ -10841,10 +12074,8 @@ ShowTargetFilterable:
 	{
 		// This statement is similar to SHOW PROCEDURE STATUS but for stored functions.
 		// See http://dev.mysql.com/doc/refman/5.7/en/show-function-status.html
-		// We do not support neither stored functions nor stored procedures.
-		// So we reuse show procedure status process logic.
 		$$ = &ast.ShowStmt{
-			Tp: ast.ShowProcedureStatus,
+			Tp: ast.ShowFunctionStatus,
 		}
 	}
 |	"EVENTS" ShowDatabaseNameOpt
@@ -11127,6 +12358,7 @@ Statement:
 	EmptyStmt
 |	AdminStmt
 |	AlterDatabaseStmt
+|	AlterEventStmt
 |	AlterTableStmt
 |	AlterUserStmt
 |	AlterImportStmt
@@ -11148,6 +12380,10 @@ Statement:
 |	CreateIndexStmt
 |	CreateTableStmt
 |	CreateViewStmt
+|	CreateMaterializedViewStmt
+|	CreateRoutineStmt
+|	CreateTriggerStmt
+|	CreateEventStmt
 |	CreateUserStmt
 |	CreateRoleStmt
 |	CreateBindingStmt
@@ -11162,6 +12398,10 @@ Statement:
 |	DropPolicyStmt
 |	DropSequenceStmt
 |	DropViewStmt
+|	DropMaterializedViewStmt
+|	DropRoutineStmt
+|	DropTriggerStmt
+|	DropEventStmt
 |	DropUserStmt
 |	DropRoleStmt
 |	DropStatisticsStmt
@@ -11181,11 +12421,13 @@ Statement:
 |	PlanReplayerStmt
 |	PreparedStmt
 |	PurgeImportStmt
+|	RecommendIndexStmt
 |	RollbackStmt
 |	RenameTableStmt
 |	RenameUserStmt
 |	ReplaceIntoStmt
 |	RecoverTableStmt
+|	RefreshMaterializedViewStmt
 |	ReleaseSavepointStmt
 |	ResumeImportStmt
 |	RevokeStmt
@@ -11652,6 +12894,7 @@ Type:
 	NumericType
 |	StringType
 |	DateAndTimeType
+|	SpatialType
 
 NumericType:
 	IntegerType OptFieldLen FieldOpts
@@ -12076,6 +13319,44 @@ BlobType:
 		$$ = tp
 	}
 
+SpatialType:
+	"GEOMETRY"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeGeometry)
+	}
+|	"POINT"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypePoint)
+	}
+|	"LINESTRING"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeLineString)
+	}
+|	"POLYGON"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypePolygon)
+	}
+|	"MULTIPOINT"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeMultiPoint)
+	}
+|	"MULTILINESTRING"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeMultiLineString)
+	}
+|	"MULTIPOLYGON"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeMultiPolygon)
+	}
+|	"GEOMETRYCOLLECTION"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeGeometryCollection)
+	}
+|	"GEOMCOLLECTION"
+	{
+		$$ = newSpatialFieldType(types.GeometryTypeGeometryCollection)
+	}
+
 TextType:
 	"TINYTEXT"
 	{
@@ -12182,6 +13463,7 @@ FieldLen:
 	}
 
 OptFieldLen:
+	%prec lowerThanParenthese
 	{
 		$$ = types.UnspecifiedLength
 	}
@@ -12211,6 +13493,7 @@ FieldOpts:
 	}
 
 FloatOpt:
+	%prec lowerThanParenthese
 	{
 		$$ = &ast.FloatOpt{Flen: types.UnspecifiedLength, Decimal: types.UnspecifiedLength}
 	}
@@ -12236,6 +13519,7 @@ OptBinMod:
 	}
 
 OptBinary:
+	%prec lowerThanParenthese
 	{
 		$$ = &ast.OptBinary{
 			IsBinary: false,
@@ -13924,6 +15208,41 @@ IndexAdviseStmt:
 		$$ = x
 	}
 
+/*******************************************************************
+ *
+ *  Recommend Index Statement
+ *
+ *  Example:
+ *  RECOMMEND INDEX
+ *  	[FOR 'sql']
+ *  	[MAX_MINUTES number]
+ *  	[MAX_IDXNUM
+ *  		[PER_TABLE number]
+ *  		[PER_DB number]
+ *  	]
+ *******************************************************************/
+RecommendIndexStmt:
+	"RECOMMEND" "INDEX" RecommendIndexForOpt MaxMinutesOpt MaxIndexNumOpt
+	{
+		x := &ast.RecommendIndexStmt{
+			SQL:        $3,
+			MaxMinutes: $4.(uint64),
+		}
+		if $5 != nil {
+			x.MaxIndexNum = $5.(*ast.MaxIndexNumClause)
+		}
+		$$ = x
+	}
+
+RecommendIndexForOpt:
+	{
+		$$ = ""
+	}
+|	"FOR" stringLit
+	{
+		$$ = $2
+	}
+
 MaxMinutesOpt:
 	{
 		$$ = uint64(ast.UnspecifiedSize)
@@ -39,12 +39,12 @@ func TestSimple(t *testing.T) {
 	reservedKws := []string{
 		"add", "all", "alter", "analyze", "and", "as", "asc", "between", "bigint",
 		"binary", "blob", "both", "by", "call", "cascade", "case", "change", "character", "check", "collate",
-		"column", "constraint", "convert", "create", "cross", "current_date", "current_time",
+		"column", "constraint", "convert", "create", "cross", "cube", "current_date", "current_time",
 		"current_timestamp", "current_user", "database", "databases", "day_hour", "day_microsecond",
 		"day_minute", "day_second", "decimal", "default", "delete", "desc", "describe",
 		"distinct", "distinctRow", "div", "double", "drop", "dual", "else", "enclosed", "escaped",
 		"exists", "explain", "false", "float", "fetch", "for", "force", "foreign", "from",
-		"fulltext", "grant", "group", "having", "hour_microsecond", "hour_minute",
+		"fulltext", "grant", "group", "grouping", "having", "hour_microsecond", "hour_minute",
 		"hour_second", "if", "ignore", "in", "index", "infile", "inner", "insert", "int", "into", "integer",
 		"interval", "is", "join", "key", "keys", "kill", "leading", "left", "like", "limit", "lines", "load",
 		"localtime", "localtimestamp", "lock", "longblob", "longtext", "mediumblob", "maxvalue", "mediumint", "mediumtext",
@@ -60,7 +60,7 @@ func TestSimple(t *testing.T) {
 		"delayed", "high_priority", "low_priority",
 		"cumeDist", "denseRank", "firstValue", "lag", "lastValue", "lead", "nthValue", "ntile",
 		"over", "percentRank", "rank", "row", "rows", "rowNumber", "window", "linear",
-		"match", "until", "placement", "tablesample", "attributes",
+		"match", "until", "placement", "tablesample", "attributes", "json_table",
 		// TODO: support the following keywords
 		// "with",
 	}
@@ -821,6 +821,8 @@ func TestDMLStmt(t *testing.T) {
 		{"admin reload bindings", true, "ADMIN RELOAD BINDINGS"},
 		{"admin show telemetry", true, "ADMIN SHOW TELEMETRY"},
 		{"admin reset telemetry_id", true, "ADMIN RESET TELEMETRY_ID"},
+		{"admin reset cardinality_correction", true, "ADMIN RESET CARDINALITY_CORRECTION"},
+		{"admin reset cardinality_correction t1, test.t2", true, "ADMIN RESET CARDINALITY_CORRECTION `t1`, `test`.`t2`"},
 		// This case would be removed once TiDB PR to remove ADMIN RELOAD STATISTICS is merged.
 		{"admin reload statistics", true, "ADMIN RELOAD STATS_EXTENDED"},
 		{"admin reload stats_extended", true, "ADMIN RELOAD STATS_EXTENDED"},
@@ -1081,10 +1083,8 @@ func TestDBAStmt(t *testing.T) {
 		{`SHOW FIELDS FROM City;`, true, "SHOW COLUMNS IN `City`"},
 		{`SHOW TRIGGERS LIKE 't'`, true, "SHOW TRIGGERS LIKE _UTF8MB4't'"},
 		{`SHOW DATABASES LIKE 'test2'`, true, "SHOW DATABASES LIKE _UTF8MB4'test2'"},
-		// PROCEDURE and FUNCTION are currently not supported.
-		// And FUNCTION reuse show procedure status process logic.
 		{`SHOW PROCEDURE STATUS WHERE Db='test'`, true, "SHOW PROCEDURE STATUS WHERE `Db`=_UTF8MB4'test'"},
-		{`SHOW FUNCTION STATUS WHERE Db='test'`, true, "SHOW PROCEDURE STATUS WHERE `Db`=_UTF8MB4'test'"},
+		{`SHOW FUNCTION STATUS WHERE Db='test'`, true, "SHOW FUNCTION STATUS WHERE `Db`=_UTF8MB4'test'"},
 		{`SHOW INDEX FROM t;`, true, "SHOW INDEX IN `t`"},
 		{`SHOW KEYS FROM t;`, true, "SHOW INDEX IN `t`"},
 		{`SHOW INDEX IN t;`, true, "SHOW INDEX IN `t`"},
@@ -2100,6 +2100,21 @@ func TestBuiltin(t *testing.T) {
 		{`select json_objectagg(c1, all c2) from t group by c1`, true, "SELECT JSON_OBJECTAGG(`c1`, `c2`) FROM `t` GROUP BY `c1`"},
 		{`select json_objectagg(all c1, all c2) from t group by c1`, true, "SELECT JSON_OBJECTAGG(`c1`, `c2`) FROM `t` GROUP BY `c1`"},
 
+		// for grouping sets and grouping function
+		{`select a, b, sum(c) from t group by a, b with rollup`, true, "SELECT `a`,`b`,SUM(`c`) FROM `t` GROUP BY `a`,`b` WITH ROLLUP"},
+		{`select a, grouping(a), grouping(a, b) from t group by a, b with rollup having grouping(b) = 1 order by grouping(a)`, true, "SELECT `a`,GROUPING(`a`),GROUPING(`a`, `b`) FROM `t` GROUP BY `a`,`b` WITH ROLLUP HAVING GROUPING(`b`)=1 ORDER BY GROUPING(`a`)"},
+		{`select a from t group by a with rollup limit 1`, true, "SELECT `a` FROM `t` GROUP BY `a` WITH ROLLUP LIMIT 1"},
+		{`select a from t group by a with rollup union select 1`, true, "SELECT `a` FROM `t` GROUP BY `a` WITH ROLLUP UNION SELECT 1"},
+		{`select a from t group by a with`, false, ""},
+		{`select a, b from t group by cube (a, b)`, true, "SELECT `a`,`b` FROM `t` GROUP BY CUBE (`a`,`b`)"},
+		{`select a, b from t group by cube ()`, false, ""},
+		{`select a, b from t group by grouping sets ((a, b), (a), ())`, true, "SELECT `a`,`b` FROM `t` GROUP BY GROUPING SETS ((`a`,`b`),(`a`),())"},
+		{`select a, b from t group by grouping sets ((a + 1), (b))`, true, "SELECT `a`,`b` FROM `t` GROUP BY GROUPING SETS ((`a`+1),(`b`))"},
+		{`select a, b from t group by grouping sets (a, b)`, false, ""},
+		{`select a from t group by grouping(a)`, true, "SELECT `a` FROM `t` GROUP BY GROUPING(`a`)"},
+		{`select grouping() from t`, false, ""},
+		{`select rollup, sets from rollup.sets`, true, "SELECT `rollup`,`sets` FROM `rollup`.`sets`"},
+
 		// for encryption and compression functions
 		{`select AES_ENCRYPT('text',UNHEX('F3229A0B371ED2D9441B830D21A390C3'))`, true, "SELECT AES_ENCRYPT(_UTF8MB4'text', UNHEX(_UTF8MB4'F3229A0B371ED2D9441B830D21A390C3'))"},
 		{`select AES_DECRYPT(@crypt_str,@key_str)`, true, "SELECT AES_DECRYPT(@`crypt_str`, @`key_str`)"},
@@ -3103,6 +3118,8 @@ func TestDDL(t *testing.T) {
 		{"CREATE UNIQUE INDEX ident ON d_n.t_n ( ident , ident ASC ) TYPE BTREE", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING BTREE"},
 		{"CREATE UNIQUE INDEX ident ON d_n.t_n ( ident , ident ASC ) TYPE HASH", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING HASH"},
 		{"CREATE UNIQUE INDEX ident ON d_n.t_n ( ident , ident ASC ) TYPE RTREE", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING RTREE"},
+		{"CREATE INDEX idx ON t ( a , b ) TYPE HYPO", true, "CREATE INDEX `idx` ON `t` (`a`, `b`) USING HYPO"},
+		{"CREATE INDEX idx ON t ( a ) USING HYPO COMMENT 'what if'", true, "CREATE INDEX `idx` ON `t` (`a`) USING HYPO COMMENT 'what if'"},
 		{"CREATE UNIQUE INDEX ident TYPE BTREE ON d_n.t_n ( ident , ident ASC )", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING BTREE"},
 		{"CREATE UNIQUE INDEX ident USING BTREE ON d_n.t_n ( ident , ident ASC )", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING BTREE"},
 		{"CREATE SPATIAL INDEX idx ON t (a)", true, "CREATE SPATIAL INDEX `idx` ON `t` (`a`)"},
@@ -3492,6 +3509,16 @@ func TestDDL(t *testing.T) {
 		{"create table t (a bigint auto_random(3) primary key, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT AUTO_RANDOM(3) PRIMARY KEY,`b` VARCHAR(255))"},
 		{"create table t (a bigint auto_random primary key, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT AUTO_RANDOM PRIMARY KEY,`b` VARCHAR(255))"},
 		{"create table t (a bigint primary key auto_random(4), b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT PRIMARY KEY AUTO_RANDOM(4),`b` VARCHAR(255))"},
+
+		// for spatial types
+		{"create table t (a geometry, b point, c linestring, d polygon)", true, "CREATE TABLE `t` (`a` GEOMETRY,`b` POINT,`c` LINESTRING,`d` POLYGON)"},
+		{"create table t (a multipoint, b multilinestring, c multipolygon, d geometrycollection, e geomcollection)", true, "CREATE TABLE `t` (`a` MULTIPOINT,`b` MULTILINESTRING,`c` MULTIPOLYGON,`d` GEOMCOLLECTION,`e` GEOMCOLLECTION)"},
+		{"create table t (a point not null srid 4326, b geometry srid 0)", true, "CREATE TABLE `t` (`a` POINT NOT NULL SRID 4326,`b` GEOMETRY SRID 0)"},
+		{"create table t (a point srid 4294967296)", false, ""},
+		{"create table t (a point srid)", false, ""},
+		{"create table t (point int, srid int, polygon point)", true, "CREATE TABLE `t` (`point` INT,`srid` INT,`polygon` POINT)"},
+		{"alter table t add column a point srid 4326", true, "ALTER TABLE `t` ADD COLUMN `a` POINT SRID 4326"},
+		{"select point, geometry from t", true, "SELECT `point`,`geometry` FROM `t`"},
 		{"create table t (a bigint primary key auto_random(3) primary key unique, b varchar(255))", true, "CREATE TABLE `t` (`a` BIGINT PRIMARY KEY AUTO_RANDOM(3) PRIMARY KEY UNIQUE KEY,`b` VARCHAR(255))"},
 
 		// for auto_id_cache
@@ -4549,6 +4576,14 @@ func TestSubquery(t *testing.T) {
 		{"select exists((select 1));", true, "SELECT EXISTS (SELECT 1)"},
 		{"select * from ((SELECT 1 a,3 b) UNION (SELECT 2,1) ORDER BY (SELECT 2)) t order by a,b", true, "SELECT * FROM ((SELECT 1 AS `a`,3 AS `b`) UNION (SELECT 2,1) ORDER BY (SELECT 2)) AS `t` ORDER BY `a`,`b`"},
 		{"select (select * from t1 where a != t.a union all (select * from t2 where a != t.a) order by a limit 1) from t1 t", true, "SELECT (SELECT * FROM `t1` WHERE `a`!=`t`.`a` UNION ALL (SELECT * FROM `t2` WHERE `a`!=`t`.`a`) ORDER BY `a` LIMIT 1) FROM `t1` AS `t`"},
+
+		// for lateral derived table
+		{"select * from t1, lateral (select * from t2 where t2.a = t1.a) as dt", true, "SELECT * FROM (`t1`) JOIN LATERAL (SELECT * FROM `t2` WHERE `t2`.`a`=`t1`.`a`) AS `dt`"},
+		{"select * from t1 join lateral (select b from t2 where t2.a = t1.a order by b limit 3) dt on true", true, "SELECT * FROM `t1` JOIN LATERAL (SELECT `b` FROM `t2` WHERE `t2`.`a`=`t1`.`a` ORDER BY `b` LIMIT 3) AS `dt` ON TRUE"},
+		{"select * from t1 left join lateral (select count(*) c from t2 where t2.a = t1.a) dt on dt.c > 0", true, "SELECT * FROM `t1` LEFT JOIN LATERAL (SELECT COUNT(1) AS `c` FROM `t2` WHERE `t2`.`a`=`t1`.`a`) AS `dt` ON `dt`.`c`>0"},
+		{"select * from t1, lateral (select a from t2 union select a from t3 where t3.a = t1.a) dt", true, "SELECT * FROM (`t1`) JOIN LATERAL (SELECT `a` FROM `t2` UNION SELECT `a` FROM `t3` WHERE `t3`.`a`=`t1`.`a`) AS `dt`"},
+		{"select * from t1, lateral t2", false, ""},
+		{"select 1 as lateral", false, ""},
 	}
 	RunTest(t, table, false)
 
@@ -5440,6 +5475,22 @@ func TestTableSample(t *testing.T) {
 	}
 }
 
+func TestJSONTable(t *testing.T) {
+	table := []testCase{
+		{"select * from json_table('[1, 2]', '$[*]' columns (a int path '$')) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1, 2]', '$[*]' COLUMNS (`a` INT PATH '$')) AS `jt`"},
+		{"select * from t, json_table(t.doc, '$.items[*]' columns (id for ordinality, name varchar(10) path '$.name', has_price int exists path '$.price')) jt", true, "SELECT * FROM (`t`) JOIN JSON_TABLE(`t`.`doc`, '$.items[*]' COLUMNS (`id` FOR ORDINALITY, `name` VARCHAR(10) PATH '$.name', `has_price` INT EXISTS PATH '$.price')) AS `jt`"},
+		{"select * from json_table('{}', '$' columns (a int path '$.a' default '0' on empty error on error, b json path '$.b' null on error, c int path '$.c' error on empty)) jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'{}', '$' COLUMNS (`a` INT PATH '$.a' DEFAULT '0' ON EMPTY ERROR ON ERROR, `b` JSON PATH '$.b' NULL ON ERROR, `c` INT PATH '$.c' ERROR ON EMPTY)) AS `jt`"},
+		{"select * from json_table('[]', '$[*]' columns (a int path '$.a', nested path '$.b[*]' columns (b int path '$', nested '$.c' columns (c for ordinality)), nested path '$.d' columns (d int path '$'))) jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[]', '$[*]' COLUMNS (`a` INT PATH '$.a', NESTED PATH '$.b[*]' COLUMNS (`b` INT PATH '$', NESTED PATH '$.c' COLUMNS (`c` FOR ORDINALITY)), NESTED PATH '$.d' COLUMNS (`d` INT PATH '$'))) AS `jt`"},
+		{"select * from t left join json_table(t.doc, '$[*]' columns (nested int path '$.nested', path int path '$.path', ordinality for ordinality, empty int path '$.empty')) as jt on true", true, "SELECT * FROM `t` LEFT JOIN JSON_TABLE(`t`.`doc`, '$[*]' COLUMNS (`nested` INT PATH '$.nested', `path` INT PATH '$.path', `ordinality` FOR ORDINALITY, `empty` INT PATH '$.empty')) AS `jt` ON TRUE"},
+		{"select * from json_table('[]', '$[*]' columns (a int path '$')) ", false, ""},
+		{"select * from json_table('[]', '$[*]' columns ()) jt", false, ""},
+		{"select * from json_table('[]', '$[*]' columns (a int)) jt", false, ""},
+		{"select * from json_table('[]', '$[*]' columns (a int path '$' error on error null on empty)) jt", false, ""},
+		{"select * from json_table('[]', '$[*]' columns (a int exists path '$' null on empty)) jt", false, ""},
+	}
+	RunTest(t, table, false)
+}
+
 func TestGeneratedColumn(t *testing.T) {
 	tests := []struct {
 		input string
@@ -5803,6 +5854,10 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT MAX(profit) OVER() AS country_profit FROM sales;`, true, "SELECT MAX(`profit`) OVER () AS `country_profit` FROM `sales`"},
 		{`SELECT MIN(profit) OVER() AS country_profit FROM sales;`, true, "SELECT MIN(`profit`) OVER () AS `country_profit` FROM `sales`"},
 		{`SELECT SUM(profit) OVER() AS country_profit FROM sales;`, true, "SELECT SUM(`profit`) OVER () AS `country_profit` FROM `sales`"},
+		{`SELECT SUM(DISTINCT profit) OVER() AS country_profit FROM sales;`, true, "SELECT SUM(DISTINCT `profit`) OVER () AS `country_profit` FROM `sales`"},
+		{`SELECT COUNT(DISTINCT country, product) OVER() FROM sales;`, true, "SELECT COUNT(DISTINCT `country`, `product`) OVER () FROM `sales`"},
+		{`SELECT GROUP_CONCAT(product) OVER() FROM sales;`, true, "SELECT GROUP_CONCAT(`product` SEPARATOR ',') OVER () FROM `sales`"},
+		{`SELECT GROUP_CONCAT(DISTINCT product, year ORDER BY year DESC SEPARATOR ';') OVER(PARTITION BY country) FROM sales;`, true, "SELECT GROUP_CONCAT(DISTINCT `product`, `year` ORDER BY `year` DESC SEPARATOR ';') OVER (PARTITION BY `country`) FROM `sales`"},
 		{`SELECT ROW_NUMBER() OVER(PARTITION BY country) AS row_num1 FROM sales;`, true, "SELECT ROW_NUMBER() OVER (PARTITION BY `country`) AS `row_num1` FROM `sales`"},
 		{`SELECT ROW_NUMBER() OVER(PARTITION BY country, d ORDER BY year, product) AS row_num2 FROM sales;`, true, "SELECT ROW_NUMBER() OVER (PARTITION BY `country`, `d` ORDER BY `year`,`product`) AS `row_num2` FROM `sales`"},
 
@@ -5815,6 +5870,7 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT AVG(val) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL '2:30' MINUTE_SECOND FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL _UTF8MB4'2:30' MINUTE_SECOND FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
+		{`SELECT AVG(val) OVER (ORDER BY time GROUPS BETWEEN 1 PRECEDING AND 2 FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (ORDER BY `time` GROUPS BETWEEN 1 PRECEDING AND 2 FOLLOWING) FROM `t`"},
 
 		// For named windows.
 		// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
@@ -5901,6 +5957,11 @@ func TestFieldText(t *testing.T) {
 	tmp := stmts[0].(*ast.SelectStmt)
 	require.Equal(t, "a", tmp.Fields.Fields[0].Text())
 
+	stmts, _, err = p.Parse("select a, b + 1  into @a, @b from t", "", "")
+	require.NoError(t, err)
+	tmp = stmts[0].(*ast.SelectStmt)
+	require.Equal(t, "b + 1", tmp.Fields.Fields[1].Text())
+
 	sqls := []string{
 		"trace select a from t",
 		"trace format = 'row' select a from t",
@@ -6165,6 +6226,13 @@ func (checker *nodeTextCleaner) Enter(in ast.Node) (out ast.Node, skipChildren b
 			var tmpCleaner nodeTextCleaner
 			node.Partition.Expr.Accept(&tmpCleaner)
 		}
+	case *ast.SelectStmt:
+		if node.SelectIntoOpt != nil {
+			for _, v := range node.SelectIntoOpt.Variables {
+				var tmpCleaner nodeTextCleaner
+				v.Accept(&tmpCleaner)
+			}
+		}
 	case *ast.DeleteStmt:
 		for _, tableHint := range node.TableHints {
 			tableHint.HintName.O = ""
@@ -6279,6 +6347,133 @@ func TestIndexAdviseStmt(t *testing.T) {
 	RunTest(t, table, false)
 }
 
+func TestRecommendIndexStmt(t *testing.T) {
+	table := []testCase{
+		{"RECOMMEND INDEX", true, "RECOMMEND INDEX"},
+		{"RECOMMEND INDEX FOR 'select * from t where a = 1'", true, "RECOMMEND INDEX FOR 'select * from t where a = 1'"},
+		{"RECOMMEND INDEX MAX_MINUTES 3", true, "RECOMMEND INDEX MAX_MINUTES 3"},
+		{"RECOMMEND INDEX MAX_IDXNUM PER_TABLE 2 PER_DB 4", true, "RECOMMEND INDEX MAX_IDXNUM PER_TABLE 2 PER_DB 4"},
+		{"RECOMMEND INDEX FOR 'select 1' MAX_MINUTES 3 MAX_IDXNUM PER_DB 4", true, "RECOMMEND INDEX FOR 'select 1' MAX_MINUTES 3 MAX_IDXNUM PER_DB 4"},
+		{"RECOMMEND INDEX FOR select 1", false, ""},
+		{"RECOMMEND INDEX MAX_MINUTES -1", false, ""},
+		{"create table recommend (a int)", true, "CREATE TABLE `recommend` (`a` INT)"},
+	}
+	RunTest(t, table, false)
+}
+
+func TestMaterializedView(t *testing.T) {
+	table := []testCase{
+		{"create materialized view mv as select a, count(*) from t group by a", true, "CREATE MATERIALIZED VIEW `mv` AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`"},
+		{"create materialized view if not exists test.mv (a, cnt) as select a, count(*) from t group by a", true, "CREATE MATERIALIZED VIEW IF NOT EXISTS `test`.`mv` (`a`,`cnt`) AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`"},
+		{"create materialized view mv as select * from t1 union select * from t2", true, "CREATE MATERIALIZED VIEW `mv` AS SELECT * FROM `t1` UNION SELECT * FROM `t2`"},
+		{"create or replace materialized view mv as select * from t", false, ""},
+		{"drop materialized view mv", true, "DROP MATERIALIZED VIEW `mv`"},
+		{"drop materialized view if exists test.mv", true, "DROP MATERIALIZED VIEW IF EXISTS `test`.`mv`"},
+		{"drop materialized view mv1, mv2", false, ""},
+		{"refresh materialized view mv", true, "REFRESH MATERIALIZED VIEW `mv`"},
+		{"refresh materialized view test.mv complete", true, "REFRESH MATERIALIZED VIEW `test`.`mv` COMPLETE"},
+		{"refresh materialized view mv fast", true, "REFRESH MATERIALIZED VIEW `mv` FAST"},
+		{"refresh materialized view mv force", false, ""},
+		{"create table materialized (refresh int, complete int)", true, "CREATE TABLE `materialized` (`refresh` INT,`complete` INT)"},
+	}
+	RunTest(t, table, false)
+}
+
+func TestStoredRoutine(t *testing.T) {
+	table := []testCase{
+		{"create procedure p() select 1", true, "CREATE PROCEDURE `p`() SELECT 1"},
+		{"create procedure if not exists test.p(in a int, out b varchar(10), inout c int) comment 'x' deterministic reads sql data sql security invoker begin declare x int default 1; declare y, z int; select a into b; end", true, "CREATE PROCEDURE IF NOT EXISTS `test`.`p`(`a` INT,OUT `b` VARCHAR(10),INOUT `c` INT) COMMENT 'x' DETERMINISTIC READS SQL DATA SQL SECURITY INVOKER BEGIN DECLARE `x` INT DEFAULT 1; DECLARE `y`,`z` INT; SELECT `a` INTO `b`; END"},
+		{"create definer = 'root'@'%' function f(a int) returns int deterministic return a + 1", true, "CREATE DEFINER = `root`@`%` FUNCTION `f`(`a` INT) RETURNS INT DETERMINISTIC RETURN `a`+1"},
+		{"create function f(a int) returns int no sql language sql not deterministic contains sql modifies sql data return a", true, "CREATE FUNCTION `f`(`a` INT) RETURNS INT NO SQL LANGUAGE SQL NOT DETERMINISTIC CONTAINS SQL MODIFIES SQL DATA RETURN `a`"},
+		{"create function f(out a int) returns int return 1", false, ""},
+		{"create function f() return 1", false, ""},
+		{"create or replace procedure p() select 1", false, ""},
+		{"create algorithm = merge procedure p() select 1", false, ""},
+		{"create procedure p() lbl: begin declare done int default 0; declare cur cursor for select a from t; declare continue handler for not found set done = 1; declare exit handler for sqlstate value '42S02', sqlwarning, sqlexception, 1062 begin end; open cur; l2: loop fetch cur into done; if done then leave l2; elseif done = 2 then iterate l2; else select 1; end if; end loop l2; close cur; end lbl", true, "CREATE PROCEDURE `p`() `lbl`: BEGIN DECLARE `done` INT DEFAULT 0; DECLARE `cur` CURSOR FOR SELECT `a` FROM `t`; DECLARE CONTINUE HANDLER FOR NOT FOUND SET @@SESSION.`done`=1; DECLARE EXIT HANDLER FOR SQLSTATE '42S02',SQLWARNING,SQLEXCEPTION,1062 BEGIN END; OPEN `cur`; `l2`: LOOP FETCH `cur` INTO `done`; IF `done` THEN LEAVE `l2`; ELSEIF `done`=2 THEN ITERATE `l2`; ELSE SELECT 1; END IF; END LOOP `l2`; CLOSE `cur`; END `lbl`"},
+		{"create procedure p() begin while a < 10 do set @a = a + 1; end while; repeat select 1; until a = 0 end repeat; case a when 1 then select 1; else select 2; end case; case when a = 1 then select 1; end case; end", true, "CREATE PROCEDURE `p`() BEGIN WHILE `a`<10 DO SET @`a`=`a`+1; END WHILE; REPEAT SELECT 1; UNTIL `a`=0 END REPEAT; CASE `a` WHEN 1 THEN SELECT 1; ELSE SELECT 2; END CASE; CASE WHEN `a`=1 THEN SELECT 1; END CASE; END"},
+		{"create procedure p() begin start transaction; fetch next from c into a, b; insert into t values (1); commit; end", true, "CREATE PROCEDURE `p`() BEGIN START TRANSACTION; FETCH `c` INTO `a`,`b`; INSERT INTO `t` VALUES (1); COMMIT; END"},
+		{"create procedure p() begin select 1; declare a int; end", false, ""},
+		{"create procedure p() begin declare a int; begin end; end", true, "CREATE PROCEDURE `p`() BEGIN DECLARE `a` INT; BEGIN END; END"},
+		{"create procedure p() begin declare exit handler for 0 begin end; end", false, ""},
+		{"drop procedure p", true, "DROP PROCEDURE `p`"},
+		{"drop procedure if exists test.p", true, "DROP PROCEDURE IF EXISTS `test`.`p`"},
+		{"drop function f", true, "DROP FUNCTION `f`"},
+		{"drop function if exists test.f", true, "DROP FUNCTION IF EXISTS `test`.`f`"},
+		{"show create procedure p", true, "SHOW CREATE PROCEDURE `p`"},
+		{"show create function test.f", true, "SHOW CREATE FUNCTION `test`.`f`"},
+		{"create table t (found int, handler int, leave int, loop int, cursor int, returns int, until int, sqlstate int)", true, "CREATE TABLE `t` (`found` INT,`handler` INT,`leave` INT,`loop` INT,`cursor` INT,`returns` INT,`until` INT,`sqlstate` INT)"},
+		{"select a, b into @a, @b from t", true, "SELECT `a`,`b` FROM `t` INTO @`a`,@`b`"},
+		{"select a into @a, x from t where a = 1", true, "SELECT `a` FROM `t` WHERE `a`=1 INTO @`a`,`x`"},
+		{"select 1 into @a", true, "SELECT 1 INTO @`a`"},
+		{"select a from t limit 1 into @a", true, "SELECT `a` FROM `t` LIMIT 1 INTO @`a`"},
+		{"select a into @a from t into @b", false, ""},
+	}
+	RunTest(t, table, false)
+
+	errTable := []testErrMsgCase{
+		{"create procedure p() l1: begin end l2", parser.ErrSpLabelMismatch},
+		{"create procedure p() begin declare exit handler for sqlstate '00000' begin end; end", parser.ErrSpBadSQLState},
+		{"create procedure p() begin declare exit handler for sqlstate '4200' begin end; end", parser.ErrSpBadSQLState},
+	}
+	RunErrMsgTest(t, errTable)
+}
+
+func TestLoadableFunction(t *testing.T) {
+	table := []testCase{
+		{"create function metaphon returns string soname 'udf_example'", true, "CREATE FUNCTION `metaphon` RETURNS STRING SONAME 'udf_example'"},
+		{"create function if not exists myfunc_int returns integer soname 'udf_example'", true, "CREATE FUNCTION IF NOT EXISTS `myfunc_int` RETURNS INTEGER SONAME 'udf_example'"},
+		{"create function myfunc_double returns real soname \"udf_example.so\"", true, "CREATE FUNCTION `myfunc_double` RETURNS REAL SONAME 'udf_example.so'"},
+		{"create function f returns decimal soname 'udf_example'", true, "CREATE FUNCTION `f` RETURNS DECIMAL SONAME 'udf_example'"},
+		{"create function test.f returns integer soname 'udf_example'", false, ""},
+		{"create function f returns int soname 'udf_example'", false, ""},
+		{"create function f returns string", false, ""},
+		{"create or replace function f returns string soname 'udf_example'", false, ""},
+		{"create definer = 'root'@'%' function f returns string soname 'udf_example'", false, ""},
+		{"create table t (soname int, string int)", true, "CREATE TABLE `t` (`soname` INT,`string` INT)"},
+	}
+	RunTest(t, table, false)
+}
+
+func TestTrigger(t *testing.T) {
+	table := []testCase{
+		{"create trigger tr before insert on t for each row set new.a = new.a + 1", true, "CREATE TRIGGER `tr` BEFORE INSERT ON `t` FOR EACH ROW SET @@SESSION.`new.a`=`new`.`a`+1"},
+		{"create definer = 'root'@'%' trigger if not exists test.tr after update on test.t for each row follows tr0 begin insert into log values (old.a, new.a); end", true, "CREATE DEFINER = `root`@`%` TRIGGER IF NOT EXISTS `test`.`tr` AFTER UPDATE ON `test`.`t` FOR EACH ROW FOLLOWS `tr0` BEGIN INSERT INTO `log` VALUES (`old`.`a`,`new`.`a`); END"},
+		{"create trigger tr before delete on t for each row precedes tr0 delete from log where id = old.id", true, "CREATE TRIGGER `tr` BEFORE DELETE ON `t` FOR EACH ROW PRECEDES `tr0` DELETE FROM `log` WHERE `id`=`old`.`id`"},
+		{"create trigger tr after insert on t for each row begin if new.a > 0 then set @x = 1; end if; end", true, "CREATE TRIGGER `tr` AFTER INSERT ON `t` FOR EACH ROW BEGIN IF `new`.`a`>0 THEN SET @`x`=1; END IF; END"},
+		{"create trigger tr instead of insert on t for each row set @a = 1", false, ""},
+		{"create trigger tr before insert on t set @a = 1", false, ""},
+		{"create or replace trigger tr before insert on t for each row set @a = 1", false, ""},
+		{"drop trigger tr", true, "DROP TRIGGER `tr`"},
+		{"drop trigger if exists test.tr", true, "DROP TRIGGER IF EXISTS `test`.`tr`"},
+		{"show create trigger test.tr", true, "SHOW CREATE TRIGGER `test`.`tr`"},
+		{"show triggers from test like 't%'", true, "SHOW TRIGGERS IN `test` LIKE _UTF8MB4't%'"},
+		{"create table t (before int, each int, follows int, precedes int)", true, "CREATE TABLE `t` (`before` INT,`each` INT,`follows` INT,`precedes` INT)"},
+	}
+	RunTest(t, table, false)
+}
+
+func TestEvent(t *testing.T) {
+	table := []testCase{
+		{"create event e on schedule at '2022-01-01 00:00:00' + interval 1 hour do insert into t values (1)", true, "CREATE EVENT `e` ON SCHEDULE AT DATE_ADD(_UTF8MB4'2022-01-01 00:00:00', INTERVAL 1 HOUR) DO INSERT INTO `t` VALUES (1)"},
+		{"create definer = 'root'@'%' event if not exists test.e on schedule every 1 day starts now() ends now() + interval 1 month on completion preserve disable comment 'purge' do begin delete from t; end", true, "CREATE DEFINER = `root`@`%` EVENT IF NOT EXISTS `test`.`e` ON SCHEDULE EVERY 1 DAY STARTS NOW() ENDS DATE_ADD(NOW(), INTERVAL 1 MONTH) ON COMPLETION PRESERVE DISABLE COMMENT 'purge' DO BEGIN DELETE FROM `t`; END"},
+		{"create event e on schedule every '1:30' hour_minute on completion not preserve disable on slave do set @x = 1", true, "CREATE EVENT `e` ON SCHEDULE EVERY _UTF8MB4'1:30' HOUR_MINUTE ON COMPLETION NOT PRESERVE DISABLE ON SLAVE DO SET @`x`=1"},
+		{"create event e do set @x = 1", false, ""},
+		{"create event e on schedule every 1 do set @x = 1", false, ""},
+		{"create or replace event e on schedule every 1 hour do set @x = 1", false, ""},
+		{"alter event e on schedule every 2 hour on completion preserve", true, "ALTER EVENT `e` ON SCHEDULE EVERY 2 HOUR ON COMPLETION PRESERVE"},
+		{"alter event e on completion not preserve rename to test.e2 enable comment '' do set @x = 1", true, "ALTER EVENT `e` ON COMPLETION NOT PRESERVE RENAME TO `test`.`e2` ENABLE COMMENT '' DO SET @`x`=1"},
+		{"alter definer = 'u'@'%' event e disable", true, "ALTER DEFINER = `u`@`%` EVENT `e` DISABLE"},
+		{"alter event e", false, ""},
+		{"drop event e", true, "DROP EVENT `e`"},
+		{"drop event if exists test.e", true, "DROP EVENT IF EXISTS `test`.`e`"},
+		{"create event e on schedule every 1 day do begin alter event e2 disable; drop event e3; end", true, "CREATE EVENT `e` ON SCHEDULE EVERY 1 DAY DO BEGIN ALTER EVENT `e2` DISABLE; DROP EVENT `e3`; END"},
+		{"show create event test.e", true, "SHOW CREATE EVENT `test`.`e`"},
+		{"show events from test like 'e%'", true, "SHOW EVENTS IN `test` LIKE _UTF8MB4'e%'"},
+		{"create table t (at int, completion int, ends int, every int, starts int)", true, "CREATE TABLE `t` (`at` INT,`completion` INT,`ends` INT,`every` INT,`starts` INT)"},
+	}
+	RunTest(t, table, false)
+}
+
 // For BRIE
 func TestBRIE(t *testing.T) {
 	table := []testCase{
@@ -6396,6 +6591,10 @@ func TestStatisticsOps(t *testing.T) {
 		{"create statistics stats1 (cardinality) on t(a,b,c)", true, "CREATE STATISTICS `stats1` (CARDINALITY) ON `t`(`a`, `b`, `c`)"},
 		{"create statistics stats2 (dependency) on t(a,b)", true, "CREATE STATISTICS `stats2` (DEPENDENCY) ON `t`(`a`, `b`)"},
 		{"create statistics stats3 (correlation) on t(a,b)", true, "CREATE STATISTICS `stats3` (CORRELATION) ON `t`(`a`, `b`)"},
+		{"create statistics stats4 (histogram) on t(a,b)", true, "CREATE STATISTICS `stats4` (HISTOGRAM) ON `t`(`a`, `b`)"},
+		{"alter table t add stats_extended s1 cardinality(a,b,c)", true, "ALTER TABLE `t` ADD STATS_EXTENDED `s1` CARDINALITY(`a`, `b`, `c`)"},
+		{"alter table t add stats_extended s2 dependency(a,b)", true, "ALTER TABLE `t` ADD STATS_EXTENDED `s2` DEPENDENCY(`a`, `b`)"},
+		{"alter table t add stats_extended if not exists s3 histogram(a,b)", true, "ALTER TABLE `t` ADD STATS_EXTENDED IF NOT EXISTS `s3` HISTOGRAM(`a`, `b`)"},
 		{"create statistics stats3 on t(a,b)", false, ""},
 		{"create statistics if not exists stats1 (cardinality) on t(a,b,c)", true, "CREATE STATISTICS IF NOT EXISTS `stats1` (CARDINALITY) ON `t`(`a`, `b`, `c`)"},
 		{"create statistics if not exists stats2 (dependency) on t(a,b)", true, "CREATE STATISTICS IF NOT EXISTS `stats2` (DEPENDENCY) ON `t`(`a`, `b`)"},
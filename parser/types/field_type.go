@@ -55,6 +55,8 @@ type FieldType struct {
 	// elems is the element list for enum and set type.
 	elems            []string
 	elemsIsBinaryLit []bool
+	// geometryType is the type of the values of the spatial type.
+	geometryType GeometryType
 	// Please keep in mind that jsonFieldType should be updated if you add a new field here.
 }
 
@@ -111,6 +113,11 @@ func (ft *FieldType) GetElems() []string {
 	return ft.elems
 }
 
+// GetGeometryType returns the geometry type of the spatial type.
+func (ft *FieldType) GetGeometryType() GeometryType {
+	return ft.geometryType
+}
+
 // SetType sets the type of the FieldType.
 func (ft *FieldType) SetType(tp byte) {
 	ft.tp = tp
@@ -197,6 +204,11 @@ func (ft *FieldType) SetCollate(collate string) {
 	ft.collate = collate
 }
 
+// SetGeometryType sets the geometry type of the spatial type.
+func (ft *FieldType) SetGeometryType(geometryType GeometryType) {
+	ft.geometryType = geometryType
+}
+
 // SetElems sets the elements of the FieldType.
 func (ft *FieldType) SetElems(elems []string) {
 	ft.elems = elems
@@ -260,7 +272,8 @@ func (ft *FieldType) Equal(other *FieldType) bool {
 		ft.charset == other.charset &&
 		ft.collate == other.collate &&
 		flenEqual &&
-		mysql.HasUnsignedFlag(ft.flag) == mysql.HasUnsignedFlag(other.flag)
+		mysql.HasUnsignedFlag(ft.flag) == mysql.HasUnsignedFlag(other.flag) &&
+		ft.geometryType == other.geometryType
 	if !partialEqual || len(ft.elems) != len(other.elems) {
 		return false
 	}
@@ -314,6 +327,9 @@ func (ft *FieldType) Init(tp byte) {
 // This is used for showing column type in infoschema.
 func (ft *FieldType) CompactStr() string {
 	ts := TypeToStr(ft.tp, ft.charset)
+	if ft.tp == mysql.TypeGeometry {
+		ts = ft.geometryType.String()
+	}
 	suffix := ""
 
 	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.tp)
@@ -403,6 +419,10 @@ func (ft *FieldType) String() string {
 
 // Restore implements Node interface.
 func (ft *FieldType) Restore(ctx *format.RestoreCtx) error {
+	if ft.tp == mysql.TypeGeometry {
+		ctx.WriteKeyWord(ft.geometryType.String())
+		return nil
+	}
 	ctx.WriteKeyWord(TypeToStr(ft.tp, ft.charset))
 
 	precision := UnspecifiedLength
@@ -569,6 +589,7 @@ type jsonFieldType struct {
 	Collate          string
 	Elems            []string
 	ElemsIsBinaryLit []bool
+	GeometryType     GeometryType
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -584,6 +605,7 @@ func (ft *FieldType) UnmarshalJSON(data []byte) error {
 		ft.collate = r.Collate
 		ft.elems = r.Elems
 		ft.elemsIsBinaryLit = r.ElemsIsBinaryLit
+		ft.geometryType = r.GeometryType
 	}
 	return err
 }
@@ -599,5 +621,6 @@ func (ft *FieldType) MarshalJSON() ([]byte, error) {
 	r.Collate = ft.collate
 	r.Elems = ft.elems
 	r.ElemsIsBinaryLit = ft.elemsIsBinaryLit
+	r.GeometryType = ft.geometryType
 	return json.Marshal(r)
 }
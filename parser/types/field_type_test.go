@@ -42,6 +42,12 @@ func TestFieldType(t *testing.T) {
 	require.Equal(t, "int(5) unsigned", ft.InfoSchemaStr())
 	require.False(t, HasCharset(ft))
 
+	ft = NewFieldType(mysql.TypeGeometry)
+	ft.SetGeometryType(GeometryTypeMultiPolygon)
+	require.Equal(t, "multipolygon", ft.InfoSchemaStr())
+	ft.SetGeometryType(GeometryTypeGeometryCollection)
+	require.Equal(t, "geomcollection", ft.CompactStr())
+
 	ft = NewFieldType(mysql.TypeFloat)
 	ft.SetFlen(12)   // Default
 	ft.SetDecimal(3) // Not Default
@@ -299,4 +305,10 @@ func TestFieldTypeEqual(t *testing.T) {
 	ft2.SetDecimal(-1)
 	ft1.SetFlen(23)
 	require.Equal(t, true, ft1.Equal(ft2))
+
+	// geometry type not equal
+	ft1 = NewFieldType(mysql.TypeGeometry)
+	ft2 = NewFieldType(mysql.TypeGeometry)
+	ft2.SetGeometryType(GeometryTypePoint)
+	require.Equal(t, false, ft1.Equal(ft2))
 }
@@ -0,0 +1,55 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// GeometryType indicates the type of the values of a spatial column, its values are the type codes of WKB.
+type GeometryType byte
+
+const (
+	// GeometryTypeGeometry represents type GEOMETRY, which accepts the values of all the other types.
+	GeometryTypeGeometry GeometryType = iota
+	// GeometryTypePoint represents type POINT.
+	GeometryTypePoint
+	// GeometryTypeLineString represents type LINESTRING.
+	GeometryTypeLineString
+	// GeometryTypePolygon represents type POLYGON.
+	GeometryTypePolygon
+	// GeometryTypeMultiPoint represents type MULTIPOINT.
+	GeometryTypeMultiPoint
+	// GeometryTypeMultiLineString represents type MULTILINESTRING.
+	GeometryTypeMultiLineString
+	// GeometryTypeMultiPolygon represents type MULTIPOLYGON.
+	GeometryTypeMultiPolygon
+	// GeometryTypeGeometryCollection represents type GEOMETRYCOLLECTION.
+	GeometryTypeGeometryCollection
+)
+
+var geometryType2Str = []string{
+	GeometryTypeGeometry:           "geometry",
+	GeometryTypePoint:              "point",
+	GeometryTypeLineString:         "linestring",
+	GeometryTypePolygon:            "polygon",
+	GeometryTypeMultiPoint:         "multipoint",
+	GeometryTypeMultiLineString:    "multilinestring",
+	GeometryTypeMultiPolygon:       "multipolygon",
+	GeometryTypeGeometryCollection: "geomcollection",
+}
+
+// String implements fmt.Stringer interface.
+func (gt GeometryType) String() string {
+	if int(gt) < len(geometryType2Str) {
+		return geometryType2Str[gt]
+	}
+	return "unknown"
+}
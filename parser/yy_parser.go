@@ -58,6 +58,10 @@ var (
 	ErrWarnDeprecatedIntegerDisplayWidth = terror.ClassParser.NewStdErr(mysql.ErrWarnDeprecatedSyntaxNoReplacement, mysql.Message("Integer display width is deprecated and will be removed in a future release.", nil))
 	// ErrWrongUsage returns for incorrect usages.
 	ErrWrongUsage = terror.ClassParser.NewStd(mysql.ErrWrongUsage)
+	// ErrSpLabelMismatch returns for an end label of a stored routine statement without a matching begin label.
+	ErrSpLabelMismatch = terror.ClassParser.NewStd(mysql.ErrSpLabelMismatch)
+	// ErrSpBadSQLState returns for an invalid SQLSTATE value in a condition handler.
+	ErrSpBadSQLState = terror.ClassParser.NewStd(mysql.ErrSpBadSQLstate)
 	// SpecFieldPattern special result field pattern
 	SpecFieldPattern = regexp.MustCompile(`(\/\*!(M?[0-9]{5,6})?|\*\/)`)
 	specCodeStart    = regexp.MustCompile(`^\/\*!(M?[0-9]{5,6})?[ \t]*`)
@@ -346,6 +350,16 @@ func getInt64FromNUM(num interface{}) (val int64, errMsg string) {
 	}
 }
 
+// newSpatialFieldType returns the field type of the spatial column, whose values are stored in binary.
+func newSpatialFieldType(geometryType types.GeometryType) *types.FieldType {
+	tp := types.NewFieldType(mysql.TypeGeometry)
+	tp.SetGeometryType(geometryType)
+	tp.SetCharset(charset.CharsetBin)
+	tp.SetCollate(charset.CharsetBin)
+	tp.AddFlag(mysql.BinaryFlag)
+	return tp
+}
+
 func isRevokeAllGrant(roleOrPrivList []*ast.RoleOrPriv) bool {
 	if len(roleOrPrivList) != 2 {
 		return false
@@ -2554,7 +2554,7 @@ func (*MergeAdjacentWindow) Match(expr *memo.ExprIter) bool {
 		}
 	}
 	for _, funDesc := range curWinPlan.WindowFuncDescs {
-		for _, arg := range funDesc.Args {
+		for _, arg := range funDesc.Exprs() {
 			cols := expression.ExtractColumns(arg)
 			for _, c := range cols {
 				if _, ok := nextWindowChildrenExistedCols[c.UniqueID]; !ok {
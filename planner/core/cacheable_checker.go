@@ -86,7 +86,7 @@ func (checker *cacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren
 				return in, true
 			}
 		}
-	case *ast.VariableExpr, *ast.ExistsSubqueryExpr, *ast.SubqueryExpr:
+	case *ast.VariableExpr, *ast.ExistsSubqueryExpr, *ast.SubqueryExpr, *ast.MatchAgainst:
 		checker.cacheable = false
 		return in, true
 	case *ast.FuncCallExpr:
@@ -0,0 +1,129 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/util/execdetails"
+)
+
+// CollectCardFeedback collects the estimated and actual row counts of the readers in the executed plan, which are
+// used to learn the cardinality corrections.
+func CollectCardFeedback(p Plan, runtimeStatsColl *execdetails.RuntimeStatsColl) []*statistics.CardCorrectionFeedback {
+	if runtimeStatsColl == nil {
+		return nil
+	}
+	switch x := p.(type) {
+	case *Explain:
+		if x.Analyze {
+			return CollectCardFeedback(x.TargetPlan, runtimeStatsColl)
+		}
+	case *Insert:
+		if x.SelectPlan != nil {
+			return collectCardFeedback(x.SelectPlan, nil, runtimeStatsColl, nil)
+		}
+	case *Update:
+		return collectCardFeedback(x.SelectPlan, nil, runtimeStatsColl, nil)
+	case *Delete:
+		return collectCardFeedback(x.SelectPlan, nil, runtimeStatsColl, nil)
+	case PhysicalPlan:
+		return collectCardFeedback(x, nil, runtimeStatsColl, nil)
+	}
+	return nil
+}
+
+func collectCardFeedback(p, parent PhysicalPlan, runtimeStatsColl *execdetails.RuntimeStatsColl,
+	feedbacks []*statistics.CardCorrectionFeedback) []*statistics.CardCorrectionFeedback {
+	if p == nil {
+		return feedbacks
+	}
+	var children []PhysicalPlan
+	switch x := p.(type) {
+	case *PhysicalTableReader, *PhysicalIndexReader, *PhysicalIndexLookUpReader, *PhysicalIndexMergeReader:
+		if fb := getReaderCardFeedback(x, parent, runtimeStatsColl); fb != nil {
+			feedbacks = append(feedbacks, fb)
+		}
+		return feedbacks
+	case *PhysicalLimit:
+		// The children may stop early, so their actual row counts are incomplete.
+		return feedbacks
+	case *PhysicalIndexJoin:
+		children = []PhysicalPlan{x.children[1-x.InnerChildIdx]}
+	case *PhysicalIndexHashJoin:
+		children = []PhysicalPlan{x.children[1-x.InnerChildIdx]}
+	case *PhysicalIndexMergeJoin:
+		children = []PhysicalPlan{x.children[1-x.InnerChildIdx]}
+	case *PhysicalApply:
+		// The inner child is executed once for every outer row.
+		children = []PhysicalPlan{x.children[0]}
+	default:
+		children = p.Children()
+	}
+	for _, child := range children {
+		feedbacks = collectCardFeedback(child, p, runtimeStatsColl, feedbacks)
+	}
+	return feedbacks
+}
+
+// getReaderCardFeedback returns the feedback of the reader if its actual row count is the one of all the rows
+// satisfying the pushed down conditions of the DataSource.
+func getReaderCardFeedback(reader, parent PhysicalPlan, runtimeStatsColl *execdetails.RuntimeStatsColl) *statistics.CardCorrectionFeedback {
+	switch parent.(type) {
+	case *PhysicalSelection, *PhysicalUnionScan:
+		// Some rows returned by the reader may be filtered or added by the parent.
+		return nil
+	}
+	var plans []PhysicalPlan
+	switch x := reader.(type) {
+	case *PhysicalTableReader:
+		plans = x.TablePlans
+	case *PhysicalIndexReader:
+		plans = x.IndexPlans
+	case *PhysicalIndexLookUpReader:
+		plans = append(append(plans, x.IndexPlans...), x.TablePlans...)
+	case *PhysicalIndexMergeReader:
+		for _, partialPlans := range x.PartialPlans {
+			plans = append(plans, partialPlans...)
+		}
+		plans = append(plans, x.TablePlans...)
+	}
+	var info *cardFeedbackInfo
+	for _, p := range plans {
+		switch x := p.(type) {
+		case *PhysicalTableScan:
+			if info == nil {
+				info = x.cardFeedback
+			}
+		case *PhysicalIndexScan:
+			if info == nil {
+				info = x.cardFeedback
+			}
+		case *PhysicalSelection, *PhysicalProjection:
+		default:
+			// The operators like Limit and Agg pushed down to the storage change the row count.
+			return nil
+		}
+	}
+	if info == nil || !runtimeStatsColl.ExistsRootStats(reader.ID()) {
+		return nil
+	}
+	return &statistics.CardCorrectionFeedback{
+		TableID:      info.tableID,
+		Predicates:   info.predicates,
+		StatsVersion: info.statsVersion,
+		EstRows:      info.estRows,
+		ActRows:      float64(runtimeStatsColl.GetRootStats(reader.ID()).GetActRows()),
+	}
+}
@@ -195,7 +195,7 @@ func (c *columnStatsUsageCollector) collectFromPlan(lp LogicalPlan) {
 			// Schema change from children to self.
 			windowColumns := x.GetWindowResultColumns()
 			for i, col := range windowColumns {
-				c.updateColMapFromExpressions(col, x.WindowFuncDescs[i].Args)
+				c.updateColMapFromExpressions(col, x.WindowFuncDescs[i].Exprs())
 			}
 		case *LogicalJoin:
 			c.collectPredicateColumnsForJoin(x)
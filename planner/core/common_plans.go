@@ -586,6 +586,24 @@ type IndexAdvise struct {
 	LinesInfo   *ast.LinesClause
 }
 
+// RecommendIndex represents a recommend index plan.
+type RecommendIndex struct {
+	baseSchemaProducer
+
+	SQL         string
+	MaxMinutes  uint64
+	MaxIndexNum *ast.MaxIndexNumClause
+}
+
+// RefreshMaterializedView represents a refresh materialized view plan.
+type RefreshMaterializedView struct {
+	baseSchemaProducer
+
+	DBName    model.CIStr
+	TableInfo *model.TableInfo
+	Method    ast.RefreshMethod
+}
+
 // SplitRegion represents a split regions plan.
 type SplitRegion struct {
 	baseSchemaProducer
@@ -950,7 +968,7 @@ func binaryOpTreeFromFlatOps(explainCtx sessionctx.Context, ops FlatPlanTree) *t
 func binaryOpFromFlatOp(explainCtx sessionctx.Context, op *FlatOperator, out *tipb.ExplainOperator) {
 	out.Name = op.Origin.ExplainID().String()
 	switch op.Label {
-	case BuildSide:
+	case BuildSide, AdaptiveBuildSide:
 		out.Labels = []tipb.OperatorLabel{tipb.OperatorLabel_buildSide}
 	case ProbeSide:
 		out.Labels = []tipb.OperatorLabel{tipb.OperatorLabel_probeSide}
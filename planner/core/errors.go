@@ -71,6 +71,7 @@ var (
 	ErrWindowRangeBoundNotConstant           = dbterror.ClassOptimizer.NewStd(mysql.ErrWindowRangeBoundNotConstant)
 	ErrWindowRowsIntervalUse                 = dbterror.ClassOptimizer.NewStd(mysql.ErrWindowRowsIntervalUse)
 	ErrWindowFunctionIgnoresFrame            = dbterror.ClassOptimizer.NewStd(mysql.ErrWindowFunctionIgnoresFrame)
+	ErrFieldInGroupingNotGroupBy             = dbterror.ClassOptimizer.NewStd(mysql.ErrFieldInGroupingNotGroupBy)
 	ErrUnsupportedOnGeneratedColumn          = dbterror.ClassOptimizer.NewStd(mysql.ErrUnsupportedOnGeneratedColumn)
 	ErrPrivilegeCheckFail                    = dbterror.ClassOptimizer.NewStd(mysql.ErrPrivilegeCheckFail)
 	ErrInvalidWildCard                       = dbterror.ClassOptimizer.NewStd(mysql.ErrInvalidWildCard)
@@ -110,4 +111,24 @@ var (
 	ErrSubqueryMoreThan1Row     = dbterror.ClassOptimizer.NewStd(mysql.ErrSubqueryNo1Row)
 	ErrKeyPart0                 = dbterror.ClassOptimizer.NewStd(mysql.ErrKeyPart0)
 	ErrGettingNoopVariable      = dbterror.ClassOptimizer.NewStd(mysql.ErrGettingNoopVariable)
+	ErrSpWrongName              = dbterror.ClassOptimizer.NewStd(mysql.ErrSpWrongName)
+	ErrSpDupParam               = dbterror.ClassOptimizer.NewStd(mysql.ErrSpDupParam)
+	ErrSpDupVar                 = dbterror.ClassOptimizer.NewStd(mysql.ErrSpDupVar)
+	ErrSpDupCurs                = dbterror.ClassOptimizer.NewStd(mysql.ErrSpDupCurs)
+	ErrSpCursorMismatch         = dbterror.ClassOptimizer.NewStd(mysql.ErrSpCursorMismatch)
+	ErrSpUndeclaredVar          = dbterror.ClassOptimizer.NewStd(mysql.ErrSpUndeclaredVar)
+	ErrSpLilabelMismatch        = dbterror.ClassOptimizer.NewStd(mysql.ErrSpLilabelMismatch)
+	ErrSpLabelRedefine          = dbterror.ClassOptimizer.NewStd(mysql.ErrSpLabelRedefine)
+	ErrSpBadReturn              = dbterror.ClassOptimizer.NewStd(mysql.ErrSpBadreturn)
+	ErrSpNoReturn               = dbterror.ClassOptimizer.NewStd(mysql.ErrSpNoreturn)
+	ErrSpNoRetset               = dbterror.ClassOptimizer.NewStd(mysql.ErrSpNoRetset)
+	ErrSpVarCondAfterCursor     = dbterror.ClassOptimizer.NewStd(mysql.ErrSpVarcondAfterCurshndlr)
+	ErrSpCursorAfterHandler     = dbterror.ClassOptimizer.NewStd(mysql.ErrSpCursorAfterHandler)
+
+	ErrTrgNoSuchRowInTrg         = dbterror.ClassOptimizer.NewStd(mysql.ErrTrgNoSuchRowInTrg)
+	ErrTrgCantChangeRow          = dbterror.ClassOptimizer.NewStd(mysql.ErrTrgCantChangeRow)
+	ErrCommitNotAllowedInSfOrTrg = dbterror.ClassOptimizer.NewStd(mysql.ErrCommitNotAllowedInSfOrTrg)
+	ErrEventRecursionForbidden   = dbterror.ClassOptimizer.NewStd(mysql.ErrEventRecursionForbidden)
+
+	ErrFtMatchingKeyNotFound = dbterror.ClassOptimizer.NewStd(mysql.ErrFtMatchingKeyNotFound)
 )
@@ -1624,6 +1624,38 @@ func filterIndexJoinBySessionVars(sc sessionctx.Context, indexJoins []PhysicalPl
 	return indexJoins
 }
 
+// makeIndexJoinsAdaptive prepares the hash joins which the index joins switch to during execution when the outer
+// sides turn out to have many more rows than estimated. The index merge joins keep the order of the inner sides, and
+// the index joins required to keep the order can't switch to the hash joins, so they are not adaptive.
+func (p *LogicalJoin) makeIndexJoinsAdaptive(prop *property.PhysicalProperty, indexJoins []PhysicalPlan) error {
+	if !p.ctx.GetSessionVars().EnableAdaptiveJoin || !prop.IsSortItemEmpty() {
+		return nil
+	}
+	for _, plan := range indexJoins {
+		var join *PhysicalIndexJoin
+		switch x := plan.(type) {
+		case *PhysicalIndexJoin:
+			join = x
+		case *PhysicalIndexHashJoin:
+			join = &x.PhysicalIndexJoin
+		default:
+			continue
+		}
+		innerIdx := join.InnerChildIdx
+		hashJoin := p.getHashJoin(prop, innerIdx, false)
+		innerTask, _, err := p.children[innerIdx].findBestTask(hashJoin.GetChildReqProps(innerIdx), &PlanCounterDisabled, nil)
+		if err != nil {
+			return err
+		}
+		if innerTask.invalid() {
+			continue
+		}
+		join.AdaptiveHashJoin = hashJoin
+		join.adaptiveInnerTask = innerTask
+	}
+	return nil
+}
+
 // tryToGetIndexJoin will get index join by hints. If we can generate a valid index join by hint, the second return value
 // will be true, which means we force to choose this index join. Otherwise we will select a join algorithm with min-cost.
 func (p *LogicalJoin) tryToGetIndexJoin(prop *property.PhysicalProperty) (indexJoins []PhysicalPlan, canForced bool) {
@@ -1828,6 +1860,9 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 	if forced {
 		return indexJoins, true, nil
 	}
+	if err := p.makeIndexJoinsAdaptive(prop, indexJoins); err != nil {
+		return nil, false, err
+	}
 	joins = append(joins, indexJoins...)
 
 	hashJoins := p.getHashJoins(prop)
@@ -2262,6 +2297,10 @@ func (lw *LogicalWindow) tryToGetMppWindows(prop *property.PhysicalProperty) []P
 		if !allSupported {
 			return nil
 		}
+		if lw.Frame != nil && lw.Frame.Type == ast.Groups {
+			lw.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because the GROUPS frame is not supported now.")
+			return nil
+		}
 		if lw.Frame != nil && lw.Frame.Type == ast.Ranges {
 			if _, err := expression.ExpressionsToPBList(lw.SCtx().GetSessionVars().StmtCtx, lw.Frame.Start.CalcFuncs, lw.ctx.GetClient()); err != nil {
 				lw.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced(
@@ -2415,7 +2454,7 @@ func (p *baseLogicalPlan) canPushToCopImpl(storeTp kv.StoreType, considerDual bo
 			}
 		case *LogicalTableDual:
 			return storeTp == kv.TiFlash && considerDual
-		case *LogicalAggregation, *LogicalSelection, *LogicalJoin, *LogicalWindow:
+		case *LogicalAggregation, *LogicalSelection, *LogicalJoin, *LogicalWindow, *LogicalExpand:
 			if storeTp == kv.TiFlash {
 				ret = ret && c.canPushToCop(storeTp)
 			} else {
@@ -2918,6 +2957,31 @@ func (ls *LogicalSort) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]
 	return nil, true, nil
 }
 
+func (p *LogicalExpand) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
+	// Expand can not keep the order, and its output is not partitioned by the grouping columns.
+	if !prop.IsSortItemEmpty() || (prop.IsFlashProp() && prop.TaskTp != property.MppTaskType) {
+		return nil, true, nil
+	}
+	if prop.TaskTp == property.MppTaskType && prop.MPPPartitionTp != property.AnyType {
+		return nil, true, nil
+	}
+	childProp := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	if prop.TaskTp == property.MppTaskType {
+		if !p.ctx.GetSessionVars().IsMPPAllowed() || !p.canPushToCop(kv.TiFlash) {
+			return nil, true, nil
+		}
+		childProp = &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64, RejectSort: true}
+	}
+	expand := PhysicalExpand{
+		SourceCols:    p.SourceCols,
+		GroupingSets:  p.GroupingSets,
+		GroupingCols:  p.GroupingCols,
+		GroupingIDCol: p.GroupingIDCol,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, childProp)
+	expand.SetSchema(p.Schema())
+	return []PhysicalPlan{expand}, true, nil
+}
+
 func (p *LogicalMaxOneRow) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
 	if !prop.IsSortItemEmpty() || prop.IsFlashProp() {
 		p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because operator `MaxOneRow` is not supported now.")
@@ -299,6 +299,12 @@ func (p *PhysicalUnionScan) ExplainInfo() string {
 // ExplainInfo implements Plan interface.
 func (p *PhysicalSelection) ExplainInfo() string {
 	exprStr := string(expression.SortedExplainExpressionList(p.Conditions))
+	if len(p.RuntimeFilters) > 0 {
+		if len(exprStr) > 0 {
+			exprStr += ", "
+		}
+		exprStr += explainRuntimeFilters(p.RuntimeFilters, true)
+	}
 	if p.TiFlashFineGrainedShuffleStreamCount > 0 {
 		exprStr += fmt.Sprintf(", stream_count: %d", p.TiFlashFineGrainedShuffleStreamCount)
 	}
@@ -352,6 +358,28 @@ func (p *PhysicalLimit) ExplainInfo() string {
 	return str.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalExpand) ExplainInfo() string {
+	var str strings.Builder
+	str.WriteString("grouping sets:[")
+	for i, set := range p.GroupingSets {
+		if i > 0 {
+			str.WriteString(", ")
+		}
+		str.WriteString("[")
+		for j, offset := range set {
+			if j > 0 {
+				str.WriteString(", ")
+			}
+			str.WriteString(p.SourceCols[offset].ExplainInfo())
+		}
+		str.WriteString("]")
+	}
+	str.WriteString("], grouping id:")
+	str.WriteString(p.GroupingIDCol.ExplainInfo())
+	return str.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *basePhysicalAgg) ExplainInfo() string {
 	return p.explainInfo(false)
@@ -447,6 +475,9 @@ func (p *PhysicalIndexJoin) explainInfo(normalized bool, isIndexMergeJoin bool)
 		buffer.WriteString(", other cond:")
 		buffer.Write(sortedExplainExpressionList(p.OtherConditions))
 	}
+	if p.AdaptiveHashJoin != nil && !normalized {
+		fmt.Fprintf(buffer, ", adaptive threshold:%d", p.AdaptiveThreshold)
+	}
 	return buffer.String()
 }
 
@@ -522,6 +553,10 @@ func (p *PhysicalHashJoin) explainInfo(normalized bool) string {
 		buffer.WriteString(", other cond:")
 		buffer.Write(sortedExplainExpressionList(p.OtherConditions))
 	}
+	if len(p.RuntimeFilters) > 0 && !normalized {
+		buffer.WriteString(", ")
+		buffer.WriteString(explainRuntimeFilters(p.RuntimeFilters, false))
+	}
 	return buffer.String()
 }
 
@@ -648,9 +683,12 @@ func (p *PhysicalWindow) ExplainInfo() string {
 		if !isFirst {
 			buffer.WriteString(" ")
 		}
-		if p.Frame.Type == ast.Rows {
+		switch p.Frame.Type {
+		case ast.Rows:
 			buffer.WriteString("rows")
-		} else {
+		case ast.Groups:
+			buffer.WriteString("groups")
+		default:
 			buffer.WriteString("range")
 		}
 		buffer.WriteString(" between ")
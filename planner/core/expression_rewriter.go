@@ -42,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/stringutil"
@@ -1224,6 +1225,8 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		}
 		er.ctxStack[len(er.ctxStack)-1].SetCoercibility(expression.CoercibilityExplicit)
 		er.ctxStack[len(er.ctxStack)-1].SetCharsetAndCollation(arg.GetType().GetCharset(), arg.GetType().GetCollate())
+	case *ast.MatchAgainst:
+		er.matchAgainstToExpression(v)
 	default:
 		er.err = errors.Errorf("UnknownType: %T", v)
 		return retNode, false
@@ -1895,6 +1898,11 @@ func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 		return
 	}
 
+	if v.Schema.L == "" && !expression.IsFunctionSupported(v.FnName.L) && expression.IsCreatedUDF(v.FnName.L) {
+		// The plan calling a loadable function isn't cached since the function may be dropped.
+		er.sctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+	}
+
 	var function expression.Expression
 	er.ctxStackPop(len(v.Args))
 	if _, ok := expression.DeferredFunctions[v.FnName.L]; er.useCache() && ok {
@@ -1974,6 +1982,100 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 	er.err = ErrUnknownColumn.GenWithStackByArgs(v.String(), clauseMsg[er.b.curClause])
 }
 
+// matchAgainstToExpression converts `MATCH (cols) AGAINST (expr)` to the function which computes the relevance.
+// The columns must be the columns of a FULLTEXT index of a base table.
+func (er *expressionRewriter) matchAgainstToExpression(v *ast.MatchAgainst) {
+	stkLen := len(er.ctxStack)
+	colLen := len(v.ColumnNames)
+	against := er.ctxStack[stkLen-1]
+	if _, ok := against.(*expression.Constant); !ok {
+		er.err = ErrWrongArguments.GenWithStackByArgs("AGAINST")
+		return
+	}
+	if v.Modifier.WithQueryExpansion() {
+		er.err = ErrNotSupportedYet.GenWithStackByArgs("WITH QUERY EXPANSION")
+		return
+	}
+	cols := er.ctxStack[stkLen-1-colLen : stkLen-1]
+	names := er.ctxNameStk[stkLen-1-colLen : stkLen-1]
+	colNames := make([]model.CIStr, 0, colLen)
+	for i, col := range cols {
+		if _, ok := col.(*expression.Column); !ok || names[i].OrigTblName.L == "" ||
+			names[i].DBName.L != names[0].DBName.L || names[i].OrigTblName.L != names[0].OrigTblName.L {
+			er.err = ErrFtMatchingKeyNotFound
+			return
+		}
+		colName := names[i].OrigColName
+		if colName.L == "" {
+			colName = names[i].ColName
+		}
+		colNames = append(colNames, colName)
+	}
+	tbl, err := er.b.is.TableByName(names[0].DBName, names[0].OrigTblName)
+	if err != nil {
+		er.err = err
+		return
+	}
+	tblInfo := tbl.Meta()
+	idxInfo := findFulltextIndex(tblInfo, colNames)
+	if idxInfo == nil {
+		er.err = ErrFtMatchingKeyNotFound
+		return
+	}
+	info := &expression.FulltextMatchInfo{
+		IndexID:     idxInfo.ID,
+		Parser:      idxInfo.FulltextParser,
+		Collation:   fulltext.Collation(tblInfo, idxInfo),
+		BooleanMode: v.Modifier.IsBooleanMode(),
+	}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			info.PhysicalIDs = append(info.PhysicalIDs, def.ID)
+		}
+	} else {
+		info.PhysicalIDs = []int64{tblInfo.ID}
+	}
+	for _, pid := range info.PhysicalIDs {
+		info.RowCount += getStatsTable(er.sctx, tblInfo, pid).Count
+	}
+	args := make([]expression.Expression, colLen)
+	copy(args, cols)
+	f, err := expression.NewFulltextMatch(er.sctx, info, against, args)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(colLen + 1)
+	er.ctxStackAppend(f, types.EmptyName)
+}
+
+// findFulltextIndex returns the public FULLTEXT index whose columns are exactly the columns.
+func findFulltextIndex(tblInfo *model.TableInfo, colNames []model.CIStr) *model.IndexInfo {
+	for _, idx := range tblInfo.Indices {
+		if idx.Tp != model.IndexTypeFulltext || idx.State != model.StatePublic || len(idx.Columns) != len(colNames) {
+			continue
+		}
+		matched := true
+		for _, idxCol := range idx.Columns {
+			found := false
+			for _, colName := range colNames {
+				if idxCol.Name.L == colName.L {
+					found = true
+					break
+				}
+			}
+			if !found {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return idx
+		}
+	}
+	return nil
+}
+
 func findFieldNameFromNaturalUsingJoin(p LogicalPlan, v *ast.ColumnName) (col *expression.Column, name *types.FieldName, err error) {
 	switch x := p.(type) {
 	case *LogicalLimit, *LogicalSelection, *LogicalTopN, *LogicalSort, *LogicalMaxOneRow:
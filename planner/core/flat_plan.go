@@ -116,6 +116,9 @@ const (
 	SeedPart
 	// RecursivePart means this operator is the recursive part of its parent (a cte)
 	RecursivePart
+	// AdaptiveBuildSide means this operator is at the build side of the hash join which its parent (an adaptive index
+	// join) switches to during execution
+	AdaptiveBuildSide
 )
 
 func (d OperatorLabel) String() string {
@@ -130,6 +133,8 @@ func (d OperatorLabel) String() string {
 		return "(Seed Part)"
 	case RecursivePart:
 		return "(Recursive Part)"
+	case AdaptiveBuildSide:
+		return "(Adaptive Build)"
 	}
 	return ""
 }
@@ -270,12 +275,20 @@ func (f *FlatPhysicalPlan) flattenRecursively(p Plan, info *operatorCtx, target
 			}
 		}
 
+		// The inner plan of the hash join which an adaptive index join switches to is put after its children.
+		adaptiveInnerPlan := getAdaptiveInnerPlan(physPlan)
 		for i := range children {
 			childCtx.label = label[i]
-			childCtx.isLastChild = i == len(children)-1
+			childCtx.isLastChild = i == len(children)-1 && adaptiveInnerPlan == nil
 			target, childIdx = f.flattenRecursively(children[i], childCtx, target)
 			childIdxs = append(childIdxs, childIdx)
 		}
+		if adaptiveInnerPlan != nil {
+			childCtx.label = AdaptiveBuildSide
+			childCtx.isLastChild = true
+			target, childIdx = f.flattenRecursively(adaptiveInnerPlan, childCtx, target)
+			childIdxs = append(childIdxs, childIdx)
+		}
 	}
 
 	// For part of physical operators and some special operators, we need some special logic to get their "children".
@@ -151,6 +151,8 @@ func (f *Fragment) init(p PhysicalPlan) error {
 // after untwist, there will be two plans in `forest` slice:
 // - ExchangeSender -> Projection (c1) -> TableScan(t)
 // - ExchangeSender -> Projection (c2) -> TableScan(s)
+// The expand operators are removed in the same way, every grouping set is untwisted into a projection upon a copy
+// of the child plan.
 func untwistPlanAndRemoveUnionAll(stack []PhysicalPlan, forest *[]*PhysicalExchangeSender) error {
 	cur := stack[len(stack)-1]
 	switch x := cur.(type) {
@@ -189,6 +191,17 @@ func untwistPlanAndRemoveUnionAll(stack []PhysicalPlan, forest *[]*PhysicalExcha
 				return errors.Trace(err)
 			}
 		}
+	case *PhysicalExpand:
+		for i := range x.GroupingSets {
+			stack[len(stack)-1] = x.projectionOfGroupingSet(i)
+			stack = append(stack, x.children[0])
+			err := untwistPlanAndRemoveUnionAll(stack, forest)
+			stack = stack[:len(stack)-1]
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		stack[len(stack)-1] = x
 	default:
 		if len(cur.Children()) != 1 {
 			return errors.Trace(errors.New("unexpected plan " + cur.ExplainID().String()))
@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// getHypoIndexes returns the hypothetical indexes of the table created in the session. They are only visible when
+// the statement is explained without being executed, so a plan using them is never executed.
+func getHypoIndexes(ctx sessionctx.Context, tblInfo *model.TableInfo) []*model.IndexInfo {
+	vars := ctx.GetSessionVars()
+	if !vars.StmtCtx.InExplainStmt || vars.StmtCtx.InExplainAnalyzeStmt || len(vars.HypoIndexes) == 0 {
+		return nil
+	}
+	hypoIndexes := make([]*model.IndexInfo, 0, len(vars.HypoIndexes[tblInfo.ID]))
+	for _, idx := range vars.HypoIndexes[tblInfo.ID] {
+		// The columns may have been changed after the index is created.
+		valid := true
+		for _, idxCol := range idx.Columns {
+			if idxCol.Offset >= len(tblInfo.Columns) || tblInfo.Columns[idxCol.Offset].Name.L != idxCol.Name.L ||
+				tblInfo.Columns[idxCol.Offset].State != model.StatePublic {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			hypoIndexes = append(hypoIndexes, idx)
+		}
+	}
+	return hypoIndexes
+}
+
+// appendHypoIndexStats returns a copy of the statistics table with the statistics of the hypothetical indexes, which
+// are derived from the statistics of their columns.
+func appendHypoIndexStats(sc *stmtctx.StatementContext, statsTbl *statistics.Table, tblInfo *model.TableInfo, hypoIndexes []*model.IndexInfo) *statistics.Table {
+	if len(hypoIndexes) == 0 || statsTbl.Pseudo {
+		return statsTbl
+	}
+	statsTbl = statsTbl.Copy()
+	for _, idxInfo := range hypoIndexes {
+		if idx := deriveHypoIndexStats(sc, statsTbl, tblInfo, idxInfo); idx != nil {
+			statsTbl.Indices[idxInfo.ID] = idx
+		}
+	}
+	return statsTbl
+}
+
+// deriveHypoIndexStats derives the statistics of the hypothetical index from the ones of its first column, since the
+// index key of a single column index is encoded from the column value in the same way as the statistics of the
+// column are queried. The NDV of a multi-column index is estimated by assuming the columns are independent.
+func deriveHypoIndexStats(sc *stmtctx.StatementContext, statsTbl *statistics.Table, tblInfo *model.TableInfo, idxInfo *model.IndexInfo) *statistics.Index {
+	col, ok := statsTbl.Columns[tblInfo.Columns[idxInfo.Columns[0].Offset].ID]
+	if !ok || col.TotalRowCount() == 0 {
+		return nil
+	}
+	ndv := col.Histogram.NDV
+	for _, idxCol := range idxInfo.Columns[1:] {
+		c, ok := statsTbl.Columns[tblInfo.Columns[idxCol.Offset].ID]
+		if !ok || c.Histogram.NDV == 0 {
+			continue
+		}
+		ndv *= c.Histogram.NDV
+		if ndv >= statsTbl.Count {
+			ndv = statsTbl.Count
+			break
+		}
+	}
+	hist := statistics.NewHistogram(idxInfo.ID, ndv, col.Histogram.NullCount, col.Histogram.LastUpdateVersion,
+		types.NewFieldType(mysql.TypeBlob), col.Histogram.Len(), col.Histogram.TotColSize)
+	hist.Correlation = col.Histogram.Correlation
+	for i := 0; i < col.Histogram.Len(); i++ {
+		lower, err := codec.EncodeKey(sc, nil, *col.Histogram.GetLower(i))
+		if err != nil {
+			return nil
+		}
+		upper, err := codec.EncodeKey(sc, nil, *col.Histogram.GetUpper(i))
+		if err != nil {
+			return nil
+		}
+		bucket := col.Histogram.Buckets[i]
+		lowerDatum, upperDatum := types.NewBytesDatum(lower), types.NewBytesDatum(upper)
+		hist.AppendBucketWithNDV(&lowerDatum, &upperDatum, bucket.Count, bucket.Repeat, bucket.NDV)
+	}
+	idx := &statistics.Index{
+		Histogram:         *hist,
+		TopN:              col.TopN,
+		ErrorRate:         col.ErrorRate,
+		StatsVer:          col.StatsVer,
+		Info:              idxInfo,
+		PhysicalID:        col.PhysicalID,
+		StatsLoadedStatus: statistics.NewStatsFullLoadStatus(),
+	}
+	// The sketch of the first column can't answer the queries of the whole key of a multi-column index.
+	if len(idxInfo.Columns) == 1 {
+		idx.CMSketch = col.CMSketch
+	}
+	return idx
+}
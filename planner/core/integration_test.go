@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/testkit/testdata"
+	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -3886,6 +3887,109 @@ func TestExtendedStatsSwitch(t *testing.T) {
 	))
 }
 
+func TestExtendedStatsColumnGroupSelectivity(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values(1,1,1),(1,1,1),(1,1,1),(1,1,1),(2,2,2),(2,2,2),(3,3,3),(4,4,4),(5,5,5),(6,6,6)")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("alter table t add stats_extended s1 histogram(a,b)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(b,c)")
+	tk.MustExec("analyze table t")
+
+	// The predicates on the correlated columns are estimated by the joint TopN.
+	rows := tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "4.00", rows[0][1])
+	rows = tk.MustQuery("explain format = 'brief' select * from t where b = 2 and c = 2").Rows()
+	require.Equal(t, "2.00", rows[0][1])
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "1.60", rows[0][1])
+}
+
+func TestExtendedStatsJoinEstimation(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	// Every value of a is paired with every value of b, so NDV(a, b) is 16 while NDV(a) and NDV(b) are 4.
+	for _, tbl := range []string{"t1", "t2"} {
+		tk.MustExec("insert into " + tbl + " values(1,1),(1,2),(1,3),(1,4),(2,1),(2,2),(2,3),(2,4),(3,1),(3,2),(3,3),(3,4),(4,1),(4,2),(4,3),(4,4)")
+	}
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	// The columns of the extended stats are declared in a different order from the sorted join keys.
+	tk.MustExec("alter table t1 add stats_extended s1 cardinality(b,a)")
+	tk.MustExec("alter table t2 add stats_extended s2 dependency(b,a)")
+	tk.MustExec("analyze table t1, t2")
+
+	// The NDV of the join keys is 16 on both sides, so the join is estimated as 16 * 16 / 16.
+	sql := "explain format = 'brief' select /*+ hash_join(t1, t2) */ * from t1 join t2 on t1.a = t2.a and t1.b = t2.b"
+	rows := tk.MustQuery(sql).Rows()
+	require.Equal(t, "16.00", rows[0][1])
+	// The NDV of the join keys of t2 is derived from the dependency b => a whose degree is 1/4, that is,
+	// 4 / (1/4 + 3/4 / 4) = 9.14, and the NDV of t1 is larger.
+	tk.MustExec("alter table t1 drop stats_extended s1")
+	tk.MustExec("analyze table t1")
+	rows = tk.MustQuery(sql).Rows()
+	require.Equal(t, "28.00", rows[0][1])
+	// Without the extended stats, the NDV of the join keys is the max NDV of the columns, which is 4.
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	rows = tk.MustQuery(sql).Rows()
+	require.Equal(t, "64.00", rows[0][1])
+}
+
+func TestCardinalityCorrection(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values(1,1),(1,1),(1,1),(1,1),(2,2),(2,2),(3,3),(4,4),(5,5),(6,6)")
+	tk.MustExec("analyze table t")
+	tk.MustExec("admin reset cardinality_correction")
+
+	// The feedback is not collected if the correction is disabled.
+	tk.MustQuery("select * from t where a = 1 and b = 1").Check(testkit.Rows("1 1", "1 1", "1 1", "1 1"))
+	tk.MustQuery("select count(*) from information_schema.cardinality_corrections").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_cardinality_correction = on")
+	rows := tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "1.60", rows[0][1])
+	tk.MustQuery("select * from t where a = 1 and b = 1").Check(testkit.Rows("1 1", "1 1", "1 1", "1 1"))
+	// The feedback under Limit is not collected.
+	tk.MustQuery("select * from t where a = 2 limit 1").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select table_name, predicates, round(estimated_rows, 2), actual_rows, round(correction_ratio, 2), feedback_count from information_schema.cardinality_corrections").Check(testkit.Rows(
+		"t eq(test.t.a, 1), eq(test.t.b, 1) 1.6 4 2.5 1",
+	))
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "4.00", rows[0][1])
+	// The persisted histograms are not changed.
+	tk.MustExec("set @@tidb_enable_cardinality_correction = off")
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "1.60", rows[0][1])
+
+	tk.MustExec("set @@tidb_enable_cardinality_correction = on")
+	tk.MustExec("admin reset cardinality_correction t")
+	tk.MustQuery("select count(*) from information_schema.cardinality_corrections").Check(testkit.Rows("0"))
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "1.60", rows[0][1])
+
+	// The correction learned on the outdated stats is not used.
+	tk.MustQuery("select * from t where a = 1 and b = 1").Check(testkit.Rows("1 1", "1 1", "1 1", "1 1"))
+	tk.MustExec("analyze table t")
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	require.Equal(t, "1.60", rows[0][1])
+}
+
 func TestOrderByNotInSelectDistinct(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -7209,3 +7313,157 @@ func TestAggWithJsonPushDownToTiFlash(t *testing.T) {
 	}
 	tk.MustQuery("explain select /*+ hash_agg() */  group_concat(a) from t;").CheckAt([]int{0, 2, 4}, rows)
 }
+
+func TestLateralDerivedTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int primary key, b int)")
+	tk.MustExec("create table t2(a int, b int, key(a))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 10), (1, 11), (1, 12), (1, 13), (2, 20), (4, 40)")
+
+	// The lateral derived table without limit is decorrelated to a join.
+	sql := "select * from t1, lateral (select t2.b from t2 where t2.a = t1.a) dt"
+	tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(
+		"Projection 12487.50 root  test.t1.a, test.t1.b, test.t2.b",
+		"└─HashJoin 12487.50 root  inner join, equal:[eq(test.t2.a, test.t1.a)]",
+		"  ├─TableReader(Build) 9990.00 root  data:Selection",
+		"  │ └─Selection 9990.00 cop[tikv]  not(isnull(test.t2.a))",
+		"  │   └─TableFullScan 10000.00 cop[tikv] table:t2 keep order:false, stats:pseudo",
+		"  └─TableReader(Probe) 10000.00 root  data:TableFullScan",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1 1 10", "1 1 11", "1 1 12", "1 1 13", "2 2 20"))
+	tk.MustQuery("select * from t1 left join lateral (select t1.b + t2.b c from t2 where t2.a = t1.a) dt on dt.c > 12").Sort().Check(
+		testkit.Rows("1 1 13", "1 1 14", "2 2 22", "3 3 <nil>"))
+
+	// The lateral derived table with limit is kept as an apply.
+	sql = "select * from t1 left join lateral (select t2.b from t2 where t2.a = t1.a order by t2.b desc limit 2) dt on true"
+	tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(
+		"Projection 10000.00 root  test.t1.a, test.t1.b, test.t2.b",
+		"└─Apply 10000.00 root  CARTESIAN left outer join",
+		"  ├─TableReader(Build) 10000.00 root  data:TableFullScan",
+		"  │ └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"  └─TopN(Probe) 2.00 root  test.t2.b:desc, offset:0, count:2",
+		"    └─IndexLookUp 2.00 root  ",
+		"      ├─IndexRangeScan(Build) 10.00 cop[tikv] table:t2, index:a(a) range: decided by [eq(test.t2.a, test.t1.a)], keep order:false, stats:pseudo",
+		"      └─TopN(Probe) 2.00 cop[tikv]  test.t2.b:desc, offset:0, count:2",
+		"        └─TableRowIDScan 10.00 cop[tikv] table:t2 keep order:false, stats:pseudo"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1 1 12", "1 1 13", "2 2 20", "3 3 <nil>"))
+	tk.MustQuery("select * from t1 join lateral (select t2.b from t2 where t2.a = t1.a order by t2.b desc limit 2) dt on true").Sort().Check(
+		testkit.Rows("1 1 12", "1 1 13", "2 2 20"))
+	tk.MustQuery("select * from t1, lateral (select count(*) c, sum(t2.b) s from t2 where t2.a = t1.a) dt").Sort().Check(
+		testkit.Rows("1 1 4 46", "2 2 1 20", "3 3 0 <nil>"))
+
+	// The lateral derived table can not refer to the left table of a right join.
+	err := tk.ExecToErr("select * from t1 right join lateral (select t2.b from t2 where t2.a = t1.a) dt on true")
+	require.True(t, core.ErrUnknownColumn.Equal(err))
+	require.EqualError(t, err, "[planner:1054]Unknown column 't1.a' in 'from clause'")
+	// It's rejected even if the name can be resolved by the outer query.
+	err = tk.ExecToErr("select (select count(*) from t2 t1 right join lateral (select t1.a) dt on true) from t1")
+	require.True(t, core.ErrUnknownColumn.Equal(err))
+	tk.MustQuery("select * from t1 right join lateral (select t2.b from t2 where t2.a = 4) dt on t1.a = dt.b").Check(
+		testkit.Rows("<nil> <nil> 40"))
+	// The lateral derived table can not be referred by the preceding tables.
+	err = tk.ExecToErr("select * from lateral (select t2.b from t2 where t2.a = t1.a) dt, t1")
+	require.True(t, core.ErrUnknownColumn.Equal(err))
+}
+
+func TestHypoIndex(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(10))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, 'c%d')", i, i%10, i))
+	}
+	tk.MustExec("analyze table t")
+	h := dom.StatsHandle()
+	tk.MustQuery("select * from t where a = 1 and c = 'c1'").Check(testkit.Rows("1 1 c1"))
+	require.NoError(t, h.LoadNeededHistograms())
+
+	tk.MustExec("create index idx_a on t(a) type hypo")
+	tk.MustExec("create index idx_bc on t(b, c) type hypo comment 'what if'")
+	err := tk.ExecToErr("create index idx_a on t(b) type hypo")
+	require.True(t, dbterror.ErrDupKeyName.Equal(err))
+	tk.MustExec("create index if not exists idx_a on t(b) type hypo")
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.GetWarnings(), 1)
+	err = tk.ExecToErr("create index idx_e on t((a + 1)) type hypo")
+	require.True(t, dbterror.ErrUnsupportedIndexType.Equal(err))
+
+	// The hypothetical indexes are used by explain.
+	// The estimation is derived from the column statistics.
+	tk.MustQuery("explain format = 'brief' select * from t where a = 1").Check(testkit.Rows(
+		"IndexLookUp 1.00 root  ",
+		"├─IndexRangeScan(Build) 1.00 cop[tikv] table:t, index:idx_a(a) range:[1,1], keep order:false",
+		"└─TableRowIDScan(Probe) 1.00 cop[tikv] table:t keep order:false"))
+	rows := tk.MustQuery("explain format = 'brief' select * from t where b = 1 and c = 'c1'").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "index:idx_bc(b, c)")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// They are never used by the statements which are executed.
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 1 c1"))
+	rows = tk.MustQuery("explain analyze select * from t where a = 1").Rows()
+	require.NotContains(t, fmt.Sprintf("%v", rows), "idx_a")
+	tk.MustQuery("show index from t").Check(testkit.Rows())
+
+	// They are only visible to the session which creates them.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	rows = tk2.MustQuery("explain format = 'brief' select * from t where a = 1").Rows()
+	require.NotContains(t, fmt.Sprintf("%v", rows), "idx_a")
+
+	tk.MustExec("drop index idx_a on t")
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1").Rows()
+	require.NotContains(t, fmt.Sprintf("%v", rows), "idx_a")
+	err = tk.ExecToErr("drop index idx_a on t")
+	require.True(t, dbterror.ErrCantDropFieldOrKey.Equal(err))
+}
+
+func TestGroupingSetsPlan(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int)")
+
+	tk.MustQuery("explain format = 'brief' select count(*) from t group by grouping sets ((a), (b), (a, a))").Check(testkit.Rows(
+		"HashAgg 8001.00 root  group by:Column#5, Column#6, Column#7, funcs:count(1)->Column#8",
+		"└─Expand 30000.00 root  grouping sets:[[test.t.a], [test.t.b], [test.t.a]], grouping id:Column#7",
+		"  └─TableReader 10000.00 root  data:TableFullScan",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	rows := tk.MustQuery("explain format = 'brief' select a, b, grouping(a, b) from t group by cube(a, b)").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "grouping(Column#7, 0, 1, 2, 3)")
+
+	// Create virtual tiflash replica info.
+	dom := domain.GetDomain(tk.Session())
+	is := dom.InfoSchema()
+	db, exists := is.SchemaByName(model.NewCIStr("test"))
+	require.True(t, exists)
+	for _, tblInfo := range db.Tables {
+		if tblInfo.Name.L == "t" {
+			tblInfo.TiFlashReplica = &model.TiFlashReplicaInfo{
+				Count:     1,
+				Available: true,
+			}
+		}
+	}
+	tk.MustExec("set @@session.tidb_isolation_read_engines = 'tiflash'")
+	tk.MustExec("set @@session.tidb_enforce_mpp = 1")
+	tk.MustQuery("explain format = 'brief' select a, b, count(c) from t group by a, b with rollup").Check(testkit.Rows(
+		"TableReader 8001.00 root  data:ExchangeSender",
+		"└─ExchangeSender 8001.00 mpp[tiflash]  ExchangeType: PassThrough",
+		"  └─Projection 8001.00 mpp[tiflash]  Column#5, Column#6, Column#8",
+		"    └─Projection 8001.00 mpp[tiflash]  Column#8, Column#5, Column#6",
+		"      └─HashAgg 8001.00 mpp[tiflash]  group by:Column#5, Column#6, Column#7, funcs:sum(Column#15)->Column#8, funcs:firstrow(Column#5)->Column#5, funcs:firstrow(Column#6)->Column#6",
+		"        └─ExchangeReceiver 8001.00 mpp[tiflash]  ",
+		"          └─ExchangeSender 8001.00 mpp[tiflash]  ExchangeType: HashPartition, Hash Cols: [name: Column#5, collate: binary], [name: Column#6, collate: binary], [name: Column#7, collate: binary]",
+		"            └─HashAgg 8001.00 mpp[tiflash]  group by:Column#5, Column#6, Column#7, funcs:count(test.t.c)->Column#15",
+		"              └─Expand 30000.00 mpp[tiflash]  grouping sets:[[test.t.a, test.t.b], [test.t.a], []], grouping id:Column#7",
+		"                └─TableFullScan 10000.00 mpp[tiflash] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+}
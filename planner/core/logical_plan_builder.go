@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -51,6 +52,7 @@ import (
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/table/temptable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -61,6 +63,7 @@ import (
 	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/plancodec"
 	"github.com/pingcap/tidb/util/set"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -177,7 +180,7 @@ func (a *aggOrderByResolver) Leave(inNode ast.Node) (ast.Node, bool) {
 }
 
 func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr, gbyItems []expression.Expression,
-	correlatedAggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, map[int]int, error) {
+	groupingSets [][]int, correlatedAggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, map[int]int, error) {
 	b.optFlag |= flagBuildKeyInfo
 	b.optFlag |= flagPushDownAgg
 	// We may apply aggregation eliminate optimization.
@@ -193,6 +196,21 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 		b.optFlag |= flagSkewDistinctAgg
 	}
 
+	var expand *LogicalExpand
+	if groupingSets != nil {
+		var err error
+		expand, err = b.buildExpand(p, gbyItems, groupingSets)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = expand
+		gbyItems = make([]expression.Expression, 0, len(expand.GroupingCols)+1)
+		for _, col := range expand.GroupingCols {
+			gbyItems = append(gbyItems, col)
+		}
+		gbyItems = append(gbyItems, expand.GroupingIDCol)
+	}
+
 	plan4Agg := LogicalAggregation{AggFuncs: make([]*aggregation.AggFuncDesc, 0, len(aggFuncList))}.Init(b.ctx, b.getSelectOffset())
 	if hint := b.TableHints(); hint != nil {
 		plan4Agg.aggHints = hint.aggHints
@@ -213,7 +231,13 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 			p = np
 			newArgList = append(newArgList, newArg)
 		}
-		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgList, aggFunc.Distinct)
+		var newFunc *aggregation.AggFuncDesc
+		var err error
+		if aggFunc.F == ast.AggFuncGrouping {
+			newFunc, err = b.buildGroupingFunc(expand, newArgList)
+		} else {
+			newFunc, err = aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgList, aggFunc.Distinct)
+		}
 		if err != nil {
 			return nil, nil, err
 		}
@@ -278,6 +302,14 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 		}
 	}
 	for i, col := range p.Schema().Columns {
+		if expand != nil {
+			// The group by columns are read from their copies produced by Expand, which are NULL in the rows of
+			// the grouping sets that don't contain them.
+			if expand.isGeneratedCol(col) {
+				continue
+			}
+			col = expand.groupingColOf(col)
+		}
 		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
 		if err != nil {
 			return nil, nil, err
@@ -329,6 +361,161 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 	return plan4Agg, aggIndexMap, nil
 }
 
+// maxGroupingSets is the max number of grouping sets of a GROUP BY clause.
+const maxGroupingSets = 4096
+
+// buildGroupingSets returns the offsets of the group by items in every grouping set of the GROUP BY clause, it
+// returns nil if the clause doesn't use grouping sets.
+func buildGroupingSets(gby *ast.GroupByClause) ([][]int, error) {
+	n := len(gby.Items)
+	var sets [][]int
+	switch gby.Tp {
+	case ast.GroupingSetsRollup:
+		sets = make([][]int, 0, n+1)
+		for i := n; i >= 0; i-- {
+			set := make([]int, 0, i)
+			for j := 0; j < i; j++ {
+				set = append(set, j)
+			}
+			sets = append(sets, set)
+		}
+	case ast.GroupingSetsCube:
+		if n >= bits.Len(maxGroupingSets) {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("more than %d grouping sets", maxGroupingSets))
+		}
+		sets = make([][]int, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			set := make([]int, 0, bits.OnesCount(uint(mask)))
+			for j := 0; j < n; j++ {
+				if mask&(1<<(n-1-j)) != 0 {
+					set = append(set, j)
+				}
+			}
+			sets = append(sets, set)
+		}
+	case ast.GroupingSetsList:
+		if len(gby.GroupingSets) > maxGroupingSets {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("more than %d grouping sets", maxGroupingSets))
+		}
+		sets = gby.GroupingSets
+	}
+	return sets, nil
+}
+
+// buildExpand builds the LogicalExpand which duplicates the rows of p for every grouping set, the grouping sets
+// are the offsets of gbyItems.
+func (b *PlanBuilder) buildExpand(p LogicalPlan, gbyItems []expression.Expression, groupingSets [][]int) (*LogicalExpand, error) {
+	expand := LogicalExpand{}.Init(b.ctx, b.getSelectOffset())
+	// srcOffsets maps the offset of a group by item to the offset of its column in SourceCols.
+	srcOffsets := make([]int, 0, len(gbyItems))
+	for _, item := range gbyItems {
+		col, ok := item.(*expression.Column)
+		if !ok {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("non-column expressions in GROUP BY with grouping sets")
+		}
+		offset := -1
+		for i, srcCol := range expand.SourceCols {
+			if srcCol.Equal(nil, col) {
+				offset = i
+				break
+			}
+		}
+		if offset < 0 {
+			offset = len(expand.SourceCols)
+			expand.SourceCols = append(expand.SourceCols, col)
+			tp := col.RetType.Clone()
+			tp.DelFlag(mysql.NotNullFlag)
+			expand.GroupingCols = append(expand.GroupingCols, &expression.Column{
+				UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+				RetType:  tp,
+			})
+		}
+		srcOffsets = append(srcOffsets, offset)
+	}
+	expand.GroupingSets = make([][]int, 0, len(groupingSets))
+	for _, set := range groupingSets {
+		offsets := make([]int, 0, len(set))
+		for _, i := range set {
+			if !slices.Contains(offsets, srcOffsets[i]) {
+				offsets = append(offsets, srcOffsets[i])
+			}
+		}
+		expand.GroupingSets = append(expand.GroupingSets, offsets)
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	tp.SetFlag(mysql.NotNullFlag)
+	expand.GroupingIDCol = &expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  tp,
+	}
+
+	schema := p.Schema().Clone()
+	names := make(types.NameSlice, 0, schema.Len()+len(expand.GroupingCols)+1)
+	names = append(names, p.OutputNames()...)
+	for _, col := range expand.GroupingCols {
+		schema.Append(col)
+		names = append(names, types.EmptyName)
+	}
+	schema.Append(expand.GroupingIDCol)
+	names = append(names, types.EmptyName)
+	expand.SetChildren(p)
+	expand.SetSchema(schema)
+	expand.names = names
+	return expand, nil
+}
+
+// maxGroupingArgs is the max number of arguments of the GROUPING function.
+const maxGroupingArgs = 64
+
+// buildGroupingFunc builds the GROUPING function as firstrow(grouping(gid, r0, r1, ...)), where ri is the result of
+// the function for the i-th grouping set. The bit for the k-th of n arguments is 1 << (n-1-k), it is set if the
+// argument is not in the grouping set.
+func (b *PlanBuilder) buildGroupingFunc(expand *LogicalExpand, args []expression.Expression) (*aggregation.AggFuncDesc, error) {
+	if expand == nil {
+		return nil, ErrInvalidGroupFuncUse
+	}
+	if len(args) > maxGroupingArgs {
+		return nil, expression.ErrIncorrectParameterCount.GenWithStackByArgs(ast.Grouping)
+	}
+	argOffsets := make([]int, 0, len(args))
+	for k, arg := range args {
+		offset := -1
+		if col, ok := arg.(*expression.Column); ok {
+			for i, srcCol := range expand.SourceCols {
+				if srcCol.Equal(nil, col) {
+					offset = i
+					break
+				}
+			}
+		}
+		if offset < 0 {
+			return nil, ErrFieldInGroupingNotGroupBy.GenWithStackByArgs(k + 1)
+		}
+		argOffsets = append(argOffsets, offset)
+	}
+	funcArgs := make([]expression.Expression, 0, len(expand.GroupingSets)+1)
+	funcArgs = append(funcArgs, expand.GroupingIDCol)
+	for _, set := range expand.GroupingSets {
+		var result uint64
+		for _, offset := range argOffsets {
+			result <<= 1
+			if !slices.Contains(set, offset) {
+				result |= 1
+			}
+		}
+		resultTp := types.NewFieldType(mysql.TypeLonglong)
+		resultTp.SetFlag(mysql.UnsignedFlag | mysql.NotNullFlag)
+		funcArgs = append(funcArgs, &expression.Constant{Value: types.NewUintDatum(result), RetType: resultTp})
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	tp.SetFlag(mysql.UnsignedFlag | mysql.NotNullFlag)
+	groupingFunc, err := expression.NewFunction(b.ctx, ast.Grouping, tp, funcArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{groupingFunc}, false)
+}
+
 func (b *PlanBuilder) buildTableRefs(ctx context.Context, from *ast.TableRefsClause) (p LogicalPlan, err error) {
 	if from == nil {
 		p = b.buildTableDual()
@@ -348,6 +535,7 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 	case *ast.Join:
 		return b.buildJoin(ctx, x)
 	case *ast.TableSource:
+		// isTableName is true if the source is not a select block.
 		var isTableName bool
 		switch v := x.Source.(type) {
 		case *ast.SelectStmt:
@@ -361,6 +549,9 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.JSONTable:
+			p, err = b.buildJSONTable(ctx, v)
+			isTableName = true
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -696,10 +887,32 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
+	// A LATERAL derived table or a JSON_TABLE can refer to the columns of the preceding tables, so it is built with
+	// the left plan as the outer plan. The references are not allowed in a RIGHT JOIN, since the left plan is the
+	// inner side.
+	isLateral := false
+	if ts, ok := joinNode.Right.(*ast.TableSource); ok {
+		_, isJSONTable := ts.Source.(*ast.JSONTable)
+		isLateral = ts.Lateral || isJSONTable
+	}
+	if isLateral {
+		b.outerSchemas = append(b.outerSchemas, leftPlan.Schema().Clone())
+		b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	}
 	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	if isLateral {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}
 	if err != nil {
 		return nil, err
 	}
+	if isLateral && joinNode.Tp == ast.RightJoin {
+		if err = checkLateralRefsInRightJoin(leftPlan, rightPlan); err != nil {
+			return nil, err
+		}
+		isLateral = false
+	}
 
 	// The recursive part in CTE must not be on the right side of a LEFT JOIN.
 	if lc, ok := rightPlan.(*LogicalCTETable); ok && joinNode.Tp == ast.LeftJoin {
@@ -812,7 +1025,11 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			if isLateral {
+				sel.SetChildren(b.buildLateralApply(joinPlan))
+			} else {
+				sel.SetChildren(joinPlan)
+			}
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -822,9 +1039,141 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
+	if isLateral {
+		return b.buildLateralApply(joinPlan), nil
+	}
 	return joinPlan, nil
 }
 
+// buildLateralApply converts the join with a LATERAL derived table or a JSON_TABLE to an Apply, which will be
+// decorrelated by the decorrelate rule if possible.
+func (b *PlanBuilder) buildLateralApply(joinPlan *LogicalJoin) *LogicalApply {
+	b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+	setIsInApplyForCTE(joinPlan.children[1])
+	ap := LogicalApply{LogicalJoin: *joinPlan}.Init(b.ctx, b.getSelectOffset())
+	ap.SetChildren(joinPlan.children...)
+	return ap
+}
+
+// checkLateralRefsInRightJoin returns an error if the LATERAL derived table or the JSON_TABLE on the right side of a
+// RIGHT JOIN refers to the columns of the left side, which is the inner side of the join, like MySQL.
+func checkLateralRefsInRightJoin(leftPlan, rightPlan LogicalPlan) error {
+	for _, corCol := range ExtractCorrelatedCols4LogicalPlan(rightPlan) {
+		if idx := leftPlan.Schema().ColumnIndex(&corCol.Column); idx >= 0 {
+			name := leftPlan.OutputNames()[idx]
+			return ErrUnknownColumn.GenWithStackByArgs(name.TblName.O+"."+name.ColName.O, "from clause")
+		}
+	}
+	return nil
+}
+
+// buildJSONTable builds the LogicalJSONTable, whose document is rewritten with the preceding tables in the FROM
+// clause as the outer schemas, see buildJoin.
+func (b *PlanBuilder) buildJSONTable(ctx context.Context, jt *ast.JSONTable) (LogicalPlan, error) {
+	// The document is rewritten upon an empty plan, so the references to the preceding tables are resolved as the
+	// correlated columns.
+	dual := LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
+	dual.SetSchema(expression.NewSchema())
+	expr, np, err := b.rewrite(ctx, jt.Expr, dual, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if np != dual {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("subquery in JSON_TABLE")
+	}
+	p := LogicalJSONTable{
+		Expr: expression.BuildCastFunction(b.ctx, expr, types.NewFieldType(mysql.TypeJSON)),
+	}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema()
+	p.Path, err = b.buildJSONTablePath(jt.Path, jt.Columns, schema, &p.names)
+	if err != nil {
+		return nil, err
+	}
+	p.SetSchema(schema)
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildJSONTablePath builds the JSONTablePath of the row path or a NESTED PATH clause, and appends its columns
+// to the schema in the order of the columns in the statement.
+func (b *PlanBuilder) buildJSONTablePath(path string, columns []*ast.JSONTableColumn, schema *expression.Schema, names *types.NameSlice) (*JSONTablePath, error) {
+	pathExpr, err := json.ParseJSONPathExpr(path)
+	if err != nil {
+		return nil, err
+	}
+	jsonTablePath := &JSONTablePath{Path: path, PathExpr: pathExpr}
+	for _, column := range columns {
+		if column.Tp == ast.JSONTableColumnNested {
+			nested, err := b.buildJSONTablePath(column.Path, column.NestedColumns, schema, names)
+			if err != nil {
+				return nil, err
+			}
+			jsonTablePath.Nested = append(jsonTablePath.Nested, nested)
+			continue
+		}
+		col := &JSONTableColumn{Tp: column.Tp, Name: column.Name, Idx: schema.Len()}
+		var ft *types.FieldType
+		if column.Tp == ast.JSONTableColumnOrdinality {
+			ft = types.NewFieldType(mysql.TypeLonglong)
+			ft.AddFlag(mysql.UnsignedFlag)
+		} else {
+			ft = column.FieldType.Clone()
+			if col.PathExpr, err = json.ParseJSONPathExpr(column.Path); err != nil {
+				return nil, err
+			}
+		}
+		setJSONTableFieldType(ft)
+		if col.OnEmpty, err = buildJSONTableOnResponse(column.OnEmpty); err != nil {
+			return nil, err
+		}
+		if col.OnError, err = buildJSONTableOnResponse(column.OnError); err != nil {
+			return nil, err
+		}
+		jsonTablePath.Columns = append(jsonTablePath.Columns, col)
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  ft,
+		})
+		*names = append(*names, &types.FieldName{ColName: column.Name})
+	}
+	return jsonTablePath, nil
+}
+
+// setJSONTableFieldType fills the charset, collation, length and decimal of the column type if they are not
+// specified in the statement.
+func setJSONTableFieldType(ft *types.FieldType) {
+	if ft.EvalType() == types.ETString {
+		if ft.GetCharset() == "" {
+			chs, coll := charset.GetDefaultCharsetAndCollate()
+			ft.SetCharset(chs)
+			ft.SetCollate(coll)
+		} else if ft.GetCollate() == "" {
+			coll, _ := charset.GetDefaultCollation(ft.GetCharset())
+			ft.SetCollate(coll)
+		}
+	} else {
+		types.SetBinChsClnFlag(ft)
+	}
+	flen, decimal := mysql.GetDefaultFieldLengthAndDecimal(ft.GetType())
+	if ft.GetFlen() == types.UnspecifiedLength {
+		ft.SetFlen(flen)
+	}
+	if ft.GetDecimal() == types.UnspecifiedLength {
+		ft.SetDecimal(decimal)
+	}
+}
+
+func buildJSONTableOnResponse(response *ast.JSONTableOnResponse) (res JSONTableOnResponse, err error) {
+	if response == nil {
+		return JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}, nil
+	}
+	res.Tp = response.Tp
+	if response.Tp == ast.JSONTableOnResponseDefault {
+		res.Default, err = json.ParseBinaryFromString(response.Default)
+	}
+	return res, err
+}
+
 // buildUsingClause eliminate the redundant columns and ordering columns based
 // on the "USING" clause.
 //
@@ -2209,6 +2558,14 @@ func (a *havingWindowAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, o
 		if a.inWindowSpec {
 			a.popCurClause()
 		}
+	case *ast.MatchAgainst:
+		// The columns of MATCH are not ColumnNameExpr, resolve them in the same way,
+		// so that the columns missing in the select fields are appended as auxiliary fields.
+		for _, name := range v.ColumnNames {
+			if _, ok := a.Leave(&ast.ColumnNameExpr{Name: name}); !ok {
+				return node, false
+			}
+		}
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
 		if a.inAggFunc || a.inWindowFunc || a.inWindowSpec || (a.curClause == orderByClause && a.inExpr) || a.curClause == fieldList {
@@ -3924,8 +4281,15 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 	if needBuildAgg {
+		var groupingSets [][]int
+		if sel.GroupBy != nil {
+			groupingSets, err = buildGroupingSets(sel.GroupBy)
+			if err != nil {
+				return nil, err
+			}
+		}
 		var aggIndexMap map[int]int
-		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, gbyCols, correlatedAggMap)
+		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, gbyCols, groupingSets, correlatedAggMap)
 		if err != nil {
 			return nil, err
 		}
@@ -4122,7 +4486,7 @@ func getStatsTable(ctx sessionctx.Context, tblInfo *model.TableInfo, pid int64)
 			pseudoEstimationOutdate.Inc()
 		}
 	}
-	return statsTbl
+	return appendHypoIndexStats(ctx.GetSessionVars().StmtCtx, statsTbl, tblInfo, getHypoIndexes(ctx, tblInfo))
 }
 
 func (b *PlanBuilder) tryBuildCTE(ctx context.Context, tn *ast.TableName, asName *model.CIStr) (LogicalPlan, error) {
@@ -5324,7 +5688,7 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		foundListItem := false
 		for _, tl := range tableList {
 			if (tl.Schema.L == "" || tl.Schema.L == name.DBName.L) && (tl.Name.L == name.TblName.L) {
-				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() {
+				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() || b.isMaintainedMaterializedViewTable(tl.TableInfo) {
 					return nil, nil, false, ErrNonUpdatableTable.GenWithStackByArgs(name.TblName.O, "UPDATE")
 				}
 				foundListItem = true
@@ -5637,6 +6001,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 			if tn.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now", tn.Name.O)
 			}
+			if b.isMaintainedMaterializedViewTable(tn.TableInfo) {
+				return nil, ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "DELETE")
+			}
 			if sessionVars.User != nil {
 				authErr = ErrTableaccessDenied.FastGenByArgs("DELETE", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tb.Name.L)
 			}
@@ -5656,6 +6023,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 			if v.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now", v.Name.O)
 			}
+			if b.isMaintainedMaterializedViewTable(v.TableInfo) {
+				return nil, ErrNonUpdatableTable.GenWithStackByArgs(v.Name.O, "DELETE")
+			}
 			dbName := v.Schema.L
 			if dbName == "" {
 				dbName = b.ctx.GetSessionVars().CurrentDB
@@ -5884,7 +6254,7 @@ func (b *PlanBuilder) buildWindowFunctionFrameBound(_ context.Context, spec *ast
 		return bound, nil
 	}
 
-	if frameType == ast.Rows {
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Type == ast.CurrentRow {
 			return bound, nil
 		}
@@ -5985,8 +6355,8 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 func (b *PlanBuilder) checkWindowFuncArgs(ctx context.Context, p LogicalPlan, windowFuncExprs []*ast.WindowFuncExpr, windowAggMap map[*ast.AggregateFuncExpr]int) error {
 	checker := &expression.ParamMarkerInPrepareChecker{}
 	for _, windowFuncExpr := range windowFuncExprs {
-		if strings.ToLower(windowFuncExpr.F) == ast.AggFuncGroupConcat {
-			return ErrNotSupportedYet.GenWithStackByArgs("group_concat as window function")
+		if _, err := b.resolveWindowFuncArgs(windowFuncExpr); err != nil {
+			return err
 		}
 		args, err := b.buildArgs4WindowFunc(ctx, p, windowFuncExpr.Args, windowAggMap)
 		if err != nil {
@@ -6007,6 +6377,30 @@ func (b *PlanBuilder) checkWindowFuncArgs(ctx context.Context, p LogicalPlan, wi
 	return nil
 }
 
+// resolveWindowFuncArgs returns the arguments of the window function, followed by the ORDER BY items of GROUP_CONCAT,
+// in which the positions are resolved to the arguments.
+func (b *PlanBuilder) resolveWindowFuncArgs(windowFunc *ast.WindowFuncExpr) ([]ast.ExprNode, error) {
+	if windowFunc.Order == nil {
+		return windowFunc.Args, nil
+	}
+	args := make([]ast.ExprNode, 0, len(windowFunc.Args)+len(windowFunc.Order.Items))
+	args = append(args, windowFunc.Args...)
+	resolver := &aggOrderByResolver{
+		ctx:  b.ctx,
+		args: windowFunc.Args[:len(windowFunc.Args)-1], // the last argument is SEPARATOR, remove it.
+	}
+	for _, byItem := range windowFunc.Order.Items {
+		resolver.exprDepth = 0
+		resolver.err = nil
+		retExpr, _ := byItem.Expr.Accept(resolver)
+		if resolver.err != nil {
+			return nil, errors.Trace(resolver.err)
+		}
+		args = append(args, retExpr.(ast.ExprNode))
+	}
+	return args, nil
+}
+
 func getAllByItems(itemsBuf []*ast.ByItem, spec *ast.WindowSpec) []*ast.ByItem {
 	itemsBuf = itemsBuf[:0]
 	if spec.PartitionBy != nil {
@@ -6072,7 +6466,11 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 		args = args[:0]
 		spec, funcs := window.spec, window.funcs
 		for _, windowFunc := range funcs {
-			args = append(args, windowFunc.Args...)
+			funcArgs, err := b.resolveWindowFuncArgs(windowFunc)
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, funcArgs...)
 		}
 		np, partitionBy, orderBy, args, err := b.buildProjectionForWindow(ctx, p, spec, args, aggMap)
 		if err != nil {
@@ -6120,6 +6518,15 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 				return nil, nil, ErrWrongArguments.GenWithStackByArgs(strings.ToLower(windowFunc.F))
 			}
 			preArgs += len(windowFunc.Args)
+			if windowFunc.Order != nil {
+				for _, byItem := range windowFunc.Order.Items {
+					desc.OrderByItems = append(desc.OrderByItems, &util.ByItems{Expr: args[preArgs], Desc: byItem.Desc})
+					preArgs++
+				}
+			}
+			desc.HasDistinct = windowFunc.Distinct
+			desc.IgnoreNull = windowFunc.IgnoreNull
+			desc.FromLast = windowFunc.FromLast
 			desc.WrapCastForAggArgs(b.ctx)
 			descs = append(descs, desc)
 			windowMap[windowFunc] = schema.Len()
@@ -6141,15 +6548,6 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 // Because the grouped specification is different from them, we should especially check them before build window frame.
 func (b *PlanBuilder) checkOriginWindowFuncs(funcs []*ast.WindowFuncExpr, orderByItems []property.SortItem) error {
 	for _, f := range funcs {
-		if f.IgnoreNull {
-			return ErrNotSupportedYet.GenWithStackByArgs("IGNORE NULLS")
-		}
-		if f.Distinct {
-			return ErrNotSupportedYet.GenWithStackByArgs("<window function>(DISTINCT ..)")
-		}
-		if f.FromLast {
-			return ErrNotSupportedYet.GenWithStackByArgs("FROM LAST")
-		}
 		spec := &f.Spec
 		if f.Spec.Name.L != "" {
 			spec = b.windowSpecs[f.Spec.Name.L]
@@ -6166,9 +6564,6 @@ func (b *PlanBuilder) checkOriginWindowSpec(spec *ast.WindowSpec, orderByItems [
 	if spec.Frame == nil {
 		return nil
 	}
-	if spec.Frame.Type == ast.Groups {
-		return ErrNotSupportedYet.GenWithStackByArgs("GROUPS")
-	}
 	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
 	if start.Type == ast.Following && start.UnBounded {
 		return ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
@@ -6200,7 +6595,8 @@ func (b *PlanBuilder) checkOriginWindowFrameBound(bound *ast.FrameBound, spec *a
 	}
 
 	frameType := spec.Frame.Type
-	if frameType == ast.Rows {
+	// The bounds of the GROUPS frames are the numbers of the peer groups, which are checked as the ROWS frames.
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Unit != ast.TimeUnitInvalid {
 			return ErrWindowRowsIntervalUse.GenWithStackByArgs(getWindowName(spec.Name.O))
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -39,6 +40,7 @@ import (
 var (
 	_ LogicalPlan = &LogicalJoin{}
 	_ LogicalPlan = &LogicalAggregation{}
+	_ LogicalPlan = &LogicalExpand{}
 	_ LogicalPlan = &LogicalProjection{}
 	_ LogicalPlan = &LogicalSelection{}
 	_ LogicalPlan = &LogicalApply{}
@@ -53,6 +55,7 @@ var (
 	_ LogicalPlan = &LogicalLock{}
 	_ LogicalPlan = &LogicalLimit{}
 	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalJSONTable{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -833,6 +836,45 @@ func (la *LogicalAggregation) GetUsedCols() (usedCols []*expression.Column) {
 	return usedCols
 }
 
+// LogicalExpand duplicates every row of its child for each grouping set of GROUP BY ... WITH ROLLUP, CUBE or
+// GROUPING SETS. The aggregation above it groups the rows by GroupingCols and GroupingIDCol, so the rows of every
+// grouping set are aggregated separately.
+type LogicalExpand struct {
+	logicalSchemaProducer
+
+	// SourceCols are the distinct group by columns of the child.
+	SourceCols []*expression.Column
+	// GroupingSets are the offsets of the SourceCols kept in every grouping set.
+	GroupingSets [][]int
+	// GroupingCols are the copies of the SourceCols, they are NULL if the column is not in the grouping set of the row.
+	GroupingCols []*expression.Column
+	// GroupingIDCol is the offset of the grouping set of the row in GroupingSets.
+	GroupingIDCol *expression.Column
+}
+
+// isGeneratedCol checks whether the column is a GroupingCol or the GroupingIDCol of the Expand.
+func (p *LogicalExpand) isGeneratedCol(col *expression.Column) bool {
+	if col.Equal(nil, p.GroupingIDCol) {
+		return true
+	}
+	for _, groupingCol := range p.GroupingCols {
+		if col.Equal(nil, groupingCol) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupingColOf returns the GroupingCol copied from the column, or the column itself if it is not a SourceCol.
+func (p *LogicalExpand) groupingColOf(col *expression.Column) *expression.Column {
+	for i, srcCol := range p.SourceCols {
+		if col.Equal(nil, srcCol) {
+			return p.GroupingCols[i]
+		}
+	}
+	return col
+}
+
 // LogicalSelection represents a where or having predicate.
 type LogicalSelection struct {
 	baseLogicalPlan
@@ -1145,6 +1187,9 @@ type DataSource struct {
 	// contain unique index and the first field is tidb_shard(),
 	// such as (tidb_shard(a), a ...), the fields are more than 2
 	containExprPrefixUk bool
+
+	// cardFeedback is used to learn the cardinality correction of the pushed down conditions after execution.
+	cardFeedback *cardFeedbackInfo
 }
 
 // ExtractCorrelatedCols implements LogicalPlan interface.
@@ -1738,7 +1783,7 @@ func (p *LogicalWindow) EqualFrame(ctx sessionctx.Context, newWindow *LogicalWin
 func (p *LogicalWindow) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 	corCols := make([]*expression.CorrelatedColumn, 0, len(p.WindowFuncDescs))
 	for _, windowFunc := range p.WindowFuncDescs {
-		for _, arg := range windowFunc.Args {
+		for _, arg := range windowFunc.Exprs() {
 			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
 		}
 	}
@@ -1904,3 +1949,49 @@ func (p *LogicalCTE) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 	}
 	return corCols
 }
+
+// LogicalJSONTable represents the JSON_TABLE table function, which extracts the rows from a JSON document.
+type LogicalJSONTable struct {
+	logicalSchemaProducer
+
+	// Expr is the JSON document, it contains the correlated columns if it refers to the preceding tables in the
+	// FROM clause, and the plan is the inner child of an Apply then.
+	Expr expression.Expression
+	// Path is the row path of the JSON_TABLE.
+	Path *JSONTablePath
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
+// JSONTablePath is the row path of the JSON_TABLE, or the path of a NESTED PATH clause. Each value matched by the
+// path produces a row, and the values matched by the nested paths of it produce the rows joined with its row.
+type JSONTablePath struct {
+	Path     string
+	PathExpr json.PathExpression
+	// Columns are the columns of the path except the NESTED PATH clauses.
+	Columns []*JSONTableColumn
+	// Nested are the NESTED PATH clauses, the rows of the sibling nested paths are not joined with each other, the
+	// columns of the other siblings are NULL for the rows of a nested path.
+	Nested []*JSONTablePath
+}
+
+// JSONTableColumn is a column of the JSON_TABLE.
+type JSONTableColumn struct {
+	Tp   ast.JSONTableColumnType
+	Name model.CIStr
+	// Idx is the offset of the column in the schema of the JSON_TABLE.
+	Idx      int
+	PathExpr json.PathExpression
+	OnEmpty  JSONTableOnResponse
+	OnError  JSONTableOnResponse
+}
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of the JSON_TABLE column.
+type JSONTableOnResponse struct {
+	Tp ast.JSONTableOnResponseType
+	// Default is the value for the ast.JSONTableOnResponseDefault type.
+	Default json.BinaryJSON
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessiontxn"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+)
+
+// isMaintainedMaterializedViewTable checks whether the table is a materialized view or a change log, which can only
+// be modified by the internal statements that maintain the views.
+func (b *PlanBuilder) isMaintainedMaterializedViewTable(tblInfo *model.TableInfo) bool {
+	return (tblInfo.MaterializedView != nil || tblInfo.MaterializedViewLog != nil) && !b.ctx.GetSessionVars().InRestrictedSQL
+}
+
+// materializedViewBaseTableFinder finds a table in the query which is a base table of materialized views.
+type materializedViewBaseTableFinder struct {
+	tblInfo *model.TableInfo
+}
+
+func (f *materializedViewBaseTableFinder) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok && tn.TableInfo != nil && len(tn.TableInfo.MaterializedViewLogIDs) > 0 {
+		f.tblInfo = tn.TableInfo
+	}
+	return in, f.tblInfo != nil
+}
+
+func (f *materializedViewBaseTableFinder) Leave(in ast.Node) (ast.Node, bool) {
+	return in, f.tblInfo == nil
+}
+
+// buildSelectFromMaterializedView builds the plan to read the result of the query from a materialized view, if a
+// view has the same definition as the query and is fresh, i.e. no changes to the base tables are logged since the
+// view is refreshed. It returns nil if no view can be used.
+func (b *PlanBuilder) buildSelectFromMaterializedView(ctx context.Context, sel *ast.SelectStmt) (Plan, error) {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.EnableMaterializedViewRewrite || sessVars.InRestrictedSQL || sessVars.SnapshotTS != 0 ||
+		sessVars.StmtCtx.IsStaleness || sel.LockInfo != nil || sel.OrderBy != nil || sel.SelectIntoOpt != nil {
+		return nil, nil
+	}
+	finder := &materializedViewBaseTableFinder{}
+	sel.Accept(finder)
+	if finder.tblInfo == nil {
+		return nil, nil
+	}
+	definition, err := ddl.RestoreMaterializedViewSelect(sel)
+	if err != nil {
+		return nil, nil
+	}
+	for _, logID := range finder.tblInfo.MaterializedViewLogIDs {
+		mv, dbInfo := b.matchMaterializedView(logID, definition)
+		if mv == nil {
+			continue
+		}
+		fresh, err := b.isMaterializedViewFresh(mv.Meta().MaterializedView)
+		if err != nil {
+			return nil, err
+		}
+		if !fresh {
+			continue
+		}
+		// The view is only used when the user can read it.
+		if pm := privilege.GetPrivilegeManager(b.ctx); pm != nil &&
+			!pm.RequestVerification(sessVars.ActiveRoles, dbInfo.Name.L, mv.Meta().Name.L, "", mysql.SelectPriv) {
+			continue
+		}
+		// Whether the view is fresh depends on the time the query is executed.
+		sessVars.StmtCtx.SkipPlanCache = true
+		return b.buildMaterializedViewScan(ctx, sel, dbInfo, mv.Meta())
+	}
+	return nil, nil
+}
+
+func (b *PlanBuilder) matchMaterializedView(logID int64, definition string) (table.Table, *model.DBInfo) {
+	logTbl, ok := b.is.TableByID(logID)
+	if !ok || logTbl.Meta().MaterializedViewLog == nil {
+		return nil, nil
+	}
+	mv, ok := b.is.TableByID(logTbl.Meta().MaterializedViewLog.MViewID)
+	if !ok || mv.Meta().MaterializedView == nil || mv.Meta().MaterializedView.SelectStmt != definition {
+		return nil, nil
+	}
+	dbInfo, ok := b.is.SchemaByTable(mv.Meta())
+	if !ok {
+		return nil, nil
+	}
+	return mv, dbInfo
+}
+
+// isMaterializedViewFresh checks whether the log of the view is empty. The view isn't fresh if its base tables are
+// changed by the current transaction, because the changes are not visible to the view.
+func (b *PlanBuilder) isMaterializedViewFresh(mvInfo *model.MaterializedViewInfo) (bool, error) {
+	if mvInfo.LogTableID == 0 {
+		return false, nil
+	}
+	deltaMap := b.ctx.GetSessionVars().TxnCtx.TableDeltaMap
+	for _, baseID := range mvInfo.BaseTableIDs {
+		physicalIDs := []int64{baseID}
+		if base, ok := b.is.TableByID(baseID); ok {
+			if pi := base.Meta().GetPartitionInfo(); pi != nil {
+				for _, def := range pi.Definitions {
+					physicalIDs = append(physicalIDs, def.ID)
+				}
+			}
+		}
+		for _, id := range physicalIDs {
+			if _, ok := deltaMap[id]; ok {
+				return false, nil
+			}
+		}
+	}
+	snapshot, err := sessiontxn.GetTxnManager(b.ctx).GetSnapshotWithStmtReadTS()
+	if err != nil {
+		return false, err
+	}
+	prefix := tablecodec.GenTableRecordPrefix(mvInfo.LogTableID)
+	iter, err := snapshot.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return false, err
+	}
+	defer iter.Close()
+	return !iter.Valid(), nil
+}
+
+// buildMaterializedViewScan builds the plan to read all the rows in the view, the output names of the query are
+// kept if they're known without building the query.
+func (b *PlanBuilder) buildMaterializedViewScan(ctx context.Context, sel *ast.SelectStmt, dbInfo *model.DBInfo, mvInfo *model.TableInfo) (Plan, error) {
+	scan := &ast.SelectStmt{
+		SelectStmtOpts: &ast.SelectStmtOpts{SQLCache: true},
+		Fields:         &ast.FieldList{Fields: []*ast.SelectField{{WildCard: &ast.WildCardField{}}}},
+		From: &ast.TableRefsClause{TableRefs: &ast.Join{Left: &ast.TableSource{
+			Source: &ast.TableName{Schema: dbInfo.Name, Name: mvInfo.Name, DBInfo: dbInfo, TableInfo: mvInfo},
+		}}},
+		Kind: ast.SelectStmtKindSelect,
+	}
+	p, err := b.buildSelect(ctx, scan)
+	if err != nil {
+		return nil, err
+	}
+	names := p.OutputNames()
+	if len(names) != len(sel.Fields.Fields) {
+		return p, nil
+	}
+	for i, field := range sel.Fields.Fields {
+		if field.AsName.L != "" {
+			names[i].ColName = field.AsName
+		} else if col, ok := field.Expr.(*ast.ColumnNameExpr); ok {
+			names[i].ColName = col.Name.Name
+		}
+	}
+	return p, nil
+}
@@ -378,11 +378,44 @@ func postOptimize(sctx sessionctx.Context, plan PhysicalPlan) PhysicalPlan {
 	mergeContinuousSelections(plan)
 	plan = eliminateUnionScanAndLock(sctx, plan)
 	plan = enableParallelApply(sctx, plan)
+	plan = enablePartitionWise(sctx, plan)
 	handleFineGrainedShuffle(sctx, plan)
+	postOptimizeAdaptiveJoins(sctx, plan)
+	generateRuntimeFilters(sctx, plan)
 	checkPlanCacheable(sctx, plan)
 	return plan
 }
 
+// postOptimizeAdaptiveJoins post-optimizes the inner plans of the hash joins which the adaptive index joins switch to,
+// since they are not the children of the index joins. The outer children of the hash joins are shared with the index
+// joins, so they are reset in case they have been replaced by the passes above.
+func postOptimizeAdaptiveJoins(sctx sessionctx.Context, plan PhysicalPlan) {
+	for _, child := range plan.Children() {
+		postOptimizeAdaptiveJoins(sctx, child)
+	}
+	var join *PhysicalIndexJoin
+	switch x := plan.(type) {
+	case *PhysicalIndexJoin:
+		join = x
+	case *PhysicalIndexHashJoin:
+		join = &x.PhysicalIndexJoin
+	}
+	if join == nil || join.AdaptiveHashJoin == nil {
+		return
+	}
+	hashJoin, innerIdx := join.AdaptiveHashJoin, join.InnerChildIdx
+	hashJoin.SetChild(1-innerIdx, join.children[1-innerIdx])
+	// The apply operators in the inner plan are not made parallel, since the index join may be on the inner side of
+	// another apply.
+	innerPlan := eliminatePhysicalProjection(hashJoin.children[innerIdx])
+	innerPlan = InjectExtraProjection(innerPlan)
+	mergeContinuousSelections(innerPlan)
+	innerPlan = enablePartitionWise(sctx, innerPlan)
+	handleFineGrainedShuffle(sctx, innerPlan)
+	postOptimizeAdaptiveJoins(sctx, innerPlan)
+	hashJoin.SetChild(innerIdx, innerPlan)
+}
+
 // Only for MPP(Window<-[Sort]<-ExchangeReceiver<-ExchangeSender).
 // TiFlashFineGrainedShuffleStreamCount:
 // == 0: fine grained shuffle is disabled.
@@ -0,0 +1,308 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/mathutil"
+)
+
+// partitionWiseInfo describes a plan which can be executed partition by partition: every partition of its tables is
+// joined or aggregated on its own, and the results of all the partitions are the result of the plan.
+type partitionWiseInfo struct {
+	// pi and partCol are the partition info and the partition column of a table in the plan, all the tables in the
+	// plan are partitioned in the same way.
+	pi      *model.PartitionInfo
+	partCol *model.ColumnInfo
+	// readers are the partitioned table readers in the plan.
+	readers []PhysicalPlan
+	// keys are the columns of the plan's schema which the output rows are partitioned by, the rows having the same
+	// value of a key all come from the partitions with the same ordinal.
+	keys []*expression.Column
+}
+
+func (info *partitionWiseInfo) isKey(col *expression.Column) bool {
+	for _, key := range info.keys {
+		if key.Equal(nil, col) {
+			return true
+		}
+	}
+	return false
+}
+
+// enablePartitionWise runs the top-most hash joins and hash aggregations over the co-partitioned tables partition by
+// partition in parallel, by putting them under the PhysicalShuffle which deals out the partitions to its workers.
+func enablePartitionWise(sctx sessionctx.Context, plan PhysicalPlan) PhysicalPlan {
+	if !sctx.GetSessionVars().EnablePartitionWiseJoin || !sctx.GetSessionVars().UseDynamicPartitionPrune() {
+		return plan
+	}
+	return tryPartitionWise(sctx, plan)
+}
+
+func tryPartitionWise(sctx sessionctx.Context, plan PhysicalPlan) PhysicalPlan {
+	switch p := plan.(type) {
+	case *PhysicalHashJoin, *PhysicalHashAgg:
+		if info := getPartitionWiseInfo(p); info != nil {
+			concurrency := mathutil.Min(sctx.GetSessionVars().ExecutorConcurrency, len(info.pi.Definitions))
+			if concurrency > 1 {
+				shuffle := PhysicalShuffle{
+					Concurrency:  concurrency,
+					DataSources:  info.readers,
+					SplitterType: TablePartitionSplitterType,
+				}.Init(sctx, p.statsInfo(), p.SelectBlockOffset(), &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+				shuffle.SetChildren(p)
+				shuffle.SetCost(p.Cost())
+				return shuffle
+			}
+		}
+	case *PhysicalApply:
+		// The inner side is executed for every outer row, it's not worth running it in parallel.
+		outerIdx := 1 - p.InnerChildIdx
+		p.SetChild(outerIdx, tryPartitionWise(sctx, p.children[outerIdx]))
+		return p
+	case *PhysicalIndexJoin:
+		return tryPartitionWise4IndexJoin(sctx, p, p.InnerChildIdx)
+	case *PhysicalIndexHashJoin:
+		return tryPartitionWise4IndexJoin(sctx, p, p.InnerChildIdx)
+	case *PhysicalIndexMergeJoin:
+		return tryPartitionWise4IndexJoin(sctx, p, p.InnerChildIdx)
+	}
+	for i, child := range plan.Children() {
+		plan.SetChild(i, tryPartitionWise(sctx, child))
+	}
+	return plan
+}
+
+func tryPartitionWise4IndexJoin(sctx sessionctx.Context, p PhysicalPlan, innerChildIdx int) PhysicalPlan {
+	// The inner side is built by the inner workers of the index join, not the executor builder.
+	outerIdx := 1 - innerChildIdx
+	p.SetChild(outerIdx, tryPartitionWise(sctx, p.Children()[outerIdx]))
+	return p
+}
+
+// getPartitionWiseInfo returns the partitionWiseInfo of the plan, it returns nil if the plan can't be executed
+// partition by partition.
+func getPartitionWiseInfo(plan PhysicalPlan) *partitionWiseInfo {
+	switch p := plan.(type) {
+	case *PhysicalTableReader, *PhysicalIndexReader, *PhysicalIndexLookUpReader:
+		return getPartitionWiseInfo4Reader(p)
+	case *PhysicalSelection:
+		return getPartitionWiseInfo(p.children[0])
+	case *PhysicalProjection:
+		info := getPartitionWiseInfo(p.children[0])
+		if info == nil {
+			return nil
+		}
+		keys := make([]*expression.Column, 0, len(info.keys))
+		for i, expr := range p.Exprs {
+			if col, ok := expr.(*expression.Column); ok && info.isKey(col) {
+				keys = append(keys, p.schema.Columns[i])
+			}
+		}
+		info.keys = keys
+		return info
+	case *PhysicalHashJoin:
+		return getPartitionWiseInfo4HashJoin(p)
+	case *PhysicalHashAgg:
+		info := getPartitionWiseInfo(p.children[0])
+		if info == nil {
+			return nil
+		}
+		byKey := false
+		for _, item := range p.GroupByItems {
+			if col, ok := item.(*expression.Column); ok && info.isKey(col) {
+				byKey = true
+				break
+			}
+		}
+		if !byKey {
+			return nil
+		}
+		keys := make([]*expression.Column, 0, len(info.keys))
+		for i, aggFunc := range p.AggFuncs {
+			if aggFunc.Name != ast.AggFuncFirstRow {
+				continue
+			}
+			if col, ok := aggFunc.Args[0].(*expression.Column); ok && info.isKey(col) {
+				keys = append(keys, p.schema.Columns[i])
+			}
+		}
+		info.keys = keys
+		return info
+	}
+	return nil
+}
+
+func getPartitionWiseInfo4Reader(reader PhysicalPlan) *partitionWiseInfo {
+	var (
+		tblInfo   *model.TableInfo
+		copPlans  []PhysicalPlan
+		isPartial bool
+	)
+	switch p := reader.(type) {
+	case *PhysicalTableReader:
+		if p.StoreType != kv.TiKV {
+			return nil
+		}
+		ts, ok := p.TablePlans[0].(*PhysicalTableScan)
+		if !ok {
+			return nil
+		}
+		tblInfo, copPlans = ts.Table, p.TablePlans
+		isPartial, _ = ts.IsPartition()
+	case *PhysicalIndexReader:
+		is := p.IndexPlans[0].(*PhysicalIndexScan)
+		if is.Index.Global {
+			return nil
+		}
+		tblInfo, copPlans = is.Table, p.IndexPlans
+		isPartial, _ = is.IsPartition()
+	case *PhysicalIndexLookUpReader:
+		is := p.IndexPlans[0].(*PhysicalIndexScan)
+		if is.Index.Global || p.PushedLimit != nil {
+			return nil
+		}
+		tblInfo = is.Table
+		copPlans = append(copPlans, p.IndexPlans...)
+		copPlans = append(copPlans, p.TablePlans...)
+		isPartial, _ = is.IsPartition()
+	}
+	// Only the filters are allowed to be pushed down, the other operators like limit and aggregation are executed on
+	// all the partitions as a whole.
+	for _, copPlan := range copPlans {
+		switch copPlan.(type) {
+		case *PhysicalTableScan, *PhysicalIndexScan, *PhysicalSelection:
+		default:
+			return nil
+		}
+	}
+	if isPartial {
+		return nil
+	}
+	partCol := getPartitionWiseColumn(tblInfo)
+	if partCol == nil {
+		return nil
+	}
+	info := &partitionWiseInfo{
+		pi:      tblInfo.GetPartitionInfo(),
+		partCol: partCol,
+		readers: []PhysicalPlan{reader},
+	}
+	for _, col := range reader.Schema().Columns {
+		if col.ID == partCol.ID {
+			info.keys = append(info.keys, col)
+		}
+	}
+	return info
+}
+
+// getPartitionWiseColumn returns the partition column of the table which is hash or range partitioned on a column,
+// it returns nil if the table is partitioned in other ways.
+func getPartitionWiseColumn(tblInfo *model.TableInfo) *model.ColumnInfo {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil || (pi.Type != model.PartitionTypeHash && pi.Type != model.PartitionTypeRange) {
+		return nil
+	}
+	var name string
+	switch len(pi.Columns) {
+	case 0:
+		name = strings.Trim(pi.Expr, "`")
+	case 1:
+		name = pi.Columns[0].L
+	default:
+		return nil
+	}
+	return model.FindColumnInfo(tblInfo.Columns, strings.ToLower(name))
+}
+
+// isCoPartitioned checks whether the rows having the same value of the partition columns are put into the partitions
+// with the same ordinal.
+func (info *partitionWiseInfo) isCoPartitioned(other *partitionWiseInfo) bool {
+	pi, otherPi := info.pi, other.pi
+	if pi.Type != otherPi.Type || len(pi.Columns) != len(otherPi.Columns) || len(pi.Definitions) != len(otherPi.Definitions) {
+		return false
+	}
+	if pi.Type == model.PartitionTypeRange {
+		for i, def := range pi.Definitions {
+			otherLessThan := otherPi.Definitions[i].LessThan
+			if len(def.LessThan) != len(otherLessThan) {
+				return false
+			}
+			for j := range def.LessThan {
+				if def.LessThan[j] != otherLessThan[j] {
+					return false
+				}
+			}
+		}
+	}
+	ft, otherFt := &info.partCol.FieldType, &other.partCol.FieldType
+	return ft.GetType() == otherFt.GetType() &&
+		mysql.HasUnsignedFlag(ft.GetFlag()) == mysql.HasUnsignedFlag(otherFt.GetFlag()) &&
+		ft.GetCollate() == otherFt.GetCollate()
+}
+
+func getPartitionWiseInfo4HashJoin(p *PhysicalHashJoin) *partitionWiseInfo {
+	lInfo := getPartitionWiseInfo(p.children[0])
+	if lInfo == nil {
+		return nil
+	}
+	rInfo := getPartitionWiseInfo(p.children[1])
+	if rInfo == nil || !lInfo.isCoPartitioned(rInfo) {
+		return nil
+	}
+	if p.JoinType == AntiSemiJoin || p.JoinType == AntiLeftOuterSemiJoin || p.JoinType == LeftOuterSemiJoin {
+		// The NULL values on the inner side of `NOT IN` and `IN` affect the outer rows of all the partitions.
+		for i := range p.LeftJoinKeys {
+			if p.LeftJoinKeys[i].InOperand || p.RightJoinKeys[i].InOperand {
+				return nil
+			}
+		}
+	}
+	joinOnKey := false
+	for i := range p.LeftJoinKeys {
+		if !p.IsNullEQ[i] && lInfo.isKey(p.LeftJoinKeys[i]) && rInfo.isKey(p.RightJoinKeys[i]) {
+			joinOnKey = true
+			break
+		}
+	}
+	if !joinOnKey {
+		return nil
+	}
+	info := &partitionWiseInfo{
+		pi:      lInfo.pi,
+		partCol: lInfo.partCol,
+		readers: append(lInfo.readers, rInfo.readers...),
+	}
+	// The columns of the outer join's inner side are padded with NULL for the unmatched rows of all the partitions,
+	// so they are not the keys any more.
+	switch p.JoinType {
+	case InnerJoin:
+		info.keys = append(lInfo.keys, rInfo.keys...)
+	case RightOuterJoin:
+		info.keys = rInfo.keys
+	default:
+		info.keys = lInfo.keys
+	}
+	return info
+}
@@ -57,6 +57,7 @@ var (
 	_ PhysicalPlan = &PhysicalIndexMergeReader{}
 	_ PhysicalPlan = &PhysicalHashAgg{}
 	_ PhysicalPlan = &PhysicalStreamAgg{}
+	_ PhysicalPlan = &PhysicalExpand{}
 	_ PhysicalPlan = &PhysicalApply{}
 	_ PhysicalPlan = &PhysicalIndexJoin{}
 	_ PhysicalPlan = &PhysicalHashJoin{}
@@ -67,6 +68,7 @@ var (
 	_ PhysicalPlan = &PhysicalShuffleReceiverStub{}
 	_ PhysicalPlan = &BatchPointGetPlan{}
 	_ PhysicalPlan = &PhysicalTableSample{}
+	_ PhysicalPlan = &PhysicalJSONTable{}
 )
 
 type tableScanAndPartitionInfo struct {
@@ -489,6 +491,9 @@ type PhysicalIndexScan struct {
 	tblColHists   *statistics.HistColl
 	pkIsHandleCol *expression.Column
 	prop          *property.PhysicalProperty
+
+	// cardFeedback is not nil if the actual row count of the reader can be used to learn the cardinality correction.
+	cardFeedback *cardFeedbackInfo
 }
 
 // Clone implements PhysicalPlan interface.
@@ -591,6 +596,9 @@ type PhysicalTableScan struct {
 	tblCols     []*expression.Column
 	tblColHists *statistics.HistColl
 	prop        *property.PhysicalProperty
+
+	// cardFeedback is not nil if the actual row count of the reader can be used to learn the cardinality correction.
+	cardFeedback *cardFeedbackInfo
 }
 
 // Clone implements PhysicalPlan interface.
@@ -872,6 +880,9 @@ type PhysicalHashJoin struct {
 	// on which store the join executes.
 	storeTp        kv.StoreType
 	mppShuffleJoin bool
+
+	// RuntimeFilters are built with the build side join keys, and applied to the probe side table readers.
+	RuntimeFilters []*RuntimeFilter
 }
 
 // Clone implements PhysicalPlan interface.
@@ -884,6 +895,7 @@ func (p *PhysicalHashJoin) Clone() (PhysicalPlan, error) {
 	cloned.basePhysicalJoin = *base
 	cloned.Concurrency = p.Concurrency
 	cloned.UseOuterToBuild = p.UseOuterToBuild
+	cloned.RuntimeFilters = p.RuntimeFilters
 	for _, c := range p.EqualConditions {
 		cloned.EqualConditions = append(cloned.EqualConditions, c.Clone().(*expression.ScalarFunction))
 	}
@@ -955,6 +967,75 @@ type PhysicalIndexJoin struct {
 	// InnerHashKeys indicates the inner keys used to build hash table during
 	// execution. InnerJoinKeys is the prefix of InnerHashKeys.
 	InnerHashKeys []*expression.Column
+
+	// AdaptiveHashJoin is the hash join which the index join switches to during execution once it has read more than
+	// AdaptiveThreshold outer rows. Its outer child is the same as the one of the index join, and its inner child
+	// scans the whole inner side. It's nil if the index join is not adaptive.
+	AdaptiveHashJoin *PhysicalHashJoin
+	// AdaptiveThreshold is the number of the outer rows above which the hash join costs less than the index join.
+	AdaptiveThreshold uint64
+	// adaptiveInnerTask is the inner task of AdaptiveHashJoin.
+	adaptiveInnerTask task
+}
+
+// Clone implements PhysicalPlan interface.
+func (p *PhysicalIndexJoin) Clone() (PhysicalPlan, error) {
+	cloned := new(PhysicalIndexJoin)
+	if err := p.cloneTo(cloned, cloned); err != nil {
+		return nil, err
+	}
+	return cloned, nil
+}
+
+// cloneTo clones the index join into cloned, whose self is newSelf.
+func (p *PhysicalIndexJoin) cloneTo(cloned *PhysicalIndexJoin, newSelf PhysicalPlan) error {
+	base, err := p.basePhysicalJoin.cloneWithSelf(newSelf)
+	if err != nil {
+		return err
+	}
+	cloned.basePhysicalJoin = *base
+	cloned.innerTask = p.innerTask
+	// The ranges and the compare filters are read-only during execution, whose workers copy the constants of the
+	// compare filters, so they are shared.
+	cloned.Ranges = p.Ranges
+	cloned.KeyOff2IdxOff = append(cloned.KeyOff2IdxOff, p.KeyOff2IdxOff...)
+	cloned.IdxColLens = append(cloned.IdxColLens, p.IdxColLens...)
+	cloned.CompareFilters = p.CompareFilters
+	cloned.OuterHashKeys = cloneCols(p.OuterHashKeys)
+	cloned.InnerHashKeys = cloneCols(p.InnerHashKeys)
+	cloned.AdaptiveThreshold = p.AdaptiveThreshold
+	cloned.adaptiveInnerTask = p.adaptiveInnerTask
+	if p.AdaptiveHashJoin != nil {
+		hashJoin, err := p.AdaptiveHashJoin.Clone()
+		if err != nil {
+			return err
+		}
+		cloned.AdaptiveHashJoin = hashJoin.(*PhysicalHashJoin)
+		// The outer child is shared with the index join.
+		outerIdx := 1 - p.InnerChildIdx
+		cloned.AdaptiveHashJoin.SetChild(outerIdx, cloned.children[outerIdx])
+	}
+	return nil
+}
+
+// adaptiveInnerPlan returns the inner child of AdaptiveHashJoin, or nil if the index join is not adaptive. It's not
+// one of the children of the index join, so the plan passes which traverse the children should visit it explicitly.
+func (p *PhysicalIndexJoin) adaptiveInnerPlan() PhysicalPlan {
+	if p.AdaptiveHashJoin == nil {
+		return nil
+	}
+	return p.AdaptiveHashJoin.children[p.InnerChildIdx]
+}
+
+// getAdaptiveInnerPlan returns the inner plan of the adaptive hash join if the plan is an adaptive index join.
+func getAdaptiveInnerPlan(p PhysicalPlan) PhysicalPlan {
+	switch x := p.(type) {
+	case *PhysicalIndexJoin:
+		return x.adaptiveInnerPlan()
+	case *PhysicalIndexHashJoin:
+		return x.adaptiveInnerPlan()
+	}
+	return nil
 }
 
 // PhysicalIndexMergeJoin represents the plan of index look up merge join.
@@ -982,6 +1063,16 @@ type PhysicalIndexHashJoin struct {
 	KeepOuterOrder bool
 }
 
+// Clone implements PhysicalPlan interface.
+func (p *PhysicalIndexHashJoin) Clone() (PhysicalPlan, error) {
+	cloned := new(PhysicalIndexHashJoin)
+	if err := p.PhysicalIndexJoin.cloneTo(&cloned.PhysicalIndexJoin, cloned); err != nil {
+		return nil, err
+	}
+	cloned.KeepOuterOrder = p.KeepOuterOrder
+	return cloned, nil
+}
+
 // PhysicalMergeJoin represents merge join implementation of LogicalJoin.
 type PhysicalMergeJoin struct {
 	basePhysicalJoin
@@ -1082,6 +1173,57 @@ func (p *PhysicalLimit) Clone() (PhysicalPlan, error) {
 	return cloned, nil
 }
 
+// PhysicalExpand is the physical operator of LogicalExpand.
+type PhysicalExpand struct {
+	physicalSchemaProducer
+
+	SourceCols    []*expression.Column
+	GroupingSets  [][]int
+	GroupingCols  []*expression.Column
+	GroupingIDCol *expression.Column
+}
+
+// Clone implements PhysicalPlan interface.
+func (p *PhysicalExpand) Clone() (PhysicalPlan, error) {
+	cloned := new(PhysicalExpand)
+	*cloned = *p
+	base, err := p.physicalSchemaProducer.cloneWithSelf(cloned)
+	if err != nil {
+		return nil, err
+	}
+	cloned.physicalSchemaProducer = *base
+	cloned.SourceCols = cloneCols(p.SourceCols)
+	cloned.GroupingCols = cloneCols(p.GroupingCols)
+	cloned.GroupingIDCol = p.GroupingIDCol.Clone().(*expression.Column)
+	return cloned, nil
+}
+
+// projectionOfGroupingSet builds the projection that outputs the rows of the i-th grouping set from the child rows.
+// TiFlash can't execute the Expand, so the MPP Expand is replaced by such projections when the fragments are built.
+// The indices of the columns must have been resolved.
+func (p *PhysicalExpand) projectionOfGroupingSet(i int) *PhysicalProjection {
+	childLen := p.Schema().Len() - len(p.GroupingCols) - 1
+	exprs := make([]expression.Expression, 0, p.Schema().Len())
+	for _, col := range p.Schema().Columns[:childLen] {
+		exprs = append(exprs, col)
+	}
+	kept := make([]bool, len(p.SourceCols))
+	for _, offset := range p.GroupingSets[i] {
+		kept[offset] = true
+	}
+	for j, col := range p.GroupingCols {
+		if kept[j] {
+			exprs = append(exprs, p.SourceCols[j])
+		} else {
+			exprs = append(exprs, &expression.Constant{Value: types.NewDatum(nil), RetType: col.RetType})
+		}
+	}
+	exprs = append(exprs, &expression.Constant{Value: types.NewIntDatum(int64(i)), RetType: p.GroupingIDCol.RetType})
+	proj := PhysicalProjection{Exprs: exprs}.Init(p.ctx, p.stats, p.blockOffset)
+	proj.SetSchema(p.Schema())
+	return proj
+}
+
 // PhysicalUnionAll is the physical operator of UnionAll.
 type PhysicalUnionAll struct {
 	physicalSchemaProducer
@@ -1322,6 +1464,10 @@ type PhysicalSelection struct {
 	// The flag is only used by cost model for compatibility and will be removed later.
 	// Please see https://github.com/pingcap/tidb/issues/36243 for more details.
 	fromDataSource bool
+
+	// RuntimeFilters are the filters built by the hash join at runtime. Their conditions are added into Conditions
+	// when the selection is pushed down to the storage.
+	RuntimeFilters []*RuntimeFilter
 }
 
 // Clone implements PhysicalPlan interface.
@@ -1333,6 +1479,7 @@ func (p *PhysicalSelection) Clone() (PhysicalPlan, error) {
 	}
 	cloned.basePhysicalPlan = *base
 	cloned.Conditions = cloneExprs(p.Conditions)
+	cloned.RuntimeFilters = p.RuntimeFilters
 	return cloned, nil
 }
 
@@ -1399,7 +1546,7 @@ type PhysicalWindow struct {
 func (p *PhysicalWindow) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 	corCols := make([]*expression.CorrelatedColumn, 0, len(p.WindowFuncDescs))
 	for _, windowFunc := range p.WindowFuncDescs {
-		for _, arg := range windowFunc.Args {
+		for _, arg := range windowFunc.Exprs() {
 			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
 		}
 	}
@@ -1454,6 +1601,8 @@ func (p *PhysicalWindow) Clone() (PhysicalPlan, error) {
 //    ==> Shuffle: for main thread
 //    ==> Window -> Sort(:Tail) -> shuffleWorker: for workers
 //    ==> DataSource: for `fetchDataAndSplit` thread
+// For `TablePartitionSplitterType`, there are no `Tails`, and `DataSources` are the partitioned table readers in the
+//  plan of the workers, every worker reads its own partitions of them.
 type PhysicalShuffle struct {
 	basePhysicalPlan
 
@@ -1473,6 +1622,9 @@ const (
 	PartitionHashSplitterType = iota
 	// PartitionRangeSplitterType is the splitter that split sorted data into the same range
 	PartitionRangeSplitterType
+	// TablePartitionSplitterType is the splitter deals out the table partitions to the workers, every worker reads
+	// its own partitions in the `DataSources` and executes the plan on them.
+	TablePartitionSplitterType
 )
 
 // PhysicalShuffleReceiverStub represents a receiver stub of `PhysicalShuffle`,
@@ -1648,3 +1800,21 @@ func (p *CTEDefinition) ExplainID() fmt.Stringer {
 		return "CTE_" + strconv.Itoa(p.CTE.IDForStorage)
 	})
 }
+
+// PhysicalJSONTable is the physical operator of LogicalJSONTable.
+type PhysicalJSONTable struct {
+	physicalSchemaProducer
+
+	Expr expression.Expression
+	Path *JSONTablePath
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
+// ExplainInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainInfo() string {
+	return fmt.Sprintf("doc:%s, path:%s", p.Expr.ExplainInfo(), p.Path.Path)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
@@ -707,6 +708,10 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		return b.buildLoadStats(x), nil
 	case *ast.IndexAdviseStmt:
 		return b.buildIndexAdvise(x), nil
+	case *ast.RecommendIndexStmt:
+		return b.buildRecommendIndex(x), nil
+	case *ast.RefreshMaterializedViewStmt:
+		return b.buildRefreshMaterializedView(x)
 	case *ast.PlanReplayerStmt:
 		return b.buildPlanReplayer(x), nil
 	case *ast.PrepareStmt:
@@ -715,6 +720,9 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		if x.SelectIntoOpt != nil {
 			return b.buildSelectInto(ctx, x)
 		}
+		if p, err := b.buildSelectFromMaterializedView(ctx, x); err != nil || p != nil {
+			return p, err
+		}
 		return b.buildSelect(ctx, x)
 	case *ast.SetOprStmt:
 		return b.buildSetOpr(ctx, x)
@@ -734,7 +742,9 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		*ast.BeginStmt, *ast.CommitStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
-		*ast.RenameUserStmt, *ast.NonTransactionalDeleteStmt, *ast.SetSessionStatesStmt:
+		*ast.RenameUserStmt, *ast.NonTransactionalDeleteStmt, *ast.SetSessionStatesStmt,
+		*ast.CreateRoutineStmt, *ast.DropRoutineStmt, *ast.CreateEventStmt, *ast.AlterEventStmt, *ast.DropEventStmt,
+		*ast.CreateLoadableFunctionStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -815,7 +825,7 @@ func (b *PlanBuilder) buildDo(ctx context.Context, v *ast.DoStmt) (Plan, error)
 	}
 	if needBuildAgg {
 		var aggIndexMap map[int]int
-		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, nil, nil)
+		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, nil, nil, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -1129,6 +1139,10 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// The entries of a FULLTEXT index are the tokens of the rows, it's only used by MATCH ... AGAINST.
+			if index.Tp == model.IndexTypeFulltext {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1143,6 +1157,13 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			publicPaths = append(publicPaths, &util.AccessPath{Index: index})
 		}
 	}
+	// The plans using the hypothetical indexes can't be executed, so they must not be cached.
+	if hypoIndexes := getHypoIndexes(ctx, tblInfo); len(hypoIndexes) > 0 {
+		ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
+		for _, index := range hypoIndexes {
+			publicPaths = append(publicPaths, &util.AccessPath{Index: index})
+		}
+	}
 
 	hasScanHint, hasUseOrForce := false, false
 	available := make([]*util.AccessPath, 0, len(publicPaths))
@@ -1447,6 +1468,8 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		return &Simple{Statement: as}, nil
 	case ast.AdminFlushPlanCache:
 		return &Simple{Statement: as}, nil
+	case ast.AdminResetCardinalityCorrection:
+		ret = &Simple{Statement: as}
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.AdminStmt(%T) for buildAdmin", as)
 	}
@@ -1631,6 +1654,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.Tp == model.IndexTypeFulltext {
+			// Skip checking FULLTEXT index, its entries are the tokens of the rows.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1714,6 +1741,9 @@ func (b *PlanBuilder) buildAdminCheckTable(ctx context.Context, as *ast.AdminStm
 		if idx.Meta().State != model.StatePublic {
 			return nil, errors.Errorf("index %s state %s isn't public", as.Index, idx.Meta().State)
 		}
+		if idx.Meta().Tp == model.IndexTypeFulltext {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("ADMIN CHECK INDEX on FULLTEXT index")
+		}
 		p.CheckIndex = true
 		readerPlans, indexInfos, err = b.buildPhysicalIndexLookUpReaders(ctx, tblName.Schema, tbl, []table.Index{idx})
 	} else {
@@ -1784,7 +1814,7 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && idx.Tp != model.IndexTypeFulltext {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
@@ -2089,7 +2119,7 @@ func getColOffsetForAnalyze(colsInfo []*model.ColumnInfo, colID int64) int {
 func getModifiedIndexesInfoForAnalyze(tblInfo *model.TableInfo, allColumns bool, colsInfo []*model.ColumnInfo) []*model.IndexInfo {
 	idxsInfo := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
 	for _, originIdx := range tblInfo.Indices {
-		if originIdx.State != model.StatePublic {
+		if originIdx.State != model.StatePublic || originIdx.Tp == model.IndexTypeFulltext {
 			continue
 		}
 		if allColumns {
@@ -2487,7 +2517,7 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 			}
 		}
 		idx := tblInfo.FindIndexByName(idxName.L)
-		if idx == nil || idx.State != model.StatePublic {
+		if idx == nil || idx.State != model.StatePublic || idx.Tp == model.IndexTypeFulltext {
 			return nil, ErrAnalyzeMissIndex.GenWithStackByArgs(idxName.O, tblInfo.Name.O)
 		}
 		for i, id := range physicalIDs {
@@ -2531,7 +2561,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 		return b.buildAnalyzeTable(as, opts, version)
 	}
 	for _, idx := range tblInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && idx.Tp != model.IndexTypeFulltext {
 			for i, id := range physicalIDs {
 				if id == tblInfo.ID {
 					id = -1
@@ -2816,6 +2846,18 @@ func buildSplitRegionsSchema() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildRecommendIndexFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(7)
+	schema.Append(buildColumnWithName("", "DATABASE", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "TABLE", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "INDEX_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "INDEX_COLUMNS", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "EST_BENEFIT", mysql.TypeDouble, 22))
+	schema.Append(buildColumnWithName("", "TOP_IMPACTED_QUERY", mysql.TypeVarchar, 1024))
+	schema.Append(buildColumnWithName("", "CREATE_INDEX_STATEMENT", mysql.TypeVarchar, 1024))
+	return schema.col2Schema(), schema.names
+}
+
 func buildShowDDLJobQueriesFields() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(1)
 	schema.Append(buildColumnWithName("", "QUERY", mysql.TypeVarchar, 256))
@@ -2993,6 +3035,10 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			}
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AllPrivMask, show.Table.Schema.L, show.Table.Name.L, "", err)
 		}
+	case ast.ShowTriggers, ast.ShowEvents:
+		if p.DBName == "" {
+			return nil, ErrNoDB
+		}
 	case ast.ShowConfig:
 		privErr := ErrSpecificAccessDenied.GenWithStackByArgs("CONFIG")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ConfigPriv, "", "", "", privErr)
@@ -3051,8 +3097,19 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	np = p
 	// If we have ShowPredicateExtractor, we do not buildSelection with Pattern
 	if show.Pattern != nil && buildPattern {
+		patternCol := p.OutputNames()[0].ColName
+		if show.Tp == ast.ShowProcedureStatus || show.Tp == ast.ShowFunctionStatus {
+			// The pattern of SHOW PROCEDURE/FUNCTION STATUS matches the routine name.
+			patternCol = p.OutputNames()[1].ColName
+		} else if show.Tp == ast.ShowTriggers {
+			// The pattern of SHOW TRIGGERS matches the table name.
+			patternCol = p.OutputNames()[2].ColName
+		} else if show.Tp == ast.ShowEvents {
+			// The pattern of SHOW EVENTS matches the event name.
+			patternCol = p.OutputNames()[1].ColName
+		}
 		show.Pattern.Expr = &ast.ColumnNameExpr{
-			Name: &ast.ColumnName{Name: p.OutputNames()[0].ColName},
+			Name: &ast.ColumnName{Name: patternCol},
 		}
 		np, err = b.buildSelection(ctx, np, show.Pattern, nil)
 		if err != nil {
@@ -3172,6 +3229,61 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 		}
 	case *ast.ShutdownStmt:
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ShutdownPriv, "", "", "", nil)
+	case *ast.CreateRoutineStmt:
+		// Stored functions cannot be invoked from expressions yet, so they are not created at all.
+		if raw.Type == ast.RoutineFunction {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("stored functions")
+		}
+		var err error
+		user := b.ctx.GetSessionVars().User
+		if user != nil {
+			err = ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, raw.Name.Schema.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreateRoutinePriv, raw.Name.Schema.L, "", "", err)
+		if raw.Definer.CurrentUser && user != nil {
+			raw.Definer = user
+		}
+		if user != nil && raw.Definer.String() != user.String() {
+			err = ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+		}
+	case *ast.CreateLoadableFunctionStmt:
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, "func")
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, mysql.SystemDB, "func", "", err)
+	case *ast.DropRoutineStmt:
+		if raw.Type == ast.RoutineFunction && raw.Name.Schema.L == "" {
+			if expression.IsCreatedUDF(raw.Name.Name.L) {
+				// The schema of the name is left empty, which means the loadable function is dropped.
+				var err error
+				if user := b.ctx.GetSessionVars().User; user != nil {
+					err = ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.AuthUsername, user.AuthHostname, "func")
+				}
+				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, mysql.SystemDB, "func", "", err)
+				break
+			}
+			currentDB := b.ctx.GetSessionVars().CurrentDB
+			if currentDB == "" {
+				return nil, ErrNoDB
+			}
+			raw.Name.Schema = model.NewCIStr(currentDB)
+		}
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, raw.Name.Schema.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterRoutinePriv, raw.Name.Schema.L, "", "", err)
+	case *ast.CreateEventStmt:
+		raw.Definer = b.appendEventVisitInfo(raw.Name.Schema.L, raw.Definer)
+	case *ast.AlterEventStmt:
+		raw.Definer = b.appendEventVisitInfo(raw.Name.Schema.L, raw.Definer)
+		if raw.NewName != nil {
+			b.appendEventVisitInfo(raw.NewName.Schema.L, nil)
+		}
+	case *ast.DropEventStmt:
+		b.appendEventVisitInfo(raw.Name.Schema.L, nil)
 	case *ast.BeginStmt:
 		readTS := b.ctx.GetSessionVars().TxnReadTS.PeakTxnReadTS()
 		if raw.AsOf != nil {
@@ -3242,6 +3354,28 @@ func collectVisitInfoFromRevokeStmt(sctx sessionctx.Context, vi []visitInfo, stm
 	return vi, nil
 }
 
+// appendEventVisitInfo checks the EVENT privilege on the schema of an event. The unspecified definer is filled with the
+// current user, and the SUPER privilege is required if the definer isn't the current user.
+func (b *PlanBuilder) appendEventVisitInfo(schema string, definer *auth.UserIdentity) *auth.UserIdentity {
+	var err error
+	user := b.ctx.GetSessionVars().User
+	if user != nil {
+		err = ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, schema)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.EventPriv, schema, "", "", err)
+	if definer == nil || user == nil {
+		return definer
+	}
+	if definer.CurrentUser {
+		definer = user
+	}
+	if definer.String() != user.String() {
+		err = ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+	}
+	return definer
+}
+
 // appendVisitInfoIsRestrictedUser appends additional visitInfo if the user has a
 // special privilege called "RESTRICTED_USER_ADMIN". It only applies when SEM is enabled.
 func appendVisitInfoIsRestrictedUser(visitInfo []visitInfo, sctx sessionctx.Context, user *auth.UserIdentity, priv string) []visitInfo {
@@ -3409,6 +3543,9 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 		return nil, err
 	}
+	if b.isMaintainedMaterializedViewTable(tableInfo) {
+		return nil, ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, "INSERT")
+	}
 	// Build Schema with DBName otherwise ColumnRef with DBName cannot match any Column in Schema.
 	schema, names, err := expression.TableInfo2SchemaAndNames(b.ctx, tn.Schema, tableInfo)
 	if err != nil {
@@ -3953,6 +4090,43 @@ func (b *PlanBuilder) buildIndexAdvise(node *ast.IndexAdviseStmt) Plan {
 	return p
 }
 
+func (b *PlanBuilder) buildRecommendIndex(node *ast.RecommendIndexStmt) Plan {
+	p := &RecommendIndex{
+		SQL:         node.SQL,
+		MaxMinutes:  node.MaxMinutes,
+		MaxIndexNum: node.MaxIndexNum,
+	}
+	// The workload in the statement summary contains the statements of all users.
+	if node.SQL == "" {
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ProcessPriv, "", "", "", ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS"))
+	}
+	schema, names := buildRecommendIndexFields()
+	p.SetSchema(schema)
+	p.names = names
+	return p
+}
+
+func (b *PlanBuilder) buildRefreshMaterializedView(node *ast.RefreshMaterializedViewStmt) (Plan, error) {
+	tblInfo := node.ViewName.TableInfo
+	if tblInfo.MaterializedView == nil {
+		return nil, infoschema.ErrWrongObject.GenWithStackByArgs(node.ViewName.Schema.O, tblInfo.Name.O, "MATERIALIZED VIEW")
+	}
+	if node.Method == ast.RefreshMethodFast && tblInfo.MaterializedView.FastRefresh == nil {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("fast refresh of this materialized view")
+	}
+	// Refreshing the view replaces the data in it.
+	var authErr error
+	user := b.ctx.GetSessionVars().User
+	for _, priv := range []mysql.PrivilegeType{mysql.InsertPriv, mysql.DeletePriv} {
+		if user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs(strings.ToUpper(priv.String()), user.AuthUsername,
+				user.AuthHostname, tblInfo.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, priv, node.ViewName.Schema.L, tblInfo.Name.L, "", authErr)
+	}
+	return &RefreshMaterializedView{DBName: node.ViewName.Schema, TableInfo: tblInfo, Method: node.Method}, nil
+}
+
 func (b *PlanBuilder) buildSplitRegion(node *ast.SplitRegionStmt) (Plan, error) {
 	if node.Table.TableInfo.TempTableType != model.TempTableNone {
 		return nil, ErrOptOnTemporaryTable.GenWithStackByArgs("split table")
@@ -4359,6 +4533,74 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
 				"", "", err)
 		}
+	case *ast.CreateMaterializedViewStmt:
+		// The definition is restored before the statement is rewritten when it's built.
+		definition, err := ddl.RestoreMaterializedViewSelect(v.Select)
+		if err != nil {
+			return nil, err
+		}
+		v.Definition = definition
+		plan, err := b.Build(ctx, v.Select)
+		if err != nil {
+			return nil, err
+		}
+		schema := plan.Schema()
+		if v.Cols == nil {
+			v.Cols = make([]model.CIStr, 0, schema.Len())
+			for _, name := range plan.OutputNames() {
+				v.Cols = append(v.Cols, name.ColName)
+			}
+		}
+		if len(v.Cols) != schema.Len() {
+			return nil, dbterror.ErrViewWrongList
+		}
+		v.ColTypes = make([]*types.FieldType, 0, schema.Len())
+		for _, col := range schema.Columns {
+			v.ColTypes = append(v.ColTypes, col.RetType)
+		}
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("CREATE", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
+	case *ast.DropMaterializedViewStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("DROP", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
+	case *ast.CreateTriggerStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("TRIGGER", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.TriggerPriv, v.Table.Schema.L,
+			v.Table.Name.L, "", authErr)
+		if v.Definer.CurrentUser && b.ctx.GetSessionVars().User != nil {
+			v.Definer = b.ctx.GetSessionVars().User
+		}
+		if b.ctx.GetSessionVars().User != nil && v.Definer.String() != b.ctx.GetSessionVars().User.String() {
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
+				"", "", err)
+		}
+	case *ast.DropTriggerStmt:
+		// The privilege is checked on the table of the trigger, or on the schema if the trigger doesn't exist.
+		tableName := ""
+		for _, tbl := range b.is.SchemaTables(v.Name.Schema) {
+			if tbl.Meta().FindTrigger(v.Name.Name.L) != nil {
+				tableName = tbl.Meta().Name.L
+				break
+			}
+		}
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("TRIGGER", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, tableName)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.TriggerPriv, v.Name.Schema.L,
+			tableName, "", authErr)
 	case *ast.CreateSequenceStmt:
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = ErrTableaccessDenied.GenWithStackByArgs("CREATE", b.ctx.GetSessionVars().User.AuthUsername,
@@ -4571,16 +4813,31 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 }
 
 func (b *PlanBuilder) buildSelectInto(ctx context.Context, sel *ast.SelectStmt) (Plan, error) {
-	if sem.IsEnabled() {
+	selectIntoInfo := sel.SelectIntoOpt
+	if sem.IsEnabled() && selectIntoInfo.Tp != ast.SelectIntoVars {
 		return nil, ErrNotSupportedWithSem.GenWithStackByArgs("SELECT INTO")
 	}
-	selectIntoInfo := sel.SelectIntoOpt
+	if selectIntoInfo.Tp == ast.SelectIntoVars {
+		// The local variables of the stored routines are replaced before the statement is built,
+		// so only the user variables are expected here.
+		for _, v := range selectIntoInfo.Variables {
+			if col, ok := v.(*ast.ColumnNameExpr); ok {
+				return nil, ErrSpUndeclaredVar.GenWithStackByArgs(col.Name.Name.O)
+			}
+		}
+	}
 	sel.SelectIntoOpt = nil
 	targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, sel, b.is)
 	if err != nil {
 		return nil, err
 	}
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
+	if selectIntoInfo.Tp == ast.SelectIntoVars {
+		if targetPlan.Schema().Len() != len(selectIntoInfo.Variables) {
+			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+		}
+	} else {
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
+	}
 	return &SelectInto{
 		TargetPlan: targetPlan,
 		IntoOpt:    selectIntoInfo,
@@ -4656,7 +4913,7 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 	var names []string
 	var ftypes []byte
 	switch s.Tp {
-	case ast.ShowProcedureStatus:
+	case ast.ShowProcedureStatus, ast.ShowFunctionStatus:
 		return buildShowProcedureSchema()
 	case ast.ShowTriggers:
 		return buildShowTriggerSchema()
@@ -4729,6 +4986,15 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		}
 	case ast.ShowCreateView:
 		names = []string{"View", "Create View", "character_set_client", "collation_connection"}
+	case ast.ShowCreateProcedure:
+		names = []string{"Procedure", "sql_mode", "Create Procedure", "character_set_client", "collation_connection", "Database Collation"}
+	case ast.ShowCreateFunction:
+		names = []string{"Function", "sql_mode", "Create Function", "character_set_client", "collation_connection", "Database Collation"}
+	case ast.ShowCreateTrigger:
+		names = []string{"Trigger", "sql_mode", "SQL Original Statement", "character_set_client", "collation_connection", "Database Collation", "Created"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTimestamp}
+	case ast.ShowCreateEvent:
+		names = []string{"Event", "sql_mode", "time_zone", "Create Event", "character_set_client", "collation_connection", "Database Collation"}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
 	case ast.ShowDrainerStatus:
@@ -349,6 +349,42 @@ func TestPhysicalPlanClone(t *testing.T) {
 	require.NoError(t, checkPhysicalPlanClone(mergeJoin))
 }
 
+func TestAdaptiveIndexJoinClone(t *testing.T) {
+	ctx := mock.NewContext()
+	stats := &property.StatsInfo{RowCount: 1000}
+	schema := expression.NewSchema(&expression.Column{RetType: types.NewFieldType(mysql.TypeLonglong)})
+	newSelection := func() PhysicalPlan {
+		return PhysicalSelection{}.Init(ctx, stats, 0)
+	}
+	outer, inner, adaptiveInner := newSelection(), newSelection(), newSelection()
+	hashJoin := PhysicalHashJoin{}.Init(ctx, stats, 0)
+	hashJoin.SetSchema(schema)
+	hashJoin.SetChildren(outer, adaptiveInner)
+	join := PhysicalIndexJoin{AdaptiveHashJoin: hashJoin, AdaptiveThreshold: 100}.Init(ctx, stats, 0)
+	join.SetSchema(schema)
+	join.InnerChildIdx = 1
+	join.SetChildren(outer, inner)
+	indexHashJoin := PhysicalIndexHashJoin{PhysicalIndexJoin: *join, KeepOuterOrder: true}.Init(ctx)
+
+	for _, p := range []PhysicalPlan{join, indexHashJoin} {
+		cloned, err := p.Clone()
+		require.NoError(t, err)
+		clonedJoin, ok := cloned.(*PhysicalIndexJoin)
+		if !ok {
+			require.True(t, cloned.(*PhysicalIndexHashJoin).KeepOuterOrder)
+			clonedJoin = &cloned.(*PhysicalIndexHashJoin).PhysicalIndexJoin
+		}
+		require.Equal(t, uint64(100), clonedJoin.AdaptiveThreshold)
+		require.NotNil(t, clonedJoin.AdaptiveHashJoin)
+		require.NotSame(t, hashJoin, clonedJoin.AdaptiveHashJoin)
+		// The cloned hash join shares the cloned outer child with the cloned index join.
+		require.NotSame(t, outer, clonedJoin.children[0])
+		require.Same(t, clonedJoin.children[0], clonedJoin.AdaptiveHashJoin.children[0])
+		require.NotSame(t, adaptiveInner, clonedJoin.AdaptiveHashJoin.children[1])
+		require.Same(t, clonedJoin.AdaptiveHashJoin.children[1], getAdaptiveInnerPlan(cloned))
+	}
+}
+
 //go:linkname valueInterface reflect.valueInterface
 func valueInterface(v reflect.Value, safe bool) interface{}
 
@@ -213,6 +213,67 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.flag |= inCreateOrDropTable
 		p.checkCreateViewGrammar(node)
 		p.checkCreateViewWithSelectGrammar(node)
+	case *ast.CreateMaterializedViewStmt:
+		p.stmtTp = TypeCreate
+		p.flag |= inCreateOrDropTable
+		p.checkCreateMaterializedViewGrammar(node)
+	case *ast.DropMaterializedViewStmt:
+		p.stmtTp = TypeDrop
+		p.flag |= inCreateOrDropTable
+	case *ast.CreateRoutineStmt:
+		p.stmtTp = TypeCreate
+		p.resolveRoutineName(node.Name)
+		if p.err == nil {
+			p.checkCreateRoutineGrammar(node)
+		}
+		// The tables referenced by the routine body may not exist yet.
+		return in, true
+	case *ast.DropRoutineStmt:
+		p.stmtTp = TypeDrop
+		// An unqualified function name may be a loadable function, it's resolved by the plan builder.
+		if node.Type != ast.RoutineFunction || node.Name.Schema.L != "" {
+			p.resolveRoutineName(node.Name)
+		}
+		return in, true
+	case *ast.CreateLoadableFunctionStmt:
+		p.stmtTp = TypeCreate
+	case *ast.CreateTriggerStmt:
+		p.stmtTp = TypeCreate
+		p.resolveRoutineName(node.Name)
+		if p.err == nil {
+			p.resolveRoutineName(node.Table)
+		}
+		if p.err == nil {
+			p.checkCreateTriggerGrammar(node)
+		}
+		// The tables referenced by the trigger body may not exist yet.
+		return in, true
+	case *ast.DropTriggerStmt:
+		p.stmtTp = TypeDrop
+		p.resolveRoutineName(node.Name)
+		return in, true
+	case *ast.CreateEventStmt:
+		p.stmtTp = TypeCreate
+		p.resolveRoutineName(node.Name)
+		if p.err == nil {
+			p.checkEventBodyGrammar(node.Body)
+		}
+		// The tables referenced by the event body may not exist yet.
+		return in, true
+	case *ast.AlterEventStmt:
+		p.stmtTp = TypeAlter
+		p.resolveRoutineName(node.Name)
+		if p.err == nil && node.NewName != nil {
+			p.resolveRoutineName(node.NewName)
+		}
+		if p.err == nil && node.Body != nil {
+			p.checkEventBodyGrammar(node.Body)
+		}
+		return in, true
+	case *ast.DropEventStmt:
+		p.stmtTp = TypeDrop
+		p.resolveRoutineName(node.Name)
+		return in, true
 	case *ast.DropTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.stmtTp = TypeDrop
@@ -241,6 +302,11 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.stmtTp = TypeShow
 		p.showTp = node.Tp
 		p.resolveShowStmt(node)
+		if node.Tp == ast.ShowCreateProcedure || node.Tp == ast.ShowCreateFunction || node.Tp == ast.ShowCreateTrigger ||
+			node.Tp == ast.ShowCreateEvent {
+			p.resolveRoutineName(node.Table)
+			return in, true
+		}
 	case *ast.SetOprSelectList:
 		p.checkSetOprSelectList(node)
 	case *ast.DeleteTableList:
@@ -487,7 +553,7 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		p.flag &= ^inCreateOrDropTable
 		p.checkAutoIncrement(x)
 		p.checkContainDotColumn(x)
-	case *ast.CreateViewStmt:
+	case *ast.CreateViewStmt, *ast.CreateMaterializedViewStmt, *ast.DropMaterializedViewStmt:
 		p.flag &= ^inCreateOrDropTable
 	case *ast.DropTableStmt, *ast.AlterTableStmt, *ast.RenameTableStmt:
 		p.flag &= ^inCreateOrDropTable
@@ -916,6 +982,366 @@ func (p *preprocessor) checkCreateViewWithSelectGrammar(stmt *ast.CreateViewStmt
 	}
 }
 
+func (p *preprocessor) checkCreateMaterializedViewGrammar(stmt *ast.CreateMaterializedViewStmt) {
+	vName := stmt.ViewName.Name.String()
+	if isIncorrectName(vName) {
+		p.err = dbterror.ErrWrongTableName.GenWithStackByArgs(vName)
+		return
+	}
+	for _, col := range stmt.Cols {
+		if isIncorrectName(col.String()) {
+			p.err = dbterror.ErrWrongColumnName.GenWithStackByArgs(col)
+			return
+		}
+	}
+	p.checkCreateViewWithSelectGrammar(&ast.CreateViewStmt{Select: stmt.Select})
+}
+
+// resolveRoutineName fills the schema of a stored routine name with the current database.
+func (p *preprocessor) resolveRoutineName(tn *ast.TableName) {
+	if tn.Schema.L == "" {
+		currentDB := p.ctx.GetSessionVars().CurrentDB
+		if currentDB == "" {
+			p.err = errors.Trace(ErrNoDB)
+			return
+		}
+		tn.Schema = model.NewCIStr(currentDB)
+	}
+	if isIncorrectName(tn.Name.O) {
+		p.err = ErrSpWrongName.GenWithStackByArgs(tn.Name.O)
+	}
+}
+
+func (p *preprocessor) checkCreateRoutineGrammar(stmt *ast.CreateRoutineStmt) {
+	checker := &routineChecker{routine: stmt}
+	scope := newRoutineScope()
+	for _, param := range stmt.Params {
+		if _, ok := scope.vars[param.Name.L]; ok {
+			p.err = ErrSpDupParam.GenWithStackByArgs(param.Name.O)
+			return
+		}
+		scope.vars[param.Name.L] = struct{}{}
+	}
+	checker.scopes = append(checker.scopes, scope)
+	if p.err = checker.checkStmt(stmt.Body); p.err != nil {
+		return
+	}
+	if stmt.Type == ast.RoutineFunction && !checker.hasReturn {
+		p.err = ErrSpNoReturn.GenWithStackByArgs(stmt.Name.Name.O)
+	}
+}
+
+func (p *preprocessor) checkEventBodyGrammar(body ast.StmtNode) {
+	checker := &routineChecker{event: true, scopes: []routineScope{newRoutineScope()}}
+	p.err = checker.checkStmt(body)
+}
+
+func (p *preprocessor) checkCreateTriggerGrammar(stmt *ast.CreateTriggerStmt) {
+	checker := &routineChecker{trigger: stmt, scopes: []routineScope{newRoutineScope()}}
+	if p.err = checker.checkStmt(stmt.Body); p.err != nil {
+		return
+	}
+	// The columns of the NEW and OLD rows are checked if the table exists, otherwise the DDL executor reports the
+	// missing table.
+	var tblInfo *model.TableInfo
+	if tbl, err := p.ensureInfoSchema().TableByName(stmt.Table.Schema, stmt.Table.Name); err == nil {
+		tblInfo = tbl.Meta()
+	}
+	rowChecker := &triggerRowChecker{trigger: stmt, tblInfo: tblInfo}
+	stmt.Body.Accept(rowChecker)
+	p.err = rowChecker.err
+}
+
+// triggerRowChecker checks the references to the NEW and OLD rows in a trigger body.
+type triggerRowChecker struct {
+	trigger *ast.CreateTriggerStmt
+	tblInfo *model.TableInfo
+	err     error
+}
+
+// checkRow checks the column of the NEW or OLD row, row is empty if the name doesn't refer to a trigger row.
+func (c *triggerRowChecker) checkRow(row, col string, assign bool) error {
+	switch {
+	case row == "new" && c.trigger.Event == model.TriggerDelete:
+		return ErrTrgNoSuchRowInTrg.GenWithStackByArgs("NEW", "DELETE")
+	case row == "old" && c.trigger.Event == model.TriggerInsert:
+		return ErrTrgNoSuchRowInTrg.GenWithStackByArgs("OLD", "INSERT")
+	case row == "old" && assign:
+		return ErrTrgCantChangeRow.GenWithStackByArgs("OLD", "")
+	case row == "new" && assign && c.trigger.Time == model.TriggerAfter:
+		return ErrTrgCantChangeRow.GenWithStackByArgs("NEW", "after ")
+	}
+	if c.tblInfo != nil && model.FindColumnInfo(c.tblInfo.Columns, strings.ToLower(col)) == nil {
+		return ErrUnknownColumn.GenWithStackByArgs(col, strings.ToUpper(row))
+	}
+	return nil
+}
+
+// Enter implements ast.Visitor interface.
+func (c *triggerRowChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ColumnNameExpr:
+		if row := x.Name.Table.L; x.Name.Schema.L == "" && (row == "new" || row == "old") {
+			c.err = c.checkRow(row, x.Name.Name.O, false)
+		}
+	case *ast.VariableAssignment:
+		// SET NEW.col = ... is parsed as an assignment to the session variable named "NEW.col".
+		if x.IsSystem && !x.IsGlobal {
+			if row, col, ok := strings.Cut(x.Name, "."); ok {
+				if row = strings.ToLower(row); row == "new" || row == "old" {
+					c.err = c.checkRow(row, col, true)
+				}
+			}
+		}
+	case *ast.SelectIntoOption:
+		for _, v := range x.Variables {
+			if col, ok := v.(*ast.ColumnNameExpr); ok && col.Name.Table.L != "" && c.err == nil {
+				c.err = c.checkRow(col.Name.Table.L, col.Name.Name.O, true)
+			}
+		}
+	}
+	return in, c.err != nil
+}
+
+// Leave implements ast.Visitor interface.
+func (c *triggerRowChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.err == nil
+}
+
+// routineScope is the set of local variables and cursors declared in a BEGIN ... END block.
+type routineScope struct {
+	vars    map[string]struct{}
+	cursors map[string]struct{}
+}
+
+func newRoutineScope() routineScope {
+	return routineScope{vars: make(map[string]struct{}), cursors: make(map[string]struct{})}
+}
+
+// routineLabel is a label of a BEGIN ... END block or a loop.
+type routineLabel struct {
+	name   string
+	isLoop bool
+}
+
+// routineChecker checks the declarations, labels and cursors of a stored routine body,
+// which are checked by MySQL when the routine is created.
+type routineChecker struct {
+	routine   *ast.CreateRoutineStmt
+	trigger   *ast.CreateTriggerStmt
+	scopes    []routineScope
+	labels    []routineLabel
+	hasReturn bool
+	// event is set when checking the body of an event, which can't create or alter events.
+	event bool
+}
+
+func (c *routineChecker) hasVar(name model.CIStr) bool {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if _, ok := c.scopes[i].vars[name.L]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *routineChecker) hasCursor(name model.CIStr) error {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if _, ok := c.scopes[i].cursors[name.L]; ok {
+			return nil
+		}
+	}
+	return ErrSpCursorMismatch.GenWithStackByArgs(name.O)
+}
+
+func (c *routineChecker) pushLabel(label model.CIStr, isLoop bool) error {
+	if label.L == "" {
+		return nil
+	}
+	for _, l := range c.labels {
+		if l.name == label.L {
+			return ErrSpLabelRedefine.GenWithStackByArgs(label.O)
+		}
+	}
+	c.labels = append(c.labels, routineLabel{name: label.L, isLoop: isLoop})
+	return nil
+}
+
+func (c *routineChecker) popLabel(label model.CIStr) {
+	if label.L != "" {
+		c.labels = c.labels[:len(c.labels)-1]
+	}
+}
+
+func (c *routineChecker) checkStmts(stmts []ast.StmtNode) error {
+	for _, stmt := range stmts {
+		if err := c.checkStmt(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *routineChecker) checkLoop(label model.CIStr, stmts []ast.StmtNode) error {
+	if err := c.pushLabel(label, true); err != nil {
+		return err
+	}
+	if err := c.checkStmts(stmts); err != nil {
+		return err
+	}
+	c.popLabel(label)
+	return nil
+}
+
+func (c *routineChecker) checkBlock(block *ast.ProcedureBlockStmt) error {
+	if err := c.pushLabel(block.Label, false); err != nil {
+		return err
+	}
+	scope := newRoutineScope()
+	c.scopes = append(c.scopes, scope)
+	// The variables must be declared before the cursors, and the cursors before the handlers.
+	const (
+		declVar = iota
+		declCursor
+		declHandler
+	)
+	phase := declVar
+	for _, decl := range block.Decls {
+		switch x := decl.(type) {
+		case *ast.ProcedureVarDeclStmt:
+			if phase > declVar {
+				return ErrSpVarCondAfterCursor.GenWithStackByArgs()
+			}
+			for _, name := range x.Names {
+				if _, ok := scope.vars[name.L]; ok {
+					return ErrSpDupVar.GenWithStackByArgs(name.O)
+				}
+				scope.vars[name.L] = struct{}{}
+			}
+		case *ast.ProcedureCursorDeclStmt:
+			if phase > declCursor {
+				return ErrSpCursorAfterHandler.GenWithStackByArgs()
+			}
+			phase = declCursor
+			if _, ok := scope.cursors[x.Name.L]; ok {
+				return ErrSpDupCurs.GenWithStackByArgs(x.Name.O)
+			}
+			if err := c.checkStmt(x.Select); err != nil {
+				return err
+			}
+			scope.cursors[x.Name.L] = struct{}{}
+		case *ast.ProcedureHandlerDeclStmt:
+			phase = declHandler
+			// A handler can not jump to the labels outside of it.
+			labels := c.labels
+			c.labels = nil
+			err := c.checkStmt(x.Stmt)
+			c.labels = labels
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.checkStmts(block.Stmts); err != nil {
+		return err
+	}
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.popLabel(block.Label)
+	return nil
+}
+
+func (c *routineChecker) checkStmt(stmt ast.StmtNode) error {
+	// The stored functions and the triggers can't return result sets or end the transaction.
+	restricted := ""
+	if c.trigger != nil {
+		restricted = "trigger"
+	} else if c.routine != nil && c.routine.Type == ast.RoutineFunction {
+		restricted = "function"
+	}
+	switch x := stmt.(type) {
+	case *ast.ProcedureBlockStmt:
+		return c.checkBlock(x)
+	case *ast.ProcedureIfStmt:
+		for _, branch := range x.Branches {
+			if err := c.checkStmts(branch.Stmts); err != nil {
+				return err
+			}
+		}
+		return c.checkStmts(x.Else)
+	case *ast.ProcedureCaseStmt:
+		for _, when := range x.WhenClauses {
+			if err := c.checkStmts(when.Stmts); err != nil {
+				return err
+			}
+		}
+		return c.checkStmts(x.Else)
+	case *ast.ProcedureWhileStmt:
+		return c.checkLoop(x.Label, x.Stmts)
+	case *ast.ProcedureRepeatStmt:
+		return c.checkLoop(x.Label, x.Stmts)
+	case *ast.ProcedureLoopStmt:
+		return c.checkLoop(x.Label, x.Stmts)
+	case *ast.ProcedureJumpStmt:
+		keyword := "LEAVE"
+		if x.Tp == ast.ProcedureIterate {
+			keyword = "ITERATE"
+		}
+		for i := len(c.labels) - 1; i >= 0; i-- {
+			if c.labels[i].name == x.Label.L && (x.Tp == ast.ProcedureLeave || c.labels[i].isLoop) {
+				return nil
+			}
+		}
+		return ErrSpLilabelMismatch.GenWithStackByArgs(keyword, x.Label.O)
+	case *ast.ProcedureOpenStmt:
+		return c.hasCursor(x.Cursor)
+	case *ast.ProcedureCloseStmt:
+		return c.hasCursor(x.Cursor)
+	case *ast.ProcedureFetchStmt:
+		if err := c.hasCursor(x.Cursor); err != nil {
+			return err
+		}
+		for _, v := range x.Vars {
+			if !c.hasVar(v) {
+				return ErrSpUndeclaredVar.GenWithStackByArgs(v.O)
+			}
+		}
+	case *ast.ProcedureReturnStmt:
+		if restricted != "function" {
+			return ErrSpBadReturn.GenWithStackByArgs()
+		}
+		c.hasReturn = true
+	case *ast.SelectStmt:
+		if x.SelectIntoOpt == nil {
+			if restricted != "" {
+				return ErrSpNoRetset.GenWithStackByArgs(restricted)
+			}
+			break
+		}
+		for _, v := range x.SelectIntoOpt.Variables {
+			if col, ok := v.(*ast.ColumnNameExpr); ok && col.Name.Table.L == "" && !c.hasVar(col.Name.Name) {
+				return ErrSpUndeclaredVar.GenWithStackByArgs(col.Name.Name.O)
+			}
+		}
+	case *ast.CreateEventStmt:
+		if c.event {
+			return ErrEventRecursionForbidden
+		}
+	case *ast.AlterEventStmt:
+		if c.event && x.Body != nil {
+			return ErrEventRecursionForbidden
+		}
+	case *ast.SetOprStmt, *ast.ShowStmt, *ast.ExplainStmt:
+		if restricted != "" {
+			return ErrSpNoRetset.GenWithStackByArgs(restricted)
+		}
+	case *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, ast.DDLNode:
+		if restricted != "" {
+			return ErrCommitNotAllowedInSfOrTrg.GenWithStackByArgs()
+		}
+	}
+	return nil
+}
+
 func (p *preprocessor) checkDropSequenceGrammar(stmt *ast.DropSequenceStmt) {
 	p.checkDropTableNames(stmt.Sequences)
 }
@@ -80,6 +80,11 @@ func (p *PhysicalHashJoin) ResolveIndices() (err error) {
 	if err != nil {
 		return err
 	}
+	return p.resolveJoinIndices()
+}
+
+// resolveJoinIndices resolves the indices of the join conditions, the children should have been resolved.
+func (p *PhysicalHashJoin) resolveJoinIndices() (err error) {
 	lSchema := p.children[0].Schema()
 	rSchema := p.children[1].Schema()
 	for i, fun := range p.EqualConditions {
@@ -222,6 +227,14 @@ func (p *PhysicalIndexJoin) ResolveIndices() (err error) {
 		}
 		p.OuterHashKeys[i], p.InnerHashKeys[i] = outerKey.(*expression.Column), innerKey.(*expression.Column)
 	}
+	if p.AdaptiveHashJoin != nil {
+		// The outer child is shared with the index join and has been resolved above.
+		err = p.AdaptiveHashJoin.children[p.InnerChildIdx].ResolveIndices()
+		if err != nil {
+			return err
+		}
+		err = p.AdaptiveHashJoin.resolveJoinIndices()
+	}
 	return
 }
 
@@ -446,6 +459,32 @@ func (p *NominalSort) ResolveIndices() (err error) {
 	return resolveIndicesForSort(p.basePhysicalPlan)
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalExpand) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	childSchema := p.children[0].Schema()
+	for i := 0; i < p.Schema().Len()-len(p.GroupingCols)-1; i++ {
+		newCol, err := p.Schema().Columns[i].ResolveIndices(childSchema)
+		if err != nil {
+			return err
+		}
+		p.Schema().Columns[i] = newCol.(*expression.Column)
+	}
+	sourceCols := make([]*expression.Column, 0, len(p.SourceCols))
+	for _, col := range p.SourceCols {
+		newCol, err := col.ResolveIndices(childSchema)
+		if err != nil {
+			return err
+		}
+		sourceCols = append(sourceCols, newCol.(*expression.Column))
+	}
+	p.SourceCols = sourceCols
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalWindow) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -481,6 +520,12 @@ func (p *PhysicalWindow) ResolveIndices() (err error) {
 				return err
 			}
 		}
+		for _, byItem := range desc.OrderByItems {
+			byItem.Expr, err = byItem.Expr.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
 	}
 	if p.Frame != nil {
 		for i := range p.Frame.Start.CalcFuncs {
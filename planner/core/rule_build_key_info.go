@@ -126,6 +126,13 @@ func (p *LogicalLimit) BuildKeyInfo(selfSchema *expression.Schema, childSchema [
 	}
 }
 
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalExpand) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	// The keys of the child are not unique anymore because the rows are duplicated for every grouping set.
+	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	selfSchema.Keys = nil
+}
+
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalTopN) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
@@ -499,6 +499,28 @@ func (p *LogicalLock) PruneColumns(parentUsedCols []*expression.Column, opt *log
 	return p.children[0].PruneColumns(parentUsedCols, opt)
 }
 
+// PruneColumns implements LogicalPlan interface.
+// The grouping columns and the grouping id column are always kept, so the source columns are always used.
+func (p *LogicalExpand) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
+	child := p.children[0]
+	childUsedCols := make([]*expression.Column, 0, len(parentUsedCols)+len(p.SourceCols))
+	for _, col := range parentUsedCols {
+		if child.Schema().Contains(col) {
+			childUsedCols = append(childUsedCols, col)
+		}
+	}
+	childUsedCols = append(childUsedCols, p.SourceCols...)
+	err := child.PruneColumns(childUsedCols, opt)
+	if err != nil {
+		return err
+	}
+
+	p.SetSchema(child.Schema().Clone())
+	p.Schema().Append(p.GroupingCols...)
+	p.Schema().Append(p.GroupingIDCol)
+	return nil
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column, opt *logicalOptimizeOp) error {
 	windowColumns := p.GetWindowResultColumns()
@@ -530,7 +552,7 @@ func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column, opt *l
 
 func (p *LogicalWindow) extractUsedCols(parentUsedCols []*expression.Column) []*expression.Column {
 	for _, desc := range p.WindowFuncDescs {
-		for _, arg := range desc.Args {
+		for _, arg := range desc.Exprs() {
 			parentUsedCols = append(parentUsedCols, expression.ExtractColumns(arg)...)
 		}
 	}
@@ -288,7 +288,7 @@ func (lt *LogicalTopN) replaceExprColumns(replace map[string]*expression.Column)
 
 func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column) {
 	for _, desc := range p.WindowFuncDescs {
-		for _, arg := range desc.Args {
+		for _, arg := range desc.Exprs() {
 			ResolveExprAndReplace(arg, replace)
 		}
 	}
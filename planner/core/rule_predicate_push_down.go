@@ -562,6 +562,14 @@ func (p *LogicalLimit) PredicatePushDown(predicates []expression.Expression, opt
 	return predicates, p
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalExpand) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
+	// The predicates pushed down by the aggregation refer to the grouping columns, which are generated by Expand, so
+	// they are kept above it.
+	p.baseLogicalPlan.PredicatePushDown(nil, opt)
+	return predicates, p
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (p *LogicalMaxOneRow) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	// MaxOneRow forbids any condition to push down.
@@ -0,0 +1,245 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+)
+
+// RuntimeFilter is a filter built by a hash join with the values of a join key of its build side. It's applied to the
+// rows scanned by the table reader of the probe side, so the probe side rows which can't be joined are filtered out
+// as early as possible.
+// The hash join and the selection pushed down to the probe side table reader share the same RuntimeFilter.
+type RuntimeFilter struct {
+	// ID identifies the filter in a statement.
+	ID int
+	// KeyIdx is the index of the join key in the equal conditions of the hash join.
+	KeyIdx int
+	// BuildCol is the join key of the build side.
+	BuildCol *expression.Column
+	// ProbeCol is the column of the table scan of the probe side which the filter is applied to.
+	ProbeCol *expression.Column
+}
+
+func explainRuntimeFilters(rfs []*RuntimeFilter, isProbeSide bool) string {
+	buffer := new(strings.Builder)
+	buffer.WriteString("runtime filter:[")
+	for i, rf := range rfs {
+		if i != 0 {
+			buffer.WriteString(" ")
+		}
+		col := rf.BuildCol
+		if isProbeSide {
+			col = rf.ProbeCol
+		}
+		fmt.Fprintf(buffer, "rf%d(%s)", rf.ID, col.String())
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
+
+// generateRuntimeFilters marks the hash joins which can build the runtime filters, and pushes the filters down to the
+// table readers of their probe sides. The conditions built from the filters are merged into the selection above the
+// table scan, so an empty selection is added if there is none.
+func generateRuntimeFilters(sctx sessionctx.Context, plan PhysicalPlan) {
+	if !sctx.GetSessionVars().EnableRuntimeFilter {
+		return
+	}
+	g := &runtimeFilterGenerator{sctx: sctx}
+	g.generate(plan)
+}
+
+type runtimeFilterGenerator struct {
+	sctx  sessionctx.Context
+	rfNum int
+}
+
+func (g *runtimeFilterGenerator) generate(plan PhysicalPlan) {
+	if join, ok := plan.(*PhysicalHashJoin); ok {
+		g.generateForHashJoin(join)
+	}
+	// The pushed down plans of the readers are not visited, since the joins in them are executed by the storage.
+	for _, child := range plan.Children() {
+		g.generate(child)
+	}
+	// The hash join which an adaptive index join switches to shares the outer side with the index join, which reads
+	// the outer side before the hash join is built, so only its inner plan is visited.
+	if innerPlan := getAdaptiveInnerPlan(plan); innerPlan != nil {
+		g.generate(innerPlan)
+	}
+}
+
+func (g *runtimeFilterGenerator) generateForHashJoin(join *PhysicalHashJoin) {
+	buildIdx, probeIdx := join.InnerChildIdx, 1-join.InnerChildIdx
+	if join.UseOuterToBuild {
+		buildIdx, probeIdx = probeIdx, buildIdx
+	}
+	// Only the probe side rows which can't be joined are filtered out, so the joins that output the unmatched
+	// probe side rows are excluded.
+	switch join.JoinType {
+	case InnerJoin:
+	case SemiJoin:
+		if join.UseOuterToBuild {
+			return
+		}
+	case LeftOuterJoin, RightOuterJoin:
+		if !join.UseOuterToBuild {
+			return
+		}
+	default:
+		return
+	}
+	// The filter can hardly filter out any row if the build side is not smaller than the probe side.
+	if join.children[buildIdx].StatsCount() >= join.children[probeIdx].StatsCount() {
+		return
+	}
+	buildKeys, probeKeys := join.LeftJoinKeys, join.RightJoinKeys
+	if buildIdx == 1 {
+		buildKeys, probeKeys = probeKeys, buildKeys
+	}
+	for i := range join.EqualConditions {
+		if join.IsNullEQ[i] || !runtimeFilterTypeMatch(buildKeys[i].RetType, probeKeys[i].RetType) {
+			continue
+		}
+		reader, probeCol := findRuntimeFilterTarget(join.children[probeIdx], probeKeys[i])
+		if reader == nil {
+			continue
+		}
+		sel, scanCol := g.getRuntimeFilterSelection(reader, probeCol)
+		if sel == nil {
+			continue
+		}
+		rf := &RuntimeFilter{
+			ID:       g.rfNum,
+			KeyIdx:   i,
+			BuildCol: buildKeys[i],
+			ProbeCol: scanCol,
+		}
+		g.rfNum++
+		join.RuntimeFilters = append(join.RuntimeFilters, rf)
+		sel.RuntimeFilters = append(sel.RuntimeFilters, rf)
+	}
+}
+
+// runtimeFilterTypeMatch checks whether the values of the build key can be compared with the ones of the probe key
+// directly, so that the min/max values and the values in the IN list can be used as constants of the probe key type.
+func runtimeFilterTypeMatch(buildTp, probeTp *types.FieldType) bool {
+	if buildTp.Hybrid() || probeTp.Hybrid() || buildTp.EvalType() != probeTp.EvalType() {
+		return false
+	}
+	switch buildTp.EvalType() {
+	case types.ETInt:
+		return mysql.HasUnsignedFlag(buildTp.GetFlag()) == mysql.HasUnsignedFlag(probeTp.GetFlag())
+	case types.ETString:
+		return buildTp.GetCollate() == probeTp.GetCollate()
+	case types.ETDecimal, types.ETDatetime:
+		return true
+	}
+	return false
+}
+
+// findRuntimeFilterTarget finds the table reader which produces the column through the selections and projections.
+func findRuntimeFilterTarget(p PhysicalPlan, col *expression.Column) (*PhysicalTableReader, *expression.Column) {
+	switch x := p.(type) {
+	case *PhysicalSelection:
+		return findRuntimeFilterTarget(x.children[0], col)
+	case *PhysicalProjection:
+		idx := x.schema.ColumnIndex(col)
+		if idx < 0 {
+			return nil, nil
+		}
+		childCol, ok := x.Exprs[idx].(*expression.Column)
+		if !ok {
+			return nil, nil
+		}
+		return findRuntimeFilterTarget(x.children[0], childCol)
+	case *PhysicalTableReader:
+		// The MPP readers are not supported, since their plans are dispatched to the storage as the fragments rather
+		// than the DAG requests whose selections the filters are added into.
+		if x.ReadReqType == MPP || x.schema.ColumnIndex(col) < 0 {
+			return nil, nil
+		}
+		return x, col
+	}
+	return nil, nil
+}
+
+// getRuntimeFilterSelection returns the selection above the table scan of the reader, and the column of the table
+// scan. The filters are only pushed down to the readers that only scan and filter the table.
+func (g *runtimeFilterGenerator) getRuntimeFilterSelection(reader *PhysicalTableReader, col *expression.Column) (*PhysicalSelection, *expression.Column) {
+	var (
+		sel  *PhysicalSelection
+		scan *PhysicalTableScan
+		ok   bool
+	)
+	switch x := reader.tablePlan.(type) {
+	case *PhysicalTableScan:
+		scan = x
+	case *PhysicalSelection:
+		if scan, ok = x.children[0].(*PhysicalTableScan); !ok {
+			return nil, nil
+		}
+		sel = x
+	default:
+		return nil, nil
+	}
+	// The temporary tables and the cached tables are not read from the storage.
+	if scan.Table.TempTableType != model.TempTableNone || scan.Table.TableCacheStatusType != model.TableCacheStatusDisable {
+		return nil, nil
+	}
+	idx := scan.schema.ColumnIndex(col)
+	// The virtual generated columns are computed after the rows are read.
+	if idx < 0 || idx >= len(scan.Columns) || scan.Columns[idx].IsGenerated() && !scan.Columns[idx].GeneratedStored {
+		return nil, nil
+	}
+	scanCol := scan.schema.Columns[idx].Clone().(*expression.Column)
+	scanCol.Index = idx
+	storeType := scan.StoreType
+	if storeType == kv.UnSpecified {
+		storeType = reader.StoreType
+	}
+	if !canPushDownRuntimeFilter(g.sctx, scanCol, storeType) {
+		return nil, nil
+	}
+	if sel == nil {
+		sel = PhysicalSelection{}.Init(g.sctx, scan.statsInfo(), scan.SelectBlockOffset())
+		sel.SetChildren(scan)
+		reader.tablePlan = sel
+		reader.TablePlans = flattenPushDownPlan(sel)
+	}
+	return sel, scanCol
+}
+
+// canPushDownRuntimeFilter checks whether the conditions built from the filter can be pushed down to the storage.
+func canPushDownRuntimeFilter(sctx sessionctx.Context, col *expression.Column, storeType kv.StoreType) bool {
+	conds := make([]expression.Expression, 0, 3)
+	for _, funcName := range []string{ast.GE, ast.LE, ast.In} {
+		cond, err := expression.NewFunction(sctx, funcName, types.NewFieldType(mysql.TypeLonglong), col, col)
+		if err != nil {
+			return false
+		}
+		conds = append(conds, cond)
+	}
+	return expression.CanExprsPushDown(sctx.GetSessionVars().StmtCtx, conds, sctx.GetClient(), storeType)
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
@@ -30,6 +31,9 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -85,6 +89,16 @@ func (p *LogicalShow) DeriveStats(_ []*property.StatsInfo, selfSchema *expressio
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalJSONTable) DeriveStats(_ []*property.StatsInfo, selfSchema *expression.Schema, _ []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	// The number of the rows is unknown until the document is evaluated, just use a fake count.
+	p.stats = getFakeStats(selfSchema)
+	return p.stats, nil
+}
+
 func getFakeStats(schema *expression.Schema) *property.StatsInfo {
 	profile := &property.StatsInfo{
 		RowCount: 1,
@@ -215,9 +229,55 @@ func (ds *DataSource) getGroupNDVs(colGroups [][]*expression.Column) []property.
 			}
 		}
 	}
+	// The NDVs of column groups can also be got from the extended stats if no index matches them.
+	for _, item := range tbl.ColGroupStats {
+		groupCols := slices.Clone(item.ColIDs)
+		slices.Sort(groupCols)
+		if slices.IndexFunc(ndvs, func(ndv property.GroupNDV) bool { return slices.Equal(ndv.Cols, groupCols) }) >= 0 {
+			continue
+		}
+		// The column groups, e.g. the group-by items of an aggregation, are not always sorted, so they are
+		// compared with the extended stats as sets.
+		if slices.IndexFunc(colGroups, func(g []*expression.Column) bool {
+			groupIDs := make([]int64, 0, len(g))
+			for _, col := range g {
+				groupIDs = append(groupIDs, col.UniqueID)
+			}
+			slices.Sort(groupIDs)
+			return slices.Equal(groupIDs, groupCols)
+		}) < 0 {
+			continue
+		}
+		var ndv float64
+		switch item.Tp {
+		case ast.StatsTypeCardinality, ast.StatsTypeHistogram:
+			ndv = item.ScalarVals
+		case ast.StatsTypeDependency:
+			ndv = getDependencyGroupNDV(item, ds.tableStats)
+		}
+		if ndv > 0 {
+			ndvs = append(ndvs, property.GroupNDV{Cols: groupCols, NDV: ndv})
+		}
+	}
 	return ndvs
 }
 
+// getDependencyGroupNDV derives the NDV of the column group (x, y) from the functional dependency x => y
+// whose degree is d. A row of x has the most common y of x with probability d, and otherwise any y as if x
+// and y were independent, so 1/NDV(x, y) = 1/NDV(x) * (d + (1-d)/NDV(y)).
+func getDependencyGroupNDV(item *statistics.ExtendedStatsItem, stats *property.StatsInfo) float64 {
+	if len(item.ColIDs) != 2 {
+		return 0
+	}
+	xNDV, yNDV := stats.ColNDVs[item.ColIDs[0]], stats.ColNDVs[item.ColIDs[1]]
+	if xNDV <= 0 || yNDV <= 0 {
+		return 0
+	}
+	degree := math.Min(math.Max(item.ScalarVals, 0), 1)
+	ndv := xNDV / (degree + (1-degree)/yNDV)
+	return math.Max(math.Min(ndv, math.Min(xNDV*yNDV, stats.RowCount)), math.Max(xNDV, yNDV))
+}
+
 func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 	if ds.tableStats != nil {
 		// Reload GroupNDVs since colGroups may have changed.
@@ -235,6 +295,8 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 	}
 	if ds.statisticTable.Pseudo {
 		tableStats.StatsVersion = statistics.PseudoVersion
+	} else if ds.ctx.GetSessionVars().EnableExtendedStats {
+		tableStats.HistColl.ColGroupStats = ds.statisticTable.ColGroupStatsByUniqueID(ds.schema.Columns)
 	}
 	for _, col := range ds.schema.Columns {
 		tableStats.ColNDVs[col.UniqueID] = ds.getColumnNDV(col.ID)
@@ -257,6 +319,63 @@ func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths
 	return stats
 }
 
+// cardFeedbackInfo records the estimation of the pushed down conditions of a DataSource. It is attached to the physical
+// scans, and the actual row count of their reader is used to learn the cardinality correction after execution.
+type cardFeedbackInfo struct {
+	tableID      int64
+	predicates   string
+	statsVersion uint64
+	// estRows is the estimated row count before it is corrected.
+	estRows float64
+}
+
+// correctStatsByFeedback adjusts the estimated row count of the DataSource by the cardinality correction learned
+// from the query feedback. The histograms are left untouched, and the access paths are still estimated by them.
+func (ds *DataSource) correctStatsByFeedback() {
+	sessVars := ds.ctx.GetSessionVars()
+	if !sessVars.EnableCardinalityCorrection || sessVars.InRestrictedSQL || sessVars.StmtCtx.UseCache ||
+		len(ds.pushedDownConds) == 0 || ds.tableStats.RowCount <= 0 {
+		return
+	}
+	// The correlated columns are changed during execution, so the actual row count can not be attributed to the conditions.
+	for _, cond := range ds.pushedDownConds {
+		if len(expression.ExtractCorColumns(cond)) > 0 {
+			return
+		}
+	}
+	statsHandle := domain.GetDomain(ds.ctx).StatsHandle()
+	if statsHandle == nil {
+		return
+	}
+	ds.cardFeedback = &cardFeedbackInfo{
+		tableID:      ds.physicalTableID,
+		predicates:   string(expression.SortedExplainExpressionList(ds.pushedDownConds)),
+		statsVersion: ds.statisticTable.Version,
+		estRows:      ds.stats.RowCount,
+	}
+	ratio, ok := statsHandle.CardCorrectionCache().Get(ds.cardFeedback.tableID, ds.cardFeedback.predicates, ds.cardFeedback.statsVersion)
+	if !ok {
+		return
+	}
+	rowCount := math.Min(ds.stats.RowCount*ratio, ds.tableStats.RowCount)
+	stats := ds.tableStats.Scale(rowCount / ds.tableStats.RowCount)
+	stats.HistColl = ds.stats.HistColl
+	ds.stats = stats
+	if sessVars.StmtCtx.InVerboseExplain {
+		sessVars.StmtCtx.AppendNote(errors.Errorf("estimated row count of %s is corrected from %.2f to %.2f by the query feedback",
+			ds.TableInfo().Name.O, ds.cardFeedback.estRows, rowCount))
+	}
+}
+
+// getCardFeedback returns the cardFeedbackInfo for the physical scan of the DataSource. It returns nil if the scan is
+// not expected to read all the rows satisfying the conditions.
+func (ds *DataSource) getCardFeedback(prop *property.PhysicalProperty) *cardFeedbackInfo {
+	if ds.cardFeedback == nil || prop.ExpectedCnt < ds.stats.RowCount {
+		return nil
+	}
+	return ds.cardFeedback
+}
+
 // We bind logic of derivePathStats and tryHeuristics together. When some path matches the heuristic rule, we don't need
 // to derive stats of subsequent paths. In this way we can save unnecessary computation of derivePathStats.
 func (ds *DataSource) derivePathStatsAndTryHeuristics() error {
@@ -402,11 +521,18 @@ func (ds *DataSource) DeriveStats(_ []*property.StatsInfo, _ *expression.Schema,
 	// TODO: Can we move ds.deriveStatsByFilter after pruning by heuristics? In this way some computation can be avoided
 	// when ds.possibleAccessPaths are pruned.
 	ds.stats = ds.deriveStatsByFilter(ds.pushedDownConds, ds.possibleAccessPaths)
+	ds.correctStatsByFeedback()
 	err := ds.derivePathStatsAndTryHeuristics()
 	if err != nil {
 		return nil, err
 	}
 
+	// Like MySQL, the FULLTEXT index is always used for `MATCH ... AGAINST` in the WHERE clause.
+	ok, err := ds.generateFulltextIndexMergePath()
+	if err != nil || ok {
+		return ds.stats, err
+	}
+
 	// Consider the IndexMergePath. Now, we just generate `IndexMergePath` in DNF case.
 	// Use allConds instread of pushedDownConds,
 	// because we want to use IndexMerge even if some expr cannot be pushed to TiKV.
@@ -496,6 +622,79 @@ func (ds *DataSource) generateAndPruneIndexMergePath(indexMergeConds []expressio
 	return nil
 }
 
+// generateFulltextIndexMergePath replaces the access paths with an IndexMerge path if there is a
+// `MATCH ... AGAINST` filter on a FULLTEXT index of the table. Each partial path of the IndexMerge
+// path scans the index entries of a token, and the filter is evaluated on the rows in the root task.
+func (ds *DataSource) generateFulltextIndexMergePath() (bool, error) {
+	if ds.tableInfo.TempTableType != model.TempTableNone {
+		return false, nil
+	}
+	for _, cond := range ds.allConds {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.FulltextMatch {
+			continue
+		}
+		args := sf.GetArgs()
+		against, ok := args[0].(*expression.Constant)
+		if !ok {
+			continue
+		}
+		if col, ok := args[2].(*expression.Column); !ok || !ds.schema.Contains(col) {
+			continue
+		}
+		info, err := expression.GetFulltextMatchInfo(sf)
+		if err != nil {
+			return false, err
+		}
+		var idxInfo *model.IndexInfo
+		for _, idx := range ds.tableInfo.Indices {
+			if idx.ID == info.IndexID && idx.State == model.StatePublic {
+				idxInfo = idx
+				break
+			}
+		}
+		if idxInfo == nil {
+			continue
+		}
+		t, ok := fulltext.GetTokenizer(info.Parser)
+		if !ok {
+			continue
+		}
+		text, isNull, err := against.EvalString(ds.ctx, chunk.Row{})
+		if err != nil {
+			return false, err
+		}
+		if isNull {
+			continue
+		}
+		tokens, ok := info.NewQuery(t, text).AccessTokens()
+		if !ok || len(tokens) == 0 {
+			continue
+		}
+		partialPaths := make([]*util.AccessPath, 0, len(tokens))
+		for _, token := range tokens {
+			path := &util.AccessPath{Index: idxInfo}
+			if err := ds.fillIndexPath(path, nil); err != nil {
+				return false, err
+			}
+			d := fulltext.TokenDatum(token, info.Collation)
+			path.Ranges = []*ranger.Range{{
+				LowVal:    []types.Datum{d},
+				HighVal:   []types.Datum{d},
+				Collators: []collate.Collator{collate.GetCollator(info.Collation)},
+			}}
+			path.CountAfterAccess = ds.stats.RowCount
+			partialPaths = append(partialPaths, path)
+		}
+		indexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths}
+		indexMergePath.TableFilters = append(indexMergePath.TableFilters, ds.pushedDownConds...)
+		indexMergePath.CountAfterAccess = ds.stats.RowCount
+		ds.possibleAccessPaths = []*util.AccessPath{indexMergePath}
+		return true, nil
+	}
+	return false, nil
+}
+
 // DeriveStats implements LogicalPlan DeriveStats interface.
 func (ts *LogicalTableScan) DeriveStats(_ []*property.StatsInfo, _ *expression.Schema, _ []*expression.Schema, _ [][]*expression.Column) (_ *property.StatsInfo, err error) {
 	ts.Source.initStats(nil)
@@ -1198,6 +1397,30 @@ func (p *LogicalMaxOneRow) DeriveStats(_ []*property.StatsInfo, selfSchema *expr
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalExpand) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, _ []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	childProfile := childStats[0]
+	numSets := float64(len(p.GroupingSets))
+	p.stats = &property.StatsInfo{
+		RowCount: childProfile.RowCount * numSets,
+		ColNDVs:  make(map[int64]float64, selfSchema.Len()),
+	}
+	for _, col := range selfSchema.Columns {
+		if ndv, ok := childProfile.ColNDVs[col.UniqueID]; ok {
+			p.stats.ColNDVs[col.UniqueID] = ndv
+		}
+	}
+	for i, col := range p.GroupingCols {
+		// The column is NULL in the grouping sets which don't contain it.
+		p.stats.ColNDVs[col.UniqueID] = math.Min(childProfile.ColNDVs[p.SourceCols[i].UniqueID]+1, p.stats.RowCount)
+	}
+	p.stats.ColNDVs[p.GroupingIDCol.UniqueID] = numSets
+	return p.stats, nil
+}
+
 func (p *LogicalWindow) getGroupNDVs(colGroups [][]*expression.Column, childStats []*property.StatsInfo) []property.GroupNDV {
 	if len(colGroups) > 0 {
 		return childStats[0].GroupNDVs
@@ -288,6 +288,7 @@ func (p *PhysicalIndexHashJoin) attach2Task(tasks ...task) task {
 		cst: p.GetCost(outerTask.count(), innerTask.count(), outerTask.cost(), innerTask.cost(), 0),
 	}
 	p.cost = t.cost()
+	p.attachAdaptiveHashJoin(outerTask)
 	return t
 }
 
@@ -304,9 +305,37 @@ func (p *PhysicalIndexJoin) attach2Task(tasks ...task) task {
 		cst: p.GetCost(outerTask.count(), innerTask.count(), outerTask.cost(), innerTask.cost(), 0),
 	}
 	p.cost = t.cost()
+	p.attachAdaptiveHashJoin(outerTask)
 	return t
 }
 
+// attachAdaptiveHashJoin sets the children of the adaptive hash join, and computes the number of the outer rows above
+// which the hash join costs less than the index join. The cost of the index join mostly grows with the outer rows,
+// while the hash join mostly costs on scanning the whole inner side and building the hash table.
+func (p *PhysicalIndexJoin) attachAdaptiveHashJoin(outerTask task) {
+	hashJoin := p.AdaptiveHashJoin
+	if hashJoin == nil {
+		return
+	}
+	innerTask := p.adaptiveInnerTask.convertToRootTask(p.ctx)
+	lCnt, rCnt := outerTask.count(), innerTask.count()
+	if p.InnerChildIdx == 1 {
+		hashJoin.SetChildren(outerTask.plan(), innerTask.plan())
+	} else {
+		hashJoin.SetChildren(innerTask.plan(), outerTask.plan())
+		lCnt, rCnt = rCnt, lCnt
+	}
+	hashJoinCost := innerTask.cost() + hashJoin.GetCost(lCnt, rCnt, false, 0)
+	hashJoin.cost = outerTask.cost() + hashJoinCost
+
+	outerCnt := math.Max(outerTask.count(), 1)
+	threshold := outerCnt
+	if costPerOuterRow := (p.cost - outerTask.cost()) / outerCnt; costPerOuterRow > 0 {
+		threshold = math.Max(threshold, hashJoinCost/costPerOuterRow)
+	}
+	p.AdaptiveThreshold = uint64(math.Min(math.Ceil(threshold), math.MaxInt64))
+}
+
 func getAvgRowSize(stats *property.StatsInfo, schema *expression.Schema) (size float64) {
 	if stats.HistColl != nil {
 		size = stats.HistColl.GetAvgRowSizeListInDisk(schema.Columns)
@@ -1117,6 +1146,37 @@ func (p *PhysicalUnionAll) attach2Task(tasks ...task) task {
 	return t
 }
 
+func (p *PhysicalExpand) attach2Task(tasks ...task) task {
+	if mpp, ok := tasks[0].(*mppTask); ok && !mpp.invalid() {
+		// The MPP Expand is untwisted into a projection for every grouping set upon a copy of the child fragment, see
+		// untwistPlanAndRemoveUnionAll. The copies share the exchange senders of the child fragment, so the data
+		// received by the child fragment must be broadcast to every copy.
+		if !receiversAreBroadcast(mpp.p) {
+			return invalidTask
+		}
+		t := mpp.copy().(*mppTask)
+		t.addCost(t.cost() * float64(len(p.GroupingSets)-1))
+		p.cost = t.cost()
+		return attachPlan2Task(p, t)
+	}
+	t := tasks[0].convertToRootTask(p.ctx)
+	p.cost = t.cost()
+	return attachPlan2Task(p, t)
+}
+
+// receiversAreBroadcast checks whether all the exchange receivers of the fragment receive the broadcast data.
+func receiversAreBroadcast(p PhysicalPlan) bool {
+	if receiver, ok := p.(*PhysicalExchangeReceiver); ok {
+		return receiver.GetExchangeSender().ExchangeType == tipb.ExchangeType_Broadcast
+	}
+	for _, child := range p.Children() {
+		if !receiversAreBroadcast(child) {
+			return false
+		}
+	}
+	return true
+}
+
 func (sel *PhysicalSelection) attach2Task(tasks ...task) task {
 	sessVars := sel.ctx.GetSessionVars()
 	if mppTask, _ := tasks[0].(*mppTask); mppTask != nil { // always push to mpp task.
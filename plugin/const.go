@@ -26,6 +26,8 @@ const (
 	Schema
 	// Daemon indicate a plugin that can run as daemon task.
 	Daemon
+	// Function indicate a plugin that provides user-defined functions.
+	Function
 )
 
 func (k Kind) String() (str string) {
@@ -38,6 +40,8 @@ func (k Kind) String() (str string) {
 		str = "Schema"
 	case Daemon:
 		str = "Daemon"
+	case Function:
+		str = "Function"
 	}
 	return
 }
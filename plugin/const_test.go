@@ -28,6 +28,7 @@ func TestConstToString(t *testing.T) {
 		Authentication:            "Authentication",
 		Schema:                    "Schema",
 		Daemon:                    "Daemon",
+		Function:                  "Function",
 		Uninitialized:             "Uninitialized",
 		Ready:                     "Ready",
 		Dying:                     "Dying",
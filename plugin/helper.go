@@ -39,6 +39,11 @@ func DeclareDaemonManifest(m *Manifest) *DaemonManifest {
 	return (*DaemonManifest)(unsafe.Pointer(m))
 }
 
+// DeclareFunctionManifest declares manifest as FunctionManifest.
+func DeclareFunctionManifest(m *Manifest) *FunctionManifest {
+	return (*FunctionManifest)(unsafe.Pointer(m))
+}
+
 // ID present plugin identity.
 type ID string
 
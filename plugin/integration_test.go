@@ -21,6 +21,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/plugin"
@@ -28,6 +31,8 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/stretchr/testify/require"
 )
 
@@ -785,3 +790,133 @@ func loadPlugin(t *testing.T, onGeneralEvent func(context.Context, *variable.Ses
 	err = plugin.Init(ctx, cfg)
 	require.NoErrorf(t, err, "init plugin [%s] fail, error [%s]\n", pluginSign, err)
 }
+
+func TestFunctionPlugin(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	calls := 0
+	functions := []*expression.UDF{
+		{
+			Name:          "udf_add",
+			ArgTypes:      []types.EvalType{types.ETInt, types.ETInt},
+			RetType:       types.ETInt,
+			Deterministic: true,
+			VecEval: func(args []*chunk.Column, numRows int, result *chunk.Column) error {
+				result.MergeNulls(args...)
+				a, b, res := args[0].Int64s(), args[1].Int64s(), result.Int64s()
+				for i := 0; i < numRows; i++ {
+					res[i] = a[i] + b[i]
+				}
+				return nil
+			},
+		},
+		{
+			Name:          "udf_join",
+			ArgTypes:      []types.EvalType{types.ETString},
+			Variadic:      true,
+			RetType:       types.ETString,
+			Deterministic: true,
+			VecEval: func(args []*chunk.Column, numRows int, result *chunk.Column) error {
+				for i := 0; i < numRows; i++ {
+					parts := make([]string, 0, len(args))
+					for _, arg := range args {
+						if !arg.IsNull(i) {
+							parts = append(parts, arg.GetString(i))
+						}
+					}
+					result.AppendString(strings.Join(parts, "-"))
+				}
+				return nil
+			},
+		},
+		{
+			Name:    "udf_calls",
+			RetType: types.ETInt,
+			VecEval: func(args []*chunk.Column, numRows int, result *chunk.Column) error {
+				res := result.Int64s()
+				for i := 0; i < numRows; i++ {
+					calls++
+					res[i] = int64(calls)
+				}
+				return nil
+			},
+		},
+	}
+	plugin.SetTestHook(func(p *plugin.Plugin, dir string, pluginID plugin.ID) (func() *plugin.Manifest, error) {
+		return func() *plugin.Manifest {
+			m := &plugin.FunctionManifest{
+				Manifest: plugin.Manifest{
+					Kind:    plugin.Function,
+					Name:    "udf_test",
+					Version: 1,
+					OnInit: func(ctx context.Context, manifest *plugin.Manifest) error {
+						return nil
+					},
+				},
+				Functions: functions,
+			}
+			return plugin.ExportManifest(m)
+		}, nil
+	})
+	cfg := plugin.Config{Plugins: []string{"udf_test-1"}}
+	require.NoError(t, plugin.Load(context.Background(), cfg))
+	require.NoError(t, plugin.Init(context.Background(), cfg))
+	defer plugin.Shutdown(context.Background())
+
+	// The functions can't be called before they are created.
+	tk.MustGetErrCode("select udf_add(1, 2)", errno.ErrSpDoesNotExist)
+	tk.MustExec("create function udf_add returns integer soname 'udf_test'")
+	tk.MustExec("create function UDF_Join returns string soname 'udf_test-1.so'")
+	tk.MustExec("create function udf_calls returns integer soname 'udf_test.so'")
+	tk.MustQuery("select * from mysql.func order by name").Check(testkit.Rows(
+		"udf_add 2 udf_test function", "udf_calls 2 udf_test function", "udf_join 0 udf_test function"))
+
+	tk.MustExec("create table t (a int, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'x'), (2, null), (null, 'z')")
+	tk.MustQuery("select udf_add(a, 10), udf_join(b, 'y', b) from t order by a").
+		Check(testkit.Rows("<nil> z-y-z", "11 x-y-x", "12 y"))
+	tk.MustQuery("select udf_add('1', 2.4), udf_join(1)").Check(testkit.Rows("3 1"))
+	tk.MustExec("set tidb_enable_vectorized_expression = off")
+	tk.MustQuery("select udf_add(a, 10) from t where a > 1").Check(testkit.Rows("12"))
+	tk.MustExec("set tidb_enable_vectorized_expression = on")
+
+	// The deterministic functions are folded, but the others are not.
+	tk.MustQuery("explain format = 'brief' select * from t where a > udf_add(1, 2)").CheckAt([]int{0, 4}, testkit.RowsWithSep("|",
+		"TableReader|data:Selection",
+		"└─Selection|gt(test.t.a, 3)",
+		"  └─TableFullScan|keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select * from t where a > udf_calls()").CheckAt([]int{0, 4}, testkit.RowsWithSep("|",
+		"Selection|gt(test.t.a, udf_calls())",
+		"└─TableReader|data:TableFullScan",
+		"  └─TableFullScan|keep order:false, stats:pseudo"))
+
+	tk.MustGetErrCode("create function udf_add returns integer soname 'udf_test'", errno.ErrUdfExists)
+	tk.MustExec("create function if not exists udf_add returns integer soname 'udf_test'")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1125 Function 'udf_add' already exists"))
+	tk.MustGetErrCode("create function udf_none returns integer soname 'udf_test'", errno.ErrCantFindDlEntry)
+	tk.MustGetErrCode("create function udf_add returns integer soname 'udf_none'", errno.ErrCantOpenLibrary)
+	tk.MustGetErrCode("create function udf_add returns integer soname '/tmp/udf_test.so'", errno.ErrUdfNoPaths)
+	tk.MustGetErrCode("create function udf_add returns string soname 'udf_test'", errno.ErrCantInitializeUdf)
+	tk.MustGetErrCode("create function concat returns string soname 'udf_test'", errno.ErrNativeFctNameCollision)
+	tk.MustGetErrCode("select udf_add(1)", errno.ErrWrongParamcountToNativeFct)
+
+	tk.MustExec("drop function udf_add")
+	tk.MustQuery("select count(*) from mysql.func where name = 'udf_add'").Check(testkit.Rows("0"))
+	tk.MustGetErrCode("select udf_add(1, 2)", errno.ErrSpDoesNotExist)
+	tk.MustExec("drop function if exists udf_add")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1305 FUNCTION test.udf_add does not exist"))
+
+	// The functions created by the other TiDB instances are read from mysql.func when they notify the cache.
+	tk.MustExec("insert into mysql.func values ('udf_add', 2, 'udf_test', 'function')")
+	tk.MustGetErrCode("select udf_add(1, 2)", errno.ErrSpDoesNotExist)
+	dom := domain.GetDomain(tk.Session())
+	require.NoError(t, dom.NotifyUpdateUDFCache())
+	tk.MustQuery("select udf_add(1, 2)").Check(testkit.Rows("3"))
+	tk.MustExec("delete from mysql.func where name = 'udf_add'")
+	tk.MustQuery("select udf_add(1, 2)").Check(testkit.Rows("3"))
+	require.NoError(t, dom.NotifyUpdateUDFCache())
+	tk.MustGetErrCode("select udf_add(1, 2)", errno.ErrSpDoesNotExist)
+}
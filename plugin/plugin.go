@@ -24,6 +24,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -216,6 +217,18 @@ func Init(ctx context.Context, cfg Config) (err error) {
 				}
 				return
 			}
+			if kind == Function {
+				if err = expression.RegisterUDFs(p.Name, DeclareFunctionManifest(p.Manifest).Functions); err != nil {
+					if cfg.SkipWhenFail {
+						logutil.Logger(ctx).Warn("register functions of plugin failure",
+							zap.String("plugin", p.Name), zap.Error(err))
+						tiPlugins.plugins[kind][i].State = Disable
+						err = nil
+						continue
+					}
+					return
+				}
+			}
 			if p.OnFlush != nil && cfg.EtcdClient != nil {
 				const pluginWatchPrefix = "/tidb/plugins/"
 				ctx, cancel := context.WithCancel(context.Background())
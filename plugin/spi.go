@@ -18,6 +18,8 @@ import (
 	"context"
 	"reflect"
 	"unsafe"
+
+	"github.com/pingcap/tidb/expression"
 )
 
 const (
@@ -80,3 +82,11 @@ type SchemaManifest struct {
 type DaemonManifest struct {
 	Manifest
 }
+
+// FunctionManifest presents a sub-manifest that every function plugin must provide.
+type FunctionManifest struct {
+	Manifest
+	// Functions are the scalar functions provided by the plugin,
+	// they can be created by CREATE FUNCTION ... SONAME after the plugin is initialized.
+	Functions []*expression.UDF
+}
@@ -76,7 +76,7 @@ func isStringColumnType(tp byte) bool {
 	switch tp {
 	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
 		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob,
-		mysql.TypeEnum, mysql.TypeSet, mysql.TypeJSON:
+		mysql.TypeEnum, mysql.TypeSet, mysql.TypeJSON, mysql.TypeGeometry:
 		return true
 	}
 	return false
@@ -87,11 +87,13 @@ import (
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/sqlexec"
 	tlsutil "github.com/pingcap/tidb/util/tls"
 	topsqlstate "github.com/pingcap/tidb/util/topsql/state"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -293,6 +295,14 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		}
 		return err
 	}
+	compression, err := cc.negotiateCompression()
+	if err != nil {
+		logutil.Logger(ctx).Warn("negotiate compression failed", zap.Error(err))
+		if err1 := cc.writeError(ctx, err); err1 != nil {
+			logutil.Logger(ctx).Debug("writeError failed", zap.Error(err1))
+		}
+		return err
+	}
 
 	// MySQL supports an "init_connect" query, which can be run on initial connection.
 	// The query must return a non-error or the client is disconnected.
@@ -313,7 +323,7 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		data = append(data, 0, 0)
 	}
 
-	err := cc.writePacket(data)
+	err = cc.writePacket(data)
 	cc.pkt.sequence = 0
 	if err != nil {
 		err = errors.SuspendStack(err)
@@ -327,9 +337,29 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
+
+	// The packets following the handshake are compressed.
+	if compression != "" {
+		err = cc.pkt.setCompression(compression, int(variable.VarZstdLevel.Load()))
+	}
 	return err
 }
 
+// negotiateCompression chooses the compression algorithm from the ones requested by the client and allowed by
+// protocol_compression_algorithms, zstd is preferred to zlib. It returns an empty string if the packets aren't compressed.
+func (cc *clientConn) negotiateCompression() (string, error) {
+	algorithms := strings.Split(variable.VarProtocolCompressionAlgorithms.Load(), ",")
+	switch {
+	case cc.capability&mysql.ClientZstdCompressionAlgorithm > 0 && slices.Contains(algorithms, mysql.CompressionZstd):
+		return mysql.CompressionZstd, nil
+	case cc.capability&mysql.ClientCompress > 0 && slices.Contains(algorithms, mysql.CompressionZlib):
+		return mysql.CompressionZlib, nil
+	case slices.Contains(algorithms, mysql.CompressionUncompressed):
+		return "", nil
+	}
+	return "", errHandshake.GenWithStackByArgs()
+}
+
 func (cc *clientConn) Close() error {
 	cc.server.rwlock.Lock()
 	delete(cc.server.clients, cc.connectionID)
@@ -377,8 +407,17 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	data = append(data, cc.salt[0:8]...)
 	// filler [00]
 	data = append(data, 0)
+	// the compression algorithms not allowed by protocol_compression_algorithms aren't advertised.
+	capability := cc.server.capability
+	algorithms := strings.Split(variable.VarProtocolCompressionAlgorithms.Load(), ",")
+	if !slices.Contains(algorithms, mysql.CompressionZlib) {
+		capability &^= mysql.ClientCompress
+	}
+	if !slices.Contains(algorithms, mysql.CompressionZstd) {
+		capability &^= mysql.ClientZstdCompressionAlgorithm
+	}
 	// capability flag lower 2 bytes, using default capability here
-	data = append(data, byte(cc.server.capability), byte(cc.server.capability>>8))
+	data = append(data, byte(capability), byte(capability>>8))
 	// charset
 	if cc.collation == 0 {
 		cc.collation = uint8(mysql.DefaultCollationID)
@@ -388,7 +427,7 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	data = dumpUint16(data, mysql.ServerStatusAutocommit)
 	// below 13 byte may not be used
 	// capability flag upper 2 bytes, using default capability here
-	data = append(data, byte(cc.server.capability>>16), byte(cc.server.capability>>24))
+	data = append(data, byte(capability>>16), byte(capability>>24))
 	// length of auth-plugin-data
 	data = append(data, byte(len(cc.salt)+1))
 	// reserved 10 [00]
@@ -1182,6 +1221,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 		}
 		cc.addMetrics(data[0], startTime, err)
 		cc.pkt.sequence = 0
+		cc.pkt.compressedSequence = 0
 	}
 }
 
@@ -1815,7 +1855,11 @@ func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *ex
 		return err
 	}
 
-	// TODO: Write the rss []ResultSet. It will be done in another PR.
+	// The recommended indexes are returned as the notes of the statement.
+	sc := cc.ctx.GetSessionVars().StmtCtx
+	for _, idx := range indexAdviseInfo.Result.Indexes {
+		sc.AppendNote(errors.Errorf("Index Advise: %s, estimated benefit: %.2f", idx.CreateStatement(), idx.Benefit))
+	}
 	return nil
 }
 
@@ -2067,6 +2111,11 @@ func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns [
 		return true, err
 	}
 
+	if call, ok := stmt.(*ast.CallStmt); ok && cc.capability&mysql.ClientMultiResults == 0 && cc.ctx.Value(session.CallResultsVarKey) != nil {
+		cc.ctx.SetValue(session.CallResultsVarKey, nil)
+		return false, errSpBadselect.GenWithStackByArgs(call.Procedure.FnName.O)
+	}
+
 	status := cc.ctx.Status()
 	if lastStmt {
 		cc.ctx.GetSessionVars().StmtCtx.AppendWarnings(warns)
@@ -2135,9 +2184,29 @@ func (cc *clientConn) handleQuerySpecial(ctx context.Context, status uint16) (bo
 		}
 	}
 
+	callResults := cc.ctx.Value(session.CallResultsVarKey)
+	if callResults != nil {
+		handled = true
+		defer cc.ctx.SetValue(session.CallResultsVarKey, nil)
+		if err := cc.writeCallResults(ctx, callResults.([]sqlexec.RecordSet), status); err != nil {
+			return handled, err
+		}
+	}
+
 	return handled, cc.writeOkWith(ctx, cc.ctx.LastMessage(), cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), status, cc.ctx.WarningCount())
 }
 
+// writeCallResults writes the result sets of the statements in a stored procedure. They are followed by the OK
+// packet of the CALL statement, so the more results exist flag is always set.
+func (cc *clientConn) writeCallResults(ctx context.Context, results []sqlexec.RecordSet, status uint16) error {
+	for _, rs := range results {
+		if _, err := cc.writeResultset(ctx, &tidbResultSet{recordSet: rs}, false, status|mysql.ServerMoreResultsExists, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // handleFieldList returns the field list for a table.
 // The sql string is composed of a table name and a terminating character \x00.
 func (cc *clientConn) handleFieldList(ctx context.Context, sql string) (err error) {
@@ -351,6 +351,37 @@ func TestInitialHandshake(t *testing.T) {
 	require.Equal(t, expected.Bytes(), outBuffer.Bytes()[4:])
 }
 
+func TestNegotiateCompression(t *testing.T) {
+	defer variable.VarProtocolCompressionAlgorithms.Store(variable.DefProtocolCompressionAlgorithms)
+	cases := []struct {
+		algorithms  string
+		capability  uint32
+		compression string
+		err         bool
+	}{
+		{variable.DefProtocolCompressionAlgorithms, 0, "", false},
+		{variable.DefProtocolCompressionAlgorithms, mysql.ClientCompress, mysql.CompressionZlib, false},
+		{variable.DefProtocolCompressionAlgorithms, mysql.ClientZstdCompressionAlgorithm, mysql.CompressionZstd, false},
+		{variable.DefProtocolCompressionAlgorithms, mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm, mysql.CompressionZstd, false},
+		{"zlib,uncompressed", mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm, mysql.CompressionZlib, false},
+		{"zlib,uncompressed", mysql.ClientZstdCompressionAlgorithm, "", false},
+		{"zstd", mysql.ClientZstdCompressionAlgorithm, mysql.CompressionZstd, false},
+		{"zstd", mysql.ClientCompress, "", true},
+		{"zstd", 0, "", true},
+	}
+	for _, ca := range cases {
+		variable.VarProtocolCompressionAlgorithms.Store(ca.algorithms)
+		cc := &clientConn{capability: ca.capability}
+		compression, err := cc.negotiateCompression()
+		if ca.err {
+			require.True(t, errHandshake.Equal(err))
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, ca.compression, compression)
+	}
+}
+
 type dispatchInput struct {
 	com byte
 	in  []byte
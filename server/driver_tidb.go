@@ -225,6 +225,12 @@ func (tc *TiDBContext) ExecuteStmt(ctx context.Context, stmt ast.StmtNode) (Resu
 	var err error
 	if s, ok := stmt.(*ast.NonTransactionalDeleteStmt); ok {
 		rs, err = session.HandleNonTransactionalDelete(ctx, s, tc.Session)
+	} else if s, ok := stmt.(*ast.CallStmt); ok {
+		// The result sets of the procedure are written in handleQuerySpecial.
+		var rss []sqlexec.RecordSet
+		if rss, err = session.HandleCall(ctx, s, tc.Session); len(rss) > 0 {
+			tc.Session.SetValue(session.CallResultsVarKey, rss)
+		}
 	} else {
 		rs, err = tc.Session.ExecuteStmt(ctx, stmt)
 	}
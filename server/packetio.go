@@ -37,6 +37,7 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"io"
 	"time"
 
@@ -65,6 +66,16 @@ type packetIO struct {
 	maxAllowedPacket uint64
 	// accumulatedLength count the length of totally received 'payload' in readPacket.
 	accumulatedLength uint64
+
+	// compressor is set when the packets are compressed, see packetio_compression.go.
+	compressor           packetCompressor
+	compressionAlgorithm string
+	compressionLevel     int
+	compressedSequence   uint8
+	// compressedReadBuf is the decompressed data which hasn't been read.
+	compressedReadBuf []byte
+	// compressedWriteBuf is the packets which haven't been compressed.
+	compressedWriteBuf bytes.Buffer
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -90,16 +101,19 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if err := p.read(header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	sequence := header[3]
-	if sequence != p.sequence {
-		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
-	}
+	// The sequence is synchronized with the compressed sequence in the compressed protocol.
+	if p.compressor == nil {
+		sequence := header[3]
+		if sequence != p.sequence {
+			return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
+		}
 
-	p.sequence++
+		p.sequence++
+	}
 
 	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
 
@@ -115,12 +129,29 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if err := p.read(data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
 }
 
+// read fills buf with the data from the connection, the data is decompressed if the packets are compressed.
+func (p *packetIO) read(buf []byte) error {
+	if p.compressor != nil {
+		return p.readCompressed(buf)
+	}
+	_, err := io.ReadFull(p.bufReadConn, buf)
+	return err
+}
+
+// write writes the packet to the buffer, the packets are compressed later if the protocol is compressed.
+func (p *packetIO) write(data []byte) (int, error) {
+	if p.compressor != nil {
+		return len(data), p.writeCompressed(data)
+	}
+	return p.bufWriter.Write(data)
+}
+
 func (p *packetIO) setMaxAllowedPacket(maxAllowedPacket uint64) {
 	p.maxAllowedPacket = maxAllowedPacket
 }
@@ -171,7 +202,7 @@ func (p *packetIO) writePacket(data []byte) error {
 		data[1] = 0xff
 		data[2] = 0xff
 
-		if n, err := p.bufWriter.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
+		if n, err := p.write(data[:4+mysql.MaxPayloadLen]); err != nil {
 			return errors.Trace(mysql.ErrBadConn)
 		} else if n != (4 + mysql.MaxPayloadLen) {
 			return errors.Trace(mysql.ErrBadConn)
@@ -186,7 +217,7 @@ func (p *packetIO) writePacket(data []byte) error {
 	data[1] = byte(length >> 8)
 	data[2] = byte(length >> 16)
 
-	if n, err := p.bufWriter.Write(data); err != nil {
+	if n, err := p.write(data); err != nil {
 		terror.Log(errors.Trace(err))
 		return errors.Trace(mysql.ErrBadConn)
 	} else if n != len(data) {
@@ -198,6 +229,11 @@ func (p *packetIO) writePacket(data []byte) error {
 }
 
 func (p *packetIO) flush() error {
+	if p.compressor != nil {
+		if err := p.flushCompressed(); err != nil {
+			return err
+		}
+	}
 	err := p.bufWriter.Flush()
 	if err != nil {
 		return errors.Trace(err)
@@ -0,0 +1,271 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+)
+
+// Compressed Packets: https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+const (
+	// compressedHeaderLen is the length of the compressed packet header, which consists of
+	// the 3 bytes length of the payload, the 1 byte sequence and the 3 bytes length before compression.
+	compressedHeaderLen = 7
+	// minCompressLength is the minimal length of the payload to compress, the shorter ones are sent as is.
+	minCompressLength = 50
+	// zlibCompressionLevel is the level of zlib, it's the default level of zlib same as MySQL.
+	zlibCompressionLevel = 6
+)
+
+var (
+	// compressedBytesReceivedCount and compressedBytesSentCount count the bytes of the compressed packets,
+	// including the headers.
+	compressedBytesReceivedCount uint64
+	compressedBytesSentCount     uint64
+)
+
+var (
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdEncodersMu  sync.Mutex
+	zstdEncoders    = make(map[zstd.EncoderLevel]*zstd.Encoder)
+)
+
+// packetCompressor compresses and decompresses the payloads of the compressed packets.
+type packetCompressor interface {
+	compress(data []byte) ([]byte, error)
+	decompress(data []byte, uncompressedLen int) ([]byte, error)
+}
+
+type zlibCompressor struct {
+	writer *zlib.Writer
+	reader io.ReadCloser
+}
+
+func (c *zlibCompressor) compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if c.writer == nil {
+		var err error
+		if c.writer, err = zlib.NewWriterLevel(&buf, zlibCompressionLevel); err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else {
+		c.writer.Reset(&buf)
+	}
+	if _, err := c.writer.Write(data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := c.writer.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *zlibCompressor) decompress(data []byte, uncompressedLen int) ([]byte, error) {
+	var err error
+	if c.reader == nil {
+		c.reader, err = zlib.NewReader(bytes.NewReader(data))
+	} else {
+		err = c.reader.(zlib.Resetter).Reset(bytes.NewReader(data), nil)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]byte, uncompressedLen)
+	if _, err = io.ReadFull(c.reader, result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// zstdCompressor uses the zstd encoder and decoder shared by all the connections,
+// EncodeAll and DecodeAll can be called concurrently.
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+}
+
+func newZstdCompressor(level int) (*zstdCompressor, error) {
+	encoderLevel := zstd.EncoderLevelFromZstd(level)
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+	encoder, ok := zstdEncoders[encoderLevel]
+	if !ok {
+		var err error
+		if encoder, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel)); err != nil {
+			return nil, errors.Trace(err)
+		}
+		zstdEncoders[encoderLevel] = encoder
+	}
+	return &zstdCompressor{encoder: encoder}, nil
+}
+
+func (c *zstdCompressor) compress(data []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c *zstdCompressor) decompress(data []byte, uncompressedLen int) ([]byte, error) {
+	zstdDecoderOnce.Do(func() {
+		// The decoded size is limited to a max payload, which is the most a compressed packet contains, so a small
+		// packet can't be decompressed into a huge one. NewReader never fails with the valid options.
+		zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+	})
+	// The frame declaring a larger content than the uncompressed length is rejected before it's decoded.
+	var header zstd.Header
+	if err := header.Decode(data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if header.HasFCS && header.FrameContentSize > uint64(uncompressedLen) {
+		return nil, errors.Trace(mysql.ErrMalformPacket)
+	}
+	result, err := zstdDecoder.DecodeAll(data, make([]byte, 0, uncompressedLen))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(result) != uncompressedLen {
+		return nil, errors.Trace(mysql.ErrMalformPacket)
+	}
+	return result, nil
+}
+
+// setCompression makes the following packets compressed with the algorithm.
+func (p *packetIO) setCompression(algorithm string, zstdLevel int) error {
+	switch algorithm {
+	case mysql.CompressionZlib:
+		p.compressor = &zlibCompressor{}
+		p.compressionLevel = zlibCompressionLevel
+	case mysql.CompressionZstd:
+		compressor, err := newZstdCompressor(zstdLevel)
+		if err != nil {
+			return err
+		}
+		p.compressor = compressor
+		p.compressionLevel = zstdLevel
+	default:
+		return errors.Errorf("unknown compression algorithm %s", algorithm)
+	}
+	p.compressionAlgorithm = algorithm
+	p.compressedSequence = 0
+	return nil
+}
+
+// readCompressed fills buf with the decompressed data, it reads the compressed packets if necessary.
+func (p *packetIO) readCompressed(buf []byte) error {
+	for len(buf) > 0 {
+		if len(p.compressedReadBuf) == 0 {
+			if err := p.readCompressedPacket(); err != nil {
+				return err
+			}
+		}
+		n := copy(buf, p.compressedReadBuf)
+		p.compressedReadBuf = p.compressedReadBuf[n:]
+		buf = buf[n:]
+	}
+	return nil
+}
+
+func (p *packetIO) readCompressedPacket() error {
+	var header [compressedHeaderLen]byte
+	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+		return errors.Trace(err)
+	}
+
+	sequence := header[3]
+	if sequence != p.compressedSequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, p.compressedSequence)
+	}
+	p.compressedSequence++
+	// Like MySQL, the sequence of the packets inside isn't checked, it follows the compressed sequence.
+	p.sequence = p.compressedSequence
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	data := make([]byte, length)
+	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+		return errors.Trace(err)
+	}
+	atomic.AddUint64(&compressedBytesReceivedCount, uint64(compressedHeaderLen+length))
+
+	// The uncompressed length is 0 if the payload isn't compressed.
+	if uncompressedLength > 0 {
+		var err error
+		if data, err = p.compressor.decompress(data, uncompressedLength); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	p.compressedReadBuf = data
+	return nil
+}
+
+// writeCompressed buffers the packets to be compressed, the full compressed packets are written at once.
+func (p *packetIO) writeCompressed(data []byte) error {
+	p.compressedWriteBuf.Write(data)
+	for p.compressedWriteBuf.Len() >= mysql.MaxPayloadLen {
+		if err := p.writeCompressedPacket(p.compressedWriteBuf.Next(mysql.MaxPayloadLen)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *packetIO) writeCompressedPacket(payload []byte) error {
+	uncompressedLength := 0
+	if len(payload) >= minCompressLength {
+		compressed, err := p.compressor.compress(payload)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Send the payload as is if it can't be compressed.
+		if len(compressed) < len(payload) {
+			uncompressedLength = len(payload)
+			payload = compressed
+		}
+	}
+
+	length := len(payload)
+	header := [compressedHeaderLen]byte{
+		byte(length), byte(length >> 8), byte(length >> 16),
+		p.compressedSequence,
+		byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16),
+	}
+	if _, err := p.bufWriter.Write(header[:]); err != nil {
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	if _, err := p.bufWriter.Write(payload); err != nil {
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	p.compressedSequence++
+	atomic.AddUint64(&compressedBytesSentCount, uint64(compressedHeaderLen+length))
+	return nil
+}
+
+// flushCompressed writes the buffered packets in a compressed packet.
+func (p *packetIO) flushCompressed() error {
+	if p.compressedWriteBuf.Len() > 0 {
+		if err := p.writeCompressedPacket(p.compressedWriteBuf.Next(p.compressedWriteBuf.Len())); err != nil {
+			return err
+		}
+	}
+	// Like MySQL, the sequence of the packets inside follows the compressed sequence after flushing.
+	p.sequence = p.compressedSequence
+	return nil
+}
@@ -21,7 +21,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -95,6 +97,82 @@ func TestPacketIORead(t *testing.T) {
 	require.Equal(t, byte(0x0a), readBytes[mysql.MaxPayloadLen])
 }
 
+func TestPacketIOCompressed(t *testing.T) {
+	small := []byte{0x01, 0x02, 0x03}
+	large := bytes.Repeat([]byte("compressed"), mysql.MaxPayloadLen/5)
+	for _, algorithm := range []string{mysql.CompressionZlib, mysql.CompressionZstd} {
+		var outBuffer bytes.Buffer
+		pkt := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		require.NoError(t, pkt.setCompression(algorithm, 3))
+		for _, payload := range [][]byte{small, large} {
+			data := make([]byte, 4, 4+len(payload))
+			require.NoError(t, pkt.writePacket(append(data, payload...)))
+		}
+		require.NoError(t, pkt.flush())
+		// The packets are larger than 2 max payloads, they're compressed into 3 compressed packets.
+		require.Equal(t, uint8(3), pkt.compressedSequence)
+		require.Equal(t, uint8(3), pkt.sequence)
+		require.Less(t, outBuffer.Len(), len(large))
+
+		brc := newBufferedReadConn(&bytesConn{outBuffer})
+		pkt = newPacketIO(brc)
+		require.NoError(t, pkt.setCompression(algorithm, 3))
+		readBytes, err := pkt.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, small, readBytes)
+		readBytes, err = pkt.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, large, readBytes)
+		require.Equal(t, uint8(3), pkt.compressedSequence)
+	}
+
+	// The short payload isn't compressed.
+	var outBuffer bytes.Buffer
+	pkt := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+	require.NoError(t, pkt.setCompression(mysql.CompressionZlib, 0))
+	require.NoError(t, pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01}))
+	require.NoError(t, pkt.flush())
+	require.Equal(t, []byte{0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01}, outBuffer.Bytes())
+
+	// The compressed sequence is checked.
+	brc := newBufferedReadConn(&bytesConn{outBuffer})
+	pkt = newPacketIO(brc)
+	require.NoError(t, pkt.setCompression(mysql.CompressionZlib, 0))
+	pkt.compressedSequence = 1
+	_, err := pkt.readPacket()
+	require.True(t, errInvalidSequence.Equal(err))
+}
+
+func TestPacketIOCompressionBomb(t *testing.T) {
+	huge := make([]byte, 2*mysql.MaxPayloadLen)
+	readCompressed := func(compressed []byte) error {
+		// The header claims that the payload is decompressed into 100 bytes.
+		var inBuffer bytes.Buffer
+		length := len(compressed)
+		inBuffer.Write([]byte{byte(length), byte(length >> 8), byte(length >> 16), 0x00, 100, 0x00, 0x00})
+		inBuffer.Write(compressed)
+		pkt := newPacketIO(newBufferedReadConn(&bytesConn{inBuffer}))
+		require.NoError(t, pkt.setCompression(mysql.CompressionZstd, 3))
+		_, err := pkt.readPacket()
+		return err
+	}
+
+	// The frame declares its content size.
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	err = readCompressed(encoder.EncodeAll(huge, nil))
+	require.True(t, terror.ErrorEqual(err, mysql.ErrMalformPacket), err)
+
+	// The content size of the streamed frame is unknown.
+	var compressed bytes.Buffer
+	encoder.Reset(&compressed)
+	_, err = encoder.Write(huge)
+	require.NoError(t, err)
+	require.NoError(t, encoder.Close())
+	err = readCompressed(compressed.Bytes())
+	require.True(t, terror.ErrorEqual(err, zstd.ErrDecoderSizeExceeded), err)
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
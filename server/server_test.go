@@ -1989,6 +1989,34 @@ func (cli *testServerClient) runTestMultiStatements(t *testing.T) {
 	})
 }
 
+func (cli *testServerClient) runTestCallProcedure(t *testing.T) {
+	cli.runTestsOnNewDB(t, nil, "CallProcedure", func(dbt *testkit.DBTestKit) {
+		dbt.MustExec("CREATE PROCEDURE p(IN a INT, OUT b INT) BEGIN SET b = a * 2; SELECT a; SELECT b, 'x'; END")
+
+		// Every result set of the procedure is sent, followed by the final OK packet.
+		rows := dbt.MustQuery("CALL p(3, @b)")
+		var a, b int
+		var x string
+		require.True(t, rows.Next())
+		require.NoError(t, rows.Scan(&a))
+		require.Equal(t, 3, a)
+		require.False(t, rows.Next())
+		require.True(t, rows.NextResultSet())
+		require.True(t, rows.Next())
+		require.NoError(t, rows.Scan(&b, &x))
+		require.Equal(t, 6, b)
+		require.Equal(t, "x", x)
+		require.False(t, rows.Next())
+		require.NoError(t, rows.Close())
+
+		rows = dbt.MustQuery("SELECT @b")
+		require.True(t, rows.Next())
+		require.NoError(t, rows.Scan(&b))
+		require.Equal(t, 6, b)
+		require.NoError(t, rows.Close())
+	})
+}
+
 func (cli *testServerClient) runTestStmtCount(t *testing.T) {
 	cli.runTestsOnNewDB(t, nil, "StatementCount", func(dbt *testkit.DBTestKit) {
 		originStmtCnt := getStmtCnt(string(cli.getMetrics(t)))
@@ -16,6 +16,7 @@ package server
 
 import (
 	"crypto/x509"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/tidb/domain/infosync"
@@ -25,15 +26,25 @@ import (
 )
 
 var (
-	serverNotAfter  = "Ssl_server_not_after"
-	serverNotBefore = "Ssl_server_not_before"
-	upTime          = "Uptime"
+	serverNotAfter          = "Ssl_server_not_after"
+	serverNotBefore         = "Ssl_server_not_before"
+	upTime                  = "Uptime"
+	compression             = "Compression"
+	compressionAlgorithm    = "Compression_algorithm"
+	compressionLevel        = "Compression_level"
+	compressedBytesReceived = "Compressed_bytes_received"
+	compressedBytesSent     = "Compressed_bytes_sent"
 )
 
 var defaultStatus = map[string]*variable.StatusVal{
-	serverNotAfter:  {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
-	serverNotBefore: {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
-	upTime:          {Scope: variable.ScopeGlobal, Value: 0},
+	serverNotAfter:          {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	serverNotBefore:         {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	upTime:                  {Scope: variable.ScopeGlobal, Value: 0},
+	compression:             {Scope: variable.ScopeSession, Value: variable.Off},
+	compressionAlgorithm:    {Scope: variable.ScopeSession, Value: ""},
+	compressionLevel:        {Scope: variable.ScopeSession, Value: 0},
+	compressedBytesReceived: {Scope: variable.ScopeGlobal, Value: uint64(0)},
+	compressedBytesSent:     {Scope: variable.ScopeGlobal, Value: uint64(0)},
 }
 
 // GetScope gets the status variables scope.
@@ -62,6 +73,19 @@ func (s *Server) Stats(vars *variable.SessionVars) (map[string]interface{}, erro
 		}
 	}
 
+	m[compressedBytesReceived] = atomic.LoadUint64(&compressedBytesReceivedCount)
+	m[compressedBytesSent] = atomic.LoadUint64(&compressedBytesSentCount)
+	if vars != nil {
+		s.rwlock.RLock()
+		cc, ok := s.clients[vars.ConnectionID]
+		s.rwlock.RUnlock()
+		if ok && cc.pkt.compressor != nil {
+			m[compression] = variable.On
+			m[compressionAlgorithm] = cc.pkt.compressionAlgorithm
+			m[compressionLevel] = cc.pkt.compressionLevel
+		}
+	}
+
 	var err error
 	info := serverInfo{}
 	info.ServerInfo, err = infosync.GetServerInfo()
@@ -421,6 +421,79 @@ const (
 	CreateAdvisoryLocks = `CREATE TABLE IF NOT EXISTS mysql.advisory_locks (
 		lock_name VARCHAR(64) NOT NULL PRIMARY KEY
 	);`
+	// CreatePlanEvolutionHistory stores the decisions made when capturing and verifying the plan baselines.
+	CreatePlanEvolutionHistory = `CREATE TABLE IF NOT EXISTS mysql.plan_evolution_history (
+		sql_digest VARCHAR(64) NOT NULL,
+		original_sql TEXT NOT NULL,
+		default_db TEXT NOT NULL,
+		bind_sql TEXT NOT NULL,
+		source VARCHAR(10) NOT NULL DEFAULT 'unknown',
+		decision ENUM('captured', 'accepted', 'rejected', 'dropped') NOT NULL,
+		current_plan_latency BIGINT(64) UNSIGNED NOT NULL DEFAULT 0 comment 'latency of the plan chosen without the binding in nanoseconds',
+		binding_plan_latency BIGINT(64) UNSIGNED NOT NULL DEFAULT 0 comment 'latency of the plan of the binding in nanoseconds',
+		reason TEXT,
+		create_time TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+		INDEX sql_index(sql_digest),
+		INDEX time_index(create_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+	// CreateRoutinesTable stores the definitions of the stored procedures and functions.
+	CreateRoutinesTable = `CREATE TABLE IF NOT EXISTS mysql.routines (
+		db VARCHAR(64) NOT NULL,
+		name VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+		type ENUM('FUNCTION', 'PROCEDURE') NOT NULL,
+		param_list TEXT NOT NULL,
+		returns TEXT NOT NULL,
+		body LONGTEXT NOT NULL,
+		definer VARCHAR(288) NOT NULL,
+		security_type ENUM('INVOKER', 'DEFINER') NOT NULL DEFAULT 'DEFINER',
+		is_deterministic ENUM('YES', 'NO') NOT NULL DEFAULT 'NO',
+		sql_data_access ENUM('CONTAINS SQL', 'NO SQL', 'READS SQL DATA', 'MODIFIES SQL DATA') NOT NULL DEFAULT 'CONTAINS SQL',
+		comment TEXT NOT NULL,
+		sql_mode VARCHAR(1024) NOT NULL DEFAULT '',
+		character_set_client VARCHAR(32) NOT NULL,
+		collation_connection VARCHAR(32) NOT NULL,
+		db_collation VARCHAR(32) NOT NULL,
+		created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		last_altered TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (db, name, type)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+	// CreateEventsTable stores the definitions and the execution status of the events. The times are in UTC.
+	CreateEventsTable = `CREATE TABLE IF NOT EXISTS mysql.events (
+		db VARCHAR(64) NOT NULL,
+		name VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+		body LONGTEXT NOT NULL,
+		definer VARCHAR(288) NOT NULL,
+		execute_at DATETIME DEFAULT NULL,
+		interval_value VARCHAR(256) DEFAULT NULL,
+		interval_field VARCHAR(18) DEFAULT NULL,
+		starts DATETIME DEFAULT NULL,
+		ends DATETIME DEFAULT NULL,
+		status ENUM('ENABLED', 'DISABLED', 'SLAVESIDE_DISABLED') NOT NULL DEFAULT 'ENABLED',
+		on_completion ENUM('DROP', 'PRESERVE') NOT NULL DEFAULT 'DROP',
+		sql_mode VARCHAR(1024) NOT NULL DEFAULT '',
+		comment TEXT NOT NULL,
+		originator BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		time_zone VARCHAR(64) NOT NULL DEFAULT 'SYSTEM',
+		character_set_client VARCHAR(32) NOT NULL,
+		collation_connection VARCHAR(32) NOT NULL,
+		db_collation VARCHAR(32) NOT NULL,
+		created DATETIME NOT NULL,
+		last_altered DATETIME NOT NULL,
+		last_executed DATETIME DEFAULT NULL,
+		executions BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		failures BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		last_error TEXT DEFAULT NULL,
+		PRIMARY KEY (db, name)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+	// CreateFuncTable stores the loadable functions created by CREATE FUNCTION ... SONAME.
+	// The ret column has the same values as ast.LoadableFunctionRetType.
+	CreateFuncTable = `CREATE TABLE IF NOT EXISTS mysql.func (
+		name CHAR(64) NOT NULL DEFAULT '',
+		ret TINYINT(1) NOT NULL DEFAULT 0,
+		dl CHAR(128) NOT NULL DEFAULT '',
+		type ENUM('function', 'aggregate') NOT NULL,
+		PRIMARY KEY (name)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
 )
 
 // bootstrap initiates system DB for a store.
@@ -622,11 +695,19 @@ const (
 	version91 = 91
 	// version92 for concurrent ddl.
 	version92 = 92
+	// version93 adds the table mysql.plan_evolution_history
+	version93 = 93
+	// version94 adds the table mysql.routines
+	version94 = 94
+	// version95 adds the table mysql.events
+	version95 = 95
+	// version96 adds the table mysql.func
+	version96 = 96
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version92
+var currentBootstrapVersion int64 = version96
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -721,6 +802,10 @@ var (
 		upgradeToVer89,
 		upgradeToVer90,
 		upgradeToVer91,
+		upgradeToVer93,
+		upgradeToVer94,
+		upgradeToVer95,
+		upgradeToVer96,
 	}
 )
 
@@ -1898,6 +1983,34 @@ func upgradeToVer91(s Session, ver int64) {
 	importConfigOption(s, "prepared-plan-cache.memory-guard-ratio", variable.TiDBPrepPlanCacheMemoryGuardRatio, valStr)
 }
 
+func upgradeToVer93(s Session, ver int64) {
+	if ver >= version93 {
+		return
+	}
+	doReentrantDDL(s, CreatePlanEvolutionHistory)
+}
+
+func upgradeToVer94(s Session, ver int64) {
+	if ver >= version94 {
+		return
+	}
+	doReentrantDDL(s, CreateRoutinesTable)
+}
+
+func upgradeToVer95(s Session, ver int64) {
+	if ver >= version95 {
+		return
+	}
+	doReentrantDDL(s, CreateEventsTable)
+}
+
+func upgradeToVer96(s Session, ver int64) {
+	if ver >= version96 {
+		return
+	}
+	doReentrantDDL(s, CreateFuncTable)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1992,6 +2105,14 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateAnalyzeJobs)
 	// Create advisory_locks table.
 	mustExecute(s, CreateAdvisoryLocks)
+	// Create plan_evolution_history table.
+	mustExecute(s, CreatePlanEvolutionHistory)
+	// Create routines table.
+	mustExecute(s, CreateRoutinesTable)
+	// Create events table.
+	mustExecute(s, CreateEventsTable)
+	// Create func table.
+	mustExecute(s, CreateFuncTable)
 }
 
 // inTestSuite checks if we are bootstrapping in the context of tests.
@@ -0,0 +1,86 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// ExecEvent implements sqlexec.EventExecutor interface. The body of the event is executed as a stored routine, in the
+// schema, the SQL mode and the time zone of the event, with the privileges of the definer of the event. The result sets
+// of the body are discarded, and the transaction left open by the body is rolled back.
+func (s *session) ExecEvent(ctx context.Context, db, createStmt, sqlMode, timeZone, dbCollation string) error {
+	sessVars := s.sessionVars
+	mode, err := mysql.GetSQLMode(sqlMode)
+	if err != nil {
+		return err
+	}
+	p := parser.New()
+	p.SetSQLMode(mode)
+	p.SetParserConfig(sessVars.BuildParserConfig())
+	node, err := p.ParseOneStmt(createStmt, "", "")
+	if err != nil {
+		return err
+	}
+	create := node.(*ast.CreateEventStmt)
+
+	// The session is authenticated as the definer with its own privilege manager, so the privileges of the session
+	// are restored after the event is executed.
+	oldPM, oldUser, oldRoles := privilege.GetPrivilegeManager(s), sessVars.User, sessVars.ActiveRoles
+	privilege.BindPrivilegeManager(s, &privileges.UserPrivileges{Handle: domain.GetDomain(s).PrivilegeHandle()})
+	defer func() {
+		privilege.BindPrivilegeManager(s, oldPM)
+		sessVars.User, sessVars.ActiveRoles = oldUser, oldRoles
+	}()
+	definer := create.Definer
+	if !s.AuthWithoutVerification(&auth.UserIdentity{Username: definer.Username, Hostname: definer.Hostname}) {
+		return errNoSuchUser.GenWithStackByArgs(definer.Username, definer.Hostname)
+	}
+
+	oldTimeZone, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.TimeZone)
+	if err != nil {
+		return err
+	}
+	if err = variable.SetSessionSystemVar(sessVars, variable.TimeZone, timeZone); err != nil {
+		return err
+	}
+	oldSQLMode, oldDB, oldRestricted := sessVars.SQLMode, sessVars.CurrentDB, sessVars.InRestrictedSQL
+	sessVars.SQLMode, sessVars.CurrentDB, sessVars.InRestrictedSQL = mode, db, false
+	c := &spCall{se: s}
+	defer func() {
+		for _, rs := range c.results {
+			terror.Call(rs.Close)
+		}
+		if sessVars.InTxn() {
+			s.RollbackTxn(ctx)
+		}
+		sessVars.SQLMode, sessVars.CurrentDB, sessVars.InRestrictedSQL = oldSQLMode, oldDB, oldRestricted
+		terror.Log(variable.SetSessionSystemVar(sessVars, variable.TimeZone, oldTimeZone))
+	}()
+
+	e := &spExec{spCall: c, name: db + "." + create.Name.Name.O, dbCollation: dbCollation}
+	_, err = e.execStmt(ctx, newSpScope(nil), create.Body)
+	return err
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventScheduler(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create event e1 on schedule every 1 second do insert into t values (1)")
+	tk.MustExec("create event e2 on schedule at current_timestamp + interval 1 second do insert into t values (2)")
+	tk.MustExec("create event e3 on schedule every 1 second do insert into no_such_table values (1)")
+	tk.MustExec("create event e4 on schedule every 1 second disable do insert into t values (4)")
+
+	// The events are executed only if the scheduler is on.
+	time.Sleep(2 * time.Second)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("0"))
+	tk.MustExec("set global event_scheduler = on")
+	defer tk.MustExec("set global event_scheduler = off")
+
+	count := func(sql string) int {
+		n, err := strconv.Atoi(tk.MustQuery(sql).Rows()[0][0].(string))
+		require.NoError(t, err)
+		return n
+	}
+	require.Eventually(t, func() bool {
+		return count("select count(*) from t where a = 1") >= 2 && count("select count(*) from t where a = 2") == 1 &&
+			count("select count(*) from information_schema.events where event_name = 'e2'") == 0 &&
+			count("select tidb_failure_count from information_schema.events where event_name = 'e3'") >= 1
+	}, 10*time.Second, 100*time.Millisecond)
+	tk.MustQuery("select count(*) from t where a = 4").Check(testkit.Rows("0"))
+	tk.MustQuery("select tidb_last_error from information_schema.events where event_name = 'e3'").
+		Check(testkit.Rows("[schema:1146]Table 'test.no_such_table' doesn't exist"))
+	tk.MustQuery("select last_executed is not null, tidb_execution_count > 0, tidb_failure_count, tidb_last_error " +
+		"from information_schema.events where event_name = 'e1'").Check(testkit.Rows("1 1 0 <nil>"))
+
+	// The event with a body of multiple statements is executed in the schema of the event.
+	tk.MustExec("drop event e1")
+	tk.MustExec("drop event e3")
+	tk.MustExec("use mysql")
+	tk.MustExec("create event test.e5 on schedule every 1 second ends current_timestamp + interval 1 second on completion preserve " +
+		"do begin declare n int; select count(*) into n from t; insert into t values (100 + n); end")
+	require.Eventually(t, func() bool {
+		return count("select count(*) from test.t where a > 100") >= 1 &&
+			count("select count(*) from information_schema.events where event_name = 'e5' and status = 'DISABLED'") == 1
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestEventDefiner(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create user 'u1'@'%', 'u2'@'%'")
+	tk.MustExec("grant event on test.* to 'u1'@'%', 'u2'@'%'")
+	tk.MustExec("grant insert on test.* to 'u2'@'%'")
+
+	// The body of the event is executed with the privileges of the definer.
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("create event test.e1 on schedule every 1 second do insert into mysql.user (host, user) values ('%', 'evil')")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil))
+	tk2.MustExec("create event test.e2 on schedule every 1 second do insert into test.t values (1)")
+	tk.MustExec("create definer = 'u3'@'%' event e3 on schedule every 1 second do insert into t values (3)")
+
+	tk.MustExec("set global event_scheduler = on")
+	defer tk.MustExec("set global event_scheduler = off")
+	require.Eventually(t, func() bool {
+		rows := tk.MustQuery("select name from mysql.events where failures > 0 or executions = 0 order by name").Rows()
+		return len(rows) == 2 && rows[0][0] == "e1" && rows[1][0] == "e3"
+	}, 10*time.Second, 100*time.Millisecond)
+	tk.MustQuery("select count(*) from mysql.user where user = 'evil'").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) > 0 from t where a = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from t where a = 3").Check(testkit.Rows("0"))
+	tk.MustQuery("select last_error from mysql.events where name = 'e1'").
+		Check(testkit.Rows("[planner:1142]INSERT command denied to user 'u1'@'%' for table 'user'"))
+	tk.MustQuery("select last_error from mysql.events where name = 'e3'").
+		Check(testkit.Rows("[session:1449]The user specified as a definer ('u3'@'%') does not exist"))
+}
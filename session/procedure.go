@@ -0,0 +1,871 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+var (
+	errSpWrongNoOfArgs      = dbterror.ClassSession.NewStd(errno.ErrSpWrongNoOfArgs)
+	errSpNotVarArg          = dbterror.ClassSession.NewStd(errno.ErrSpNotVarArg)
+	errSpRecursionLimit     = dbterror.ClassSession.NewStd(errno.ErrSpRecursionLimit)
+	errSpCursorAlreadyOpen  = dbterror.ClassSession.NewStd(errno.ErrSpCursorAlreadyOpen)
+	errSpCursorNotOpen      = dbterror.ClassSession.NewStd(errno.ErrSpCursorNotOpen)
+	errSpWrongNoOfFetchArgs = dbterror.ClassSession.NewStd(errno.ErrSpWrongNoOfFetchArgs)
+	errSpCaseNotFound       = dbterror.ClassSession.NewStd(errno.ErrSpCaseNotFound)
+	errProcaccessDenied     = dbterror.ClassSession.NewStd(errno.ErrProcaccessDenied)
+	errNoSuchUser           = dbterror.ClassSession.NewStd(errno.ErrNoSuchUser)
+)
+
+// CallResultsVarKeyType is a dummy type to avoid naming collision in context.
+type CallResultsVarKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k CallResultsVarKeyType) String() string {
+	return "call_results_var_key"
+}
+
+// CallResultsVarKey is a variable key for the result sets returned by the statements in a stored procedure.
+const CallResultsVarKey CallResultsVarKeyType = 0
+
+// HandleCall is the entry point for a CALL statement. It executes the stored procedure on top of the session, and
+// returns the result sets of the statements in the procedure.
+func HandleCall(ctx context.Context, stmt *ast.CallStmt, se Session) ([]sqlexec.RecordSet, error) {
+	c := &spCall{se: se.(*session)}
+	if err := c.call(ctx, stmt, nil); err != nil {
+		for _, rs := range c.results {
+			terror.Call(rs.Close)
+		}
+		return nil, err
+	}
+	return c.results, nil
+}
+
+// spCall is the context of a CALL statement or a trigger, which is shared by the nested calls.
+type spCall struct {
+	se      *session
+	results []sqlexec.RecordSet
+	// stack is the names of the routines being executed.
+	stack []string
+	// inTrigger is set when the statements are executed by a trigger, inside the statement activating the trigger.
+	inTrigger bool
+}
+
+// spVar is a local variable or a parameter of a stored routine.
+type spVar struct {
+	tp    *types.FieldType
+	value types.Datum
+}
+
+// spCursor is a cursor of a stored routine. The rows are materialized when it's opened.
+type spCursor struct {
+	stmt  ast.StmtNode
+	scope *spScope
+	rows  [][]types.Datum
+	pos   int
+	open  bool
+}
+
+// spScope is the scope of a BEGIN ... END block or a handler body.
+type spScope struct {
+	parent   *spScope
+	vars     map[string]*spVar
+	cursors  map[string]*spCursor
+	handlers []*ast.ProcedureHandlerDeclStmt
+	// handlerOuter is set for the scope of a handler body. The conditions raised in the body are not handled by the
+	// handlers of the block declaring the handler, but by the handlers out of it.
+	handlerOuter *spScope
+	isHandler    bool
+}
+
+func newSpScope(parent *spScope) *spScope {
+	return &spScope{parent: parent, vars: make(map[string]*spVar), cursors: make(map[string]*spCursor)}
+}
+
+func (sc *spScope) findVar(name string) *spVar {
+	for ; sc != nil; sc = sc.parent {
+		if v, ok := sc.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (sc *spScope) findCursor(name string) *spCursor {
+	for ; sc != nil; sc = sc.parent {
+		if c, ok := sc.cursors[name]; ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// localVar returns the local variable referred by the expression, or nil if it's not a local variable. The columns
+// of the NEW and OLD rows of a trigger are local variables as well.
+func (sc *spScope) localVar(expr ast.ExprNode) *spVar {
+	col, ok := expr.(*ast.ColumnNameExpr)
+	if !ok || sc == nil || col.Name.Schema.L != "" {
+		return nil
+	}
+	if table := col.Name.Table.L; table != "" {
+		if table != "new" && table != "old" {
+			return nil
+		}
+		return sc.findVar(table + "." + col.Name.Name.L)
+	}
+	return sc.findVar(col.Name.Name.L)
+}
+
+type spSignalType int
+
+const (
+	spSignalLeave spSignalType = iota
+	spSignalIterate
+	// spSignalExit leaves the block declaring an EXIT handler after the handler is executed.
+	spSignalExit
+)
+
+// spSignal is the control flow jumping out of the statements being executed.
+type spSignal struct {
+	tp    spSignalType
+	label string
+	scope *spScope
+}
+
+// spExec executes a stored routine.
+type spExec struct {
+	*spCall
+	name        string
+	dbCollation string
+	// warnings are the warnings of the last executed statement, which may activate the handlers.
+	warnings []stmtctx.SQLWarn
+	// stmts caches the parsed statements by their keys if it's not nil, see execSQL.
+	stmts map[ast.Node]*spStmt
+}
+
+func (c *spCall) call(ctx context.Context, stmt *ast.CallStmt, caller *spScope) error {
+	sessVars := c.se.sessionVars
+	db := stmt.Procedure.Schema.O
+	if db == "" {
+		db = sessVars.CurrentDB
+	}
+	if db == "" {
+		return plannercore.ErrNoDB
+	}
+	name := strings.ToLower(db) + "." + stmt.Procedure.FnName.O
+	if checker := privilege.GetPrivilegeManager(c.se); checker != nil && sessVars.User != nil &&
+		!checker.RequestVerification(sessVars.ActiveRoles, strings.ToLower(db), "", "", mysql.ExecutePriv) {
+		return errProcaccessDenied.GenWithStackByArgs("execute", sessVars.User.AuthUsername, sessVars.User.AuthHostname, name)
+	}
+	routine, err := executor.LoadRoutine(ctx, c.se, ast.RoutineProcedure, db, stmt.Procedure.FnName.O)
+	if err != nil {
+		return err
+	}
+	if routine == nil {
+		return executor.ErrSpDoesNotExist.GenWithStackByArgs("PROCEDURE", name)
+	}
+	name = routine.DB + "." + routine.Name
+
+	maxDepth, err := variable.GetSessionOrGlobalSystemVar(sessVars, variable.MaxSpRecursionDepth)
+	if err != nil {
+		return err
+	}
+	depth, err := strconv.Atoi(maxDepth)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, n := range c.stack {
+		if n == name {
+			depth--
+		}
+	}
+	if depth < 0 {
+		return errSpRecursionLimit.GenWithStackByArgs(maxDepth, routine.Name)
+	}
+
+	sqlMode, err := mysql.GetSQLMode(routine.SQLMode)
+	if err != nil {
+		return err
+	}
+	p := parser.New()
+	p.SetSQLMode(sqlMode)
+	p.SetParserConfig(sessVars.BuildParserConfig())
+	node, err := p.ParseOneStmt(routine.CreateStmt(), "", "")
+	if err != nil {
+		return err
+	}
+	create := node.(*ast.CreateRoutineStmt)
+	if len(stmt.Procedure.Args) != len(create.Params) {
+		return errSpWrongNoOfArgs.GenWithStackByArgs("PROCEDURE", name, len(create.Params), len(stmt.Procedure.Args))
+	}
+
+	// The arguments are evaluated in the context of the caller.
+	e := &spExec{spCall: c, name: name, dbCollation: routine.DBCollation}
+	inArgs := make([]ast.ExprNode, 0, len(create.Params))
+	for i, param := range create.Params {
+		arg := stmt.Procedure.Args[i]
+		if param.Mode != ast.RoutineParamModeIn {
+			if v, ok := arg.(*ast.VariableExpr); !(ok && !v.IsSystem) && caller.localVar(arg) == nil {
+				return errSpNotVarArg.GenWithStackByArgs(i+1, name)
+			}
+		}
+		if param.Mode != ast.RoutineParamModeOut {
+			inArgs = append(inArgs, arg)
+		}
+	}
+	var inValues []types.Datum
+	if len(inArgs) > 0 {
+		if inValues, err = e.evalExprs(ctx, caller, stmt, inArgs); err != nil {
+			return err
+		}
+	}
+
+	oldSQLMode, oldDB := sessVars.SQLMode, sessVars.CurrentDB
+	sessVars.SQLMode, sessVars.CurrentDB = sqlMode, routine.DB
+	c.stack = append(c.stack, name)
+	defer func() {
+		sessVars.SQLMode, sessVars.CurrentDB = oldSQLMode, oldDB
+		c.stack = c.stack[:len(c.stack)-1]
+	}()
+
+	scope := newSpScope(nil)
+	params := make([]*spVar, len(create.Params))
+	for i, param := range create.Params {
+		v := &spVar{tp: spVarType(param.Tp, routine.DBCollation)}
+		if param.Mode != ast.RoutineParamModeOut {
+			if err = e.setVar(v, inValues[0]); err != nil {
+				return err
+			}
+			inValues = inValues[1:]
+		}
+		scope.vars[param.Name.L] = v
+		params[i] = v
+	}
+	if _, err = e.execStmt(ctx, scope, create.Body); err != nil {
+		return err
+	}
+	sessVars.SQLMode, sessVars.CurrentDB = oldSQLMode, oldDB
+
+	for i, param := range create.Params {
+		if param.Mode == ast.RoutineParamModeIn {
+			continue
+		}
+		if err = e.assign(caller, stmt.Procedure.Args[i], params[i].value, params[i].tp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spVarType fills the unspecified length and the collation of the declared type of a variable.
+func spVarType(tp *types.FieldType, dbCollation string) *types.FieldType {
+	tp = tp.Clone()
+	flen, decimal := mysql.GetDefaultFieldLengthAndDecimal(tp.GetType())
+	if tp.GetFlen() == types.UnspecifiedLength {
+		tp.SetFlen(flen)
+	}
+	if tp.GetDecimal() == types.UnspecifiedLength {
+		tp.SetDecimal(decimal)
+	}
+	if types.IsString(tp.GetType()) && tp.GetCharset() == "" {
+		if coll, err := charset.GetCollationByName(dbCollation); err == nil {
+			tp.SetCharset(coll.CharsetName)
+			tp.SetCollate(coll.Name)
+		} else {
+			tp.SetCharset(mysql.DefaultCharset)
+			tp.SetCollate(mysql.DefaultCollationName)
+		}
+	}
+	return tp
+}
+
+// setVar converts the value to the type of the variable and sets the variable.
+func (e *spExec) setVar(v *spVar, value types.Datum) error {
+	sessVars := e.se.sessionVars
+	sc := &stmtctx.StatementContext{TimeZone: sessVars.Location()}
+	converted, err := value.ConvertTo(sc, v.tp)
+	if err != nil {
+		return err
+	}
+	v.value = converted
+	return nil
+}
+
+// assign sets the local variable or the user variable referred by the target.
+func (e *spExec) assign(scope *spScope, target ast.ExprNode, value types.Datum, tp *types.FieldType) error {
+	if v := scope.localVar(target); v != nil {
+		return e.setVar(v, value)
+	}
+	sessVars := e.se.sessionVars
+	name := strings.ToLower(target.(*ast.VariableExpr).Name)
+	sessVars.UsersLock.Lock()
+	defer sessVars.UsersLock.Unlock()
+	if value.IsNull() {
+		delete(sessVars.Users, name)
+		delete(sessVars.UserVarTypes, name)
+		return nil
+	}
+	var d types.Datum
+	value.Copy(&d)
+	sessVars.Users[name] = d
+	sessVars.UserVarTypes[name] = tp
+	return nil
+}
+
+func (e *spExec) execStmts(ctx context.Context, scope *spScope, stmts []ast.StmtNode) (*spSignal, error) {
+	for _, stmt := range stmts {
+		if sig, err := e.execStmt(ctx, scope, stmt); sig != nil || err != nil {
+			return sig, err
+		}
+	}
+	return nil, nil
+}
+
+func (e *spExec) execStmt(ctx context.Context, scope *spScope, stmt ast.StmtNode) (*spSignal, error) {
+	if atomic.LoadUint32(&e.se.sessionVars.Killed) == 1 {
+		return nil, executor.ErrQueryInterrupted
+	}
+	e.warnings = nil
+	switch x := stmt.(type) {
+	case *ast.ProcedureBlockStmt:
+		return e.execBlock(ctx, scope, x)
+	case *ast.ProcedureIfStmt:
+		for _, branch := range x.Branches {
+			ok, err := e.evalBool(ctx, scope, branch.Cond, branch.Cond)
+			if err != nil {
+				return e.handle(ctx, scope, err)
+			}
+			if ok {
+				return e.execStmts(ctx, scope, branch.Stmts)
+			}
+		}
+		return e.execStmts(ctx, scope, x.Else)
+	case *ast.ProcedureCaseStmt:
+		for _, when := range x.WhenClauses {
+			cond := when.Expr
+			if x.Value != nil {
+				cond = &ast.BinaryOperationExpr{Op: opcode.EQ, L: x.Value, R: when.Expr}
+			}
+			// The condition is built for every execution, so it's identified by the WHEN expression.
+			ok, err := e.evalBool(ctx, scope, when.Expr, cond)
+			if err != nil {
+				return e.handle(ctx, scope, err)
+			}
+			if ok {
+				return e.execStmts(ctx, scope, when.Stmts)
+			}
+		}
+		if x.Else == nil {
+			return e.handle(ctx, scope, errSpCaseNotFound.GenWithStackByArgs())
+		}
+		return e.execStmts(ctx, scope, x.Else)
+	case *ast.ProcedureWhileStmt:
+		for {
+			ok, err := e.evalBool(ctx, scope, x.Cond, x.Cond)
+			if err != nil {
+				return e.handle(ctx, scope, err)
+			}
+			if !ok {
+				return nil, nil
+			}
+			sig, err := e.execStmts(ctx, scope, x.Stmts)
+			if done, sig, err := loopSignal(x.Label, sig, err); done {
+				return sig, err
+			}
+		}
+	case *ast.ProcedureRepeatStmt:
+		for {
+			sig, err := e.execStmts(ctx, scope, x.Stmts)
+			if done, sig, err := loopSignal(x.Label, sig, err); done {
+				return sig, err
+			}
+			ok, err := e.evalBool(ctx, scope, x.Until, x.Until)
+			if err != nil {
+				return e.handle(ctx, scope, err)
+			}
+			if ok {
+				return nil, nil
+			}
+		}
+	case *ast.ProcedureLoopStmt:
+		for {
+			sig, err := e.execStmts(ctx, scope, x.Stmts)
+			if done, sig, err := loopSignal(x.Label, sig, err); done {
+				return sig, err
+			}
+		}
+	case *ast.ProcedureJumpStmt:
+		tp := spSignalLeave
+		if x.Tp == ast.ProcedureIterate {
+			tp = spSignalIterate
+		}
+		return &spSignal{tp: tp, label: x.Label.L}, nil
+	case *ast.ProcedureOpenStmt:
+		return e.handle(ctx, scope, e.openCursor(ctx, scope.findCursor(x.Cursor.L)))
+	case *ast.ProcedureFetchStmt:
+		return e.handle(ctx, scope, e.fetchCursor(scope, x))
+	case *ast.ProcedureCloseStmt:
+		cursor := scope.findCursor(x.Cursor.L)
+		if !cursor.open {
+			return e.handle(ctx, scope, errSpCursorNotOpen.GenWithStackByArgs())
+		}
+		cursor.open, cursor.rows = false, nil
+		return nil, nil
+	default:
+		return e.handle(ctx, scope, e.execLeaf(ctx, scope, stmt))
+	}
+}
+
+// loopSignal checks whether the loop is done after its body is executed, and returns the signal to the outer
+// statements.
+func loopSignal(label model.CIStr, sig *spSignal, err error) (bool, *spSignal, error) {
+	if err != nil {
+		return true, nil, err
+	}
+	if sig == nil {
+		return false, nil, nil
+	}
+	if label.L != "" && sig.label == label.L && sig.tp != spSignalExit {
+		return sig.tp == spSignalLeave, nil, nil
+	}
+	return true, sig, nil
+}
+
+func (e *spExec) execBlock(ctx context.Context, parent *spScope, block *ast.ProcedureBlockStmt) (*spSignal, error) {
+	scope := newSpScope(parent)
+	for _, decl := range block.Decls {
+		switch x := decl.(type) {
+		case *ast.ProcedureVarDeclStmt:
+			value := types.NewDatum(nil)
+			if x.Default != nil {
+				values, err := e.evalExprs(ctx, scope, x.Default, []ast.ExprNode{x.Default})
+				if err != nil {
+					if sig, err := e.handle(ctx, scope, err); sig != nil || err != nil {
+						return e.leaveBlock(block, scope, sig, err)
+					}
+				} else {
+					value = values[0]
+				}
+			}
+			for _, name := range x.Names {
+				v := &spVar{tp: spVarType(x.Tp, e.dbCollation)}
+				if err := e.setVar(v, value); err != nil {
+					return nil, err
+				}
+				scope.vars[name.L] = v
+			}
+		case *ast.ProcedureCursorDeclStmt:
+			scope.cursors[x.Name.L] = &spCursor{stmt: x.Select, scope: scope}
+		case *ast.ProcedureHandlerDeclStmt:
+			scope.handlers = append(scope.handlers, x)
+		}
+	}
+	sig, err := e.execStmts(ctx, scope, block.Stmts)
+	return e.leaveBlock(block, scope, sig, err)
+}
+
+func (e *spExec) leaveBlock(block *ast.ProcedureBlockStmt, scope *spScope, sig *spSignal, err error) (*spSignal, error) {
+	if sig != nil && (sig.tp == spSignalExit && sig.scope == scope || sig.tp == spSignalLeave && sig.label == block.Label.L && sig.label != "") {
+		return nil, err
+	}
+	return sig, err
+}
+
+// handle activates the handler of the error, or the handler of the warnings of the last executed statement if err
+// is nil. The error is returned if there is no handler for it.
+func (e *spExec) handle(ctx context.Context, scope *spScope, err error) (*spSignal, error) {
+	warnings := e.warnings
+	e.warnings = nil
+	var handler *ast.ProcedureHandlerDeclStmt
+	var handlerScope *spScope
+	if err != nil {
+		handler, handlerScope = findHandler(scope, spCondition(err))
+	} else {
+		for _, w := range warnings {
+			if handler, handlerScope = findHandler(scope, spCondition(w.Err)); handler != nil {
+				break
+			}
+		}
+	}
+	if handler == nil {
+		return nil, err
+	}
+	bodyScope := newSpScope(handlerScope)
+	bodyScope.isHandler, bodyScope.handlerOuter = true, handlerScope.parent
+	if _, err := e.execStmt(ctx, bodyScope, handler.Stmt); err != nil {
+		return nil, err
+	}
+	if handler.Action == ast.ProcedureHandlerExit {
+		return &spSignal{tp: spSignalExit, scope: handlerScope}, nil
+	}
+	return nil, nil
+}
+
+// spCondition converts the error to the condition with the error code and the SQLSTATE.
+func spCondition(err error) *mysql.SQLError {
+	if te, ok := errors.Cause(err).(*terror.Error); ok {
+		return terror.ToSQLError(te)
+	}
+	return mysql.NewErrf(mysql.ErrUnknown, "%s", nil, err.Error())
+}
+
+// findHandler finds the handler of the innermost scope which handles the condition. If there are several handlers
+// in a scope, the handler of the error code is preferred to the handler of the SQLSTATE, which is preferred to the
+// handlers of a class of conditions.
+func findHandler(scope *spScope, cond *mysql.SQLError) (*ast.ProcedureHandlerDeclStmt, *spScope) {
+	class := cond.State[:2]
+	for scope != nil {
+		var best *ast.ProcedureHandlerDeclStmt
+		bestRank := 0
+		for _, handler := range scope.handlers {
+			for _, c := range handler.Conditions {
+				rank := 0
+				switch c.Tp {
+				case ast.ProcedureConditionErrorCode:
+					if uint16(cond.Code) == c.ErrorCode {
+						rank = 3
+					}
+				case ast.ProcedureConditionSQLState:
+					if cond.State == c.SQLState {
+						rank = 2
+					}
+				case ast.ProcedureConditionSQLWarning:
+					if class == "01" {
+						rank = 1
+					}
+				case ast.ProcedureConditionNotFound:
+					if class == "02" {
+						rank = 1
+					}
+				case ast.ProcedureConditionSQLException:
+					if class != "00" && class != "01" && class != "02" {
+						rank = 1
+					}
+				}
+				if rank > bestRank {
+					best, bestRank = handler, rank
+				}
+			}
+		}
+		if best != nil {
+			return best, scope
+		}
+		if scope.isHandler {
+			scope = scope.handlerOuter
+		} else {
+			scope = scope.parent
+		}
+	}
+	return nil, nil
+}
+
+func (e *spExec) openCursor(ctx context.Context, cursor *spCursor) error {
+	if cursor.open {
+		return errSpCursorAlreadyOpen.GenWithStackByArgs()
+	}
+	_, rows, err := e.execSQL(ctx, cursor.scope, cursor.stmt, cursor.stmt)
+	if err != nil {
+		return err
+	}
+	cursor.rows, cursor.pos, cursor.open = rows, 0, true
+	return nil
+}
+
+func (e *spExec) fetchCursor(scope *spScope, stmt *ast.ProcedureFetchStmt) error {
+	cursor := scope.findCursor(stmt.Cursor.L)
+	if !cursor.open {
+		return errSpCursorNotOpen.GenWithStackByArgs()
+	}
+	if cursor.pos >= len(cursor.rows) {
+		return executor.ErrSpFetchNoData.GenWithStackByArgs()
+	}
+	row := cursor.rows[cursor.pos]
+	if len(row) != len(stmt.Vars) {
+		return errSpWrongNoOfFetchArgs.GenWithStackByArgs()
+	}
+	cursor.pos++
+	for i, name := range stmt.Vars {
+		if err := e.setVar(scope.findVar(name.L), row[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execLeaf executes a statement which is not a compound statement of the stored routine.
+func (e *spExec) execLeaf(ctx context.Context, scope *spScope, stmt ast.StmtNode) error {
+	switch x := stmt.(type) {
+	case *ast.CallStmt:
+		return e.call(ctx, x, scope)
+	case *ast.SetStmt:
+		for _, assign := range x.Variables {
+			if v := scope.findVar(strings.ToLower(assign.Name)); v != nil && assign.IsSystem && !assign.IsGlobal {
+				values, err := e.evalExprs(ctx, scope, assign.Value, []ast.ExprNode{assign.Value})
+				if err != nil {
+					return err
+				}
+				if err = e.setVar(v, values[0]); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, _, err := e.execSQL(ctx, scope, assign, &ast.SetStmt{Variables: []*ast.VariableAssignment{assign}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.SelectStmt:
+		if into := x.SelectIntoOpt; into != nil && into.Tp == ast.SelectIntoVars {
+			return e.selectIntoVars(ctx, scope, x)
+		}
+	}
+	fields, rows, err := e.execSQL(ctx, scope, stmt, stmt)
+	if err != nil || fields == nil {
+		return err
+	}
+	if e.inTrigger {
+		return plannercore.ErrSpNoRetset.GenWithStackByArgs("trigger")
+	}
+	values := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		value := make([]interface{}, len(row))
+		for i := range row {
+			value[i] = row[i].GetValue()
+		}
+		values = append(values, value)
+	}
+	e.results = append(e.results, &sqlexec.SimpleRecordSet{
+		ResultFields: fields,
+		Rows:         values,
+		MaxChunkSize: e.se.sessionVars.MaxChunkSize,
+	})
+	return nil
+}
+
+// selectIntoVars executes the SELECT ... INTO statement, the selected values are assigned to the local variables
+// and the user variables.
+func (e *spExec) selectIntoVars(ctx context.Context, scope *spScope, stmt *ast.SelectStmt) error {
+	into := stmt.SelectIntoOpt
+	stmt.SelectIntoOpt = nil
+	fields, rows, err := e.execSQL(ctx, scope, stmt, stmt)
+	stmt.SelectIntoOpt = into
+	if err != nil {
+		return err
+	}
+	if len(fields) != len(into.Variables) {
+		return plannercore.ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
+	}
+	if len(rows) > 1 {
+		return executor.ErrTooManyRows.GenWithStackByArgs()
+	}
+	if len(rows) == 0 {
+		warn := executor.ErrSpFetchNoData.GenWithStackByArgs()
+		e.se.sessionVars.StmtCtx.AppendWarning(warn)
+		e.warnings = append(e.warnings, stmtctx.SQLWarn{Level: stmtctx.WarnLevelWarning, Err: warn})
+		return nil
+	}
+	for i, target := range into.Variables {
+		if err = e.assign(scope, target, rows[0][i], &fields[i].Column.FieldType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalExprs evaluates the expressions. The key identifies the expressions in the routine, see execSQL.
+func (e *spExec) evalExprs(ctx context.Context, scope *spScope, key ast.Node, exprs []ast.ExprNode) ([]types.Datum, error) {
+	fields := make([]*ast.SelectField, 0, len(exprs))
+	for _, expr := range exprs {
+		fields = append(fields, &ast.SelectField{Expr: expr})
+	}
+	stmt := &ast.SelectStmt{SelectStmtOpts: &ast.SelectStmtOpts{SQLCache: true}, Kind: ast.SelectStmtKindSelect, Fields: &ast.FieldList{Fields: fields}}
+	_, rows, err := e.execSQL(ctx, scope, key, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return rows[0], nil
+}
+
+func (e *spExec) evalBool(ctx context.Context, scope *spScope, key ast.Node, expr ast.ExprNode) (bool, error) {
+	values, err := e.evalExprs(ctx, scope, key, []ast.ExprNode{expr})
+	if err != nil || values[0].IsNull() {
+		return false, err
+	}
+	b, err := values[0].ToBool(e.se.sessionVars.StmtCtx)
+	return b != 0, err
+}
+
+// spStmt is a statement of a stored routine which is parsed once and executed repeatedly, the local variables it
+// refers to are replaced by the value expressions, which are bound to the values of the variables before every
+// execution.
+type spStmt struct {
+	node ast.StmtNode
+	vars []spVarRef
+}
+
+// spVarRef is a reference to a local variable in a parsed statement.
+type spVarRef struct {
+	ref   ast.ExprNode
+	value *driver.ValueExpr
+}
+
+// parseStmt restores the statement of the routine and parses it again, so the statement of the routine is not
+// changed by the execution. The references to the local variables are replaced by their values.
+func (e *spExec) parseStmt(ctx context.Context, scope *spScope, stmt ast.StmtNode) (*spStmt, error) {
+	var sb strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return nil, err
+	}
+	sql := sb.String()
+	stmts, _, err := e.se.ParseSQL(ctx, sql, e.se.sessionVars.GetParseParams()...)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &spStmt{node: stmts[0]}
+	if scope != nil {
+		replacer := &spVarReplacer{scope: scope}
+		parsed.node.Accept(replacer)
+		parsed.vars = replacer.vars
+	}
+	parsed.node.SetText(nil, sql)
+	return parsed, nil
+}
+
+// execSQL executes the statement with the local variables replaced by their values. The rows are returned if the
+// statement returns a result set. The statement is parsed again for every execution, unless the parsed statements
+// are cached by the routine, in which case the key identifies the statement in the cache.
+func (e *spExec) execSQL(ctx context.Context, scope *spScope, key ast.Node, stmt ast.StmtNode) ([]*ast.ResultField, [][]types.Datum, error) {
+	parsed, ok := e.stmts[key]
+	if ok {
+		for _, v := range parsed.vars {
+			bindVarValue(v.value, scope.localVar(v.ref))
+		}
+	} else {
+		var err error
+		if parsed, err = e.parseStmt(ctx, scope, stmt); err != nil {
+			return nil, nil, err
+		}
+		if e.stmts != nil {
+			e.stmts[key] = parsed
+		}
+	}
+	node := parsed.node
+	if e.inTrigger {
+		fields, chunkRows, warnings, err := executor.ExecSubStmt(ctx, e.se, node)
+		if err != nil {
+			return nil, nil, err
+		}
+		e.warnings = warnings
+		return fields, chunkRowsToDatums(fields, chunkRows), nil
+	}
+	rs, err := e.se.ExecuteStmt(ctx, node)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.warnings = e.se.sessionVars.StmtCtx.GetWarnings()
+	if rs == nil {
+		return nil, nil, nil
+	}
+	chunkRows, err := drainRecordSet(ctx, e.se, rs, nil)
+	if closeErr := rs.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := rs.Fields()
+	return fields, chunkRowsToDatums(fields, chunkRows), nil
+}
+
+func chunkRowsToDatums(fields []*ast.ResultField, chunkRows []chunk.Row) [][]types.Datum {
+	rows := make([][]types.Datum, 0, len(chunkRows))
+	for _, chunkRow := range chunkRows {
+		row := make([]types.Datum, len(fields))
+		for i, field := range fields {
+			d := chunkRow.GetDatum(i, &field.Column.FieldType)
+			d.Copy(&row[i])
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// spVarReplacer replaces the references to the local variables with the values of the variables.
+type spVarReplacer struct {
+	scope *spScope
+	vars  []spVarRef
+}
+
+// Enter implements ast.Visitor interface.
+func (r *spVarReplacer) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ValuesExpr, *ast.DefaultExpr:
+		return in, true
+	case *ast.SelectField:
+		// The column of the variable is named after the variable.
+		if x.AsName.L == "" && r.scope.localVar(x.Expr) != nil {
+			x.AsName = x.Expr.(*ast.ColumnNameExpr).Name.Name
+		}
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (r *spVarReplacer) Leave(in ast.Node) (ast.Node, bool) {
+	expr := asExpr(in)
+	if v := r.scope.localVar(expr); v != nil {
+		ve := &driver.ValueExpr{}
+		bindVarValue(ve, v)
+		ve.SetProjectionOffset(-1)
+		r.vars = append(r.vars, spVarRef{ref: expr, value: ve})
+		return ve, true
+	}
+	return in, true
+}
+
+func bindVarValue(ve *driver.ValueExpr, v *spVar) {
+	v.value.Copy(&ve.Datum)
+	ve.SetType(v.tp.Clone())
+}
+
+func asExpr(in ast.Node) ast.ExprNode {
+	expr, _ := in.(ast.ExprNode)
+	return expr
+}
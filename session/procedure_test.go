@@ -0,0 +1,199 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallProcedureParams(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create procedure p(in a int, out b varchar(10), inout c int) begin set b = concat('v', a); set c = c + a; set a = 100; end")
+	tk.MustExec("set @a = 1, @b = 'x', @c = 10")
+	tk.MustExec("call p(@a, @b, @c)")
+	tk.MustQuery("select @a, @b, @c").Check(testkit.Rows("1 v1 11"))
+	tk.MustExec("call p(@a + 1, @b, @c)")
+	tk.MustQuery("select @a, @b, @c").Check(testkit.Rows("1 v2 13"))
+
+	tk.MustGetErrCode("call p(1, @b)", errno.ErrSpWrongNoOfArgs)
+	tk.MustGetErrCode("call p(1, 'x', @c)", errno.ErrSpNotVarArg)
+	tk.MustGetErrCode("call no_such_proc()", errno.ErrSpDoesNotExist)
+
+	// The parameters and the local variables are typed.
+	tk.MustExec("create procedure q(out r varchar(20)) begin declare x int default 3; declare y decimal(5,2); set y = x / 4; set r = concat(x, ',', y); end")
+	tk.MustExec("call q(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("3,0.75"))
+
+	// OUT arguments can be the local variables of the caller.
+	tk.MustExec("create procedure outer_p(out r int) begin declare v int default 5; call p(1, @b, v); set r = v; end")
+	tk.MustExec("call outer_p(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("6"))
+}
+
+func TestCallProcedureControlFlow(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec(`create procedure p(n int, out r varchar(100))
+begin
+	declare i int default 0;
+	set r = '';
+	l1: while i < n do
+		set i = i + 1;
+		if i = 2 then
+			iterate l1;
+		elseif i > 5 then
+			leave l1;
+		end if;
+		set r = concat(r, i);
+	end while;
+	repeat
+		set i = i - 1;
+	until i <= 3 end repeat;
+	l2: loop
+		case i
+			when 3 then set r = concat(r, 'c');
+			else set r = concat(r, 'e');
+		end case;
+		set i = i - 1;
+		if i < 2 then
+			leave l2;
+		end if;
+	end loop;
+	case when n > 4 then set r = concat(r, '!'); end case;
+end`)
+	tk.MustExec("call p(10, @r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("1345ce!"))
+	tk.MustGetErrCode("call p(1, @r)", errno.ErrSpCaseNotFound)
+}
+
+func TestCallProcedureCursorAndHandler(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, 'c')")
+	tk.MustExec(`create procedure p(out total int, out names varchar(100))
+begin
+	declare done int default 0;
+	declare x int;
+	declare y varchar(10);
+	declare cur cursor for select a, b from t order by a;
+	declare continue handler for not found set done = 1;
+	set total = 0, names = '';
+	open cur;
+	fetch_loop: loop
+		fetch cur into x, y;
+		if done then
+			leave fetch_loop;
+		end if;
+		set total = total + x;
+		set names = concat(names, y);
+	end loop;
+	close cur;
+end`)
+	tk.MustExec("call p(@total, @names)")
+	tk.MustQuery("select @total, @names").Check(testkit.Rows("6 abc"))
+
+	// SELECT INTO local variables.
+	tk.MustExec("create procedure q(k int, out r varchar(10)) begin declare v varchar(10) default 'none'; select b into v from t where a = k; set r = v; end")
+	tk.MustExec("call q(2, @r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("b"))
+	tk.MustExec("call q(5, @r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("none"))
+	tk.MustExec("create procedure q2(k int, out r varchar(10)) select b into r from t where a = k")
+	tk.MustExec("call q2(5, @r)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1329 No data - zero rows fetched, selected, or processed"))
+	tk.MustQuery("select @r").Check(testkit.Rows("<nil>"))
+
+	// An EXIT handler leaves the block declaring it.
+	tk.MustExec(`create procedure e(out r varchar(20))
+begin
+	set r = 'start';
+	begin
+		declare exit handler for 1062 set r = concat(r, ',dup');
+		insert into t values (1, 'x');
+		set r = 'unreachable';
+	end;
+	set r = concat(r, ',end');
+end`)
+	tk.MustExec("call e(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("start,dup,end"))
+
+	// Errors without a handler are returned to the caller.
+	tk.MustExec("create procedure f() begin declare x int; declare c cursor for select a from t; fetch c into x; end")
+	tk.MustGetErrCode("call f()", errno.ErrSpCursorNotOpen)
+	tk.MustExec("create procedure g() insert into t values (1, 'x')")
+	tk.MustGetErrCode("call g()", errno.ErrDupEntry)
+}
+
+func TestCallProcedureResultSets(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create procedure p(a int) begin declare b int default a * 2; select a, b; select a + b as c; end")
+
+	stmt, err := parser.New().ParseOneStmt("call p(3)", "", "")
+	require.NoError(t, err)
+	rss, err := session.HandleCall(context.Background(), stmt.(*ast.CallStmt), tk.Session())
+	require.NoError(t, err)
+	require.Len(t, rss, 2)
+	require.Equal(t, "a", rss[0].Fields()[0].Column.Name.O)
+	require.Equal(t, "b", rss[0].Fields()[1].Column.Name.O)
+	tk.ResultSetToResult(rss[0], "first").Check(testkit.Rows("3 6"))
+	tk.ResultSetToResult(rss[1], "second").Check(testkit.Rows("9"))
+
+	tk.MustExec("create procedure r(n int) begin if n > 0 then call r(n - 1); end if; end")
+	tk.MustGetErrCode("call r(1)", errno.ErrSpRecursionLimit)
+	tk.MustExec("set @@max_sp_recursion_depth = 3")
+	tk.MustExec("call r(3)")
+	tk.MustGetErrCode("call r(4)", errno.ErrSpRecursionLimit)
+}
+
+func TestCallProcedurePrivilege(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create procedure p(out r int) set r = 1")
+	tk.MustExec("create user 'u1'@'%'")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustGetErrCode("call test.p(@r)", errno.ErrProcaccessDenied)
+	tk.MustExec("grant execute on test.* to 'u1'@'%'")
+	tk1.MustExec("call test.p(@r)")
+	tk1.MustQuery("select @r").Check(testkit.Rows("1"))
+}
@@ -2726,6 +2726,11 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 		return nil, err
 	}
 
+	err = dom.LoadUDFCacheLoop()
+	if err != nil {
+		return nil, err
+	}
+
 	if len(cfg.Instance.PluginLoad) > 0 {
 		err := plugin.Init(context.Background(), plugin.Config{EtcdClient: dom.GetEtcdClient()})
 		if err != nil {
@@ -2763,6 +2768,7 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 
 	dom.DumpFileGcCheckerLoop()
 	dom.LoadSigningCertLoop()
+	dom.EventSchedulerLoop()
 
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+)
+
+// triggerCacheKey identifies a trigger of a version of the table.
+type triggerCacheKey struct {
+	tableID  int64
+	updateTS uint64
+	name     string
+}
+
+// triggerBody is the parsed body of a trigger. It's cached in the statement context of the statement activating the
+// trigger, so the trigger is parsed once for the statement rather than for every row.
+type triggerBody struct {
+	body  ast.StmtNode
+	stmts map[ast.Node]*spStmt
+}
+
+func (s *session) triggerBody(tblInfo *model.TableInfo, trigger *model.TriggerInfo, sqlMode mysql.SQLMode) (*triggerBody, error) {
+	cache := s.sessionVars.StmtCtx.GetOrStoreStmtCache(stmtctx.StmtTriggerCacheKey, make(map[triggerCacheKey]*triggerBody)).(map[triggerCacheKey]*triggerBody)
+	key := triggerCacheKey{tableID: tblInfo.ID, updateTS: tblInfo.UpdateTS, name: trigger.Name.L}
+	if body, ok := cache[key]; ok {
+		return body, nil
+	}
+	p := parser.New()
+	p.SetSQLMode(sqlMode)
+	p.SetParserConfig(s.sessionVars.BuildParserConfig())
+	node, err := p.ParseOneStmt(executor.TriggerCreateStmt(tblInfo, trigger), "", "")
+	if err != nil {
+		return nil, err
+	}
+	body := &triggerBody{body: node.(*ast.CreateTriggerStmt).Body, stmts: make(map[ast.Node]*spStmt)}
+	cache[key] = body
+	return body, nil
+}
+
+// ExecTrigger implements sqlexec.TriggerExecutor interface. The body of the trigger is executed as a stored routine,
+// whose local variables are the columns of the NEW and OLD rows. The changes of the NEW row made by a trigger executed
+// before the event are written back to newRow.
+func (s *session) ExecTrigger(ctx context.Context, db string, tblInfo *model.TableInfo, trigger *model.TriggerInfo, oldRow, newRow []types.Datum) error {
+	sessVars := s.sessionVars
+	sqlMode, err := mysql.GetSQLMode(trigger.SQLMode)
+	if err != nil {
+		return err
+	}
+	body, err := s.triggerBody(tblInfo, trigger, sqlMode)
+	if err != nil {
+		return err
+	}
+
+	scope := newSpScope(nil)
+	newVars := make(map[int]*spVar, len(tblInfo.Columns))
+	for _, col := range tblInfo.Columns {
+		if col.State != model.StatePublic {
+			continue
+		}
+		if oldRow != nil {
+			v := &spVar{tp: &col.FieldType}
+			oldRow[col.Offset].Copy(&v.value)
+			scope.vars["old."+col.Name.L] = v
+		}
+		if newRow != nil {
+			v := &spVar{tp: &col.FieldType}
+			newRow[col.Offset].Copy(&v.value)
+			scope.vars["new."+col.Name.L] = v
+			newVars[col.Offset] = v
+		}
+	}
+
+	oldSQLMode, oldDB := sessVars.SQLMode, sessVars.CurrentDB
+	sessVars.SQLMode, sessVars.CurrentDB = sqlMode, db
+	sessVars.TriggerTables = append(sessVars.TriggerTables, tblInfo.ID)
+	defer func() {
+		sessVars.SQLMode, sessVars.CurrentDB = oldSQLMode, oldDB
+		sessVars.TriggerTables = sessVars.TriggerTables[:len(sessVars.TriggerTables)-1]
+	}()
+
+	e := &spExec{spCall: &spCall{se: s, inTrigger: true}, name: db + "." + trigger.Name.O, dbCollation: trigger.DBCollation, stmts: body.stmts}
+	if _, err = e.execStmt(ctx, scope, body.body); err != nil {
+		return err
+	}
+	if trigger.Time == model.TriggerBefore {
+		for offset, v := range newVars {
+			v.value.Copy(&newRow[offset])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerRows(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key auto_increment, a int, b varchar(20))")
+	tk.MustExec("create table log (id int auto_increment primary key, msg varchar(100))")
+	tk.MustExec("create trigger bi before insert on t for each row begin if new.a < 0 then set new.a = 0; end if; set new.b = concat('v', new.a); end")
+	tk.MustExec("create trigger ai after insert on t for each row insert into log (msg) values (concat('insert ', new.id, ' ', new.b))")
+	tk.MustExec("create trigger bu before update on t for each row set new.b = concat(old.b, '->', new.a)")
+	tk.MustExec("create trigger au after update on t for each row insert into log (msg) values (concat('update ', old.a, ' ', new.a))")
+	tk.MustExec("create trigger bd before delete on t for each row insert into log (msg) values (concat('delete ', old.id))")
+
+	tk.MustExec("insert into t (a) values (1), (-5)")
+	require.Equal(t, uint64(2), tk.Session().AffectedRows())
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1 v1", "2 0 v0"))
+	tk.MustExec("update t set a = a + 10 where id = 1")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 11 v1->11", "2 0 v0"))
+	tk.MustExec("insert into t values (2, 7, '') on duplicate key update a = 3")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 11 v1->11", "2 3 v0->3"))
+	tk.MustExec("replace into t values (1, 8, '')")
+	tk.MustExec("delete from t where id = 2")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 8 v8"))
+	tk.MustQuery("select msg from log order by id").Check(testkit.Rows(
+		"insert 1 v1", "insert 2 v0", "update 1 11", "update 0 3", "delete 1", "insert 1 v8", "delete 2"))
+
+	// The triggers are executed in the order of FOLLOWS and PRECEDES.
+	tk.MustExec("create table t2 (a varchar(20))")
+	tk.MustExec("create trigger t2_1 before insert on t2 for each row set new.a = concat(new.a, '1')")
+	tk.MustExec("create trigger t2_2 before insert on t2 for each row precedes t2_1 set new.a = concat(new.a, '2')")
+	tk.MustExec("insert into t2 values ('x')")
+	tk.MustQuery("select * from t2").Check(testkit.Rows("x21"))
+
+	// The parsed body of a trigger is executed for every row of the statement with the values of the row.
+	tk.MustExec("create table t3 (a int, b varchar(20))")
+	tk.MustExec("create trigger t3_bi before insert on t3 for each row begin declare i int default 0; declare s varchar(20) default ''; " +
+		"while i < new.a do set s = concat(s, i); set i = i + 1; end while; " +
+		"case new.a when 1 then set new.b = concat('one:', s); else set new.b = concat(new.b, ':', s); end case; " +
+		"set @last = new.b; end")
+	tk.MustExec("insert into t3 values (1, 'x'), (3, 'y'), (0, 'z'), (2, null)")
+	tk.MustQuery("select * from t3").Check(testkit.Rows("1 one:0", "3 y:012", "0 z:", "2 <nil>"))
+	tk.MustQuery("select @last").Check(testkit.Rows("<nil>"))
+}
+
+func TestTriggerErrors(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key)")
+	tk.MustExec("create table log (a int primary key)")
+	tk.MustExec("create trigger ai after insert on t for each row insert into log values (new.a % 10)")
+
+	// The statement fails and its changes are rolled back if a trigger fails.
+	tk.MustExec("insert into t values (1)")
+	tk.MustGetErrCode("insert into t values (2), (11)", errno.ErrDupEntry)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from log").Check(testkit.Rows("1"))
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (3)")
+	tk.MustGetErrCode("insert into t values (4), (13)", errno.ErrDupEntry)
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select * from log").Check(testkit.Rows("1", "3"))
+
+	// A trigger can't modify the table activating it.
+	tk.MustExec("create trigger bd before delete on t for each row delete from t where a = old.a + 1")
+	tk.MustGetErrCode("delete from t where a = 1", errno.ErrCantUpdateUsedTableInSfOrTrg)
+	tk.MustExec("create trigger bd2 before delete on log for each row insert into t values (old.a + 100)")
+	tk.MustExec("drop trigger bd")
+	tk.MustExec("create trigger bd before delete on t for each row delete from log where a = old.a")
+	tk.MustGetErrCode("delete from t where a = 1", errno.ErrCantUpdateUsedTableInSfOrTrg)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1", "3"))
+
+	// A trigger can't return a result set, even by a procedure.
+	tk.MustExec("drop trigger bd2")
+	tk.MustExec("create procedure p() select 1")
+	tk.MustExec("create trigger bu before update on t for each row call p()")
+	tk.MustGetErrCode("update t set a = 5 where a = 3", errno.ErrSpNoRetset)
+
+	// The NOT NULL constraint is checked after the triggers executed before the event.
+	tk.MustExec("create table t2 (a int not null, b int)")
+	tk.MustExec("create trigger bi before insert on t2 for each row set new.a = new.b")
+	tk.MustGetErrCode("insert into t2 values (1, null)", errno.ErrBadNull)
+	tk.MustExec("insert into t2 values (1, 2)")
+	tk.MustQuery("select * from t2").Check(testkit.Rows("2 2"))
+}
@@ -653,6 +653,10 @@ type SessionVars struct {
 	// InRestrictedSQL indicates if the session is handling restricted SQL execution.
 	InRestrictedSQL bool
 
+	// TriggerTables are the IDs of the tables whose triggers are being executed. It's not empty when the session is
+	// executing the statements of a trigger, which can't modify these tables.
+	TriggerTables []int64
+
 	// SnapshotTS is used for reading history data. For simplicity, SnapshotTS only supports distsql request.
 	SnapshotTS uint64
 
@@ -962,6 +966,9 @@ type SessionVars struct {
 	// EnableExtendedStats indicates whether we enable the extended statistics feature.
 	EnableExtendedStats bool
 
+	// EnableCardinalityCorrection indicates whether we learn and use the cardinality corrections from the query feedback.
+	EnableCardinalityCorrection bool
+
 	// Unexported fields should be accessed and set through interfaces like GetReplicaRead() and SetReplicaRead().
 
 	// allowInSubqToJoinAndAgg can be set to false to forbid rewriting the semi join to inner join with agg.
@@ -1022,6 +1029,10 @@ type SessionVars struct {
 	// OptimizerUseInvisibleIndexes indicates whether optimizer can use invisible index
 	OptimizerUseInvisibleIndexes bool
 
+	// HypoIndexes are the hypothetical indexes created by `CREATE INDEX ... TYPE HYPO` in this session, grouped by
+	// the table ID. They are only visible to the optimizer when explaining a statement without executing it.
+	HypoIndexes map[int64][]*model.IndexInfo
+
 	// SelectLimit limits the max counts of select statement's output
 	SelectLimit uint64
 
@@ -1034,6 +1045,19 @@ type SessionVars struct {
 	// EnableParallelApply indicates that thether to use parallel apply.
 	EnableParallelApply bool
 
+	// EnableRuntimeFilter indicates whether to use the runtime filters for the hash joins.
+	EnableRuntimeFilter bool
+
+	// EnableAdaptiveJoin indicates whether the index joins can switch to the hash joins at runtime.
+	EnableAdaptiveJoin bool
+
+	// EnablePartitionWiseJoin indicates whether the joins and aggregations of the co-partitioned tables are executed
+	// partition by partition.
+	EnablePartitionWiseJoin bool
+
+	// EnableMaterializedViewRewrite indicates whether to answer the queries with the fresh materialized views.
+	EnableMaterializedViewRewrite bool
+
 	// EnableRedactLog indicates that whether redact log.
 	EnableRedactLog bool
 
@@ -1407,6 +1431,7 @@ func NewSessionVars() *SessionVars {
 		UsePlanBaselines:            DefTiDBUsePlanBaselines,
 		EvolvePlanBaselines:         DefTiDBEvolvePlanBaselines,
 		EnableExtendedStats:         false,
+		EnableCardinalityCorrection: DefTiDBEnableCardinalityCorrection,
 		IsolationReadEngines:        make(map[kv.StoreType]struct{}),
 		LockWaitTimeout:             DefInnodbLockWaitTimeout * 1000,
 		MetricSchemaStep:            DefTiDBMetricSchemaStep,
@@ -1421,6 +1446,9 @@ func NewSessionVars() *SessionVars {
 		AllowAutoRandExplicitInsert: DefTiDBAllowAutoRandExplicitInsert,
 		EnableClusteredIndex:        DefTiDBEnableClusteredIndex,
 		EnableParallelApply:         DefTiDBEnableParallelApply,
+		EnableRuntimeFilter:         DefTiDBEnableRuntimeFilter,
+		EnableAdaptiveJoin:          DefTiDBEnableAdaptiveJoin,
+		EnablePartitionWiseJoin:     DefTiDBEnablePartitionWiseJoin,
 		ShardAllocateStep:           DefTiDBShardAllocateStep,
 		EnablePointGetCache:         DefTiDBPointGetCache,
 		EnableAmendPessimisticTxn:   DefTiDBEnableAmendPessimisticTxn,
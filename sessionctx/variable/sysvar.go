@@ -538,6 +538,9 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskMaxTime, Value: strconv.Itoa(DefTiDBEvolvePlanTaskMaxTime), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskStartTime, Value: DefTiDBEvolvePlanTaskStartTime, Type: TypeTime},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskEndTime, Value: DefTiDBEvolvePlanTaskEndTime, Type: TypeTime},
+	{Scope: ScopeGlobal, Name: TiDBEvolvePlanVerifyCount, Value: strconv.Itoa(DefTiDBEvolvePlanVerifyCount), Type: TypeUnsigned, MinValue: 1, MaxValue: 100},
+	{Scope: ScopeGlobal, Name: TiDBCapturePlanRegression, Value: BoolToOnOff(DefTiDBCapturePlanRegression), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: TiDBPlanRegressionRatio, Value: strconv.FormatFloat(DefTiDBPlanRegressionRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 1, MaxValue: math.MaxUint64},
 	{Scope: ScopeGlobal, Name: TiDBStoreLimit, Value: strconv.FormatInt(atomic.LoadInt64(&config.GetGlobalConfig().TiKVClient.StoreLimit), 10), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
 		return strconv.FormatInt(tikvstore.StoreLimit.Load(), 10), nil
 	}, SetGlobal: func(s *SessionVars, val string) error {
@@ -659,6 +662,16 @@ var defaultSysVars = []*SysVar{
 	}},
 	{Scope: ScopeGlobal, Name: SkipNameResolve, Value: Off, Type: TypeBool},
 	{Scope: ScopeGlobal, Name: DefaultAuthPlugin, Value: mysql.AuthNativePassword, Type: TypeEnum, PossibleValues: []string{mysql.AuthNativePassword, mysql.AuthCachingSha2Password}},
+	{Scope: ScopeGlobal, Name: ProtocolCompressionAlgorithms, Value: DefProtocolCompressionAlgorithms, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		return checkProtocolCompressionAlgorithms(normalizedValue, originalValue)
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		VarProtocolCompressionAlgorithms.Store(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: ZstdLevel, Value: strconv.Itoa(DefZstdLevel), Type: TypeUnsigned, MinValue: 1, MaxValue: 22, SetGlobal: func(s *SessionVars, val string) error {
+		VarZstdLevel.Store(int32(tidbOptPositiveInt32(val, DefZstdLevel)))
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBPersistAnalyzeOptions, Value: BoolToOnOff(DefTiDBPersistAnalyzeOptions), Type: TypeBool,
 		GetGlobal: func(s *SessionVars) (string, error) {
 			return BoolToOnOff(PersistAnalyzeOptions.Load()), nil
@@ -778,6 +791,15 @@ var defaultSysVars = []*SysVar{
 	}, GetGlobal: func(s *SessionVars) (string, error) {
 		return strconv.FormatUint(PreparedPlanCacheSize.Load(), 10), nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBCardinalityCorrectionCacheSize, Value: strconv.FormatUint(uint64(DefTiDBCardinalityCorrectionCacheSize), 10), Type: TypeUnsigned, MinValue: 1, MaxValue: 1000000, SetGlobal: func(s *SessionVars, val string) error {
+		uVal, err := strconv.ParseUint(val, 10, 64)
+		if err == nil {
+			CardinalityCorrectionCacheSize.Store(uVal)
+		}
+		return err
+	}, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(CardinalityCorrectionCacheSize.Load(), 10), nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBPrepPlanCacheMemoryGuardRatio, Value: strconv.FormatFloat(DefTiDBPrepPlanCacheMemoryGuardRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 0.0, MaxValue: 1.0, SetGlobal: func(s *SessionVars, val string) error {
 		f, err := strconv.ParseFloat(val, 64)
 		if err == nil {
@@ -869,6 +891,8 @@ var defaultSysVars = []*SysVar{
 		s.SetStatusFlag(mysql.ServerStatusNoBackslashEscaped, sqlMode.HasNoBackslashEscapesMode())
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: MaxSpRecursionDepth, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: 255},
+	{Scope: ScopeGlobal, Name: EventScheduler, Value: Off, Type: TypeBool},
 	{Scope: ScopeGlobal | ScopeSession, Name: MaxExecutionTime, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		timeoutMS := tidbOptPositiveInt32(val, 0)
 		s.MaxExecutionTime = uint64(timeoutMS)
@@ -1310,6 +1334,22 @@ var defaultSysVars = []*SysVar{
 		s.EnableParallelApply = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableRuntimeFilter, Value: BoolToOnOff(DefTiDBEnableRuntimeFilter), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableRuntimeFilter = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableAdaptiveJoin, Value: BoolToOnOff(DefTiDBEnableAdaptiveJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableAdaptiveJoin = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnablePartitionWiseJoin, Value: BoolToOnOff(DefTiDBEnablePartitionWiseJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnablePartitionWiseJoin = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableMaterializedViewRewrite, Value: BoolToOnOff(DefTiDBEnableMaterializedViewRewrite), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableMaterializedViewRewrite = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemQuotaApplyCache, Value: strconv.Itoa(DefTiDBMemQuotaApplyCache), Type: TypeUnsigned, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
 		s.MemQuotaApplyCache = TidbOptInt64(val, DefTiDBMemQuotaApplyCache)
 		return nil
@@ -1469,6 +1509,10 @@ var defaultSysVars = []*SysVar{
 		s.EnableExtendedStats = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableCardinalityCorrection, Value: BoolToOnOff(DefTiDBEnableCardinalityCorrection), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableCardinalityCorrection = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: CTEMaxRecursionDepth, Value: strconv.Itoa(DefCTEMaxRecursionDepth), Type: TypeInt, MinValue: 0, MaxValue: 4294967295, SetSession: func(s *SessionVars, val string) error {
 		s.CTEMaxRecursionDepth = TidbOptInt(val, DefCTEMaxRecursionDepth)
 		return nil
@@ -1789,6 +1833,12 @@ const (
 	MaxSortLength = "max_sort_length"
 	// MaxSpRecursionDepth is the name for 'max_sp_recursion_depth' system variable.
 	MaxSpRecursionDepth = "max_sp_recursion_depth"
+	// EventScheduler is the name for 'event_scheduler' system variable.
+	EventScheduler = "event_scheduler"
+	// ProtocolCompressionAlgorithms is the name for 'protocol_compression_algorithms' system variable.
+	ProtocolCompressionAlgorithms = "protocol_compression_algorithms"
+	// ZstdLevel is the name for 'zstd_level' system variable.
+	ZstdLevel = "zstd_level"
 	// MaxUserConnections is the name for 'max_user_connections' system variable.
 	MaxUserConnections = "max_user_connections"
 	// OfflineMode is the name for 'offline_mode' system variable.
@@ -988,6 +988,22 @@ func TestTiDBBatchPendingTiFlashCount(t *testing.T) {
 	require.EqualError(t, err, "[variable:1232]Incorrect argument type to variable 'tidb_batch_pending_tiflash_count'")
 }
 
+func TestProtocolCompressionAlgorithms(t *testing.T) {
+	sv := GetSysVar(ProtocolCompressionAlgorithms)
+	vars := NewSessionVars()
+
+	val, err := sv.Validate(vars, "ZSTD, zlib,zstd", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "zstd,zlib", val)
+
+	_, err = sv.Validate(vars, "zlib,lz4", ScopeGlobal)
+	require.EqualError(t, err, "[variable:1231]Variable 'protocol_compression_algorithms' can't be set to the value of 'zlib,lz4'")
+
+	require.NoError(t, sv.SetGlobalFromHook(vars, "zlib", false))
+	require.Equal(t, "zlib", VarProtocolCompressionAlgorithms.Load())
+	require.NoError(t, sv.SetGlobalFromHook(vars, DefProtocolCompressionAlgorithms, false))
+}
+
 func TestTiDBMemQuotaQuery(t *testing.T) {
 	sv := GetSysVar(TiDBMemQuotaQuery)
 	vars := NewSessionVars()
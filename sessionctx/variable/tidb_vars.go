@@ -189,6 +189,15 @@ const (
 	TiDBEvolvePlanTaskStartTime = "tidb_evolve_plan_task_start_time"
 	// TiDBEvolvePlanTaskEndTime is the end time of evolution task.
 	TiDBEvolvePlanTaskEndTime = "tidb_evolve_plan_task_end_time"
+	// TiDBEvolvePlanVerifyCount is the number of times each plan is executed when verifying a regressed plan.
+	TiDBEvolvePlanVerifyCount = "tidb_evolve_plan_verify_count"
+
+	// TiDBCapturePlanRegression indicates whether the previous plan of a statement is captured as a
+	// pending verify binding when its latest plan regresses.
+	TiDBCapturePlanRegression = "tidb_capture_plan_regression"
+	// TiDBPlanRegressionRatio is the ratio of the average latency of the latest plan to the one of the
+	// previous plan above which the latest plan is regarded as regressed.
+	TiDBPlanRegressionRatio = "tidb_plan_regression_ratio"
 
 	// TiDBSlowLogThreshold is used to set the slow log threshold in the server.
 	TiDBSlowLogThreshold = "tidb_slow_log_threshold"
@@ -423,6 +432,22 @@ const (
 	// TiDBEnableParallelApply is used for parallel apply.
 	TiDBEnableParallelApply = "tidb_enable_parallel_apply"
 
+	// TiDBEnableRuntimeFilter indicates whether the hash joins build the runtime filters with the join keys of the
+	// build side, and use them to filter the rows scanned by the probe side.
+	TiDBEnableRuntimeFilter = "tidb_enable_runtime_filter"
+
+	// TiDBEnableAdaptiveJoin indicates whether the index joins switch to the hash joins at runtime when the outer sides
+	// have many more rows than estimated.
+	TiDBEnableAdaptiveJoin = "tidb_enable_adaptive_join"
+
+	// TiDBEnablePartitionWiseJoin indicates whether the joins and aggregations of the tables partitioned identically on
+	// the join keys or the group-by items are executed partition by partition in parallel under the dynamic prune mode.
+	TiDBEnablePartitionWiseJoin = "tidb_enable_partition_wise_join"
+
+	// TiDBEnableMaterializedViewRewrite indicates whether the queries are answered with the materialized views which
+	// have the same definitions and are fresh.
+	TiDBEnableMaterializedViewRewrite = "tidb_enable_materialized_view_rewrite"
+
 	// TiDBBackoffLockFast is used for tikv backoff base time in milliseconds.
 	TiDBBackoffLockFast = "tidb_backoff_lock_fast"
 
@@ -540,6 +565,10 @@ const (
 	// TiDBEnableExtendedStats indicates whether the extended statistics feature is enabled.
 	TiDBEnableExtendedStats = "tidb_enable_extended_stats"
 
+	// TiDBEnableCardinalityCorrection indicates whether the optimizer learns the cardinality corrections from the
+	// actual row counts of the executed plans, and uses them to adjust the estimated row counts.
+	TiDBEnableCardinalityCorrection = "tidb_enable_cardinality_correction"
+
 	// TiDBIsolationReadEngines indicates the tidb only read from the stores whose engine type is involved in IsolationReadEngines.
 	// Now, only support TiKV and TiFlash.
 	TiDBIsolationReadEngines = "tidb_isolation_read_engines"
@@ -771,6 +800,8 @@ const (
 	TiDBPrepPlanCacheSize = "tidb_prepared_plan_cache_size"
 	// TiDBPrepPlanCacheMemoryGuardRatio is used to prevent [performance.max-memory] from being exceeded
 	TiDBPrepPlanCacheMemoryGuardRatio = "tidb_prepared_plan_cache_memory_guard_ratio"
+	// TiDBCardinalityCorrectionCacheSize indicates the max number of the cardinality corrections cached in a TiDB instance.
+	TiDBCardinalityCorrectionCacheSize = "tidb_cardinality_correction_cache_size"
 	// TiDBMaxAutoAnalyzeTime is the max time that auto analyze can run. If auto analyze runs longer than the value, it
 	// will be killed. 0 indicates that there is no time limit.
 	TiDBMaxAutoAnalyzeTime = "tidb_max_auto_analyze_time"
@@ -910,6 +941,9 @@ const (
 	DefTiDBEvolvePlanTaskMaxTime                   = 600 // 600s
 	DefTiDBEvolvePlanTaskStartTime                 = "00:00 +0000"
 	DefTiDBEvolvePlanTaskEndTime                   = "23:59 +0000"
+	DefTiDBEvolvePlanVerifyCount                   = 3
+	DefTiDBCapturePlanRegression                   = false
+	DefTiDBPlanRegressionRatio                     = 1.5
 	DefInnodbLockWaitTimeout                       = 50 // 50s
 	DefTiDBStoreLimit                              = 0
 	DefTiDBMetricSchemaStep                        = 60 // 60s
@@ -925,6 +959,10 @@ const (
 	DefTiDBShardAllocateStep                       = math.MaxInt64
 	DefTiDBEnableTelemetry                         = true
 	DefTiDBEnableParallelApply                     = false
+	DefTiDBEnableRuntimeFilter                     = false
+	DefTiDBEnableAdaptiveJoin                      = false
+	DefTiDBEnablePartitionWiseJoin                 = false
+	DefTiDBEnableMaterializedViewRewrite           = true
 	DefTiDBEnableAmendPessimisticTxn               = false
 	DefTiDBPartitionPruneMode                      = "static"
 	DefTiDBEnableRateLimitAction                   = true
@@ -970,6 +1008,8 @@ const (
 	DefTiDBReadStaleness                           = 0
 	DefTiDBGCMaxWaitTime                           = 24 * 60 * 60
 	DefMaxAllowedPacket                     uint64 = 67108864
+	DefProtocolCompressionAlgorithms               = "zlib,zstd,uncompressed"
+	DefZstdLevel                                   = 3
 	DefTiDBEnableBatchDML                          = false
 	DefTiDBMemQuotaQuery                           = 1073741824 // 1GB
 	DefTiDBStatsCacheMemQuota                      = 0
@@ -985,6 +1025,8 @@ const (
 	DefTiDBEnablePrepPlanCache                     = true
 	DefTiDBPrepPlanCacheSize                       = 100
 	DefTiDBPrepPlanCacheMemoryGuardRatio           = 0.1
+	DefTiDBEnableCardinalityCorrection             = false
+	DefTiDBCardinalityCorrectionCacheSize          = 1000
 	DefTiDBEnableConcurrentDDL                     = concurrencyddl.TiDBEnableConcurrentDDL
 	DefTiDBSimplifiedMetrics                       = false
 	DefTiDBEnablePaging                            = true
@@ -1042,8 +1084,12 @@ var (
 	EnablePreparedPlanCache           = atomic.NewBool(DefTiDBEnablePrepPlanCache)
 	PreparedPlanCacheSize             = atomic.NewUint64(DefTiDBPrepPlanCacheSize)
 	PreparedPlanCacheMemoryGuardRatio = atomic.NewFloat64(DefTiDBPrepPlanCacheMemoryGuardRatio)
+	CardinalityCorrectionCacheSize    = atomic.NewUint64(DefTiDBCardinalityCorrectionCacheSize)
 	EnableConcurrentDDL               = atomic.NewBool(DefTiDBEnableConcurrentDDL)
 	EnableNoopVariables               = atomic.NewBool(DefTiDBEnableNoopVariables)
+	// variables for protocol compression
+	VarProtocolCompressionAlgorithms = atomic.NewString(DefProtocolCompressionAlgorithms)
+	VarZstdLevel                     = atomic.NewInt32(DefZstdLevel)
 )
 
 var (
@@ -121,6 +121,24 @@ func checkCharacterSet(normalizedValue string, argName string) (string, error) {
 	return cs.Name, nil
 }
 
+// checkProtocolCompressionAlgorithms checks the comma separated algorithms of protocol_compression_algorithms,
+// the duplicate ones are removed.
+func checkProtocolCompressionAlgorithms(normalizedValue string, originalValue string) (string, error) {
+	algorithms := make([]string, 0, 3)
+	for _, algorithm := range strings.Split(normalizedValue, ",") {
+		algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+		switch algorithm {
+		case mysql.CompressionZlib, mysql.CompressionZstd, mysql.CompressionUncompressed:
+		default:
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(ProtocolCompressionAlgorithms, originalValue)
+		}
+		if !slices.Contains(algorithms, algorithm) {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	return strings.Join(algorithms, ","), nil
+}
+
 // checkReadOnly requires TiDBEnableNoopFuncs=1 for the same scope otherwise an error will be returned.
 func checkReadOnly(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag, offlineMode bool) (string, error) {
 	errMsg := ErrFunctionsNoopImpl.GenWithStackByArgs("READ ONLY")
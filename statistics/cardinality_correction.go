@@ -0,0 +1,168 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/kvcache"
+)
+
+// cardCorrectionSmoothFactor is the weight of the latest feedback when it is merged into the learned correction ratio.
+const cardCorrectionSmoothFactor = 0.5
+
+// CardCorrectionFeedback is the estimated and actual row count of the predicates on a table, which is collected
+// from the runtime stats after a statement is executed.
+type CardCorrectionFeedback struct {
+	// TableID is the physical table ID.
+	TableID int64
+	// Predicates is the signature of the predicates on the table.
+	Predicates string
+	// StatsVersion is the version of the table stats used to make the estimation.
+	StatsVersion uint64
+	// EstRows is the estimated row count before it is corrected.
+	EstRows float64
+	// ActRows is the actual row count.
+	ActRows float64
+}
+
+// CardCorrection is the cardinality correction learned from the query feedback of the predicates on a table.
+type CardCorrection struct {
+	TableID      int64
+	Predicates   string
+	StatsVersion uint64
+	// EstRows and ActRows are the ones of the latest feedback.
+	EstRows float64
+	ActRows float64
+	// Ratio is the learned ratio of the actual row count to the estimated one.
+	Ratio          float64
+	FeedbackCount  int64
+	LastUpdateTime time.Time
+}
+
+type cardCorrectionKey struct {
+	tableID    int64
+	predicates string
+}
+
+// Hash implements kvcache.Key interface.
+func (k cardCorrectionKey) Hash() []byte {
+	b := codec.EncodeInt(make([]byte, 0, 8+len(k.predicates)), k.tableID)
+	return append(b, k.predicates...)
+}
+
+// CardCorrectionCache is a bounded LRU cache of the cardinality corrections. The corrections are only kept in memory
+// and are never written back to the persisted histograms.
+type CardCorrectionCache struct {
+	mu       sync.Mutex
+	capacity uint
+	cache    *kvcache.SimpleLRUCache
+}
+
+// NewCardCorrectionCache creates a CardCorrectionCache.
+func NewCardCorrectionCache() *CardCorrectionCache {
+	capacity := uint(variable.CardinalityCorrectionCacheSize.Load())
+	return &CardCorrectionCache{
+		capacity: capacity,
+		cache:    kvcache.NewSimpleLRUCache(capacity, 0, 0),
+	}
+}
+
+// adjustCapacity syncs the capacity of the cache with the system variable. The caller should hold the lock.
+func (c *CardCorrectionCache) adjustCapacity() {
+	capacity := uint(variable.CardinalityCorrectionCacheSize.Load())
+	if capacity != c.capacity && c.cache.SetCapacity(capacity) == nil {
+		c.capacity = capacity
+	}
+}
+
+// Update merges the feedback into the cache. The correction learned on an outdated version of the table stats
+// is discarded.
+func (c *CardCorrectionCache) Update(fb *CardCorrectionFeedback) {
+	ratio := math.Max(fb.ActRows, 1) / math.Max(fb.EstRows, 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.adjustCapacity()
+	key := cardCorrectionKey{tableID: fb.TableID, predicates: fb.Predicates}
+	item := &CardCorrection{
+		TableID:      fb.TableID,
+		Predicates:   fb.Predicates,
+		StatsVersion: fb.StatsVersion,
+		Ratio:        ratio,
+	}
+	if val, ok := c.cache.Get(key); ok {
+		old := val.(*CardCorrection)
+		if old.StatsVersion == fb.StatsVersion {
+			// Use the weighted geometric mean, since the ratio may vary by orders of magnitude.
+			item.Ratio = math.Pow(old.Ratio, 1-cardCorrectionSmoothFactor) * math.Pow(ratio, cardCorrectionSmoothFactor)
+			item.FeedbackCount = old.FeedbackCount
+		}
+	}
+	item.EstRows, item.ActRows = fb.EstRows, fb.ActRows
+	item.FeedbackCount++
+	item.LastUpdateTime = time.Now()
+	c.cache.Put(key, item)
+}
+
+// Get returns the correction ratio of the predicates on the table. It returns false if the correction does not
+// exist or it is learned on another version of the table stats.
+func (c *CardCorrectionCache) Get(tableID int64, predicates string, statsVersion uint64) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, ok := c.cache.Get(cardCorrectionKey{tableID: tableID, predicates: predicates})
+	if !ok {
+		return 0, false
+	}
+	item := val.(*CardCorrection)
+	if item.StatsVersion != statsVersion {
+		return 0, false
+	}
+	return item.Ratio, true
+}
+
+// Reset removes the corrections on the given tables. All the corrections are removed if no table is given.
+func (c *CardCorrectionCache) Reset(tableIDs ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(tableIDs) == 0 {
+		c.cache.DeleteAll()
+		return
+	}
+	idSet := make(map[int64]struct{}, len(tableIDs))
+	for _, id := range tableIDs {
+		idSet[id] = struct{}{}
+	}
+	for _, key := range c.cache.Keys() {
+		if _, ok := idSet[key.(cardCorrectionKey).tableID]; ok {
+			c.cache.Delete(key)
+		}
+	}
+}
+
+// Corrections returns the copies of all the corrections in the cache, from the most recently used to the least.
+func (c *CardCorrectionCache) Corrections() []CardCorrection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := c.cache.Values()
+	items := make([]CardCorrection, 0, len(values))
+	for _, val := range values {
+		items = append(items, *val.(*CardCorrection))
+	}
+	return items
+}
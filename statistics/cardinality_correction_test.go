@@ -0,0 +1,69 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardCorrectionCache(t *testing.T) {
+	origSize := variable.CardinalityCorrectionCacheSize.Load()
+	defer variable.CardinalityCorrectionCacheSize.Store(origSize)
+	variable.CardinalityCorrectionCacheSize.Store(2)
+
+	c := NewCardCorrectionCache()
+	c.Update(&CardCorrectionFeedback{TableID: 1, Predicates: "eq(test.t.a, 1)", StatsVersion: 10, EstRows: 10, ActRows: 40})
+	ratio, ok := c.Get(1, "eq(test.t.a, 1)", 10)
+	require.True(t, ok)
+	require.Equal(t, 4.0, ratio)
+	// The correction learned on another version of stats is not used.
+	_, ok = c.Get(1, "eq(test.t.a, 1)", 11)
+	require.False(t, ok)
+
+	// The ratios are merged by the geometric mean.
+	c.Update(&CardCorrectionFeedback{TableID: 1, Predicates: "eq(test.t.a, 1)", StatsVersion: 10, EstRows: 10, ActRows: 10})
+	ratio, ok = c.Get(1, "eq(test.t.a, 1)", 10)
+	require.True(t, ok)
+	require.InDelta(t, 2.0, ratio, 1e-9)
+	require.Equal(t, int64(2), c.Corrections()[0].FeedbackCount)
+	// The correction is relearned if the stats are updated.
+	c.Update(&CardCorrectionFeedback{TableID: 1, Predicates: "eq(test.t.a, 1)", StatsVersion: 11, EstRows: 10, ActRows: 5})
+	ratio, ok = c.Get(1, "eq(test.t.a, 1)", 11)
+	require.True(t, ok)
+	require.Equal(t, 0.5, ratio)
+	require.Equal(t, int64(1), c.Corrections()[0].FeedbackCount)
+
+	// The least recently used correction is evicted.
+	c.Update(&CardCorrectionFeedback{TableID: 2, Predicates: "eq(test.t2.a, 1)", StatsVersion: 10, EstRows: 10, ActRows: 5})
+	c.Update(&CardCorrectionFeedback{TableID: 3, Predicates: "eq(test.t3.a, 1)", StatsVersion: 10, EstRows: 10, ActRows: 5})
+	require.Len(t, c.Corrections(), 2)
+	_, ok = c.Get(1, "eq(test.t.a, 1)", 11)
+	require.False(t, ok)
+	// The capacity follows the system variable.
+	variable.CardinalityCorrectionCacheSize.Store(3)
+	c.Update(&CardCorrectionFeedback{TableID: 1, Predicates: "eq(test.t.a, 1)", StatsVersion: 11, EstRows: 10, ActRows: 5})
+	require.Len(t, c.Corrections(), 3)
+
+	c.Reset(2)
+	items := c.Corrections()
+	require.Len(t, items, 2)
+	require.Equal(t, int64(1), items[0].TableID)
+	require.Equal(t, int64(3), items[1].TableID)
+	c.Reset()
+	require.Len(t, c.Corrections(), 0)
+}
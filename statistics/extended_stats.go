@@ -0,0 +1,438 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/mathutil"
+	"github.com/pingcap/tidb/util/ranger"
+	"golang.org/x/exp/slices"
+)
+
+// colGroupStatsVals is the format of the Cardinality and Histogram extended stats stored in mysql.stats_extended.
+type colGroupStatsVals struct {
+	NDV      float64    `json:"ndv"`
+	Count    int64      `json:"count"`
+	TopN     []TopNMeta `json:"topn,omitempty"`
+	FMSketch []byte     `json:"fm_sketch,omitempty"`
+}
+
+// EncodeVals encodes the values of the item to the string stored in mysql.stats_extended.
+func (item *ExtendedStatsItem) EncodeVals() (string, error) {
+	switch item.Tp {
+	case ast.StatsTypeCardinality, ast.StatsTypeHistogram:
+		vals := colGroupStatsVals{NDV: item.ScalarVals, Count: item.Count}
+		if item.TopN != nil {
+			vals.TopN = item.TopN.TopN
+		}
+		data, err := EncodeFMSketch(item.FMSketch)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		vals.FMSketch = data
+		bytes, err := json.Marshal(&vals)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return string(bytes), nil
+	}
+	return fmt.Sprintf("%f", item.ScalarVals), nil
+}
+
+// DecodeVals decodes the string stored in mysql.stats_extended to the values of the item.
+func (item *ExtendedStatsItem) DecodeVals(statsStr string) (err error) {
+	if statsStr == "" {
+		return nil
+	}
+	switch item.Tp {
+	case ast.StatsTypeCardinality, ast.StatsTypeHistogram:
+		var vals colGroupStatsVals
+		if err = json.Unmarshal([]byte(statsStr), &vals); err != nil {
+			return errors.Trace(err)
+		}
+		item.ScalarVals, item.Count, item.StringVals = vals.NDV, vals.Count, statsStr
+		if len(vals.TopN) > 0 {
+			item.TopN = &TopN{TopN: vals.TopN}
+			item.TopN.Sort()
+		}
+		if len(vals.FMSketch) > 0 {
+			item.FMSketch, err = DecodeFMSketch(vals.FMSketch)
+		}
+		return err
+	}
+	item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
+	return errors.Trace(err)
+}
+
+// ExtStatsRowSamples contains the row samples collected by ANALYZE version 2, which are used to build
+// the extended stats on column groups.
+type ExtStatsRowSamples struct {
+	Samples []*ReservoirRowSampleItem
+	// Count is the row count of the table.
+	Count   int64
+	NumTopN uint32
+	// ColGroups are the column groups pushed down to the storage layer, and FMSketches are their
+	// FMSketches built on the whole data rather than the samples.
+	ColGroups  [][]int64
+	FMSketches []*FMSketch
+	// KeepFMSketch indicates whether the FMSketches should be kept in the built stats, i.e, whether
+	// the stats would be merged into the global-level stats later.
+	KeepFMSketch bool
+}
+
+// BuildColGroupStats fills the values of the item whose type is Cardinality, Dependency or Histogram.
+// colOffsets are the offsets of the grouped columns in the row samples.
+func (s *ExtStatsRowSamples) BuildColGroupStats(sc *stmtctx.StatementContext, item *ExtendedStatsItem, colOffsets []int) (*ExtendedStatsItem, error) {
+	switch item.Tp {
+	case ast.StatsTypeDependency:
+		if len(colOffsets) != 2 {
+			return nil, nil
+		}
+		return s.buildDependency(sc, item, colOffsets)
+	case ast.StatsTypeCardinality, ast.StatsTypeHistogram:
+		return s.buildCardinalityAndHistogram(sc, item, colOffsets)
+	}
+	return nil, nil
+}
+
+// buildDependency calculates the degree of the functional dependency colOffsets[0] => colOffsets[1], that
+// is, the fraction of rows whose value of the second column can be determined by the first column.
+func (s *ExtStatsRowSamples) buildDependency(sc *stmtctx.StatementContext, item *ExtendedStatsItem, colOffsets []int) (*ExtendedStatsItem, error) {
+	if len(s.Samples) == 0 {
+		item.ScalarVals = 0
+		return item, nil
+	}
+	// groups maps each value of the determinant column to the frequencies of the dependent column values.
+	groups := make(map[string]map[string]int64)
+	for _, row := range s.Samples {
+		x, err := codec.EncodeKey(sc, nil, row.Columns[colOffsets[0]])
+		if err != nil {
+			return nil, err
+		}
+		y, err := codec.EncodeKey(sc, nil, row.Columns[colOffsets[1]])
+		if err != nil {
+			return nil, err
+		}
+		freqs, ok := groups[string(x)]
+		if !ok {
+			freqs = make(map[string]int64)
+			groups[string(x)] = freqs
+		}
+		freqs[string(y)]++
+	}
+	var supportRows int64
+	for _, freqs := range groups {
+		var maxFreq int64
+		for _, freq := range freqs {
+			maxFreq = mathutil.Max(maxFreq, freq)
+		}
+		supportRows += maxFreq
+	}
+	item.ScalarVals = float64(supportRows) / float64(len(s.Samples))
+	return item, nil
+}
+
+func (s *ExtStatsRowSamples) buildCardinalityAndHistogram(sc *stmtctx.StatementContext, item *ExtendedStatsItem, colOffsets []int) (*ExtendedStatsItem, error) {
+	item.Count = s.Count
+	var fms *FMSketch
+	for i, group := range s.ColGroups {
+		if slices.Equal(group, item.ColIDs) {
+			fms = s.FMSketches[i]
+			break
+		}
+	}
+	if fms != nil {
+		item.ScalarVals = float64(fms.NDV())
+		if s.KeepFMSketch {
+			item.FMSketch = fms.Copy()
+		}
+	}
+	if len(s.Samples) == 0 {
+		return item, nil
+	}
+	sample := make([][]byte, 0, len(s.Samples))
+	datums := make([]types.Datum, len(colOffsets))
+	for _, row := range s.Samples {
+		for i, offset := range colOffsets {
+			datums[i] = row.Columns[offset]
+		}
+		b, err := codec.EncodeKey(sc, nil, datums...)
+		if err != nil {
+			return nil, err
+		}
+		sample = append(sample, b)
+	}
+	helper := newTopNHelper(sample, s.NumTopN)
+	rowCount := mathutil.Max(uint64(s.Count), uint64(len(sample)))
+	if fms == nil {
+		ndv, _ := calculateEstimateNDV(helper, rowCount)
+		item.ScalarVals = float64(ndv)
+	}
+	if item.Tp == ast.StatsTypeHistogram {
+		scaleRatio := float64(rowCount) / float64(len(sample))
+		topN := NewTopN(int(helper.actualNumTop))
+		for i := uint32(0); i < helper.actualNumTop; i++ {
+			topN.AppendTopN(helper.sorted[i].data, uint64(float64(helper.sorted[i].cnt)*scaleRatio+0.5))
+		}
+		topN.Sort()
+		item.TopN = topN
+	}
+	return item, nil
+}
+
+// MergePartExtendedStats2Global merges the partition-level extended stats into the global-level ones.
+// counts are the row counts of the partitions. The extended stats which are not collected on all the
+// partitions are skipped.
+func MergePartExtendedStats2Global(partStats []*ExtendedStatsColl, counts []int64, numTopN uint32) *ExtendedStatsColl {
+	if len(partStats) == 0 || partStats[0] == nil {
+		return nil
+	}
+	var totalCount int64
+	for _, count := range counts {
+		totalCount += count
+	}
+	globalStats := NewExtendedStatsColl()
+	for name, first := range partStats[0].Stats {
+		items := make([]*ExtendedStatsItem, 0, len(partStats))
+		for _, coll := range partStats {
+			if coll == nil {
+				break
+			}
+			item, ok := coll.Stats[name]
+			if !ok || item.Tp != first.Tp || !slices.Equal(item.ColIDs, first.ColIDs) {
+				break
+			}
+			items = append(items, item)
+		}
+		if len(items) != len(partStats) {
+			continue
+		}
+		globalStats.Stats[name] = mergePartExtendedStatsItems(items, counts, totalCount, numTopN)
+	}
+	if len(globalStats.Stats) == 0 {
+		return nil
+	}
+	return globalStats
+}
+
+func mergePartExtendedStatsItems(items []*ExtendedStatsItem, counts []int64, totalCount int64, numTopN uint32) *ExtendedStatsItem {
+	merged := &ExtendedStatsItem{ColIDs: items[0].ColIDs, Tp: items[0].Tp}
+	switch merged.Tp {
+	case ast.StatsTypeCorrelation, ast.StatsTypeDependency:
+		// Use the average weighted by the row counts of the partitions.
+		if totalCount == 0 {
+			return merged
+		}
+		for i, item := range items {
+			merged.ScalarVals += item.ScalarVals * float64(counts[i])
+		}
+		merged.ScalarVals /= float64(totalCount)
+	case ast.StatsTypeCardinality, ast.StatsTypeHistogram:
+		var ndvSum float64
+		fms := items[0].FMSketch.Copy()
+		topNs := make([]*TopN, 0, len(items))
+		for i, item := range items {
+			merged.Count += item.Count
+			ndvSum += item.ScalarVals
+			if item.FMSketch == nil {
+				fms = nil
+			} else if i > 0 {
+				fms.MergeFMSketch(item.FMSketch)
+			}
+			topNs = append(topNs, item.TopN)
+		}
+		if fms != nil {
+			merged.ScalarVals = float64(fms.NDV())
+		} else {
+			// The sum of the partition-level NDVs is an upper bound of the global-level NDV.
+			merged.ScalarVals = math.Min(ndvSum, float64(merged.Count))
+		}
+		if merged.Tp == ast.StatsTypeHistogram {
+			merged.TopN, _ = MergeTopN(topNs, numTopN)
+		}
+	}
+	return merged
+}
+
+// ColGroupStatsByUniqueID returns the extended stats on column groups whose column IDs are mapped to the
+// unique IDs of the given columns. The extended stats on the columns not in the given ones are skipped.
+func (t *Table) ColGroupStatsByUniqueID(columns []*expression.Column) []*ExtendedStatsItem {
+	if t.ExtendedStats == nil || len(t.ExtendedStats.Stats) == 0 {
+		return nil
+	}
+	colID2UniqueID := make(map[int64]int64, len(columns))
+	for _, col := range columns {
+		colID2UniqueID[col.ID] = col.UniqueID
+	}
+	var items []*ExtendedStatsItem
+	for _, item := range t.ExtendedStats.Stats {
+		if item.Tp == ast.StatsTypeCorrelation {
+			continue
+		}
+		uniqueIDs := make([]int64, 0, len(item.ColIDs))
+		for _, colID := range item.ColIDs {
+			uniqueID, ok := colID2UniqueID[colID]
+			if !ok {
+				break
+			}
+			uniqueIDs = append(uniqueIDs, uniqueID)
+		}
+		if len(uniqueIDs) != len(item.ColIDs) {
+			continue
+		}
+		newItem := *item
+		newItem.ColIDs = uniqueIDs
+		items = append(items, &newItem)
+	}
+	// Keep the order stable since the order of the map iteration is random.
+	slices.SortFunc(items, func(i, j *ExtendedStatsItem) bool {
+		if i.Tp != j.Tp {
+			return i.Tp < j.Tp
+		}
+		return slices.Compare(i.ColIDs, j.ColIDs) < 0
+	})
+	return items
+}
+
+// getColGroupStatsNodes builds the StatsNodes for the conditions which can be estimated by the extended
+// stats on column groups.
+func (coll *HistColl) getColGroupStatsNodes(ctx sessionctx.Context, exprs []expression.Expression, cols []*expression.Column) ([]*StatsNode, error) {
+	nodes := make([]*StatsNode, 0, len(coll.ColGroupStats))
+	for i, item := range coll.ColGroupStats {
+		groupCols := make([]*expression.Column, 0, len(item.ColIDs))
+		for _, id := range item.ColIDs {
+			for _, col := range cols {
+				if col.UniqueID == id {
+					groupCols = append(groupCols, col)
+					break
+				}
+			}
+		}
+		if len(groupCols) != len(item.ColIDs) {
+			continue
+		}
+		var node *StatsNode
+		var err error
+		switch item.Tp {
+		case ast.StatsTypeCardinality, ast.StatsTypeHistogram:
+			node, err = coll.getColGroupNode(ctx, item, exprs, groupCols)
+		case ast.StatsTypeDependency:
+			node, err = coll.getDependencyNode(ctx, item, exprs, groupCols)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			node.ID = int64(i)
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// getColGroupNode estimates the point conditions on all the grouped columns by the NDV and the joint TopN
+// of the column group. The values not in the TopN are assumed to be uniformly distributed.
+func (coll *HistColl) getColGroupNode(ctx sessionctx.Context, item *ExtendedStatsItem, exprs []expression.Expression, cols []*expression.Column) (*StatsNode, error) {
+	if item.Count <= 0 || item.ScalarVals <= 0 {
+		return nil, nil
+	}
+	lengths := make([]int, 0, len(cols))
+	for range cols {
+		lengths = append(lengths, types.UnspecifiedLength)
+	}
+	mask, ranges, partCover, err := getMaskAndRanges(ctx, exprs, ranger.IndexRangeType, lengths, nil, cols...)
+	if err != nil || mask == 0 || partCover || len(ranges) == 0 {
+		return nil, err
+	}
+	for _, ran := range ranges {
+		if len(ran.LowVal) != len(cols) || !ran.IsPointNullable(ctx) {
+			return nil, nil
+		}
+	}
+	topNCount, topNNum := float64(item.TopN.TotalCount()), float64(item.TopN.Num())
+	avgCount := math.Max(float64(item.Count)-topNCount, 0) / math.Max(item.ScalarVals-topNNum, 1)
+	sc := ctx.GetSessionVars().StmtCtx
+	var rowCount float64
+	for _, ran := range ranges {
+		val, err := codec.EncodeKey(sc, nil, ran.LowVal...)
+		if err != nil {
+			return nil, err
+		}
+		if cnt, ok := item.TopN.QueryTopN(val); ok {
+			rowCount += float64(cnt)
+			continue
+		}
+		rowCount += math.Max(avgCount, 1)
+	}
+	return &StatsNode{
+		Tp:          ColGroupType,
+		mask:        mask,
+		Ranges:      ranges,
+		numCols:     len(cols),
+		Selectivity: math.Min(rowCount/float64(item.Count), 1),
+	}, nil
+}
+
+// getDependencyNode estimates the point conditions on the two columns of a functional dependency a => b by
+// P(a, b) = P(a) * (d + (1 - d) * P(b)), where d is the degree of the dependency.
+func (coll *HistColl) getDependencyNode(ctx sessionctx.Context, item *ExtendedStatsItem, exprs []expression.Expression, cols []*expression.Column) (*StatsNode, error) {
+	var mask int64
+	sels := make([]float64, 0, len(cols))
+	for _, col := range cols {
+		colHist := coll.Columns[col.UniqueID]
+		if colHist == nil || colHist.IsInvalid(ctx, coll.Pseudo) {
+			return nil, nil
+		}
+		colMask, ranges, _, err := getMaskAndRanges(ctx, exprs, ranger.ColumnRangeType, nil, nil, col)
+		if err != nil || colMask == 0 || len(ranges) == 0 {
+			return nil, err
+		}
+		for _, ran := range ranges {
+			if !ran.IsPointNullable(ctx) {
+				return nil, nil
+			}
+		}
+		var cnt float64
+		if colHist.IsHandle {
+			cnt, err = coll.GetRowCountByIntColumnRanges(ctx, col.UniqueID, ranges)
+		} else {
+			cnt, err = coll.GetRowCountByColumnRanges(ctx, col.UniqueID, ranges)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mask |= colMask
+		sels = append(sels, cnt/float64(coll.Count))
+	}
+	degree := item.ScalarVals
+	selectivity := sels[0] * (degree + (1-degree)*sels[1])
+	return &StatsNode{
+		Tp:          ColGroupType,
+		mask:        mask,
+		numCols:     len(cols),
+		Selectivity: math.Min(selectivity, math.Min(sels[0], sels[1])),
+	}, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendedStatsEncodeDecode(t *testing.T) {
+	topN := NewTopN(2)
+	topN.AppendTopN([]byte("a"), 3)
+	topN.AppendTopN([]byte("b"), 2)
+	item := &ExtendedStatsItem{
+		ColIDs:     []int64{1, 2},
+		Tp:         ast.StatsTypeHistogram,
+		ScalarVals: 4,
+		Count:      10,
+		TopN:       topN,
+		FMSketch:   NewFMSketch(1000),
+	}
+	str, err := item.EncodeVals()
+	require.NoError(t, err)
+	decoded := &ExtendedStatsItem{ColIDs: item.ColIDs, Tp: item.Tp}
+	require.NoError(t, decoded.DecodeVals(str))
+	require.Equal(t, item.ScalarVals, decoded.ScalarVals)
+	require.Equal(t, item.Count, decoded.Count)
+	require.True(t, item.TopN.Equal(decoded.TopN))
+	require.NotNil(t, decoded.FMSketch)
+
+	item = &ExtendedStatsItem{ColIDs: []int64{1, 2}, Tp: ast.StatsTypeDependency, ScalarVals: 0.5}
+	str, err = item.EncodeVals()
+	require.NoError(t, err)
+	require.Equal(t, "0.500000", str)
+	decoded = &ExtendedStatsItem{ColIDs: item.ColIDs, Tp: item.Tp}
+	require.NoError(t, decoded.DecodeVals(str))
+	require.Equal(t, 0.5, decoded.ScalarVals)
+}
+
+func TestMergePartExtendedStats2Global(t *testing.T) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	newPartStats := func(start, end int, dependency float64) *ExtendedStatsColl {
+		fms := NewFMSketch(1000)
+		for i := start; i < end; i++ {
+			require.NoError(t, fms.InsertValue(sc, types.NewIntDatum(int64(i))))
+		}
+		coll := NewExtendedStatsColl()
+		coll.Stats["s1"] = &ExtendedStatsItem{
+			ColIDs:     []int64{1, 2},
+			Tp:         ast.StatsTypeCardinality,
+			ScalarVals: float64(fms.NDV()),
+			Count:      int64(end - start),
+			FMSketch:   fms,
+		}
+		coll.Stats["s2"] = &ExtendedStatsItem{ColIDs: []int64{1, 2}, Tp: ast.StatsTypeDependency, ScalarVals: dependency}
+		return coll
+	}
+	part0, part1 := newPartStats(0, 10, 1), newPartStats(5, 35, 0.6)
+	// s3 only exists on one partition, so it is not merged.
+	part0.Stats["s3"] = &ExtendedStatsItem{ColIDs: []int64{1, 3}, Tp: ast.StatsTypeCorrelation, ScalarVals: 1}
+	global := MergePartExtendedStats2Global([]*ExtendedStatsColl{part0, part1}, []int64{10, 30}, 100)
+	require.Len(t, global.Stats, 2)
+	// The overlapped values are de-duplicated by the merged FMSketch.
+	require.Equal(t, float64(35), global.Stats["s1"].ScalarVals)
+	require.Equal(t, int64(40), global.Stats["s1"].Count)
+	require.InDelta(t, 0.7, global.Stats["s2"].ScalarVals, 1e-9)
+
+	// The sum of the NDVs is used if any partition has no FMSketch.
+	part1.Stats["s1"].FMSketch = nil
+	global = MergePartExtendedStats2Global([]*ExtendedStatsColl{part0, part1}, []int64{10, 30}, 100)
+	require.Equal(t, float64(40), global.Stats["s1"].ScalarVals)
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
@@ -71,9 +72,9 @@ func dumpJSONExtendedStats(statsColl *statistics.ExtendedStatsColl) []*jsonExten
 	return stats
 }
 
-func extendedStatsFromJSON(statsColl []*jsonExtendedStats) *statistics.ExtendedStatsColl {
+func extendedStatsFromJSON(statsColl []*jsonExtendedStats) (*statistics.ExtendedStatsColl, error) {
 	if len(statsColl) == 0 {
-		return nil
+		return nil, nil
 	}
 	stats := statistics.NewExtendedStatsColl()
 	for _, js := range statsColl {
@@ -83,9 +84,15 @@ func extendedStatsFromJSON(statsColl []*jsonExtendedStats) *statistics.ExtendedS
 			ScalarVals: js.ScalarVals,
 			StringVals: js.StringVals,
 		}
+		// The values of the column group stats are encoded in the StringVals.
+		if js.Tp == ast.StatsTypeCardinality || js.Tp == ast.StatsTypeHistogram {
+			if err := item.DecodeVals(js.StringVals); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 		stats.Stats[js.StatsName] = item
 	}
-	return stats
+	return stats, nil
 }
 
 type jsonColumn struct {
@@ -338,7 +345,11 @@ func TableStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *J
 			tbl.Columns[col.ID] = col
 		}
 	}
-	tbl.ExtendedStats = extendedStatsFromJSON(jsonTbl.ExtStats)
+	extStats, err := extendedStatsFromJSON(jsonTbl.ExtStats)
+	if err != nil {
+		return nil, err
+	}
+	tbl.ExtendedStats = extStats
 	return tbl, nil
 }
 
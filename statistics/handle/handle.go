@@ -96,6 +96,8 @@ type Handle struct {
 		sync.Mutex
 		data *statistics.QueryFeedbackMap
 	}
+	// cardCorrection caches the cardinality corrections learned from the query feedback.
+	cardCorrection *statistics.CardCorrectionCache
 	// colMap contains all the column stats usage information from collectors when we dump them to KV.
 	colMap struct {
 		sync.Mutex
@@ -211,6 +213,7 @@ func NewHandle(ctx sessionctx.Context, lease time.Duration, pool sessionPool, tr
 	handle.statsCache.Store(newStatsCache())
 	handle.globalMap.data = make(tableDeltaMap)
 	handle.feedback.data = statistics.NewQueryFeedbackMap()
+	handle.cardCorrection = statistics.NewCardCorrectionCache()
 	handle.colMap.data = make(colStatsUsageMap)
 	handle.StatsLoad.SubCtxs = make([]sessionctx.Context, cfg.Performance.StatsLoadConcurrency)
 	handle.StatsLoad.NeededItemsCh = make(chan *NeededItemTask, cfg.Performance.StatsLoadQueueSize)
@@ -233,6 +236,11 @@ func (h *Handle) SetLease(lease time.Duration) {
 	h.lease.Store(lease)
 }
 
+// CardCorrectionCache returns the cache of the cardinality corrections learned from the query feedback.
+func (h *Handle) CardCorrectionCache() *statistics.CardCorrectionCache {
+	return h.cardCorrection
+}
+
 // GetQueryFeedback gets the query feedback. It is only used in test.
 func (h *Handle) GetQueryFeedback() *statistics.QueryFeedbackMap {
 	h.feedback.Lock()
@@ -400,6 +408,8 @@ type GlobalStats struct {
 	Cms   []*statistics.CMSketch
 	TopN  []*statistics.TopN
 	Fms   []*statistics.FMSketch
+	// ExtStats is the global-level extended stats, it is only merged along with the column stats.
+	ExtStats *statistics.ExtendedStatsColl
 }
 
 // MergePartitionStats2GlobalStatsByTableID merge the partition-level stats to global-level stats based on the tableID.
@@ -457,6 +467,8 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 		allFms[i] = make([]*statistics.FMSketch, 0, partitionNum)
 	}
 
+	var allExtStats []*statistics.ExtendedStatsColl
+	var allCounts []int64
 	for _, partitionID := range partitionIDs {
 		h.mu.Lock()
 		partitionTable, ok := h.getTableByPhysicalID(is, partitionID)
@@ -482,6 +494,10 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 			err = types.ErrPartitionStatsMissing.GenWithStackByArgs(errMsg)
 			return
 		}
+		if isIndex == 0 {
+			allExtStats = append(allExtStats, partitionStats.ExtendedStats)
+			allCounts = append(allCounts, partitionStats.Count)
+		}
 		for i := 0; i < globalStats.Num; i++ {
 			count, hg, cms, topN, fms := partitionStats.GetStatsInfo(histIDs[i], isIndex == 1)
 			// partition stats is not empty but column stats(hist, topn) is missing
@@ -506,6 +522,10 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 		}
 	}
 
+	if isIndex == 0 {
+		globalStats.ExtStats = statistics.MergePartExtendedStats2Global(allExtStats, allCounts, uint32(opts[ast.AnalyzeOptNumTopN]))
+	}
+
 	// After collect all of the statistics from the partition-level stats,
 	// we should merge them together.
 	for i := 0; i < globalStats.Num; i++ {
@@ -1065,16 +1085,9 @@ func (h *Handle) extendedStatsFromStorage(reader *statsReader, table *statistics
 				return nil, err
 			}
 			statsStr := row.GetString(4)
-			if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeCorrelation {
-				if statsStr != "" {
-					item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
-					if err != nil {
-						logutil.BgLogger().Error("[stats] parse scalar stats failed", zap.String("stats", statsStr), zap.Error(err))
-						return nil, err
-					}
-				}
-			} else {
-				item.StringVals = statsStr
+			if err = item.DecodeVals(statsStr); err != nil {
+				logutil.BgLogger().Error("[stats] decode extended stats failed", zap.String("stats", statsStr), zap.Error(err))
+				return nil, err
 			}
 			table.ExtendedStats.Stats[name] = item
 		}
@@ -1304,11 +1317,9 @@ func (h *Handle) SaveTableStatsToStorage(results *statistics.AnalyzeResults, nee
 			return err
 		}
 		strColIDs := string(bytes)
-		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
-			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
+		statsStr, err = item.EncodeVals()
+		if err != nil {
+			return err
 		}
 		if _, err = exec.ExecuteInternal(ctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, StatsStatusAnalyzed); err != nil {
 			return err
@@ -1796,7 +1807,10 @@ func (h *Handle) ReloadExtendedStatistics() error {
 }
 
 // BuildExtendedStats build extended stats for column groups if needed based on the column samples.
-func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) (*statistics.ExtendedStatsColl, error) {
+// The tableID is the ID of the logical table for partitions, since the extended stats are registered on it.
+// The rowSamples are only collected by ANALYZE version 2, which are required by the Cardinality, Dependency
+// and Histogram types.
+func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector, rowSamples *statistics.ExtStatsRowSamples) (*statistics.ExtendedStatsColl, error) {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
 	const sql = "SELECT name, type, column_ids FROM mysql.stats_extended WHERE table_id = %? and status in (%?, %?)"
 	rows, _, err := h.execRestrictedSQL(ctx, sql, tableID, StatsStatusAnalyzed, StatsStatusInited)
@@ -1816,7 +1830,7 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 			logutil.BgLogger().Error("invalid column_ids in mysql.stats_extended, skip collecting extended stats for this row", zap.String("column_ids", colIDs), zap.Error(err))
 			continue
 		}
-		item = h.fillExtendedStatsItemVals(item, cols, collectors)
+		item = h.fillExtendedStatsItemVals(item, cols, collectors, rowSamples)
 		if item != nil {
 			statsColl.Stats[name] = item
 		}
@@ -1827,16 +1841,43 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 	return statsColl, nil
 }
 
-func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector, rowSamples *statistics.ExtStatsRowSamples) *statistics.ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeCardinality, ast.StatsTypeDependency, ast.StatsTypeHistogram:
+		if rowSamples == nil {
+			return nil
+		}
+		return h.fillExtStatsColGroupVals(item, cols, rowSamples)
 	case ast.StatsTypeCorrelation:
 		return h.fillExtStatsCorrVals(item, cols, collectors)
 	}
 	return nil
 }
 
+func (h *Handle) fillExtStatsColGroupVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, rowSamples *statistics.ExtStatsRowSamples) *statistics.ExtendedStatsItem {
+	colOffsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != len(item.ColIDs) {
+		return nil
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	newItem, err := rowSamples.BuildColGroupStats(sc, item, colOffsets)
+	if err != nil {
+		logutil.BgLogger().Error("[stats] build extended stats on column group failed", zap.Int64s("column_ids", item.ColIDs), zap.Error(err))
+		return nil
+	}
+	return newItem
+}
+
 func (h *Handle) fillExtStatsCorrVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	colOffsets := make([]int, 0, 2)
 	for _, id := range item.ColIDs {
@@ -1938,12 +1979,9 @@ func (h *Handle) SaveExtendedStatsToStorage(tableID int64, extStats *statistics.
 			return errors.Trace(err)
 		}
 		strColIDs := string(bytes)
-		var statsStr string
-		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
-			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
+		statsStr, err := item.EncodeVals()
+		if err != nil {
+			return errors.Trace(err)
 		}
 		// If isLoad is true, it's INSERT; otherwise, it's UPDATE.
 		if _, err := exec.ExecuteInternal(ctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, StatsStatusAnalyzed); err != nil {
@@ -2084,6 +2122,29 @@ func (h *Handle) CollectColumnsInExtendedStats(tableID int64) ([]int64, error) {
 	return columnIDs, nil
 }
 
+// CollectColumnGroupsInExtendedStats returns the column groups whose NDVs are needed by the extended stats,
+// i.e, the columns of the Cardinality and Histogram types.
+func (h *Handle) CollectColumnGroupsInExtendedStats(tableID int64) ([][]int64, error) {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	const sql = "SELECT column_ids FROM mysql.stats_extended WHERE table_id = %? and type in (%?, %?) and status in (%?, %?)"
+	rows, _, err := h.execRestrictedSQL(ctx, sql, tableID, ast.StatsTypeCardinality, ast.StatsTypeHistogram, StatsStatusAnalyzed, StatsStatusInited)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	colGroups := make([][]int64, 0, len(rows))
+	for _, row := range rows {
+		var colIDs []int64
+		data := row.GetString(0)
+		err := json.Unmarshal([]byte(data), &colIDs)
+		if err != nil {
+			logutil.BgLogger().Error("invalid column_ids in mysql.stats_extended, skip collecting extended stats for this row", zap.String("column_ids", data), zap.Error(err))
+			continue
+		}
+		colGroups = append(colGroups, colIDs)
+	}
+	return colGroups, nil
+}
+
 // GetPredicateColumns returns IDs of predicate columns, which are the columns whose stats are used(needed) when generating query plans.
 func (h *Handle) GetPredicateColumns(tableID int64) ([]int64, error) {
 	disableTime, err := h.getDisableColumnTrackingTime()
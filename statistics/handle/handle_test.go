@@ -2548,18 +2548,76 @@ func TestFeedbackWithGlobalStats(t *testing.T) {
 }
 
 func TestExtendedStatsPartitionTable(t *testing.T) {
-	store, clean := testkit.CreateMockStore(t)
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
 	defer clean()
 	tk := testkit.NewTestKit(t, store)
 	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
 	tk.MustExec("use test")
-	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("drop table if exists t1")
 	tk.MustExec("create table t1(a int, b int, c int) partition by range(a) (partition p0 values less than (5), partition p1 values less than (10))")
-	tk.MustExec("create table t2(a int, b int, c int) partition by hash(a) partitions 4")
-	err := tk.ExecToErr("alter table t1 add stats_extended s1 correlation(b,c)")
-	require.Equal(t, "Extended statistics on partitioned tables are not supported now", err.Error())
-	err = tk.ExecToErr("alter table t2 add stats_extended s1 correlation(b,c)")
-	require.Equal(t, "Extended statistics on partitioned tables are not supported now", err.Error())
+	tk.MustExec("insert into t1 values(1,1,1),(2,1,1),(3,2,2),(4,2,2),(5,3,3),(6,3,3),(7,4,4),(8,4,4),(9,5,5)")
+	tk.MustExec("alter table t1 add stats_extended s1 cardinality(b,c)")
+	tk.MustExec("alter table t1 add stats_extended s2 dependency(b,c)")
+	tk.MustExec("analyze table t1")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	p0, p1 := tblInfo.Partition.Definitions[0].ID, tblInfo.Partition.Definitions[1].ID
+	rows := tk.MustQuery("select name, table_id, status from mysql.stats_extended order by name, table_id").Rows()
+	require.Len(t, rows, 6)
+	for _, row := range rows {
+		require.Equal(t, "1", row[2])
+	}
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_extended where table_id in (%d, %d)", p0, p1)).Check(testkit.Rows("4"))
+	// The NDV of the global-level stats is merged from the FMSketches of the partitions.
+	rows = tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 't1'").Sort().Rows()
+	require.Len(t, rows, 2)
+	require.Equal(t, []interface{}{"test", "t1", "s1", "[b,c]", "cardinality", "5.000000"}, rows[0][:6])
+	require.Equal(t, []interface{}{"test", "t1", "s2", "[b,c]", "dependency", "1.000000"}, rows[1][:6])
+
+	tk.MustExec("alter table t1 drop stats_extended s1")
+	tk.MustQuery("select table_id, status from mysql.stats_extended where name = 's1' and status != 2").Check(testkit.Rows())
+}
+
+func TestExtendedStatsColumnGroups(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	require.Equal(t, "Only support Cardinality and Histogram statistics types on at least 2 columns", err.Error())
+	tk.MustExec("insert into t values(1,1,1),(1,1,1),(1,1,1),(2,2,1),(3,3,2),(4,4,2)")
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b,c)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(a,c)")
+	tk.MustExec("alter table t add stats_extended s3 histogram(b,c)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select name, type, status from mysql.stats_extended order by name").Check(testkit.Rows(
+		"s1 0 1",
+		"s2 1 1",
+		"s3 3 1",
+	))
+	rows := tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 't'").Sort().Rows()
+	require.Len(t, rows, 3)
+	require.Equal(t, []interface{}{"s1", "[a,b,c]", "cardinality", "4.000000"}, rows[0][2:6])
+	require.Equal(t, []interface{}{"s2", "[a,c]", "dependency", "1.000000"}, rows[1][2:6])
+	require.Equal(t, []interface{}{"s3", "[b,c]", "histogram", "ndv: 4.000000, topn: 1"}, rows[2][2:6])
+
+	h := dom.StatsHandle()
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	h.Clear()
+	require.NoError(t, h.Update(dom.InfoSchema()))
+	statsTbl := h.GetTableStats(tbl.Meta())
+	require.Len(t, statsTbl.ExtendedStats.Stats, 3)
+	item := statsTbl.ExtendedStats.Stats["s3"]
+	require.Equal(t, int64(6), item.Count)
+	require.Equal(t, 1, item.TopN.Num())
+	require.Equal(t, uint64(3), item.TopN.TopN[0].Count)
 }
 
 func TestHideIndexUsageSyncLease(t *testing.T) {
@@ -993,6 +993,9 @@ func (coll *HistColl) NewHistCollBySelectivity(sctx sessionctx.Context, statsNod
 		Count:         coll.Count,
 	}
 	for _, node := range statsNodes {
+		if node.Tp == ColGroupType {
+			continue
+		}
 		if node.Tp == IndexType {
 			idxHist, ok := coll.Indices[node.ID]
 			if !ok {
@@ -61,6 +61,7 @@ const (
 	IndexType = iota
 	PkType
 	ColType
+	ColGroupType
 )
 
 func compareType(l, r int) int {
@@ -76,7 +77,14 @@ func compareType(l, r int) int {
 	if r == ColType {
 		return 1
 	}
-	return -1
+	if r == PkType {
+		return -1
+	}
+	// Prefer the index to the extended stats on column groups.
+	if l == IndexType {
+		return -1
+	}
+	return 1
 }
 
 // MockStatsNode is only used for test.
@@ -289,6 +297,13 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 			})
 		}
 	}
+	if len(coll.ColGroupStats) > 0 {
+		colGroupNodes, err := coll.getColGroupStatsNodes(ctx, remainedExprs, extractedCols)
+		if err != nil {
+			return 0, nil, errors.Trace(err)
+		}
+		nodes = append(nodes, colGroupNodes...)
+	}
 	usedSets := GetUsableSetsByGreedy(nodes)
 	// Initialize the mask with the full set.
 	mask := (int64(1) << uint(len(remainedExprs))) - 1
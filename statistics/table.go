@@ -81,6 +81,14 @@ type ExtendedStatsItem struct {
 	Tp         uint8
 	ScalarVals float64
 	StringVals string
+	// Count is the row count of the table when the column group stats are collected. It is only
+	// used by the Cardinality and Histogram types.
+	Count int64
+	// TopN is the joint TopN of the column group, it is only used by the Histogram type.
+	TopN *TopN
+	// FMSketch is the FMSketch of the column group. It is only kept for partitions, so the NDV
+	// of the column group can be merged into the global-level stats.
+	FMSketch *FMSketch
 }
 
 // ExtendedStatsColl is a collection of cached items for mysql.stats_extended records.
@@ -110,6 +118,10 @@ type HistColl struct {
 	// The physical id is used when try to load column stats from storage.
 	HavePhysicalID bool
 	Pseudo         bool
+
+	// ColGroupStats are the extended stats on column groups, whose ColIDs are the unique IDs of
+	// the columns. It's used to calculate the selectivity in planner.
+	ColGroupStats []*ExtendedStatsItem
 }
 
 // TableMemoryUsage records tbl memory usage
@@ -253,18 +253,20 @@ func (b *mppExecBuilder) buildMPPExchangeSender(pb *tipb.ExchangeSender) (*exchS
 		exchangeTp: pb.Tp,
 	}
 	if pb.Tp == tipb.ExchangeType_Hash {
-		if len(pb.PartitionKeys) != 1 {
-			return nil, errors.New("The number of hash key must be 1")
+		if len(pb.PartitionKeys) == 0 {
+			return nil, errors.New("The number of hash key must be at least 1")
 		}
-		expr, err := expression.PBToExpr(pb.PartitionKeys[0], child.getFieldTypes(), b.sc)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		col, ok := expr.(*expression.Column)
-		if !ok {
-			return nil, errors.New("Hash key must be column type")
+		for _, key := range pb.PartitionKeys {
+			expr, err := expression.PBToExpr(key, child.getFieldTypes(), b.sc)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			col, ok := expr.(*expression.Column)
+			if !ok {
+				return nil, errors.New("Hash key must be column type")
+			}
+			e.hashKeyOffsets = append(e.hashKeyOffsets, col.Index)
 		}
-		e.hashKeyOffset = col.Index
 	}
 
 	for _, taskMeta := range pb.EncodedTaskMeta {
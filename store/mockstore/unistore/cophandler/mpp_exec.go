@@ -492,10 +492,10 @@ func (e *topNExec) next() (*chunk.Chunk, error) {
 type exchSenderExec struct {
 	baseMPPExec
 
-	tunnels       []*ExchangerTunnel
-	outputOffsets []uint32
-	exchangeTp    tipb.ExchangeType
-	hashKeyOffset int
+	tunnels        []*ExchangerTunnel
+	outputOffsets  []uint32
+	exchangeTp     tipb.ExchangeType
+	hashKeyOffsets []int
 }
 
 func (e *exchSenderExec) open() error {
@@ -550,13 +550,15 @@ func (e *exchSenderExec) next() (*chunk.Chunk, error) {
 				}
 				for i := 0; i < rows; i++ {
 					row := chk.GetRow(i)
-					d := row.GetDatum(e.hashKeyOffset, e.fieldTypes[e.hashKeyOffset])
-					if d.IsNull() {
-						targetChunks[0].AppendRow(row)
-					} else {
-						hashKey := int(d.GetInt64() % int64(len(e.tunnels)))
-						targetChunks[hashKey].AppendRow(row)
+					var hash int64
+					for _, offset := range e.hashKeyOffsets {
+						d := row.GetDatum(offset, e.fieldTypes[offset])
+						if !d.IsNull() {
+							hash = hash*31 + d.GetInt64()
+						}
 					}
+					hashKey := int(uint64(hash) % uint64(len(e.tunnels)))
+					targetChunks[hashKey].AppendRow(row)
 				}
 				for i, tunnel := range e.tunnels {
 					if targetChunks[i].NumRows() > 0 {
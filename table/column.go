@@ -332,6 +332,11 @@ func CastValue(ctx sessionctx.Context, val types.Datum, col *model.ColumnInfo, r
 	if col.GetType() == mysql.TypeString && !types.IsBinaryStr(&col.FieldType) {
 		truncateTrailingSpaces(&casted)
 	}
+	if col.SRID != nil && !casted.IsNull() {
+		if srid := types.GetGeometrySRID(casted.GetBytes()); srid != *col.SRID {
+			return casted, ErrWrongSRIDForColumn.GenWithStackByArgs(col.Name.O, srid, *col.SRID)
+		}
+	}
 	return casted, err
 }
 
@@ -640,7 +645,7 @@ func GetZeroValue(col *model.ColumnInfo) types.Datum {
 		} else {
 			d.SetString("", col.GetCollate())
 		}
-	case mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+	case mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeGeometry:
 		d.SetString("", col.GetCollate())
 	case mysql.TypeDuration:
 		d.SetMysqlDuration(types.ZeroDuration)
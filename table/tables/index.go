@@ -93,6 +93,14 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	if c.idxInfo.Tp == model.IndexTypeFulltext {
+		return nil, c.createFulltext(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+	}
+	return c.createEntry(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+}
+
+// createEntry creates the entry of indexedValues in the index.
+func (c *index) createEntry(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
 		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
 	}
@@ -216,6 +224,14 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
+	if c.idxInfo.Tp == model.IndexTypeFulltext {
+		return c.deleteFulltext(sc, txn, indexedValues, h)
+	}
+	return c.deleteEntry(sc, txn, indexedValues, h)
+}
+
+// deleteEntry removes the entry of indexedValues from the index.
+func (c *index) deleteEntry(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return err
@@ -236,6 +252,13 @@ func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexed
 }
 
 func (c *index) Exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
+	if c.idxInfo.Tp == model.IndexTypeFulltext {
+		return c.existFulltext(sc, txn, indexedValues, h)
+	}
+	return c.existEntry(sc, txn, indexedValues, h)
+}
+
+func (c *index) existEntry(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return false, nil, err
@@ -0,0 +1,76 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+// fulltextTokens returns the distinct tokens of the row, every token is an entry of the FULLTEXT index.
+func (c *index) fulltextTokens(indexedValues []types.Datum) ([]string, error) {
+	t, ok := fulltext.GetTokenizer(c.idxInfo.FulltextParser)
+	if !ok {
+		return nil, errors.Errorf("unknown parser %s of FULLTEXT index %s", c.idxInfo.FulltextParser, c.idxInfo.Name)
+	}
+	return fulltext.DocumentOf(t, indexedValues).UniqueTokens(), nil
+}
+
+func (c *index) createFulltext(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) error {
+	tokens, err := c.fulltextTokens(indexedValues)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		vals := fulltext.IndexedValues(c.tblInfo, c.idxInfo, token)
+		if _, err = c.createEntry(sctx, txn, vals, h, handleRestoreData, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *index) deleteFulltext(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
+	tokens, err := c.fulltextTokens(indexedValues)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err = c.deleteEntry(sc, txn, fulltext.IndexedValues(c.tblInfo, c.idxInfo, token), h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// existFulltext checks whether all the entries of the row exist in the FULLTEXT index.
+func (c *index) existFulltext(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
+	tokens, err := c.fulltextTokens(indexedValues)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, token := range tokens {
+		exist, _, err := c.existEntry(sc, txn, fulltext.IndexedValues(c.tblInfo, c.idxInfo, token), h)
+		if err != nil || !exist {
+			return false, nil, err
+		}
+	}
+	return true, h, nil
+}
@@ -192,6 +192,11 @@ func checkIndexKeys(
 			return errors.New("index not found")
 		}
 
+		// the keys of a FULLTEXT index store the tokens instead of the column values
+		if indexInfo.Tp == model.IndexTypeFulltext {
+			continue
+		}
+
 		// when we cannot decode the key to get the original value
 		if len(m.value) == 0 && NeedRestoredData(indexInfo.Columns, t.Meta().Columns) {
 			continue
@@ -0,0 +1,71 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+const (
+	// materializedViewLogInsert marks the row is inserted into the base table.
+	materializedViewLogInsert = 1
+	// materializedViewLogDelete marks the row is deleted from the base table.
+	materializedViewLogDelete = -1
+)
+
+// tableByIDGetter is implemented by the information schema. The information schema package can't be imported here.
+type tableByIDGetter interface {
+	TableByID(id int64) (table.Table, bool)
+}
+
+// writeMaterializedViewLogs writes the changed row to the change logs of the materialized views based on the table, so
+// the views know they're stale and can be refreshed incrementally.
+func (t *TableCommon) writeMaterializedViewLogs(sctx sessionctx.Context, r []types.Datum, op int64) error {
+	if len(t.meta.MaterializedViewLogIDs) == 0 {
+		return nil
+	}
+	is, ok := sctx.GetInfoSchema().(tableByIDGetter)
+	if !ok {
+		return nil
+	}
+	for _, logID := range t.meta.MaterializedViewLogIDs {
+		logTbl, ok := is.TableByID(logID)
+		if !ok {
+			continue
+		}
+		logCols := logTbl.Cols()
+		row := make([]types.Datum, len(logCols))
+		for i, col := range logCols {
+			if col.Name.L == model.MaterializedViewLogOpName.L {
+				row[i].SetInt64(op)
+				continue
+			}
+			// The log columns have the same IDs as the base columns.
+			for _, baseCol := range t.meta.Columns {
+				if baseCol.ID == col.ID && baseCol.Offset < len(r) {
+					row[i] = r[baseCol.Offset]
+					break
+				}
+			}
+		}
+		if _, err := logTbl.AddRecord(sctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -456,6 +456,12 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 	}
 
 	memBuffer.Release(sh)
+	if err = t.writeMaterializedViewLogs(sctx, oldData, materializedViewLogDelete); err != nil {
+		return err
+	}
+	if err = t.writeMaterializedViewLogs(sctx, newData, materializedViewLogInsert); err != nil {
+		return err
+	}
 	if shouldWriteBinlog(sctx, t.meta) {
 		if !t.meta.PKIsHandle && !t.meta.IsCommonHandle {
 			binlogColIDs = append(binlogColIDs, model.ExtraHandleID)
@@ -900,6 +906,9 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 	}
 
 	memBuffer.Release(sh)
+	if err = t.writeMaterializedViewLogs(sctx, r, materializedViewLogInsert); err != nil {
+		return nil, err
+	}
 
 	if shouldWriteBinlog(sctx, t.meta) {
 		// For insert, TiDB and Binlog can use same row and schema.
@@ -1162,6 +1171,9 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 		}
 	}
 	memBuffer.Release(sh)
+	if err = t.writeMaterializedViewLogs(ctx, r, materializedViewLogDelete); err != nil {
+		return err
+	}
 
 	if shouldWriteBinlog(ctx, t.meta) {
 		cols := t.Cols()
@@ -236,6 +236,12 @@ func (tk *TestKit) Exec(sql string, args ...interface{}) (sqlexec.RecordSet, err
 			var err error
 			if s, ok := stmt.(*ast.NonTransactionalDeleteStmt); ok {
 				rs, err = session.HandleNonTransactionalDelete(ctx, s, tk.Session())
+			} else if s, ok := stmt.(*ast.CallStmt); ok {
+				// Only the last result set of the procedure is returned.
+				var rss []sqlexec.RecordSet
+				if rss, err = session.HandleCall(ctx, s, tk.Session()); len(rss) > 0 {
+					rs = rss[len(rss)-1]
+				}
 			} else {
 				rs, err = tk.Session().ExecuteStmt(ctx, stmt)
 			}
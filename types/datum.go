@@ -919,6 +919,8 @@ func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datu
 		return d.convertToMysqlSet(sc, target)
 	case mysql.TypeJSON:
 		return d.convertToMysqlJSON(sc, target)
+	case mysql.TypeGeometry:
+		return d.convertToGeometry(sc, target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -1703,6 +1705,20 @@ func (d *Datum) convertToMysqlJSON(_ *stmtctx.StatementContext, _ *FieldType) (r
 	return ret, errors.Trace(err)
 }
 
+// convertToGeometry checks the value is the stored binary of a geometry of the target geometry type.
+func (d *Datum) convertToGeometry(_ *stmtctx.StatementContext, target *FieldType) (ret Datum, err error) {
+	switch d.k {
+	case KindString, KindBytes, KindBinaryLiteral:
+		g, err := DecodeGeometry(d.GetBytes())
+		if err != nil || (target.GetGeometryType() != GeometryTypeGeometry && g.Type != target.GetGeometryType()) {
+			return ret, ErrCantCreateGeometryObject.GenWithStackByArgs()
+		}
+		ret.SetBytes(d.GetBytes())
+		return ret, nil
+	}
+	return ret, ErrCantCreateGeometryObject.GenWithStackByArgs()
+}
+
 // ToBool converts to a bool.
 // We will use 1 for true, and 0 for false.
 func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
@@ -84,6 +84,16 @@ var (
 	ErrWrongValue = dbterror.ClassTypes.NewStdErr(mysql.ErrTruncatedWrongValue, mysql.MySQLErrName[mysql.ErrWrongValue])
 	// ErrWrongValueForType is returned when the input value is in wrong format for function.
 	ErrWrongValueForType = dbterror.ClassTypes.NewStdErr(mysql.ErrWrongValueForType, mysql.MySQLErrName[mysql.ErrWrongValueForType])
+	// ErrCantCreateGeometryObject is returned when the value stored in a spatial column is not a geometry of its type.
+	ErrCantCreateGeometryObject = dbterror.ClassTypes.NewStd(mysql.ErrCantCreateGeometryObject)
+	// ErrGISInvalidData is returned when the geometry passed to a spatial function is invalid.
+	ErrGISInvalidData = dbterror.ClassTypes.NewStd(mysql.ErrGISInvalidData)
+	// ErrSRSNotFound is returned when the SRID doesn't refer to a known spatial reference system.
+	ErrSRSNotFound = dbterror.ClassTypes.NewStd(mysql.ErrSRSNotFound)
+	// ErrLatitudeOutOfRange is returned when the latitude of a geographic point is out of range.
+	ErrLatitudeOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrLatitudeOutOfRange)
+	// ErrLongitudeOutOfRange is returned when the longitude of a geographic point is out of range.
+	ErrLongitudeOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrLongitudeOutOfRange)
 	// ErrPartitionStatsMissing is returned when the partition-level stats is missing and the build global-level stats fails.
 	// Put this error here is to prevent `import cycle not allowed`.
 	ErrPartitionStatsMissing = dbterror.ClassTypes.NewStd(mysql.ErrPartitionStatsMissing)
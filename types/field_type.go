@@ -1347,6 +1347,11 @@ func CheckModifyTypeCompatible(origin *FieldType, to *FieldType) (canReorg bool,
 			}
 		}
 
+		if origin.GetType() == mysql.TypeGeometry && origin.GetGeometryType() != to.GetGeometryType() {
+			msg := fmt.Sprintf("change from original type %v to %v is currently unsupported yet", origin.CompactStr(), to.CompactStr())
+			return false, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs(msg)
+		}
+
 		needReorg, reason := needReorgToChange(origin, to)
 		if !needReorg {
 			return false, nil
@@ -1437,6 +1442,11 @@ func checkTypeChangeSupported(origin *FieldType, to *FieldType) bool {
 		return false
 	}
 
+	if origin.GetType() == mysql.TypeGeometry || to.GetType() == mysql.TypeGeometry {
+		// TODO: Currently geometry can't be cast from or to other types, should fix here after supported.
+		return false
+	}
+
 	return true
 }
 
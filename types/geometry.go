@@ -0,0 +1,639 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package types
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	ast "github.com/pingcap/tidb/parser/types"
+)
+
+// GeometryType is the type of a geometry value.
+type GeometryType = ast.GeometryType
+
+const (
+	// GeometryTypeGeometry represents type GEOMETRY.
+	GeometryTypeGeometry = ast.GeometryTypeGeometry
+	// GeometryTypePoint represents type POINT.
+	GeometryTypePoint = ast.GeometryTypePoint
+	// GeometryTypeLineString represents type LINESTRING.
+	GeometryTypeLineString = ast.GeometryTypeLineString
+	// GeometryTypePolygon represents type POLYGON.
+	GeometryTypePolygon = ast.GeometryTypePolygon
+	// GeometryTypeMultiPoint represents type MULTIPOINT.
+	GeometryTypeMultiPoint = ast.GeometryTypeMultiPoint
+	// GeometryTypeMultiLineString represents type MULTILINESTRING.
+	GeometryTypeMultiLineString = ast.GeometryTypeMultiLineString
+	// GeometryTypeMultiPolygon represents type MULTIPOLYGON.
+	GeometryTypeMultiPolygon = ast.GeometryTypeMultiPolygon
+	// GeometryTypeGeometryCollection represents type GEOMETRYCOLLECTION.
+	GeometryTypeGeometryCollection = ast.GeometryTypeGeometryCollection
+)
+
+// errInvalidGeometry is returned when the WKT or the binary of a geometry is invalid. The callers convert it to the
+// MySQL error of their context.
+var errInvalidGeometry = errors.New("invalid geometry")
+
+// GeomPoint is a point of a geometry.
+type GeomPoint struct {
+	X, Y float64
+}
+
+// Geometry is a spatial value. It's stored in binary like MySQL does, the SRID in 4 little-endian bytes is followed
+// by the WKB of the geometry.
+type Geometry struct {
+	Type GeometryType
+	SRID uint32
+	// Points are the points of a Point or a LineString.
+	Points []GeomPoint
+	// Rings are the rings of a Polygon, the first one is the exterior ring.
+	Rings [][]GeomPoint
+	// Geometries are the elements of a MultiPoint, MultiLineString, MultiPolygon or GeometryCollection, their SRIDs
+	// are not used.
+	Geometries []*Geometry
+}
+
+// SpatialReferenceSystem is a spatial reference system known by TiDB.
+type SpatialReferenceSystem struct {
+	SRID uint32
+	Name string
+	// Geographic systems use the latitude and the longitude as their coordinates, the others are Cartesian.
+	Geographic bool
+}
+
+var spatialReferenceSystems = map[uint32]*SpatialReferenceSystem{
+	0:    {SRID: 0, Name: ""},
+	3857: {SRID: 3857, Name: "WGS 84 / Pseudo-Mercator"},
+	4326: {SRID: 4326, Name: "WGS 84", Geographic: true},
+}
+
+// GetSpatialReferenceSystem returns the spatial reference system of the SRID.
+func GetSpatialReferenceSystem(srid uint32) (*SpatialReferenceSystem, error) {
+	srs, ok := spatialReferenceSystems[srid]
+	if !ok {
+		return nil, ErrSRSNotFound.GenWithStackByArgs(srid)
+	}
+	return srs, nil
+}
+
+const (
+	wkbLittleEndian = 1
+	wkbBigEndian    = 0
+	// geometryHeaderLen is the length of the SRID, the byte order and the type of a stored geometry.
+	geometryHeaderLen = 4 + 1 + 4
+	// wkbPointLen is the length of the coordinates of a WKB point.
+	wkbPointLen = 16
+)
+
+// NewPointGeometry returns a Point.
+func NewPointGeometry(srid uint32, x, y float64) *Geometry {
+	return &Geometry{Type: GeometryTypePoint, SRID: srid, Points: []GeomPoint{{X: x, Y: y}}}
+}
+
+// Encode encodes the geometry into the stored binary.
+func (g *Geometry) Encode() []byte {
+	buf := make([]byte, 4, 64)
+	binary.LittleEndian.PutUint32(buf, g.SRID)
+	return g.appendWKB(buf)
+}
+
+// WKB returns the WKB of the geometry, which is the stored binary without the SRID.
+func (g *Geometry) WKB() []byte {
+	return g.appendWKB(make([]byte, 0, 64))
+}
+
+func (g *Geometry) appendWKB(buf []byte) []byte {
+	buf = append(buf, wkbLittleEndian)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(g.Type))
+	switch g.Type {
+	case GeometryTypePoint:
+		buf = appendWKBPoint(buf, g.Points[0])
+	case GeometryTypeLineString:
+		buf = appendWKBPoints(buf, g.Points)
+	case GeometryTypePolygon:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.Rings)))
+		for _, ring := range g.Rings {
+			buf = appendWKBPoints(buf, ring)
+		}
+	default:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.Geometries)))
+		for _, elem := range g.Geometries {
+			buf = elem.appendWKB(buf)
+		}
+	}
+	return buf
+}
+
+func appendWKBPoint(buf []byte, p GeomPoint) []byte {
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.X))
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Y))
+}
+
+func appendWKBPoints(buf []byte, points []GeomPoint) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(points)))
+	for _, p := range points {
+		buf = appendWKBPoint(buf, p)
+	}
+	return buf
+}
+
+// GetGeometrySRID returns the SRID of the stored binary of a geometry.
+func GetGeometrySRID(b []byte) uint32 {
+	if len(b) < 4 {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+// DecodeGeometry decodes the stored binary of a geometry.
+func DecodeGeometry(b []byte) (*Geometry, error) {
+	if len(b) < geometryHeaderLen {
+		return nil, errInvalidGeometry
+	}
+	return ParseGeometryFromWKB(b[4:], binary.LittleEndian.Uint32(b))
+}
+
+// ParseGeometryFromWKB parses the geometry from its WKB in either byte order.
+func ParseGeometryFromWKB(wkb []byte, srid uint32) (*Geometry, error) {
+	d := wkbDecoder{buf: wkb}
+	g, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.buf) != 0 {
+		return nil, errInvalidGeometry
+	}
+	g.SRID = srid
+	return g, nil
+}
+
+// maxGeometryDepth is the max nesting depth of the geometry collections.
+const maxGeometryDepth = 64
+
+type wkbDecoder struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+func (d *wkbDecoder) uint32() (uint32, error) {
+	if len(d.buf) < 4 {
+		return 0, errInvalidGeometry
+	}
+	v := d.order.Uint32(d.buf)
+	d.buf = d.buf[4:]
+	return v, nil
+}
+
+func (d *wkbDecoder) point() (GeomPoint, error) {
+	if len(d.buf) < wkbPointLen {
+		return GeomPoint{}, errInvalidGeometry
+	}
+	p := GeomPoint{X: math.Float64frombits(d.order.Uint64(d.buf)), Y: math.Float64frombits(d.order.Uint64(d.buf[8:]))}
+	d.buf = d.buf[wkbPointLen:]
+	if math.IsNaN(p.X) || math.IsInf(p.X, 0) || math.IsNaN(p.Y) || math.IsInf(p.Y, 0) {
+		return GeomPoint{}, errInvalidGeometry
+	}
+	return p, nil
+}
+
+// count reads the number of the elements, which are at least elemLen bytes each.
+func (d *wkbDecoder) count(elemLen int) (int, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(elemLen) > uint64(len(d.buf)) {
+		return 0, errInvalidGeometry
+	}
+	return int(n), nil
+}
+
+func (d *wkbDecoder) points() ([]GeomPoint, error) {
+	n, err := d.count(wkbPointLen)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]GeomPoint, 0, n)
+	for i := 0; i < n; i++ {
+		p, err := d.point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (d *wkbDecoder) decode(depth int) (*Geometry, error) {
+	if len(d.buf) < 5 || depth > maxGeometryDepth {
+		return nil, errInvalidGeometry
+	}
+	switch d.buf[0] {
+	case wkbLittleEndian:
+		d.order = binary.LittleEndian
+	case wkbBigEndian:
+		d.order = binary.BigEndian
+	default:
+		return nil, errInvalidGeometry
+	}
+	d.buf = d.buf[1:]
+	tp, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+	g := &Geometry{Type: GeometryType(tp)}
+	switch g.Type {
+	case GeometryTypePoint:
+		p, err := d.point()
+		if err != nil {
+			return nil, err
+		}
+		g.Points = []GeomPoint{p}
+	case GeometryTypeLineString:
+		if g.Points, err = d.points(); err != nil {
+			return nil, err
+		}
+	case GeometryTypePolygon:
+		n, err := d.count(4)
+		if err != nil {
+			return nil, err
+		}
+		g.Rings = make([][]GeomPoint, 0, n)
+		for i := 0; i < n; i++ {
+			ring, err := d.points()
+			if err != nil {
+				return nil, err
+			}
+			g.Rings = append(g.Rings, ring)
+		}
+	case GeometryTypeMultiPoint, GeometryTypeMultiLineString, GeometryTypeMultiPolygon, GeometryTypeGeometryCollection:
+		n, err := d.count(geometryHeaderLen - 4)
+		if err != nil {
+			return nil, err
+		}
+		g.Geometries = make([]*Geometry, 0, n)
+		for i := 0; i < n; i++ {
+			elem, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			g.Geometries = append(g.Geometries, elem)
+		}
+	default:
+		return nil, errInvalidGeometry
+	}
+	if !g.isValid() {
+		return nil, errInvalidGeometry
+	}
+	return g, nil
+}
+
+// elemType returns the type of the elements of a multi geometry.
+func (g *Geometry) elemType() GeometryType {
+	switch g.Type {
+	case GeometryTypeMultiPoint:
+		return GeometryTypePoint
+	case GeometryTypeMultiLineString:
+		return GeometryTypeLineString
+	case GeometryTypeMultiPolygon:
+		return GeometryTypePolygon
+	}
+	return GeometryTypeGeometry
+}
+
+// isValid checks the structure of the geometry like MySQL does: a LineString has at least 2 points, a ring of a
+// Polygon is closed and has at least 4 points, and the elements of a multi geometry are of its element type.
+func (g *Geometry) isValid() bool {
+	switch g.Type {
+	case GeometryTypePoint:
+		return len(g.Points) == 1
+	case GeometryTypeLineString:
+		return len(g.Points) >= 2
+	case GeometryTypePolygon:
+		if len(g.Rings) == 0 {
+			return false
+		}
+		for _, ring := range g.Rings {
+			if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+				return false
+			}
+		}
+		return true
+	case GeometryTypeGeometryCollection:
+		return true
+	}
+	if len(g.Geometries) == 0 {
+		return false
+	}
+	for _, elem := range g.Geometries {
+		if elem.Type != g.elemType() {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseGeometryFromWKT parses the geometry from its WKT.
+func ParseGeometryFromWKT(wkt string, srid uint32) (*Geometry, error) {
+	p := wktParser{s: wkt}
+	g, err := p.parseGeometry(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, errInvalidGeometry
+	}
+	g.SRID = srid
+	return g, nil
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// consume skips the spaces and consumes the byte if it's the next one.
+func (p *wktParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] >= 'a' && p.s[p.pos] <= 'z' || p.s[p.pos] >= 'A' && p.s[p.pos] <= 'Z') {
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("0123456789+-.eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil || math.IsInf(v, 0) {
+		return 0, errInvalidGeometry
+	}
+	return v, nil
+}
+
+func (p *wktParser) point() (GeomPoint, error) {
+	x, err := p.number()
+	if err != nil {
+		return GeomPoint{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return GeomPoint{}, err
+	}
+	return GeomPoint{X: x, Y: y}, nil
+}
+
+// points parses the parenthesized point list.
+func (p *wktParser) points() ([]GeomPoint, error) {
+	if !p.consume('(') {
+		return nil, errInvalidGeometry
+	}
+	var points []GeomPoint
+	for {
+		pt, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+		if !p.consume(',') {
+			break
+		}
+	}
+	if !p.consume(')') {
+		return nil, errInvalidGeometry
+	}
+	return points, nil
+}
+
+// list parses the parenthesized list whose elements are parsed by fn.
+func (p *wktParser) list(fn func() error) error {
+	if !p.consume('(') {
+		return errInvalidGeometry
+	}
+	return p.listTail(fn)
+}
+
+// listTail parses the elements and the closing parenthesis of a list.
+func (p *wktParser) listTail(fn func() error) error {
+	for {
+		if err := fn(); err != nil {
+			return err
+		}
+		if !p.consume(',') {
+			break
+		}
+	}
+	if !p.consume(')') {
+		return errInvalidGeometry
+	}
+	return nil
+}
+
+func (p *wktParser) parseGeometry(depth int) (*Geometry, error) {
+	if depth > maxGeometryDepth {
+		return nil, errInvalidGeometry
+	}
+	var g *Geometry
+	var err error
+	switch p.word() {
+	case "POINT":
+		g = &Geometry{Type: GeometryTypePoint}
+		if !p.consume('(') {
+			return nil, errInvalidGeometry
+		}
+		pt, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		g.Points = []GeomPoint{pt}
+		if !p.consume(')') {
+			return nil, errInvalidGeometry
+		}
+	case "LINESTRING":
+		g = &Geometry{Type: GeometryTypeLineString}
+		g.Points, err = p.points()
+	case "POLYGON":
+		g = &Geometry{Type: GeometryTypePolygon}
+		err = p.list(func() error {
+			ring, err := p.points()
+			g.Rings = append(g.Rings, ring)
+			return err
+		})
+	case "MULTIPOINT":
+		g = &Geometry{Type: GeometryTypeMultiPoint}
+		err = p.list(func() error {
+			// The points of a MultiPoint may be parenthesized or not.
+			parenthesized := p.consume('(')
+			pt, err := p.point()
+			if err != nil {
+				return err
+			}
+			if parenthesized && !p.consume(')') {
+				return errInvalidGeometry
+			}
+			g.Geometries = append(g.Geometries, &Geometry{Type: GeometryTypePoint, Points: []GeomPoint{pt}})
+			return nil
+		})
+	case "MULTILINESTRING":
+		g = &Geometry{Type: GeometryTypeMultiLineString}
+		err = p.list(func() error {
+			points, err := p.points()
+			g.Geometries = append(g.Geometries, &Geometry{Type: GeometryTypeLineString, Points: points})
+			return err
+		})
+	case "MULTIPOLYGON":
+		g = &Geometry{Type: GeometryTypeMultiPolygon}
+		err = p.list(func() error {
+			polygon := &Geometry{Type: GeometryTypePolygon}
+			g.Geometries = append(g.Geometries, polygon)
+			return p.list(func() error {
+				ring, err := p.points()
+				polygon.Rings = append(polygon.Rings, ring)
+				return err
+			})
+		})
+	case "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		g = &Geometry{Type: GeometryTypeGeometryCollection}
+		// An empty collection is written as GEOMETRYCOLLECTION EMPTY or GEOMETRYCOLLECTION().
+		switch {
+		case p.word() == "EMPTY":
+		case !p.consume('('):
+			err = errInvalidGeometry
+		case p.consume(')'):
+		default:
+			err = p.listTail(func() error {
+				elem, err := p.parseGeometry(depth + 1)
+				g.Geometries = append(g.Geometries, elem)
+				return err
+			})
+		}
+	default:
+		return nil, errInvalidGeometry
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, elem := range g.Geometries {
+		if !elem.isValid() {
+			return nil, errInvalidGeometry
+		}
+	}
+	if !g.isValid() {
+		return nil, errInvalidGeometry
+	}
+	return g, nil
+}
+
+// String returns the WKT of the geometry.
+func (g *Geometry) String() string {
+	var sb strings.Builder
+	g.writeWKT(&sb)
+	return sb.String()
+}
+
+var geometryTypeWKTNames = []string{
+	GeometryTypePoint:              "POINT",
+	GeometryTypeLineString:         "LINESTRING",
+	GeometryTypePolygon:            "POLYGON",
+	GeometryTypeMultiPoint:         "MULTIPOINT",
+	GeometryTypeMultiLineString:    "MULTILINESTRING",
+	GeometryTypeMultiPolygon:       "MULTIPOLYGON",
+	GeometryTypeGeometryCollection: "GEOMETRYCOLLECTION",
+}
+
+func (g *Geometry) writeWKT(sb *strings.Builder) {
+	sb.WriteString(geometryTypeWKTNames[g.Type])
+	g.writeWKTBody(sb)
+}
+
+// writeWKTBody writes the parenthesized coordinates of the geometry.
+func (g *Geometry) writeWKTBody(sb *strings.Builder) {
+	switch g.Type {
+	case GeometryTypePoint, GeometryTypeLineString:
+		writeWKTPoints(sb, g.Points)
+		return
+	case GeometryTypePolygon:
+		sb.WriteByte('(')
+		for i, ring := range g.Rings {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			writeWKTPoints(sb, ring)
+		}
+		sb.WriteByte(')')
+		return
+	}
+	if g.Type == GeometryTypeGeometryCollection && len(g.Geometries) == 0 {
+		sb.WriteString(" EMPTY")
+		return
+	}
+	sb.WriteByte('(')
+	for i, elem := range g.Geometries {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if g.Type == GeometryTypeGeometryCollection {
+			elem.writeWKT(sb)
+		} else {
+			elem.writeWKTBody(sb)
+		}
+	}
+	sb.WriteByte(')')
+}
+
+func writeWKTPoints(sb *strings.Builder, points []GeomPoint) {
+	sb.WriteByte('(')
+	for i, p := range points {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(formatGeomCoordinate(p.X))
+		sb.WriteByte(' ')
+		sb.WriteString(formatGeomCoordinate(p.Y))
+	}
+	sb.WriteByte(')')
+}
+
+// formatGeomCoordinate formats the coordinate in the shortest representation, the exponent is used for the very
+// large or small values like MySQL does.
+func formatGeomCoordinate(v float64) string {
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-5 || abs >= 1e15) {
+		return strings.Replace(strconv.FormatFloat(v, 'g', -1, 64), "e+", "e", 1)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,468 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package types
+
+import (
+	"math"
+)
+
+// The relations are computed in the Cartesian plane, the coordinates of a geographic geometry are used as they are.
+
+// geomParts are the primitive components of a geometry.
+type geomParts struct {
+	points   []GeomPoint
+	lines    [][]GeomPoint
+	polygons [][][]GeomPoint
+}
+
+func (g *Geometry) parts() *geomParts {
+	ps := &geomParts{}
+	ps.collect(g)
+	return ps
+}
+
+func (ps *geomParts) collect(g *Geometry) {
+	switch g.Type {
+	case GeometryTypePoint:
+		ps.points = append(ps.points, g.Points[0])
+	case GeometryTypeLineString:
+		ps.lines = append(ps.lines, g.Points)
+	case GeometryTypePolygon:
+		ps.polygons = append(ps.polygons, g.Rings)
+	default:
+		for _, elem := range g.Geometries {
+			ps.collect(elem)
+		}
+	}
+}
+
+func (ps *geomParts) isEmpty() bool {
+	return len(ps.points) == 0 && len(ps.lines) == 0 && len(ps.polygons) == 0
+}
+
+// WalkPoints calls fn for every point of the geometry until it returns false.
+func (g *Geometry) WalkPoints(fn func(p GeomPoint) bool) bool {
+	for _, p := range g.Points {
+		if !fn(p) {
+			return false
+		}
+	}
+	for _, ring := range g.Rings {
+		for _, p := range ring {
+			if !fn(p) {
+				return false
+			}
+		}
+	}
+	for _, elem := range g.Geometries {
+		if !elem.WalkPoints(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckGeographicRange checks the coordinates of the geometry of a geographic spatial reference system, the first
+// coordinate is the latitude and the second one is the longitude like the axis order of EPSG 4326.
+func (g *Geometry) CheckGeographicRange(funcName string) error {
+	var err error
+	g.WalkPoints(func(p GeomPoint) bool {
+		if p.X < -90 || p.X > 90 {
+			err = ErrLatitudeOutOfRange.GenWithStackByArgs(p.X, funcName, -90.0, 90.0)
+		} else if p.Y <= -180 || p.Y > 180 {
+			err = ErrLongitudeOutOfRange.GenWithStackByArgs(p.Y, funcName, -180.0, 180.0)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// orient returns the cross product of b-a and c-a, it's positive if c is on the left of a->b.
+func orient(a, b, c GeomPoint) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// onSegment checks whether p is on the segment a-b.
+func onSegment(p, a, b GeomPoint) bool {
+	return orient(a, b, p) == 0 &&
+		math.Min(a.X, b.X) <= p.X && p.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= p.Y && p.Y <= math.Max(a.Y, b.Y)
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// segmentsIntersect checks whether the segments a-b and c-d share any point.
+func segmentsIntersect(a, b, c, d GeomPoint) bool {
+	o1, o2 := sign(orient(a, b, c)), sign(orient(a, b, d))
+	o3, o4 := sign(orient(c, d, a)), sign(orient(c, d, b))
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return onSegment(c, a, b) || onSegment(d, a, b) || onSegment(a, c, d) || onSegment(b, c, d)
+}
+
+// segmentsCross checks whether the segments a-b and c-d cross at a point inside both of them.
+func segmentsCross(a, b, c, d GeomPoint) bool {
+	return sign(orient(a, b, c))*sign(orient(a, b, d)) < 0 && sign(orient(c, d, a))*sign(orient(c, d, b)) < 0
+}
+
+const (
+	outside  = -1
+	boundary = 0
+	inside   = 1
+)
+
+// pointInRing locates the point against the closed ring.
+func pointInRing(p GeomPoint, ring []GeomPoint) int {
+	in := false
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := ring[i], ring[i+1]
+		if onSegment(p, a, b) {
+			return boundary
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	if in {
+		return inside
+	}
+	return outside
+}
+
+// pointInPolygon locates the point against the polygon, the holes are outside of the polygon.
+func pointInPolygon(p GeomPoint, rings [][]GeomPoint) int {
+	loc := pointInRing(p, rings[0])
+	if loc != inside {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch pointInRing(p, hole) {
+		case boundary:
+			return boundary
+		case inside:
+			return outside
+		}
+	}
+	return inside
+}
+
+func pointOnLine(p GeomPoint, line []GeomPoint) bool {
+	for i := 0; i+1 < len(line); i++ {
+		if onSegment(p, line[i], line[i+1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// pointInLineInterior checks whether the point is on the line but not an end of it, a closed line has no ends.
+func pointInLineInterior(p GeomPoint, line []GeomPoint) bool {
+	if !pointOnLine(p, line) {
+		return false
+	}
+	first, last := line[0], line[len(line)-1]
+	return first == last || (p != first && p != last)
+}
+
+func linesIntersect(l1, l2 []GeomPoint) bool {
+	for i := 0; i+1 < len(l1); i++ {
+		for j := 0; j+1 < len(l2); j++ {
+			if segmentsIntersect(l1[i], l1[i+1], l2[j], l2[j+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lineIntersectsPolygon(line []GeomPoint, rings [][]GeomPoint) bool {
+	for _, p := range line {
+		if pointInPolygon(p, rings) != outside {
+			return true
+		}
+	}
+	for _, ring := range rings {
+		if linesIntersect(line, ring) {
+			return true
+		}
+	}
+	return false
+}
+
+func polygonsIntersect(r1, r2 [][]GeomPoint) bool {
+	return lineIntersectsPolygon(r1[0], r2) || lineIntersectsPolygon(r2[0], r1)
+}
+
+// Intersects checks whether the geometries share any point.
+func (g *Geometry) Intersects(other *Geometry) bool {
+	a, b := g.parts(), other.parts()
+	for _, p := range a.points {
+		if b.coversPoint(p) {
+			return true
+		}
+	}
+	for _, p := range b.points {
+		if a.coversPoint(p) {
+			return true
+		}
+	}
+	for _, l1 := range a.lines {
+		for _, l2 := range b.lines {
+			if linesIntersect(l1, l2) {
+				return true
+			}
+		}
+		for _, poly := range b.polygons {
+			if lineIntersectsPolygon(l1, poly) {
+				return true
+			}
+		}
+	}
+	for _, poly := range a.polygons {
+		for _, l := range b.lines {
+			if lineIntersectsPolygon(l, poly) {
+				return true
+			}
+		}
+		for _, poly2 := range b.polygons {
+			if polygonsIntersect(poly, poly2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coversPoint checks whether the point is in the interior or on the boundary of the parts.
+func (ps *geomParts) coversPoint(p GeomPoint) bool {
+	for _, q := range ps.points {
+		if p == q {
+			return true
+		}
+	}
+	for _, l := range ps.lines {
+		if pointOnLine(p, l) {
+			return true
+		}
+	}
+	for _, poly := range ps.polygons {
+		if pointInPolygon(p, poly) != outside {
+			return true
+		}
+	}
+	return false
+}
+
+// pointInInterior checks whether the point is in the interior of the parts.
+func (ps *geomParts) pointInInterior(p GeomPoint) bool {
+	for _, q := range ps.points {
+		if p == q {
+			return true
+		}
+	}
+	for _, l := range ps.lines {
+		if pointInLineInterior(p, l) {
+			return true
+		}
+	}
+	for _, poly := range ps.polygons {
+		if pointInPolygon(p, poly) == inside {
+			return true
+		}
+	}
+	return false
+}
+
+func midpoint(a, b GeomPoint) GeomPoint {
+	return GeomPoint{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// segmentInPolygon checks whether the segment is in the polygon, the boundary is part of the polygon.
+func segmentInPolygon(a, b GeomPoint, rings [][]GeomPoint) bool {
+	if pointInPolygon(a, rings) == outside || pointInPolygon(b, rings) == outside ||
+		pointInPolygon(midpoint(a, b), rings) == outside {
+		return false
+	}
+	for _, ring := range rings {
+		for i := 0; i+1 < len(ring); i++ {
+			if segmentsCross(a, b, ring[i], ring[i+1]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// coversSegment checks whether the segment is in the interior or on the boundary of the parts.
+func (ps *geomParts) coversSegment(a, b GeomPoint) bool {
+	for _, poly := range ps.polygons {
+		if segmentInPolygon(a, b, poly) {
+			return true
+		}
+	}
+	// The segment may be covered by the consecutive segments of a line.
+	onLines := func(p GeomPoint) bool {
+		for _, l := range ps.lines {
+			if pointOnLine(p, l) {
+				return true
+			}
+		}
+		return false
+	}
+	return onLines(a) && onLines(b) && onLines(midpoint(a, b))
+}
+
+func (ps *geomParts) coversLine(line []GeomPoint) bool {
+	for i := 0; i+1 < len(line); i++ {
+		if !ps.coversSegment(line[i], line[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// coversPolygon checks whether the polygon is covered by one of the polygons of the parts.
+func (ps *geomParts) coversPolygon(rings [][]GeomPoint) bool {
+	for _, poly := range ps.polygons {
+		covered := true
+		for i := 0; i+1 < len(rings[0]) && covered; i++ {
+			covered = segmentInPolygon(rings[0][i], rings[0][i+1], poly)
+		}
+		// The holes of the covering polygon must not be in the polygon.
+		for _, hole := range poly[1:] {
+			if !covered {
+				break
+			}
+			for _, p := range hole {
+				if pointInPolygon(p, rings) == inside {
+					covered = false
+					break
+				}
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// Within checks whether the geometry is within the other one: no point of the geometry is in the exterior of the
+// other one, and their interiors share at least one point.
+func (g *Geometry) Within(other *Geometry) bool {
+	a, b := g.parts(), other.parts()
+	if a.isEmpty() || b.isEmpty() {
+		return false
+	}
+	interiorsIntersect := false
+	for _, p := range a.points {
+		if !b.coversPoint(p) {
+			return false
+		}
+		interiorsIntersect = interiorsIntersect || b.pointInInterior(p)
+	}
+	for _, l := range a.lines {
+		if !b.coversLine(l) {
+			return false
+		}
+		for i := 0; i+1 < len(l) && !interiorsIntersect; i++ {
+			interiorsIntersect = b.pointInInterior(midpoint(l[i], l[i+1]))
+		}
+	}
+	for _, poly := range a.polygons {
+		if !b.coversPolygon(poly) {
+			return false
+		}
+		interiorsIntersect = true
+	}
+	return interiorsIntersect
+}
+
+// Contains checks whether the geometry contains the other one.
+func (g *Geometry) Contains(other *Geometry) bool {
+	return other.Within(g)
+}
+
+// BufferPointsPerCircle is the number of the points of the circle around a point, which is the default of the
+// point_circle strategy of MySQL.
+const BufferPointsPerCircle = 32
+
+// BufferPoint returns the polygon approximating the circle of the radius around the point.
+func (g *Geometry) BufferPoint(radius float64, pointsPerCircle int) *Geometry {
+	center := g.Points[0]
+	ring := make([]GeomPoint, 0, pointsPerCircle+1)
+	for i := 0; i < pointsPerCircle; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(pointsPerCircle)
+		ring = append(ring, GeomPoint{X: center.X + radius*math.Cos(angle), Y: center.Y + radius*math.Sin(angle)})
+	}
+	ring = append(ring, ring[0])
+	return &Geometry{Type: GeometryTypePolygon, SRID: g.SRID, Rings: [][]GeomPoint{ring}}
+}
+
+// DefaultSphereRadius is the default radius of the sphere used by ST_Distance_Sphere in meters.
+const DefaultSphereRadius = 6370986
+
+// DistanceSphere returns the minimum spherical distance between the points of the geometries on the sphere of the
+// radius. The points are in the latitude-longitude order like CheckGeographicRange expects, the caller swaps the
+// coordinates of the points of SRID 0.
+func (g *Geometry) DistanceSphere(other *Geometry, radius float64) float64 {
+	dist := math.Inf(1)
+	g.WalkPoints(func(p1 GeomPoint) bool {
+		other.WalkPoints(func(p2 GeomPoint) bool {
+			dist = math.Min(dist, haversine(p1, p2, radius))
+			return true
+		})
+		return true
+	})
+	return dist
+}
+
+func haversine(p1, p2 GeomPoint, radius float64) float64 {
+	lat1, lon1 := p1.X*math.Pi/180, p1.Y*math.Pi/180
+	lat2, lon2 := p2.X*math.Pi/180, p2.Y*math.Pi/180
+	sinLat, sinLon := math.Sin((lat2-lat1)/2), math.Sin((lon2-lon1)/2)
+	a := sinLat*sinLat + math.Cos(lat1)*math.Cos(lat2)*sinLon*sinLon
+	return 2 * radius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// SwapXY returns a copy of the geometry whose coordinates of every point are swapped.
+func (g *Geometry) SwapXY() *Geometry {
+	swapped := &Geometry{Type: g.Type, SRID: g.SRID}
+	swapPoints := func(points []GeomPoint) []GeomPoint {
+		res := make([]GeomPoint, len(points))
+		for i, p := range points {
+			res[i] = GeomPoint{X: p.Y, Y: p.X}
+		}
+		return res
+	}
+	if g.Points != nil {
+		swapped.Points = swapPoints(g.Points)
+	}
+	for _, ring := range g.Rings {
+		swapped.Rings = append(swapped.Rings, swapPoints(ring))
+	}
+	for _, elem := range g.Geometries {
+		swapped.Geometries = append(swapped.Geometries, elem.SwapXY())
+	}
+	return swapped
+}
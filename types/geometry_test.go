@@ -0,0 +1,191 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeometryFromWKT(t *testing.T) {
+	tests := []struct {
+		wkt    string
+		tp     GeometryType
+		expect string
+	}{
+		{"POINT(1 2)", GeometryTypePoint, "POINT(1 2)"},
+		{" point ( -1.5  2e3 ) ", GeometryTypePoint, "POINT(-1.5 2000)"},
+		{"LINESTRING(0 0,1 1,2 0)", GeometryTypeLineString, "LINESTRING(0 0,1 1,2 0)"},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0),(1 1,2 1,2 2,1 1))", GeometryTypePolygon, "POLYGON((0 0,10 0,10 10,0 10,0 0),(1 1,2 1,2 2,1 1))"},
+		{"MULTIPOINT(1 1, 2 2)", GeometryTypeMultiPoint, "MULTIPOINT((1 1),(2 2))"},
+		{"MULTIPOINT((1 1),(2 2))", GeometryTypeMultiPoint, "MULTIPOINT((1 1),(2 2))"},
+		{"MULTILINESTRING((0 0,1 1),(2 2,3 3))", GeometryTypeMultiLineString, "MULTILINESTRING((0 0,1 1),(2 2,3 3))"},
+		{"MULTIPOLYGON(((0 0,1 0,1 1,0 0)))", GeometryTypeMultiPolygon, "MULTIPOLYGON(((0 0,1 0,1 1,0 0)))"},
+		{"GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))", GeometryTypeGeometryCollection, "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))"},
+		{"GEOMCOLLECTION EMPTY", GeometryTypeGeometryCollection, "GEOMETRYCOLLECTION EMPTY"},
+		{"GEOMETRYCOLLECTION()", GeometryTypeGeometryCollection, "GEOMETRYCOLLECTION EMPTY"},
+		{"POINT(0.000001 1e20)", GeometryTypePoint, "POINT(1e-06 1e20)"},
+	}
+	for _, tt := range tests {
+		g, err := ParseGeometryFromWKT(tt.wkt, 4326)
+		require.NoError(t, err, tt.wkt)
+		require.Equal(t, tt.tp, g.Type, tt.wkt)
+		require.Equal(t, uint32(4326), g.SRID)
+		require.Equal(t, tt.expect, g.String())
+
+		decoded, err := DecodeGeometry(g.Encode())
+		require.NoError(t, err)
+		require.Equal(t, tt.expect, decoded.String())
+		require.Equal(t, uint32(4326), decoded.SRID)
+	}
+
+	for _, wkt := range []string{
+		"",
+		"POINT(1)",
+		"POINT(1 2",
+		"POINT(1 2) x",
+		"LINESTRING(0 0)",
+		"POLYGON((0 0,1 0,1 1,0 1))",
+		"POLYGON((0 0,1 0,0 0))",
+		"MULTIPOLYGON((0 0,1 0,1 1,0 0))",
+		"CIRCLE(0 0)",
+	} {
+		_, err := ParseGeometryFromWKT(wkt, 0)
+		require.Error(t, err, wkt)
+	}
+}
+
+func TestDecodeGeometry(t *testing.T) {
+	// SRID 0 followed by the big-endian WKB of POINT(1 2).
+	b, err := hex.DecodeString("00000000" + "00" + "00000001" + "3ff0000000000000" + "4000000000000000")
+	require.NoError(t, err)
+	g, err := DecodeGeometry(b)
+	require.NoError(t, err)
+	require.Equal(t, "POINT(1 2)", g.String())
+	require.Equal(t, "0101000000000000000000f03f0000000000000040", hex.EncodeToString(g.WKB()))
+
+	g = NewPointGeometry(3857, 1, 2)
+	require.Equal(t, uint32(3857), GetGeometrySRID(g.Encode()))
+
+	for _, s := range []string{
+		"",
+		"00000000",
+		"000000000101000000000000000000f03f",
+		"000000000101000000000000000000f03f000000000000004000",
+		"000000000109000000",
+		"00000000010200000001000000000000000000f03f0000000000000040",
+	} {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		_, err = DecodeGeometry(b)
+		require.Error(t, err, s)
+	}
+}
+
+func TestGeometryRelations(t *testing.T) {
+	mustParse := func(wkt string) *Geometry {
+		g, err := ParseGeometryFromWKT(wkt, 0)
+		require.NoError(t, err, wkt)
+		return g
+	}
+	tests := []struct {
+		g1, g2     string
+		intersects bool
+		within     bool
+	}{
+		{"POINT(1 1)", "POINT(1 1)", true, true},
+		{"POINT(1 1)", "POINT(1 2)", false, false},
+		{"POINT(5 5)", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, true},
+		{"POINT(0 5)", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, false},
+		{"POINT(1.5 1.5)", "POLYGON((0 0,10 0,10 10,0 10,0 0),(1 1,2 1,2 2,1 2,1 1))", false, false},
+		{"POINT(0.5 0.5)", "LINESTRING(0 0,1 1)", true, true},
+		{"POINT(0 0)", "LINESTRING(0 0,1 1)", true, false},
+		{"LINESTRING(0 0,2 2)", "LINESTRING(0 2,2 0)", true, false},
+		{"LINESTRING(1 1,2 2)", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, true},
+		{"LINESTRING(-1 5,11 5)", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, false},
+		{"POLYGON((1 1,2 1,2 2,1 2,1 1))", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, true},
+		{"POLYGON((5 5,15 5,15 15,5 15,5 5))", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, false},
+		{"POLYGON((20 20,30 20,30 30,20 20))", "POLYGON((0 0,10 0,10 10,0 10,0 0))", false, false},
+		{"MULTIPOINT((1 1),(5 5))", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, true},
+		{"MULTIPOINT((1 1),(50 5))", "POLYGON((0 0,10 0,10 10,0 10,0 0))", true, false},
+		{"GEOMETRYCOLLECTION EMPTY", "POLYGON((0 0,10 0,10 10,0 10,0 0))", false, false},
+	}
+	for _, tt := range tests {
+		g1, g2 := mustParse(tt.g1), mustParse(tt.g2)
+		require.Equal(t, tt.intersects, g1.Intersects(g2), "%s intersects %s", tt.g1, tt.g2)
+		require.Equal(t, tt.intersects, g2.Intersects(g1), "%s intersects %s", tt.g2, tt.g1)
+		require.Equal(t, tt.within, g1.Within(g2), "%s within %s", tt.g1, tt.g2)
+		require.Equal(t, tt.within, g2.Contains(g1), "%s contains %s", tt.g2, tt.g1)
+	}
+}
+
+func TestGeometryBufferAndDistance(t *testing.T) {
+	buffer := NewPointGeometry(0, 1, 1).BufferPoint(2, BufferPointsPerCircle)
+	require.Equal(t, GeometryTypePolygon, buffer.Type)
+	require.Len(t, buffer.Rings[0], BufferPointsPerCircle+1)
+	require.Equal(t, buffer.Rings[0][0], buffer.Rings[0][BufferPointsPerCircle])
+	require.Equal(t, GeomPoint{X: 3, Y: 1}, buffer.Rings[0][0])
+	require.True(t, NewPointGeometry(0, 2, 2).Within(buffer))
+	require.False(t, NewPointGeometry(0, 3, 3).Within(buffer))
+
+	// The points are in the latitude-longitude order.
+	d := NewPointGeometry(4326, 0, 0).DistanceSphere(NewPointGeometry(4326, 0, 1), DefaultSphereRadius)
+	require.InDelta(t, 111194.68, d, 0.01)
+	d = NewPointGeometry(4326, 90, 0).DistanceSphere(NewPointGeometry(4326, -90, 0), 1)
+	require.InDelta(t, 3.14159265, d, 1e-8)
+
+	swapped := NewPointGeometry(0, 1, 2).SwapXY()
+	require.Equal(t, "POINT(2 1)", swapped.String())
+
+	err := NewPointGeometry(4326, 91, 0).CheckGeographicRange("st_distance_sphere")
+	require.True(t, terror.ErrorEqual(err, ErrLatitudeOutOfRange))
+	err = NewPointGeometry(4326, 0, -180).CheckGeographicRange("st_distance_sphere")
+	require.True(t, terror.ErrorEqual(err, ErrLongitudeOutOfRange))
+	require.NoError(t, NewPointGeometry(4326, -90, 180).CheckGeographicRange("st_distance_sphere"))
+
+	_, err = GetSpatialReferenceSystem(1234)
+	require.True(t, terror.ErrorEqual(err, ErrSRSNotFound))
+}
+
+func TestConvertToGeometry(t *testing.T) {
+	sc := new(stmtctx.StatementContext)
+	ft := NewFieldType(mysql.TypeGeometry)
+	ft.SetGeometryType(GeometryTypePoint)
+
+	convert := func(d Datum) (Datum, error) {
+		return d.ConvertTo(sc, ft)
+	}
+	point := NewPointGeometry(0, 1, 2).Encode()
+	d, err := convert(NewBytesDatum(point))
+	require.NoError(t, err)
+	require.Equal(t, point, d.GetBytes())
+
+	line, err := ParseGeometryFromWKT("LINESTRING(0 0,1 1)", 0)
+	require.NoError(t, err)
+	_, err = convert(NewBytesDatum(line.Encode()))
+	require.True(t, terror.ErrorEqual(err, ErrCantCreateGeometryObject))
+	_, err = convert(NewStringDatum("POINT(1 2)"))
+	require.True(t, terror.ErrorEqual(err, ErrCantCreateGeometryObject))
+
+	ft.SetGeometryType(GeometryTypeGeometry)
+	d, err = convert(NewBytesDatum(line.Encode()))
+	require.NoError(t, err)
+	require.Equal(t, line.Encode(), d.GetBytes())
+}
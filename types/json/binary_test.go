@@ -92,6 +92,30 @@ func TestBinaryJSONExtract(t *testing.T) {
 	}
 }
 
+func TestBinaryJSONExtractAll(t *testing.T) {
+	bj := mustParseBinaryFromString(t, `{"a": [1, "2", {"aa": "bb"}, [3]], "b": {"aa": "cc"}}`)
+	var tests = []struct {
+		pathExpr string
+		expected []string
+	}{
+		{"$.a", []string{`[1, "2", {"aa": "bb"}, [3]]`}},
+		{"$.a[*]", []string{`1`, `"2"`, `{"aa": "bb"}`, `[3]`}},
+		{"$**.aa", []string{`"bb"`, `"cc"`}},
+		{"$.a[3][*]", []string{`3`}},
+		{"$.c", nil},
+		{"$.a[10]", nil},
+	}
+	for _, test := range tests {
+		pe, err := ParseJSONPathExpr(test.pathExpr)
+		require.NoError(t, err)
+		var result []string
+		for _, v := range bj.ExtractAll(pe) {
+			result = append(result, v.String())
+		}
+		require.Equal(t, test.expected, result, test.pathExpr)
+	}
+}
+
 func TestBinaryJSONType(t *testing.T) {
 	var tests = []struct {
 		in  string
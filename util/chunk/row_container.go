@@ -160,6 +160,23 @@ func (c *RowContainer) Reset() error {
 	return nil
 }
 
+// Clear clears RowContainer. Different from Reset, the chunks in memory are released instead of being kept for reuse,
+// so the rows got from the RowContainer before are still valid after it's cleared.
+func (c *RowContainer) Clear() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.alreadySpilled() {
+		err := c.m.records.inDisk.Close()
+		c.m.records.inDisk = nil
+		if err != nil {
+			return err
+		}
+		c.actionSpill.Reset()
+	}
+	c.m.records.inMemory.Clear()
+	return nil
+}
+
 // alreadySpilled indicates that records have spilled out into disk.
 func (c *RowContainer) alreadySpilled() bool {
 	return c.m.records.inDisk != nil
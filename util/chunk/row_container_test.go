@@ -219,6 +219,44 @@ func TestRowContainerResetAndAction(t *testing.T) {
 	require.Greater(t, rc.GetDiskTracker().BytesConsumed(), int64(0))
 }
 
+func TestRowContainerClear(t *testing.T) {
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+	sz := 20
+	rc := NewRowContainer(fields, sz)
+
+	chk := NewChunkWithCapacity(fields, sz)
+	for i := 0; i < sz; i++ {
+		chk.AppendInt64(0, int64(i))
+	}
+	require.NoError(t, rc.Add(chk))
+	row, err := rc.GetRow(RowPtr{ChkIdx: 0, RowIdx: 1})
+	require.NoError(t, err)
+	require.Greater(t, rc.GetMemTracker().BytesConsumed(), int64(0))
+	// The memory is released, and the chunk isn't reused.
+	require.NoError(t, rc.Clear())
+	require.Equal(t, int64(0), rc.GetMemTracker().BytesConsumed())
+	require.Equal(t, 0, rc.NumRow())
+	newChk := rc.AllocChunk()
+	newChk.AppendInt64(0, 100)
+	require.Equal(t, int64(1), row.GetInt64(0))
+
+	// Clear and Spill again.
+	tracker := rc.GetMemTracker()
+	tracker.SetBytesLimit(chk.MemoryUsage() + 1)
+	tracker.FallbackOldAndSetNewAction(rc.ActionSpillForTest())
+	require.NoError(t, rc.Add(chk))
+	require.NoError(t, rc.Add(chk))
+	rc.actionSpill.WaitForTest()
+	require.Greater(t, rc.GetDiskTracker().BytesConsumed(), int64(0))
+	require.NoError(t, rc.Clear())
+	require.Equal(t, int64(0), rc.GetDiskTracker().BytesConsumed())
+	require.NoError(t, rc.Add(chk))
+	require.NoError(t, rc.Add(chk))
+	rc.actionSpill.WaitForTest()
+	require.Greater(t, rc.GetDiskTracker().BytesConsumed(), int64(0))
+	require.NoError(t, rc.Close())
+}
+
 func TestSpillActionDeadLock(t *testing.T) {
 	// Maybe get deadlock if we use two RLock in one goroutine, for oom-action call stack.
 	// Now the implement avoids the situation.
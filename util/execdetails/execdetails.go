@@ -455,6 +455,10 @@ const (
 	TpBasicCopRunTimeStats
 	// TpUpdateRuntimeStats is the tp for UpdateRuntimeStats
 	TpUpdateRuntimeStats
+	// TpRuntimeFilterRuntimeStats is the tp for the runtime stats of the runtime filters.
+	TpRuntimeFilterRuntimeStats
+	// TpAdaptiveJoinRuntimeStats is the tp for the runtime stats of the adaptive joins.
+	TpAdaptiveJoinRuntimeStats
 )
 
 // RuntimeStats is used to express the executor runtime information.
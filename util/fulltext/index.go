@@ -0,0 +1,70 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// A FULLTEXT index is an inverted index. Every distinct token of a row is stored as an index entry,
+// the token is stored as the value of the first index column and the other index columns are NULL,
+// so the layout of the keys is the same as the normal non-unique index:
+//
+//	t{tableID}_i{indexID}{token}{NULL...}{handle}
+//
+// The tokens are encoded with the collation of the first index column.
+
+// Collation returns the collation of the tokens of the index.
+func Collation(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) string {
+	return tblInfo.Columns[idxInfo.Columns[0].Offset].GetCollate()
+}
+
+// TokenDatum returns the value of the first index column of the index entry of the token.
+func TokenDatum(token, collation string) types.Datum {
+	return types.NewCollationStringDatum(token, collation)
+}
+
+// IndexedValues returns the index column values of the index entry of the token.
+func IndexedValues(tblInfo *model.TableInfo, idxInfo *model.IndexInfo, token string) []types.Datum {
+	vals := make([]types.Datum, len(idxInfo.Columns))
+	vals[0] = TokenDatum(token, Collation(tblInfo, idxInfo))
+	return vals
+}
+
+// DocumentOf tokenizes the index column values of a row.
+func DocumentOf(t Tokenizer, indexedValues []types.Datum) *Document {
+	texts := make([]string, 0, len(indexedValues))
+	for _, v := range indexedValues {
+		if !v.IsNull() {
+			texts = append(texts, v.GetString())
+		}
+	}
+	return NewDocument(t, texts...)
+}
+
+// TokenKeyRange returns the key range of the index entries of the token in the physical table.
+func TokenKeyRange(sc *stmtctx.StatementContext, physicalID, indexID int64, collation, token string) (kv.KeyRange, error) {
+	prefix := tablecodec.EncodeTableIndexPrefix(physicalID, indexID)
+	key, err := codec.EncodeKey(sc, prefix, TokenDatum(token, collation))
+	if err != nil {
+		return kv.KeyRange{}, err
+	}
+	return kv.KeyRange{StartKey: key, EndKey: kv.Key(key).PrefixNext()}, nil
+}
@@ -0,0 +1,392 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/util/mathutil"
+	"golang.org/x/exp/slices"
+)
+
+// Document is the tokenized text of the FULLTEXT columns of a row.
+type Document struct {
+	tokens []string
+	tf     map[string]int
+}
+
+// NewDocument tokenizes the texts as one document.
+func NewDocument(t Tokenizer, texts ...string) *Document {
+	doc := &Document{tf: make(map[string]int)}
+	for _, text := range texts {
+		doc.tokens = append(doc.tokens, t.Tokenize(text)...)
+	}
+	for _, token := range doc.tokens {
+		doc.tf[token]++
+	}
+	return doc
+}
+
+// UniqueTokens returns the distinct tokens of the document.
+func (doc *Document) UniqueTokens() []string {
+	tokens := make([]string, 0, len(doc.tf))
+	for token := range doc.tf {
+		tokens = append(tokens, token)
+	}
+	slices.Sort(tokens)
+	return tokens
+}
+
+// phraseCount returns how many times the tokens appear contiguously in the document.
+func (doc *Document) phraseCount(phrase []string) int {
+	cnt := 0
+	for i := 0; i+len(phrase) <= len(doc.tokens); i++ {
+		match := true
+		for j, token := range phrase {
+			if doc.tokens[i+j] != token {
+				match = false
+				break
+			}
+		}
+		if match {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IDF returns the inverse document frequency of a token which appears in docs of the total documents.
+// Unlike InnoDB, a token appearing in every document still has a positive weight, so that the rows
+// matching the query are never filtered out because the table is small.
+func IDF(total, docs int64) float64 {
+	if docs < 1 {
+		docs = 1
+	}
+	if total < docs {
+		total = docs
+	}
+	return math.Log10(float64(total+1) / float64(docs))
+}
+
+// IDFFunc returns the inverse document frequency of a token.
+type IDFFunc func(token string) (float64, error)
+
+// booleanItem is an operand of a query in boolean mode.
+type booleanItem struct {
+	// op is the operator before the operand, it's one of '+', '-', '~', '<', '>' or 0.
+	op byte
+	// tokens are the tokens of a word or a phrase.
+	tokens []string
+	// phrase is true if the tokens must appear contiguously.
+	phrase bool
+	// prefix is true if the word is followed by the truncation operator '*'.
+	prefix bool
+	// group is the operands in the parentheses.
+	group []*booleanItem
+}
+
+// Query is the parsed search string of MATCH ... AGAINST.
+type Query struct {
+	boolean bool
+	// tokens are the distinct tokens of the query in natural language mode.
+	tokens []string
+	// items are the operands of the query in boolean mode.
+	items []*booleanItem
+}
+
+// NewNaturalLanguageQuery parses the search string in natural language mode.
+func NewNaturalLanguageQuery(t Tokenizer, text string) *Query {
+	q := &Query{}
+	for _, token := range t.Tokenize(text) {
+		if !containsString(q.tokens, token) {
+			q.tokens = append(q.tokens, token)
+		}
+	}
+	return q
+}
+
+// NewBooleanQuery parses the search string in boolean mode. The operators are the same as the
+// default value of `ft_boolean_syntax`, the unmatched parentheses and quotes are closed implicitly.
+func NewBooleanQuery(t Tokenizer, text string) *Query {
+	p := &booleanParser{t: t, text: text}
+	return &Query{boolean: true, items: p.parseGroup()}
+}
+
+type booleanParser struct {
+	t    Tokenizer
+	text string
+	pos  int
+}
+
+func isBooleanOperator(r rune) bool {
+	return strings.ContainsRune(`+-~<>()"*@`, r)
+}
+
+func (p *booleanParser) parseGroup() []*booleanItem {
+	var (
+		items []*booleanItem
+		op    byte
+	)
+	for p.pos < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		switch {
+		case unicode.IsSpace(r):
+			p.pos += size
+			op = 0
+		case r == '+' || r == '-' || r == '~' || r == '<' || r == '>':
+			p.pos += size
+			op = byte(r)
+		case r == '(':
+			p.pos += size
+			if group := p.parseGroup(); len(group) > 0 {
+				items = append(items, &booleanItem{op: op, group: group})
+			}
+			op = 0
+		case r == ')':
+			p.pos += size
+			return items
+		case r == '"':
+			p.pos += size
+			end := strings.IndexByte(p.text[p.pos:], '"')
+			if end < 0 {
+				end = len(p.text) - p.pos
+			}
+			if tokens := p.t.Tokenize(p.text[p.pos : p.pos+end]); len(tokens) > 0 {
+				items = append(items, &booleanItem{op: op, tokens: tokens, phrase: true})
+			}
+			p.pos = mathutil.Min(p.pos+end+1, len(p.text))
+			op = 0
+		case r == '@':
+			// The proximity operator of InnoDB is not supported, the operator and its distance are ignored.
+			p.pos += size
+			for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+				p.pos++
+			}
+		case r == '*':
+			// A truncation operator without a word is ignored.
+			p.pos += size
+		default:
+			start := p.pos
+			for p.pos < len(p.text) {
+				r, size = utf8.DecodeRuneInString(p.text[p.pos:])
+				if unicode.IsSpace(r) || isBooleanOperator(r) {
+					break
+				}
+				p.pos += size
+			}
+			word := p.text[start:p.pos]
+			prefix := p.pos < len(p.text) && p.text[p.pos] == '*'
+			if prefix {
+				p.pos++
+			}
+			if item := p.newWordItem(op, word, prefix); item != nil {
+				items = append(items, item)
+			}
+			op = 0
+		}
+	}
+	return items
+}
+
+func (p *booleanParser) newWordItem(op byte, word string, prefix bool) *booleanItem {
+	tokens := p.t.Tokenize(word)
+	if prefix && len(tokens) == 0 && !IsStopword(strings.ToLower(word)) {
+		// The truncated word is allowed to be shorter than the minimum token size.
+		tokens = []string{strings.ToLower(word)}
+	}
+	switch len(tokens) {
+	case 0:
+		return nil
+	case 1:
+		return &booleanItem{op: op, tokens: tokens, prefix: prefix}
+	default:
+		// A word which is split into several tokens, e.g. by the ngram tokenizer, is searched as a phrase.
+		return &booleanItem{op: op, tokens: tokens, phrase: true}
+	}
+}
+
+// Score returns the relevance of the document, the document matches the query if the relevance is not zero.
+// The relevance of a token is tf * idf * idf, which is the same as InnoDB.
+func (q *Query) Score(doc *Document, idf IDFFunc) (float64, error) {
+	if !q.boolean {
+		var score float64
+		for _, token := range q.tokens {
+			tf := doc.tf[token]
+			if tf == 0 {
+				continue
+			}
+			w, err := idf(token)
+			if err != nil {
+				return 0, err
+			}
+			score += float64(tf) * w * w
+		}
+		return score, nil
+	}
+	_, score, err := scoreGroup(q.items, doc, idf)
+	return score, err
+}
+
+func scoreGroup(items []*booleanItem, doc *Document, idf IDFFunc) (matched bool, score float64, err error) {
+	hasRequired, hasOptional := false, false
+	for _, item := range items {
+		m, s, err := scoreItem(item, doc, idf)
+		if err != nil {
+			return false, 0, err
+		}
+		switch item.op {
+		case '+':
+			if !m {
+				return false, 0, nil
+			}
+			hasRequired = true
+			score += s
+		case '-':
+			if m {
+				return false, 0, nil
+			}
+		case '~':
+			hasOptional = hasOptional || m
+			score -= s
+		case '>':
+			hasOptional = hasOptional || m
+			score += s * 1.5
+		case '<':
+			hasOptional = hasOptional || m
+			score += s / 1.5
+		default:
+			hasOptional = hasOptional || m
+			score += s
+		}
+	}
+	if !hasRequired && !hasOptional {
+		return false, 0, nil
+	}
+	if score == 0 {
+		// The relevance of a matched row must not be zero, e.g. `MATCH (a) AGAINST ('~word' IN BOOLEAN MODE)`.
+		score = math.SmallestNonzeroFloat64
+	}
+	return true, score, nil
+}
+
+func scoreItem(item *booleanItem, doc *Document, idf IDFFunc) (matched bool, score float64, err error) {
+	switch {
+	case item.group != nil:
+		return scoreGroup(item.group, doc, idf)
+	case item.phrase:
+		cnt := doc.phraseCount(item.tokens)
+		if cnt == 0 {
+			return false, 0, nil
+		}
+		for _, token := range item.tokens {
+			w, err := idf(token)
+			if err != nil {
+				return false, 0, err
+			}
+			score += float64(cnt) * w * w
+		}
+		return true, score, nil
+	case item.prefix:
+		for token, tf := range doc.tf {
+			if !strings.HasPrefix(token, item.tokens[0]) {
+				continue
+			}
+			w, err := idf(token)
+			if err != nil {
+				return false, 0, err
+			}
+			matched = true
+			score += float64(tf) * w * w
+		}
+		return matched, score, nil
+	default:
+		tf := doc.tf[item.tokens[0]]
+		if tf == 0 {
+			return false, 0, nil
+		}
+		w, err := idf(item.tokens[0])
+		if err != nil {
+			return false, 0, err
+		}
+		return true, float64(tf) * w * w, nil
+	}
+}
+
+// AccessTokens returns the tokens to look up in the index, every document which matches the query
+// contains at least one of the tokens. It returns false if the query can't be answered by the index,
+// e.g. the truncation operator is used.
+func (q *Query) AccessTokens() ([]string, bool) {
+	if !q.boolean {
+		return q.tokens, true
+	}
+	return accessTokens(q.items)
+}
+
+func accessTokens(items []*booleanItem) ([]string, bool) {
+	hasRequired := false
+	for _, item := range items {
+		if item.op != '+' {
+			continue
+		}
+		hasRequired = true
+		if tokens, ok := itemAccessTokens(item); ok {
+			// Every matched document contains the required operand.
+			return tokens, true
+		}
+	}
+	if hasRequired {
+		return nil, false
+	}
+	var tokens []string
+	for _, item := range items {
+		if item.op == '-' {
+			continue
+		}
+		itemTokens, ok := itemAccessTokens(item)
+		if !ok {
+			return nil, false
+		}
+		for _, token := range itemTokens {
+			if !containsString(tokens, token) {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens, true
+}
+
+func itemAccessTokens(item *booleanItem) ([]string, bool) {
+	switch {
+	case item.group != nil:
+		return accessTokens(item.group)
+	case item.prefix:
+		return nil, false
+	default:
+		// Every document containing the phrase contains its first token.
+		return item.tokens[:1], true
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func unitIDF(string) (float64, error) {
+	return 1, nil
+}
+
+func TestDocument(t *testing.T) {
+	doc := NewDocument(standardTokenizer{}, "red fox", "Quick red FOX")
+	require.Equal(t, []string{"fox", "quick", "red"}, doc.UniqueTokens())
+	require.Equal(t, 2, doc.tf["fox"])
+	require.Equal(t, 2, doc.phraseCount([]string{"red", "fox"}))
+	require.Equal(t, 1, doc.phraseCount([]string{"fox", "quick"}))
+	require.Equal(t, 0, doc.phraseCount([]string{"fox", "red"}))
+}
+
+func TestIDF(t *testing.T) {
+	require.InDelta(t, 1.0, IDF(9, 1), 1e-9)
+	require.InDelta(t, 1.0, IDF(9, 0), 1e-9)
+	// The token appears in every document.
+	require.Greater(t, IDF(10, 10), 0.0)
+	require.Equal(t, IDF(3, 3), IDF(1, 3))
+}
+
+func TestNaturalLanguageQuery(t *testing.T) {
+	q := NewNaturalLanguageQuery(standardTokenizer{}, "red fox, red FOX")
+	tokens, ok := q.AccessTokens()
+	require.True(t, ok)
+	require.Equal(t, []string{"red", "fox"}, tokens)
+
+	score, err := q.Score(NewDocument(standardTokenizer{}, "red red fox"), unitIDF)
+	require.NoError(t, err)
+	require.Equal(t, 3.0, score)
+	score, err = q.Score(NewDocument(standardTokenizer{}, "blue dog"), unitIDF)
+	require.NoError(t, err)
+	require.Equal(t, 0.0, score)
+}
+
+func TestBooleanQuery(t *testing.T) {
+	docs := []string{
+		"quick red fox",
+		"lazy brown dog",
+		"red dog",
+		"quick brown fox jumps",
+	}
+	tests := []struct {
+		query   string
+		matched []bool
+		tokens  []string
+		ok      bool
+	}{
+		{"red fox", []bool{true, false, true, true}, []string{"red", "fox"}, true},
+		{"+red -dog", []bool{true, false, false, false}, []string{"red"}, true},
+		{"+fox +brown", []bool{false, false, false, true}, []string{"fox"}, true},
+		{"-fox", []bool{false, false, false, false}, nil, true},
+		{"~dog", []bool{false, true, true, false}, []string{"dog"}, true},
+		{`"brown fox"`, []bool{false, false, false, true}, []string{"brown"}, true},
+		{`+"red fox`, []bool{true, false, false, false}, []string{"red"}, true},
+		{"qui*", []bool{true, false, false, true}, nil, false},
+		{"+(red dog) -lazy", []bool{true, false, true, false}, []string{"red", "dog"}, true},
+		{">red <dog @3", []bool{true, true, true, false}, []string{"red", "dog"}, true},
+		{"the", []bool{false, false, false, false}, nil, true},
+	}
+	for _, tt := range tests {
+		q := NewBooleanQuery(standardTokenizer{}, tt.query)
+		for i, text := range docs {
+			score, err := q.Score(NewDocument(standardTokenizer{}, text), unitIDF)
+			require.NoError(t, err)
+			require.Equal(t, tt.matched[i], score != 0, "%s: %s", tt.query, text)
+		}
+		tokens, ok := q.AccessTokens()
+		require.Equal(t, tt.ok, ok, tt.query)
+		require.Equal(t, tt.tokens, tokens, tt.query)
+	}
+}
+
+func TestBooleanQueryWeight(t *testing.T) {
+	doc := NewDocument(standardTokenizer{}, "red fox")
+	score, err := NewBooleanQuery(standardTokenizer{}, ">red fox").Score(doc, unitIDF)
+	require.NoError(t, err)
+	require.Equal(t, 2.5, score)
+	score, err = NewBooleanQuery(standardTokenizer{}, "red ~fox").Score(doc, unitIDF)
+	require.NoError(t, err)
+	require.Greater(t, score, 0.0)
+	require.Less(t, score, 1e-300)
+}
+
+func TestNgramBooleanQuery(t *testing.T) {
+	tokenizer := ngramTokenizer{n: NgramTokenSize}
+	q := NewBooleanQuery(tokenizer, "+数据库")
+	tokens, ok := q.AccessTokens()
+	require.True(t, ok)
+	require.Equal(t, []string{"数据"}, tokens)
+	for text, matched := range map[string]bool{"分布式数据库": true, "数据 库": false, "据库数据": false} {
+		score, err := q.Score(NewDocument(tokenizer, text), unitIDF)
+		require.NoError(t, err)
+		require.Equal(t, matched, score != 0, text)
+	}
+}
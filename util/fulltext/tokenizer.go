@@ -0,0 +1,136 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// StandardParser is the name of the default tokenizer, which is used when a FULLTEXT index
+	// is created without the `WITH PARSER` clause.
+	StandardParser = "standard"
+	// NgramParser is the name of the tokenizer for ideographic languages, such as Chinese and Japanese.
+	NgramParser = "ngram"
+
+	// MinTokenSize is the minimum length in characters of the tokens of the standard tokenizer,
+	// it's the same as the default value of `innodb_ft_min_token_size`.
+	MinTokenSize = 3
+	// MaxTokenSize is the maximum length in characters of the tokens of the standard tokenizer,
+	// it's the same as the default value of `innodb_ft_max_token_size`.
+	MaxTokenSize = 84
+	// NgramTokenSize is the length in characters of the tokens of the ngram tokenizer,
+	// it's the same as the default value of `ngram_token_size`.
+	NgramTokenSize = 2
+)
+
+// Tokenizer splits a text into the tokens which are stored in a FULLTEXT index.
+// The tokens are used as the keys of the index, so a tokenizer must be deterministic,
+// and its output for the same text must never change once the index is created.
+type Tokenizer interface {
+	// Tokenize returns the tokens of the text in the order they appear. The tokens are
+	// normalized, e.g. lowercased, and the same token may appear more than once.
+	Tokenize(text string) []string
+}
+
+var tokenizers = struct {
+	sync.RWMutex
+	m map[string]Tokenizer
+}{m: make(map[string]Tokenizer)}
+
+// RegisterTokenizer registers a tokenizer with the parser name used in `WITH PARSER`.
+// It's usually called in the init function of the package which implements the tokenizer.
+func RegisterTokenizer(name string, t Tokenizer) {
+	tokenizers.Lock()
+	defer tokenizers.Unlock()
+	tokenizers.m[strings.ToLower(name)] = t
+}
+
+// GetTokenizer returns the tokenizer registered with the parser name, the standard
+// tokenizer is returned for the empty name.
+func GetTokenizer(name string) (Tokenizer, bool) {
+	if name == "" {
+		name = StandardParser
+	}
+	tokenizers.RLock()
+	defer tokenizers.RUnlock()
+	t, ok := tokenizers.m[strings.ToLower(name)]
+	return t, ok
+}
+
+func init() {
+	RegisterTokenizer(StandardParser, standardTokenizer{})
+	RegisterTokenizer(NgramParser, ngramTokenizer{n: NgramTokenSize})
+}
+
+// stopwords is the default stopword list of InnoDB, see `INFORMATION_SCHEMA.INNODB_FT_DEFAULT_STOPWORD`.
+var stopwords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "com": {}, "de": {},
+	"en": {}, "for": {}, "from": {}, "how": {}, "i": {}, "in": {}, "is": {}, "it": {}, "la": {}, "of": {},
+	"on": {}, "or": {}, "that": {}, "the": {}, "this": {}, "to": {}, "was": {}, "what": {}, "when": {}, "where": {},
+	"who": {}, "will": {}, "with": {}, "und": {}, "www": {},
+}
+
+// IsStopword returns whether the token is ignored by the standard tokenizer.
+func IsStopword(token string) bool {
+	_, ok := stopwords[token]
+	return ok
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// standardTokenizer splits the text into words by the characters which are neither letters nor digits,
+// the words which are too short, too long or in the stopword list are ignored.
+type standardTokenizer struct{}
+
+// Tokenize implements the Tokenizer interface.
+func (standardTokenizer) Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !isWordChar(r) }) {
+		n := utf8.RuneCountInString(word)
+		if n < MinTokenSize || n > MaxTokenSize {
+			continue
+		}
+		word = strings.ToLower(word)
+		if IsStopword(word) {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// ngramTokenizer splits the text into the contiguous sequences of n characters,
+// the sequences are never across the characters which are neither letters nor digits.
+type ngramTokenizer struct {
+	n int
+}
+
+// Tokenize implements the Tokenizer interface.
+func (t ngramTokenizer) Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !isWordChar(r) }) {
+		runes := []rune(strings.ToLower(word))
+		for i := 0; i+t.n <= len(runes); i++ {
+			tokens = append(tokens, string(runes[i:i+t.n]))
+		}
+	}
+	return tokens
+}
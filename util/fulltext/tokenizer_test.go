@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandardTokenizer(t *testing.T) {
+	tokenizer, ok := GetTokenizer("")
+	require.True(t, ok)
+	tests := []struct {
+		text   string
+		tokens []string
+	}{
+		{"", nil},
+		{"The quick brown fox", []string{"quick", "brown", "fox"}},
+		{"MySQL,TiDB;tikv-server", []string{"mysql", "tidb", "tikv", "server"}},
+		{"an it go up abc", []string{"abc"}},
+		{"snake_case and über", []string{"snake_case", "and", "über"}},
+		{"fox fox FOX", []string{"fox", "fox", "fox"}},
+		{strings.Repeat("a", MaxTokenSize+1) + " bar", []string{"bar"}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.tokens, tokenizer.Tokenize(tt.text), tt.text)
+	}
+}
+
+func TestNgramTokenizer(t *testing.T) {
+	tokenizer, ok := GetTokenizer("NGRAM")
+	require.True(t, ok)
+	tests := []struct {
+		text   string
+		tokens []string
+	}{
+		{"", nil},
+		{"a", nil},
+		{"Abc", []string{"ab", "bc"}},
+		{"数据库 ab", []string{"数据", "据库", "ab"}},
+		{"全文,检索", []string{"全文", "检索"}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.tokens, tokenizer.Tokenize(tt.text), tt.text)
+	}
+}
+
+type upperTokenizer struct{}
+
+func (upperTokenizer) Tokenize(text string) []string {
+	return strings.Fields(strings.ToUpper(text))
+}
+
+func TestRegisterTokenizer(t *testing.T) {
+	_, ok := GetTokenizer("upper")
+	require.False(t, ok)
+	RegisterTokenizer("Upper", upperTokenizer{})
+	tokenizer, ok := GetTokenizer("UPPER")
+	require.True(t, ok)
+	require.Equal(t, []string{"A", "B"}, tokenizer.Tokenize("a b"))
+}
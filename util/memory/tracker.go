@@ -703,6 +703,8 @@ const (
 	LabelForAnalyzeMemory int = -24
 	// LabelForGlobalAnalyzeMemory represents the label of the global memory of all analyze jobs
 	LabelForGlobalAnalyzeMemory int = -25
+	// LabelForHashAggWorker represents the label of the workers of the parallel HashAgg
+	LabelForHashAggWorker int = -26
 )
 
 // MetricsTypes is used to get label for metrics
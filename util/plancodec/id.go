@@ -127,6 +127,10 @@ const (
 	TypeCTE = "CTEFullScan"
 	// TypeCTEDefinition is the type of CTE definition
 	TypeCTEDefinition = "CTE"
+	// TypeJSONTable is the type of JSONTable.
+	TypeJSONTable = "JSONTable"
+	// TypeExpand is the type of Expand.
+	TypeExpand = "Expand"
 )
 
 // plan id.
@@ -187,6 +191,8 @@ const (
 	typePartitionUnionID      int = 53
 	typeShuffleID             int = 54
 	typeShuffleReceiverID     int = 55
+	typeJSONTableID           int = 56
+	typeExpandID              int = 57
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -302,6 +308,10 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeCTEDefinitionID
 	case TypeCTETable:
 		return typeCTETableID
+	case TypeJSONTable:
+		return typeJSONTableID
+	case TypeExpand:
+		return typeExpandID
 	}
 	// Should never reach here.
 	return 0
@@ -420,6 +430,10 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeCTEDefinition
 	case typeCTETableID:
 		return TypeCTETable
+	case typeJSONTableID:
+		return TypeJSONTable
+	case typeExpandID:
+		return TypeExpand
 	}
 
 	// Should never reach here.
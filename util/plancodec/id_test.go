@@ -81,6 +81,8 @@ func TestPlanIDChanged(t *testing.T) {
 		{typePartitionUnionID, 53},
 		{typeShuffleID, 54},
 		{typeShuffleReceiverID, 55},
+		{typeJSONTableID, 56},
+		{typeExpandID, 57},
 	}
 
 	for _, testcase := range testCases {
@@ -89,7 +91,7 @@ func TestPlanIDChanged(t *testing.T) {
 }
 
 func TestReverse(t *testing.T) {
-	for i := 1; i <= 55; i++ {
+	for i := 1; i <= 57; i++ {
 		require.Equal(t, TypeStringToPhysicalID(PhysicalIDToTypeString(i)), i)
 	}
 }
@@ -20,8 +20,10 @@ import (
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -159,6 +161,24 @@ type SQLParser interface {
 	ParseSQL(ctx context.Context, sql string, params ...parser.ParseParam) ([]ast.StmtNode, []error, error)
 }
 
+// TriggerExecutor is an interface provides executing the triggers activated by the DML statements.
+// The triggers are executed on top of the session, so we define this interface and use session as its implementation,
+// thus avoid the import cycle between executor and session.
+type TriggerExecutor interface {
+	// ExecTrigger executes the trigger of the table in the schema db for a row. oldRow is nil for INSERT and newRow is
+	// nil for DELETE. The BEFORE triggers can change the values of newRow.
+	ExecTrigger(ctx context.Context, db string, tblInfo *model.TableInfo, trigger *model.TriggerInfo, oldRow, newRow []types.Datum) error
+}
+
+// EventExecutor is an interface provides executing the events by the event scheduler.
+// The events are executed on top of the session, so we define this interface and use session as its implementation,
+// thus avoid the import cycle between domain and session.
+type EventExecutor interface {
+	// ExecEvent executes the body of the event in the schema db, with the SQL mode and the time zone of the event.
+	// createStmt is the CREATE EVENT statement of the event, which contains the body.
+	ExecEvent(ctx context.Context, db, createStmt, sqlMode, timeZone, dbCollation string) error
+}
+
 // Statement is an interface for SQL execution.
 // NOTE: all Statement implementations must be safe for
 // concurrent using by multiple goroutines.
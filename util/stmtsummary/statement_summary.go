@@ -420,6 +420,180 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 	return stmts
 }
 
+// WorkloadStmt is a statement extracted from statements_summary which represents the workload of users.
+type WorkloadStmt struct {
+	Schema     string
+	Query      string
+	ExecCount  int64
+	SumLatency time.Duration
+}
+
+// GetTopLatencyStmts gets at most `limit` users' select SQLs which cost the most total latency.
+// The prepared statements are excluded since their parameters are unknown.
+func (ssMap *stmtSummaryByDigestMap) GetTopLatencyStmts(limit int) []*WorkloadStmt {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	stmts := make([]*WorkloadStmt, 0, len(values))
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		func() {
+			ssbd.Lock()
+			defer ssbd.Unlock()
+			if !ssbd.initialized || ssbd.isInternal || ssbd.stmtType != "Select" || ssbd.history.Len() == 0 {
+				return
+			}
+			stmt := &WorkloadStmt{Schema: ssbd.schemaName}
+			for e := ssbd.history.Front(); e != nil; e = e.Next() {
+				ssElement := e.Value.(*stmtSummaryByDigestElement)
+				ssElement.Lock()
+				// Empty auth users means that it is an internal queries.
+				if len(ssElement.authUsers) > 0 && !ssElement.prepared {
+					stmt.Query = ssElement.sampleSQL
+					stmt.ExecCount += ssElement.execCount
+					stmt.SumLatency += ssElement.sumLatency
+				}
+				ssElement.Unlock()
+			}
+			if stmt.ExecCount > 0 {
+				stmts = append(stmts, stmt)
+			}
+		}()
+	}
+	slices.SortFunc(stmts, func(i, j *WorkloadStmt) bool {
+		return i.SumLatency > j.SumLatency
+	})
+	if len(stmts) > limit {
+		stmts = stmts[:limit]
+	}
+	return stmts
+}
+
+// RegressedStmt is a statement whose latest plan is slower than a plan it used before.
+// The embedded BindableStmt carries the sample SQL and the hints of the previous plan.
+type RegressedStmt struct {
+	BindableStmt
+	Digest         string
+	PlanDigest     string
+	PrevPlanDigest string
+	AvgLatency     time.Duration
+	PrevAvgLatency time.Duration
+}
+
+// planLatencySummary aggregates the history of one plan of a statement.
+type planLatencySummary struct {
+	digest     string
+	planDigest string
+	stmt       *BindableStmt
+	execCount  int64
+	sumLatency time.Duration
+	firstSeen  time.Time
+	lastSeen   time.Time
+}
+
+func (s *planLatencySummary) avgLatency() time.Duration {
+	if s.execCount == 0 {
+		return 0
+	}
+	return s.sumLatency / time.Duration(s.execCount)
+}
+
+// GetPlanRegressedStmts gets users' bindable SQLs whose latest plan has an average latency more than
+// `ratio` times the one of a previous plan. Only plans executed at least `minExecCount` times are considered.
+func (ssMap *stmtSummaryByDigestMap) GetPlanRegressedStmts(ratio float64, minExecCount int64) []*RegressedStmt {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	// schema + digest -> plans of the statement.
+	plansByStmt := make(map[string][]*planLatencySummary, len(values))
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		func() {
+			ssbd.Lock()
+			defer ssbd.Unlock()
+			if !ssbd.initialized || ssbd.isInternal || ssbd.planDigest == "" || ssbd.history.Len() == 0 {
+				return
+			}
+			if ssbd.stmtType != "Select" && ssbd.stmtType != "Delete" && ssbd.stmtType != "Update" && ssbd.stmtType != "Insert" && ssbd.stmtType != "Replace" {
+				return
+			}
+			summary := &planLatencySummary{digest: ssbd.digest, planDigest: ssbd.planDigest}
+			for e := ssbd.history.Front(); e != nil; e = e.Next() {
+				ssElement := e.Value.(*stmtSummaryByDigestElement)
+				ssElement.Lock()
+				summary.execCount += ssElement.execCount
+				summary.sumLatency += ssElement.sumLatency
+				if summary.firstSeen.IsZero() || ssElement.firstSeen.Before(summary.firstSeen) {
+					summary.firstSeen = ssElement.firstSeen
+				}
+				if ssElement.lastSeen.After(summary.lastSeen) {
+					summary.lastSeen = ssElement.lastSeen
+				}
+				ssElement.Unlock()
+			}
+			ssElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
+			ssElement.Lock()
+			defer ssElement.Unlock()
+			// Empty auth users means that it is an internal queries.
+			if len(ssElement.authUsers) == 0 || ssElement.planHint == "" || summary.execCount < minExecCount {
+				return
+			}
+			summary.stmt = &BindableStmt{
+				Schema:    ssbd.schemaName,
+				Query:     ssElement.sampleSQL,
+				PlanHint:  ssElement.planHint,
+				Charset:   ssElement.charset,
+				Collation: ssElement.collation,
+				Users:     ssElement.authUsers,
+			}
+			if ssElement.prepared {
+				summary.stmt.Query = ssbd.normalizedSQL
+			}
+			key := ssbd.schemaName + "." + ssbd.digest
+			plansByStmt[key] = append(plansByStmt[key], summary)
+		}()
+	}
+
+	stmts := make([]*RegressedStmt, 0)
+	for _, plans := range plansByStmt {
+		if len(plans) < 2 {
+			continue
+		}
+		// The latest plan is the one that was executed most recently.
+		latest := plans[0]
+		for _, plan := range plans[1:] {
+			if plan.lastSeen.After(latest.lastSeen) {
+				latest = plan
+			}
+		}
+		// Compare the latest plan with the fastest plan that was used before it. The same plan may be summarized
+		// more than once, such as with different previous statements, which is not compared with itself.
+		var best *planLatencySummary
+		for _, plan := range plans {
+			if plan.planDigest == latest.planDigest || !plan.firstSeen.Before(latest.firstSeen) {
+				continue
+			}
+			if best == nil || plan.avgLatency() < best.avgLatency() {
+				best = plan
+			}
+		}
+		if best == nil || float64(latest.avgLatency()) <= float64(best.avgLatency())*ratio {
+			continue
+		}
+		stmts = append(stmts, &RegressedStmt{
+			BindableStmt:   *best.stmt,
+			Digest:         best.digest,
+			PlanDigest:     latest.planDigest,
+			PrevPlanDigest: best.planDigest,
+			AvgLatency:     latest.avgLatency(),
+			PrevAvgLatency: best.avgLatency(),
+		})
+	}
+	return stmts
+}
+
 // SetEnabled enables or disables statement summary
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value bool) error {
 	// `optEnabled` and `ssMap` don't need to be strictly atomically updated.
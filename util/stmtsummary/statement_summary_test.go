@@ -1163,6 +1163,132 @@ func TestGetMoreThanCntBindableStmt(t *testing.T) {
 	require.Equal(t, 1, len(stmts))
 }
 
+// Test GetPlanRegressedStmts.
+func TestGetPlanRegressedStmts(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	now := time.Now()
+	planGenerator := func(hint string) func() (string, string) {
+		return func() (string, string) {
+			return "", hint
+		}
+	}
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.NormalizedSQL = "select ?"
+	stmtExecInfo1.Digest = "digest1"
+	stmtExecInfo1.PlanDigest = "plan_digest1"
+	stmtExecInfo1.PlanGenerator = planGenerator("use_index(@`sel_1` `t` `a`)")
+	stmtExecInfo1.StmtCtx.StmtType = "Select"
+	stmtExecInfo1.TotalLatency = 1000
+	stmtExecInfo1.StartTime = now.Add(-time.Minute)
+	ssMap.AddStatement(stmtExecInfo1)
+	ssMap.AddStatement(stmtExecInfo1)
+	stmts := ssMap.GetPlanRegressedStmts(1.5, 1)
+	require.Equal(t, 0, len(stmts))
+
+	// The new plan is not slow enough.
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.NormalizedSQL = "select ?"
+	stmtExecInfo2.Digest = "digest1"
+	stmtExecInfo2.PlanDigest = "plan_digest2"
+	stmtExecInfo2.PlanGenerator = planGenerator("use_index(@`sel_1` `t` )")
+	stmtExecInfo2.StmtCtx.StmtType = "Select"
+	stmtExecInfo2.TotalLatency = 1200
+	stmtExecInfo2.StartTime = now
+	ssMap.AddStatement(stmtExecInfo2)
+	stmts = ssMap.GetPlanRegressedStmts(1.5, 1)
+	require.Equal(t, 0, len(stmts))
+
+	stmtExecInfo2.TotalLatency = 10000
+	ssMap.AddStatement(stmtExecInfo2)
+	stmts = ssMap.GetPlanRegressedStmts(1.5, 1)
+	require.Equal(t, 1, len(stmts))
+	require.Equal(t, "digest1", stmts[0].Digest)
+	require.Equal(t, "plan_digest2", stmts[0].PlanDigest)
+	require.Equal(t, "plan_digest1", stmts[0].PrevPlanDigest)
+	require.Equal(t, time.Duration(5600), stmts[0].AvgLatency)
+	require.Equal(t, time.Duration(1000), stmts[0].PrevAvgLatency)
+	require.Equal(t, "schema_name", stmts[0].Schema)
+	require.Equal(t, "use_index(@`sel_1` `t` `a`)", stmts[0].PlanHint)
+
+	// The old plan has not been executed enough times.
+	stmts = ssMap.GetPlanRegressedStmts(1.5, 3)
+	require.Equal(t, 0, len(stmts))
+
+	// The latest plan is not compared with itself.
+	ssMap.Clear()
+	stmtExecInfo3 := generateAnyExecInfo()
+	stmtExecInfo3.NormalizedSQL = "select ?"
+	stmtExecInfo3.Digest = "digest1"
+	stmtExecInfo3.PrevSQLDigest = "prev_digest"
+	stmtExecInfo3.PlanDigest = "plan_digest2"
+	stmtExecInfo3.PlanGenerator = planGenerator("use_index(@`sel_1` `t` )")
+	stmtExecInfo3.StmtCtx.StmtType = "Select"
+	stmtExecInfo3.TotalLatency = 1000
+	stmtExecInfo3.StartTime = now.Add(-time.Minute)
+	ssMap.AddStatement(stmtExecInfo3)
+	ssMap.AddStatement(stmtExecInfo2)
+	stmts = ssMap.GetPlanRegressedStmts(1.5, 1)
+	require.Equal(t, 0, len(stmts))
+
+	// Statements without users are internal queries, which are ignored.
+	ssMap.Clear()
+	stmtExecInfo1.User = ""
+	stmtExecInfo2.User = ""
+	ssMap.AddStatement(stmtExecInfo1)
+	ssMap.AddStatement(stmtExecInfo2)
+	stmts = ssMap.GetPlanRegressedStmts(1.5, 1)
+	require.Equal(t, 0, len(stmts))
+}
+
+// Test GetTopLatencyStmts.
+func TestGetTopLatencyStmts(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.StmtCtx.StmtType = "Insert"
+	ssMap.AddStatement(stmtExecInfo1)
+	stmts := ssMap.GetTopLatencyStmts(10)
+	require.Equal(t, 0, len(stmts))
+
+	stmtExecInfo1.OriginalSQL = "select * from t where a = 1"
+	stmtExecInfo1.NormalizedSQL = "select * from t where a = ?"
+	stmtExecInfo1.Digest = "digest1"
+	stmtExecInfo1.StmtCtx.StmtType = "Select"
+	stmtExecInfo1.TotalLatency = 1000
+	ssMap.AddStatement(stmtExecInfo1)
+	ssMap.AddStatement(stmtExecInfo1)
+
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.OriginalSQL = "select * from t where b = 1"
+	stmtExecInfo2.NormalizedSQL = "select * from t where b = ?"
+	stmtExecInfo2.Digest = "digest2"
+	stmtExecInfo2.StmtCtx.StmtType = "Select"
+	stmtExecInfo2.TotalLatency = 3000
+	ssMap.AddStatement(stmtExecInfo2)
+
+	// The prepared statements are ignored.
+	stmtExecInfo3 := generateAnyExecInfo()
+	stmtExecInfo3.NormalizedSQL = "select * from t where c = ?"
+	stmtExecInfo3.Digest = "digest3"
+	stmtExecInfo3.StmtCtx.StmtType = "Select"
+	stmtExecInfo3.Prepared = true
+	stmtExecInfo3.TotalLatency = 5000
+	ssMap.AddStatement(stmtExecInfo3)
+
+	stmts = ssMap.GetTopLatencyStmts(10)
+	require.Equal(t, 2, len(stmts))
+	require.Equal(t, "select * from t where b = 1", stmts[0].Query)
+	require.Equal(t, time.Duration(3000), stmts[0].SumLatency)
+	require.Equal(t, "select * from t where a = 1", stmts[1].Query)
+	require.Equal(t, int64(2), stmts[1].ExecCount)
+	require.Equal(t, "schema_name", stmts[1].Schema)
+
+	stmts = ssMap.GetTopLatencyStmts(1)
+	require.Equal(t, 1, len(stmts))
+	require.Equal(t, "select * from t where b = 1", stmts[0].Query)
+}
+
 // Test `formatBackoffTypes`.
 func TestFormatBackoffTypes(t *testing.T) {
 	backoffMap := make(map[string]int)